@@ -0,0 +1,299 @@
+// Load test for the optimized hotspot search: generates synthetic hotspots in a bounding box,
+// then issues searches at a fixed rate and reports latency percentiles and the cache hit rate.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"unalone-backend/internal/apiclient"
+	"unalone-backend/internal/models"
+)
+
+type config struct {
+	api         string
+	box         models.BoundingBox
+	generate    int
+	qps         int
+	duration    time.Duration
+	workers     int
+	repeatRatio float64
+	querySet    int
+	password    string
+	seed        int64
+}
+
+// sample is the outcome of one search
+type sample struct {
+	latency   time.Duration
+	queryTime time.Duration // Server-reported query_time_ms
+	cacheHit  bool
+	clusters  int
+	err       error
+}
+
+func main() {
+	var cfg config
+	var bbox string
+	flag.StringVar(&cfg.api, "api", "http://localhost:8080", "Server root URL")
+	flag.StringVar(&bbox, "bbox", "12.85,77.45,13.10,77.75", "Bounding box as swLat,swLng,neLat,neLng (default: Bengaluru)")
+	flag.IntVar(&cfg.generate, "generate", 500, "Synthetic hotspots to create before the run (0 to reuse existing data)")
+	flag.IntVar(&cfg.qps, "qps", 50, "Target searches per second")
+	flag.DurationVar(&cfg.duration, "duration", 30*time.Second, "Length of the run")
+	flag.IntVar(&cfg.workers, "workers", 32, "Concurrent requests in flight")
+	flag.Float64Var(&cfg.repeatRatio, "repeat", 0.5, "Share of searches drawn from a fixed query set, to exercise the cache")
+	flag.IntVar(&cfg.querySet, "queries", 20, "Size of the fixed query set")
+	flag.StringVar(&cfg.password, "password", "loadtest-password", "Password for the load test account")
+	flag.Int64Var(&cfg.seed, "seed", 1, "Random seed")
+	flag.Parse()
+
+	box, err := parseBoundingBox(bbox)
+	if err != nil {
+		log.Fatalf("Invalid -bbox: %v", err)
+	}
+	cfg.box = box
+
+	client := apiclient.New(cfg.api, &http.Client{Timeout: 10 * time.Second, Transport: &http.Transport{MaxIdleConnsPerHost: cfg.workers}})
+	user, err := client.SignUp(models.AuthRequest{
+		Email:    "loadtest@loadtest.unalone.dev",
+		Password: cfg.password,
+		RealName: "Load Test",
+		Nickname: "loadtest",
+	})
+	if err != nil {
+		log.Fatalf("Failed to sign in: %v", err)
+	}
+
+	rng := rand.New(rand.NewSource(cfg.seed))
+	if cfg.generate > 0 {
+		start := time.Now()
+		if err := generateHotspots(client, user.Token, cfg, rng); err != nil {
+			log.Fatalf("Failed to generate hotspots: %v", err)
+		}
+		log.Printf("Generated %d hotspots in %s", cfg.generate, time.Since(start).Round(time.Millisecond))
+	}
+
+	samples, dropped := run(client, user.Token, cfg, rng)
+	report(samples, dropped, cfg)
+}
+
+func parseBoundingBox(s string) (models.BoundingBox, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 4 {
+		return models.BoundingBox{}, fmt.Errorf("want 4 comma-separated numbers")
+	}
+	var v [4]float64
+	for i, p := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil {
+			return models.BoundingBox{}, err
+		}
+		v[i] = f
+	}
+	if v[0] >= v[2] || v[1] >= v[3] {
+		return models.BoundingBox{}, fmt.Errorf("south-west corner must be below and left of north-east")
+	}
+	return models.BoundingBox{
+		SouthWest: models.HotspotLocation{Latitude: v[0], Longitude: v[1]},
+		NorthEast: models.HotspotLocation{Latitude: v[2], Longitude: v[3]},
+	}, nil
+}
+
+func randomPoint(rng *rand.Rand, box models.BoundingBox) models.HotspotLocation {
+	return models.HotspotLocation{
+		Latitude:  box.SouthWest.Latitude + rng.Float64()*(box.NorthEast.Latitude-box.SouthWest.Latitude),
+		Longitude: box.SouthWest.Longitude + rng.Float64()*(box.NorthEast.Longitude-box.SouthWest.Longitude),
+	}
+}
+
+var syntheticCategories = []models.HotspotCategory{
+	models.CategoryCafe, models.CategoryPark, models.CategorySports, models.CategoryEvent,
+	models.CategoryStudy, models.CategoryRestaurant, models.CategoryLibrary,
+}
+
+// generateHotspots creates hotspots uniformly over the box, several at a time
+func generateHotspots(client *apiclient.Client, token string, cfg config, rng *rand.Rand) error {
+	type job struct {
+		req models.CreateHotspotRequest
+	}
+	jobs := make(chan job)
+	errs := make(chan error, cfg.workers)
+	var wg sync.WaitGroup
+	for i := 0; i < cfg.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				if err := client.Expect(http.StatusCreated, "POST", "/hotspots/", token, j.req, nil); err != nil {
+					select {
+					case errs <- err:
+					default:
+					}
+				}
+			}
+		}()
+	}
+
+	now := time.Now().Truncate(time.Hour)
+	for i := 0; i < cfg.generate; i++ {
+		start := now.Add(time.Duration(rng.Intn(14*24)) * time.Hour)
+		jobs <- job{req: models.CreateHotspotRequest{
+			Name:          fmt.Sprintf("Load test hotspot %d", i+1),
+			Description:   "Synthetic hotspot generated by cmd/loadtest",
+			Category:      syntheticCategories[rng.Intn(len(syntheticCategories))],
+			Location:      randomPoint(rng, cfg.box),
+			Address:       models.HotspotAddress{City: "Loadtest", Country: "India"},
+			MaxCapacity:   2 + rng.Intn(50),
+			IsPublic:      true,
+			Tags:          []string{"loadtest"},
+			ScheduledTime: &start,
+		}}
+	}
+	close(jobs)
+	wg.Wait()
+	close(errs)
+	return <-errs
+}
+
+// randomQuery builds a search at a random zoom level, as a map client would while panning
+func randomQuery(rng *rand.Rand, box models.BoundingBox) models.OptimizedHotspotSearchRequest {
+	zoom := 8 + rng.Intn(9) // 8 (region) to 16 (street)
+	radius := 40.0 / float64(int(1)<<uint(zoom-8))
+	if radius < 0.5 {
+		radius = 0.5
+	}
+	return models.OptimizedHotspotSearchRequest{
+		GeospatialQuery: models.GeospatialQuery{
+			Center:    randomPoint(rng, box),
+			Radius:    radius,
+			ZoomLevel: zoom,
+		},
+		Pagination: models.Pagination{Limit: 100},
+		Clustering: models.ClusterConfig{Mode: models.ClusteringModeAuto, ZoomLevel: zoom},
+	}
+}
+
+// run issues searches at cfg.qps for cfg.duration. Ticks that find every worker busy are
+// dropped rather than queued, so a slow server shows up as lost throughput, not hidden latency.
+func run(client *apiclient.Client, token string, cfg config, rng *rand.Rand) ([]sample, int) {
+	fixed := make([]models.OptimizedHotspotSearchRequest, cfg.querySet)
+	for i := range fixed {
+		fixed[i] = randomQuery(rng, cfg.box)
+	}
+
+	queries := make(chan models.OptimizedHotspotSearchRequest)
+	results := make(chan sample, cfg.workers)
+	var wg sync.WaitGroup
+	for i := 0; i < cfg.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for q := range queries {
+				results <- search(client, token, q)
+			}
+		}()
+	}
+
+	var samples []sample
+	collected := make(chan struct{})
+	go func() {
+		for s := range results {
+			samples = append(samples, s)
+		}
+		close(collected)
+	}()
+
+	log.Printf("Searching at %d qps for %s", cfg.qps, cfg.duration)
+	dropped := 0
+	ticker := time.NewTicker(time.Second / time.Duration(cfg.qps))
+	deadline := time.After(cfg.duration)
+loop:
+	for {
+		select {
+		case <-deadline:
+			break loop
+		case <-ticker.C:
+			q := randomQuery(rng, cfg.box)
+			if len(fixed) > 0 && rng.Float64() < cfg.repeatRatio {
+				q = fixed[rng.Intn(len(fixed))]
+			}
+			select {
+			case queries <- q:
+			default:
+				dropped++
+			}
+		}
+	}
+	ticker.Stop()
+	close(queries)
+	wg.Wait()
+	close(results)
+	<-collected
+	return samples, dropped
+}
+
+func search(client *apiclient.Client, token string, q models.OptimizedHotspotSearchRequest) sample {
+	var result models.HotspotSearchResultOptimized
+	start := time.Now()
+	err := client.Expect(http.StatusOK, "POST", "/hotspots/search/optimized", token, q, &result)
+	return sample{
+		latency:   time.Since(start),
+		queryTime: time.Duration(result.QueryTime) * time.Millisecond,
+		cacheHit:  result.CacheHit,
+		clusters:  result.ClusterCount,
+		err:       err,
+	}
+}
+
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := int(float64(len(sorted)-1) * p)
+	return sorted[i]
+}
+
+func report(samples []sample, dropped int, cfg config) {
+	var latencies, queryTimes []time.Duration
+	hits, failures, clusters := 0, 0, 0
+	var firstErr error
+	for _, s := range samples {
+		if s.err != nil {
+			failures++
+			if firstErr == nil {
+				firstErr = s.err
+			}
+			continue
+		}
+		latencies = append(latencies, s.latency)
+		queryTimes = append(queryTimes, s.queryTime)
+		clusters += s.clusters
+		if s.cacheHit {
+			hits++
+		}
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	sort.Slice(queryTimes, func(i, j int) bool { return queryTimes[i] < queryTimes[j] })
+
+	ok := len(latencies)
+	fmt.Printf("requests   %d ok, %d failed, %d dropped (%.1f qps achieved of %d)\n",
+		ok, failures, dropped, float64(ok+failures)/cfg.duration.Seconds(), cfg.qps)
+	if ok > 0 {
+		fmt.Printf("latency    p50 %s  p95 %s  p99 %s  max %s\n",
+			percentile(latencies, 0.50), percentile(latencies, 0.95), percentile(latencies, 0.99), latencies[ok-1])
+		fmt.Printf("server     p50 %s  p95 %s (query_time_ms)\n", percentile(queryTimes, 0.50), percentile(queryTimes, 0.95))
+		fmt.Printf("cache      %.1f%% hits (%d of %d)\n", 100*float64(hits)/float64(ok), hits, ok)
+		fmt.Printf("clusters   %.1f per response\n", float64(clusters)/float64(ok))
+	}
+	if firstErr != nil {
+		fmt.Printf("first error: %v\n", firstErr)
+	}
+}
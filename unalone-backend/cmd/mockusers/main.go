@@ -0,0 +1,60 @@
+// Exports or imports the mock-mode user database as JSON (the mock_users.json format).
+// Stop the server first: only one process can open the database.
+//
+//	go run ./cmd/mockusers export > users.json
+//	go run ./cmd/mockusers import users.json
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"unalone-backend/internal/services"
+)
+
+func main() {
+	if len(os.Args) < 2 || (os.Args[1] != "export" && os.Args[1] != "import") {
+		fmt.Fprintln(os.Stderr, "usage: mockusers export [file] | import file")
+		os.Exit(2)
+	}
+
+	path := services.MockUsersDBPath()
+	store, err := services.OpenUserStoreFile(path)
+	if err != nil {
+		log.Fatalf("Failed to open %s: %v", path, err)
+	}
+	defer store.Close()
+
+	switch os.Args[1] {
+	case "export":
+		out := os.Stdout
+		if len(os.Args) > 2 {
+			f, err := os.Create(os.Args[2])
+			if err != nil {
+				log.Fatalf("Failed to create %s: %v", os.Args[2], err)
+			}
+			defer f.Close()
+			out = f
+		}
+		n, err := services.ExportUsersJSON(store, out)
+		if err != nil {
+			log.Fatalf("Export failed: %v", err)
+		}
+		log.Printf("Exported %d users from %s", n, path)
+	case "import":
+		if len(os.Args) < 3 {
+			log.Fatal("import needs a file")
+		}
+		f, err := os.Open(os.Args[2])
+		if err != nil {
+			log.Fatalf("Failed to open %s: %v", os.Args[2], err)
+		}
+		defer f.Close()
+		n, err := services.ImportUsersJSON(store, f)
+		if err != nil {
+			log.Fatalf("Import failed: %v", err)
+		}
+		log.Printf("Imported %d users into %s", n, path)
+	}
+}
@@ -0,0 +1,18 @@
+// Prints the OpenAPI document to stdout for client code generation
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+
+	"unalone-backend/internal/routes"
+)
+
+func main() {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(routes.Spec()); err != nil {
+		log.Fatalf("Failed to write OpenAPI document: %v", err)
+	}
+}
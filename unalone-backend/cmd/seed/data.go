@@ -0,0 +1,69 @@
+package main
+
+import "unalone-backend/internal/models"
+
+// city is a seeding area; hotspots are scattered around its center
+type city struct {
+	Name      string
+	Region    string
+	Latitude  float64
+	Longitude float64
+	Areas     []string // Neighbourhoods used as street names
+}
+
+var cities = map[string]city{
+	"bengaluru": {Name: "Bengaluru", Region: "Karnataka", Latitude: 12.9716, Longitude: 77.5946, Areas: []string{"Indiranagar", "Koramangala", "Jayanagar", "Malleshwaram", "HSR Layout", "Whitefield", "Basavanagudi"}},
+	"mumbai":    {Name: "Mumbai", Region: "Maharashtra", Latitude: 19.0760, Longitude: 72.8777, Areas: []string{"Bandra", "Andheri", "Colaba", "Powai", "Dadar", "Juhu", "Lower Parel"}},
+	"delhi":     {Name: "Delhi", Region: "Delhi", Latitude: 28.6139, Longitude: 77.2090, Areas: []string{"Hauz Khas", "Connaught Place", "Lajpat Nagar", "Saket", "Karol Bagh", "Dwarka", "Greater Kailash"}},
+}
+
+var firstNames = []string{
+	"Aarav", "Ananya", "Arjun", "Diya", "Ishaan", "Kavya", "Rohan", "Meera", "Vikram", "Priya",
+	"Aditya", "Sneha", "Karan", "Nisha", "Rahul", "Pooja", "Siddharth", "Tanvi", "Nikhil", "Riya",
+	"Farhan", "Zoya", "Kabir", "Sana", "Dev", "Aisha", "Varun", "Neha", "Yash", "Ira",
+}
+
+var lastNames = []string{
+	"Sharma", "Iyer", "Reddy", "Nair", "Khan", "Gupta", "Menon", "Das", "Patel", "Singh",
+	"Rao", "Joshi", "Kulkarni", "Bose", "Fernandes", "Chatterjee", "Pillai", "Mehta",
+}
+
+// hotspotTemplate describes a kind of meetup the seeder creates
+type hotspotTemplate struct {
+	Name        string
+	Description string
+	Category    models.HotspotCategory
+	Subcategory string
+	Tags        []string
+	Capacity    int
+}
+
+var hotspotTemplates = []hotspotTemplate{
+	{"Board games evening", "Catan, Codenames and chai. Beginners welcome, we explain every game.", models.CategoryCafe, "board-games", []string{"boardgames", "chai", "beginners"}, 8},
+	{"Sunday morning football", "Casual 5-a-side, mixed skill levels. Bring water and studs if you have them.", models.CategorySports, "football", []string{"football", "fitness", "weekend"}, 12},
+	{"Gully cricket in the park", "Tennis-ball cricket, teams picked on the spot. Nobody keeps score seriously.", models.CategorySports, "cricket", []string{"cricket", "outdoors"}, 16},
+	{"Silent reading hour", "Bring a book, read together for an hour, then talk about it over coffee.", models.CategoryLibrary, "book-club", []string{"books", "reading", "quiet"}, 10},
+	{"Open mic night", "Poetry, stand-up, music - five minutes each. Come to perform or just listen.", models.CategoryEvent, "live-music", []string{"openmic", "music", "poetry"}, 30},
+	{"Co-working and filter coffee", "Laptops out, headphones optional. Lunch break together at one.", models.CategoryStudy, "", []string{"coworking", "remote", "coffee"}, 6},
+	{"Evening walk by the lake", "An easy 5 km loop at sunset. Good for meeting people without the pressure.", models.CategoryPark, "", []string{"walking", "outdoors", "sunset"}, 15},
+	{"Badminton doubles", "Two courts booked, we rotate pairs every game. Shuttles provided.", models.CategorySports, "badminton", []string{"badminton", "fitness"}, 8},
+	{"Street food crawl", "Four stalls, two hours, one shared budget. Vegetarian options at every stop.", models.CategoryRestaurant, "", []string{"food", "streetfood", "explore"}, 8},
+	{"Pottery workshop", "Hands-on wheel session with a local potter. Materials included in the fee.", models.CategoryEvent, "workshop", []string{"pottery", "art", "workshop"}, 10},
+	{"New in town meetup", "Recently moved here? Meet others figuring out the city too.", models.CategoryCafe, "", []string{"newintown", "friends", "chai"}, 20},
+	{"Morning yoga in the park", "Gentle flow for all levels. Bring your own mat.", models.CategoryPark, "", []string{"yoga", "wellbeing", "morning"}, 20},
+}
+
+var chatLines = []string{
+	"Hi everyone! Looking forward to this.",
+	"Is anyone coming from the metro station? We could walk together.",
+	"First time joining one of these, a bit nervous haha",
+	"Welcome! Everyone is really friendly, don't worry.",
+	"I'll be wearing a blue jacket.",
+	"Running 10 minutes late, save me a seat!",
+	"Should we bring anything?",
+	"Just water and good vibes.",
+	"Is there parking nearby?",
+	"There's a paid lot right behind the place.",
+	"See you all there!",
+	"That was so much fun, same time next week?",
+}
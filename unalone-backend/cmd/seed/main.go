@@ -0,0 +1,225 @@
+// Seeds a running server with users, friendships, hotspots around a city and chat history.
+// It goes through the public API, so it fills whichever backend the server is configured
+// with (mock storage or Firestore, including the emulator) and reuses accounts on re-runs.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+
+	"unalone-backend/internal/apiclient"
+	"unalone-backend/internal/models"
+)
+
+type config struct {
+	api          string
+	city         city
+	users        int
+	hotspots     int
+	friends      int
+	messages     int
+	radiusKm     float64
+	password     string
+	emailDomain  string
+	randomSource int64
+}
+
+func main() {
+	var cfg config
+	var cityName string
+	flag.StringVar(&cfg.api, "api", "http://localhost:8080", "Server root URL")
+	flag.StringVar(&cityName, "city", "bengaluru", "City to seed: bengaluru, mumbai or delhi")
+	flag.IntVar(&cfg.users, "users", 40, "Number of users")
+	flag.IntVar(&cfg.hotspots, "hotspots", 25, "Number of hotspots")
+	flag.IntVar(&cfg.friends, "friends", 4, "Friend requests sent per user")
+	flag.IntVar(&cfg.messages, "messages", 6, "Chat messages per hotspot")
+	flag.Float64Var(&cfg.radiusKm, "radius", 8, "Kilometers from the city center")
+	flag.StringVar(&cfg.password, "password", "seed-password", "Password for every seeded account")
+	flag.StringVar(&cfg.emailDomain, "domain", "seed.unalone.dev", "Email domain for seeded accounts")
+	flag.Int64Var(&cfg.randomSource, "seed", 1, "Random seed; the same seed produces the same data")
+	flag.Parse()
+
+	c, ok := cities[strings.ToLower(cityName)]
+	if !ok {
+		log.Fatalf("Unknown city %q", cityName)
+	}
+	cfg.city = c
+
+	if err := seed(apiclient.New(cfg.api, nil), cfg); err != nil {
+		log.Fatalf("Seeding failed: %v", err)
+	}
+}
+
+func seed(client *apiclient.Client, cfg config) error {
+	rng := rand.New(rand.NewSource(cfg.randomSource))
+
+	users, err := seedUsers(client, cfg)
+	if err != nil {
+		return err
+	}
+	log.Printf("Users: %d", len(users))
+
+	friendships := seedFriendships(client, cfg, rng, users)
+	log.Printf("Friendships: %d", friendships)
+
+	hotspots, err := seedHotspots(client, cfg, rng, users)
+	if err != nil {
+		return err
+	}
+	log.Printf("Hotspots: %d in %s", len(hotspots), cfg.city.Name)
+
+	joins, messages := seedAttendance(client, cfg, rng, users, hotspots)
+	log.Printf("Joins: %d, chat messages: %d", joins, messages)
+	log.Printf("Sign in as any %s account with password %q", cfg.emailDomain, cfg.password)
+	return nil
+}
+
+func seedUsers(client *apiclient.Client, cfg config) ([]*apiclient.User, error) {
+	users := make([]*apiclient.User, 0, cfg.users)
+	for i := 0; i < cfg.users; i++ {
+		first := firstNames[i%len(firstNames)]
+		last := lastNames[(i*7)%len(lastNames)]
+		nickname := fmt.Sprintf("%s%d", strings.ToLower(first), i+1)
+		user, err := client.SignUp(models.AuthRequest{
+			Email:    nickname + "@" + cfg.emailDomain,
+			Password: cfg.password,
+			RealName: first + " " + last,
+			Nickname: nickname,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("user %s: %w", nickname, err)
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+// seedFriendships sends requests between random pairs; most are accepted, the rest stay pending
+func seedFriendships(client *apiclient.Client, cfg config, rng *rand.Rand, users []*apiclient.User) int {
+	accepted := 0
+	for _, from := range users {
+		for i := 0; i < cfg.friends; i++ {
+			to := users[rng.Intn(len(users))]
+			if to.ID == from.ID {
+				continue
+			}
+			// Duplicates and existing friendships are rejected by the API; skip them
+			if err := client.Expect(http.StatusOK, "POST", "/friends/requests", from.Token, models.SendFriendRequestRequest{Target: to.ID}, nil); err != nil {
+				continue
+			}
+			if rng.Intn(5) == 0 {
+				continue
+			}
+			if err := client.Expect(http.StatusOK, "POST", "/friends/accept", to.Token, models.FriendActionRequest{UserID: from.ID}, nil); err == nil {
+				accepted++
+			}
+		}
+	}
+	return accepted
+}
+
+func seedHotspots(client *apiclient.Client, cfg config, rng *rand.Rand, users []*apiclient.User) ([]*models.Hotspot, error) {
+	hotspots := make([]*models.Hotspot, 0, cfg.hotspots)
+	// Start on the next hour so schedules look hand-picked
+	now := time.Now().Truncate(time.Hour).Add(time.Hour)
+	for i := 0; i < cfg.hotspots; i++ {
+		template := hotspotTemplates[rng.Intn(len(hotspotTemplates))]
+		host := users[rng.Intn(len(users))]
+		area := cfg.city.Areas[rng.Intn(len(cfg.city.Areas))]
+		lat, lng := scatter(rng, cfg.city.Latitude, cfg.city.Longitude, cfg.radiusKm)
+		start := now.Add(time.Duration(rng.Intn(7*24)) * time.Hour)
+		end := start.Add(time.Duration(1+rng.Intn(3)) * time.Hour)
+
+		var hotspot models.Hotspot
+		err := client.Expect(http.StatusCreated, "POST", "/hotspots/", host.Token, models.CreateHotspotRequest{
+			Name:          template.Name + " in " + area,
+			Description:   template.Description,
+			Category:      template.Category,
+			Subcategory:   template.Subcategory,
+			Location:      models.HotspotLocation{Latitude: lat, Longitude: lng},
+			Address:       models.HotspotAddress{Street: area, City: cfg.city.Name, Region: cfg.city.Region, Country: "India"},
+			MaxCapacity:   template.Capacity,
+			IsPublic:      true,
+			Tags:          template.Tags,
+			ScheduledTime: &start,
+			EndTime:       &end,
+		}, &hotspot)
+		if err != nil {
+			return nil, fmt.Errorf("hotspot %d: %w", i+1, err)
+		}
+		hotspots = append(hotspots, &hotspot)
+	}
+	return hotspots, nil
+}
+
+// seedAttendance fills hotspots to a random share of their capacity and has attendees chat
+func seedAttendance(client *apiclient.Client, cfg config, rng *rand.Rand, users []*apiclient.User, hotspots []*models.Hotspot) (int, int) {
+	byID := make(map[string]*apiclient.User, len(users))
+	for _, user := range users {
+		byID[user.ID] = user
+	}
+
+	joins, messages := 0, 0
+	for _, hotspot := range hotspots {
+		var attendees []*apiclient.User
+		for _, id := range hotspot.Attendees {
+			if user, ok := byID[id]; ok {
+				attendees = append(attendees, user)
+			}
+		}
+		want := 1 + rng.Intn(hotspot.MaxCapacity)
+		for _, i := range rng.Perm(len(users)) {
+			if len(attendees) >= want {
+				break
+			}
+			user := users[i]
+			if user.ID == hotspot.CreatedBy {
+				continue
+			}
+			if err := client.Expect(http.StatusOK, "POST", "/hotspots/"+hotspot.ID+"/join", user.Token, nil, nil); err != nil {
+				continue
+			}
+			attendees = append(attendees, user)
+			joins++
+		}
+		for i := 0; i < cfg.messages && len(attendees) > 0; i++ {
+			author := attendees[rng.Intn(len(attendees))]
+			if err := sendChat(client, hotspot.ID, author, chatLines[(i+rng.Intn(3))%len(chatLines)]); err != nil {
+				log.Printf("Chat in %s: %v", hotspot.ID, err)
+				break
+			}
+			messages++
+		}
+	}
+	return joins, messages
+}
+
+// sendChat posts one message over the chat WebSocket, which is the only way to send one
+func sendChat(client *apiclient.Client, hotspotID string, author *apiclient.User, content string) error {
+	conn, err := client.Dial("/hotspots/"+hotspotID+"/chat/ws", author.Token)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if err := conn.WriteJSON(map[string]string{"type": "message", "content": content}); err != nil {
+		return err
+	}
+	// Wait for the broadcast so the message is stored before the connection closes
+	var echoed models.ChatMessage
+	return apiclient.ReadJSON(conn, 5*time.Second, &echoed)
+}
+
+// scatter picks a uniformly distributed point within radiusKm of the center
+func scatter(rng *rand.Rand, lat, lng, radiusKm float64) (float64, float64) {
+	distance := radiusKm * math.Sqrt(rng.Float64())
+	bearing := rng.Float64() * 2 * math.Pi
+	dLat := distance / 111.32 * math.Cos(bearing)
+	dLng := distance / (111.32 * math.Cos(lat*math.Pi/180)) * math.Sin(bearing)
+	return lat + dLat, lng + dLng
+}
@@ -0,0 +1,157 @@
+// Minimal HTTP/WebSocket client for the /api/v1 API, used by the end-to-end tests and the seed command
+package apiclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"unalone-backend/internal/models"
+
+	"github.com/gorilla/websocket"
+)
+
+// Client sends requests to one server
+type Client struct {
+	BaseURL string // Server root, e.g. http://localhost:8080
+	HTTP    *http.Client
+}
+
+// Response is a recorded API response
+type Response struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// User is a signed-in account and its access token
+type User struct {
+	ID       string
+	Email    string
+	Nickname string
+	Token    string
+}
+
+// StatusError is returned by Expect when the response status is not the wanted one
+type StatusError struct {
+	Method string
+	Path   string
+	Status int
+	Want   int
+	Body   string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s %s: status %d, want %d: %s", e.Method, e.Path, e.Status, e.Want, e.Body)
+}
+
+// New creates a client; a nil httpClient uses one with a 30s timeout
+func New(baseURL string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &Client{BaseURL: strings.TrimRight(baseURL, "/"), HTTP: httpClient}
+}
+
+// Do sends a JSON request to an /api/v1 path; token may be empty
+func (c *Client) Do(method, path, token string, body interface{}) (*Response, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, c.BaseURL+"/api/v1"+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &Response{Status: resp.StatusCode, Header: resp.Header, Body: data}, nil
+}
+
+// Expect sends a request and decodes the envelope's data into out (if non-nil),
+// returning a *StatusError unless the response has the wanted status
+func (c *Client) Expect(want int, method, path, token string, body, out interface{}) error {
+	resp, err := c.Do(method, path, token, body)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	if resp.Status != want {
+		return &StatusError{Method: method, Path: path, Status: resp.Status, Want: want, Body: strings.TrimSpace(string(resp.Body))}
+	}
+	if out == nil {
+		return nil
+	}
+	envelope := struct {
+		Data json.RawMessage `json:"data"`
+	}{}
+	if err := json.Unmarshal(resp.Body, &envelope); err != nil {
+		return fmt.Errorf("%s %s: decode response: %w", method, path, err)
+	}
+	if err := json.Unmarshal(envelope.Data, out); err != nil {
+		return fmt.Errorf("%s %s: decode data: %w", method, path, err)
+	}
+	return nil
+}
+
+// SignUp registers an account and logs it in. An account that already exists is logged in
+// with the same credentials, so repeated runs reuse it.
+func (c *Client) SignUp(creds models.AuthRequest) (*User, error) {
+	err := c.Expect(http.StatusCreated, "POST", "/auth/register", "", creds, nil)
+	var statusErr *StatusError
+	if err != nil && !(errors.As(err, &statusErr) && statusErr.Status == http.StatusConflict) {
+		return nil, err
+	}
+	return c.Login(creds.Email, creds.Password)
+}
+
+// Login signs in with email and password
+func (c *Client) Login(email, password string) (*User, error) {
+	var login models.AuthResponse
+	if err := c.Expect(http.StatusOK, "POST", "/auth/login", "", models.AuthRequest{Email: email, Password: password}, &login); err != nil {
+		return nil, err
+	}
+	return &User{ID: login.User.ID, Email: email, Nickname: login.User.Nickname, Token: login.Token}, nil
+}
+
+// Dial opens a WebSocket on an /api/v1 path, offering the token as a bearer subprotocol
+func (c *Client) Dial(path, token string) (*websocket.Conn, error) {
+	url := "ws" + strings.TrimPrefix(c.BaseURL, "http") + "/api/v1" + path
+	dialer := *websocket.DefaultDialer
+	dialer.Subprotocols = []string{"unalone.v1", "bearer." + token}
+	conn, resp, err := dialer.Dial(url, nil)
+	if err != nil {
+		if resp != nil {
+			return nil, fmt.Errorf("dial %s: status %d: %w", path, resp.StatusCode, err)
+		}
+		return nil, fmt.Errorf("dial %s: %w", path, err)
+	}
+	return conn, nil
+}
+
+// ReadJSON reads the next frame from a WebSocket, waiting at most timeout
+func ReadJSON(conn *websocket.Conn, timeout time.Duration, out interface{}) error {
+	conn.SetReadDeadline(time.Now().Add(timeout))
+	return conn.ReadJSON(out)
+}
@@ -0,0 +1,449 @@
+// Application wiring: builds every service, background job and handler behind the router
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"unalone-backend/internal/handlers"
+	"unalone-backend/internal/jobs"
+	"unalone-backend/internal/middleware"
+	"unalone-backend/internal/models"
+	"unalone-backend/internal/routes"
+	"unalone-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// App is a fully wired server; background jobs run until the context passed to New is cancelled.
+// Queued jobs only run once StartWorkers is called.
+type App struct {
+	Router *gin.Engine
+
+	firestore *services.FirestoreService
+	redis     *services.RedisService
+	users     *services.UserService
+	jobs      *jobs.Queue
+}
+
+// Options adjusts how New wires the application
+type Options struct {
+	// Clock is what every service reads the current time from; nil uses the system clock
+	Clock services.Clock
+}
+
+// New initializes storage from the environment and wires the whole application
+func New(ctx context.Context, opts Options) (*App, error) {
+	if opts.Clock == nil {
+		opts.Clock = services.SystemClock
+	}
+	services.SetClock(opts.Clock)
+
+	// Credentials from the environment, Google Secret Manager or Vault (SECRETS_PROVIDER)
+	secrets, err := services.NewSecretsProvider(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("initialize secrets provider: %w", err)
+	}
+	log.Printf("Secrets: %s", secrets.Name())
+
+	// Initialize Firestore service
+	firestoreService, err := services.NewFirestoreService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("initialize Firestore service: %w", err)
+	}
+
+	// Initialize Redis service for caching and geospatial operations
+	redisService, err := services.NewRedisService(ctx, secrets)
+	if err != nil {
+		log.Printf("Redis service initialization failed: %v. Continuing without cache.", err)
+	}
+
+	// Query limits, per-request read budgets and daily read and write counts for Firestore
+	firestoreGuard := services.NewFirestoreGuard(redisService)
+	firestoreService.SetGuard(firestoreGuard)
+
+	// Which dependencies are reachable, so services can take degraded paths while one is down
+	health := services.NewHealthRegistry(firestoreService, redisService)
+
+	// Uploaded media such as chat voice notes
+	objectStore, err := services.NewObjectStore(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("initialize object storage: %w", err)
+	}
+
+	// Domain events published by services and handled in the background
+	eventBus := services.NewEventBus(redisService)
+	log.Printf("Event bus: %s delivery", eventBus.Backend())
+	outbox := services.NewOutbox(firestoreService, eventBus)
+	// Queued background work with retries and dead letters, shared through Redis when available
+	jobQueue := services.NewJobQueue(redisService)
+
+	// Initialize other services
+	authService, err := services.NewAuthService(firestoreService, secrets)
+	if err != nil {
+		return nil, fmt.Errorf("initialize auth: %w", err)
+	}
+	authService.StartKeyRefresh(ctx)
+	userService := services.NewUserService(firestoreService)
+	nicknameService := services.NewNicknameService(firestoreService)
+	profileService := services.NewProfileService(firestoreService, userService, nicknameService)
+	// Daily calls and estimated spend for Gemini, SMS and geocoding, with optional budgets
+	quotaService := services.NewQuotaService(redisService)
+	emailSender := services.NewEmailSender()
+	smsGateway := services.NewSMSGateway(firestoreService, redisService, secrets, quotaService, emailSender)
+	loginSecurityService := services.NewLoginSecurityService(firestoreService, redisService, emailSender)
+	log.Printf("SMS mode: %s", smsGateway.ProviderName())
+	phoneVerificationService := services.NewPhoneVerificationService(firestoreService, userService, smsGateway)
+	categoryService := services.NewCategoryService(firestoreService)
+	resourceService := services.NewResourceService(firestoreService)
+	tagService := services.NewTagService(firestoreService)
+	friendListService := services.NewFriendListService(firestoreService, userService)
+	hotspotReadCache := services.NewHotspotReadCache(redisService, health)
+	// What each user lets us process; services check it before location, analytics, AI and marketing email
+	consentService := services.NewConsentService(firestoreService)
+	userLocationService := services.NewUserLocationService(userService, profileService, redisService, consentService)
+	venueService := services.NewVenueService(firestoreService)
+	// Club pages: admins host hotspots for the club and followers hear about new ones
+	clubService := services.NewClubService(firestoreService, userService)
+	hotspotService := services.NewHotspotService(firestoreService, userService, categoryService, tagService, friendListService, outbox, hotspotReadCache, venueService, clubService)
+	// Ticketed hotspots: joining needs a paid ticket, refunded if the hotspot is cancelled
+	paymentService := services.NewPaymentService(firestoreService, hotspotService, secrets)
+	hotspotService.SetTicketGate(paymentService)
+	log.Printf("Payments mode: %s", paymentService.ProviderName())
+	// Tickets are refunded when the hotspot is cancelled or deleted, never because it went inactive
+	// after everyone left or it ended
+	eventBus.Subscribe(models.DomainEventHotspotCancelled, "ticket_refunds", func(ctx context.Context, event *models.DomainEvent) error {
+		if event.Hotspot == nil || len(event.Hotspot.TicketTiers) == 0 {
+			return nil
+		}
+		_, err := paymentService.RefundHotspot(ctx, event.HotspotID)
+		return err
+	})
+	eventBus.Subscribe(models.DomainEventHotspotDeleted, "ticket_refunds", func(ctx context.Context, event *models.DomainEvent) error {
+		_, err := paymentService.RefundHotspot(ctx, event.HotspotID)
+		return err
+	})
+	chatService := services.NewChatService(firestoreService, userService, hotspotService)
+	voiceNoteService := services.NewVoiceNoteService(firestoreService, chatService, objectStore, services.NewAudioTranscoder(), jobQueue)
+	friendsService := services.NewFriendsService(firestoreService, userService, outbox)
+	eventService := services.NewEventService()
+	notificationService := services.NewNotificationService(profileService, userService, eventService, chatService, clubService)
+	gamificationService := services.NewGamificationService(firestoreService, userService, notificationService, eventService)
+	// Point awards and notifications for joins and friendships are delivered from the outbox
+	eventBus.Subscribe(models.DomainEventUserJoined, "join_points", func(ctx context.Context, event *models.DomainEvent) error {
+		_, _, err := gamificationService.AwardForHotspotJoin(event.ActorID)
+		return err
+	})
+	eventBus.Subscribe(models.DomainEventUserJoined, "join_notification", func(ctx context.Context, event *models.DomainEvent) error {
+		return notificationService.NotifyHotspotJoined(event.Hotspot, event.ActorID)
+	})
+	eventBus.Subscribe(models.DomainEventUserCheckedInQR, "checkin_points", func(ctx context.Context, event *models.DomainEvent) error {
+		_, _, err := gamificationService.AwardForQRCheckIn(event.ActorID)
+		return err
+	})
+	onboardingService := services.NewOnboardingService(firestoreService, userService, hotspotService, gamificationService, eventService)
+	eventBus.Subscribe(models.DomainEventUserJoined, "onboarding_first_join", func(ctx context.Context, event *models.DomainEvent) error {
+		return onboardingService.CompleteStep(event.ActorID, models.OnboardingFirstHotspotJoined)
+	})
+	eventBus.Subscribe(models.DomainEventFriendAccepted, "friendship_points", func(ctx context.Context, event *models.DomainEvent) error {
+		if _, _, err := gamificationService.AwardForFriendship(event.ActorID); err != nil {
+			return err
+		}
+		_, _, err := gamificationService.AwardForFriendship(event.TargetUserID)
+		return err
+	})
+	eventBus.Subscribe(models.DomainEventFriendAccepted, "friendship_notification", func(ctx context.Context, event *models.DomainEvent) error {
+		return notificationService.NotifyFriendAccepted(event.ActorID, event.TargetUserID)
+	})
+	eventBus.Subscribe(models.DomainEventHotspotPublished, "club_follower_notification", func(ctx context.Context, event *models.DomainEvent) error {
+		return notificationService.NotifyClubFollowers(event.Hotspot)
+	})
+	eventBus.Subscribe(models.DomainEventOwnershipTransferred, "new_host_notification", func(ctx context.Context, event *models.DomainEvent) error {
+		return notificationService.NotifyNewHost(event.Hotspot, event.ActorID)
+	})
+	calendarService := services.NewCalendarService(firestoreService, hotspotService)
+	eventBus.Subscribe(models.DomainEventHotspotDeleted, "calendar_tombstones", calendarService.HandleDomainEvent)
+	// Deterministic A/B buckets for recommendation, onboarding and prompt experiments
+	experimentService := services.NewExperimentService(firestoreService)
+	// Versioned AI prompts, with per-experiment overrides
+	promptService := services.NewPromptService(firestoreService, experimentService)
+	// AI chat service (in-memory). If a GEMINI_API_KEY secret is set, real calls are made.
+	aiService := services.NewInMemoryAIChatService(friendsService, userService, categoryService, resourceService, userLocationService, promptService, services.NewAIReplyCache(redisService), consentService, secrets, quotaService, health)
+	if aiService.GeminiConfigured() {
+		model := os.Getenv("GEMINI_MODEL")
+		if strings.TrimSpace(model) == "" {
+			model = "gemini-2.5-flash"
+		}
+		log.Printf("AI mode: Gemini enabled (model=%s)", model)
+	} else {
+		log.Printf("AI mode: Stubbed responses (no GEMINI_API_KEY set)")
+	}
+
+	// Initialize advanced geospatial service
+	geospatialService := services.NewGeospatialService(redisService, firestoreService, userService)
+	for _, eventType := range []string{models.DomainEventHotspotCreated, models.DomainEventHotspotUpdated, models.DomainEventHotspotDeleted} {
+		eventBus.Subscribe(eventType, "geo_cache_sync", geospatialService.SyncHotspotCache)
+	}
+	trendingService := services.NewTrendingService(redisService, hotspotService)
+	feedbackService := services.NewFeedbackService(firestoreService, profileService, notificationService)
+	analyticsService := services.NewAnalyticsService(firestoreService, hotspotService, feedbackService, health, jobQueue)
+	for _, eventType := range []string{models.DomainEventUserJoined, models.DomainEventUserLeft, models.DomainEventUserCheckedIn} {
+		eventBus.Subscribe(eventType, "occupancy_timeline", analyticsService.RecordOccupancy)
+	}
+	// Platform-wide usage for the admin dashboard, rolled up nightly
+	platformMetrics := services.NewPlatformMetricsService(firestoreService, redisService, userService, consentService)
+	for _, eventType := range []string{models.DomainEventHotspotCreated, models.DomainEventUserJoined} {
+		eventBus.Subscribe(eventType, "platform_metrics", platformMetrics.HandleDomainEvent)
+	}
+	aiService.OnMoodScored(platformMetrics.RecordWellbeing)
+	locationFuzzer := services.NewLocationFuzzer(userService, profileService)
+	locationSharingService := services.NewLocationSharingService(hotspotService, profileService, userService, locationFuzzer, consentService)
+	// Revoking a consent removes what was collected under it
+	consentService.OnRevoked(func(userID, purpose string) {
+		switch purpose {
+		case models.ConsentLocation:
+			locationSharingService.StopAllFor(userID)
+			if err := userLocationService.ClearLocation(userID); err != nil {
+				log.Printf("Failed to clear location after consent revocation for user %s: %v", userID, err)
+			}
+		case models.ConsentAIProcessing:
+			if _, err := aiService.SetInsightsConsent(ctx, userID, false); err != nil {
+				log.Printf("Failed to turn off AI insights after consent revocation for user %s: %v", userID, err)
+			}
+		}
+	})
+	proximityService := services.NewProximityService(userService, profileService, hotspotService, notificationService)
+	// Nearby friends hear about hotspots going live and joins from the event bus
+	for _, eventType := range []string{models.DomainEventHotspotPublished, models.DomainEventUserJoined} {
+		eventBus.Subscribe(eventType, "proximity_alerts", proximityService.HandleDomainEvent)
+	}
+	safetyService := services.NewSafetyService(firestoreService, userService, friendsService, hotspotService, notificationService)
+	safetyService.SetAlertSender(smsGateway)
+	historyService := services.NewHistoryService(firestoreService, userService, friendsService)
+	historyService.OnHotspotEnded(feedbackService.RequestFeedback)
+	hostVerificationService := services.NewHostVerificationService(userService, profileService, historyService, redisService)
+	// Online and last-seen status from heartbeats and the events WebSocket
+	presenceService := services.NewPresenceService(redisService, userService, profileService)
+	wsTicketService := services.NewWebSocketTicketService(redisService)
+	profileViewService := services.NewProfileViewService(userService, profileService, friendsService, hotspotService, redisService, nicknameService, presenceService)
+
+	// Places autocomplete proxy. If PLACES_API_KEY is set, real provider calls are made.
+	placesService := services.NewPlacesService(redisService, quotaService)
+	if placesService.IsConfigured() {
+		log.Printf("Places mode: provider enabled")
+	} else {
+		log.Printf("Places mode: empty suggestions (no PLACES_API_KEY set)")
+	}
+
+	// Public event feeds imported as hotspots so new cities are not empty
+	eventImportService := services.NewEventImportService(firestoreService, hotspotService, categoryService)
+
+	// Weekly email digest of upcoming hotspots, nearby picks and friend activity
+	digestService, err := services.NewDigestService(firestoreService, userService, profileService, hotspotService, emailSender, consentService, jobQueue, secrets)
+	if err != nil {
+		return nil, fmt.Errorf("initialize digest: %w", err)
+	}
+
+	// Travel times for search results. ROUTING_PROVIDER picks osrm or google; otherwise they are estimated.
+	travelTimeService := services.NewTravelTimeService(redisService)
+	log.Printf("Routing mode: %s", travelTimeService.ProviderName())
+
+	// Short links to hotspots and profiles, opening friend-list hotspots when a host shares them
+	shareLinkService := services.NewShareLinkService(firestoreService, hotspotService, userService, profileViewService)
+
+	// Anonymous reads for the website are throttled per IP
+	publicRateLimiter := services.NewPublicRateLimiter(redisService)
+
+	// How long AI sessions, locations and chats are kept, published at /privacy/policy
+	retentionPolicy := services.NewRetentionPolicy()
+
+	// Campus and organization deployments: members only see their own organization
+	orgService := services.NewOrganizationService(firestoreService, userService)
+
+	// Maintenance jobs. With several replicas sharing Redis only the elected leader runs them.
+	scheduler := services.NewScheduler(redisService)
+	scheduler.Register("phone_verification_cleanup", 15*time.Minute, func(ctx context.Context) error {
+		phoneVerificationService.CleanupExpiredVerifications()
+		return nil
+	})
+	scheduler.Register("hotspot_archival", 6*time.Hour, func(ctx context.Context) error {
+		// Hotspots that ended over 30 days ago leave search and browse
+		_, err := hotspotService.ArchiveEndedHotspots(opts.Clock.Now().AddDate(0, 0, -30))
+		return err
+	})
+	scheduler.Register("chat_retention", time.Hour, func(ctx context.Context) error {
+		// Ephemeral messages after 24 hours, whole chats once their hotspot ended CHAT_RETENTION_DAYS ago
+		if _, err := chatService.PurgeExpiredMessages(opts.Clock.Now()); err != nil {
+			return err
+		}
+		// Voice notes go with their messages
+		_, err := voiceNoteService.PurgeOrphaned(ctx, opts.Clock.Now())
+		return err
+	})
+	scheduler.Register("ai_session_purge", 24*time.Hour, func(ctx context.Context) error {
+		aiService.PurgeStaleSessions(opts.Clock.Now().Add(-retentionPolicy.AISessions))
+		return nil
+	})
+	scheduler.Register("location_retention", 24*time.Hour, func(ctx context.Context) error {
+		_, err := userLocationService.PurgeStaleLocations(opts.Clock.Now().Add(-retentionPolicy.Locations))
+		return err
+	})
+	scheduler.Register("cache_purge", 10*time.Minute, func(ctx context.Context) error {
+		placesService.PurgeExpiredCache()
+		travelTimeService.PurgeExpiredCache()
+		hostVerificationService.PurgeExpiredCache()
+		profileViewService.PurgeExpiredCache()
+		nicknameService.PurgeExpiredReservations(opts.Clock.Now())
+		presenceService.PurgeExpired()
+		wsTicketService.PurgeExpired()
+		publicRateLimiter.PurgeExpired()
+		quotaService.PurgeExpired()
+		loginSecurityService.PurgeExpired()
+		firestoreGuard.PurgeExpired()
+		return nil
+	})
+	scheduler.Register("outbox_purge", time.Hour, func(ctx context.Context) error {
+		_, err := outbox.PurgeDispatched(time.Now().Add(-24 * time.Hour))
+		return err
+	})
+	scheduler.Register("ticket_maintenance", 5*time.Minute, paymentService.RunMaintenance)
+	scheduler.Register("event_import", 6*time.Hour, eventImportService.RunAll)
+	scheduler.Register("firestore_budget", 5*time.Minute, firestoreGuard.CheckBudgets)
+	scheduler.Register("metrics_rollup", 24*time.Hour, platformMetrics.RollupPending)
+	scheduler.Register("analytics_aggregation", 24*time.Hour, func(ctx context.Context) error {
+		return analyticsService.AggregateAll()
+	})
+	scheduler.Register("friend_request_expiry", time.Hour, func(ctx context.Context) error {
+		_, err := friendsService.ExpireFriendRequests()
+		return err
+	})
+	scheduler.Register("safety_timer_alerts", 30*time.Second, func(ctx context.Context) error {
+		safetyService.AlertOverdue(opts.Clock.Now())
+		return nil
+	})
+	scheduler.Register("attendance_history", 10*time.Minute, func(ctx context.Context) error {
+		_, err := historyService.RecordEndedHotspots(opts.Clock.Now())
+		return err
+	})
+	// Live location shares are kept in each instance's memory, so every instance expires its own
+	scheduler.RegisterLocal("location_share_expiry", 30*time.Second, func(ctx context.Context) error {
+		locationSharingService.ExpireShares(opts.Clock.Now())
+		return nil
+	})
+	scheduler.Register("email_digest", time.Hour, func(ctx context.Context) error {
+		// Each user gets at most one digest a week, so running hourly only spreads them out
+		_, err := digestService.EnqueueDue(ctx)
+		return err
+	})
+	scheduler.Start(ctx)
+	health.Start(ctx)
+	firestoreGuard.Start(ctx)
+	eventBus.Start(ctx)
+	outbox.StartDispatcher(ctx)
+
+	// Initialize handlers
+	authHandler := handlers.NewAuthHandler(authService, userService, loginSecurityService, nicknameService)
+	userHandler := handlers.NewUserHandler(userService, profileViewService, userLocationService)
+	profileHandler := handlers.NewProfileHandler(profileService, phoneVerificationService, hostVerificationService, userLocationService, onboardingService)
+	friendsHandler := handlers.NewFriendsHandler(friendsService, gamificationService, notificationService, presenceService)
+	friendListHandler := handlers.NewFriendListHandler(friendListService)
+	historyHandler := handlers.NewHistoryHandler(historyService)
+	feedbackHandler := handlers.NewFeedbackHandler(feedbackService, hotspotService)
+	checkInQRService, err := services.NewCheckInQRService(hotspotService, secrets)
+	if err != nil {
+		return nil, fmt.Errorf("initialize check-in codes: %w", err)
+	}
+	safetyHandler := handlers.NewSafetyHandler(safetyService, hotspotService, analyticsService, checkInQRService)
+	hotspotHandler := handlers.NewHotspotHandler(hotspotService, geospatialService, gamificationService, trendingService, analyticsService, notificationService, hostVerificationService, hotspotReadCache, travelTimeService, userLocationService, shareLinkService, profileService, presenceService)
+	chatHandler := handlers.NewChatHandler(chatService, hotspotService, authService, trendingService, analyticsService, locationSharingService, platformMetrics, notificationService, voiceNoteService, wsTicketService)
+	aiHandler := handlers.NewAIChatHandler(aiService, platformMetrics, hotspotHandler)
+	placesHandler := handlers.NewPlacesHandler(placesService)
+	calendarHandler := handlers.NewCalendarHandler(calendarService, hotspotService)
+	categoryHandler := handlers.NewCategoryHandler(categoryService)
+	resourceHandler := handlers.NewResourceHandler(resourceService)
+	tagHandler := handlers.NewTagHandler(tagService)
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	eventsHandler := handlers.NewEventsHandler(eventService, authService, presenceService, wsTicketService)
+	smsHandler := handlers.NewSMSHandler(smsGateway)
+	schedulerHandler := handlers.NewSchedulerHandler(scheduler, jobQueue)
+	eventImportHandler := handlers.NewEventImportHandler(eventImportService)
+	publicHandler := handlers.NewPublicHandler(hotspotService)
+	shareHandler := handlers.NewShareHandler(shareLinkService)
+	digestHandler := handlers.NewDigestHandler(digestService)
+	metricsHandler := handlers.NewMetricsHandler(platformMetrics, quotaService, firestoreGuard)
+	experimentHandler := handlers.NewExperimentHandler(experimentService)
+	promptHandler := handlers.NewPromptHandler(promptService)
+	interestHandler := handlers.NewInterestHandler()
+	presenceHandler := handlers.NewPresenceHandler(presenceService)
+	privacyHandler := handlers.NewPrivacyHandler(retentionPolicy, consentService)
+	organizationHandler := handlers.NewOrganizationHandler(orgService)
+	clubHandler := handlers.NewClubHandler(clubService, hotspotService)
+	paymentHandler := handlers.NewPaymentHandler(paymentService, hotspotService)
+	healthHandler := handlers.NewHealthHandler(health)
+
+	// Mount every route module on the router
+	router := routes.NewRouter(&routes.Deps{
+		Auth:                middleware.AuthMiddleware(authService, platformMetrics, orgService),
+		Admin:               middleware.AdminMiddleware(),
+		OrgAdmin:            middleware.OrgAdminMiddleware(orgService),
+		CORS:                middleware.NewCORSPolicy(),
+		PublicRateLimit:     middleware.RateLimitMiddleware(publicRateLimiter),
+		ServiceMode:         middleware.ServiceModeMiddleware(health),
+		FirestoreBudget:     middleware.FirestoreBudgetMiddleware(firestoreGuard),
+		AuthHandler:         authHandler,
+		UserHandler:         userHandler,
+		ProfileHandler:      profileHandler,
+		FriendsHandler:      friendsHandler,
+		FriendListHandler:   friendListHandler,
+		HistoryHandler:      historyHandler,
+		FeedbackHandler:     feedbackHandler,
+		SafetyHandler:       safetyHandler,
+		HotspotHandler:      hotspotHandler,
+		ChatHandler:         chatHandler,
+		AIHandler:           aiHandler,
+		PlacesHandler:       placesHandler,
+		CalendarHandler:     calendarHandler,
+		CategoryHandler:     categoryHandler,
+		ResourceHandler:     resourceHandler,
+		TagHandler:          tagHandler,
+		NotificationHandler: notificationHandler,
+		EventsHandler:       eventsHandler,
+		SMSHandler:          smsHandler,
+		SchedulerHandler:    schedulerHandler,
+		EventImportHandler:  eventImportHandler,
+		PublicHandler:       publicHandler,
+		ShareHandler:        shareHandler,
+		DigestHandler:       digestHandler,
+		MetricsHandler:      metricsHandler,
+		ExperimentHandler:   experimentHandler,
+		PromptHandler:       promptHandler,
+		InterestHandler:     interestHandler,
+		PresenceHandler:     presenceHandler,
+		PrivacyHandler:      privacyHandler,
+		OrganizationHandler: organizationHandler,
+		ClubHandler:         clubHandler,
+		PaymentHandler:      paymentHandler,
+		HealthHandler:       healthHandler,
+	})
+
+	return &App{Router: router, firestore: firestoreService, redis: redisService, users: userService, jobs: jobQueue}, nil
+}
+
+// StartWorkers runs queued jobs until ctx is cancelled or the app is closed
+func (a *App) StartWorkers(ctx context.Context) {
+	a.jobs.Start(ctx)
+}
+
+// Close lets running jobs finish, then releases the storage clients
+func (a *App) Close() {
+	a.jobs.Stop(services.JobShutdownGrace)
+	a.users.Close()
+	if a.redis != nil {
+		a.redis.Close()
+	}
+	a.firestore.Close()
+}
@@ -0,0 +1,245 @@
+// End-to-end tests: the fully wired application behind an httptest server with in-memory storage
+package e2e
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"unalone-backend/internal/apiclient"
+	"unalone-backend/internal/app"
+	"unalone-backend/internal/models"
+	"unalone-backend/internal/routes"
+
+	"github.com/gin-gonic/gin"
+)
+
+// testClock runs alongside the wall clock, shifted by however far the tests advanced it
+type testClock struct {
+	mu     sync.Mutex
+	offset time.Duration
+}
+
+func (tc *testClock) Now() time.Time {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	return time.Now().Add(tc.offset)
+}
+
+// Advance moves the services' time forward by d
+func (tc *testClock) Advance(d time.Duration) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	tc.offset += d
+}
+
+var (
+	client *apiclient.Client
+	clock  = &testClock{}
+)
+
+// TestMain starts the application once with mock Firestore storage and without Redis.
+// Mock user records are written to a temporary directory. Request logs are discarded
+// unless E2E_VERBOSE is set.
+func TestMain(m *testing.M) {
+	os.Exit(run(m))
+}
+
+func run(m *testing.M) int {
+	dir, err := os.MkdirTemp("", "unalone-e2e")
+	if err != nil {
+		log.Printf("Failed to create work directory: %v", err)
+		return 1
+	}
+	defer os.RemoveAll(dir)
+	if err := os.Chdir(dir); err != nil {
+		log.Printf("Failed to enter work directory: %v", err)
+		return 1
+	}
+
+	os.Setenv("APP_MODE", "test")
+	os.Setenv("REDIS_DISABLED", "true")
+	gin.SetMode(gin.ReleaseMode)
+	if os.Getenv("E2E_VERBOSE") == "" {
+		gin.DefaultWriter = io.Discard
+		log.SetOutput(io.Discard)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	application, err := app.New(ctx, app.Options{Clock: clock})
+	if err != nil {
+		log.Printf("Failed to start the app: %v", err)
+		return 1
+	}
+	defer application.Close()
+	application.StartWorkers(ctx)
+
+	server := httptest.NewServer(application.Router)
+	defer server.Close()
+	client = apiclient.New(server.URL, server.Client())
+
+	return m.Run()
+}
+
+// register creates an account named after nickname and logs it in
+func register(t *testing.T, nickname string) *apiclient.User {
+	t.Helper()
+	user, err := client.SignUp(models.AuthRequest{
+		Email:    nickname + "@e2e.unalone.test",
+		Password: "e2e-password",
+		RealName: "E2E " + nickname,
+		Nickname: nickname,
+	})
+	if err != nil {
+		t.Fatalf("register %s: %v", nickname, err)
+	}
+	return user
+}
+
+// expect calls the API and fails the test unless it answers with status want
+func expect(t *testing.T, want int, method, path, token string, body, out interface{}) {
+	t.Helper()
+	if err := client.Expect(want, method, path, token, body, out); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// createHotspot hosts a public hotspot starting after the given delay on the test clock
+func createHotspot(t *testing.T, host *apiclient.User, startsIn time.Duration) *models.Hotspot {
+	t.Helper()
+	scheduled := clock.Now().Add(startsIn)
+	var hotspot models.Hotspot
+	expect(t, http.StatusCreated, "POST", "/hotspots/", host.Token, models.CreateHotspotRequest{
+		Name:          "E2E board games",
+		Description:   "Board games for the end-to-end suite",
+		Category:      "cafe",
+		Location:      models.HotspotLocation{Latitude: 12.97, Longitude: 77.59},
+		Address:       models.HotspotAddress{City: "Bengaluru", Country: "India"},
+		MaxCapacity:   10,
+		IsPublic:      true,
+		ScheduledTime: &scheduled,
+	}, &hotspot)
+	return &hotspot
+}
+
+var pathParam = regexp.MustCompile(`:[^/]+`)
+
+// TestProtectedRoutesRequireAuth calls every documented non-public route without a token
+func TestProtectedRoutesRequireAuth(t *testing.T) {
+	for _, op := range routes.Operations {
+		if op.Public {
+			continue
+		}
+		path := pathParam.ReplaceAllString(op.Path, "e2e")
+		resp, err := client.Do(op.Method, path, "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.Status != http.StatusUnauthorized {
+			t.Errorf("%s %s without a token: status %d, want 401", op.Method, path, resp.Status)
+		}
+	}
+}
+
+func TestRegisterAndLogin(t *testing.T) {
+	user := register(t, "e2e_auth")
+	var profile models.User
+	expect(t, http.StatusOK, "GET", "/users/profile", user.Token, nil, &profile)
+	if profile.ID != user.ID {
+		t.Fatalf("profile id %q, want %q", profile.ID, user.ID)
+	}
+	expect(t, http.StatusUnauthorized, "POST", "/auth/login", "", models.AuthRequest{Email: user.Email, Password: "wrong-password"}, nil)
+	expect(t, http.StatusUnauthorized, "GET", "/users/profile", "not-a-token", nil, nil)
+}
+
+func TestHotspotJoinChatLeave(t *testing.T) {
+	host := register(t, "e2e_host")
+	guest := register(t, "e2e_guest")
+	hotspot := createHotspot(t, host, 24*time.Hour)
+	base := "/hotspots/" + hotspot.ID
+
+	// Only attendees may open the chat
+	if conn, err := client.Dial(base+"/chat/ws", guest.Token); err == nil {
+		conn.Close()
+		t.Fatal("guest opened the chat before joining")
+	}
+	expect(t, http.StatusOK, "POST", base+"/join", guest.Token, nil, hotspot)
+	if !contains(hotspot.Attendees, guest.ID) {
+		t.Fatal("guest missing from attendees after join")
+	}
+
+	hostConn, err := client.Dial(base+"/chat/ws", host.Token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer hostConn.Close()
+	guestConn, err := client.Dial(base+"/chat/ws", guest.Token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer guestConn.Close()
+
+	if err := guestConn.WriteJSON(map[string]string{"type": "message", "content": "hello from e2e"}); err != nil {
+		t.Fatal(err)
+	}
+	var received models.ChatMessage
+	if err := apiclient.ReadJSON(hostConn, 5*time.Second, &received); err != nil {
+		t.Fatalf("host did not receive the chat message: %v", err)
+	}
+	if received.Content != "hello from e2e" || received.UserID != guest.ID {
+		t.Fatalf("host received %+v", received)
+	}
+
+	var history []*models.ChatMessage
+	expect(t, http.StatusOK, "GET", base+"/chat/messages", host.Token, nil, &history)
+	if len(history) != 1 || history[0].ID != received.ID {
+		t.Fatalf("chat history has %d messages, want the one sent", len(history))
+	}
+
+	expect(t, http.StatusOK, "POST", base+"/leave", guest.Token, nil, hotspot)
+	if contains(hotspot.Attendees, guest.ID) {
+		t.Fatal("guest still attending after leave")
+	}
+	expect(t, http.StatusForbidden, "GET", base+"/chat/messages", guest.Token, nil, nil)
+}
+
+// TestCheckInOpensAtStart moves the clock to the hotspot's start to open check-ins
+func TestCheckInOpensAtStart(t *testing.T) {
+	host := register(t, "e2e_checkin_host")
+	guest := register(t, "e2e_checkin_guest")
+	hotspot := createHotspot(t, host, 24*time.Hour)
+	base := "/hotspots/" + hotspot.ID
+	expect(t, http.StatusOK, "POST", base+"/join", guest.Token, nil, nil)
+
+	onSite := models.CheckInRequest{Latitude: hotspot.Location.Latitude, Longitude: hotspot.Location.Longitude}
+	err := client.Expect(http.StatusOK, "POST", base+"/checkin", guest.Token, onSite, nil)
+	var statusErr *apiclient.StatusError
+	if !errors.As(err, &statusErr) || statusErr.Status != http.StatusBadRequest {
+		t.Fatalf("check-in a day early: %v, want status 400", err)
+	}
+
+	clock.Advance(24 * time.Hour)
+	var checkIn models.CheckInResponse
+	expect(t, http.StatusOK, "POST", base+"/checkin", guest.Token, onSite, &checkIn)
+	if checkIn.HotspotID != hotspot.ID {
+		t.Fatalf("checked in to %q, want %q", checkIn.HotspotID, hotspot.ID)
+	}
+}
+
+func contains(ids []string, id string) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 
@@ -8,14 +10,17 @@ import (
 	"unalone-backend/internal/services"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 )
 
 type AIChatHandler struct {
-	svc services.AIChatService
+	svc      services.AIChatService
+	metrics  *services.PlatformMetricsService
+	hotspots *HotspotHandler
 }
 
-func NewAIChatHandler(svc services.AIChatService) *AIChatHandler {
-	return &AIChatHandler{svc: svc}
+func NewAIChatHandler(svc services.AIChatService, pm *services.PlatformMetricsService, hh *HotspotHandler) *AIChatHandler {
+	return &AIChatHandler{svc: svc, metrics: pm, hotspots: hh}
 }
 
 func (h *AIChatHandler) CreateSession(c *gin.Context) {
@@ -30,6 +35,9 @@ func (h *AIChatHandler) CreateSession(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	if h.metrics != nil {
+		h.metrics.Increment(services.MetricAISessions)
+	}
 	c.JSON(http.StatusOK, sess)
 }
 
@@ -77,8 +85,189 @@ func (h *AIChatHandler) SendMessage(c *gin.Context) {
 	}
 	userMsg, aiMsg, err := h.svc.SendMessage(c.Request.Context(), userID, id, req.Content)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		c.JSON(aiErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
+	if h.metrics != nil {
+		h.metrics.Increment(services.MetricAIMessages)
+	}
 	c.JSON(http.StatusOK, gin.H{"user": userMsg, "ai": aiMsg})
 }
+
+// AddParticipant shares a session with a friend as read-only or co-chat
+func (h *AIChatHandler) AddParticipant(c *gin.Context) {
+	var req models.AddAIParticipantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	sess, err := h.svc.AddParticipant(c.Request.Context(), c.GetString("userID"), c.Param("id"), req.UserID, req.Role)
+	if err != nil {
+		c.JSON(aiErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, sess)
+}
+
+// UpdateParticipant changes a participant's role
+func (h *AIChatHandler) UpdateParticipant(c *gin.Context) {
+	var req models.UpdateAIParticipantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	sess, err := h.svc.UpdateParticipant(c.Request.Context(), c.GetString("userID"), c.Param("id"), c.Param("userId"), req.Role)
+	if err != nil {
+		c.JSON(aiErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, sess)
+}
+
+// RemoveParticipant unshares a session; participants can use it to leave
+func (h *AIChatHandler) RemoveParticipant(c *gin.Context) {
+	sess, err := h.svc.RemoveParticipant(c.Request.Context(), c.GetString("userID"), c.Param("id"), c.Param("userId"))
+	if err != nil {
+		c.JSON(aiErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, sess)
+}
+
+// DraftHotspot asks the assistant to propose a hotspot from the conversation
+func (h *AIChatHandler) DraftHotspot(c *gin.Context) {
+	var req models.DraftHotspotRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	draft, err := h.svc.DraftHotspot(c.Request.Context(), c.GetString("userID"), c.Param("id"), &req)
+	if err != nil {
+		c.JSON(aiErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, draft)
+}
+
+// ConfirmHotspotDraft creates the drafted hotspot. The body, if any, is the edited
+// CreateHotspotRequest to use instead of the draft.
+func (h *AIChatHandler) ConfirmHotspotDraft(c *gin.Context) {
+	var edited *models.CreateHotspotRequest
+	if c.Request.ContentLength > 0 {
+		edited = &models.CreateHotspotRequest{}
+		if err := c.ShouldBindJSON(edited); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	userID := c.GetString("userID")
+	hotspot, err := h.svc.ConfirmHotspotDraft(c.Request.Context(), userID, c.Param("id"), c.Param("draftId"), edited, func(req *models.CreateHotspotRequest) (*models.Hotspot, error) {
+		// An unedited draft has not been through binding yet
+		if err := binding.Validator.ValidateStruct(req); err != nil {
+			return nil, fmt.Errorf("%w: %v", errIncompleteDraft, err)
+		}
+		return h.hotspots.Create(userID, req)
+	})
+	if err != nil {
+		status := aiErrorStatus(err)
+		if status == http.StatusInternalServerError {
+			// Everything else comes from creating the hotspot, like the hotspot endpoint
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, hotspot)
+}
+
+// errIncompleteDraft is returned when a confirmed draft still misses required fields
+var errIncompleteDraft = errors.New("draft is incomplete; send the completed hotspot")
+
+// GetInsights returns the caller's private mood trend over the last days (default and at most 90)
+func (h *AIChatHandler) GetInsights(c *gin.Context) {
+	days := models.MaxAIInsightDays
+	if raw := c.Query("days"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > models.MaxAIInsightDays {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("days must be between 1 and %d", models.MaxAIInsightDays)})
+			return
+		}
+		days = n
+	}
+	insights, err := h.svc.GetInsights(c.Request.Context(), c.GetString("userID"), days)
+	if err != nil {
+		c.JSON(aiErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, insights)
+}
+
+// UpdateInsightsConsent opts the caller in to mood insights, or out, deleting what was collected
+func (h *AIChatHandler) UpdateInsightsConsent(c *gin.Context) {
+	var req models.UpdateAIInsightsConsentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	insights, err := h.svc.SetInsightsConsent(c.Request.Context(), c.GetString("userID"), *req.Enabled)
+	if err != nil {
+		c.JSON(aiErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, insights)
+}
+
+// ExportSession downloads a session's transcript as JSON (default) or markdown
+func (h *AIChatHandler) ExportSession(c *gin.Context) {
+	format := c.DefaultQuery("format", models.AIExportJSON)
+	if format != models.AIExportJSON && format != models.AIExportMarkdown {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json or markdown"})
+		return
+	}
+	userID := c.GetString("userID")
+	export, err := h.svc.ExportSession(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		c.JSON(aiErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	// Transcripts are sensitive; keep them out of shared caches
+	c.Header("Cache-Control", "no-store")
+	if format == models.AIExportMarkdown {
+		c.Header("Content-Disposition", `attachment; filename="ai-session-`+export.Session.ID+`.md"`)
+		c.Data(http.StatusOK, "text/markdown; charset=utf-8", []byte(services.RenderSessionMarkdown(export, userID)))
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="ai-session-`+export.Session.ID+`.json"`)
+	c.JSON(http.StatusOK, export)
+}
+
+// DeleteSession permanently deletes the caller's session and everything in it
+func (h *AIChatHandler) DeleteSession(c *gin.Context) {
+	id := c.Param("id")
+	if err := h.svc.DeleteSession(c.Request.Context(), c.GetString("userID"), id); err != nil {
+		c.JSON(aiErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"id": id, "deleted": true})
+}
+
+func aiErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrAINotSessionOwner), errors.Is(err, services.ErrAIReadOnly), errors.Is(err, services.ErrAINotFriend), errors.Is(err, services.ErrAINotOwnerDelete), errors.Is(err, services.ErrAIConsentRequired):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrAITooManyParticipants), errors.Is(err, services.ErrAIDraftConfirmed):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrAIDraftNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrAIDraftUnavailable):
+		return http.StatusBadGateway
+	case err.Error() == "content required":
+		return http.StatusBadRequest
+	case errors.Is(err, services.ErrAIParticipantNotFound), errors.Is(err, services.ErrAISessionNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
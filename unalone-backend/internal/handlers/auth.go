@@ -2,7 +2,10 @@
 package handlers
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"unalone-backend/internal/models"
@@ -11,15 +14,19 @@ import (
 
 // AuthHandler handles authentication endpoints
 type AuthHandler struct {
-	authService *services.AuthService
-	userService *services.UserService
+	authService   *services.AuthService
+	userService   *services.UserService
+	loginSecurity *services.LoginSecurityService
+	nicknames     *services.NicknameService
 }
 
 // NewAuthHandler creates a new authentication handler
-func NewAuthHandler(authService *services.AuthService, userService *services.UserService) *AuthHandler {
+func NewAuthHandler(authService *services.AuthService, userService *services.UserService, loginSecurity *services.LoginSecurityService, nicknames *services.NicknameService) *AuthHandler {
 	return &AuthHandler{
-		authService: authService,
-		userService: userService,
+		authService:   authService,
+		userService:   userService,
+		loginSecurity: loginSecurity,
+		nicknames:     nicknames,
 	}
 }
 
@@ -29,34 +36,40 @@ func (ah *AuthHandler) Register(c *gin.Context) {
 
 	// Bind JSON request
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage("Invalid request format"))
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format"))
 		return
 	}
 
 	// Validate required fields for registration
 	if req.RealName == "" || req.Nickname == "" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage("Real name and nickname are required"))
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Real name and nickname are required"))
+		return
+	}
+
+	// Recently released nicknames stay reserved for their previous owner
+	if err := ah.nicknames.CheckAvailable("", req.Nickname, services.Now()); err != nil {
+		c.JSON(http.StatusConflict, errorResponse(c, err.Error()))
 		return
 	}
 
 	// Hash password
 	hashedPassword, err := ah.authService.HashPassword(req.Password)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponseWithMessage("Error processing password"))
+		c.JSON(http.StatusInternalServerError, errorResponse(c, "Error processing password"))
 		return
 	}
 
 	// Create user
 	user, err := ah.userService.CreateUser(req.Email, req.RealName, req.Nickname, hashedPassword)
 	if err != nil {
-		c.JSON(http.StatusConflict, models.ErrorResponseWithMessage(err.Error()))
+		c.JSON(http.StatusConflict, errorResponse(c, err.Error()))
 		return
 	}
 
 	// Generate JWT token
 	token, err := ah.authService.GenerateToken(user.ID, user.Email)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponseWithMessage("Error generating token"))
+		c.JSON(http.StatusInternalServerError, errorResponse(c, "Error generating token"))
 		return
 	}
 
@@ -67,7 +80,7 @@ func (ah *AuthHandler) Register(c *gin.Context) {
 		Message: "Registration successful",
 	}
 
-	c.JSON(http.StatusCreated, models.SuccessResponse(response, "User registered successfully"))
+	c.JSON(http.StatusCreated, successResponse(c, response, "User registered successfully"))
 }
 
 // Login handles user login
@@ -76,27 +89,38 @@ func (ah *AuthHandler) Login(c *gin.Context) {
 
 	// Bind JSON request
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage("Invalid request format"))
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format"))
+		return
+	}
+
+	// Refuse attempts while the account or IP is locked out, without checking the password
+	var locked *services.LoginLockedError
+	if err := ah.loginSecurity.CheckAllowed(req.Email, c.ClientIP()); errors.As(err, &locked) {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+		c.JSON(http.StatusTooManyRequests, errorResponse(c, err.Error()))
 		return
 	}
 
 	// Get user by email
 	user, err := ah.userService.GetUserByEmail(req.Email)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, models.ErrorResponseWithMessage("Invalid email or password"))
+		ah.loginSecurity.RecordFailure(req.Email, c.ClientIP())
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Invalid email or password"))
 		return
 	}
 
 	// Verify password
 	if err := ah.authService.VerifyPassword(user.PasswordHash, req.Password); err != nil {
-		c.JSON(http.StatusUnauthorized, models.ErrorResponseWithMessage("Invalid email or password"))
+		ah.loginSecurity.RecordFailure(req.Email, c.ClientIP())
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Invalid email or password"))
 		return
 	}
+	ah.loginSecurity.RecordSuccess(user, c.ClientIP(), c.Request.UserAgent())
 
 	// Generate JWT token
 	token, err := ah.authService.GenerateToken(user.ID, user.Email)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponseWithMessage("Error generating token"))
+		c.JSON(http.StatusInternalServerError, errorResponse(c, "Error generating token"))
 		return
 	}
 
@@ -107,7 +131,7 @@ func (ah *AuthHandler) Login(c *gin.Context) {
 		Message: "Login successful",
 	}
 
-	c.JSON(http.StatusOK, models.SuccessResponse(response, "Login successful"))
+	c.JSON(http.StatusOK, successResponse(c, response, "Login successful"))
 }
 
 // RefreshToken handles token refresh
@@ -115,22 +139,22 @@ func (ah *AuthHandler) RefreshToken(c *gin.Context) {
 	// Get user from context (set by auth middleware)
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, models.ErrorResponseWithMessage("Unauthorized"))
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
 		return
 	}
 
 	userEmail, exists := c.Get("userEmail")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, models.ErrorResponseWithMessage("Unauthorized"))
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
 		return
 	}
 
 	// Generate new token
 	token, err := ah.authService.RefreshToken(userID.(string), userEmail.(string))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponseWithMessage("Error refreshing token"))
+		c.JSON(http.StatusInternalServerError, errorResponse(c, "Error refreshing token"))
 		return
 	}
 
-	c.JSON(http.StatusOK, models.SuccessResponse(gin.H{"token": token}, "Token refreshed successfully"))
+	c.JSON(http.StatusOK, successResponse(c, gin.H{"token": token}, "Token refreshed successfully"))
 }
@@ -0,0 +1,122 @@
+// Calendar handlers for ICS export and subscription feeds
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+
+	"unalone-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+const icsContentType = "text/calendar; charset=utf-8"
+
+// CalendarHandler handles calendar export endpoints
+type CalendarHandler struct {
+	calendarService *services.CalendarService
+	hotspotService  *services.HotspotService
+}
+
+// NewCalendarHandler creates a new calendar handler
+func NewCalendarHandler(cs *services.CalendarService, hs *services.HotspotService) *CalendarHandler {
+	return &CalendarHandler{
+		calendarService: cs,
+		hotspotService:  hs,
+	}
+}
+
+// HotspotCalendar exports a single hotspot as an ICS file
+func (ch *CalendarHandler) HotspotCalendar(c *gin.Context) {
+	hotspotID := c.Param("id")
+	if hotspotID == "" {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Hotspot ID is required"))
+		return
+	}
+
+	hotspot, err := ch.hotspotService.GetHotspot(hotspotID)
+	if err != nil || (hotspot.IsDraft && hotspot.CreatedBy != c.GetString("userID")) ||
+		!services.CanViewHotspot(hotspot, c.GetString("userID"), c.GetString("orgID")) {
+		c.JSON(http.StatusNotFound, errorResponse(c, "hotspot not found"))
+		return
+	}
+
+	ics, err := ch.calendarService.HotspotICS(hotspot)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="hotspot-`+hotspot.ID+`.ics"`)
+	c.Data(http.StatusOK, icsContentType, []byte(ics))
+}
+
+// GetFeedURL returns the current user's calendar subscription URL
+func (ch *CalendarHandler) GetFeedURL(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
+		return
+	}
+
+	token, err := ch.calendarService.GetFeedToken(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, gin.H{"url": ch.feedURL(c, token)}, "Calendar feed URL retrieved"))
+}
+
+// RotateFeedURL revokes the current subscription URL and issues a new one
+func (ch *CalendarHandler) RotateFeedURL(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
+		return
+	}
+
+	token, err := ch.calendarService.RotateFeedToken(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, gin.H{"url": ch.feedURL(c, token)}, "Calendar feed URL rotated"))
+}
+
+// Feed serves the joined-hotspots ICS feed. Calendar apps cannot send auth
+// headers, so the unguessable token in the URL authenticates the request.
+func (ch *CalendarHandler) Feed(c *gin.Context) {
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+	userID, err := ch.calendarService.UserIDForFeedToken(token)
+	if errors.Is(err, services.ErrCalendarFeedNotFound) {
+		c.JSON(http.StatusNotFound, errorResponse(c, err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+
+	ics, err := ch.calendarService.FeedICS(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.Data(http.StatusOK, icsContentType, []byte(ics))
+}
+
+// feedURL builds an absolute feed URL, preferring PUBLIC_BASE_URL when configured
+func (ch *CalendarHandler) feedURL(c *gin.Context, token string) string {
+	base := strings.TrimRight(strings.TrimSpace(os.Getenv("PUBLIC_BASE_URL")), "/")
+	if base == "" {
+		scheme := "http"
+		if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+			scheme = "https"
+		}
+		base = scheme + "://" + c.Request.Host
+	}
+	return base + "/api/v1/calendar/feeds/" + token + ".ics"
+}
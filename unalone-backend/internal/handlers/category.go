@@ -0,0 +1,73 @@
+// Category handlers for the hotspot taxonomy
+package handlers
+
+import (
+	"net/http"
+
+	"unalone-backend/internal/models"
+	"unalone-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CategoryHandler handles category taxonomy endpoints
+type CategoryHandler struct {
+	categoryService *services.CategoryService
+}
+
+// NewCategoryHandler creates a new category handler
+func NewCategoryHandler(cs *services.CategoryService) *CategoryHandler {
+	return &CategoryHandler{categoryService: cs}
+}
+
+// ListCategories returns the active category taxonomy with subcategories and icons
+func (ch *CategoryHandler) ListCategories(c *gin.Context) {
+	categories, err := ch.categoryService.ListCategories()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, categories, "Categories retrieved successfully"))
+}
+
+// UpsertCategory creates or updates a category (admin only)
+func (ch *CategoryHandler) UpsertCategory(c *gin.Context) {
+	categoryID := c.Param("id")
+	if categoryID == "" {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Category ID is required"))
+		return
+	}
+
+	var req models.UpsertCategoryRequest
+
+	// Bind JSON request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format: "+err.Error()))
+		return
+	}
+
+	category, err := ch.categoryService.UpsertCategory(categoryID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, category, "Category saved successfully"))
+}
+
+// DeactivateCategory hides a category from clients (admin only)
+func (ch *CategoryHandler) DeactivateCategory(c *gin.Context) {
+	categoryID := c.Param("id")
+	if categoryID == "" {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Category ID is required"))
+		return
+	}
+
+	if err := ch.categoryService.DeactivateCategory(categoryID); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, nil, "Category deactivated successfully"))
+}
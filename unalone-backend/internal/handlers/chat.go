@@ -2,8 +2,12 @@
 package handlers
 
 import (
+	"errors"
+	"io"
 	"log"
 	"net/http"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"unalone-backend/internal/models"
@@ -17,57 +21,169 @@ type ChatHandler struct {
 	chatService    *services.ChatService
 	hotspotService *services.HotspotService
 	authService    *services.AuthService
+	trending       *services.TrendingService
+	analytics      *services.AnalyticsService
+	locations      *services.LocationSharingService
+	metrics        *services.PlatformMetricsService
+	notifications  *services.NotificationService
+	voiceNotes     *services.VoiceNoteService
+	tickets        *services.WebSocketTicketService
 }
 
-func NewChatHandler(cs *services.ChatService, hs *services.HotspotService, as *services.AuthService) *ChatHandler {
-	return &ChatHandler{chatService: cs, hotspotService: hs, authService: as}
+func NewChatHandler(cs *services.ChatService, hs *services.HotspotService, as *services.AuthService, ts *services.TrendingService, an *services.AnalyticsService, ls *services.LocationSharingService, pm *services.PlatformMetricsService, ns *services.NotificationService, vns *services.VoiceNoteService, wts *services.WebSocketTicketService) *ChatHandler {
+	hh := &ChatHandler{chatService: cs, hotspotService: hs, authService: as, trending: ts, analytics: an, locations: ls, metrics: pm, notifications: ns, voiceNotes: vns, tickets: wts}
+	// Voice notes reach the room once transcoded, like any other message
+	if vns != nil {
+		vns.OnReady(hh.deliver)
+	}
+	// Tell the room when a share is stopped or expires so clients drop the marker
+	if ls != nil {
+		ls.OnStop(func(share *models.LocationShare) {
+			broadcastShare(ls, models.ChatFrameLocationStopped, share)
+		})
+	}
+	return hh
 }
 
-// Simple in-process hub per hotspot for broadcasting
+// Simple in-process hub per hotspot for broadcasting.
+// Chat messages are sent as-is; other frames carry a "type" field.
 type wsClient struct {
 	conn *websocket.Conn
-	send chan *models.ChatMessage
+	send chan interface{}
 	user string
+
+	fullSince int64 // UnixNano of the first frame dropped since send last had room, or 0
+	evicted   int32
+}
+
+const (
+	// chatMaxFrameBytes bounds inbound chat frames; larger frames close the connection
+	chatMaxFrameBytes = 8 << 10
+	// chatSendBuffer is how many outbound frames wait for a client before frames are dropped
+	chatSendBuffer = 64
+	// chatSlowConsumerTimeout is how long a client's buffer may stay full before it is disconnected
+	chatSlowConsumerTimeout = 5 * time.Second
+	// chatRateLimit frames per chatRateWindow are accepted from one connection; the excess is
+	// rejected, and a connection sending more than chatRateCloseLimit in a window is closed
+	chatRateLimit      = 20
+	chatRateCloseLimit = 3 * chatRateLimit
+	chatRateWindow     = 10 * time.Second
+	chatWriteTimeout   = 10 * time.Second
+	chatPingInterval   = 30 * time.Second
+	// chatIdleTimeout closes connections that answer neither pings nor send frames
+	chatIdleTimeout = 2 * chatPingInterval
+)
+
+// queue hands a frame to the client's writer without blocking. A client whose buffer stays
+// full is disconnected so it cannot fall ever further behind; it reconnects and catches up over REST.
+func (cli *wsClient) queue(frame interface{}) {
+	select {
+	case cli.send <- frame:
+		atomic.StoreInt64(&cli.fullSince, 0)
+	default:
+		now := time.Now().UnixNano()
+		if !atomic.CompareAndSwapInt64(&cli.fullSince, 0, now) &&
+			time.Duration(now-atomic.LoadInt64(&cli.fullSince)) >= chatSlowConsumerTimeout &&
+			atomic.CompareAndSwapInt32(&cli.evicted, 0, 1) {
+			log.Printf("[chat] disconnecting slow client %s", cli.user)
+			cli.conn.Close()
+		}
+	}
+}
+
+// frameWindow counts the frames a connection sent in the current rate window
+type frameWindow struct {
+	start time.Time
+	count int
 }
 
+// hit counts a frame and returns how many were sent in the window so far
+func (w *frameWindow) hit(now time.Time) int {
+	if now.Sub(w.start) >= chatRateWindow {
+		w.start = now
+		w.count = 0
+	}
+	w.count++
+	return w.count
+}
+
+// retryAfter is how many whole seconds remain in the window
+func (w *frameWindow) retryAfter(now time.Time) int {
+	return int((w.start.Add(chatRateWindow).Sub(now) + time.Second - 1) / time.Second)
+}
+
+// WebSocket subprotocols. Clients authenticate by offering wsSubprotocol together with
+// "bearer.<access token>"; the server selects wsSubprotocol.
+const (
+	wsSubprotocol  = "unalone.v1"
+	wsBearerPrefix = "bearer."
+)
+
 var upgrader = websocket.Upgrader{
 	ReadBufferSize:  1024,
 	WriteBufferSize: 1024,
-	CheckOrigin:     func(r *http.Request) bool { return true },
+	Subprotocols:    []string{wsSubprotocol},
+	// CheckOrigin defaults to same-host only until SetWebSocketOriginCheck is called
+}
+
+// SetWebSocketOriginCheck sets the origin policy for chat and event WebSockets
+func SetWebSocketOriginCheck(check func(r *http.Request) bool) {
+	upgrader.CheckOrigin = check
 }
 
 // hotspotID -> set of clients
-var rooms = make(map[string]map[*wsClient]bool)
+var (
+	roomsMu sync.Mutex
+	rooms   = make(map[string]map[*wsClient]bool)
+)
+
+// broadcastToRoom queues a frame for every client in a hotspot room, dropping it for slow clients
+func broadcastToRoom(hotspotID string, frame interface{}) {
+	roomsMu.Lock()
+	defer roomsMu.Unlock()
+	for cli := range rooms[hotspotID] {
+		cli.queue(frame)
+	}
+}
+
+// roomMembers returns the users connected to a hotspot room
+func roomMembers(hotspotID string) map[string]bool {
+	roomsMu.Lock()
+	defer roomsMu.Unlock()
+	members := make(map[string]bool, len(rooms[hotspotID]))
+	for cli := range rooms[hotspotID] {
+		members[cli.user] = true
+	}
+	return members
+}
+
+// broadcastShare queues a location frame for every client in the share's hotspot room,
+// with the position fuzzed for each viewer
+func broadcastShare(ls *services.LocationSharingService, frameType string, share *models.LocationShare) {
+	policy := ls.PolicyFor(share.UserID)
+	roomsMu.Lock()
+	defer roomsMu.Unlock()
+	for cli := range rooms[share.HotspotID] {
+		cli.queue(&models.LocationShareFrame{Type: frameType, Share: policy.Share(cli.user, share)})
+	}
+}
 
 func (hh *ChatHandler) ChatWebSocket(c *gin.Context) {
-	userID := ""
-	if userIDAny, exists := c.Get("userID"); exists {
-		userID = userIDAny.(string)
-	} else {
-		// Fallback for WebSocket clients that cannot set headers: token in query
-		token := c.Query("token")
-		if token == "" {
-			c.JSON(http.StatusUnauthorized, models.ErrorResponseWithMessage("Unauthorized"))
-			return
-		}
-		claims, err := hh.authService.ValidateToken(token)
-		if err != nil {
-			c.JSON(http.StatusUnauthorized, models.ErrorResponseWithMessage("Invalid token"))
-			return
-		}
-		userID = claims.UserID
+	userID, header, ok := webSocketUserID(c, hh.authService, hh.tickets)
+	if !ok {
+		return
 	}
 
 	hotspotID := c.Param("id")
 	if hotspotID == "" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage("Hotspot ID required"))
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Hotspot ID required"))
 		return
 	}
 
 	// Ensure membership
 	hotspot, err := hh.hotspotService.GetHotspot(hotspotID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, models.ErrorResponseWithMessage("Hotspot not found"))
+		c.JSON(http.StatusNotFound, errorResponse(c, "Hotspot not found"))
 		return
 	}
 	isMember := false
@@ -78,60 +194,145 @@ func (hh *ChatHandler) ChatWebSocket(c *gin.Context) {
 		}
 	}
 	if !isMember {
-		c.JSON(http.StatusForbidden, models.ErrorResponseWithMessage("Not a member of this hotspot"))
+		c.JSON(http.StatusForbidden, errorResponse(c, "Not a member of this hotspot"))
 		return
 	}
 
 	// Upgrade
-	ws, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	ws, err := upgrader.Upgrade(c.Writer, c.Request, header)
 	if err != nil {
 		log.Println("WS upgrade error:", err)
 		return
 	}
 
-	client := &wsClient{conn: ws, send: make(chan *models.ChatMessage, 16), user: userID}
+	ws.SetReadLimit(chatMaxFrameBytes)
+	// Pongs and frames keep the connection open; silent clients are dropped after chatIdleTimeout
+	ws.SetReadDeadline(time.Now().Add(chatIdleTimeout))
+	ws.SetPongHandler(func(string) error {
+		return ws.SetReadDeadline(time.Now().Add(chatIdleTimeout))
+	})
+
+	client := &wsClient{conn: ws, send: make(chan interface{}, chatSendBuffer), user: userID}
 	// Register client
+	roomsMu.Lock()
 	if rooms[hotspotID] == nil {
 		rooms[hotspotID] = make(map[*wsClient]bool)
 	}
 	rooms[hotspotID][client] = true
+	roomsMu.Unlock()
 
-	// Writer goroutine
+	// Replay what a reconnecting client missed before going live. The client joined the room
+	// first, so messages sent meanwhile wait in its buffer; the writer skips those already replayed.
+	replayed := hh.replay(ws, hotspotID, c.Query("resume"))
+
+	// Writer goroutine: delivers queued frames and pings the client
 	go func() {
+		ping := time.NewTicker(chatPingInterval)
 		defer func() {
+			ping.Stop()
 			ws.Close()
 		}()
-		for msg := range client.send {
-			ws.SetWriteDeadline(time.Now().Add(10 * time.Second))
-			if err := ws.WriteJSON(msg); err != nil {
-				break
+		for {
+			select {
+			case msg, ok := <-client.send:
+				if !ok {
+					return
+				}
+				if chatMsg, isChat := msg.(*models.ChatMessage); isChat && replayed[chatMsg.ID] {
+					continue
+				}
+				ws.SetWriteDeadline(time.Now().Add(chatWriteTimeout))
+				if err := ws.WriteJSON(msg); err != nil {
+					return
+				}
+			case <-ping.C:
+				if err := ws.WriteControl(websocket.PingMessage, nil, time.Now().Add(chatWriteTimeout)); err != nil {
+					return
+				}
 			}
 		}
 	}()
 
+	// Catch the new client up on attendees who are already sharing
+	if hh.locations != nil {
+		for _, share := range hh.locations.ActiveSharesFor(userID, hotspotID) {
+			client.queue(&models.LocationShareFrame{Type: models.ChatFrameLocation, Share: share})
+		}
+	}
+
 	// Reader loop
+	var window frameWindow
 	for {
 		var inbound struct {
-			Content string `json:"content"`
+			Type      string  `json:"type"`
+			Content   string  `json:"content"`
+			Latitude  float64 `json:"latitude"`
+			Longitude float64 `json:"longitude"`
+			Label     string  `json:"label"`
+			ClientID  string  `json:"client_id"`
 		}
 		if err := ws.ReadJSON(&inbound); err != nil {
+			if errors.Is(err, websocket.ErrReadLimit) {
+				log.Printf("[chat] closing connection of %s: frame over %d bytes", userID, chatMaxFrameBytes)
+			}
 			break
 		}
-		// Persist via service (also validates membership and sets nickname)
-		msg, err := hh.chatService.SendMessage(userID, hotspotID, inbound.Content)
-		if err != nil {
+		now := time.Now()
+		ws.SetReadDeadline(now.Add(chatIdleTimeout))
+		if sent := window.hit(now); sent > chatRateCloseLimit {
+			log.Printf("[chat] closing connection of %s: %d frames in %s", userID, sent, chatRateWindow)
+			ws.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "rate limit exceeded"), now.Add(chatWriteTimeout))
+			break
+		} else if sent > chatRateLimit {
+			client.queue(&models.ChatErrorFrame{Type: models.ChatFrameError, Code: models.ChatErrorRateLimited, ClientID: inbound.ClientID, RetryAfter: window.retryAfter(now)})
 			continue
 		}
-		// Broadcast to room
-		for cli := range rooms[hotspotID] {
-			select {
-			case cli.send <- msg:
-			default:
+		if inbound.Type == models.ChatFrameLocation {
+			if hh.locations == nil {
+				continue
+			}
+			// Only attendees who opted in via the REST endpoint may publish positions
+			share, err := hh.locations.UpdateLocation(userID, hotspotID, inbound.Latitude, inbound.Longitude)
+			if err != nil {
+				continue
+			}
+			broadcastShare(hh.locations, models.ChatFrameLocation, share)
+			continue
+		}
+		// Persist via service (also validates membership and sets nickname)
+		var msg *models.ChatMessage
+		switch inbound.Type {
+		case models.ChatFrameAnnouncement:
+			// Only the host and co-hosts may announce; announce also broadcasts it
+			msg, err = hh.announce(userID, hotspotID, inbound.ClientID, inbound.Content)
+		case models.ChatFrameLocationPin:
+			msg, err = hh.chatService.SendLocationPin(userID, hotspotID, inbound.ClientID, inbound.Latitude, inbound.Longitude, inbound.Label)
+		default:
+			msg, err = hh.chatService.SendMessage(userID, hotspotID, inbound.ClientID, inbound.Content)
+		}
+		// A retried frame gets the original message's ack again and is not redelivered
+		duplicate := errors.Is(err, services.ErrDuplicateChatMessage)
+		if err != nil && !duplicate {
+			code := models.ChatErrorRejected
+			if errors.Is(err, services.ErrChatMessageTooLong) {
+				code = models.ChatErrorTooLong
 			}
+			if inbound.ClientID != "" || code == models.ChatErrorTooLong {
+				client.queue(&models.ChatErrorFrame{Type: models.ChatFrameError, Code: code, ClientID: inbound.ClientID})
+			}
+			continue
+		}
+		if inbound.ClientID != "" {
+			client.queue(&models.ChatAckFrame{Type: models.ChatFrameAck, ClientID: inbound.ClientID, MessageID: msg.ID, CreatedAt: msg.CreatedAt, Duplicate: duplicate})
+		}
+		if !duplicate && inbound.Type != models.ChatFrameAnnouncement {
+			hh.deliver(msg)
 		}
 	}
 
 	// Cleanup on disconnect
+	roomsMu.Lock()
+	defer roomsMu.Unlock()
 	if clients, ok := rooms[hotspotID]; ok {
 		if _, ok2 := clients[client]; ok2 {
 			delete(clients, client)
@@ -143,24 +344,72 @@ func (hh *ChatHandler) ChatWebSocket(c *gin.Context) {
 	}
 }
 
+// replay writes the messages sent after the client's last received message, followed by a
+// resumed frame, and returns the IDs it wrote. Without a resume ID it writes nothing.
+func (hh *ChatHandler) replay(ws *websocket.Conn, hotspotID, lastMessageID string) map[string]bool {
+	if lastMessageID == "" {
+		return nil
+	}
+	msgs, complete, err := hh.chatService.MessagesAfter(hotspotID, lastMessageID, models.ChatResumeLimit)
+	if err != nil {
+		log.Printf("[chat] resume %s: %v", hotspotID, err)
+	}
+	replayed := make(map[string]bool, len(msgs))
+	for _, msg := range msgs {
+		ws.SetWriteDeadline(time.Now().Add(chatWriteTimeout))
+		if err := ws.WriteJSON(msg); err != nil {
+			ws.Close()
+			return replayed
+		}
+		replayed[msg.ID] = true
+	}
+	ws.SetWriteDeadline(time.Now().Add(chatWriteTimeout))
+	if err := ws.WriteJSON(&models.ChatResumeFrame{Type: models.ChatFrameResumed, Replayed: len(msgs), Complete: complete}); err != nil {
+		ws.Close()
+	}
+	return replayed
+}
+
+// deliver records a new message's engagement, broadcasts it to the room and notifies attendees who are away
+func (hh *ChatHandler) deliver(msg *models.ChatMessage) {
+	// Count the message toward trending, chat engagement and platform metrics (best-effort)
+	if hh.trending != nil {
+		hh.trending.RecordMessage(msg.HotspotID, msg.ID)
+	}
+	if hh.analytics != nil {
+		hh.analytics.RecordMessage(msg.HotspotID, msg.UserID)
+	}
+	if hh.metrics != nil {
+		hh.metrics.Increment(services.MetricChatMessages)
+	}
+	// Broadcast to room
+	broadcastToRoom(msg.HotspotID, msg)
+	// Attendees who are not in the room get a notification unless they muted it
+	if hh.notifications != nil {
+		if current, err := hh.hotspotService.GetHotspot(msg.HotspotID); err == nil {
+			hh.notifications.NotifyChatMessage(current, msg, roomMembers(msg.HotspotID))
+		}
+	}
+}
+
 // Get recent messages via REST
 func (hh *ChatHandler) GetRecentMessages(c *gin.Context) {
 	hotspotID := c.Param("id")
 	if hotspotID == "" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage("Hotspot ID required"))
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Hotspot ID required"))
 		return
 	}
 
 	// Optional: ensure requester is a member
 	userIDAny, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, models.ErrorResponseWithMessage("Unauthorized"))
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
 		return
 	}
 	userID := userIDAny.(string)
 	hotspot, err := hh.hotspotService.GetHotspot(hotspotID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, models.ErrorResponseWithMessage("Hotspot not found"))
+		c.JSON(http.StatusNotFound, errorResponse(c, "Hotspot not found"))
 		return
 	}
 	isMember := false
@@ -171,15 +420,229 @@ func (hh *ChatHandler) GetRecentMessages(c *gin.Context) {
 		}
 	}
 	if !isMember {
-		c.JSON(http.StatusForbidden, models.ErrorResponseWithMessage("Not a member"))
+		c.JSON(http.StatusForbidden, errorResponse(c, "Not a member"))
 		return
 	}
 
 	messages, err := hh.chatService.GetRecentMessages(hotspotID, 50)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponseWithMessage(err.Error()))
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, messages, "Messages retrieved"))
+}
+
+// PostAnnouncement posts a host announcement to the chat and pushes it to every attendee
+func (hh *ChatHandler) PostAnnouncement(c *gin.Context) {
+	var req models.SendMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format: "+err.Error()))
+		return
+	}
+
+	msg, err := hh.announce(c.GetString("userID"), c.Param("id"), req.ClientID, req.Content)
+	if errors.Is(err, services.ErrDuplicateChatMessage) {
+		c.JSON(http.StatusOK, successResponse(c, msg, "Announcement already posted"))
+		return
+	}
+	if err != nil {
+		c.JSON(chatErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+	c.JSON(http.StatusCreated, successResponse(c, msg, "Announcement posted"))
+}
+
+// announce stores an announcement, broadcasts it to the room and notifies attendees.
+// A retried announcement returns the original with ErrDuplicateChatMessage and is not sent again.
+func (hh *ChatHandler) announce(userID, hotspotID, clientID, content string) (*models.ChatMessage, error) {
+	msg, err := hh.chatService.SendAnnouncement(userID, hotspotID, clientID, content)
+	if err != nil {
+		return msg, err
+	}
+	broadcastToRoom(hotspotID, msg)
+	if hh.notifications != nil {
+		if hotspot, err := hh.hotspotService.GetHotspot(hotspotID); err == nil {
+			hh.notifications.NotifyAnnouncement(hotspot, msg)
+		}
+	}
+	return msg, nil
+}
+
+// GetPins returns the hotspot chat's pinned messages
+func (hh *ChatHandler) GetPins(c *gin.Context) {
+	hotspot, err := hh.hotspotService.GetHotspot(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, errorResponse(c, "Hotspot not found"))
 		return
 	}
+	if !containsID(hotspot.Attendees, c.GetString("userID")) {
+		c.JSON(http.StatusForbidden, errorResponse(c, "Not a member"))
+		return
+	}
+
+	pins, err := hh.chatService.GetPinnedMessages(hotspot.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, pins, "Pinned messages retrieved"))
+}
 
-	c.JSON(http.StatusOK, models.SuccessResponse(messages, "Messages retrieved"))
+// PinMessage pins a chat message for everyone in the room (host only, up to 3)
+func (hh *ChatHandler) PinMessage(c *gin.Context) {
+	var req models.PinMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format: "+err.Error()))
+		return
+	}
+
+	msg, err := hh.chatService.PinMessage(c.GetString("userID"), c.Param("id"), req.MessageID)
+	if err != nil {
+		c.JSON(chatErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+	broadcastToRoom(msg.HotspotID, &models.ChatPinFrame{Type: models.ChatFramePinned, Message: msg})
+	c.JSON(http.StatusOK, successResponse(c, msg, "Message pinned"))
+}
+
+// UnpinMessage unpins a chat message (host only)
+func (hh *ChatHandler) UnpinMessage(c *gin.Context) {
+	msg, err := hh.chatService.UnpinMessage(c.GetString("userID"), c.Param("id"), c.Param("messageId"))
+	if err != nil {
+		c.JSON(chatErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+	broadcastToRoom(msg.HotspotID, &models.ChatPinFrame{Type: models.ChatFrameUnpinned, Message: msg})
+	c.JSON(http.StatusOK, successResponse(c, msg, "Message unpinned"))
+}
+
+// UploadVoiceNote accepts a multipart voice note ("audio" file, hotspot_id, duration_ms); it is
+// posted to the chat once transcoded
+func (hh *ChatHandler) UploadVoiceNote(c *gin.Context) {
+	var req models.UploadVoiceNoteRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format: "+err.Error()))
+		return
+	}
+	file, err := c.FormFile("audio")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Audio file is required"))
+		return
+	}
+	if file.Size > models.MaxVoiceNoteBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, errorResponse(c, services.ErrVoiceNoteTooLarge.Error()))
+		return
+	}
+	f, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Audio file is required"))
+		return
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, models.MaxVoiceNoteBytes+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Audio file is required"))
+		return
+	}
+
+	note, err := hh.voiceNotes.Upload(c.Request.Context(), c.GetString("userID"), &req, data)
+	if err != nil {
+		c.JSON(voiceNoteErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+	c.JSON(http.StatusAccepted, successResponse(c, note, "Voice note is being processed"))
+}
+
+// GetVoiceNote returns a voice note's processing status
+func (hh *ChatHandler) GetVoiceNote(c *gin.Context) {
+	note, err := hh.voiceNotes.GetVoiceNote(c.GetString("userID"), c.Param("id"))
+	if err != nil {
+		c.JSON(voiceNoteErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, note, "Voice note retrieved"))
+}
+
+// DownloadVoiceNote streams a ready voice note to a member of its hotspot
+func (hh *ChatHandler) DownloadVoiceNote(c *gin.Context) {
+	data, contentType, err := hh.voiceNotes.Open(c.Request.Context(), c.GetString("userID"), c.Param("id"))
+	if err != nil {
+		c.JSON(voiceNoteErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+	c.Header("Cache-Control", "private, max-age=86400")
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// voiceNoteErrorStatus maps voice note errors to HTTP status codes
+func voiceNoteErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrVoiceNoteTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, services.ErrVoiceNoteFormat):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, services.ErrVoiceNoteNotFound), errors.Is(err, services.ErrObjectNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrVoiceNoteUnavailable):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrVoiceNoteQueueFull):
+		return http.StatusServiceUnavailable
+	default:
+		return chatErrorStatus(err)
+	}
+}
+
+// MuteChat mutes notifications from a hotspot's chat for 1h, 8h or until unmuted
+func (hh *ChatHandler) MuteChat(c *gin.Context) {
+	var req models.MuteChatRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format: "+err.Error()))
+		return
+	}
+
+	prefs, err := hh.chatService.MuteChat(c.GetString("userID"), c.Param("id"), req.Duration)
+	if err != nil {
+		c.JSON(chatErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, prefs, "Chat muted"))
+}
+
+// UnmuteChat turns a hotspot's chat notifications back on
+func (hh *ChatHandler) UnmuteChat(c *gin.Context) {
+	prefs, err := hh.chatService.UnmuteChat(c.GetString("userID"), c.Param("id"))
+	if err != nil {
+		c.JSON(chatErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, prefs, "Chat unmuted"))
+}
+
+// GetChatPrefs returns the user's notification settings for a hotspot's chat
+func (hh *ChatHandler) GetChatPrefs(c *gin.Context) {
+	prefs, err := hh.chatService.GetChatPrefs(c.GetString("userID"), c.Param("id"))
+	if err != nil {
+		c.JSON(chatErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, prefs, "Chat settings retrieved"))
+}
+
+// chatErrorStatus maps chat service errors to HTTP status codes
+func chatErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrNotHotspotMember), errors.Is(err, services.ErrNotChatHost):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrChatMessageNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrTooManyPins):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrChatMessageTooLong), errors.Is(err, services.ErrInvalidChatClientID), err.Error() == "message content cannot be empty":
+		return http.StatusBadRequest
+	case err.Error() == "hotspot not found":
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
 }
@@ -0,0 +1,175 @@
+// Club handlers for club pages, their admins and followers
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"unalone-backend/internal/models"
+	"unalone-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// clubPageHotspots is how many upcoming hotspots a club page shows
+const clubPageHotspots = 10
+
+// ClubHandler handles club endpoints
+type ClubHandler struct {
+	clubService    *services.ClubService
+	hotspotService *services.HotspotService
+}
+
+// NewClubHandler creates a new club handler
+func NewClubHandler(cs *services.ClubService, hs *services.HotspotService) *ClubHandler {
+	return &ClubHandler{clubService: cs, hotspotService: hs}
+}
+
+// CreateClub creates a club with the current user as its first admin
+func (ch *ClubHandler) CreateClub(c *gin.Context) {
+	var req models.CreateClubRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format: "+err.Error()))
+		return
+	}
+	club, err := ch.clubService.CreateClub(c.GetString("userID"), &req)
+	if err != nil {
+		c.JSON(clubErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+	c.JSON(http.StatusCreated, successResponse(c, club, "Club created"))
+}
+
+// ListClubs lists clubs, optionally those whose name contains q, most followed first
+func (ch *ClubHandler) ListClubs(c *gin.Context) {
+	limit, offset := parsePagination(c)
+	clubs, total, err := ch.clubService.ListClubs(c.GetString("orgID"), c.Query("q"), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+	response := models.ClubListResponse{Clubs: clubs, Total: total, HasMore: offset+len(clubs) < total}
+	c.JSON(http.StatusOK, successResponse(c, response, "Clubs retrieved"))
+}
+
+// ListFollowedClubs lists the clubs the current user follows
+func (ch *ClubHandler) ListFollowedClubs(c *gin.Context) {
+	clubs, err := ch.clubService.FollowedClubs(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, clubs, "Followed clubs retrieved"))
+}
+
+// GetClub returns a club page with its admins and upcoming hotspots
+func (ch *ClubHandler) GetClub(c *gin.Context) {
+	userID, orgID := c.GetString("userID"), c.GetString("orgID")
+	club, err := ch.clubService.GetClub(c.Param("id"), orgID)
+	if err != nil {
+		c.JSON(clubErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+	profile, err := ch.clubService.Profile(userID, club)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+	upcoming, err := ch.hotspotService.GetClubHotspots(club, userID, orgID, clubPageHotspots, 0)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+	profile.UpcomingHotspots = upcoming.Hotspots
+	c.JSON(http.StatusOK, successResponse(c, profile, "Club retrieved"))
+}
+
+// GetClubHotspots lists the upcoming hotspots a club hosts, soonest first
+func (ch *ClubHandler) GetClubHotspots(c *gin.Context) {
+	userID, orgID := c.GetString("userID"), c.GetString("orgID")
+	club, err := ch.clubService.GetClub(c.Param("id"), orgID)
+	if err != nil {
+		c.JSON(clubErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+	limit, offset := parsePagination(c)
+	response, err := ch.hotspotService.GetClubHotspots(club, userID, orgID, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, response, "Club hotspots retrieved"))
+}
+
+// UpdateClub changes a club's name, description or logo (club admins)
+func (ch *ClubHandler) UpdateClub(c *gin.Context) {
+	var req models.UpdateClubRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format: "+err.Error()))
+		return
+	}
+	club, err := ch.clubService.UpdateClub(c.GetString("userID"), c.Param("id"), c.GetString("orgID"), &req)
+	if err != nil {
+		c.JSON(clubErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, club, "Club updated"))
+}
+
+// AddClubAdmin makes another user an admin of the club (club admins)
+func (ch *ClubHandler) AddClubAdmin(c *gin.Context) {
+	var req models.ClubAdminRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format: "+err.Error()))
+		return
+	}
+	club, err := ch.clubService.AddAdmin(c.GetString("userID"), c.Param("id"), c.GetString("orgID"), req.UserID)
+	if err != nil {
+		c.JSON(clubErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, club, "Club admin added"))
+}
+
+// RemoveClubAdmin takes away a user's admin role in the club (club admins)
+func (ch *ClubHandler) RemoveClubAdmin(c *gin.Context) {
+	club, err := ch.clubService.RemoveAdmin(c.GetString("userID"), c.Param("id"), c.GetString("orgID"), c.Param("userId"))
+	if err != nil {
+		c.JSON(clubErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, club, "Club admin removed"))
+}
+
+// FollowClub subscribes the current user to the club's new hotspots
+func (ch *ClubHandler) FollowClub(c *gin.Context) {
+	club, err := ch.clubService.Follow(c.GetString("userID"), c.Param("id"), c.GetString("orgID"))
+	if err != nil {
+		c.JSON(clubErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, club, "Club followed"))
+}
+
+// UnfollowClub stops the current user's notifications from the club
+func (ch *ClubHandler) UnfollowClub(c *gin.Context) {
+	club, err := ch.clubService.Unfollow(c.GetString("userID"), c.Param("id"), c.GetString("orgID"))
+	if err != nil {
+		c.JSON(clubErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, club, "Club unfollowed"))
+}
+
+func clubErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrClubNotFound), err.Error() == "user not found":
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrNotClubAdmin):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrClubLastAdmin):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
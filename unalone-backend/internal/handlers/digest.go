@@ -0,0 +1,75 @@
+// Weekly email digest handlers: subscription settings, preview and unsubscribe links
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"unalone-backend/internal/models"
+	"unalone-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DigestHandler manages the weekly digest subscription
+type DigestHandler struct {
+	digestService *services.DigestService
+}
+
+// NewDigestHandler creates a new digest handler
+func NewDigestHandler(ds *services.DigestService) *DigestHandler {
+	return &DigestHandler{digestService: ds}
+}
+
+// GetDigest returns whether the current user gets the weekly digest
+func (dh *DigestHandler) GetDigest(c *gin.Context) {
+	sub, err := dh.digestService.GetSubscription(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, sub, "Email digest settings retrieved successfully"))
+}
+
+// UpdateDigest turns the weekly digest on or off
+func (dh *DigestHandler) UpdateDigest(c *gin.Context) {
+	var req models.UpdateDigestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format: "+err.Error()))
+		return
+	}
+
+	sub, err := dh.digestService.SetEnabled(c.GetString("userID"), req.Enabled)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, sub, "Email digest settings updated successfully"))
+}
+
+// PreviewDigest renders the digest the current user would get now
+func (dh *DigestHandler) PreviewDigest(c *gin.Context) {
+	msg, err := dh.digestService.Preview(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, gin.H{"subject": msg.Subject, "body": msg.Body}, "Email digest preview generated successfully"))
+}
+
+// Unsubscribe turns the digest off from the link in the email; no sign-in needed
+func (dh *DigestHandler) Unsubscribe(c *gin.Context) {
+	if err := dh.digestService.Unsubscribe(c.Query("user"), c.Query("token")); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrInvalidUnsubscribeLink) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, nil, "You have been unsubscribed from the weekly digest"))
+}
@@ -0,0 +1,80 @@
+// Event import handlers for managing external event feeds
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"unalone-backend/internal/models"
+	"unalone-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EventImportHandler lets admins manage the feeds hotspots are imported from
+type EventImportHandler struct {
+	importService *services.EventImportService
+}
+
+// NewEventImportHandler creates a new event import handler
+func NewEventImportHandler(eis *services.EventImportService) *EventImportHandler {
+	return &EventImportHandler{importService: eis}
+}
+
+// ListFeeds returns the registered feeds and their last runs (admin only)
+func (eh *EventImportHandler) ListFeeds(c *gin.Context) {
+	feeds, err := eh.importService.ListFeeds()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, feeds, "Import feeds retrieved successfully"))
+}
+
+// AddFeed registers an ICS or JSON event feed (admin only)
+func (eh *EventImportHandler) AddFeed(c *gin.Context) {
+	var req models.CreateImportFeedRequest
+
+	// Bind JSON request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format: "+err.Error()))
+		return
+	}
+
+	feed, err := eh.importService.AddFeed(c.GetString("userID"), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, successResponse(c, feed, "Import feed added successfully"))
+}
+
+// DeleteFeed stops importing from a feed (admin only)
+func (eh *EventImportHandler) DeleteFeed(c *gin.Context) {
+	if err := eh.importService.DeleteFeed(c.Param("id")); err != nil {
+		c.JSON(importErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, nil, "Import feed deleted successfully"))
+}
+
+// RunFeed imports a feed immediately and returns what changed (admin only)
+func (eh *EventImportHandler) RunFeed(c *gin.Context) {
+	run, err := eh.importService.RunFeed(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(importErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, run, "Import feed run completed"))
+}
+
+func importErrorStatus(err error) int {
+	if errors.Is(err, services.ErrImportFeedNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,160 @@
+// Events handler: per-user WebSocket for realtime updates
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"unalone-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	eventsWriteTimeout  = 10 * time.Second
+	eventsPingInterval  = 30 * time.Second
+	eventsMaxFrameBytes = 512 // Clients never need to send more than control frames
+)
+
+// EventsHandler streams realtime user events over WebSocket
+type EventsHandler struct {
+	events      *services.EventService
+	authService *services.AuthService
+	presence    *services.PresenceService
+	tickets     *services.WebSocketTicketService
+}
+
+// NewEventsHandler creates a new events handler
+func NewEventsHandler(es *services.EventService, as *services.AuthService, ps *services.PresenceService, wts *services.WebSocketTicketService) *EventsHandler {
+	return &EventsHandler{events: es, authService: as, presence: ps, tickets: wts}
+}
+
+// IssueTicket returns a short-lived, single-use ticket for opening a WebSocket
+func (eh *EventsHandler) IssueTicket(c *gin.Context) {
+	userID := c.GetString("userID")
+	ticket, err := eh.tickets.Issue(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, "Failed to issue ticket: "+err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, ticket, "Ticket issued"))
+}
+
+// UserEventsWebSocket delivers friend requests, hotspot updates, points and other
+// events for the authenticated user without polling
+func (eh *EventsHandler) UserEventsWebSocket(c *gin.Context) {
+	userID, header, ok := webSocketUserID(c, eh.authService, eh.tickets)
+	if !ok {
+		return
+	}
+
+	// Upgrade
+	ws, err := upgrader.Upgrade(c.Writer, c.Request, header)
+	if err != nil {
+		log.Println("WS upgrade error:", err)
+		return
+	}
+	defer ws.Close()
+	ws.SetReadLimit(eventsMaxFrameBytes)
+
+	events, unsubscribe := eh.events.Subscribe(userID)
+	defer unsubscribe()
+	// An open events connection keeps the user online; each successful ping refreshes it
+	eh.presence.Touch(userID)
+
+	// Reader goroutine: the channel is server-to-client, so inbound frames are
+	// discarded and only used to detect disconnects
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			if _, _, err := ws.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(eventsPingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case event := <-events:
+			ws.SetWriteDeadline(time.Now().Add(eventsWriteTimeout))
+			if err := ws.WriteJSON(event); err != nil {
+				return
+			}
+		case <-ping.C:
+			if err := ws.WriteControl(websocket.PingMessage, nil, time.Now().Add(eventsWriteTimeout)); err != nil {
+				return
+			}
+			eh.presence.Touch(userID)
+		}
+	}
+}
+
+// webSocketUserID authenticates a WebSocket request from, in order, the auth middleware context,
+// a "bearer.<token>" subprotocol, a ticket query parameter, or the deprecated token query parameter.
+// It returns headers to send with the upgrade response.
+func webSocketUserID(c *gin.Context, authService *services.AuthService, tickets *services.WebSocketTicketService) (string, http.Header, bool) {
+	if userIDAny, exists := c.Get("userID"); exists {
+		return userIDAny.(string), nil, true
+	}
+
+	var header http.Header
+	token := bearerSubprotocol(c.Request)
+	if token == "" {
+		if ticket := c.Query("ticket"); ticket != "" {
+			userID, err := tickets.Redeem(ticket)
+			if err != nil {
+				c.JSON(http.StatusUnauthorized, errorResponse(c, "Invalid or expired ticket"))
+				return "", nil, false
+			}
+			return userID, nil, true
+		}
+		token = c.Query("token")
+		if token != "" {
+			if !queryTokenAuthEnabled() {
+				c.JSON(http.StatusUnauthorized, errorResponse(c, "Pass the access token as a WebSocket subprotocol or use a ticket"))
+				return "", nil, false
+			}
+			// Query tokens end up in proxy and access logs; clients should move to subprotocols or tickets
+			header = http.Header{"Deprecation": []string{"true"}}
+		}
+	}
+	if token == "" {
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
+		return "", nil, false
+	}
+	claims, err := authService.ValidateToken(token)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Invalid token"))
+		return "", nil, false
+	}
+	return claims.UserID, header, true
+}
+
+// bearerSubprotocol returns the access token offered as a "bearer.<token>" subprotocol, if any.
+// The server only ever selects wsSubprotocol, so the token is not echoed back.
+func bearerSubprotocol(r *http.Request) string {
+	for _, protocol := range websocket.Subprotocols(r) {
+		if strings.HasPrefix(protocol, wsBearerPrefix) {
+			return strings.TrimPrefix(protocol, wsBearerPrefix)
+		}
+	}
+	return ""
+}
+
+// queryTokenAuthEnabled reports whether the deprecated token query parameter is still accepted;
+// set WS_QUERY_TOKEN_AUTH=false to turn it off once clients have moved
+func queryTokenAuthEnabled() bool {
+	enabled, err := strconv.ParseBool(strings.TrimSpace(os.Getenv("WS_QUERY_TOKEN_AUTH")))
+	return err != nil || enabled
+}
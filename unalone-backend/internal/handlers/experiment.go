@@ -0,0 +1,117 @@
+// A/B experiment handlers
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"unalone-backend/internal/models"
+	"unalone-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExperimentHandler serves experiment variants to clients and experiment setup to admins
+type ExperimentHandler struct {
+	experimentService *services.ExperimentService
+}
+
+// NewExperimentHandler creates a new experiment handler
+func NewExperimentHandler(es *services.ExperimentService) *ExperimentHandler {
+	return &ExperimentHandler{experimentService: es}
+}
+
+// GetAssignments returns the caller's variant of each active experiment they are enrolled in,
+// optionally limited to a comma-separated keys list
+func (eh *ExperimentHandler) GetAssignments(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	assignments, err := eh.experimentService.GetAssignments(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+	if raw := c.Query("keys"); raw != "" {
+		wanted := make(map[string]string)
+		for _, key := range strings.Split(raw, ",") {
+			if variant, ok := assignments.Variants[strings.TrimSpace(key)]; ok {
+				wanted[strings.TrimSpace(key)] = variant
+			}
+		}
+		assignments.Variants = wanted
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, assignments, "Experiments retrieved successfully"))
+}
+
+// RecordExposure logs that the caller was shown their variant of an experiment
+func (eh *ExperimentHandler) RecordExposure(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	var req models.RecordExposureRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format: "+err.Error()))
+		return
+	}
+
+	exposure, err := eh.experimentService.RecordExposure(userID, req.ExperimentKey)
+	if err != nil {
+		c.JSON(experimentErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, exposure, "Exposure recorded successfully"))
+}
+
+// ListExperiments returns every experiment (admin)
+func (eh *ExperimentHandler) ListExperiments(c *gin.Context) {
+	experiments, err := eh.experimentService.ListExperiments()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, experiments, "Experiments retrieved successfully"))
+}
+
+// UpsertExperiment creates or updates an experiment (admin)
+func (eh *ExperimentHandler) UpsertExperiment(c *gin.Context) {
+	key := c.Param("key")
+
+	var req models.UpsertExperimentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format: "+err.Error()))
+		return
+	}
+
+	experiment, err := eh.experimentService.UpsertExperiment(key, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, experiment, "Experiment saved successfully"))
+}
+
+// GetResults returns exposure counts per variant of an experiment (admin)
+func (eh *ExperimentHandler) GetResults(c *gin.Context) {
+	results, err := eh.experimentService.GetResults(c.Param("key"))
+	if err != nil {
+		c.JSON(experimentErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, results, "Experiment results retrieved successfully"))
+}
+
+func experimentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrExperimentNotFound):
+		return http.StatusNotFound
+	case err.Error() == "user is not enrolled in this experiment":
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,76 @@
+// Feedback handlers for post-event prompts
+package handlers
+
+import (
+	"net/http"
+
+	"unalone-backend/internal/models"
+	"unalone-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FeedbackHandler handles post-event feedback endpoints
+type FeedbackHandler struct {
+	feedbackService *services.FeedbackService
+	hotspotService  *services.HotspotService
+}
+
+// NewFeedbackHandler creates a new feedback handler
+func NewFeedbackHandler(fbs *services.FeedbackService, hs *services.HotspotService) *FeedbackHandler {
+	return &FeedbackHandler{feedbackService: fbs, hotspotService: hs}
+}
+
+// ListPending returns hotspots the user has been asked to give feedback on
+func (fh *FeedbackHandler) ListPending(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
+		return
+	}
+
+	pending, err := fh.feedbackService.ListPendingFeedback(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, pending, "Pending feedback retrieved successfully"))
+}
+
+// SubmitFeedback answers the feedback prompt for a hotspot
+func (fh *FeedbackHandler) SubmitFeedback(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
+		return
+	}
+
+	hotspotID := c.Param("id")
+	if hotspotID == "" {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Hotspot ID is required"))
+		return
+	}
+
+	var req models.SubmitFeedbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format: "+err.Error()))
+		return
+	}
+
+	hotspot, err := fh.hotspotService.GetHotspot(hotspotID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, errorResponse(c, "Hotspot not found"))
+		return
+	}
+
+	feedback, err := fh.feedbackService.SubmitFeedback(userID.(string), hotspot, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, successResponse(c, feedback, "Feedback submitted successfully"))
+}
@@ -0,0 +1,140 @@
+// Friend list handlers for organizing friends into named groups
+package handlers
+
+import (
+	"net/http"
+
+	"unalone-backend/internal/models"
+	"unalone-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FriendListHandler handles friend list endpoints
+type FriendListHandler struct {
+	friendListService *services.FriendListService
+}
+
+// NewFriendListHandler creates a new friend list handler
+func NewFriendListHandler(fls *services.FriendListService) *FriendListHandler {
+	return &FriendListHandler{friendListService: fls}
+}
+
+// ListLists returns the current user's friend lists
+func (flh *FriendListHandler) ListLists(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
+		return
+	}
+
+	lists, err := flh.friendListService.ListLists(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, lists, "Friend lists retrieved successfully"))
+}
+
+// CreateList creates a friend list
+func (flh *FriendListHandler) CreateList(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
+		return
+	}
+
+	var req models.CreateFriendListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format: "+err.Error()))
+		return
+	}
+
+	list, err := flh.friendListService.CreateList(userID.(string), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, successResponse(c, list, "Friend list created successfully"))
+}
+
+// GetList returns one of the current user's friend lists
+func (flh *FriendListHandler) GetList(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
+		return
+	}
+
+	listID := c.Param("id")
+	if listID == "" {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Friend list ID is required"))
+		return
+	}
+
+	list, err := flh.friendListService.GetList(userID.(string), listID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, list, "Friend list retrieved successfully"))
+}
+
+// UpdateList renames a friend list or replaces its members
+func (flh *FriendListHandler) UpdateList(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
+		return
+	}
+
+	listID := c.Param("id")
+	if listID == "" {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Friend list ID is required"))
+		return
+	}
+
+	var req models.UpdateFriendListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format: "+err.Error()))
+		return
+	}
+
+	list, err := flh.friendListService.UpdateList(userID.(string), listID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, list, "Friend list updated successfully"))
+}
+
+// DeleteList deletes a friend list
+func (flh *FriendListHandler) DeleteList(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
+		return
+	}
+
+	listID := c.Param("id")
+	if listID == "" {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Friend list ID is required"))
+		return
+	}
+
+	if err := flh.friendListService.DeleteList(userID.(string), listID); err != nil {
+		c.JSON(http.StatusNotFound, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, nil, "Friend list deleted successfully"))
+}
@@ -13,127 +13,195 @@ import (
 type FriendsHandler struct {
 	friendsService      *services.FriendsService
 	gamificationService *services.GamificationService
+	notifications       *services.NotificationService
+	presence            *services.PresenceService
 }
 
-func NewFriendsHandler(fs *services.FriendsService, gs *services.GamificationService) *FriendsHandler {
-	return &FriendsHandler{friendsService: fs, gamificationService: gs}
+func NewFriendsHandler(fs *services.FriendsService, gs *services.GamificationService, ns *services.NotificationService, ps *services.PresenceService) *FriendsHandler {
+	return &FriendsHandler{friendsService: fs, gamificationService: gs, notifications: ns, presence: ps}
 }
 
 // GET /friends
 func (fh *FriendsHandler) ListFriends(c *gin.Context) {
 	uidAny, ok := c.Get("userID")
 	if !ok {
-		c.JSON(http.StatusUnauthorized, models.ErrorResponseWithMessage("Unauthorized"))
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
 		return
 	}
 	friends, err := fh.friendsService.ListFriends(uidAny.(string))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponseWithMessage(err.Error()))
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
 		return
 	}
-	c.JSON(http.StatusOK, models.SuccessResponse(friends, "Friends retrieved"))
+	fh.presence.Annotate(friends)
+	c.JSON(http.StatusOK, successResponse(c, friends, "Friends retrieved"))
 }
 
 // GET /friends/requests
 func (fh *FriendsHandler) ListRequests(c *gin.Context) {
 	uidAny, ok := c.Get("userID")
 	if !ok {
-		c.JSON(http.StatusUnauthorized, models.ErrorResponseWithMessage("Unauthorized"))
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
 		return
 	}
 	reqs, err := fh.friendsService.ListFriendRequests(uidAny.(string))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponseWithMessage(err.Error()))
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
 		return
 	}
-	c.JSON(http.StatusOK, models.SuccessResponse(reqs, "Requests retrieved"))
+	c.JSON(http.StatusOK, successResponse(c, reqs, "Requests retrieved"))
 }
 
-type sendReq struct {
-	Target string `json:"target" binding:"required"` // ID or nickname
+// POST /friends/status
+func (fh *FriendsHandler) GetStatuses(c *gin.Context) {
+	uidAny, ok := c.Get("userID")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
+		return
+	}
+	var req models.FriendStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format: "+err.Error()))
+		return
+	}
+	statuses, err := fh.friendsService.GetFriendStatuses(uidAny.(string), req.UserIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, statuses, "Friend statuses retrieved"))
 }
 
 // POST /friends/requests
 func (fh *FriendsHandler) SendRequest(c *gin.Context) {
 	uidAny, ok := c.Get("userID")
 	if !ok {
-		c.JSON(http.StatusUnauthorized, models.ErrorResponseWithMessage("Unauthorized"))
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
 		return
 	}
-	var req sendReq
+	var req models.SendFriendRequestRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage("Invalid request"))
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request"))
 		return
 	}
-	if err := fh.friendsService.SendFriendRequest(uidAny.(string), req.Target); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage(err.Error()))
+	targetID, accepted, err := fh.friendsService.SendFriendRequest(uidAny.(string), req.Target, req.Note)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, err.Error()))
 		return
 	}
-	c.JSON(http.StatusOK, models.SuccessResponse(nil, "Request sent"))
-}
-
-type actReq struct {
-	UserID string `json:"user_id" binding:"required"`
+	// Notify the other user (best-effort); a mutual accept is handled by friend.accepted subscribers
+	if fh.notifications != nil && !accepted {
+		_ = fh.notifications.NotifyFriendRequest(uidAny.(string), targetID)
+	}
+	c.JSON(http.StatusOK, successResponse(c, nil, "Request sent"))
 }
 
 // POST /friends/accept
 func (fh *FriendsHandler) Accept(c *gin.Context) {
 	uidAny, ok := c.Get("userID")
 	if !ok {
-		c.JSON(http.StatusUnauthorized, models.ErrorResponseWithMessage("Unauthorized"))
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
 		return
 	}
-	var req actReq
+	var req models.FriendActionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage("Invalid request"))
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request"))
 		return
 	}
 	if err := fh.friendsService.AcceptFriendRequest(uidAny.(string), req.UserID); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage(err.Error()))
+		c.JSON(http.StatusBadRequest, errorResponse(c, err.Error()))
 		return
 	}
-	// Award points to both users for new friendship (best-effort)
-	if fh.gamificationService != nil {
-		_, _, _ = fh.gamificationService.AwardForFriendship(uidAny.(string))
-		_, _, _ = fh.gamificationService.AwardForFriendship(req.UserID)
-	}
-	c.JSON(http.StatusOK, models.SuccessResponse(nil, "Friend request accepted"))
+	c.JSON(http.StatusOK, successResponse(c, nil, "Friend request accepted"))
 }
 
 // POST /friends/reject
 func (fh *FriendsHandler) Reject(c *gin.Context) {
 	uidAny, ok := c.Get("userID")
 	if !ok {
-		c.JSON(http.StatusUnauthorized, models.ErrorResponseWithMessage("Unauthorized"))
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
 		return
 	}
-	var req actReq
+	var req models.FriendActionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage("Invalid request"))
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request"))
 		return
 	}
 	if err := fh.friendsService.RejectFriendRequest(uidAny.(string), req.UserID); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage(err.Error()))
+		c.JSON(http.StatusBadRequest, errorResponse(c, err.Error()))
 		return
 	}
-	c.JSON(http.StatusOK, models.SuccessResponse(nil, "Friend request rejected"))
+	c.JSON(http.StatusOK, successResponse(c, nil, "Friend request rejected"))
 }
 
 // POST /friends/remove
 func (fh *FriendsHandler) Remove(c *gin.Context) {
 	uidAny, ok := c.Get("userID")
 	if !ok {
-		c.JSON(http.StatusUnauthorized, models.ErrorResponseWithMessage("Unauthorized"))
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
 		return
 	}
-	var req actReq
+	var req models.FriendActionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage("Invalid request"))
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request"))
 		return
 	}
 	if err := fh.friendsService.RemoveFriend(uidAny.(string), req.UserID); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage(err.Error()))
+		c.JSON(http.StatusBadRequest, errorResponse(c, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, nil, "Friend removed"))
+}
+
+// GET /friends/muted
+func (fh *FriendsHandler) ListMuted(c *gin.Context) {
+	uidAny, ok := c.Get("userID")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
+		return
+	}
+	muted, err := fh.friendsService.ListMutedFriends(uidAny.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, muted, "Muted friends retrieved"))
+}
+
+// POST /friends/mute
+func (fh *FriendsHandler) Mute(c *gin.Context) {
+	uidAny, ok := c.Get("userID")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
+		return
+	}
+	var req models.FriendActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request"))
+		return
+	}
+	if err := fh.friendsService.MuteFriend(uidAny.(string), req.UserID); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, nil, "Friend muted"))
+}
+
+// POST /friends/unmute
+func (fh *FriendsHandler) Unmute(c *gin.Context) {
+	uidAny, ok := c.Get("userID")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
+		return
+	}
+	var req models.FriendActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request"))
+		return
+	}
+	if err := fh.friendsService.UnmuteFriend(uidAny.(string), req.UserID); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, err.Error()))
 		return
 	}
-	c.JSON(http.StatusOK, models.SuccessResponse(nil, "Friend removed"))
+	c.JSON(http.StatusOK, successResponse(c, nil, "Friend unmuted"))
 }
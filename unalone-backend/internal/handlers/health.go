@@ -0,0 +1,56 @@
+// Health handlers for readiness and degraded dependency modes
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"unalone-backend/internal/models"
+	"unalone-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HealthHandler reports dependency health and lets admins force a dependency down
+type HealthHandler struct {
+	health *services.HealthRegistry
+}
+
+// NewHealthHandler creates a new health handler
+func NewHealthHandler(h *services.HealthRegistry) *HealthHandler {
+	return &HealthHandler{health: h}
+}
+
+// Ready reports the service mode and each dependency; it is 503 while a required dependency is down
+func (hh *HealthHandler) Ready(c *gin.Context) {
+	report := hh.health.Status()
+	status := http.StatusOK
+	if report.Status == models.ServiceModeUnavailable {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
+
+// GetStatus returns the service mode and each dependency to admins
+func (hh *HealthHandler) GetStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, successResponse(c, hh.health.Status(), "Dependency health retrieved"))
+}
+
+// ForceDependency marks a dependency down on every instance, e.g. during a known outage,
+// or hands it back to its health check
+func (hh *HealthHandler) ForceDependency(c *gin.Context) {
+	var req models.ForceDependencyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format: "+err.Error()))
+		return
+	}
+	if err := hh.health.Force(c.Request.Context(), c.Param("dependency"), req.Down); err != nil {
+		if errors.Is(err, services.ErrUnknownDependency) {
+			c.JSON(http.StatusNotFound, errorResponse(c, err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, hh.health.Status(), "Dependency health updated"))
+}
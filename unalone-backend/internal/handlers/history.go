@@ -0,0 +1,75 @@
+// History handlers for past events and people met there
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"unalone-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HistoryHandler handles attendance history endpoints
+type HistoryHandler struct {
+	historyService *services.HistoryService
+}
+
+// NewHistoryHandler creates a new history handler
+func NewHistoryHandler(hs *services.HistoryService) *HistoryHandler {
+	return &HistoryHandler{historyService: hs}
+}
+
+// GetEventHistory returns hotspots the user attended after they ended
+func (hh *HistoryHandler) GetEventHistory(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
+		return
+	}
+
+	limit, offset := parsePagination(c)
+	events, err := hh.historyService.GetEventHistory(userID.(string), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, events, "Event history retrieved successfully"))
+}
+
+// GetPeopleMet returns people the user attended hotspots with and their current relationship
+func (hh *HistoryHandler) GetPeopleMet(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
+		return
+	}
+
+	limit, offset := parsePagination(c)
+	people, err := hh.historyService.GetPeopleMet(userID.(string), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, people, "People met retrieved successfully"))
+}
+
+// parsePagination reads optional limit (1-100, default 20) and offset query parameters
+func parsePagination(c *gin.Context) (limit, offset int) {
+	limit = 20 // Default limit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
+			limit = l
+		}
+	}
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
+			offset = o
+		}
+	}
+	return limit, offset
+}
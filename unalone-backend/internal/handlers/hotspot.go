@@ -2,8 +2,14 @@
 package handlers
 
 import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"unalone-backend/internal/models"
 	"unalone-backend/internal/services"
@@ -16,14 +22,34 @@ type HotspotHandler struct {
 	hotspotService    *services.HotspotService
 	geospatialService *services.GeospatialService
 	gamification      *services.GamificationService
+	trending          *services.TrendingService
+	analytics         *services.AnalyticsService
+	notifications     *services.NotificationService
+	hostVerification  *services.HostVerificationService
+	readCache         *services.HotspotReadCache
+	travelTimes       *services.TravelTimeService
+	userLocations     *services.UserLocationService
+	shareLinks        *services.ShareLinkService
+	profiles          *services.ProfileService
+	presence          *services.PresenceService
 }
 
 // NewHotspotHandler creates a new hotspot handler
-func NewHotspotHandler(hs *services.HotspotService, gs *services.GeospatialService, gam *services.GamificationService) *HotspotHandler {
+func NewHotspotHandler(hs *services.HotspotService, gs *services.GeospatialService, gam *services.GamificationService, ts *services.TrendingService, as *services.AnalyticsService, ns *services.NotificationService, hvs *services.HostVerificationService, rc *services.HotspotReadCache, tts *services.TravelTimeService, uls *services.UserLocationService, sls *services.ShareLinkService, pfs *services.ProfileService, prs *services.PresenceService) *HotspotHandler {
 	return &HotspotHandler{
 		hotspotService:    hs,
 		geospatialService: gs,
 		gamification:      gam,
+		trending:          ts,
+		analytics:         as,
+		notifications:     ns,
+		hostVerification:  hvs,
+		readCache:         rc,
+		travelTimes:       tts,
+		userLocations:     uls,
+		shareLinks:        sls,
+		profiles:          pfs,
+		presence:          prs,
 	}
 }
 
@@ -32,7 +58,7 @@ func (hh *HotspotHandler) CreateHotspot(c *gin.Context) {
 	// Get user ID from context (set by auth middleware)
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, models.ErrorResponseWithMessage("Unauthorized"))
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
 		return
 	}
 
@@ -40,35 +66,148 @@ func (hh *HotspotHandler) CreateHotspot(c *gin.Context) {
 
 	// Bind JSON request
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage("Invalid request format: "+err.Error()))
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format: "+err.Error()))
 		return
 	}
 
 	// Create hotspot
-	hotspot, err := hh.hotspotService.CreateHotspot(userID.(string), &req)
+	hotspot, err := hh.Create(userID.(string), &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage(err.Error()))
+		if errors.Is(err, services.ErrNotClubAdmin) {
+			c.JSON(http.StatusForbidden, errorResponse(c, err.Error()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, errorResponse(c, err.Error()))
 		return
 	}
 
-	c.JSON(http.StatusCreated, models.SuccessResponse(hotspot, "Hotspot created successfully"))
+	c.JSON(http.StatusCreated, successResponse(c, hotspot, "Hotspot created successfully"))
+}
+
+// Create creates a hotspot from a validated request and alerts nearby friends and invitees.
+// Other handlers that create hotspots on a user's behalf go through it too.
+func (hh *HotspotHandler) Create(userID string, req *models.CreateHotspotRequest) (*models.Hotspot, error) {
+	hotspot, err := hh.hotspotService.CreateHotspot(userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if hh.notifications != nil && !hotspot.IsDraft {
+		hh.notifications.NotifyHotspotInvites(hotspot, hotspot.InvitedUserIDs)
+	}
+	return hotspot, nil
 }
 
 // GetHotspot retrieves a hotspot by ID
 func (hh *HotspotHandler) GetHotspot(c *gin.Context) {
 	hotspotID := c.Param("id")
 	if hotspotID == "" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage("Hotspot ID is required"))
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Hotspot ID is required"))
+		return
+	}
+
+	hotspot, cacheHit, err := hh.readCache.Hotspot(hotspotID, hh.hotspotService.GetHotspot)
+	if err != nil {
+		c.JSON(http.StatusNotFound, errorResponse(c, err.Error()))
+		return
+	}
+	setCacheStatus(c, cacheHit)
+
+	// Drafts are only visible to their creator, list-only hotspots to invitees
+	if (hotspot.IsDraft && hotspot.CreatedBy != c.GetString("userID")) || !services.CanViewHotspot(hotspot, c.GetString("userID"), c.GetString("orgID")) {
+		c.JSON(http.StatusNotFound, errorResponse(c, "hotspot not found"))
+		return
+	}
+
+	// Count the view toward trending and host stats, ignoring the host's own views (best-effort)
+	if viewerID := c.GetString("userID"); !hotspot.IsDraft && viewerID != hotspot.CreatedBy {
+		if hh.trending != nil {
+			hh.trending.RecordView(hotspot.ID, viewerID)
+		}
+		if hh.analytics != nil {
+			hh.analytics.RecordView(hotspot.ID, viewerID)
+		}
+	}
+
+	// Clients revalidate with the version ETag
+	c.Header("ETag", hotspotETag(hotspot.Version))
+	c.Header("Cache-Control", "private, no-cache")
+	if etagMatches(c.GetHeader("If-None-Match"), hotspotETag(hotspot.Version)) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	// Copy before adding the per-response badge so the stored hotspot is left untouched
+	view := *hotspot
+	if hh.hostVerification != nil {
+		view.HostVerified = hh.hostVerification.VerifiedHosts([]string{hotspot.CreatedBy})[hotspot.CreatedBy]
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, &view, "Hotspot retrieved successfully"))
+}
+
+// BatchGetHotspots returns up to 50 hotspots by ID in one request
+func (hh *HotspotHandler) BatchGetHotspots(c *gin.Context) {
+	var req models.BatchHotspotsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format: "+err.Error()))
 		return
 	}
 
-	hotspot, err := hh.hotspotService.GetHotspot(hotspotID)
+	found, err := hh.hotspotService.GetHotspotsByIDs(req.IDs)
 	if err != nil {
-		c.JSON(http.StatusNotFound, models.ErrorResponseWithMessage(err.Error()))
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
 		return
 	}
 
-	c.JSON(http.StatusOK, models.SuccessResponse(hotspot, "Hotspot retrieved successfully"))
+	// Hidden hotspots are reported as missing, the same as a 404 from GetHotspot
+	userID := c.GetString("userID")
+	byID := make(map[string]*models.Hotspot, len(found))
+	hostIDs := make([]string, 0, len(found))
+	for _, hotspot := range found {
+		if (hotspot.IsDraft && hotspot.CreatedBy != userID) || !services.CanViewHotspot(hotspot, userID, c.GetString("orgID")) {
+			continue
+		}
+		byID[hotspot.ID] = hotspot
+		hostIDs = append(hostIDs, hotspot.CreatedBy)
+	}
+	var verified map[string]bool
+	if hh.hostVerification != nil {
+		verified = hh.hostVerification.VerifiedHosts(hostIDs)
+	}
+
+	response := models.BatchHotspotsResponse{Hotspots: []models.Hotspot{}, Missing: []string{}}
+	seen := make(map[string]bool, len(req.IDs))
+	for _, id := range req.IDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		hotspot, ok := byID[id]
+		if !ok {
+			response.Missing = append(response.Missing, id)
+			continue
+		}
+		// Copy before adding the per-response badge so the stored hotspot is left untouched
+		view := *hotspot
+		view.HostVerified = verified[hotspot.CreatedBy]
+		response.Hotspots = append(response.Hotspots, view)
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, response, "Hotspots retrieved successfully"))
+}
+
+// GetAttendees lists who is going to a hotspot with their availability and, where shared, online status
+func (hh *HotspotHandler) GetAttendees(c *gin.Context) {
+	hotspot, err := hh.hotspotService.GetHotspot(c.Param("id"))
+	if err != nil || (hotspot.IsDraft && hotspot.CreatedBy != c.GetString("userID")) || !services.CanViewHotspot(hotspot, c.GetString("userID"), c.GetString("orgID")) {
+		c.JSON(http.StatusNotFound, errorResponse(c, "hotspot not found"))
+		return
+	}
+
+	attendees := hh.hotspotService.GetAttendees(hotspot)
+	hh.presence.Annotate(attendees)
+	c.JSON(http.StatusOK, successResponse(c, attendees, "Attendees retrieved successfully"))
 }
 
 // UpdateHotspot updates an existing hotspot
@@ -76,13 +215,13 @@ func (hh *HotspotHandler) UpdateHotspot(c *gin.Context) {
 	// Get user ID from context
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, models.ErrorResponseWithMessage("Unauthorized"))
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
 		return
 	}
 
 	hotspotID := c.Param("id")
 	if hotspotID == "" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage("Hotspot ID is required"))
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Hotspot ID is required"))
 		return
 	}
 
@@ -90,18 +229,121 @@ func (hh *HotspotHandler) UpdateHotspot(c *gin.Context) {
 
 	// Bind JSON request
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage("Invalid request format: "+err.Error()))
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format: "+err.Error()))
 		return
 	}
+	if ifMatch := c.GetHeader("If-Match"); ifMatch != "" {
+		version, ok := parseHotspotETag(ifMatch)
+		if !ok {
+			c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid If-Match header"))
+			return
+		}
+		req.Version = &version
+	}
+
+	// Remember the schedule sequence so attendees are only notified of meaningful changes
+	previousSequence := -1
+	var previousInvited []string
+	if existing, err := hh.hotspotService.GetHotspot(hotspotID); err == nil {
+		previousSequence = existing.Sequence
+		previousInvited = append(previousInvited, existing.InvitedUserIDs...)
+	}
 
 	// Update hotspot
 	hotspot, err := hh.hotspotService.UpdateHotspot(userID.(string), hotspotID, &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage(err.Error()))
+		var conflict *services.VersionConflictError
+		switch {
+		case errors.As(err, &conflict):
+			c.Header("ETag", hotspotETag(conflict.Current))
+			resp := errorResponseWithCode(c, "version_conflict", err.Error())
+			resp.Data = gin.H{"current_version": conflict.Current}
+			c.JSON(http.StatusConflict, resp)
+		case errors.Is(err, services.ErrHotspotVersionRequired):
+			c.JSON(http.StatusPreconditionRequired, errorResponse(c, err.Error()))
+		default:
+			c.JSON(http.StatusBadRequest, errorResponse(c, err.Error()))
+		}
+		return
+	}
+
+	// Tell attendees when the time, place or status changed (best-effort)
+	if hh.notifications != nil && !hotspot.IsDraft && hotspot.Sequence != previousSequence {
+		hh.notifications.NotifyHotspotUpdated(hotspot)
+	}
+	// Invite anyone added by restricting the hotspot to a friend list
+	if hh.notifications != nil && !hotspot.IsDraft {
+		var added []string
+		for _, id := range hotspot.InvitedUserIDs {
+			if !containsID(previousInvited, id) {
+				added = append(added, id)
+			}
+		}
+		hh.notifications.NotifyHotspotInvites(hotspot, added)
+	}
+
+	c.Header("ETag", hotspotETag(hotspot.Version))
+	c.JSON(http.StatusOK, successResponse(c, hotspot, "Hotspot updated successfully"))
+}
+
+// hotspotETag formats a hotspot version as a strong entity tag
+func hotspotETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// parseHotspotETag reads the version from an If-Match value, accepting weak tags
+func parseHotspotETag(value string) (int64, bool) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "W/")
+	version, err := strconv.ParseInt(strings.Trim(value, `"`), 10, 64)
+	return version, err == nil
+}
+
+// etagMatches reports whether an If-None-Match value lists the given tag, comparing weakly
+func etagMatches(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	want := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == want {
+			return true
+		}
+	}
+	return false
+}
+
+// setCacheStatus reports whether the read cache served the response
+func setCacheStatus(c *gin.Context, hit bool) {
+	if hit {
+		c.Header("X-Cache", "HIT")
+	} else {
+		c.Header("X-Cache", "MISS")
+	}
+}
+
+// writeWithETag writes a JSON body tagged with a weak content hash, answering 304 when the client already has it
+func writeWithETag(c *gin.Context, body interface{}) {
+	writeCacheable(c, body, "private, no-cache")
+}
+
+// writeCacheable is writeWithETag with the given Cache-Control policy
+func writeCacheable(c *gin.Context, body interface{}, cacheControl string) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, "Failed to encode response"))
 		return
 	}
+	sum := sha1.Sum(data)
+	etag := `W/"` + hex.EncodeToString(sum[:8]) + `"`
 
-	c.JSON(http.StatusOK, models.SuccessResponse(hotspot, "Hotspot updated successfully"))
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", cacheControl)
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", data)
 }
 
 // DeleteHotspot deletes a hotspot
@@ -109,24 +351,86 @@ func (hh *HotspotHandler) DeleteHotspot(c *gin.Context) {
 	// Get user ID from context
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, models.ErrorResponseWithMessage("Unauthorized"))
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
 		return
 	}
 
 	hotspotID := c.Param("id")
 	if hotspotID == "" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage("Hotspot ID is required"))
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Hotspot ID is required"))
 		return
 	}
 
 	// Delete hotspot
 	err := hh.hotspotService.DeleteHotspot(userID.(string), hotspotID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage(err.Error()))
+		c.JSON(http.StatusBadRequest, errorResponse(c, err.Error()))
 		return
 	}
 
-	c.JSON(http.StatusOK, models.SuccessResponse(nil, "Hotspot deleted successfully"))
+	c.JSON(http.StatusOK, successResponse(c, nil, "Hotspot deleted successfully"))
+}
+
+// CloneHotspot copies a hotspot owned by the current user into a new one with a new schedule
+func (hh *HotspotHandler) CloneHotspot(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
+		return
+	}
+
+	hotspotID := c.Param("id")
+	if hotspotID == "" {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Hotspot ID is required"))
+		return
+	}
+
+	var req models.CloneHotspotRequest
+
+	// Bind JSON request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format: "+err.Error()))
+		return
+	}
+
+	// Clone hotspot
+	hotspot, err := hh.hotspotService.CloneHotspot(userID.(string), hotspotID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, successResponse(c, hotspot, "Hotspot cloned successfully"))
+}
+
+// PublishHotspot makes a draft hotspot live after validating it is complete
+func (hh *HotspotHandler) PublishHotspot(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
+		return
+	}
+
+	hotspotID := c.Param("id")
+	if hotspotID == "" {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Hotspot ID is required"))
+		return
+	}
+
+	// Publish hotspot
+	hotspot, err := hh.hotspotService.PublishHotspot(userID.(string), hotspotID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, err.Error()))
+		return
+	}
+
+	if hh.notifications != nil {
+		hh.notifications.NotifyHotspotInvites(hotspot, hotspot.InvitedUserIDs)
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, hotspot, "Hotspot published successfully"))
 }
 
 // JoinHotspot adds the current user to a hotspot
@@ -134,29 +438,86 @@ func (hh *HotspotHandler) JoinHotspot(c *gin.Context) {
 	// Get user ID from context
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, models.ErrorResponseWithMessage("Unauthorized"))
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
 		return
 	}
 
 	hotspotID := c.Param("id")
 	if hotspotID == "" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage("Hotspot ID is required"))
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Hotspot ID is required"))
 		return
 	}
 
-	// Join hotspot
-	hotspot, err := hh.hotspotService.JoinHotspot(userID.(string), hotspotID)
+	// Join hotspot, through a share link when one is given
+	var hotspot *models.Hotspot
+	var err error
+	if code := c.Query("share"); code != "" {
+		hotspot, err = hh.shareLinks.JoinHotspot(userID.(string), hotspotID, code)
+	} else {
+		hotspot, err = hh.hotspotService.JoinHotspot(userID.(string), hotspotID)
+	}
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage(err.Error()))
+		var audienceErr *services.AudienceError
+		if errors.As(err, &audienceErr) {
+			c.JSON(http.StatusForbidden, errorResponseWithCode(c, audienceErr.Code, audienceErr.Message))
+			return
+		}
+		if errors.Is(err, services.ErrShareLinkNotFound) || errors.Is(err, services.ErrShareLinkExpired) {
+			c.JSON(shareErrorStatus(err), errorResponse(c, err.Error()))
+			return
+		}
+		if errors.Is(err, services.ErrTicketRequired) {
+			c.JSON(http.StatusPaymentRequired, errorResponse(c, err.Error()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, errorResponse(c, err.Error()))
 		return
 	}
 
-	// Award points for joining a hotspot (best-effort)
-	if hh.gamification != nil {
-		_, _, _ = hh.gamification.AwardForHotspotJoin(userID.(string))
+	// Count the join toward trending and the host's RSVP funnel (best-effort)
+	if hh.trending != nil {
+		hh.trending.RecordJoin(hotspotID, userID.(string))
+	}
+	if hh.analytics != nil {
+		hh.analytics.RecordJoin(hotspotID, userID.(string))
 	}
 
-	c.JSON(http.StatusOK, models.SuccessResponse(hotspot, "Joined hotspot successfully"))
+	c.JSON(http.StatusOK, successResponse(c, hotspot, "Joined hotspot successfully"))
+}
+
+// InviteToHotspot invites one of the host's friend lists and/or individual friends
+func (hh *HotspotHandler) InviteToHotspot(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
+		return
+	}
+
+	hotspotID := c.Param("id")
+	if hotspotID == "" {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Hotspot ID is required"))
+		return
+	}
+
+	var req models.InviteToHotspotRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format: "+err.Error()))
+		return
+	}
+
+	hotspot, added, err := hh.hotspotService.InviteToHotspot(userID.(string), hotspotID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, err.Error()))
+		return
+	}
+
+	// Drafts hold their invitations until published
+	if hh.notifications != nil && !hotspot.IsDraft {
+		hh.notifications.NotifyHotspotInvites(hotspot, added)
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, hotspot, "Invitations sent"))
 }
 
 // LeaveHotspot removes the current user from a hotspot
@@ -164,24 +525,24 @@ func (hh *HotspotHandler) LeaveHotspot(c *gin.Context) {
 	// Get user ID from context
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, models.ErrorResponseWithMessage("Unauthorized"))
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
 		return
 	}
 
 	hotspotID := c.Param("id")
 	if hotspotID == "" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage("Hotspot ID is required"))
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Hotspot ID is required"))
 		return
 	}
 
 	// Leave hotspot
 	hotspot, err := hh.hotspotService.LeaveHotspot(userID.(string), hotspotID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage(err.Error()))
+		c.JSON(http.StatusBadRequest, errorResponse(c, err.Error()))
 		return
 	}
 
-	c.JSON(http.StatusOK, models.SuccessResponse(hotspot, "Left hotspot successfully"))
+	c.JSON(http.StatusOK, successResponse(c, hotspot, "Left hotspot successfully"))
 }
 
 // SearchHotspots searches for hotspots based on location and filters
@@ -193,11 +554,11 @@ func (hh *HotspotHandler) SearchHotspots(c *gin.Context) {
 		if lat, err := strconv.ParseFloat(latStr, 64); err == nil {
 			req.Latitude = lat
 		} else {
-			c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage("Invalid latitude"))
+			c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid latitude"))
 			return
 		}
 	} else {
-		c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage("Latitude is required"))
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Latitude is required"))
 		return
 	}
 
@@ -205,11 +566,11 @@ func (hh *HotspotHandler) SearchHotspots(c *gin.Context) {
 		if lon, err := strconv.ParseFloat(lonStr, 64); err == nil {
 			req.Longitude = lon
 		} else {
-			c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage("Invalid longitude"))
+			c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid longitude"))
 			return
 		}
 	} else {
-		c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage("Longitude is required"))
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Longitude is required"))
 		return
 	}
 
@@ -239,6 +600,12 @@ func (hh *HotspotHandler) SearchHotspots(c *gin.Context) {
 		}
 	}
 
+	if verifiedStr := c.Query("verified_hosts_only"); verifiedStr != "" {
+		if verifiedOnly, err := strconv.ParseBool(verifiedStr); err == nil {
+			req.VerifiedHostsOnly = verifiedOnly
+		}
+	}
+
 	// Pagination
 	req.Limit = 20 // Default limit
 	if limitStr := c.Query("limit"); limitStr != "" {
@@ -254,14 +621,21 @@ func (hh *HotspotHandler) SearchHotspots(c *gin.Context) {
 		}
 	}
 
-	// Search hotspots
-	response, err := hh.hotspotService.SearchHotspots(&req)
+	// Search hotspots, paging after the viewer's filters
+	req.OrgID = c.GetString("orgID")
+	limit, offset := req.Limit, req.Offset
+	req.Limit, req.Offset = searchScanSize(limit, offset), 0
+	response, cacheHit, err := hh.readCache.Search(&req, hh.hotspotService.SearchHotspots)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponseWithMessage(err.Error()))
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
 		return
 	}
+	setCacheStatus(c, cacheHit)
+	response = hh.pageVisible(c, response, req.VerifiedHostsOnly, limit, offset)
+	hh.annotateTravel(c, req.Latitude, req.Longitude, response.Hotspots, c.Query("travel") == "true")
+	hh.recordImpressions(c, response.Hotspots)
 
-	c.JSON(http.StatusOK, models.SuccessResponse(response, "Hotspots retrieved successfully"))
+	writeWithETag(c, successResponse(c, response, "Hotspots retrieved successfully"))
 }
 
 // GetUserHotspots retrieves hotspots created by the current user
@@ -269,64 +643,462 @@ func (hh *HotspotHandler) GetUserHotspots(c *gin.Context) {
 	// Get user ID from context
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, models.ErrorResponseWithMessage("Unauthorized"))
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
 		return
 	}
 
 	hotspots, err := hh.hotspotService.GetUserHotspots(userID.(string))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponseWithMessage(err.Error()))
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
 		return
 	}
 
-	c.JSON(http.StatusOK, models.SuccessResponse(hotspots, "User hotspots retrieved successfully"))
+	c.JSON(http.StatusOK, successResponse(c, hotspots, "User hotspots retrieved successfully"))
 }
 
-// GetNearbyHotspots is a convenience endpoint that gets nearby hotspots for the current user
-func (hh *HotspotHandler) GetNearbyHotspots(c *gin.Context) {
-	// This endpoint could use the user's last known location or require location parameters
-	// For now, we'll require location parameters similar to SearchHotspots
-	var req models.HotspotSearchRequest
+// ListCities returns cities that have browsable hotspots, with counts
+func (hh *HotspotHandler) ListCities(c *gin.Context) {
+	cities, err := hh.hotspotService.ListCities(c.GetString("orgID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
 
-	// Parse required location parameters
-	if latStr := c.Query("latitude"); latStr != "" {
-		if lat, err := strconv.ParseFloat(latStr, 64); err == nil {
-			req.Latitude = lat
-		} else {
-			c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage("Invalid latitude"))
+	c.JSON(http.StatusOK, successResponse(c, cities, "Cities retrieved successfully"))
+}
+
+// GetHotspotsByCity lists hotspots in a city for users without location access
+func (hh *HotspotHandler) GetHotspotsByCity(c *gin.Context) {
+	city := c.Param("city")
+	if city == "" {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "City is required"))
+		return
+	}
+
+	// Pagination
+	limit := 20 // Default limit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
+			limit = l
+		}
+	}
+	offset := 0
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
+			offset = o
+		}
+	}
+
+	response, err := hh.hotspotService.GetHotspotsByCity(c.GetString("orgID"), city, c.Query("country"), searchScanSize(limit, offset), 0)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, err.Error()))
+		return
+	}
+	response = hh.pageVisible(c, response, c.Query("verified_hosts_only") == "true", limit, offset)
+	hh.recordImpressions(c, response.Hotspots)
+
+	c.JSON(http.StatusOK, successResponse(c, response, "Hotspots retrieved successfully"))
+}
+
+// GetHotspotCounts returns per-geohash hotspot counts for map badges at low zoom
+func (hh *HotspotHandler) GetHotspotCounts(c *gin.Context) {
+	parts := strings.Split(c.Query("bbox"), ",")
+	if len(parts) != 4 {
+		c.JSON(http.StatusBadRequest, errorResponse(c, services.ErrInvalidBoundingBox.Error()))
+		return
+	}
+	var coords [4]float64
+	for i, part := range parts {
+		value, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse(c, services.ErrInvalidBoundingBox.Error()))
 			return
 		}
-	} else {
-		c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage("Latitude is required"))
+		coords[i] = value
+	}
+	box := models.BoundingBox{
+		SouthWest: models.HotspotLocation{Latitude: coords[0], Longitude: coords[1]},
+		NorthEast: models.HotspotLocation{Latitude: coords[2], Longitude: coords[3]},
+	}
+
+	precision := 0
+	if precisionStr := c.Query("precision"); precisionStr != "" {
+		p, err := strconv.Atoi(precisionStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid precision"))
+			return
+		}
+		precision = p
+	}
+
+	response, err := hh.geospatialService.CountHotspots(c.GetString("orgID"), box, precision)
+	if err != nil {
+		if errors.Is(err, services.ErrHotspotCountsUnavailable) {
+			c.JSON(http.StatusServiceUnavailable, errorResponse(c, err.Error()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, errorResponse(c, err.Error()))
 		return
 	}
 
-	if lonStr := c.Query("longitude"); lonStr != "" {
-		if lon, err := strconv.ParseFloat(lonStr, 64); err == nil {
-			req.Longitude = lon
-		} else {
-			c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage("Invalid longitude"))
+	c.JSON(http.StatusOK, successResponse(c, response, "Hotspot counts retrieved successfully"))
+}
+
+// GetTrendingHotspots ranks nearby hotspots by recent joins, chat activity, and views
+func (hh *HotspotHandler) GetTrendingHotspots(c *gin.Context) {
+	lat, lon, ok := parseLocationQuery(c)
+	if !ok {
+		return
+	}
+
+	// Default radius to 10km if not provided
+	radius := 10.0
+	if radiusStr := c.Query("radius"); radiusStr != "" {
+		if r, err := strconv.ParseFloat(radiusStr, 64); err == nil && r > 0 && r <= 100 {
+			radius = r
+		}
+	}
+
+	limit := 10 // Default limit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 50 {
+			limit = l
+		}
+	}
+
+	trending, err := hh.trending.GetTrending(c.GetString("userID"), c.GetString("orgID"), lat, lon, radius, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+	if hh.hostVerification != nil && len(trending) > 0 {
+		hostIDs := make([]string, len(trending))
+		for i, t := range trending {
+			hostIDs[i] = t.Hotspot.CreatedBy
+		}
+		verified := hh.hostVerification.VerifiedHosts(hostIDs)
+		for i := range trending {
+			trending[i].Hotspot.HostVerified = verified[trending[i].Hotspot.CreatedBy]
+		}
+	}
+	if hh.analytics != nil && len(trending) > 0 {
+		ids := make([]string, len(trending))
+		for i, t := range trending {
+			ids[i] = t.Hotspot.ID
+		}
+		hh.analytics.RecordImpressions(c.GetString("userID"), ids)
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, trending, "Trending hotspots retrieved successfully"))
+}
+
+// GetHotspotStats returns view and impression statistics to the host
+func (hh *HotspotHandler) GetHotspotStats(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
+		return
+	}
+
+	hotspotID := c.Param("id")
+	if hotspotID == "" {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Hotspot ID is required"))
+		return
+	}
+
+	stats, err := hh.analytics.GetHotspotStats(userID.(string), hotspotID)
+	if err != nil {
+		c.JSON(http.StatusForbidden, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, stats, "Hotspot stats retrieved successfully"))
+}
+
+// GetHotspotAnalytics returns the host dashboard: RSVP funnel, retention, popular times and chat engagement
+func (hh *HotspotHandler) GetHotspotAnalytics(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
+		return
+	}
+
+	hotspotID := c.Param("id")
+	if hotspotID == "" {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Hotspot ID is required"))
+		return
+	}
+
+	analytics, err := hh.analytics.GetHotspotAnalytics(userID.(string), hotspotID)
+	if err != nil {
+		c.JSON(http.StatusForbidden, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, analytics, "Hotspot analytics retrieved successfully"))
+}
+
+// GetOccupancyTimeline returns how RSVPs and check-ins changed over time (host only)
+func (hh *HotspotHandler) GetOccupancyTimeline(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
+		return
+	}
+
+	hotspotID := c.Param("id")
+	if hotspotID == "" {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Hotspot ID is required"))
+		return
+	}
+
+	var interval time.Duration
+	if value := c.Query("interval_minutes"); value != "" {
+		minutes, err := strconv.Atoi(value)
+		if err != nil || minutes < 5 || minutes > 1440 {
+			c.JSON(http.StatusBadRequest, errorResponse(c, "interval_minutes must be between 5 and 1440"))
 			return
 		}
-	} else {
-		c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage("Longitude is required"))
+		interval = time.Duration(minutes) * time.Minute
+	}
+
+	timeline, err := hh.analytics.GetOccupancyTimeline(userID.(string), hotspotID, interval)
+	if err != nil {
+		c.JSON(http.StatusForbidden, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, timeline, "Occupancy timeline retrieved successfully"))
+}
+
+// GetHotspotActivity returns a page of the hotspot's activity log, newest first (host only)
+func (hh *HotspotHandler) GetHotspotActivity(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
+		return
+	}
+
+	hotspotID := c.Param("id")
+	if hotspotID == "" {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Hotspot ID is required"))
 		return
 	}
 
+	limit, offset := parsePagination(c)
+	activity, err := hh.hotspotService.GetHotspotActivity(userID.(string), hotspotID, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusForbidden, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, activity, "Hotspot activity retrieved successfully"))
+}
+
+// containsID reports whether ids contains id
+func containsID(ids []string, id string) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	// searchScanLimit is the fewest matches read for a search page, so hotspots hidden from
+	// the viewer do not leave it short
+	searchScanLimit = 500
+	// nearbyLimit is how many hotspots the nearby endpoint returns
+	nearbyLimit = 10
+)
+
+// visibleHotspots drops hotspots outside the current user's organization and friend-list-only
+// hotspots they were not invited to
+func visibleHotspots(c *gin.Context, results []models.HotspotWithDistance) []models.HotspotWithDistance {
+	userID, orgID := c.GetString("userID"), c.GetString("orgID")
+	visible := make([]models.HotspotWithDistance, 0, len(results))
+	for _, r := range results {
+		if services.CanViewHotspot(&r.Hotspot, userID, orgID) {
+			visible = append(visible, r)
+		}
+	}
+	return visible
+}
+
+// pageVisible drops results the viewer may not see, then returns the requested page with Total
+// and HasMore counted over what is left, so pages stay full and hidden hotspots are not counted.
+// The response holds the first searchScanSize matches; its HasMore says storage held more.
+func (hh *HotspotHandler) pageVisible(c *gin.Context, response *models.HotspotSearchResponse, verifiedOnly bool, limit, offset int) *models.HotspotSearchResponse {
+	visible := hh.markVerifiedHosts(hh.withinAgeRange(c, visibleHotspots(c, response.Hotspots)), verifiedOnly)
+	total := len(visible)
+	start := min(offset, total)
+	end := min(start+limit, total)
+	return &models.HotspotSearchResponse{
+		Hotspots: visible[start:end],
+		Total:    total,
+		HasMore:  end < total || response.HasMore,
+	}
+}
+
+// searchScanSize is how many matches to read before filtering for the viewer and paging
+func searchScanSize(limit, offset int) int {
+	return max(searchScanLimit, offset+limit)
+}
+
+// withinAgeRange drops hotspots whose host is outside the current user's preferred age range
+func (hh *HotspotHandler) withinAgeRange(c *gin.Context, results []models.HotspotWithDistance) []models.HotspotWithDistance {
+	if hh.profiles == nil || len(results) == 0 {
+		return results
+	}
+	hostIDs := make([]string, len(results))
+	for i, r := range results {
+		hostIDs[i] = r.Hotspot.CreatedBy
+	}
+	outside := hh.profiles.UsersOutsideAgeRange(c.GetString("userID"), hostIDs)
+	if len(outside) == 0 {
+		return results
+	}
+
+	kept := make([]models.HotspotWithDistance, 0, len(results))
+	for _, r := range results {
+		if !outside[r.Hotspot.CreatedBy] {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+// markVerifiedHosts sets the verified host badge on each result, optionally dropping unverified hosts
+func (hh *HotspotHandler) markVerifiedHosts(results []models.HotspotWithDistance, verifiedOnly bool) []models.HotspotWithDistance {
+	if hh.hostVerification == nil || len(results) == 0 {
+		return results
+	}
+	hostIDs := make([]string, len(results))
+	for i, r := range results {
+		hostIDs[i] = r.Hotspot.CreatedBy
+	}
+	verified := hh.hostVerification.VerifiedHosts(hostIDs)
+
+	marked := make([]models.HotspotWithDistance, 0, len(results))
+	for _, r := range results {
+		r.Hotspot.HostVerified = verified[r.Hotspot.CreatedBy]
+		if verifiedOnly && !r.Hotspot.HostVerified {
+			continue
+		}
+		marked = append(marked, r)
+	}
+	return marked
+}
+
+// annotateTravel adds the bearing from the search origin and, on request, walking/driving times
+func (hh *HotspotHandler) annotateTravel(c *gin.Context, lat, lon float64, results []models.HotspotWithDistance, withTravel bool) {
+	if hh.travelTimes == nil {
+		return
+	}
+	origin := models.HotspotLocation{Latitude: lat, Longitude: lon}
+	hh.travelTimes.Annotate(c.Request.Context(), origin, results, withTravel)
+}
+
+// recordImpressions counts search results as impressions for the current user (best-effort)
+func (hh *HotspotHandler) recordImpressions(c *gin.Context, results []models.HotspotWithDistance) {
+	if hh.analytics == nil || len(results) == 0 {
+		return
+	}
+	ids := make([]string, len(results))
+	for i, r := range results {
+		ids[i] = r.Hotspot.ID
+	}
+	hh.analytics.RecordImpressions(c.GetString("userID"), ids)
+}
+
+// parseLocationQuery reads required latitude/longitude query parameters,
+// writing a 400 response and returning ok=false when they are missing or invalid
+func parseLocationQuery(c *gin.Context) (lat, lon float64, ok bool) {
+	latStr := c.Query("latitude")
+	if latStr == "" {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Latitude is required"))
+		return 0, 0, false
+	}
+	lat, err := strconv.ParseFloat(latStr, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid latitude"))
+		return 0, 0, false
+	}
+
+	lonStr := c.Query("longitude")
+	if lonStr == "" {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Longitude is required"))
+		return 0, 0, false
+	}
+	lon, err = strconv.ParseFloat(lonStr, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid longitude"))
+		return 0, 0, false
+	}
+
+	return lat, lon, true
+}
+
+// GetNearbyHotspots is a convenience endpoint that gets nearby hotspots for the current user
+func (hh *HotspotHandler) GetNearbyHotspots(c *gin.Context) {
+	var req models.HotspotSearchRequest
+
+	// Without coordinates, search around the user's last known location
+	latStr, lonStr := c.Query("latitude"), c.Query("longitude")
+	if latStr == "" && lonStr == "" {
+		location, ok := hh.userLocations.LastKnownLocation(c.GetString("userID"))
+		if !ok {
+			c.JSON(http.StatusBadRequest, errorResponse(c, "Location is required: send latitude and longitude or update your location"))
+			return
+		}
+		req.Latitude, req.Longitude = location.Latitude, location.Longitude
+		c.Header("X-Location-Source", "last-known")
+	} else {
+		if latStr == "" {
+			c.JSON(http.StatusBadRequest, errorResponse(c, "Latitude is required"))
+			return
+		}
+		lat, err := strconv.ParseFloat(latStr, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid latitude"))
+			return
+		}
+		if lonStr == "" {
+			c.JSON(http.StatusBadRequest, errorResponse(c, "Longitude is required"))
+			return
+		}
+		lon, err := strconv.ParseFloat(lonStr, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid longitude"))
+			return
+		}
+		req.Latitude, req.Longitude = lat, lon
+	}
+
 	// Default settings for nearby hotspots
 	req.Radius = 5.0 // 5km radius
 	isActive := true
 	req.IsActive = &isActive
-	req.Limit = 10
+	req.Limit = searchScanSize(nearbyLimit, 0)
+	req.OrgID = c.GetString("orgID")
 
 	// Search for nearby active hotspots
-	response, err := hh.hotspotService.SearchHotspots(&req)
+	response, cacheHit, err := hh.readCache.Search(&req, hh.hotspotService.SearchHotspots)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponseWithMessage(err.Error()))
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
 		return
 	}
+	setCacheStatus(c, cacheHit)
+	response = hh.pageVisible(c, response, c.Query("verified_hosts_only") == "true", nearbyLimit, 0)
+	hh.annotateTravel(c, req.Latitude, req.Longitude, response.Hotspots, c.Query("travel") == "true")
+	hh.recordImpressions(c, response.Hotspots)
 
-	c.JSON(http.StatusOK, models.SuccessResponse(response, "Nearby hotspots retrieved successfully"))
+	writeWithETag(c, successResponse(c, response, "Nearby hotspots retrieved successfully"))
 }
 
 // SearchHotspotsOptimized performs optimized geospatial search with clustering
@@ -335,13 +1107,13 @@ func (hh *HotspotHandler) SearchHotspotsOptimized(c *gin.Context) {
 
 	// Bind JSON request
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage("Invalid request format: "+err.Error()))
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format: "+err.Error()))
 		return
 	}
 
 	// Validate required fields
 	if req.GeospatialQuery.Center.Latitude == 0 && req.GeospatialQuery.Center.Longitude == 0 {
-		c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage("Center coordinates are required"))
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Center coordinates are required"))
 		return
 	}
 
@@ -360,6 +1132,7 @@ func (hh *HotspotHandler) SearchHotspotsOptimized(c *gin.Context) {
 	if req.GeospatialQuery.ZoomLevel <= 0 {
 		req.GeospatialQuery.ZoomLevel = 10 // Default zoom level
 	}
+	req.Filters.OrgID = c.GetString("orgID")
 
 	// Set default clustering configuration
 	if req.Clustering.Mode == "" {
@@ -371,15 +1144,39 @@ func (hh *HotspotHandler) SearchHotspotsOptimized(c *gin.Context) {
 	if req.Clustering.MaxClusterSize <= 0 {
 		req.Clustering.MaxClusterSize = 100
 	}
+	if req.Clustering.IncludeHotspots {
+		if req.Clustering.PreviewCount <= 0 {
+			req.Clustering.PreviewCount = 3
+		}
+		if req.Clustering.PreviewCount > 10 {
+			req.Clustering.PreviewCount = 10
+		}
+		switch req.Clustering.PreviewSort {
+		case "":
+			req.Clustering.PreviewSort = models.ClusterPreviewSortOccupancy
+		case models.ClusterPreviewSortOccupancy, models.ClusterPreviewSortStartTime:
+		default:
+			c.JSON(http.StatusBadRequest, errorResponse(c, "preview_sort must be occupancy or start_time"))
+			return
+		}
+	}
 
-	// Perform optimized search
+	// Perform optimized search, paging individual hotspots after the viewer's filters
+	limit, offset := req.Pagination.Limit, req.Pagination.Offset
+	req.Pagination.Limit, req.Pagination.Offset = searchScanSize(limit, offset), 0
 	response, err := hh.geospatialService.SearchHotspotsOptimized(&req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponseWithMessage(err.Error()))
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
 		return
 	}
+	if len(response.Clusters) == 0 {
+		page := hh.pageVisible(c, &models.HotspotSearchResponse{Hotspots: response.Hotspots, HasMore: response.HasMore}, req.Filters.VerifiedHostsOnly, limit, offset)
+		response.Hotspots, response.TotalCount, response.HasMore = page.Hotspots, page.Total, page.HasMore
+	}
+	hh.annotateTravel(c, req.GeospatialQuery.Center.Latitude, req.GeospatialQuery.Center.Longitude, response.Hotspots, req.IncludeTravel)
+	hh.recordImpressions(c, response.Hotspots)
 
-	c.JSON(http.StatusOK, models.SuccessResponse(response, "Optimized search completed successfully"))
+	c.JSON(http.StatusOK, successResponse(c, response, "Optimized search completed successfully"))
 }
 
 // GetCacheStats returns cache performance statistics
@@ -390,15 +1187,18 @@ func (hh *HotspotHandler) GetCacheStats(c *gin.Context) {
 		"status":  "healthy",
 	}
 
+	stats["read_cache"] = hh.readCache.Stats()
+
 	// Add Redis stats if available
 	if hh.geospatialService != nil {
 		// For now, we'll add a placeholder
 		// In a real implementation, you'd get actual cache stats from Redis
 		stats["cache"] = map[string]interface{}{
-			"available": true,
-			"type":      "redis",
+			"available":          true,
+			"type":               "redis",
+			"coalesced_searches": hh.geospatialService.CoalescedSearches(),
 		}
 	}
 
-	c.JSON(http.StatusOK, models.SuccessResponse(stats, "Cache statistics retrieved"))
+	c.JSON(http.StatusOK, successResponse(c, stats, "Cache statistics retrieved"))
 }
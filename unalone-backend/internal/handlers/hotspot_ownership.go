@@ -0,0 +1,107 @@
+// Hotspot co-host and ownership transfer handlers
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"unalone-backend/internal/models"
+	"unalone-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AddCoHost makes an attendee a co-host of the host's hotspot
+func (hh *HotspotHandler) AddCoHost(c *gin.Context) {
+	var req models.HotspotMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format: "+err.Error()))
+		return
+	}
+
+	hotspot, err := hh.hotspotService.AddCoHost(c.GetString("userID"), c.Param("id"), req.UserID)
+	if err != nil {
+		c.JSON(ownershipErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, hotspot, "Co-host added successfully"))
+}
+
+// RemoveCoHost takes an attendee off the co-host list
+func (hh *HotspotHandler) RemoveCoHost(c *gin.Context) {
+	hotspot, err := hh.hotspotService.RemoveCoHost(c.GetString("userID"), c.Param("id"), c.Param("userId"))
+	if err != nil {
+		c.JSON(ownershipErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, hotspot, "Co-host removed successfully"))
+}
+
+// TransferOwnership offers the hotspot to another attendee, who has to accept it
+func (hh *HotspotHandler) TransferOwnership(c *gin.Context) {
+	var req models.HotspotMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format: "+err.Error()))
+		return
+	}
+
+	hotspot, err := hh.hotspotService.OfferOwnership(c.GetString("userID"), c.Param("id"), req.UserID)
+	if err != nil {
+		c.JSON(ownershipErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+	if hh.notifications != nil {
+		_ = hh.notifications.NotifyOwnershipOffer(hotspot)
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, hotspot, "Ownership offer sent"))
+}
+
+// CancelOwnershipTransfer withdraws the host's pending ownership offer
+func (hh *HotspotHandler) CancelOwnershipTransfer(c *gin.Context) {
+	hotspot, err := hh.hotspotService.CancelOwnershipOffer(c.GetString("userID"), c.Param("id"))
+	if err != nil {
+		c.JSON(ownershipErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, hotspot, "Ownership offer cancelled"))
+}
+
+// AcceptOwnership makes the current user the host of a hotspot they were offered
+func (hh *HotspotHandler) AcceptOwnership(c *gin.Context) {
+	hotspot, err := hh.hotspotService.AcceptOwnership(c.GetString("userID"), c.Param("id"))
+	if err != nil {
+		c.JSON(ownershipErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, hotspot, "You are now the host"))
+}
+
+// DeclineOwnership turns down an ownership offer
+func (hh *HotspotHandler) DeclineOwnership(c *gin.Context) {
+	hotspot, err := hh.hotspotService.DeclineOwnership(c.GetString("userID"), c.Param("id"))
+	if err != nil {
+		c.JSON(ownershipErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, hotspot, "Ownership offer declined"))
+}
+
+// ownershipErrorStatus maps co-host and ownership errors to HTTP status codes
+func ownershipErrorStatus(err error) int {
+	switch {
+	case err.Error() == "hotspot not found", errors.Is(err, services.ErrNoOwnershipOffer):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrOwnershipOfferExpired):
+		return http.StatusGone
+	case err.Error() == "only the host can manage co-hosts and ownership":
+		return http.StatusForbidden
+	default:
+		return http.StatusBadRequest
+	}
+}
@@ -0,0 +1,40 @@
+// Interest taxonomy handlers
+package handlers
+
+import (
+	"net/http"
+
+	"unalone-backend/internal/i18n"
+	"unalone-backend/internal/models"
+	"unalone-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InterestHandler serves the curated interest catalog
+type InterestHandler struct{}
+
+// NewInterestHandler creates a new interest handler
+func NewInterestHandler() *InterestHandler {
+	return &InterestHandler{}
+}
+
+// ListInterests returns the catalog grouped by category, labelled in the request language
+func (ih *InterestHandler) ListInterests(c *gin.Context) {
+	catalog := services.ListInterestCatalog(i18n.FromContext(c.Request.Context()))
+	c.JSON(http.StatusOK, successResponse(c, catalog, "Interests retrieved successfully"))
+}
+
+// SuggestInterests returns catalog interests matching typed text, tolerating small typos
+func (ih *InterestHandler) SuggestInterests(c *gin.Context) {
+	var req models.SuggestInterestsRequest
+
+	// Bind query parameters
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format: "+err.Error()))
+		return
+	}
+
+	suggestions := services.SuggestInterests(req.Q, i18n.FromContext(c.Request.Context()), req.Limit)
+	c.JSON(http.StatusOK, successResponse(c, suggestions, "Interest suggestions retrieved successfully"))
+}
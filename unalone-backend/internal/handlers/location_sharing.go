@@ -0,0 +1,101 @@
+// Live location sharing handlers for attendees of an active hotspot
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"unalone-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StartLocationSharing opts the user in to sharing their location with the hotspot for a limited time.
+// Positions are then sent over the hotspot WebSocket as {"type":"location","latitude":..,"longitude":..}.
+func (hh *ChatHandler) StartLocationSharing(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
+		return
+	}
+
+	hotspotID := c.Param("id")
+	if hotspotID == "" {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Hotspot ID is required"))
+		return
+	}
+
+	var req models.StartLocationSharingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format: "+err.Error()))
+		return
+	}
+
+	share, err := hh.locations.StartSharing(userID.(string), hotspotID, time.Duration(req.DurationMinutes)*time.Minute)
+	if err != nil {
+		status := http.StatusBadRequest
+		if err.Error() == "hotspot not found" {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, share, "Location sharing started"))
+}
+
+// StopLocationSharing ends the user's location share for the hotspot
+func (hh *ChatHandler) StopLocationSharing(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
+		return
+	}
+
+	hotspotID := c.Param("id")
+	if hotspotID == "" {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Hotspot ID is required"))
+		return
+	}
+
+	hh.locations.StopSharing(userID.(string), hotspotID)
+	c.JSON(http.StatusOK, successResponse(c, nil, "Location sharing stopped"))
+}
+
+// GetSharedLocations returns the current positions of attendees sharing their location
+func (hh *ChatHandler) GetSharedLocations(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
+		return
+	}
+
+	hotspotID := c.Param("id")
+	if hotspotID == "" {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Hotspot ID is required"))
+		return
+	}
+
+	// Only attendees may see where other attendees are
+	hotspot, err := hh.hotspotService.GetHotspot(hotspotID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, errorResponse(c, "Hotspot not found"))
+		return
+	}
+	isMember := false
+	for _, id := range hotspot.Attendees {
+		if id == userID.(string) {
+			isMember = true
+			break
+		}
+	}
+	if !isMember {
+		c.JSON(http.StatusForbidden, errorResponse(c, "Not a member of this hotspot"))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, hh.locations.ActiveSharesFor(userID.(string), hotspotID), "Shared locations retrieved"))
+}
@@ -0,0 +1,96 @@
+// Admin metrics handlers for platform usage
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"unalone-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultMetricsDays is how many finished days the dashboard shows by default
+const defaultMetricsDays = 30
+
+// MetricsHandler serves the admin usage dashboard
+type MetricsHandler struct {
+	metrics   *services.PlatformMetricsService
+	quota     *services.QuotaService
+	firestore *services.FirestoreGuard
+}
+
+// NewMetricsHandler creates a new metrics handler
+func NewMetricsHandler(pm *services.PlatformMetricsService, qs *services.QuotaService, fg *services.FirestoreGuard) *MetricsHandler {
+	return &MetricsHandler{metrics: pm, quota: qs, firestore: fg}
+}
+
+// GetMetrics returns daily active users, registrations, hotspot, chat and AI activity
+// for the requested number of finished days (default 30) plus today so far
+func (mh *MetricsHandler) GetMetrics(c *gin.Context) {
+	days := defaultMetricsDays
+	if raw := c.Query("days"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid days"))
+			return
+		}
+		days = n
+	}
+
+	dashboard, err := mh.metrics.GetDashboard(days)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if days < 1 || days > services.MaxMetricsDays {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, dashboard, "Metrics retrieved successfully"))
+}
+
+// GetProviderUsage returns calls, estimated cost and budget state per external provider
+// for today and the days before it (days, default and at most 7)
+func (mh *MetricsHandler) GetProviderUsage(c *gin.Context) {
+	days := services.MaxUsageDays
+	if raw := c.Query("days"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid days"))
+			return
+		}
+		days = n
+	}
+
+	report, err := mh.quota.Usage(days)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, report, "Provider usage retrieved"))
+}
+
+// GetFirestoreUsage returns documents read and written, rejected queries and daily budget state
+// for today and the days before it (days, default and at most 7), with the per-request limits
+func (mh *MetricsHandler) GetFirestoreUsage(c *gin.Context) {
+	days := services.MaxUsageDays
+	if raw := c.Query("days"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid days"))
+			return
+		}
+		days = n
+	}
+
+	report, err := mh.firestore.Usage(days)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, report, "Firestore usage retrieved"))
+}
@@ -0,0 +1,96 @@
+// Notification handlers for the in-app inbox
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"unalone-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationHandler handles notification inbox endpoints
+type NotificationHandler struct {
+	notificationService *services.NotificationService
+}
+
+// NewNotificationHandler creates a new notification handler
+func NewNotificationHandler(ns *services.NotificationService) *NotificationHandler {
+	return &NotificationHandler{notificationService: ns}
+}
+
+// ListNotifications returns the user's inbox with the unread count
+func (nh *NotificationHandler) ListNotifications(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
+		return
+	}
+
+	limit := 20 // Default limit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
+			limit = l
+		}
+	}
+
+	offset := 0
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
+			offset = o
+		}
+	}
+
+	unreadOnly, _ := strconv.ParseBool(c.Query("unread_only"))
+
+	response, err := nh.notificationService.ListNotifications(userID.(string), unreadOnly, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, response, "Notifications retrieved successfully"))
+}
+
+// MarkRead marks a single notification as read
+func (nh *NotificationHandler) MarkRead(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
+		return
+	}
+
+	notificationID := c.Param("id")
+	if notificationID == "" {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Notification ID is required"))
+		return
+	}
+
+	if err := nh.notificationService.MarkRead(userID.(string), notificationID); err != nil {
+		c.JSON(http.StatusNotFound, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, nil, "Notification marked as read"))
+}
+
+// MarkAllRead marks every notification as read
+func (nh *NotificationHandler) MarkAllRead(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
+		return
+	}
+
+	updated, err := nh.notificationService.MarkAllRead(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, gin.H{"updated": updated}, "All notifications marked as read"))
+}
@@ -0,0 +1,142 @@
+// Organization handlers for campus and organization deployments
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"unalone-backend/internal/models"
+	"unalone-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OrganizationHandler handles organization endpoints
+type OrganizationHandler struct {
+	orgService *services.OrganizationService
+}
+
+// NewOrganizationHandler creates a new organization handler
+func NewOrganizationHandler(ors *services.OrganizationService) *OrganizationHandler {
+	return &OrganizationHandler{orgService: ors}
+}
+
+// GetMyOrganization returns the current user's organization and role
+func (oh *OrganizationHandler) GetMyOrganization(c *gin.Context) {
+	membership, err := oh.orgService.GetMembership(c.GetString("userID"))
+	if err != nil {
+		c.JSON(orgErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, membership, "Organization retrieved"))
+}
+
+// JoinOrganization joins the organization with the given invite code
+func (oh *OrganizationHandler) JoinOrganization(c *gin.Context) {
+	var req models.JoinOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format: "+err.Error()))
+		return
+	}
+	membership, err := oh.orgService.Join(c.GetString("userID"), req.InviteCode)
+	if err != nil {
+		c.JSON(orgErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, membership, "Joined organization"))
+}
+
+// LeaveOrganization takes the current user back to the public network
+func (oh *OrganizationHandler) LeaveOrganization(c *gin.Context) {
+	if err := oh.orgService.Leave(c.GetString("userID")); err != nil {
+		c.JSON(orgErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, nil, "Left organization"))
+}
+
+// ListMembers lists the members of the admin's organization
+func (oh *OrganizationHandler) ListMembers(c *gin.Context) {
+	members, err := oh.orgService.Members(c.Request.Context(), c.GetString("orgID"))
+	if err != nil {
+		c.JSON(orgErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, members, "Organization members retrieved"))
+}
+
+// SetMemberRole makes a member an admin or a regular member
+func (oh *OrganizationHandler) SetMemberRole(c *gin.Context) {
+	var req models.SetOrgRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format: "+err.Error()))
+		return
+	}
+	member, err := oh.orgService.SetRole(c.GetString("orgID"), c.Param("userId"), req.Role)
+	if err != nil {
+		c.JSON(orgErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, member, "Member role updated"))
+}
+
+// RemoveMember removes a member from the admin's organization
+func (oh *OrganizationHandler) RemoveMember(c *gin.Context) {
+	if err := oh.orgService.RemoveMember(c.GetString("orgID"), c.Param("userId")); err != nil {
+		c.JSON(orgErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, nil, "Member removed"))
+}
+
+// RotateInviteCode issues a new invite code for the admin's organization
+func (oh *OrganizationHandler) RotateInviteCode(c *gin.Context) {
+	org, err := oh.orgService.RotateInviteCode(c.GetString("orgID"))
+	if err != nil {
+		c.JSON(orgErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, org, "Invite code rotated"))
+}
+
+// CreateOrganization creates an organization with its first admin (platform admins only)
+func (oh *OrganizationHandler) CreateOrganization(c *gin.Context) {
+	var req models.CreateOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format: "+err.Error()))
+		return
+	}
+	membership, err := oh.orgService.CreateOrganization(c.GetString("userID"), &req)
+	if err != nil {
+		c.JSON(orgErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+	c.JSON(http.StatusCreated, successResponse(c, membership.Organization, "Organization created"))
+}
+
+// ListOrganizations lists every organization (platform admins only)
+func (oh *OrganizationHandler) ListOrganizations(c *gin.Context) {
+	orgs, err := oh.orgService.ListOrganizations()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, orgs, "Organizations retrieved"))
+}
+
+func orgErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrOrgNotFound),
+		errors.Is(err, services.ErrOrgInviteInvalid),
+		errors.Is(err, services.ErrOrgMemberNotFound),
+		errors.Is(err, services.ErrNotOrgMember),
+		err.Error() == "user not found":
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrOrgAlreadyMember), errors.Is(err, services.ErrOrgLastAdmin):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrReadBudgetExceeded):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
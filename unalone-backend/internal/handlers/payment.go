@@ -0,0 +1,138 @@
+// Payment handlers for hotspot ticket tiers, ticket purchases and provider webhooks
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"unalone-backend/internal/models"
+	"unalone-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PaymentHandler handles ticket and payment endpoints
+type PaymentHandler struct {
+	paymentService *services.PaymentService
+	hotspotService *services.HotspotService
+}
+
+// NewPaymentHandler creates a new payment handler
+func NewPaymentHandler(ps *services.PaymentService, hs *services.HotspotService) *PaymentHandler {
+	return &PaymentHandler{paymentService: ps, hotspotService: hs}
+}
+
+// GetTicketTiers lists a hotspot's ticket tiers with what is left, and the current user's ticket
+func (ph *PaymentHandler) GetTicketTiers(c *gin.Context) {
+	userID := c.GetString("userID")
+	hotspot, err := ph.hotspotService.GetHotspot(c.Param("id"))
+	if err != nil || !services.CanViewHotspot(hotspot, userID, c.GetString("orgID")) {
+		c.JSON(http.StatusNotFound, errorResponse(c, "Hotspot not found"))
+		return
+	}
+	tickets, err := ph.paymentService.HotspotTickets(hotspot, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, tickets, "Ticket tiers retrieved"))
+}
+
+// SetTicketTiers replaces a hotspot's ticket tiers (host only)
+func (ph *PaymentHandler) SetTicketTiers(c *gin.Context) {
+	var req models.SetTicketTiersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format: "+err.Error()))
+		return
+	}
+	hotspot, err := ph.paymentService.SetTicketTiers(c.GetString("userID"), c.Param("id"), req.Tiers)
+	if err != nil {
+		c.JSON(paymentErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, hotspot, "Ticket tiers updated"))
+}
+
+// PurchaseTicket holds a ticket and starts checkout with the payment provider
+func (ph *PaymentHandler) PurchaseTicket(c *gin.Context) {
+	var req models.PurchaseTicketRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format: "+err.Error()))
+		return
+	}
+	checkout, err := ph.paymentService.PurchaseTicket(c.Request.Context(), c.GetString("userID"), c.Param("id"), req.TierID)
+	if err != nil {
+		var audienceErr *services.AudienceError
+		if errors.As(err, &audienceErr) {
+			c.JSON(http.StatusForbidden, errorResponseWithCode(c, audienceErr.Code, audienceErr.Message))
+			return
+		}
+		c.JSON(paymentErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+	c.JSON(http.StatusCreated, successResponse(c, checkout, "Checkout started"))
+}
+
+// GetHotspotSales lists a hotspot's tickets for its host
+func (ph *PaymentHandler) GetHotspotSales(c *gin.Context) {
+	tickets, err := ph.paymentService.HotspotSales(c.GetString("userID"), c.Param("id"))
+	if err != nil {
+		c.JSON(paymentErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, tickets, "Ticket sales retrieved"))
+}
+
+// ListMyTickets lists the current user's tickets, newest first
+func (ph *PaymentHandler) ListMyTickets(c *gin.Context) {
+	tickets, err := ph.paymentService.UserTickets(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, tickets, "Tickets retrieved"))
+}
+
+// GetTicket returns one of the current user's tickets, e.g. to poll for payment confirmation
+func (ph *PaymentHandler) GetTicket(c *gin.Context) {
+	ticket, err := ph.paymentService.GetTicket(c.GetString("userID"), c.Param("id"))
+	if err != nil {
+		c.JSON(paymentErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, ticket, "Ticket retrieved"))
+}
+
+// Webhook applies a payment provider's webhook; providers authenticate with their signature header
+func (ph *PaymentHandler) Webhook(c *gin.Context) {
+	if err := ph.paymentService.HandleWebhook(c.Request.Context(), c.Param("provider"), c.Request); err != nil {
+		switch {
+		case errors.Is(err, services.ErrPaymentWebhookUnauthorized):
+			c.JSON(http.StatusForbidden, errorResponse(c, err.Error()))
+		case errors.Is(err, services.ErrPaymentUnknownProvider):
+			c.JSON(http.StatusNotFound, errorResponse(c, err.Error()))
+		default:
+			c.JSON(http.StatusBadRequest, errorResponse(c, err.Error()))
+		}
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, nil, "Webhook processed"))
+}
+
+func paymentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrTicketNotFound), errors.Is(err, services.ErrTicketTierNotFound),
+		err.Error() == "hotspot not found":
+		return http.StatusNotFound
+	case err.Error() == "only the host can change ticket tiers", err.Error() == "only the host can see ticket sales",
+		err.Error() == "this hotspot is only open to invited friends":
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrTicketsSoldOut), errors.Is(err, services.ErrTicketAlreadyOwned),
+		errors.Is(err, services.ErrTicketTierLocked):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrPaymentsUnavailable):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusBadRequest
+	}
+}
@@ -0,0 +1,52 @@
+// Places handlers for venue autocomplete
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"unalone-backend/internal/models"
+	"unalone-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PlacesHandler handles places-related endpoints
+type PlacesHandler struct {
+	placesService *services.PlacesService
+}
+
+// NewPlacesHandler creates a new places handler
+func NewPlacesHandler(ps *services.PlacesService) *PlacesHandler {
+	return &PlacesHandler{placesService: ps}
+}
+
+// Autocomplete returns venue suggestions without exposing provider keys to clients
+func (ph *PlacesHandler) Autocomplete(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
+		return
+	}
+
+	var req models.PlacesAutocompleteRequest
+
+	// Bind query parameters
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format: "+err.Error()))
+		return
+	}
+
+	response, err := ph.placesService.Autocomplete(c.Request.Context(), userID.(string), &req)
+	if err != nil {
+		if errors.Is(err, services.ErrPlacesRateLimited) {
+			c.JSON(http.StatusTooManyRequests, errorResponse(c, err.Error()))
+			return
+		}
+		c.JSON(http.StatusBadGateway, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, response, "Suggestions retrieved successfully"))
+}
@@ -0,0 +1,27 @@
+// Presence handlers
+package handlers
+
+import (
+	"net/http"
+
+	"unalone-backend/internal/models"
+	"unalone-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PresenceHandler records client heartbeats
+type PresenceHandler struct {
+	presence *services.PresenceService
+}
+
+// NewPresenceHandler creates a new presence handler
+func NewPresenceHandler(ps *services.PresenceService) *PresenceHandler {
+	return &PresenceHandler{presence: ps}
+}
+
+// Heartbeat marks the user as online; clients without the events WebSocket call it every minute
+func (ph *PresenceHandler) Heartbeat(c *gin.Context) {
+	ph.presence.Touch(c.GetString("userID"))
+	c.JSON(http.StatusOK, successResponse(c, models.PresenceHeartbeat{IntervalSeconds: int(services.PresenceHeartbeatInterval.Seconds())}, "Heartbeat recorded"))
+}
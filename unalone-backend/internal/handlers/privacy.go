@@ -0,0 +1,84 @@
+// Privacy handlers for the published data handling policy and user consents
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"unalone-backend/internal/models"
+	"unalone-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PrivacyHandler handles privacy endpoints
+type PrivacyHandler struct {
+	retention      *services.RetentionPolicy
+	consentService *services.ConsentService
+}
+
+// NewPrivacyHandler creates a new privacy handler
+func NewPrivacyHandler(rp *services.RetentionPolicy, cs *services.ConsentService) *PrivacyHandler {
+	return &PrivacyHandler{retention: rp, consentService: cs}
+}
+
+// GetPolicy returns how long each kind of personal data is kept
+func (ph *PrivacyHandler) GetPolicy(c *gin.Context) {
+	c.JSON(http.StatusOK, successResponse(c, ph.retention.Describe(), "Privacy policy retrieved"))
+}
+
+// ListConsents returns the current user's standing for every consent purpose
+func (ph *PrivacyHandler) ListConsents(c *gin.Context) {
+	states, err := ph.consentService.ListConsents(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, states, "Consents retrieved"))
+}
+
+// ConsentHistory returns every grant and revocation of the current user, newest first
+func (ph *PrivacyHandler) ConsentHistory(c *gin.Context) {
+	records, err := ph.consentService.History(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, records, "Consent history retrieved"))
+}
+
+// GrantConsent records the current user's consent to a purpose at the version they were shown
+func (ph *PrivacyHandler) GrantConsent(c *gin.Context) {
+	var req models.GrantConsentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format: "+err.Error()))
+		return
+	}
+	state, err := ph.consentService.Grant(c.GetString("userID"), c.Param("purpose"), req.Version, models.ConsentSourceAPI)
+	if err != nil {
+		c.JSON(consentErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, state, "Consent granted"))
+}
+
+// RevokeConsent withdraws the current user's consent to a purpose
+func (ph *PrivacyHandler) RevokeConsent(c *gin.Context) {
+	state, err := ph.consentService.Revoke(c.GetString("userID"), c.Param("purpose"), models.ConsentSourceAPI)
+	if err != nil {
+		c.JSON(consentErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, state, "Consent revoked"))
+}
+
+func consentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrConsentPurposeNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrConsentVersionOutdated):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -2,6 +2,8 @@
 package handlers
 
 import (
+	"errors"
+	"log"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -13,13 +15,19 @@ import (
 type ProfileHandler struct {
 	profileService           *services.ProfileService
 	phoneVerificationService *services.PhoneVerificationService
+	hostVerificationService  *services.HostVerificationService
+	userLocationService      *services.UserLocationService
+	onboardingService        *services.OnboardingService
 }
 
 // NewProfileHandler creates a new profile handler
-func NewProfileHandler(ps *services.ProfileService, pvs *services.PhoneVerificationService) *ProfileHandler {
+func NewProfileHandler(ps *services.ProfileService, pvs *services.PhoneVerificationService, hvs *services.HostVerificationService, uls *services.UserLocationService, obs *services.OnboardingService) *ProfileHandler {
 	return &ProfileHandler{
 		profileService:           ps,
 		phoneVerificationService: pvs,
+		hostVerificationService:  hvs,
+		userLocationService:      uls,
+		onboardingService:        obs,
 	}
 }
 
@@ -28,7 +36,7 @@ func (ph *ProfileHandler) UpdateProfile(c *gin.Context) {
 	// Get user ID from context (set by auth middleware)
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, models.ErrorResponseWithMessage("Unauthorized"))
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
 		return
 	}
 
@@ -36,18 +44,31 @@ func (ph *ProfileHandler) UpdateProfile(c *gin.Context) {
 
 	// Bind JSON request
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage("Invalid request format"))
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format"))
 		return
 	}
 
 	// Update profile
 	user, err := ph.profileService.UpdateProfile(userID.(string), &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage(err.Error()))
+		var cooldown *services.NicknameCooldownError
+		status := http.StatusBadRequest
+		switch {
+		case errors.Is(err, services.ErrDateOfBirthLocked):
+			status = http.StatusForbidden
+		case errors.Is(err, services.ErrNicknameReserved):
+			status = http.StatusConflict
+		case errors.As(err, &cooldown):
+			status = http.StatusTooManyRequests
+		}
+		c.JSON(status, errorResponse(c, err.Error()))
 		return
 	}
 
-	c.JSON(http.StatusOK, models.SuccessResponse(user, "Profile updated successfully"))
+	ph.refreshOnboarding(userID.(string))
+	user.Completeness = services.ComputeProfileCompleteness(user)
+
+	c.JSON(http.StatusOK, successResponse(c, user, "Profile updated successfully"))
 }
 
 // SendPhoneVerification sends phone verification code
@@ -55,7 +76,7 @@ func (ph *ProfileHandler) SendPhoneVerification(c *gin.Context) {
 	// Get user ID from context
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, models.ErrorResponseWithMessage("Unauthorized"))
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
 		return
 	}
 
@@ -63,18 +84,30 @@ func (ph *ProfileHandler) SendPhoneVerification(c *gin.Context) {
 
 	// Bind JSON request
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage("Invalid request format"))
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format"))
 		return
 	}
 
 	// Send verification code
-	err := ph.phoneVerificationService.SendVerificationCode(userID.(string), req.PhoneNumber)
+	err := ph.phoneVerificationService.SendVerificationCode(c.Request.Context(), userID.(string), req.PhoneNumber)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponseWithMessage(err.Error()))
+		if errors.Is(err, services.ErrSMSRateLimited) {
+			c.JSON(http.StatusTooManyRequests, errorResponse(c, err.Error()))
+			return
+		}
+		if errors.Is(err, services.ErrSMSBudgetExhausted) {
+			c.JSON(http.StatusServiceUnavailable, errorResponse(c, err.Error()))
+			return
+		}
+		if errors.Is(err, services.ErrInvalidPhoneNumber) {
+			c.JSON(http.StatusBadRequest, errorResponse(c, err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
 		return
 	}
 
-	c.JSON(http.StatusOK, models.SuccessResponse(nil, "Verification code sent successfully"))
+	c.JSON(http.StatusOK, successResponse(c, nil, "Verification code sent successfully"))
 }
 
 // VerifyPhone verifies phone number with code
@@ -82,7 +115,7 @@ func (ph *ProfileHandler) VerifyPhone(c *gin.Context) {
 	// Get user ID from context
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, models.ErrorResponseWithMessage("Unauthorized"))
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
 		return
 	}
 
@@ -90,18 +123,128 @@ func (ph *ProfileHandler) VerifyPhone(c *gin.Context) {
 
 	// Bind JSON request
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage("Invalid request format"))
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format"))
 		return
 	}
 
 	// Verify phone code
 	err := ph.phoneVerificationService.VerifyPhoneCode(userID.(string), req.PhoneNumber, req.Code)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage(err.Error()))
+		c.JSON(http.StatusBadRequest, errorResponse(c, err.Error()))
+		return
+	}
+
+	ph.refreshOnboarding(userID.(string))
+
+	c.JSON(http.StatusOK, successResponse(c, nil, "Phone number verified successfully"))
+}
+
+// CorrectDateOfBirth changes a user's locked date of birth through the support flow (admin)
+func (ph *ProfileHandler) CorrectDateOfBirth(c *gin.Context) {
+	var req models.CorrectDateOfBirthRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format: "+err.Error()))
+		return
+	}
+
+	user, err := ph.profileService.CorrectDateOfBirth(c.Param("id"), &req, c.GetString("userID"))
+	if err != nil {
+		status := http.StatusBadRequest
+		if err.Error() == "user not found" {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, user, "Date of birth corrected successfully"))
+}
+
+// GetNickname returns the current user's nickname history and when they may change it next
+func (ph *ProfileHandler) GetNickname(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
+		return
+	}
+
+	status, err := ph.profileService.GetNicknameStatus(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, status, "Nickname history retrieved successfully"))
+}
+
+// SetAvailability sets whether the current user is available to meet, busy or invisible
+func (ph *ProfileHandler) SetAvailability(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
+		return
+	}
+
+	var req models.SetAvailabilityRequest
+
+	// Bind JSON request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format: "+err.Error()))
+		return
+	}
+
+	availability, err := ph.profileService.SetAvailability(userID.(string), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
 		return
 	}
 
-	c.JSON(http.StatusOK, models.SuccessResponse(nil, "Phone number verified successfully"))
+	c.JSON(http.StatusOK, successResponse(c, availability, "Availability updated successfully"))
+}
+
+// GetOnboarding returns the current user's onboarding checklist, rewarding any steps completed since the last check
+func (ph *ProfileHandler) GetOnboarding(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
+		return
+	}
+
+	progress, err := ph.onboardingService.Refresh(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, progress, "Onboarding progress retrieved successfully"))
+}
+
+// refreshOnboarding completes onboarding steps satisfied by a profile change (best-effort)
+func (ph *ProfileHandler) refreshOnboarding(userID string) {
+	if _, err := ph.onboardingService.Refresh(userID); err != nil {
+		log.Printf("[onboarding] refresh %s: %v", userID, err)
+	}
+}
+
+// GetHostVerification returns the current user's progress toward the verified host badge
+func (ph *ProfileHandler) GetHostVerification(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
+		return
+	}
+
+	verification, err := ph.hostVerificationService.GetVerification(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, verification, "Host verification retrieved successfully"))
 }
 
 // UpdateProfileImage updates user's profile image
@@ -109,35 +252,33 @@ func (ph *ProfileHandler) UpdateProfileImage(c *gin.Context) {
 	// Get user ID from context
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, models.ErrorResponseWithMessage("Unauthorized"))
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
 		return
 	}
 
 	// TODO: Implement image upload to cloud storage
 	// For now, we'll accept an image URL
-	type ImageUpdateRequest struct {
-		ImageURL string `json:"image_url" binding:"required,url"`
-	}
-
-	var req ImageUpdateRequest
+	var req models.UpdateProfileImageRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage("Invalid request format"))
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format"))
 		return
 	}
 
 	// Update profile image
 	_, err := ph.profileService.UpdateProfileImage(userID.(string), req.ImageURL)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponseWithMessage(err.Error()))
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
 		return
 	}
 
+	ph.refreshOnboarding(userID.(string))
+
 	response := models.ProfileImageUploadResponse{
 		ImageURL: req.ImageURL,
 		Message:  "Profile image updated successfully",
 	}
 
-	c.JSON(http.StatusOK, models.SuccessResponse(response, "Profile image updated successfully"))
+	c.JSON(http.StatusOK, successResponse(c, response, "Profile image updated successfully"))
 }
 
 // GetSettings retrieves user settings
@@ -145,18 +286,18 @@ func (ph *ProfileHandler) GetSettings(c *gin.Context) {
 	// Get user ID from context
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, models.ErrorResponseWithMessage("Unauthorized"))
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
 		return
 	}
 
 	// Get settings
 	settings, err := ph.profileService.GetUserSettings(userID.(string))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponseWithMessage(err.Error()))
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
 		return
 	}
 
-	c.JSON(http.StatusOK, models.SuccessResponse(settings, "Settings retrieved successfully"))
+	c.JSON(http.StatusOK, successResponse(c, settings, "Settings retrieved successfully"))
 }
 
 // UpdateSettings updates user settings
@@ -164,7 +305,7 @@ func (ph *ProfileHandler) UpdateSettings(c *gin.Context) {
 	// Get user ID from context
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, models.ErrorResponseWithMessage("Unauthorized"))
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
 		return
 	}
 
@@ -172,18 +313,25 @@ func (ph *ProfileHandler) UpdateSettings(c *gin.Context) {
 
 	// Bind JSON request
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage("Invalid request format"))
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format"))
 		return
 	}
 
 	// Update settings
 	settings, err := ph.profileService.UpdateUserSettings(userID.(string), &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage(err.Error()))
+		c.JSON(http.StatusBadRequest, errorResponse(c, err.Error()))
 		return
 	}
 
-	c.JSON(http.StatusOK, models.SuccessResponse(settings, "Settings updated successfully"))
+	// Turning sharing off also forgets the last reported location
+	if !settings.LocationSharing && ph.userLocationService != nil {
+		if err := ph.userLocationService.ClearLocation(userID.(string)); err != nil {
+			log.Printf("Failed to clear location for user %s: %v", userID, err)
+		}
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, settings, "Settings updated successfully"))
 }
 
 // BlockUser blocks a user
@@ -191,7 +339,7 @@ func (ph *ProfileHandler) BlockUser(c *gin.Context) {
 	// Get user ID from context
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, models.ErrorResponseWithMessage("Unauthorized"))
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
 		return
 	}
 
@@ -199,18 +347,18 @@ func (ph *ProfileHandler) BlockUser(c *gin.Context) {
 
 	// Bind JSON request
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage("Invalid request format"))
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format"))
 		return
 	}
 
 	// Block user
 	err := ph.profileService.BlockUser(userID.(string), req.UserID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage(err.Error()))
+		c.JSON(http.StatusBadRequest, errorResponse(c, err.Error()))
 		return
 	}
 
-	c.JSON(http.StatusOK, models.SuccessResponse(nil, "User blocked successfully"))
+	c.JSON(http.StatusOK, successResponse(c, nil, "User blocked successfully"))
 }
 
 // UnblockUser unblocks a user
@@ -218,7 +366,7 @@ func (ph *ProfileHandler) UnblockUser(c *gin.Context) {
 	// Get user ID from context
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, models.ErrorResponseWithMessage("Unauthorized"))
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
 		return
 	}
 
@@ -226,18 +374,18 @@ func (ph *ProfileHandler) UnblockUser(c *gin.Context) {
 
 	// Bind JSON request
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage("Invalid request format"))
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format"))
 		return
 	}
 
 	// Unblock user
 	err := ph.profileService.UnblockUser(userID.(string), req.UserID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage(err.Error()))
+		c.JSON(http.StatusBadRequest, errorResponse(c, err.Error()))
 		return
 	}
 
-	c.JSON(http.StatusOK, models.SuccessResponse(nil, "User unblocked successfully"))
+	c.JSON(http.StatusOK, successResponse(c, nil, "User unblocked successfully"))
 }
 
 // ReportUser reports a user
@@ -245,7 +393,7 @@ func (ph *ProfileHandler) ReportUser(c *gin.Context) {
 	// Get user ID from context
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, models.ErrorResponseWithMessage("Unauthorized"))
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
 		return
 	}
 
@@ -253,16 +401,16 @@ func (ph *ProfileHandler) ReportUser(c *gin.Context) {
 
 	// Bind JSON request
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage("Invalid request format"))
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format"))
 		return
 	}
 
 	// Report user
 	err := ph.profileService.ReportUser(userID.(string), &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage(err.Error()))
+		c.JSON(http.StatusBadRequest, errorResponse(c, err.Error()))
 		return
 	}
 
-	c.JSON(http.StatusOK, models.SuccessResponse(nil, "User reported successfully"))
+	c.JSON(http.StatusOK, successResponse(c, nil, "User reported successfully"))
 }
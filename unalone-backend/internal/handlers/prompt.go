@@ -0,0 +1,74 @@
+// Prompt template handlers for the AI assistant (admin)
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"unalone-backend/internal/models"
+	"unalone-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PromptHandler serves AI prompt versions to admins
+type PromptHandler struct {
+	promptService *services.PromptService
+}
+
+// NewPromptHandler creates a new prompt handler
+func NewPromptHandler(ps *services.PromptService) *PromptHandler {
+	return &PromptHandler{promptService: ps}
+}
+
+// ListPrompts returns each prompt with its current version and experiment overrides (admin)
+func (ph *PromptHandler) ListPrompts(c *gin.Context) {
+	prompts, err := ph.promptService.ListPrompts()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, prompts, "Prompts retrieved successfully"))
+}
+
+// ListVersions returns every published version of a prompt, newest first (admin)
+func (ph *PromptHandler) ListVersions(c *gin.Context) {
+	versions, err := ph.promptService.ListVersions(c.Param("key"))
+	if err != nil {
+		c.JSON(promptErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, versions, "Prompt versions retrieved successfully"))
+}
+
+// PublishVersion publishes a new version of a prompt, optionally only for an experiment variant (admin)
+func (ph *PromptHandler) PublishVersion(c *gin.Context) {
+	var req models.PublishPromptRequest
+
+	// Bind JSON request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format: "+err.Error()))
+		return
+	}
+
+	template, err := ph.promptService.PublishVersion(c.Param("key"), c.GetString("userID"), &req)
+	if err != nil {
+		c.JSON(promptErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, successResponse(c, template, "Prompt version published successfully"))
+}
+
+func promptErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrPromptNotFound), errors.Is(err, services.ErrExperimentNotFound):
+		return http.StatusNotFound
+	case err.Error() == "firestore implementation needed":
+		return http.StatusInternalServerError
+	default:
+		return http.StatusBadRequest
+	}
+}
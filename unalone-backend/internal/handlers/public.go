@@ -0,0 +1,83 @@
+// Public handlers: read-only hotspot browsing without an account
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"unalone-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// publicCacheControl lets browsers and CDNs in front of the website reuse responses briefly
+	publicCacheControl = "public, max-age=60"
+	publicMaxPageSize  = 50
+)
+
+// PublicHandler serves redacted hotspots to the website and app preview
+type PublicHandler struct {
+	hotspotService *services.HotspotService
+}
+
+// NewPublicHandler creates a new public handler
+func NewPublicHandler(hs *services.HotspotService) *PublicHandler {
+	return &PublicHandler{hotspotService: hs}
+}
+
+// GetNearbyHotspots lists public hotspots around latitude and longitude within radius km (default 5, at most 25)
+func (ph *PublicHandler) GetNearbyHotspots(c *gin.Context) {
+	lat, err := strconv.ParseFloat(c.Query("latitude"), 64)
+	if err != nil || lat < -90 || lat > 90 {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid latitude"))
+		return
+	}
+	lon, err := strconv.ParseFloat(c.Query("longitude"), 64)
+	if err != nil || lon < -180 || lon > 180 {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid longitude"))
+		return
+	}
+	radius := 5.0
+	if radiusStr := c.Query("radius"); radiusStr != "" {
+		r, err := strconv.ParseFloat(radiusStr, 64)
+		if err != nil || r <= 0 {
+			c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid radius"))
+			return
+		}
+		radius = r
+	}
+	limit, offset := parsePagination(c)
+
+	list, err := ph.hotspotService.PublicNearbyHotspots(lat, lon, radius, min(limit, publicMaxPageSize), offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+
+	writeCacheable(c, successResponse(c, list, "Nearby hotspots retrieved successfully"), publicCacheControl)
+}
+
+// GetHotspotsByCity lists a city's public hotspots, soonest first (optional country)
+func (ph *PublicHandler) GetHotspotsByCity(c *gin.Context) {
+	limit, offset := parsePagination(c)
+
+	list, err := ph.hotspotService.PublicCityHotspots(c.Param("city"), c.Query("country"), min(limit, publicMaxPageSize), offset)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, err.Error()))
+		return
+	}
+
+	writeCacheable(c, successResponse(c, list, "Hotspots retrieved successfully"), publicCacheControl)
+}
+
+// GetHotspot returns one public hotspot; private, draft and ended hotspots are not found
+func (ph *PublicHandler) GetHotspot(c *gin.Context) {
+	hotspot, err := ph.hotspotService.GetPublicHotspot(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, errorResponse(c, "Hotspot not found"))
+		return
+	}
+
+	writeCacheable(c, successResponse(c, hotspot, "Hotspot retrieved successfully"), publicCacheControl)
+}
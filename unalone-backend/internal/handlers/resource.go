@@ -0,0 +1,86 @@
+// Resource handlers for the wellbeing resources directory
+package handlers
+
+import (
+	"net/http"
+
+	"unalone-backend/internal/models"
+	"unalone-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ResourceHandler handles wellbeing resource endpoints
+type ResourceHandler struct {
+	resourceService *services.ResourceService
+}
+
+// NewResourceHandler creates a new resource handler
+func NewResourceHandler(rs *services.ResourceService) *ResourceHandler {
+	return &ResourceHandler{resourceService: rs}
+}
+
+// GetResources lists the helplines, counseling centers and NGOs that apply at latitude and longitude
+func (rh *ResourceHandler) GetResources(c *gin.Context) {
+	lat, lon, ok := parseLocationQuery(c)
+	if !ok {
+		return
+	}
+	if lat < -90 || lat > 90 {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid latitude"))
+		return
+	}
+	if lon < -180 || lon > 180 {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid longitude"))
+		return
+	}
+
+	directory, err := rh.resourceService.ResourcesNear(lat, lon)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, directory, "Resources retrieved successfully"))
+}
+
+// UpsertResource creates or updates a wellbeing resource (admin only)
+func (rh *ResourceHandler) UpsertResource(c *gin.Context) {
+	resourceID := c.Param("id")
+	if resourceID == "" {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Resource ID is required"))
+		return
+	}
+
+	var req models.UpsertResourceRequest
+
+	// Bind JSON request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format: "+err.Error()))
+		return
+	}
+
+	resource, err := rh.resourceService.UpsertResource(resourceID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, resource, "Resource saved successfully"))
+}
+
+// DeactivateResource hides a wellbeing resource from the directory (admin only)
+func (rh *ResourceHandler) DeactivateResource(c *gin.Context) {
+	resourceID := c.Param("id")
+	if resourceID == "" {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Resource ID is required"))
+		return
+	}
+
+	if err := rh.resourceService.DeactivateResource(resourceID); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, nil, "Resource deactivated successfully"))
+}
@@ -0,0 +1,24 @@
+// Response helpers that translate messages to the request language
+package handlers
+
+import (
+	"unalone-backend/internal/i18n"
+	"unalone-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// successResponse builds a success response with a translated message
+func successResponse(c *gin.Context, data interface{}, message string) models.APIResponse {
+	return models.SuccessResponse(data, i18n.Translate(i18n.FromContext(c.Request.Context()), message))
+}
+
+// errorResponse builds an error response with a translated message
+func errorResponse(c *gin.Context, message string) models.ErrorResponse {
+	return models.ErrorResponseWithMessage(i18n.Translate(i18n.FromContext(c.Request.Context()), message))
+}
+
+// errorResponseWithCode builds an error response with a code and a translated message
+func errorResponseWithCode(c *gin.Context, code, message string) models.ErrorResponse {
+	return models.ErrorResponseWithCode(code, i18n.Translate(i18n.FromContext(c.Request.Context()), message))
+}
@@ -0,0 +1,283 @@
+// Safety handlers for trusted contacts, check-ins and safety timers
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"unalone-backend/internal/models"
+	"unalone-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SafetyHandler handles trusted contact and safety timer endpoints
+type SafetyHandler struct {
+	safetyService  *services.SafetyService
+	hotspotService *services.HotspotService
+	analytics      *services.AnalyticsService
+	checkInQR      *services.CheckInQRService
+}
+
+// NewSafetyHandler creates a new safety handler
+func NewSafetyHandler(ss *services.SafetyService, hs *services.HotspotService, an *services.AnalyticsService, qrs *services.CheckInQRService) *SafetyHandler {
+	return &SafetyHandler{safetyService: ss, hotspotService: hs, analytics: an, checkInQR: qrs}
+}
+
+// ListTrustedContacts returns the current user's trusted contacts
+func (sh *SafetyHandler) ListTrustedContacts(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
+		return
+	}
+
+	contacts, err := sh.safetyService.ListTrustedContacts(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, contacts, "Trusted contacts retrieved successfully"))
+}
+
+// AddTrustedContact nominates a trusted contact
+func (sh *SafetyHandler) AddTrustedContact(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
+		return
+	}
+
+	var req models.AddTrustedContactRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format: "+err.Error()))
+		return
+	}
+
+	contact, err := sh.safetyService.AddTrustedContact(userID.(string), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, successResponse(c, contact, "Trusted contact added successfully"))
+}
+
+// RemoveTrustedContact deletes one of the current user's trusted contacts
+func (sh *SafetyHandler) RemoveTrustedContact(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
+		return
+	}
+
+	contactID := c.Param("id")
+	if contactID == "" {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Contact ID is required"))
+		return
+	}
+
+	if err := sh.safetyService.RemoveTrustedContact(userID.(string), contactID); err != nil {
+		c.JSON(http.StatusNotFound, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, nil, "Trusted contact removed successfully"))
+}
+
+// CheckIn checks the current user in at a hotspot and optionally starts a safety timer
+func (sh *SafetyHandler) CheckIn(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
+		return
+	}
+
+	hotspotID := c.Param("id")
+	if hotspotID == "" {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Hotspot ID is required"))
+		return
+	}
+
+	var req models.CheckInRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format: "+err.Error()))
+		return
+	}
+
+	// Fail before checking in so a rejected timer does not leave a half-done check-in
+	if req.SafetyTimerMinutes > 0 {
+		if err := sh.safetyService.CanStartTimer(userID.(string)); err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse(c, err.Error()))
+			return
+		}
+	}
+
+	hotspot, first, err := sh.hotspotService.CheckIn(userID.(string), hotspotID, req.Latitude, req.Longitude)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, err.Error()))
+		return
+	}
+	// Count the check-in toward the host's RSVP funnel, which leaves out the host (best-effort)
+	if first && sh.analytics != nil && hotspot.CreatedBy != userID.(string) {
+		sh.analytics.RecordCheckIn(hotspotID, userID.(string))
+	}
+
+	response := &models.CheckInResponse{HotspotID: hotspot.ID, CheckedInAt: services.Now()}
+	if req.SafetyTimerMinutes > 0 {
+		timer, err := sh.safetyService.StartTimer(userID.(string), hotspot, time.Duration(req.SafetyTimerMinutes)*time.Minute)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse(c, err.Error()))
+			return
+		}
+		response.SafetyTimer = timer
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, response, "Checked in successfully"))
+}
+
+// GetCheckInQR returns the rotating check-in code for the host to display (host or co-host only)
+func (sh *SafetyHandler) GetCheckInQR(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
+		return
+	}
+
+	code, err := sh.checkInQR.CurrentCode(userID.(string), c.Param("id"))
+	if err != nil {
+		c.JSON(checkInQRErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+
+	// The code rotates, so clients must not reuse a cached copy
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, successResponse(c, code, "Check-in code retrieved successfully"))
+}
+
+// CheckInQR checks the current user in with a code scanned from the host's screen
+func (sh *SafetyHandler) CheckInQR(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
+		return
+	}
+
+	var req models.QRCheckInRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format: "+err.Error()))
+		return
+	}
+
+	hotspotID := c.Param("id")
+	hotspot, first, _, err := sh.checkInQR.CheckIn(userID.(string), hotspotID, req.Token)
+	if err != nil {
+		c.JSON(checkInQRErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+	// Count the check-in toward the host's RSVP funnel (best-effort); hosts cannot scan their own code
+	if first && sh.analytics != nil {
+		sh.analytics.RecordCheckIn(hotspotID, userID.(string))
+	}
+
+	response := &models.CheckInResponse{HotspotID: hotspot.ID, CheckedInAt: services.Now()}
+	c.JSON(http.StatusOK, successResponse(c, response, "Checked in successfully"))
+}
+
+func checkInQRErrorStatus(err error) int {
+	switch {
+	case err.Error() == "hotspot not found":
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrCheckInCodeHostOnly):
+		return http.StatusForbidden
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// ListTimers returns the current user's running and alerted safety timers
+func (sh *SafetyHandler) ListTimers(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
+		return
+	}
+
+	timers, err := sh.safetyService.ListTimers(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, timers, "Safety timers retrieved successfully"))
+}
+
+// ConfirmSafe stops a safety timer with "I'm safe"
+func (sh *SafetyHandler) ConfirmSafe(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
+		return
+	}
+
+	timerID := c.Param("id")
+	if timerID == "" {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Timer ID is required"))
+		return
+	}
+
+	timer, err := sh.safetyService.ConfirmSafe(userID.(string), timerID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, timer, "Marked as safe"))
+}
+
+// RaiseSOS sends an emergency alert from inside a hotspot. It is plain REST so it
+// works when the chat WebSocket is disconnected.
+func (sh *SafetyHandler) RaiseSOS(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
+		return
+	}
+
+	var req models.SOSRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format: "+err.Error()))
+		return
+	}
+
+	alert, err := sh.safetyService.RaiseSOS(userID.(string), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, successResponse(c, alert, "SOS sent"))
+}
+
+// ListSOSAlerts returns recent SOS alerts for moderators
+func (sh *SafetyHandler) ListSOSAlerts(c *gin.Context) {
+	limit, offset := parsePagination(c)
+	alerts, err := sh.safetyService.ListSOSAlerts(limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, alerts, "SOS alerts retrieved successfully"))
+}
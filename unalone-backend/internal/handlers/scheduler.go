@@ -0,0 +1,69 @@
+// Scheduler handlers for maintenance job and job queue monitoring
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"unalone-backend/internal/jobs"
+	"unalone-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SchedulerHandler exposes maintenance job metrics and the background job queue to admins
+type SchedulerHandler struct {
+	scheduler *services.Scheduler
+	queue     *jobs.Queue
+}
+
+// NewSchedulerHandler creates a new scheduler handler
+func NewSchedulerHandler(s *services.Scheduler, q *jobs.Queue) *SchedulerHandler {
+	return &SchedulerHandler{scheduler: s, queue: q}
+}
+
+// GetStatus returns whether this instance is the leader and how each job has been running
+func (sh *SchedulerHandler) GetStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, successResponse(c, sh.scheduler.Status(), "Scheduled jobs retrieved successfully"))
+}
+
+// GetQueueStats returns how many queued jobs are waiting, running and dead, and how each job
+// type has run on this instance
+func (sh *SchedulerHandler) GetQueueStats(c *gin.Context) {
+	stats, err := sh.queue.Stats(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, stats, "Job queue retrieved"))
+}
+
+// ListDeadJobs returns dead-lettered jobs, newest first (limit, default 50 and at most 200)
+func (sh *SchedulerHandler) ListDeadJobs(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 200 {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid limit"))
+		return
+	}
+	dead, err := sh.queue.DeadLetters(c.Request.Context(), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, dead, "Dead jobs retrieved"))
+}
+
+// RetryDeadJob queues a dead-lettered job again with its attempts reset
+func (sh *SchedulerHandler) RetryDeadJob(c *gin.Context) {
+	job, err := sh.queue.Retry(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, jobs.ErrNotFound) {
+			c.JSON(http.StatusNotFound, errorResponse(c, err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, job, "Job queued again"))
+}
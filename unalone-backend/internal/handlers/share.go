@@ -0,0 +1,102 @@
+// Share link handlers for hotspot and profile deep links
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"unalone-backend/internal/models"
+	"unalone-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ShareHandler creates, resolves and revokes share links
+type ShareHandler struct {
+	shareLinks *services.ShareLinkService
+}
+
+// NewShareHandler creates a new share handler
+func NewShareHandler(sls *services.ShareLinkService) *ShareHandler {
+	return &ShareHandler{shareLinks: sls}
+}
+
+// ShareHotspot returns a short link to a hotspot; the body is optional
+func (sh *ShareHandler) ShareHotspot(c *gin.Context) {
+	req, ok := bindShareRequest(c)
+	if !ok {
+		return
+	}
+
+	link, err := sh.shareLinks.ShareHotspot(c.GetString("userID"), c.Param("id"), req)
+	if err != nil {
+		c.JSON(shareErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, successResponse(c, link, "Share link created successfully"))
+}
+
+// ShareProfile returns a short link to the user's own profile; the body is optional
+func (sh *ShareHandler) ShareProfile(c *gin.Context) {
+	req, ok := bindShareRequest(c)
+	if !ok {
+		return
+	}
+
+	link, err := sh.shareLinks.ShareProfile(c.GetString("userID"), req)
+	if err != nil {
+		c.JSON(shareErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, successResponse(c, link, "Share link created successfully"))
+}
+
+// ResolveShareLink returns the hotspot or profile behind a link and whether the user can join
+func (sh *ShareHandler) ResolveShareLink(c *gin.Context) {
+	preview, err := sh.shareLinks.Resolve(c.GetString("userID"), c.Param("code"))
+	if err != nil {
+		c.JSON(shareErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, preview, "Share link resolved successfully"))
+}
+
+// RevokeShareLink disables a link the user created
+func (sh *ShareHandler) RevokeShareLink(c *gin.Context) {
+	if err := sh.shareLinks.Revoke(c.GetString("userID"), c.Param("code")); err != nil {
+		c.JSON(shareErrorStatus(err), errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, nil, "Share link revoked successfully"))
+}
+
+// bindShareRequest reads the optional share options, answering 400 when they are invalid
+func bindShareRequest(c *gin.Context) (*models.CreateShareLinkRequest, bool) {
+	var req models.CreateShareLinkRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format: "+err.Error()))
+			return nil, false
+		}
+	}
+	return &req, true
+}
+
+func shareErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrShareLinkNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrShareLinkExpired):
+		return http.StatusGone
+	case errors.Is(err, services.ErrShareNotAllowed):
+		return http.StatusForbidden
+	case err.Error() == "hotspot not found":
+		return http.StatusNotFound
+	default:
+		return http.StatusBadRequest
+	}
+}
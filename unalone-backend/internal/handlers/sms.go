@@ -0,0 +1,39 @@
+// SMS handlers for provider delivery callbacks
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"unalone-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SMSHandler handles SMS provider webhooks
+type SMSHandler struct {
+	smsGateway *services.SMSGateway
+}
+
+// NewSMSHandler creates a new SMS handler
+func NewSMSHandler(gw *services.SMSGateway) *SMSHandler {
+	return &SMSHandler{smsGateway: gw}
+}
+
+// StatusCallback records a delivery report; providers authenticate with the callback token in the URL
+func (sh *SMSHandler) StatusCallback(c *gin.Context) {
+	err := sh.smsGateway.HandleStatusCallback(c.Param("provider"), c.Query("token"), c.Request)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrSMSCallbackUnauthorized):
+			c.JSON(http.StatusForbidden, errorResponse(c, err.Error()))
+		case errors.Is(err, services.ErrSMSUnknownProvider):
+			c.JSON(http.StatusNotFound, errorResponse(c, err.Error()))
+		default:
+			c.JSON(http.StatusBadRequest, errorResponse(c, err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, nil, "Delivery status recorded"))
+}
@@ -0,0 +1,40 @@
+// Tag handlers for popular tags and autocomplete
+package handlers
+
+import (
+	"net/http"
+
+	"unalone-backend/internal/models"
+	"unalone-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TagHandler handles tag endpoints
+type TagHandler struct {
+	tagService *services.TagService
+}
+
+// NewTagHandler creates a new tag handler
+func NewTagHandler(ts *services.TagService) *TagHandler {
+	return &TagHandler{tagService: ts}
+}
+
+// GetPopularTags returns the most used tags, optionally for a city and prefix
+func (th *TagHandler) GetPopularTags(c *gin.Context) {
+	var req models.PopularTagsRequest
+
+	// Bind query parameters
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format: "+err.Error()))
+		return
+	}
+
+	tags, err := th.tagService.GetPopularTags(&req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, tags, "Popular tags retrieved successfully"))
+}
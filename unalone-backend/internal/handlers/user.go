@@ -2,6 +2,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -11,33 +12,97 @@ import (
 
 // UserHandler handles user-related endpoints
 type UserHandler struct {
-	userService *services.UserService
+	userService        *services.UserService
+	profileViewService *services.ProfileViewService
+	locationService    *services.UserLocationService
 }
 
 // NewUserHandler creates a new user handler
-func NewUserHandler(userService *services.UserService) *UserHandler {
+func NewUserHandler(userService *services.UserService, profileViewService *services.ProfileViewService, locationService *services.UserLocationService) *UserHandler {
 	return &UserHandler{
-		userService: userService,
+		userService:        userService,
+		profileViewService: profileViewService,
+		locationService:    locationService,
 	}
 }
 
+// UpdateLocation stores the current user's last known location
+func (uh *UserHandler) UpdateLocation(c *gin.Context) {
+	var req models.UpdateLocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format: "+err.Error()))
+		return
+	}
+
+	user, err := uh.locationService.UpdateLocation(c.GetString("userID"), &req)
+	if err != nil {
+		if errors.Is(err, services.ErrLocationSharingDisabled) || errors.Is(err, services.ErrLocationConsentRequired) {
+			c.JSON(http.StatusForbidden, errorResponse(c, err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, user.Location, "Location updated successfully"))
+}
+
+// ClearLocation forgets the current user's stored location
+func (uh *UserHandler) ClearLocation(c *gin.Context) {
+	if err := uh.locationService.ClearLocation(c.GetString("userID")); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, nil, "Location cleared successfully"))
+}
+
 // GetProfile returns the current user's profile
 func (uh *UserHandler) GetProfile(c *gin.Context) {
 	// Get user ID from context (set by auth middleware)
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, models.ErrorResponseWithMessage("Unauthorized"))
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
 		return
 	}
 
 	// Get user from database
 	user, err := uh.userService.GetUserByID(userID.(string))
 	if err != nil {
-		c.JSON(http.StatusNotFound, models.ErrorResponseWithMessage("User not found"))
+		c.JSON(http.StatusNotFound, errorResponse(c, "User not found"))
+		return
+	}
+	user.Completeness = services.ComputeProfileCompleteness(user)
+
+	c.JSON(http.StatusOK, successResponse(c, user, "Profile retrieved successfully"))
+}
+
+// GetUserProfile returns another user's public profile with mutual friends and shared hotspots
+func (uh *UserHandler) GetUserProfile(c *gin.Context) {
+	// Get user ID from context (set by auth middleware)
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
+		return
+	}
+
+	targetID := c.Param("id")
+	if targetID == "" {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "User ID is required"))
+		return
+	}
+
+	view, err := uh.profileViewService.GetProfileView(userID.(string), targetID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			c.JSON(http.StatusNotFound, errorResponse(c, "User not found"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
 		return
 	}
 
-	c.JSON(http.StatusOK, models.SuccessResponse(user, "Profile retrieved successfully"))
+	c.JSON(http.StatusOK, successResponse(c, view, "Profile retrieved successfully"))
 }
 
 // UpdateProfile updates the current user's profile
@@ -45,7 +110,7 @@ func (uh *UserHandler) UpdateProfile(c *gin.Context) {
 	// Get user ID from context (set by auth middleware)
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, models.ErrorResponseWithMessage("Unauthorized"))
+		c.JSON(http.StatusUnauthorized, errorResponse(c, "Unauthorized"))
 		return
 	}
 
@@ -53,7 +118,7 @@ func (uh *UserHandler) UpdateProfile(c *gin.Context) {
 
 	// Bind JSON request
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponseWithMessage("Invalid request format"))
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Invalid request format"))
 		return
 	}
 
@@ -66,9 +131,9 @@ func (uh *UserHandler) UpdateProfile(c *gin.Context) {
 	// Update user
 	user, err := uh.userService.UpdateUser(userID.(string), updates)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponseWithMessage("Error updating profile"))
+		c.JSON(http.StatusInternalServerError, errorResponse(c, "Error updating profile"))
 		return
 	}
 
-	c.JSON(http.StatusOK, models.SuccessResponse(user, "Profile updated successfully"))
+	c.JSON(http.StatusOK, successResponse(c, user, "Profile updated successfully"))
 }
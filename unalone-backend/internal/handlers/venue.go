@@ -0,0 +1,31 @@
+// Venue handlers for browsing hotspots at a place
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetVenueHotspots returns a venue with the hotspots taking place there, soonest first
+func (hh *HotspotHandler) GetVenueHotspots(c *gin.Context) {
+	venueID := c.Param("id")
+	if venueID == "" {
+		c.JSON(http.StatusBadRequest, errorResponse(c, "Venue ID is required"))
+		return
+	}
+
+	limit, offset := parsePagination(c)
+	response, err := hh.hotspotService.GetVenueHotspots(venueID, c.GetString("orgID"), limit, offset)
+	if err != nil {
+		if err.Error() == "venue not found" {
+			c.JSON(http.StatusNotFound, errorResponse(c, "Venue not found"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(c, err.Error()))
+		return
+	}
+	response.Hotspots = hh.markVerifiedHosts(visibleHotspots(c, response.Hotspots), c.Query("verified_hosts_only") == "true")
+
+	c.JSON(http.StatusOK, successResponse(c, response, "Venue hotspots retrieved successfully"))
+}
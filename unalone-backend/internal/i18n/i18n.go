@@ -0,0 +1,115 @@
+// Package i18n provides language negotiation and translated API messages
+package i18n
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Supported languages
+const (
+	English = "en"
+	Hindi   = "hi"
+
+	// DefaultLanguage is used when the client expresses no supported preference
+	DefaultLanguage = English
+)
+
+// languageNames are used when instructing the AI assistant which language to reply in
+var languageNames = map[string]string{
+	English: "English",
+	Hindi:   "Hindi (हिन्दी)",
+}
+
+// catalogs map English source messages to their translations
+var catalogs = map[string]map[string]string{
+	Hindi: hindiMessages,
+}
+
+type contextKey struct{}
+
+// IsSupported reports whether a language has translations
+func IsSupported(lang string) bool {
+	_, ok := languageNames[lang]
+	return ok
+}
+
+// Name returns the display name of a supported language
+func Name(lang string) string {
+	if name, ok := languageNames[lang]; ok {
+		return name
+	}
+	return languageNames[DefaultLanguage]
+}
+
+// ParseAcceptLanguage picks the best supported language from an Accept-Language header
+func ParseAcceptLanguage(header string) string {
+	type candidate struct {
+		lang string
+		q    float64
+	}
+
+	candidates := make([]candidate, 0, 4)
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.ToLower(strings.TrimSpace(fields[0]))
+		if tag == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		// Match on the primary subtag, e.g. "hi-IN" -> "hi"
+		if i := strings.IndexByte(tag, '-'); i != -1 {
+			tag = tag[:i]
+		}
+		if q > 0 && IsSupported(tag) {
+			candidates = append(candidates, candidate{lang: tag, q: q})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return DefaultLanguage
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	return candidates[0].lang
+}
+
+// Translate returns message in lang, falling back to the English source. Messages
+// built as "<known prefix>: <detail>" have only their prefix translated.
+func Translate(lang, message string) string {
+	catalog, ok := catalogs[lang]
+	if !ok {
+		return message
+	}
+	if translated, ok := catalog[message]; ok {
+		return translated
+	}
+	if i := strings.Index(message, ": "); i != -1 {
+		if prefix, ok := catalog[message[:i+2]]; ok {
+			return prefix + message[i+2:]
+		}
+	}
+	return message
+}
+
+// WithLanguage returns a context carrying the preferred language
+func WithLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, contextKey{}, lang)
+}
+
+// FromContext returns the preferred language stored in ctx, or the default
+func FromContext(ctx context.Context) string {
+	if lang, ok := ctx.Value(contextKey{}).(string); ok && lang != "" {
+		return lang
+	}
+	return DefaultLanguage
+}
@@ -0,0 +1,434 @@
+package i18n
+
+// hindiMessages translates API messages to Hindi, keyed by the English source.
+// Entries ending in ": " translate the prefix of messages that carry details.
+var hindiMessages = map[string]string{
+	// Common request errors
+	"Unauthorized":                        "अनधिकृत",
+	"Authorization header required":       "Authorization हेडर आवश्यक है",
+	"Invalid authorization header format": "Authorization हेडर का प्रारूप अमान्य है",
+	"Invalid or expired token":            "टोकन अमान्य है या समाप्त हो गया है",
+	"Invalid token":                       "अमान्य टोकन",
+	"Admin access required":               "एडमिन पहुँच आवश्यक है",
+	"Invalid request":                     "अमान्य अनुरोध",
+	"Invalid request format":              "अनुरोध का प्रारूप अमान्य है",
+	"Invalid request format: ":            "अनुरोध का प्रारूप अमान्य है: ",
+	"Latitude is required":                "अक्षांश आवश्यक है",
+	"Longitude is required":               "देशांतर आवश्यक है",
+	"Invalid latitude":                    "अमान्य अक्षांश",
+	"Invalid longitude":                   "अमान्य देशांतर",
+	"Center coordinates are required":     "केंद्र के निर्देशांक आवश्यक हैं",
+	"City is required":                    "शहर आवश्यक है",
+	"Category ID is required":             "श्रेणी ID आवश्यक है",
+
+	// Auth and users
+	"User registered successfully":        "उपयोगकर्ता सफलतापूर्वक पंजीकृत हुआ",
+	"Login successful":                    "लॉगिन सफल",
+	"Token refreshed successfully":        "टोकन सफलतापूर्वक रीफ़्रेश हुआ",
+	"Invalid email or password":           "ईमेल या पासवर्ड अमान्य है",
+	"Error generating token":              "टोकन बनाने में त्रुटि",
+	"Error refreshing token":              "टोकन रीफ़्रेश करने में त्रुटि",
+	"Error processing password":           "पासवर्ड प्रोसेस करने में त्रुटि",
+	"Error updating profile":              "प्रोफ़ाइल अपडेट करने में त्रुटि",
+	"Real name and nickname are required": "असली नाम और उपनाम आवश्यक हैं",
+	"User not found":                      "उपयोगकर्ता नहीं मिला",
+	"User ID is required":                 "उपयोगकर्ता ID आवश्यक है",
+	"user not found":                      "उपयोगकर्ता नहीं मिला",
+	"user with this email already exists": "इस ईमेल वाला उपयोगकर्ता पहले से मौजूद है",
+	"nickname is already taken":           "यह उपनाम पहले से लिया जा चुका है",
+	"user must be at least 18 years old":  "उपयोगकर्ता की आयु कम से कम 18 वर्ष होनी चाहिए",
+
+	// Profile, settings and safety
+	"Profile retrieved successfully":           "प्रोफ़ाइल सफलतापूर्वक प्राप्त हुई",
+	"Profile updated successfully":             "प्रोफ़ाइल सफलतापूर्वक अपडेट हुई",
+	"Profile image updated successfully":       "प्रोफ़ाइल चित्र सफलतापूर्वक अपडेट हुआ",
+	"Settings retrieved successfully":          "सेटिंग्स सफलतापूर्वक प्राप्त हुईं",
+	"Settings updated successfully":            "सेटिंग्स सफलतापूर्वक अपडेट हुईं",
+	"Verification code sent successfully":      "सत्यापन कोड सफलतापूर्वक भेजा गया",
+	"Phone number verified successfully":       "फ़ोन नंबर सफलतापूर्वक सत्यापित हुआ",
+	"Host verification retrieved successfully": "होस्ट सत्यापन सफलतापूर्वक प्राप्त हुआ",
+	"invalid verification code":                "अमान्य सत्यापन कोड",
+	"verification code has expired":            "सत्यापन कोड की समय-सीमा समाप्त हो गई है",
+	"maximum verification attempts exceeded":   "सत्यापन के अधिकतम प्रयास पूरे हो गए",
+	"User blocked successfully":                "उपयोगकर्ता को सफलतापूर्वक ब्लॉक किया गया",
+	"User unblocked successfully":              "उपयोगकर्ता को सफलतापूर्वक अनब्लॉक किया गया",
+	"User reported successfully":               "उपयोगकर्ता की सफलतापूर्वक रिपोर्ट की गई",
+
+	// Friends
+	"Friends retrieved":         "मित्र प्राप्त हुए",
+	"Requests retrieved":        "अनुरोध प्राप्त हुए",
+	"Request sent":              "अनुरोध भेजा गया",
+	"Friend request accepted":   "मित्र अनुरोध स्वीकार किया गया",
+	"Friend request rejected":   "मित्र अनुरोध अस्वीकार किया गया",
+	"Friend removed":            "मित्र हटाया गया",
+	"already friends":           "आप पहले से मित्र हैं",
+	"request already sent":      "अनुरोध पहले ही भेजा जा चुका है",
+	"cannot friend yourself":    "आप स्वयं को मित्र नहीं बना सकते",
+	"Muted friends retrieved":   "म्यूट किए गए मित्र प्राप्त हुए",
+	"Friend muted":              "मित्र म्यूट किया गया",
+	"Friend unmuted":            "मित्र अनम्यूट किया गया",
+	"not friends":               "आप मित्र नहीं हैं",
+	"note is too long":          "संदेश बहुत लंबा है",
+	"Friend statuses retrieved": "मित्रता की स्थिति प्राप्त हुई",
+
+	// Friend lists and invitations
+	"Friend lists retrieved successfully":                                   "मित्र सूचियाँ सफलतापूर्वक प्राप्त हुईं",
+	"Friend list created successfully":                                      "मित्र सूची सफलतापूर्वक बनाई गई",
+	"Friend list retrieved successfully":                                    "मित्र सूची सफलतापूर्वक प्राप्त हुई",
+	"Friend list updated successfully":                                      "मित्र सूची सफलतापूर्वक अपडेट हुई",
+	"Friend list deleted successfully":                                      "मित्र सूची सफलतापूर्वक हटाई गई",
+	"Friend list ID is required":                                            "मित्र सूची ID आवश्यक है",
+	"friend list not found":                                                 "मित्र सूची नहीं मिली",
+	"friend list limit reached":                                             "मित्र सूचियों की सीमा पूरी हो गई",
+	"list name is required":                                                 "सूची का नाम आवश्यक है",
+	"list members must be your friends":                                     "सूची के सदस्य आपके मित्र होने चाहिए",
+	"Invitations sent":                                                      "निमंत्रण भेजे गए",
+	"you can only invite your friends":                                      "आप केवल अपने मित्रों को आमंत्रित कर सकते हैं",
+	"friend list or user IDs are required":                                  "मित्र सूची या उपयोगकर्ता ID आवश्यक हैं",
+	"only the creator can invite to this hotspot":                           "केवल निर्माता ही इस हॉटस्पॉट में आमंत्रित कर सकता है",
+	"this hotspot is only open to invited friends":                          "यह हॉटस्पॉट केवल आमंत्रित मित्रों के लिए है",
+	"verify your phone number to join this hotspot":                         "इस हॉटस्पॉट में शामिल होने के लिए अपना फ़ोन नंबर सत्यापित करें",
+	"add your gender to your profile to join this hotspot":                  "इस हॉटस्पॉट में शामिल होने के लिए अपनी प्रोफ़ाइल में अपना लिंग जोड़ें",
+	"add your date of birth to your profile to join this hotspot":           "इस हॉटस्पॉट में शामिल होने के लिए अपनी प्रोफ़ाइल में अपनी जन्मतिथि जोड़ें",
+	"this hotspot is limited to a specific audience":                        "यह हॉटस्पॉट एक विशेष समूह तक सीमित है",
+	"this hotspot is limited to a different age group":                      "यह हॉटस्पॉट किसी अन्य आयु वर्ग तक सीमित है",
+	"this hotspot is limited to students with a school or university email": "यह हॉटस्पॉट स्कूल या विश्वविद्यालय ईमेल वाले छात्रों तक सीमित है",
+	"audience minimum age cannot be greater than maximum age":               "दर्शकों की न्यूनतम आयु अधिकतम आयु से अधिक नहीं हो सकती",
+
+	// Hotspots
+	"Hotspot ID is required":                             "हॉटस्पॉट ID आवश्यक है",
+	"Hotspot ID required":                                "हॉटस्पॉट ID आवश्यक है",
+	"Hotspot not found":                                  "हॉटस्पॉट नहीं मिला",
+	"hotspot not found":                                  "हॉटस्पॉट नहीं मिला",
+	"Hotspot created successfully":                       "हॉटस्पॉट सफलतापूर्वक बनाया गया",
+	"Hotspot retrieved successfully":                     "हॉटस्पॉट सफलतापूर्वक प्राप्त हुआ",
+	"Hotspot updated successfully":                       "हॉटस्पॉट सफलतापूर्वक अपडेट हुआ",
+	"Hotspot deleted successfully":                       "हॉटस्पॉट सफलतापूर्वक हटाया गया",
+	"Hotspot cloned successfully":                        "हॉटस्पॉट की सफलतापूर्वक प्रतिलिपि बनाई गई",
+	"Hotspot published successfully":                     "हॉटस्पॉट सफलतापूर्वक प्रकाशित हुआ",
+	"Joined hotspot successfully":                        "हॉटस्पॉट में सफलतापूर्वक शामिल हुए",
+	"Left hotspot successfully":                          "हॉटस्पॉट सफलतापूर्वक छोड़ा",
+	"Hotspots retrieved successfully":                    "हॉटस्पॉट सफलतापूर्वक प्राप्त हुए",
+	"Nearby hotspots retrieved successfully":             "आस-पास के हॉटस्पॉट सफलतापूर्वक प्राप्त हुए",
+	"Trending hotspots retrieved successfully":           "ट्रेंडिंग हॉटस्पॉट सफलतापूर्वक प्राप्त हुए",
+	"User hotspots retrieved successfully":               "उपयोगकर्ता के हॉटस्पॉट सफलतापूर्वक प्राप्त हुए",
+	"Optimized search completed successfully":            "खोज सफलतापूर्वक पूरी हुई",
+	"Cities retrieved successfully":                      "शहर सफलतापूर्वक प्राप्त हुए",
+	"Hotspot stats retrieved successfully":               "हॉटस्पॉट आँकड़े सफलतापूर्वक प्राप्त हुए",
+	"Hotspot analytics retrieved successfully":           "हॉटस्पॉट विश्लेषण सफलतापूर्वक प्राप्त हुआ",
+	"hotspot is at maximum capacity":                     "हॉटस्पॉट अपनी अधिकतम क्षमता पर है",
+	"hotspot is not active":                              "हॉटस्पॉट सक्रिय नहीं है",
+	"hotspot is not published yet":                       "हॉटस्पॉट अभी प्रकाशित नहीं हुआ है",
+	"hotspot is already published":                       "हॉटस्पॉट पहले से प्रकाशित है",
+	"user is already in this hotspot":                    "आप पहले से इस हॉटस्पॉट में हैं",
+	"user is not in this hotspot":                        "आप इस हॉटस्पॉट में नहीं हैं",
+	"end time cannot be before scheduled time":           "समाप्ति समय निर्धारित समय से पहले नहीं हो सकता",
+	"scheduled time must be in the future":               "निर्धारित समय भविष्य में होना चाहिए",
+	"only the host or a co-host can update this hotspot": "केवल होस्ट या सह-होस्ट ही इस हॉटस्पॉट को अपडेट कर सकते हैं",
+	"only the creator can delete this hotspot":           "केवल निर्माता ही इस हॉटस्पॉट को हटा सकता है",
+
+	// Categories, tags and places
+	"Categories retrieved successfully":                "श्रेणियाँ सफलतापूर्वक प्राप्त हुईं",
+	"Category saved successfully":                      "श्रेणी सफलतापूर्वक सहेजी गई",
+	"Category deactivated successfully":                "श्रेणी सफलतापूर्वक निष्क्रिय की गई",
+	"Popular tags retrieved successfully":              "लोकप्रिय टैग सफलतापूर्वक प्राप्त हुए",
+	"Suggestions retrieved successfully":               "सुझाव सफलतापूर्वक प्राप्त हुए",
+	"invalid category: ":                               "अमान्य श्रेणी: ",
+	"too many autocomplete requests, please slow down": "बहुत अधिक अनुरोध, कृपया थोड़ा धीमे चलें",
+
+	// Chat
+	"Messages retrieved":                   "संदेश प्राप्त हुए",
+	"Not a member of this hotspot":         "आप इस हॉटस्पॉट के सदस्य नहीं हैं",
+	"Not a member":                         "आप सदस्य नहीं हैं",
+	"user is not a member of this hotspot": "आप इस हॉटस्पॉट के सदस्य नहीं हैं",
+	"message content cannot be empty":      "संदेश खाली नहीं हो सकता",
+
+	// Live location sharing
+	"Location sharing started":                      "लोकेशन साझा करना शुरू हुआ",
+	"Location sharing stopped":                      "लोकेशन साझा करना बंद हुआ",
+	"Shared locations retrieved":                    "साझा की गई लोकेशन प्राप्त हुईं",
+	"location sharing is disabled in your settings": "आपकी सेटिंग्स में लोकेशन साझा करना बंद है",
+	"location sharing is not active":                "लोकेशन साझा करना सक्रिय नहीं है",
+	"hotspot has not started yet":                   "हॉटस्पॉट अभी शुरू नहीं हुआ है",
+	"hotspot has already ended":                     "हॉटस्पॉट समाप्त हो चुका है",
+
+	// Notifications
+	"Notifications retrieved successfully": "सूचनाएँ सफलतापूर्वक प्राप्त हुईं",
+	"Notification marked as read":          "सूचना पढ़ी गई के रूप में चिह्नित की गई",
+	"All notifications marked as read":     "सभी सूचनाएँ पढ़ी गई के रूप में चिह्नित की गईं",
+	"Notification ID is required":          "सूचना ID आवश्यक है",
+	"notification not found":               "सूचना नहीं मिली",
+
+	// Attendance history
+	"Event history retrieved successfully": "कार्यक्रम इतिहास सफलतापूर्वक प्राप्त हुआ",
+	"People met retrieved successfully":    "जिनसे आप मिले वे सफलतापूर्वक प्राप्त हुए",
+
+	// Post-event feedback
+	"Pending feedback retrieved successfully":      "लंबित प्रतिक्रिया सफलतापूर्वक प्राप्त हुई",
+	"Feedback submitted successfully":              "प्रतिक्रिया सफलतापूर्वक सबमिट की गई",
+	"only attendees can rate a hotspot":            "केवल उपस्थित लोग ही हॉटस्पॉट को रेट कर सकते हैं",
+	"feedback already submitted":                   "प्रतिक्रिया पहले ही सबमिट की जा चुकी है",
+	"no pending feedback request for this hotspot": "इस हॉटस्पॉट के लिए कोई लंबित प्रतिक्रिया अनुरोध नहीं है",
+
+	// Trusted contacts, check-ins and safety timers
+	"Trusted contacts retrieved successfully":               "विश्वसनीय संपर्क सफलतापूर्वक प्राप्त हुए",
+	"Trusted contact added successfully":                    "विश्वसनीय संपर्क सफलतापूर्वक जोड़ा गया",
+	"Trusted contact removed successfully":                  "विश्वसनीय संपर्क सफलतापूर्वक हटाया गया",
+	"Contact ID is required":                                "संपर्क ID आवश्यक है",
+	"trusted contact not found":                             "विश्वसनीय संपर्क नहीं मिला",
+	"a trusted contact needs a user, phone number or email": "विश्वसनीय संपर्क के लिए उपयोगकर्ता, फ़ोन नंबर या ईमेल आवश्यक है",
+	"trusted contacts on the app must be your friends":      "ऐप पर विश्वसनीय संपर्क आपके मित्र होने चाहिए",
+	"this person is already a trusted contact":              "यह व्यक्ति पहले से विश्वसनीय संपर्क है",
+	"Checked in successfully":                               "सफलतापूर्वक चेक-इन किया गया",
+	"you are too far from the hotspot to check in":          "चेक-इन करने के लिए आप हॉटस्पॉट से बहुत दूर हैं",
+	"add a trusted contact before starting a safety timer":  "सुरक्षा टाइमर शुरू करने से पहले एक विश्वसनीय संपर्क जोड़ें",
+	"Safety timers retrieved successfully":                  "सुरक्षा टाइमर सफलतापूर्वक प्राप्त हुए",
+	"Timer ID is required":                                  "टाइमर ID आवश्यक है",
+	"Marked as safe":                                        "सुरक्षित के रूप में चिह्नित किया गया",
+	"safety timer not found":                                "सुरक्षा टाइमर नहीं मिला",
+	"safety timer already confirmed":                        "सुरक्षा टाइमर की पुष्टि पहले ही हो चुकी है",
+	"SOS sent":                                              "SOS भेजा गया",
+	"SOS alerts retrieved successfully":                     "SOS अलर्ट सफलतापूर्वक प्राप्त हुए",
+
+	// Maintenance jobs
+	"Scheduled jobs retrieved successfully": "निर्धारित कार्य सफलतापूर्वक प्राप्त हुए",
+
+	// SMS delivery
+	"invalid phone number": "अमान्य फ़ोन नंबर",
+	"too many messages to this number, please try again later": "इस नंबर पर बहुत अधिक संदेश भेजे गए, कृपया बाद में पुनः प्रयास करें",
+	"failed to send SMS":       "SMS भेजने में विफल",
+	"unknown SMS provider":     "अज्ञात SMS प्रदाता",
+	"invalid callback token":   "अमान्य कॉलबैक टोकन",
+	"Delivery status recorded": "डिलीवरी स्थिति दर्ज की गई",
+
+	// Calendar and cache
+	"Calendar feed URL retrieved": "कैलेंडर फ़ीड URL प्राप्त हुआ",
+	"Calendar feed URL rotated":   "कैलेंडर फ़ीड URL बदला गया",
+	"calendar feed not found":     "कैलेंडर फ़ीड नहीं मिला",
+	"Cache statistics retrieved":  "कैश आँकड़े प्राप्त हुए",
+
+	// Hotspot versions
+	"Invalid If-Match header":                                   "अमान्य If-Match हेडर",
+	"hotspot version is required, send If-Match or version":     "हॉटस्पॉट संस्करण आवश्यक है, If-Match या version भेजें",
+	"hotspot was changed by someone else, reload and try again": "हॉटस्पॉट को किसी और ने बदल दिया है, पुनः लोड करें और फिर से प्रयास करें",
+
+	// Request bodies
+	"Request body too large":                "अनुरोध का आकार बहुत बड़ा है",
+	"Invalid request body":                  "अमान्य अनुरोध बॉडी",
+	"Content-Type must be application/json": "Content-Type application/json होना चाहिए",
+
+	// Login security
+	"too many failed login attempts, please try again later": "बहुत अधिक असफल लॉगिन प्रयास, कृपया बाद में पुनः प्रयास करें",
+
+	// Response caching
+	"Failed to encode response": "प्रतिक्रिया एन्कोड करने में विफल",
+
+	// Map badge counts
+	"Hotspot counts retrieved successfully":                                   "हॉटस्पॉट गिनती सफलतापूर्वक प्राप्त हुई",
+	"hotspot counts are unavailable right now":                                "हॉटस्पॉट गिनती अभी उपलब्ध नहीं है",
+	"bbox must be south-west and north-east corners: swLat,swLng,neLat,neLng": "bbox में दक्षिण-पश्चिम और उत्तर-पूर्व कोने होने चाहिए: swLat,swLng,neLat,neLng",
+	"bbox covers too many cells at this precision":                            "इस सटीकता पर bbox बहुत अधिक सेल को कवर करता है",
+	"Invalid precision": "अमान्य सटीकता",
+
+	// Cluster previews
+	"preview_sort must be occupancy or start_time": "preview_sort occupancy या start_time होना चाहिए",
+
+	// User location
+	"Location updated successfully":                                             "स्थान सफलतापूर्वक अपडेट किया गया",
+	"Location cleared successfully":                                             "स्थान सफलतापूर्वक हटाया गया",
+	"Location is required: send latitude and longitude or update your location": "स्थान आवश्यक है: अक्षांश और देशांतर भेजें या अपना स्थान अपडेट करें",
+	"location sharing is turned off in your settings":                           "आपकी सेटिंग्स में स्थान साझाकरण बंद है",
+
+	// Occupancy timeline
+	"interval_minutes must be between 5 and 1440":   "interval_minutes 5 और 1440 के बीच होना चाहिए",
+	"only the host can view the occupancy timeline": "केवल होस्ट ही उपस्थिति टाइमलाइन देख सकता है",
+	"Occupancy timeline retrieved successfully":     "उपस्थिति टाइमलाइन सफलतापूर्वक प्राप्त हुई",
+
+	// Hotspot activity
+	"only the host can view hotspot activity": "केवल होस्ट ही हॉटस्पॉट गतिविधि देख सकता है",
+	"Hotspot activity retrieved successfully": "हॉटस्पॉट गतिविधि सफलतापूर्वक प्राप्त हुई",
+
+	// Co-hosts and ownership transfer
+	"Co-host added successfully":                          "सह-होस्ट सफलतापूर्वक जोड़ा गया",
+	"Co-host removed successfully":                        "सह-होस्ट सफलतापूर्वक हटाया गया",
+	"Ownership offer sent":                                "स्वामित्व प्रस्ताव भेजा गया",
+	"Ownership offer cancelled":                           "स्वामित्व प्रस्ताव रद्द किया गया",
+	"Ownership offer declined":                            "स्वामित्व प्रस्ताव अस्वीकार किया गया",
+	"You are now the host":                                "अब आप होस्ट हैं",
+	"there is no ownership offer for you on this hotspot": "इस हॉटस्पॉट पर आपके लिए कोई स्वामित्व प्रस्ताव नहीं है",
+	"the ownership offer has expired":                     "स्वामित्व प्रस्ताव की समय-सीमा समाप्त हो गई है",
+	"only the host can manage co-hosts and ownership":     "केवल होस्ट ही सह-होस्ट और स्वामित्व प्रबंधित कर सकता है",
+	"you already host this hotspot":                       "आप पहले से ही इस हॉटस्पॉट के होस्ट हैं",
+
+	// Venues
+	"Venue ID is required":                       "स्थल आईडी आवश्यक है",
+	"Venue not found":                            "स्थल नहीं मिला",
+	"venue not found":                            "स्थल नहीं मिला",
+	"venue is too far from the hotspot location": "स्थल हॉटस्पॉट के स्थान से बहुत दूर है",
+	"Venue hotspots retrieved successfully":      "स्थल के हॉटस्पॉट सफलतापूर्वक प्राप्त हुए",
+	"Import feeds retrieved successfully":        "आयात फ़ीड सफलतापूर्वक प्राप्त हुए",
+	"Import feed added successfully":             "आयात फ़ीड सफलतापूर्वक जोड़ी गई",
+	"Import feed deleted successfully":           "आयात फ़ीड सफलतापूर्वक हटाई गई",
+	"Import feed run completed":                  "आयात फ़ीड चलाना पूरा हुआ",
+	"import feed not found":                      "आयात फ़ीड नहीं मिली",
+	"feed URL must be http or https":             "फ़ीड URL http या https होना चाहिए",
+	"Invalid radius":                             "अमान्य त्रिज्या",
+	"Too many requests, please try again later":  "बहुत अधिक अनुरोध, कृपया बाद में पुनः प्रयास करें",
+	"Share link created successfully":            "शेयर लिंक सफलतापूर्वक बनाया गया",
+	"Share link resolved successfully":           "शेयर लिंक सफलतापूर्वक खोला गया",
+	"Share link revoked successfully":            "शेयर लिंक सफलतापूर्वक रद्द किया गया",
+	"share link not found":                       "शेयर लिंक नहीं मिला",
+	"share link has expired":                     "शेयर लिंक की समय सीमा समाप्त हो गई है",
+	"only the host or a co-host can share a hotspot limited to a friend list":                 "मित्र सूची तक सीमित हॉटस्पॉट को केवल होस्ट या सह-होस्ट ही साझा कर सकते हैं",
+	"publish the hotspot before sharing it":                                                   "साझा करने से पहले हॉटस्पॉट प्रकाशित करें",
+	"Check-in code retrieved successfully":                                                    "चेक-इन कोड सफलतापूर्वक प्राप्त किया गया",
+	"check-in code is invalid or has expired":                                                 "चेक-इन कोड अमान्य है या उसकी समय-सीमा समाप्त हो गई है",
+	"only the host or a co-host can show the check-in code":                                   "केवल होस्ट या सह-होस्ट ही चेक-इन कोड दिखा सकते हैं",
+	"hosts cannot scan their own check-in code":                                               "होस्ट अपना स्वयं का चेक-इन कोड स्कैन नहीं कर सकते",
+	"Email digest settings retrieved successfully":                                            "ईमेल डाइजेस्ट सेटिंग्स सफलतापूर्वक प्राप्त की गईं",
+	"Email digest settings updated successfully":                                              "ईमेल डाइजेस्ट सेटिंग्स सफलतापूर्वक अपडेट की गईं",
+	"Email digest preview generated successfully":                                             "ईमेल डाइजेस्ट पूर्वावलोकन सफलतापूर्वक तैयार किया गया",
+	"You have been unsubscribed from the weekly digest":                                       "आपको साप्ताहिक डाइजेस्ट से अनसब्सक्राइब कर दिया गया है",
+	"unsubscribe link is invalid":                                                             "अनसब्सक्राइब लिंक अमान्य है",
+	"Metrics retrieved successfully":                                                          "मेट्रिक्स सफलतापूर्वक प्राप्त किए गए",
+	"Invalid days":                                                                            "अमान्य दिन",
+	"days must be between 1 and 90":                                                           "दिन 1 से 90 के बीच होने चाहिए",
+	"Experiments retrieved successfully":                                                      "प्रयोग सफलतापूर्वक प्राप्त किए गए",
+	"Exposure recorded successfully":                                                          "एक्सपोज़र सफलतापूर्वक दर्ज किया गया",
+	"Experiment saved successfully":                                                           "प्रयोग सफलतापूर्वक सहेजा गया",
+	"Experiment results retrieved successfully":                                               "प्रयोग के परिणाम सफलतापूर्वक प्राप्त किए गए",
+	"experiment not found":                                                                    "प्रयोग नहीं मिला",
+	"user is not enrolled in this experiment":                                                 "उपयोगकर्ता इस प्रयोग में शामिल नहीं है",
+	"experiment key must be a lowercase slug":                                                 "प्रयोग कुंजी लोअरकेस स्लग होनी चाहिए",
+	"variant names must be unique":                                                            "वेरिएंट के नाम अद्वितीय होने चाहिए",
+	"Onboarding progress retrieved successfully":                                              "ऑनबोर्डिंग प्रगति सफलतापूर्वक प्राप्त की गई",
+	"Interests retrieved successfully":                                                        "रुचियाँ सफलतापूर्वक प्राप्त की गईं",
+	"Interest suggestions retrieved successfully":                                             "रुचि सुझाव सफलतापूर्वक प्राप्त किए गए",
+	"date of birth cannot be in the future":                                                   "जन्म तिथि भविष्य की नहीं हो सकती",
+	"invalid date of birth":                                                                   "अमान्य जन्म तिथि",
+	"date of birth cannot be changed after phone verification; contact support to correct it": "फ़ोन सत्यापन के बाद जन्म तिथि नहीं बदली जा सकती; इसे ठीक कराने के लिए सहायता से संपर्क करें",
+	"Date of birth corrected successfully":                                                    "जन्म तिथि सफलतापूर्वक ठीक की गई",
+	"this nickname was recently used by someone else and is reserved":                         "यह उपनाम हाल ही में किसी और ने इस्तेमाल किया था और आरक्षित है",
+	"nickname was changed recently; next change allowed: ":                                    "उपनाम हाल ही में बदला गया था; अगला बदलाव इस तिथि से संभव है: ",
+	"Nickname history retrieved successfully":                                                 "उपनाम इतिहास सफलतापूर्वक प्राप्त किया गया",
+	"Heartbeat recorded":                                                                      "हार्टबीट दर्ज की गई",
+	"Availability updated successfully":                                                       "उपलब्धता सफलतापूर्वक अपडेट की गई",
+	"Attendees retrieved successfully":                                                        "प्रतिभागी सफलतापूर्वक प्राप्त किए गए",
+	"Chat muted":                                                                              "चैट म्यूट की गई",
+	"Chat unmuted":                                                                            "चैट अनम्यूट की गई",
+	"Chat settings retrieved":                                                                 "चैट सेटिंग्स प्राप्त की गईं",
+	"duration must be 1h, 8h or forever":                                                      "अवधि 1h, 8h या forever होनी चाहिए",
+	"only the host can pin messages or post announcements":                                    "केवल होस्ट ही संदेश पिन कर सकते हैं या घोषणाएँ पोस्ट कर सकते हैं",
+	"a chat can have at most 3 pinned messages":                                               "एक चैट में अधिकतम 3 पिन किए गए संदेश हो सकते हैं",
+	"message not found":                                                                       "संदेश नहीं मिला",
+	"Announcement posted":                                                                     "घोषणा पोस्ट की गई",
+	"Pinned messages retrieved":                                                               "पिन किए गए संदेश प्राप्त किए गए",
+	"Message pinned":                                                                          "संदेश पिन किया गया",
+	"Message unpinned":                                                                        "संदेश अनपिन किया गया",
+	"location pins need valid coordinates and a label of up to 100 characters":                "लोकेशन पिन के लिए मान्य निर्देशांक और अधिकतम 100 अक्षरों का लेबल आवश्यक है",
+	"voice notes can be at most 512 KB":                                                       "वॉइस नोट अधिकतम 512 KB का हो सकता है",
+	"unsupported audio format":                                                                "असमर्थित ऑडियो फ़ॉर्मेट",
+	"voice note not found":                                                                    "वॉइस नोट नहीं मिला",
+	"voice notes cannot be processed right now, try again shortly":                            "अभी वॉइस नोट प्रोसेस नहीं हो सकते, थोड़ी देर बाद फिर कोशिश करें",
+	"voice note is not ready":                                                                 "वॉइस नोट अभी तैयार नहीं है",
+	"Audio file is required":                                                                  "ऑडियो फ़ाइल आवश्यक है",
+	"Voice note is being processed":                                                           "वॉइस नोट प्रोसेस किया जा रहा है",
+	"Voice note retrieved":                                                                    "वॉइस नोट प्राप्त किया गया",
+	"Invalid or expired ticket":                                                               "अमान्य या समाप्त टिकट",
+	"Pass the access token as a WebSocket subprotocol or use a ticket":                        "एक्सेस टोकन को WebSocket सबप्रोटोकॉल के रूप में भेजें या टिकट का उपयोग करें",
+	"Failed to issue ticket: ":                                                                "टिकट जारी करने में विफल: ",
+	"Ticket issued":                                                                           "टिकट जारी किया गया",
+	"messages can be at most 2000 characters":                                                 "संदेश अधिकतम 2000 अक्षरों के हो सकते हैं",
+	"Announcement already posted":                                                             "घोषणा पहले ही पोस्ट की जा चुकी है",
+	"client_id can be at most 64 characters":                                                  "client_id अधिकतम 64 अक्षरों का हो सकता है",
+	"Resources retrieved successfully":                                                        "संसाधन सफलतापूर्वक प्राप्त किए गए",
+	"Resource ID is required":                                                                 "संसाधन ID आवश्यक है",
+	"Resource saved successfully":                                                             "संसाधन सफलतापूर्वक सहेजा गया",
+	"Resource deactivated successfully":                                                       "संसाधन सफलतापूर्वक निष्क्रिय किया गया",
+	"resource ID must be a lowercase slug":                                                    "संसाधन ID छोटे अक्षरों वाला स्लग होना चाहिए",
+	"resource needs a phone number, URL or location":                                          "संसाधन के लिए फ़ोन नंबर, URL या स्थान आवश्यक है",
+	"region must be a subdivision of the resource's country, e.g. IN-KA":                      "क्षेत्र संसाधन के देश का उपविभाग होना चाहिए, जैसे IN-KA",
+	"resources with a location need a radius":                                                 "स्थान वाले संसाधनों के लिए त्रिज्या आवश्यक है",
+	"resource not found":                                                                      "संसाधन नहीं मिला",
+	"Prompts retrieved successfully":                                                          "प्रॉम्प्ट सफलतापूर्वक प्राप्त किए गए",
+	"Prompt versions retrieved successfully":                                                  "प्रॉम्प्ट संस्करण सफलतापूर्वक प्राप्त किए गए",
+	"Prompt version published successfully":                                                   "प्रॉम्प्ट संस्करण सफलतापूर्वक प्रकाशित किया गया",
+	"prompt not found":                                                                        "प्रॉम्प्ट नहीं मिला",
+	"unknown prompt variable: ":                                                               "अज्ञात प्रॉम्प्ट वेरिएबल: ",
+	"experiment_key and variant must be set together":                                         "experiment_key और variant एक साथ सेट होने चाहिए",
+	"variant is not part of the experiment":                                                   "वेरिएंट इस प्रयोग का हिस्सा नहीं है",
+	"Privacy policy retrieved":                                                                "गोपनीयता नीति प्राप्त हुई",
+	"Consents retrieved":                                                                      "सहमतियाँ प्राप्त हुईं",
+	"Consent history retrieved":                                                               "सहमति इतिहास प्राप्त हुआ",
+	"Consent granted":                                                                         "सहमति दी गई",
+	"Consent revoked":                                                                         "सहमति वापस ली गई",
+	"consent purpose not found":                                                               "सहमति का उद्देश्य नहीं मिला",
+	"the consent text has changed; show the current version and ask again":                    "सहमति का पाठ बदल गया है; मौजूदा संस्करण दिखाएँ और फिर से पूछें",
+	"you have not consented to location processing":                                           "आपने स्थान प्रोसेसिंग के लिए सहमति नहीं दी है",
+	"Organization admin access required":                                                      "संगठन एडमिन पहुँच आवश्यक है",
+	"Organization retrieved":                                                                  "संगठन प्राप्त हुआ",
+	"Joined organization":                                                                     "संगठन में शामिल हो गए",
+	"Left organization":                                                                       "संगठन छोड़ दिया",
+	"Organization members retrieved":                                                          "संगठन के सदस्य प्राप्त हुए",
+	"Member role updated":                                                                     "सदस्य की भूमिका अपडेट की गई",
+	"Member removed":                                                                          "सदस्य हटाया गया",
+	"Invite code rotated":                                                                     "आमंत्रण कोड बदला गया",
+	"Organization created":                                                                    "संगठन बनाया गया",
+	"Organizations retrieved":                                                                 "संगठन प्राप्त हुए",
+	"organization not found":                                                                  "संगठन नहीं मिला",
+	"invalid invite code":                                                                     "अमान्य आमंत्रण कोड",
+	"you already belong to an organization; leave it first":                                   "आप पहले से एक संगठन के सदस्य हैं; पहले उसे छोड़ें",
+	"you do not belong to an organization":                                                    "आप किसी संगठन के सदस्य नहीं हैं",
+	"member not found":                                                                        "सदस्य नहीं मिला",
+	"an organization needs at least one admin":                                                "संगठन में कम से कम एक एडमिन होना चाहिए",
+	"Club created":                                                                            "क्लब बनाया गया",
+	"Clubs retrieved":                                                                         "क्लब प्राप्त हुए",
+	"Followed clubs retrieved":                                                                "फ़ॉलो किए गए क्लब प्राप्त हुए",
+	"Club retrieved":                                                                          "क्लब प्राप्त हुआ",
+	"Club hotspots retrieved":                                                                 "क्लब के हॉटस्पॉट प्राप्त हुए",
+	"Club updated":                                                                            "क्लब अपडेट किया गया",
+	"Club admin added":                                                                        "क्लब एडमिन जोड़ा गया",
+	"Club admin removed":                                                                      "क्लब एडमिन हटाया गया",
+	"Club followed":                                                                           "क्लब फ़ॉलो किया गया",
+	"Club unfollowed":                                                                         "क्लब अनफ़ॉलो किया गया",
+	"club not found":                                                                          "क्लब नहीं मिला",
+	"only club admins can do this":                                                            "केवल क्लब एडमिन ही यह कर सकते हैं",
+	"a club needs at least one admin":                                                         "क्लब में कम से कम एक एडमिन होना चाहिए",
+	"Ticket tiers retrieved":                                                                  "टिकट श्रेणियाँ प्राप्त हुईं",
+	"Ticket tiers updated":                                                                    "टिकट श्रेणियाँ अपडेट की गईं",
+	"Checkout started":                                                                        "भुगतान शुरू हुआ",
+	"Ticket sales retrieved":                                                                  "टिकट बिक्री प्राप्त हुई",
+	"Tickets retrieved":                                                                       "टिकट प्राप्त हुए",
+	"Ticket retrieved":                                                                        "टिकट प्राप्त हुआ",
+	"Webhook processed":                                                                       "वेबहुक संसाधित किया गया",
+	"a ticket is required to join this hotspot":                                               "इस हॉटस्पॉट में शामिल होने के लिए टिकट आवश्यक है",
+	"payments are not available right now":                                                    "भुगतान अभी उपलब्ध नहीं हैं",
+	"unknown payment provider":                                                                "अज्ञात भुगतान प्रदाता",
+	"invalid webhook signature":                                                               "अमान्य वेबहुक हस्ताक्षर",
+	"ticket not found":                                                                        "टिकट नहीं मिला",
+	"ticket tier not found":                                                                   "टिकट श्रेणी नहीं मिली",
+	"tickets are sold out":                                                                    "टिकट बिक चुके हैं",
+	"you already have a ticket for this hotspot":                                              "आपके पास इस हॉटस्पॉट का टिकट पहले से है",
+	"tickets have been sold for this tier: it cannot be removed, repriced or shrunk below the number sold": "इस श्रेणी के टिकट बिक चुके हैं: इसे हटाया नहीं जा सकता, इसकी कीमत बदली नहीं जा सकती और इसे बिके टिकटों से कम नहीं किया जा सकता",
+	"only the host can change ticket tiers":                             "केवल होस्ट टिकट श्रेणियाँ बदल सकता है",
+	"only the host can see ticket sales":                                "केवल होस्ट टिकट बिक्री देख सकता है",
+	"failed to start payment":                                           "भुगतान शुरू करने में विफल",
+	"invalid payment webhook":                                           "अमान्य भुगतान वेबहुक",
+	"invalid Razorpay webhook":                                          "अमान्य Razorpay वेबहुक",
+	"invalid Stripe webhook":                                            "अमान्य Stripe वेबहुक",
+	"Provider usage retrieved":                                          "प्रदाता उपयोग प्राप्त हुआ",
+	"text messages are temporarily unavailable, please try again later": "टेक्स्ट संदेश अस्थायी रूप से उपलब्ध नहीं हैं, कृपया बाद में पुनः प्रयास करें",
+	"days must be between 1 and 7":                                      "दिन 1 से 7 के बीच होने चाहिए",
+	"Job queue retrieved":                                               "जॉब कतार प्राप्त हुई",
+	"Dead jobs retrieved":                                               "विफल जॉब प्राप्त हुए",
+	"Job queued again":                                                  "जॉब फिर से कतार में डाला गया",
+	"Invalid limit":                                                     "अमान्य सीमा",
+	"job not found":                                                     "जॉब नहीं मिला",
+	"Dependency health retrieved":                                       "निर्भरता की स्थिति प्राप्त हुई",
+	"Dependency health updated":                                         "निर्भरता की स्थिति अपडेट हुई",
+	"unknown dependency":                                                "अज्ञात निर्भरता",
+	"Firestore usage retrieved":                                         "Firestore उपयोग प्राप्त हुआ",
+	"this request reads too much data, narrow it down and try again":    "यह अनुरोध बहुत अधिक डेटा पढ़ता है, इसे सीमित करें और फिर से प्रयास करें",
+	"only the host or a co-host can clone this hotspot":                 "केवल होस्ट या सह-होस्ट ही इस हॉटस्पॉट की कॉपी बना सकते हैं",
+	"only the host can clone a hotspot limited to their friend list":    "अपनी मित्र सूची तक सीमित हॉटस्पॉट की कॉपी केवल होस्ट ही बना सकता है",
+}
@@ -0,0 +1,164 @@
+// Background job queue: typed job payloads, retries with backoff and dead letters
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultMaxAttempts is how often a job runs before it is dead-lettered when its type does not say
+	DefaultMaxAttempts = 5
+	// DefaultTimeout bounds one attempt when the job type does not say
+	DefaultTimeout = time.Minute
+	// MaxTimeout is the longest an attempt may run; claimed jobs are leased a little longer
+	MaxTimeout = 10 * time.Minute
+	// MaxDeadLetters is how many dead-lettered jobs are kept; older ones are dropped
+	MaxDeadLetters = 1000
+)
+
+// ErrNotFound is returned when a dead-lettered job does not exist
+var ErrNotFound = errors.New("job not found")
+
+// Job is one unit of queued work
+type Job struct {
+	ID          string          `json:"id"`
+	Type        string          `json:"type"`
+	Payload     json.RawMessage `json:"payload"`
+	Attempt     int             `json:"attempt"` // Attempts made so far
+	MaxAttempts int             `json:"max_attempts"`
+	EnqueuedAt  time.Time       `json:"enqueued_at"`
+	RunAt       time.Time       `json:"run_at"` // Not run before then
+	LastError   string          `json:"last_error,omitempty"`
+	FailedAt    *time.Time      `json:"failed_at,omitempty"` // When it was dead-lettered
+}
+
+// Counts are the jobs in a store, across every instance sharing it
+type Counts struct {
+	Ready   int64 `json:"ready"`   // Waiting for a worker
+	Delayed int64 `json:"delayed"` // Waiting for their run time or a retry
+	Leased  int64 `json:"leased"`  // Being run
+	Dead    int64 `json:"dead"`
+}
+
+// Store holds queued jobs. A claimed job is leased to its worker; when the lease runs out
+// before Ack, Retry or Bury (e.g. the instance crashed) the job is queued again, so handlers
+// must tolerate running more than once.
+type Store interface {
+	// Backend returns "redis" or "memory"
+	Backend() string
+	// Push queues a job, delayed when its RunAt is in the future
+	Push(ctx context.Context, job *Job) error
+	// Claim leases the next ready job, or returns nil when none is ready
+	Claim(ctx context.Context, lease time.Duration) (*Job, error)
+	// Ack removes a finished job
+	Ack(ctx context.Context, job *Job) error
+	// Retry queues a leased job again at its RunAt
+	Retry(ctx context.Context, job *Job) error
+	// Bury moves a leased job to the dead letters, keeping the newest MaxDeadLetters
+	Bury(ctx context.Context, job *Job) error
+	// Promote readies delayed jobs that are due and jobs whose lease ran out
+	Promote(ctx context.Context, now time.Time) (int, error)
+	Counts(ctx context.Context) (Counts, error)
+	// DeadLetters lists dead-lettered jobs, newest first
+	DeadLetters(ctx context.Context, limit int) ([]*Job, error)
+	// Revive queues a dead-lettered job again with its attempts reset
+	Revive(ctx context.Context, id string) (*Job, error)
+}
+
+// Type names a kind of job and the payload it carries. Declare one per kind of work and
+// use it both to enqueue jobs and to handle them.
+type Type[T any] struct {
+	Name        string
+	MaxAttempts int           // Attempts before the job is dead-lettered; DefaultMaxAttempts when zero
+	Timeout     time.Duration // Per attempt, at most MaxTimeout; DefaultTimeout when zero
+}
+
+// Enqueue queues a job to run as soon as a worker is free
+func (t Type[T]) Enqueue(ctx context.Context, q *Queue, payload T) (string, error) {
+	return t.EnqueueAt(ctx, q, payload, time.Time{})
+}
+
+// EnqueueAt queues a job to run once at has passed
+func (t Type[T]) EnqueueAt(ctx context.Context, q *Queue, payload T, at time.Time) (string, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("encode %s payload: %w", t.Name, err)
+	}
+	now := time.Now()
+	if at.Before(now) {
+		at = now
+	}
+	job := &Job{
+		ID:          uuid.New().String(),
+		Type:        t.Name,
+		Payload:     data,
+		MaxAttempts: t.MaxAttempts,
+		EnqueuedAt:  now,
+		RunAt:       at,
+	}
+	if job.MaxAttempts <= 0 {
+		job.MaxAttempts = DefaultMaxAttempts
+	}
+	if err := q.push(ctx, job); err != nil {
+		return "", err
+	}
+	return job.ID, nil
+}
+
+// Handle registers the function that runs jobs of this type. Register before the queue starts.
+// Returning an error retries the job with backoff; wrap it with Permanent to give up at once.
+func (t Type[T]) Handle(q *Queue, run func(ctx context.Context, payload T) error) {
+	timeout := t.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	if timeout > MaxTimeout {
+		timeout = MaxTimeout
+	}
+	q.register(t.Name, timeout, func(ctx context.Context, data json.RawMessage) error {
+		var payload T
+		if err := json.Unmarshal(data, &payload); err != nil {
+			return Permanent(fmt.Errorf("decode %s payload: %w", t.Name, err))
+		}
+		return run(ctx, payload)
+	})
+}
+
+// HandleDead registers a function called when a job of this type is dead-lettered,
+// e.g. to mark the work it was doing as failed
+func (t Type[T]) HandleDead(q *Queue, fn func(ctx context.Context, payload T, err error)) {
+	q.registerDead(t.Name, func(ctx context.Context, data json.RawMessage, err error) {
+		var payload T
+		if json.Unmarshal(data, &payload) == nil {
+			fn(ctx, payload, err)
+		}
+	})
+}
+
+// permanentError marks a failure that retrying will not fix
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps a handler error so the job is dead-lettered without further attempts
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether err was wrapped with Permanent
+func IsPermanent(err error) bool {
+	var perm *permanentError
+	return errors.As(err, &perm)
+}
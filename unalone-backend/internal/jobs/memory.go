@@ -0,0 +1,161 @@
+// In-process job store for single instances and runs without Redis
+package jobs
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps jobs in this process; they are lost when it exits
+type MemoryStore struct {
+	mu      sync.Mutex
+	jobs    map[string]*Job
+	ready   []string             // Oldest first
+	delayed map[string]time.Time // id -> run at
+	leased  map[string]time.Time // id -> lease expiry
+	dead    []string             // Newest first
+}
+
+// NewMemoryStore creates an empty in-process store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		jobs:    make(map[string]*Job),
+		delayed: make(map[string]time.Time),
+		leased:  make(map[string]time.Time),
+	}
+}
+
+// Backend returns "memory"
+func (ms *MemoryStore) Backend() string {
+	return "memory"
+}
+
+func (ms *MemoryStore) Push(ctx context.Context, job *Job) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	ms.jobs[job.ID] = copyJob(job)
+	if job.RunAt.After(time.Now()) {
+		ms.delayed[job.ID] = job.RunAt
+	} else {
+		ms.ready = append(ms.ready, job.ID)
+	}
+	return nil
+}
+
+func (ms *MemoryStore) Claim(ctx context.Context, lease time.Duration) (*Job, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	for len(ms.ready) > 0 {
+		id := ms.ready[0]
+		ms.ready = ms.ready[1:]
+		if job, ok := ms.jobs[id]; ok {
+			ms.leased[id] = time.Now().Add(lease)
+			return copyJob(job), nil
+		}
+	}
+	return nil, nil
+}
+
+func (ms *MemoryStore) Ack(ctx context.Context, job *Job) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	delete(ms.leased, job.ID)
+	delete(ms.jobs, job.ID)
+	return nil
+}
+
+func (ms *MemoryStore) Retry(ctx context.Context, job *Job) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	delete(ms.leased, job.ID)
+	ms.jobs[job.ID] = copyJob(job)
+	ms.delayed[job.ID] = job.RunAt
+	return nil
+}
+
+func (ms *MemoryStore) Bury(ctx context.Context, job *Job) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	delete(ms.leased, job.ID)
+	ms.jobs[job.ID] = copyJob(job)
+	ms.dead = append([]string{job.ID}, ms.dead...)
+	if len(ms.dead) > MaxDeadLetters {
+		for _, id := range ms.dead[MaxDeadLetters:] {
+			delete(ms.jobs, id)
+		}
+		ms.dead = ms.dead[:MaxDeadLetters]
+	}
+	return nil
+}
+
+func (ms *MemoryStore) Promote(ctx context.Context, now time.Time) (int, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	promoted := 0
+	for _, due := range []map[string]time.Time{ms.delayed, ms.leased} {
+		for id, at := range due {
+			if at.After(now) {
+				continue
+			}
+			delete(due, id)
+			ms.ready = append(ms.ready, id)
+			promoted++
+		}
+	}
+	return promoted, nil
+}
+
+func (ms *MemoryStore) Counts(ctx context.Context) (Counts, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	return Counts{
+		Ready:   int64(len(ms.ready)),
+		Delayed: int64(len(ms.delayed)),
+		Leased:  int64(len(ms.leased)),
+		Dead:    int64(len(ms.dead)),
+	}, nil
+}
+
+func (ms *MemoryStore) DeadLetters(ctx context.Context, limit int) ([]*Job, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	jobs := make([]*Job, 0, limit)
+	for _, id := range ms.dead {
+		if len(jobs) == limit {
+			break
+		}
+		if job, ok := ms.jobs[id]; ok {
+			jobs = append(jobs, copyJob(job))
+		}
+	}
+	return jobs, nil
+}
+
+func (ms *MemoryStore) Revive(ctx context.Context, id string) (*Job, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	for i, deadID := range ms.dead {
+		if deadID != id {
+			continue
+		}
+		ms.dead = append(ms.dead[:i], ms.dead[i+1:]...)
+		job := ms.jobs[id]
+		revive(job)
+		ms.ready = append(ms.ready, id)
+		return copyJob(job), nil
+	}
+	return nil, ErrNotFound
+}
+
+// revive resets a dead-lettered job to run again now
+func revive(job *Job) {
+	job.Attempt = 0
+	job.FailedAt = nil
+	job.RunAt = time.Now()
+}
+
+func copyJob(job *Job) *Job {
+	c := *job
+	return &c
+}
@@ -0,0 +1,347 @@
+// Job queue workers: claim jobs, run their handlers and retry or dead-letter failures
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// pollInterval is how often idle workers look for jobs and delayed jobs are promoted
+	pollInterval = time.Second
+	// leaseMargin is added to a handler's timeout so its lease outlives the attempt
+	leaseMargin = time.Minute
+	// Retry backoff doubles from retryBaseDelay up to retryMaxDelay
+	retryBaseDelay = 10 * time.Second
+	retryMaxDelay  = 30 * time.Minute
+)
+
+type handler struct {
+	timeout time.Duration
+	run     func(ctx context.Context, payload json.RawMessage) error
+	dead    func(ctx context.Context, payload json.RawMessage, err error)
+}
+
+// TypeStats reports how one job type has been running on this instance since it started
+type TypeStats struct {
+	Type      string     `json:"type"`
+	Succeeded int        `json:"succeeded"`
+	Retried   int        `json:"retried"`
+	Dead      int        `json:"dead"`
+	LastRunAt *time.Time `json:"last_run_at,omitempty"`
+	LastError string     `json:"last_error,omitempty"`
+}
+
+// Stats is the queue state returned to admins
+type Stats struct {
+	Backend string      `json:"backend"`
+	Workers int         `json:"workers"` // On this instance; 0 only enqueues
+	Running int         `json:"running"` // Jobs running on this instance
+	Queue   Counts      `json:"queue"`   // Across every instance sharing the store
+	Types   []TypeStats `json:"types"`
+}
+
+// Queue runs jobs from a Store on a pool of workers. Jobs that fail are retried with
+// exponential backoff until their type's attempts run out, then dead-lettered.
+type Queue struct {
+	store   Store
+	workers int
+	wake    chan struct{}
+
+	mu       sync.Mutex
+	handlers map[string]*handler
+	stats    map[string]*TypeStats
+	running  int
+
+	stop  context.CancelFunc // stops claiming jobs
+	abort context.CancelFunc // cancels jobs still running after Stop's grace period
+	wg    sync.WaitGroup
+}
+
+// New creates a queue over store with workers concurrent jobs per instance.
+// With no workers the instance only enqueues, leaving jobs to other instances.
+func New(store Store, workers int) *Queue {
+	if workers < 0 {
+		workers = 0
+	}
+	return &Queue{
+		store:    store,
+		workers:  workers,
+		wake:     make(chan struct{}, 1),
+		handlers: make(map[string]*handler),
+		stats:    make(map[string]*TypeStats),
+	}
+}
+
+// Backend returns the store's backend
+func (q *Queue) Backend() string {
+	return q.store.Backend()
+}
+
+// Workers returns how many jobs this instance runs at once
+func (q *Queue) Workers() int {
+	return q.workers
+}
+
+func (q *Queue) register(jobType string, timeout time.Duration, run func(context.Context, json.RawMessage) error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	h := q.handlerFor(jobType)
+	h.timeout = timeout
+	h.run = run
+}
+
+func (q *Queue) registerDead(jobType string, fn func(context.Context, json.RawMessage, error)) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.handlerFor(jobType).dead = fn
+}
+
+// handlerFor returns the handler entry for a job type; callers hold mu
+func (q *Queue) handlerFor(jobType string) *handler {
+	h := q.handlers[jobType]
+	if h == nil {
+		h = &handler{}
+		q.handlers[jobType] = h
+		q.stats[jobType] = &TypeStats{Type: jobType}
+	}
+	return h
+}
+
+func (q *Queue) push(ctx context.Context, job *Job) error {
+	if err := q.store.Push(ctx, job); err != nil {
+		return fmt.Errorf("enqueue %s: %w", job.Type, err)
+	}
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Start runs the workers and promotes delayed jobs until ctx is cancelled or Stop is called
+func (q *Queue) Start(ctx context.Context) {
+	if q.workers == 0 {
+		log.Printf("Job queue: no workers on this instance, only enqueueing")
+		return
+	}
+	claimCtx, stop := context.WithCancel(ctx)
+	// Running jobs are not cancelled with ctx so Stop can let them finish
+	runCtx, abort := context.WithCancel(context.Background())
+	q.mu.Lock()
+	q.stop, q.abort = stop, abort
+	q.mu.Unlock()
+
+	q.wg.Add(1)
+	go func() {
+		defer q.wg.Done()
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-claimCtx.Done():
+				return
+			case <-ticker.C:
+				if _, err := q.store.Promote(claimCtx, time.Now()); err != nil && claimCtx.Err() == nil {
+					log.Printf("Job queue: promote failed: %v", err)
+				}
+			}
+		}
+	}()
+
+	for i := 0; i < q.workers; i++ {
+		q.wg.Add(1)
+		go func() {
+			defer q.wg.Done()
+			q.work(claimCtx, runCtx)
+		}()
+	}
+	log.Printf("Job queue: %d workers, %s store", q.workers, q.store.Backend())
+}
+
+// Stop stops claiming jobs and waits up to grace for running ones to finish; jobs still
+// running after that are cancelled and retried later
+func (q *Queue) Stop(grace time.Duration) {
+	q.mu.Lock()
+	stop, abort := q.stop, q.abort
+	q.mu.Unlock()
+	if stop == nil {
+		return
+	}
+	stop()
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(grace):
+		log.Printf("Job queue: cancelling jobs still running after %s", grace)
+		abort()
+		<-done
+	}
+	abort()
+}
+
+// work claims and runs jobs until claimCtx is cancelled
+func (q *Queue) work(claimCtx, runCtx context.Context) {
+	for claimCtx.Err() == nil {
+		job, err := q.store.Claim(claimCtx, q.leaseDuration())
+		if err != nil && claimCtx.Err() == nil {
+			log.Printf("Job queue: claim failed: %v", err)
+		}
+		if job == nil {
+			select {
+			case <-claimCtx.Done():
+			case <-q.wake:
+			case <-time.After(pollInterval):
+			}
+			continue
+		}
+		q.run(runCtx, job)
+	}
+}
+
+// leaseDuration outlives the longest handler timeout
+func (q *Queue) leaseDuration() time.Duration {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	longest := DefaultTimeout
+	for _, h := range q.handlers {
+		if h.timeout > longest {
+			longest = h.timeout
+		}
+	}
+	return longest + leaseMargin
+}
+
+// run makes one attempt at a job and acknowledges, retries or dead-letters it
+func (q *Queue) run(ctx context.Context, job *Job) {
+	q.mu.Lock()
+	h := q.handlers[job.Type]
+	q.running++
+	q.mu.Unlock()
+	defer func() {
+		q.mu.Lock()
+		q.running--
+		q.mu.Unlock()
+	}()
+
+	job.Attempt++
+	var err error
+	if h == nil || h.run == nil {
+		err = Permanent(fmt.Errorf("no handler for job type %q", job.Type))
+	} else {
+		attemptCtx, cancel := context.WithTimeout(ctx, h.timeout)
+		err = safeRun(attemptCtx, h.run, job.Payload)
+		cancel()
+	}
+	now := time.Now()
+	// Bookkeeping outlives a cancelled run context
+	storeCtx := context.Background()
+
+	if err == nil {
+		q.record(job.Type, now, func(s *TypeStats) { s.Succeeded++ })
+		if err := q.store.Ack(storeCtx, job); err != nil {
+			log.Printf("Job queue: ack %s %s: %v", job.Type, job.ID, err)
+		}
+		return
+	}
+
+	job.LastError = err.Error()
+	if !IsPermanent(err) && job.Attempt < job.MaxAttempts {
+		job.RunAt = now.Add(Backoff(job.Attempt))
+		q.record(job.Type, now, func(s *TypeStats) { s.Retried++; s.LastError = job.LastError })
+		if err := q.store.Retry(storeCtx, job); err != nil {
+			log.Printf("Job queue: retry %s %s: %v", job.Type, job.ID, err)
+		}
+		return
+	}
+
+	job.FailedAt = &now
+	q.record(job.Type, now, func(s *TypeStats) { s.Dead++; s.LastError = job.LastError })
+	log.Printf("Job queue: %s %s dead-lettered after %d attempts: %v", job.Type, job.ID, job.Attempt, err)
+	if err := q.store.Bury(storeCtx, job); err != nil {
+		log.Printf("Job queue: bury %s %s: %v", job.Type, job.ID, err)
+	}
+	if h != nil && h.dead != nil {
+		h.dead(storeCtx, job.Payload, err)
+	}
+}
+
+// safeRun runs a handler, turning a panic into a failed attempt
+func safeRun(ctx context.Context, run func(context.Context, json.RawMessage) error, payload json.RawMessage) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return run(ctx, payload)
+}
+
+func (q *Queue) record(jobType string, at time.Time, update func(*TypeStats)) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	stats := q.stats[jobType]
+	if stats == nil {
+		stats = &TypeStats{Type: jobType}
+		q.stats[jobType] = stats
+	}
+	stats.LastRunAt = &at
+	update(stats)
+}
+
+// Backoff is the delay before retrying a job that has failed attempt times:
+// 10s doubling up to 30 minutes, spread by up to 10%
+func Backoff(attempt int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempt && delay < retryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+	return delay + time.Duration(rand.Int63n(int64(delay/10)+1))
+}
+
+// Stats returns the store's counts and how each job type has run on this instance
+func (q *Queue) Stats(ctx context.Context) (*Stats, error) {
+	counts, err := q.store.Counts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	stats := &Stats{Backend: q.store.Backend(), Workers: q.workers, Running: q.running, Queue: counts, Types: make([]TypeStats, 0, len(q.stats))}
+	for _, s := range q.stats {
+		stats.Types = append(stats.Types, *s)
+	}
+	sort.Slice(stats.Types, func(i, j int) bool { return stats.Types[i].Type < stats.Types[j].Type })
+	return stats, nil
+}
+
+// DeadLetters lists dead-lettered jobs, newest first
+func (q *Queue) DeadLetters(ctx context.Context, limit int) ([]*Job, error) {
+	return q.store.DeadLetters(ctx, limit)
+}
+
+// Retry queues a dead-lettered job again with its attempts reset
+func (q *Queue) Retry(ctx context.Context, id string) (*Job, error) {
+	job, err := q.store.Revive(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return job, nil
+}
@@ -0,0 +1,31 @@
+// Admin middleware for restricting routes to operators
+package middleware
+
+import (
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminMiddleware allows only users whose email is listed in ADMIN_EMAILS
+// (comma-separated). It must run after AuthMiddleware.
+func AdminMiddleware() gin.HandlerFunc {
+	admins := make(map[string]bool)
+	for _, email := range strings.Split(os.Getenv("ADMIN_EMAILS"), ",") {
+		if email = strings.ToLower(strings.TrimSpace(email)); email != "" {
+			admins[email] = true
+		}
+	}
+
+	return func(c *gin.Context) {
+		if !admins[strings.ToLower(c.GetString("userEmail"))] {
+			c.JSON(http.StatusForbidden, errorResponse(c, "Admin access required"))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
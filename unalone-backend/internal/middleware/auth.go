@@ -6,17 +6,18 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"unalone-backend/internal/models"
 	"unalone-backend/internal/services"
 )
 
-// AuthMiddleware creates authentication middleware
-func AuthMiddleware(authService *services.AuthService) gin.HandlerFunc {
+// AuthMiddleware creates authentication middleware. When metrics is set, each authenticated
+// request marks its user as active for the admin dashboard. The user's organization, if any,
+// is set as orgID so handlers can scope what they return.
+func AuthMiddleware(authService *services.AuthService, metrics *services.PlatformMetricsService, orgs *services.OrganizationService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get Authorization header
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, models.ErrorResponseWithMessage("Authorization header required"))
+			c.JSON(http.StatusUnauthorized, errorResponse(c, "Authorization header required"))
 			c.Abort()
 			return
 		}
@@ -24,7 +25,7 @@ func AuthMiddleware(authService *services.AuthService) gin.HandlerFunc {
 		// Check Bearer token format
 		tokenParts := strings.Split(authHeader, " ")
 		if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, models.ErrorResponseWithMessage("Invalid authorization header format"))
+			c.JSON(http.StatusUnauthorized, errorResponse(c, "Invalid authorization header format"))
 			c.Abort()
 			return
 		}
@@ -34,7 +35,7 @@ func AuthMiddleware(authService *services.AuthService) gin.HandlerFunc {
 		// Validate token
 		claims, err := authService.ValidateToken(tokenString)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, models.ErrorResponseWithMessage("Invalid or expired token"))
+			c.JSON(http.StatusUnauthorized, errorResponse(c, "Invalid or expired token"))
 			c.Abort()
 			return
 		}
@@ -42,6 +43,12 @@ func AuthMiddleware(authService *services.AuthService) gin.HandlerFunc {
 		// Set user information in context
 		c.Set("userID", claims.UserID)
 		c.Set("userEmail", claims.Email)
+		if orgs != nil {
+			c.Set("orgID", orgs.OrgOf(claims.UserID))
+		}
+		if metrics != nil {
+			metrics.RecordActive(claims.UserID)
+		}
 
 		// Continue to next handler
 		c.Next()
@@ -0,0 +1,47 @@
+// Client IP resolution behind load balancers and proxies
+package middleware
+
+import (
+	"log"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// cloudRunProxies are the addresses Cloud Run's front end connects from. It appends the
+// caller's address to X-Forwarded-For, so only that last entry is believed.
+var cloudRunProxies = []string{"169.254.0.0/16"}
+
+// ConfigureClientIP decides which forwarding headers c.ClientIP() believes. Login lockouts
+// and rate limits key on it, so a header anyone can send must not be trusted.
+// TRUSTED_PLATFORM names a header set by the hosting platform ("google" for
+// X-Appengine-Remote-Addr, "cloudflare" for CF-Connecting-IP, or any header name), and
+// TRUSTED_PROXIES lists the proxy addresses or CIDRs whose X-Forwarded-For is believed.
+// On Cloud Run without either, its front end is trusted. Otherwise no header is, and the
+// client IP is the address of the connection.
+func ConfigureClientIP(engine *gin.Engine) {
+	switch platform := strings.TrimSpace(os.Getenv("TRUSTED_PLATFORM")); strings.ToLower(platform) {
+	case "":
+	case "google":
+		engine.TrustedPlatform = gin.PlatformGoogleAppEngine
+	case "cloudflare":
+		engine.TrustedPlatform = gin.PlatformCloudflare
+	default:
+		engine.TrustedPlatform = platform
+	}
+
+	var proxies []string
+	for _, proxy := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	if len(proxies) == 0 && engine.TrustedPlatform == "" && os.Getenv("K_SERVICE") != "" {
+		proxies = cloudRunProxies
+	}
+	if err := engine.SetTrustedProxies(proxies); err != nil {
+		log.Printf("Client IP: invalid TRUSTED_PROXIES (%v), using the connection address", err)
+		_ = engine.SetTrustedProxies(nil)
+	}
+}
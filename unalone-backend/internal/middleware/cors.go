@@ -2,18 +2,103 @@
 package middleware
 
 import (
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	defaultCORSMethods = "GET, POST, PUT, DELETE, OPTIONS"
+	defaultCORSHeaders = "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, If-Match, If-None-Match, Accept-Language"
+)
+
+// CORSPolicy decides which browser origins may call the API. Origins are read
+// from CORS_ALLOWED_ORIGINS (comma-separated, "*" for any); only origins listed
+// explicitly may send credentials.
+type CORSPolicy struct {
+	origins  map[string]bool
+	allowAny bool
+	methods  string
+	headers  string
+}
+
+// NewCORSPolicy loads the policy from CORS_ALLOWED_ORIGINS, CORS_ALLOWED_METHODS
+// and CORS_ALLOWED_HEADERS. Without CORS_ALLOWED_ORIGINS any origin is allowed,
+// without credentials, to keep local development working.
+func NewCORSPolicy() *CORSPolicy {
+	policy := &CORSPolicy{
+		origins: make(map[string]bool),
+		methods: envOr("CORS_ALLOWED_METHODS", defaultCORSMethods),
+		headers: envOr("CORS_ALLOWED_HEADERS", defaultCORSHeaders),
+	}
+	raw := os.Getenv("CORS_ALLOWED_ORIGINS")
+	if strings.TrimSpace(raw) == "" {
+		log.Printf("CORS: CORS_ALLOWED_ORIGINS not set, allowing any origin without credentials")
+		policy.allowAny = true
+		return policy
+	}
+	for _, origin := range strings.Split(raw, ",") {
+		origin = strings.TrimRight(strings.ToLower(strings.TrimSpace(origin)), "/")
+		switch origin {
+		case "":
+		case "*":
+			policy.allowAny = true
+		default:
+			policy.origins[origin] = true
+		}
+	}
+	return policy
+}
+
+// Trusted reports whether an origin is listed explicitly
+func (p *CORSPolicy) Trusted(origin string) bool {
+	return p.origins[strings.TrimRight(strings.ToLower(origin), "/")]
+}
+
+// Allowed reports whether a browser on origin may call the API
+func (p *CORSPolicy) Allowed(origin string) bool {
+	return p.allowAny || p.Trusted(origin)
+}
+
+// CheckWebSocketOrigin accepts clients without an Origin header (native apps),
+// same-host pages and allowed origins
+func (p *CORSPolicy) CheckWebSocketOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	return p.Allowed(origin)
+}
+
 // CORSMiddleware handles Cross-Origin Resource Sharing
-func CORSMiddleware() gin.HandlerFunc {
+func CORSMiddleware(policy *CORSPolicy) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Credentials", "true")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Header("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
+		origin := c.GetHeader("Origin")
+		c.Header("Vary", "Origin")
+		if origin != "" && policy.Allowed(origin) {
+			if policy.Trusted(origin) {
+				c.Header("Access-Control-Allow-Origin", origin)
+				c.Header("Access-Control-Allow-Credentials", "true")
+			} else {
+				c.Header("Access-Control-Allow-Origin", "*")
+			}
+			c.Header("Access-Control-Allow-Headers", policy.headers)
+			c.Header("Access-Control-Allow-Methods", policy.methods)
+			c.Header("Access-Control-Expose-Headers", "ETag, Content-Language, X-Service-Mode, X-Degraded")
+		}
 
 		if c.Request.Method == "OPTIONS" {
+			if origin != "" && !policy.Allowed(origin) {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
 			c.AbortWithStatus(204)
 			return
 		}
@@ -21,3 +106,11 @@ func CORSMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// envOr returns the environment variable or fallback when it is unset
+func envOr(key, fallback string) string {
+	if value := strings.TrimSpace(os.Getenv(key)); value != "" {
+		return value
+	}
+	return fallback
+}
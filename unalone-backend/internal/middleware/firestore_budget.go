@@ -0,0 +1,24 @@
+// Firestore read budget middleware
+package middleware
+
+import (
+	"log"
+
+	"unalone-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FirestoreBudgetMiddleware gives each request a budget of Firestore document reads, which
+// services draw from when they read with the request context, and logs requests that used
+// most of it
+func FirestoreBudgetMiddleware(guard *services.FirestoreGuard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, budget := services.WithReadBudget(c.Request.Context(), guard.MaxReadsPerRequest())
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+		if budget.Approaching() {
+			log.Printf("[firestore] %s %s read %d of the %d documents a request may read", c.Request.Method, c.FullPath(), budget.Used(), budget.Max())
+		}
+	}
+}
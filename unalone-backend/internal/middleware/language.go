@@ -0,0 +1,28 @@
+// Language middleware for negotiating the response language
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"unalone-backend/internal/i18n"
+	"unalone-backend/internal/models"
+)
+
+// LanguageMiddleware resolves the preferred language from Accept-Language and
+// stores it in the gin and request contexts for handlers and services
+func LanguageMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		lang := i18n.ParseAcceptLanguage(c.GetHeader("Accept-Language"))
+
+		c.Set("lang", lang)
+		c.Request = c.Request.WithContext(i18n.WithLanguage(c.Request.Context(), lang))
+		c.Header("Content-Language", lang)
+		c.Header("Vary", "Accept-Language")
+
+		c.Next()
+	}
+}
+
+// errorResponse builds an error response translated to the request language
+func errorResponse(c *gin.Context, message string) models.ErrorResponse {
+	return models.ErrorResponseWithMessage(i18n.Translate(i18n.FromContext(c.Request.Context()), message))
+}
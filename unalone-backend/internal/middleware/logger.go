@@ -3,6 +3,8 @@ package middleware
 
 import (
 	"fmt"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -15,7 +17,7 @@ func LoggerMiddleware() gin.HandlerFunc {
 			param.ClientIP,
 			param.TimeStamp.Format(time.RFC1123),
 			param.Method,
-			param.Path,
+			redactQuery(param.Path),
 			param.Request.Proto,
 			param.StatusCode,
 			param.Latency,
@@ -24,3 +26,29 @@ func LoggerMiddleware() gin.HandlerFunc {
 		)
 	})
 }
+
+// redactedQueryParams carry credentials and are masked in access logs
+var redactedQueryParams = []string{"token", "ticket"}
+
+// redactQuery masks credential query parameters in a logged path
+func redactQuery(path string) string {
+	i := strings.IndexByte(path, '?')
+	if i < 0 {
+		return path
+	}
+	query, err := url.ParseQuery(path[i+1:])
+	if err != nil {
+		return path[:i] + "?REDACTED"
+	}
+	redacted := false
+	for _, name := range redactedQueryParams {
+		if _, ok := query[name]; ok {
+			query.Set(name, "REDACTED")
+			redacted = true
+		}
+	}
+	if !redacted {
+		return path
+	}
+	return path[:i+1] + query.Encode()
+}
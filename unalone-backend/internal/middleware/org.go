@@ -0,0 +1,25 @@
+// Organization middleware for routes run by an organization's admins
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"unalone-backend/internal/services"
+)
+
+// OrgAdminMiddleware allows only admins of the caller's organization and sets orgID to it.
+// It must run after AuthMiddleware.
+func OrgAdminMiddleware(orgs *services.OrganizationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		orgID, ok := orgs.IsOrgAdmin(c.GetString("userID"))
+		if !ok {
+			c.JSON(http.StatusForbidden, errorResponse(c, "Organization admin access required"))
+			c.Abort()
+			return
+		}
+
+		c.Set("orgID", orgID)
+		c.Next()
+	}
+}
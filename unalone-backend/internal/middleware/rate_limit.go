@@ -0,0 +1,29 @@
+// Rate limit middleware for endpoints open to anonymous clients
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"unalone-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimitMiddleware limits requests per client IP, answering 429 with Retry-After once
+// the limit is used up
+func RateLimitMiddleware(rl *services.RateLimiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		allowed, retryAfter := rl.Allow(c.ClientIP())
+		c.Header("X-RateLimit-Limit", strconv.Itoa(rl.Limit()))
+		if !allowed {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			c.JSON(http.StatusTooManyRequests, errorResponse(c, "Too many requests, please try again later"))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,23 @@
+// Service mode middleware reporting degraded dependencies on every response
+package middleware
+
+import (
+	"strings"
+
+	"unalone-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ServiceModeMiddleware sets X-Service-Mode (normal, degraded or unavailable) on every response
+// and, outside normal mode, X-Degraded with the dependencies that are down
+func ServiceModeMiddleware(health *services.HealthRegistry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		mode, down := health.Mode()
+		c.Header("X-Service-Mode", mode)
+		if len(down) > 0 {
+			c.Header("X-Degraded", strings.Join(down, ","))
+		}
+		c.Next()
+	}
+}
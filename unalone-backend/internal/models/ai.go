@@ -4,21 +4,56 @@ package models
 import "time"
 
 type AIChatSession struct {
-	ID        string    `json:"id"`
-	UserID    string    `json:"user_id"`
-	Title     string    `json:"title"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID           string          `json:"id"`
+	UserID       string          `json:"user_id"` // Owner
+	Title        string          `json:"title"`
+	Participants []AIParticipant `json:"participants,omitempty"` // Friends the owner shared the session with
+	CreatedAt    time.Time       `json:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+}
+
+// AI session participant roles
+const (
+	AIParticipantReadOnly = "read_only" // Can read the conversation
+	AIParticipantCoChat   = "co_chat"   // Can also send messages
+)
+
+// MaxAIParticipants is how many friends a session can be shared with
+const MaxAIParticipants = 7
+
+// AIParticipant is a friend a session is shared with
+type AIParticipant struct {
+	UserID   string    `json:"user_id"`
+	Nickname string    `json:"nickname"`
+	Role     string    `json:"role"`
+	AddedAt  time.Time `json:"added_at"`
 }
 
 type AIMessage struct {
-	ID        string    `json:"id"`
-	SessionID string    `json:"session_id"`
-	Role      string    `json:"role"` // user | ai
-	Content   string    `json:"content"`
-	CreatedAt time.Time `json:"created_at"`
+	ID             string              `json:"id"`
+	SessionID      string              `json:"session_id"`
+	Role           string              `json:"role"`                      // user | ai
+	AuthorID       string              `json:"author_id,omitempty"`       // Who sent a user message
+	AuthorNickname string              `json:"author_nickname,omitempty"` // Shown in shared sessions
+	Content        string              `json:"content"`
+	Resources      []WellbeingResource `json:"resources,omitempty"` // Crisis helplines attached to a reply
+	Cached         bool                `json:"cached,omitempty"`    // Reply served from the AI reply cache
+	CreatedAt      time.Time           `json:"created_at"`
 }
 
+// AISessionExport is a session's full transcript as downloaded by its owner or a participant
+type AISessionExport struct {
+	Session    AIChatSession `json:"session"`
+	Messages   []*AIMessage  `json:"messages"`
+	ExportedAt time.Time     `json:"exported_at"`
+}
+
+// AI session export formats
+const (
+	AIExportJSON     = "json"
+	AIExportMarkdown = "markdown"
+)
+
 type CreateAISessionRequest struct {
 	Title string `json:"title"`
 }
@@ -26,3 +61,97 @@ type CreateAISessionRequest struct {
 type SendAIMessageRequest struct {
 	Content string `json:"content" binding:"required,min=1,max=4000"`
 }
+
+// AddAIParticipantRequest shares a session with a friend
+type AddAIParticipantRequest struct {
+	UserID string `json:"user_id" binding:"required"`
+	Role   string `json:"role" binding:"required,oneof=read_only co_chat"`
+}
+
+// UpdateAIParticipantRequest changes what a participant may do
+type UpdateAIParticipantRequest struct {
+	Role string `json:"role" binding:"required,oneof=read_only co_chat"`
+}
+
+// DraftHotspotRequest asks the assistant to draft a hotspot from the conversation. The
+// assistant cannot place pins, so the client passes the position to use, usually its own.
+type DraftHotspotRequest struct {
+	Latitude  *float64 `json:"latitude" binding:"omitempty,min=-90,max=90"`
+	Longitude *float64 `json:"longitude" binding:"omitempty,min=-180,max=180"`
+	City      string   `json:"city" binding:"max=100"`    // Used when the conversation names no city
+	Country   string   `json:"country" binding:"max=100"` // Used when the conversation names no country
+}
+
+// AIHotspotDraft is a hotspot the assistant proposed. The client shows it for review and
+// confirms it, with edits, to create the hotspot.
+type AIHotspotDraft struct {
+	ID        string               `json:"id"`
+	SessionID string               `json:"session_id"`
+	CreatedBy string               `json:"created_by"`
+	Hotspot   CreateHotspotRequest `json:"hotspot"`
+	Missing   []string             `json:"missing,omitempty"`    // Fields the client must fill in before confirming
+	HotspotID string               `json:"hotspot_id,omitempty"` // Set once confirmed
+	CreatedAt time.Time            `json:"created_at"`
+	ExpiresAt time.Time            `json:"expires_at"`
+}
+
+// AI mood tags derived from a user's own messages
+const (
+	AIMoodAnxious  = "anxious"
+	AIMoodStressed = "stressed"
+	AIMoodSad      = "sad"
+	AIMoodLonely   = "lonely"
+	AIMoodAngry    = "angry"
+	AIMoodTired    = "tired"
+	AIMoodHopeful  = "hopeful"
+	AIMoodCalm     = "calm"
+	AIMoodHappy    = "happy"
+	AIMoodGrateful = "grateful"
+)
+
+// AIMoodTags lists every mood tag
+var AIMoodTags = []string{
+	AIMoodAnxious, AIMoodStressed, AIMoodSad, AIMoodLonely, AIMoodAngry,
+	AIMoodTired, AIMoodHopeful, AIMoodCalm, AIMoodHappy, AIMoodGrateful,
+}
+
+// AIMoodScore is what is kept of one message for insights: a score and tags, never the text
+type AIMoodScore struct {
+	Sentiment float64  // -1 (negative) to 1 (positive)
+	Moods     []string // See AIMood*
+}
+
+// MaxAIInsightDays is the longest trend GET /ai/insights returns; older insights are purged
+const MaxAIInsightDays = 90
+
+// AISessionInsight aggregates the sentiment of a user's own messages in one session
+type AISessionInsight struct {
+	SessionID string         `json:"session_id"`
+	Messages  int            `json:"messages"`
+	Sentiment float64        `json:"sentiment"`       // Average, -1 to 1
+	Moods     map[string]int `json:"moods,omitempty"` // Messages tagged with each mood
+	FirstDay  string         `json:"first_day"`       // YYYY-MM-DD, UTC
+	LastDay   string         `json:"last_day"`
+}
+
+// AIInsightDay aggregates a user's sentiment across sessions on one UTC day
+type AIInsightDay struct {
+	Day       string         `json:"day"`
+	Sessions  int            `json:"sessions"`
+	Messages  int            `json:"messages"`
+	Sentiment float64        `json:"sentiment"`
+	Moods     map[string]int `json:"moods,omitempty"`
+}
+
+// AIInsights is a user's private mood trend, built only while they consent
+type AIInsights struct {
+	Enabled     bool               `json:"enabled"`
+	ConsentedAt *time.Time         `json:"consented_at,omitempty"`
+	Days        []AIInsightDay     `json:"days"`     // Oldest first
+	Sessions    []AISessionInsight `json:"sessions"` // Most recent first
+}
+
+// UpdateAIInsightsConsentRequest turns mood insights on or off; turning them off deletes them
+type UpdateAIInsightsConsentRequest struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
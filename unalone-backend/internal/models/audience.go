@@ -0,0 +1,20 @@
+// Audience restriction models for hotspots
+package models
+
+// HotspotAudience limits who may join a hotspot. Each set field must match the
+// joiner's verified profile; the joiner's own attributes are never shown to the host.
+type HotspotAudience struct {
+	Gender       string `firestore:"gender" json:"gender,omitempty" binding:"omitempty,oneof=female male"` // e.g. female for women-only
+	MinAge       int    `firestore:"min_age" json:"min_age,omitempty" binding:"omitempty,min=13,max=120"`
+	MaxAge       int    `firestore:"max_age" json:"max_age,omitempty" binding:"omitempty,min=13,max=120"`
+	StudentsOnly bool   `firestore:"students_only" json:"students_only,omitempty"`
+}
+
+// Error codes returned when a user does not meet a hotspot's audience restriction
+const (
+	AudienceErrorVerificationRequired = "audience_verification_required"
+	AudienceErrorProfileIncomplete    = "audience_profile_incomplete"
+	AudienceErrorGender               = "audience_gender_restricted"
+	AudienceErrorAge                  = "audience_age_restricted"
+	AudienceErrorStudentsOnly         = "audience_students_only"
+)
@@ -0,0 +1,18 @@
+// Calendar subscription models
+package models
+
+import "time"
+
+// CalendarFeed is a user's calendar subscription token, stored at calendar_feeds/{userID}
+type CalendarFeed struct {
+	UserID    string    `firestore:"user_id" json:"user_id"`
+	Token     string    `firestore:"token" json:"-"`
+	CreatedAt time.Time `firestore:"created_at" json:"created_at"`
+}
+
+// CalendarTombstone keeps a deleted hotspot in an attendee's feed so their calendar app
+// cancels the event, stored at calendar_feeds/{userID}/tombstones/{hotspotID}
+type CalendarTombstone struct {
+	Hotspot   *Hotspot  `firestore:"hotspot" json:"hotspot"` // Last state, inactive with a bumped sequence
+	DeletedAt time.Time `firestore:"deleted_at" json:"deleted_at"`
+}
@@ -0,0 +1,26 @@
+// Category models for the data-driven hotspot taxonomy
+package models
+
+import "time"
+
+// Category represents a hotspot category or subcategory in the taxonomy
+type Category struct {
+	ID            string     `firestore:"id" json:"id"` // Slug, e.g. "cafe" or "board-games"
+	Name          string     `firestore:"name" json:"name"`
+	Icon          string     `firestore:"icon" json:"icon"`
+	ParentID      string     `firestore:"parent_id" json:"parent_id,omitempty"` // Empty for top-level categories
+	SortOrder     int        `firestore:"sort_order" json:"sort_order"`
+	IsActive      bool       `firestore:"is_active" json:"is_active"`
+	Subcategories []Category `firestore:"-" json:"subcategories,omitempty"`
+	CreatedAt     time.Time  `firestore:"created_at" json:"created_at"`
+	UpdatedAt     time.Time  `firestore:"updated_at" json:"updated_at"`
+}
+
+// UpsertCategoryRequest represents an admin request to create or update a category
+type UpsertCategoryRequest struct {
+	Name      string `json:"name" binding:"required,min=2,max=50"`
+	Icon      string `json:"icon" binding:"max=50"`
+	ParentID  string `json:"parent_id" binding:"max=50"`
+	SortOrder int    `json:"sort_order"`
+	IsActive  *bool  `json:"is_active"`
+}
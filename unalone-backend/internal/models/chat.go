@@ -5,15 +5,127 @@ import "time"
 
 // ChatMessage represents a message in a hotspot chat room
 type ChatMessage struct {
-	ID        string    `firestore:"id" json:"id"`
-	HotspotID string    `firestore:"hotspot_id" json:"hotspot_id"`
-	UserID    string    `firestore:"user_id" json:"user_id"`
-	Nickname  string    `firestore:"nickname" json:"nickname"`
-	Content   string    `firestore:"content" json:"content"`
-	CreatedAt time.Time `firestore:"created_at" json:"created_at"`
+	ID           string           `firestore:"id" json:"id"`
+	HotspotID    string           `firestore:"hotspot_id" json:"hotspot_id"`
+	UserID       string           `firestore:"user_id" json:"user_id"`
+	Nickname     string           `firestore:"nickname" json:"nickname"`
+	Content      string           `firestore:"content" json:"content"`
+	Kind         string           `firestore:"kind" json:"kind,omitempty"` // Empty for text; see ChatMessageKind*
+	Location     *ChatLocationPin `firestore:"location" json:"location,omitempty"`
+	Attachment   *ChatAttachment  `firestore:"attachment" json:"attachment,omitempty"`
+	Announcement bool             `firestore:"announcement" json:"announcement,omitempty"` // Host announcement pushed to every attendee
+	PinnedAt     *time.Time       `firestore:"pinned_at" json:"pinned_at,omitempty"`
+	ExpiresAt    *time.Time       `firestore:"expires_at" json:"expires_at,omitempty"` // Set in ephemeral chats
+	ClientID     string           `firestore:"client_id" json:"client_id,omitempty"`   // Sender's own ID for the message, used to dedupe retries
+	CreatedAt    time.Time        `firestore:"created_at" json:"created_at"`
+}
+
+// Chat message kinds other than plain text
+const (
+	ChatMessageKindLocationPin = "location_pin"
+	ChatMessageKindVoiceNote   = "voice_note"
+)
+
+// MaxLocationPinLabel is the longest label a location pin may carry
+const MaxLocationPinLabel = 100
+
+// ChatLocationPin is a labelled point an attendee drops in the chat
+type ChatLocationPin struct {
+	Latitude  float64 `firestore:"latitude" json:"latitude"`
+	Longitude float64 `firestore:"longitude" json:"longitude"`
+	Label     string  `firestore:"label" json:"label"`
+}
+
+// MaxChatMessageLength is the longest chat message, in characters
+const MaxChatMessageLength = 2000
+
+// MaxChatClientIDLength is the longest client-generated message ID
+const MaxChatClientIDLength = 64
+
+// ChatResumeLimit is the most messages replayed when a chat WebSocket resumes
+const ChatResumeLimit = 200
+
+// MaxPinnedMessages is how many messages a hotspot chat can have pinned at once
+const MaxPinnedMessages = 3
+
+// Chat WebSocket frame types for announcements and pins
+const (
+	ChatFrameAnnouncement = "announcement" // Sent by a host as { "type", "content" }; echoed as a chat message
+	ChatFrameLocationPin  = "location_pin" // Sent as { "type", "latitude", "longitude", "label" }; echoed as a chat message
+	ChatFramePinned       = "pinned"
+	ChatFrameUnpinned     = "unpinned"
+	ChatFrameError        = "error" // Sent to one client when its frame was rejected
+	ChatFrameResumed      = "resumed"
+	ChatFrameAck          = "ack" // Sent to the sender once a frame with a client_id is stored
+)
+
+// ChatAckFrame confirms a message to its sender with the canonical ID and time.
+// Duplicate is set when the client_id was already used and no new message was created.
+type ChatAckFrame struct {
+	Type      string    `json:"type"`
+	ClientID  string    `json:"client_id"`
+	MessageID string    `json:"message_id"`
+	CreatedAt time.Time `json:"created_at"`
+	Duplicate bool      `json:"duplicate,omitempty"`
+}
+
+// ChatResumeFrame follows the messages replayed on a resumed connection; live messages come after it.
+// Complete is false when the gap could not be replayed in full and the client should reload history.
+type ChatResumeFrame struct {
+	Type     string `json:"type"`
+	Replayed int    `json:"replayed"`
+	Complete bool   `json:"complete"`
+}
+
+// Chat error frame codes
+const (
+	ChatErrorRateLimited = "rate_limited"
+	ChatErrorTooLong     = "message_too_long"
+	ChatErrorRejected    = "rejected" // Not a member, invalid content or any other refusal
+)
+
+// ChatErrorFrame tells a client its frame was not delivered
+type ChatErrorFrame struct {
+	Type       string `json:"type"`
+	Code       string `json:"code"`
+	ClientID   string `json:"client_id,omitempty"` // Echoed from the rejected frame
+	RetryAfter int    `json:"retry_after_seconds,omitempty"`
+}
+
+// ChatPinFrame tells the room a message was pinned or unpinned
+type ChatPinFrame struct {
+	Type    string       `json:"type"`
+	Message *ChatMessage `json:"message"`
+}
+
+// PinMessageRequest pins a chat message
+type PinMessageRequest struct {
+	MessageID string `json:"message_id" binding:"required"`
 }
 
 // SendMessageRequest is used by clients to send a message
 type SendMessageRequest struct {
-	Content string `json:"content" binding:"required,min=1,max=2000"`
+	Content  string `json:"content" binding:"required,min=1,max=2000"`
+	ClientID string `json:"client_id" binding:"omitempty,max=64"` // Retrying with the same ID returns the original message
+}
+
+// Chat mute durations
+const (
+	ChatMuteOneHour    = "1h"
+	ChatMuteEightHours = "8h"
+	ChatMuteForever    = "forever"
+)
+
+// ChatNotificationPrefs are a user's notification settings for one hotspot's chat room
+type ChatNotificationPrefs struct {
+	HotspotID  string     `firestore:"hotspot_id" json:"hotspot_id"`
+	UserID     string     `firestore:"user_id" json:"user_id"`
+	Muted      bool       `firestore:"muted" json:"muted"`
+	MutedUntil *time.Time `firestore:"muted_until" json:"muted_until,omitempty"` // nil while muted means until unmuted
+	UpdatedAt  time.Time  `firestore:"updated_at" json:"updated_at"`
+}
+
+// MuteChatRequest mutes a hotspot chat for 1h, 8h or until unmuted
+type MuteChatRequest struct {
+	Duration string `json:"duration" binding:"required,oneof=1h 8h forever"`
 }
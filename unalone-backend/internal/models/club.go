@@ -0,0 +1,62 @@
+// Club models: organizer pages that host hotspots and that users follow
+package models
+
+import "time"
+
+// Club is a page for a group that hosts hotspots, such as a student society or a running club.
+// Its admins can host hotspots on its behalf, and followers hear when it publishes one.
+type Club struct {
+	ID            string    `firestore:"id" json:"id"`
+	Name          string    `firestore:"name" json:"name"`
+	Description   string    `firestore:"description" json:"description"`
+	LogoURL       string    `firestore:"logo_url" json:"logo_url,omitempty"`
+	AdminIDs      []string  `firestore:"admin_ids" json:"admin_ids"`
+	OrgID         string    `firestore:"org_id" json:"-"` // The creator's organization; only its members see the club
+	FollowerCount int       `firestore:"follower_count" json:"follower_count"`
+	CreatedBy     string    `firestore:"created_by" json:"created_by"`
+	CreatedAt     time.Time `firestore:"created_at" json:"created_at"`
+	UpdatedAt     time.Time `firestore:"updated_at" json:"updated_at"`
+}
+
+// ClubProfile is a club page as seen by the current user
+type ClubProfile struct {
+	Club
+	Admins           []PublicUser          `json:"admins"`
+	Following        bool                  `json:"following"`
+	IsAdmin          bool                  `json:"is_admin"`
+	UpcomingHotspots []HotspotWithDistance `json:"upcoming_hotspots"` // Soonest first, up to 10
+}
+
+// ClubListResponse is one page of clubs, most followed first
+type ClubListResponse struct {
+	Clubs   []Club `json:"clubs"`
+	Total   int    `json:"total"`
+	HasMore bool   `json:"has_more"`
+}
+
+// ClubHotspotsResponse lists the hotspots a club hosts, soonest first
+type ClubHotspotsResponse struct {
+	Club     *Club                 `json:"club"`
+	Hotspots []HotspotWithDistance `json:"hotspots"`
+	Total    int                   `json:"total"`
+	HasMore  bool                  `json:"has_more"`
+}
+
+// CreateClubRequest creates a club with the current user as its first admin
+type CreateClubRequest struct {
+	Name        string `json:"name" binding:"required,min=3,max=100"`
+	Description string `json:"description" binding:"max=1000"`
+	LogoURL     string `json:"logo_url" binding:"omitempty,url"`
+}
+
+// UpdateClubRequest changes a club's page; omitted fields are left as they are
+type UpdateClubRequest struct {
+	Name        *string `json:"name" binding:"omitempty,min=3,max=100"`
+	Description *string `json:"description" binding:"omitempty,max=1000"`
+	LogoURL     *string `json:"logo_url" binding:"omitempty,url|len=0"` // Empty string removes the logo
+}
+
+// ClubAdminRequest names a user to make a club admin
+type ClubAdminRequest struct {
+	UserID string `json:"user_id" binding:"required"`
+}
@@ -0,0 +1,43 @@
+// Weekly email digest models
+package models
+
+import "time"
+
+// DigestSubscription tracks whether a user gets the weekly digest and when it last went out
+type DigestSubscription struct {
+	UserID         string     `firestore:"user_id" json:"-"`
+	Enabled        bool       `firestore:"enabled" json:"enabled"`
+	LastSentAt     *time.Time `firestore:"last_sent_at" json:"last_sent_at,omitempty"`
+	UnsubscribedAt *time.Time `firestore:"unsubscribed_at" json:"unsubscribed_at,omitempty"`
+}
+
+// UpdateDigestRequest turns the weekly digest on or off
+type UpdateDigestRequest struct {
+	Enabled bool `json:"enabled"`
+}
+
+// DigestHotspot is one hotspot line in a digest email
+type DigestHotspot struct {
+	Name       string
+	When       string
+	City       string
+	DistanceKm float64
+	Friend     string // Friend attending, for friend activity
+}
+
+// DigestContent is what the digest templates render
+type DigestContent struct {
+	Nickname       string
+	Upcoming       []DigestHotspot
+	Recommended    []DigestHotspot
+	FriendActivity []DigestHotspot
+	UnsubscribeURL string
+}
+
+// DigestRun summarizes one pass of the digest job
+type DigestRun struct {
+	Checked int `json:"checked"`
+	Queued  int `json:"queued"`  // Send jobs queued; each skips the user if there is nothing to report
+	Skipped int `json:"skipped"` // Opted out or sent within the last week
+	Failed  int `json:"failed"`
+}
@@ -0,0 +1,31 @@
+// Domain event models published on the internal event bus
+package models
+
+import "time"
+
+// Domain event types
+const (
+	DomainEventHotspotCreated       = "hotspot.created"
+	DomainEventHotspotUpdated       = "hotspot.updated"
+	DomainEventHotspotDeleted       = "hotspot.deleted"
+	DomainEventHotspotCancelled     = "hotspot.cancelled" // The host or its source feed called it off; follows updated
+	DomainEventHotspotPublished     = "hotspot.published" // Went live: created as a non-draft or a draft was published; follows created or updated
+	DomainEventUserJoined           = "hotspot.user_joined"
+	DomainEventUserLeft             = "hotspot.user_left"
+	DomainEventUserCheckedIn        = "hotspot.user_checked_in"
+	DomainEventUserCheckedInQR      = "hotspot.user_checked_in_qr"    // Presence proven with the host's QR code; follows user_checked_in on a first check-in
+	DomainEventOwnershipTransferred = "hotspot.ownership_transferred" // ActorID is the previous host, Hotspot.CreatedBy the new one
+	DomainEventFriendAccepted       = "friend.accepted"
+)
+
+// DomainEvent records something that happened so other services can react to it.
+// It is serialized as JSON when the bus runs on Redis Streams.
+type DomainEvent struct {
+	ID           string    `json:"id"`
+	Type         string    `json:"type"`
+	ActorID      string    `json:"actor_id,omitempty"`       // User who caused the event
+	TargetUserID string    `json:"target_user_id,omitempty"` // Other user involved, e.g. the requester of an accepted friend request
+	HotspotID    string    `json:"hotspot_id,omitempty"`
+	Hotspot      *Hotspot  `json:"hotspot,omitempty"` // Snapshot after the change, for hotspot events
+	OccurredAt   time.Time `json:"occurred_at"`
+}
@@ -0,0 +1,9 @@
+// Email models for outbound messages
+package models
+
+// EmailMessage is a plain-text email
+type EmailMessage struct {
+	To      string
+	Subject string
+	Body    string
+}
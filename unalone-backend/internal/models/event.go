@@ -0,0 +1,31 @@
+// Realtime user event models delivered over the per-user WebSocket
+package models
+
+import "time"
+
+// User event types that are not notifications; notification events use the notification type
+const (
+	UserEventPointsAwarded  = "points_awarded"
+	UserEventOnboardingStep = "onboarding_step" // Data is the updated OnboardingProgress
+)
+
+// UserEvent is a realtime event pushed to a single user
+type UserEvent struct {
+	Type      string      `json:"type"`
+	Data      interface{} `json:"data"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// PointsAwardedEvent is the payload of a points_awarded event
+type PointsAwardedEvent struct {
+	Delta  int    `json:"delta"`
+	Points int    `json:"points"`
+	Level  int    `json:"level"`
+	Reason string `json:"reason"`
+}
+
+// WebSocketTicket authenticates one WebSocket handshake in place of the access token
+type WebSocketTicket struct {
+	Ticket    string    `json:"ticket"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,79 @@
+// Models for importing public events from external ICS and JSON feeds
+package models
+
+import "time"
+
+// ExternalHostID is the host of imported hotspots; nobody can edit them in the app
+const ExternalHostID = "external"
+
+// Import feed formats
+const (
+	ImportFormatICS  = "ics"
+	ImportFormatJSON = "json"
+)
+
+// ExternalSource links an imported hotspot back to the listing it came from
+type ExternalSource struct {
+	FeedID     string    `firestore:"feed_id" json:"feed_id"`
+	Provider   string    `firestore:"provider" json:"provider"`   // e.g. meetup or eventbrite
+	SourceID   string    `firestore:"source_id" json:"source_id"` // Event UID in the feed; imports are deduplicated on provider + source ID
+	URL        string    `firestore:"url" json:"url,omitempty"`   // Where to RSVP on the original site
+	ImportedAt time.Time `firestore:"imported_at" json:"imported_at"`
+}
+
+// ImportFeed is a public event feed polled for new hotspots
+type ImportFeed struct {
+	ID        string          `firestore:"id" json:"id"`
+	Name      string          `firestore:"name" json:"name"` // Shown as the host of imported hotspots
+	Provider  string          `firestore:"provider" json:"provider"`
+	URL       string          `firestore:"url" json:"url"`
+	Format    string          `firestore:"format" json:"format"`
+	Category  HotspotCategory `firestore:"category" json:"category"`
+	City      string          `firestore:"city" json:"city"` // Used when an event has no city of its own
+	Country   string          `firestore:"country" json:"country"`
+	CreatedBy string          `firestore:"created_by" json:"created_by"`
+	CreatedAt time.Time       `firestore:"created_at" json:"created_at"`
+	LastRun   *ImportRun      `firestore:"last_run" json:"last_run,omitempty"`
+}
+
+// CreateImportFeedRequest registers a feed to import from
+type CreateImportFeedRequest struct {
+	Name     string          `json:"name" binding:"required,min=2,max=100"`
+	Provider string          `json:"provider" binding:"required,max=50"`
+	URL      string          `json:"url" binding:"required,url"`
+	Format   string          `json:"format" binding:"required,oneof=ics json"`
+	Category HotspotCategory `json:"category" binding:"max=50"` // Defaults to event
+	City     string          `json:"city" binding:"required,max=100"`
+	Country  string          `json:"country" binding:"required,max=100"`
+}
+
+// ImportRun summarizes one pass over a feed
+type ImportRun struct {
+	FeedID     string    `firestore:"feed_id" json:"feed_id"`
+	StartedAt  time.Time `firestore:"started_at" json:"started_at"`
+	FinishedAt time.Time `firestore:"finished_at" json:"finished_at"`
+	Fetched    int       `firestore:"fetched" json:"fetched"`
+	Created    int       `firestore:"created" json:"created"`
+	Updated    int       `firestore:"updated" json:"updated"`
+	Cancelled  int       `firestore:"cancelled" json:"cancelled"`
+	Unchanged  int       `firestore:"unchanged" json:"unchanged"`
+	Skipped    int       `firestore:"skipped" json:"skipped"` // Past, too far ahead, or missing a start time or coordinates
+	Error      string    `firestore:"error" json:"error,omitempty"`
+}
+
+// ExternalEvent is one event read from a feed, before it becomes a hotspot
+type ExternalEvent struct {
+	SourceID    string     `json:"id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	URL         string     `json:"url"`
+	Start       *time.Time `json:"start"`
+	End         *time.Time `json:"end"`
+	Latitude    *float64   `json:"latitude"`
+	Longitude   *float64   `json:"longitude"`
+	Venue       string     `json:"venue"`
+	Address     string     `json:"address"`
+	City        string     `json:"city"`
+	Country     string     `json:"country"`
+	Cancelled   bool       `json:"cancelled"`
+}
@@ -0,0 +1,54 @@
+// A/B experiment models
+package models
+
+import "time"
+
+// ExperimentVariant is one arm of an experiment; users are split in proportion to Weight
+type ExperimentVariant struct {
+	Name   string `firestore:"name" json:"name" binding:"required,min=1,max=50"`
+	Weight int    `firestore:"weight" json:"weight" binding:"min=1,max=1000"`
+}
+
+// Experiment splits users between variants, e.g. of a recommendation algorithm or onboarding flow
+type Experiment struct {
+	Key            string              `firestore:"key" json:"key"`
+	Description    string              `firestore:"description" json:"description,omitempty"`
+	Variants       []ExperimentVariant `firestore:"variants" json:"variants"`
+	TrafficPercent int                 `firestore:"traffic_percent" json:"traffic_percent"` // Share of users enrolled; the rest get no variant
+	IsActive       bool                `firestore:"is_active" json:"is_active"`
+	CreatedAt      time.Time           `firestore:"created_at" json:"created_at"`
+	UpdatedAt      time.Time           `firestore:"updated_at" json:"updated_at"`
+}
+
+// UpsertExperimentRequest creates or updates an experiment (admin)
+type UpsertExperimentRequest struct {
+	Description    string              `json:"description" binding:"max=500"`
+	Variants       []ExperimentVariant `json:"variants" binding:"required,min=2,max=10,dive"`
+	TrafficPercent *int                `json:"traffic_percent" binding:"omitempty,min=0,max=100"` // Default 100
+	IsActive       *bool               `json:"is_active"`
+}
+
+// ExperimentAssignments maps each active experiment the user is enrolled in to their variant
+type ExperimentAssignments struct {
+	Variants map[string]string `json:"variants"`
+}
+
+// RecordExposureRequest logs that the user saw their variant of an experiment
+type RecordExposureRequest struct {
+	ExperimentKey string `json:"experiment_key" binding:"required,max=50"`
+}
+
+// ExperimentExposure is the first time a user saw their variant
+type ExperimentExposure struct {
+	ExperimentKey string    `firestore:"experiment_key" json:"experiment_key"`
+	UserID        string    `firestore:"user_id" json:"-"`
+	Variant       string    `firestore:"variant" json:"variant"`
+	ExposedAt     time.Time `firestore:"exposed_at" json:"exposed_at"`
+}
+
+// ExperimentResults counts exposed users per variant
+type ExperimentResults struct {
+	Experiment Experiment     `json:"experiment"`
+	Exposures  map[string]int `json:"exposures"` // Variant -> users exposed
+	Total      int            `json:"total"`
+}
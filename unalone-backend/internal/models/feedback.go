@@ -0,0 +1,52 @@
+// Post-event feedback models
+package models
+
+import "time"
+
+// Issues attendees can flag in post-event feedback
+const (
+	FeedbackIssueSafety     = "safety"
+	FeedbackIssueHarassment = "harassment"
+	FeedbackIssueMisleading = "misleading"
+	FeedbackIssueHostNoShow = "host_no_show"
+	FeedbackIssueOther      = "other"
+)
+
+// FeedbackRequest is a pending prompt asking an attendee about a hotspot that ended
+type FeedbackRequest struct {
+	HotspotID   string    `firestore:"hotspot_id" json:"hotspot_id"`
+	HotspotName string    `firestore:"hotspot_name" json:"hotspot_name"`
+	UserID      string    `firestore:"user_id" json:"user_id"`
+	RequestedAt time.Time `firestore:"requested_at" json:"requested_at"`
+	ExpiresAt   time.Time `firestore:"expires_at" json:"expires_at"`
+}
+
+// HotspotFeedback is an attendee's response to a feedback prompt
+type HotspotFeedback struct {
+	ID        string    `firestore:"id" json:"id"`
+	HotspotID string    `firestore:"hotspot_id" json:"hotspot_id"`
+	HostID    string    `firestore:"host_id" json:"host_id"`
+	UserID    string    `firestore:"user_id" json:"user_id"`
+	Attended  bool      `firestore:"attended" json:"attended"`
+	Rating    int       `firestore:"rating" json:"rating,omitempty"` // 1-5, only when attended
+	Comment   string    `firestore:"comment" json:"comment,omitempty"`
+	Issues    []string  `firestore:"issues" json:"issues,omitempty"`
+	CreatedAt time.Time `firestore:"created_at" json:"created_at"`
+}
+
+// SubmitFeedbackRequest represents an attendee's answers to a feedback prompt
+type SubmitFeedbackRequest struct {
+	Attended *bool    `json:"attended" binding:"required"`
+	Rating   int      `json:"rating" binding:"omitempty,min=1,max=5"`
+	Comment  string   `json:"comment" binding:"max=1000"`
+	Issues   []string `json:"issues" binding:"max=5,dive,oneof=safety harassment misleading host_no_show other"`
+}
+
+// FeedbackSummary aggregates feedback for the host dashboard
+type FeedbackSummary struct {
+	Responses     int            `firestore:"responses" json:"responses"`
+	Attended      int            `firestore:"attended" json:"attended"`
+	NoShows       int            `firestore:"no_shows" json:"no_shows"` // Responders who said they did not go
+	AverageRating float64        `firestore:"average_rating" json:"average_rating"`
+	IssueCounts   map[string]int `firestore:"issue_counts" json:"issue_counts"`
+}
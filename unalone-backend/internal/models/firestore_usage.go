@@ -0,0 +1,29 @@
+// Firestore usage models for read and write budgets
+package models
+
+// Firestore budget states, from the share of a daily budget used
+const (
+	FirestoreBudgetOK          = "ok"
+	FirestoreBudgetApproaching = "approaching" // At least 80% used
+	FirestoreBudgetExceeded    = "exceeded"
+)
+
+// FirestoreDailyUsage is the documents read and written on one UTC day
+type FirestoreDailyUsage struct {
+	Date        string `json:"date"` // YYYY-MM-DD
+	Reads       int64  `json:"reads"`
+	Writes      int64  `json:"writes"`
+	Rejected    int64  `json:"rejected"`     // Queries refused for a missing limit or a spent request budget
+	ReadBudget  int64  `json:"read_budget"`  // 0 when reads have no daily budget
+	WriteBudget int64  `json:"write_budget"` // 0 when writes have no daily budget
+	ReadState   string `json:"read_state"`   // ok, approaching or exceeded
+	WriteState  string `json:"write_state"`
+}
+
+// FirestoreUsageReport is returned to admins, today first
+type FirestoreUsageReport struct {
+	Shared             bool                  `json:"shared"` // False when Redis is unavailable and counts cover this instance only
+	MaxReadsPerRequest int                   `json:"max_reads_per_request"`
+	MaxQueryLimit      int                   `json:"max_query_limit"`
+	Days               []FirestoreDailyUsage `json:"days"`
+}
@@ -0,0 +1,32 @@
+// Friend list models for organizing friends into named groups
+package models
+
+import "time"
+
+// FriendList is a named group of a user's friends, e.g. "running buddies"
+type FriendList struct {
+	ID        string    `firestore:"id" json:"id"`
+	OwnerID   string    `firestore:"owner_id" json:"owner_id"`
+	Name      string    `firestore:"name" json:"name"`
+	MemberIDs []string  `firestore:"member_ids" json:"member_ids"`
+	CreatedAt time.Time `firestore:"created_at" json:"created_at"`
+	UpdatedAt time.Time `firestore:"updated_at" json:"updated_at"`
+}
+
+// CreateFriendListRequest represents the request to create a friend list
+type CreateFriendListRequest struct {
+	Name      string   `json:"name" binding:"required,min=1,max=50"`
+	MemberIDs []string `json:"member_ids" binding:"max=500"`
+}
+
+// UpdateFriendListRequest renames a list or replaces its members
+type UpdateFriendListRequest struct {
+	Name      *string  `json:"name" binding:"omitempty,min=1,max=50"`
+	MemberIDs []string `json:"member_ids" binding:"omitempty,max=500"`
+}
+
+// InviteToHotspotRequest invites a friend list and/or individual friends to a hotspot
+type InviteToHotspotRequest struct {
+	FriendListID string   `json:"friend_list_id"`
+	UserIDs      []string `json:"user_ids" binding:"max=500"`
+}
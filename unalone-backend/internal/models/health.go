@@ -0,0 +1,41 @@
+// Health models for dependency status and degraded modes
+package models
+
+import "time"
+
+// Dependency statuses
+const (
+	DependencyUp   = "up"
+	DependencyDown = "down"
+)
+
+// Service modes reported by the readiness check and the X-Service-Mode header
+const (
+	ServiceModeNormal      = "normal"
+	ServiceModeDegraded    = "degraded"    // An optional dependency is down and its fallback is serving
+	ServiceModeUnavailable = "unavailable" // A required dependency is down; only degraded paths work
+)
+
+// DependencyHealth is the state of one external dependency
+type DependencyHealth struct {
+	Name        string     `json:"name"`
+	Status      string     `json:"status"`
+	Required    bool       `json:"required"`              // The instance is not ready without it
+	Forced      bool       `json:"forced,omitempty"`      // Marked down by an admin
+	Degradation string     `json:"degradation,omitempty"` // What changes while it is down
+	LastError   string     `json:"last_error,omitempty"`
+	CheckedAt   *time.Time `json:"checked_at,omitempty"`
+	Since       *time.Time `json:"since,omitempty"` // When it entered its current status
+}
+
+// HealthReport is the instance's mode and the state of each dependency
+type HealthReport struct {
+	Status       string             `json:"status"`
+	Degraded     []string           `json:"degraded"` // Dependencies that are down
+	Dependencies []DependencyHealth `json:"dependencies"`
+}
+
+// ForceDependencyRequest marks a dependency down on every instance, or clears that
+type ForceDependencyRequest struct {
+	Down bool `json:"down"`
+}
@@ -0,0 +1,37 @@
+// Attendance history models for past hotspots and the people met there
+package models
+
+import "time"
+
+// AttendanceRecord is a hotspot the user attended, written once the hotspot has ended
+type AttendanceRecord struct {
+	HotspotID     string          `firestore:"hotspot_id" json:"hotspot_id"`
+	HotspotName   string          `firestore:"hotspot_name" json:"hotspot_name"`
+	Category      HotspotCategory `firestore:"category" json:"category"`
+	City          string          `firestore:"city" json:"city,omitempty"`
+	HostID        string          `firestore:"host_id" json:"host_id"`
+	ScheduledTime *time.Time      `firestore:"scheduled_time" json:"scheduled_time,omitempty"`
+	EndedAt       time.Time       `firestore:"ended_at" json:"ended_at"`
+	AttendeeCount int             `firestore:"attendee_count" json:"attendee_count"`
+}
+
+// CoAttendance is an edge between two users who attended the same hotspots
+type CoAttendance struct {
+	UserID          string    `firestore:"user_id" json:"user_id"`
+	OtherUserID     string    `firestore:"other_user_id" json:"other_user_id"`
+	TimesMet        int       `firestore:"times_met" json:"times_met"`
+	LastMetAt       time.Time `firestore:"last_met_at" json:"last_met_at"`
+	LastHotspotID   string    `firestore:"last_hotspot_id" json:"last_hotspot_id"`
+	LastHotspotName string    `firestore:"last_hotspot_name" json:"last_hotspot_name"`
+}
+
+// PersonMet is someone the user attended a hotspot with, with their current relationship
+type PersonMet struct {
+	UserID          string       `json:"user_id"`
+	Nickname        string       `json:"nickname"`
+	TimesMet        int          `json:"times_met"`
+	LastMetAt       time.Time    `json:"last_met_at"`
+	LastHotspotID   string       `json:"last_hotspot_id"`
+	LastHotspotName string       `json:"last_hotspot_name"`
+	Relationship    FriendStatus `json:"relationship"`
+}
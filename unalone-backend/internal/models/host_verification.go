@@ -0,0 +1,15 @@
+// Host verification models
+package models
+
+import "time"
+
+// HostVerification reports whether a user qualifies for the verified host badge and why
+type HostVerification struct {
+	UserID           string    `json:"user_id"`
+	Verified         bool      `json:"verified"`
+	PhoneVerified    bool      `json:"phone_verified"`
+	SuccessfulEvents int       `json:"successful_events"` // Ended hotspots that had at least one guest
+	RequiredEvents   int       `json:"required_events"`
+	RecentReports    int       `json:"recent_reports"` // Reports against the user inside the review window
+	CheckedAt        time.Time `json:"checked_at"`
+}
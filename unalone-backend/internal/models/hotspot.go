@@ -18,7 +18,8 @@ type HotspotAddress struct {
 	PostalCode string `firestore:"postal_code" json:"postal_code"`
 }
 
-// HotspotCategory represents the type of hotspot
+// HotspotCategory represents the type of hotspot. The constants below seed the
+// default taxonomy; the authoritative list is served by CategoryService.
 type HotspotCategory string
 
 const (
@@ -39,47 +40,94 @@ const (
 
 // Hotspot represents a location where users can meet
 type Hotspot struct {
-	ID                string          `firestore:"id" json:"id"`
-	Name              string          `firestore:"name" json:"name"`
-	Description       string          `firestore:"description" json:"description"`
-	Category          HotspotCategory `firestore:"category" json:"category"`
-	Location          HotspotLocation `firestore:"location" json:"location"`
-	Address           HotspotAddress  `firestore:"address" json:"address"`
-	CreatedBy         string          `firestore:"created_by" json:"created_by"`
-	CreatedByNickname string          `firestore:"created_by_nickname" json:"created_by_nickname"`
-	MaxCapacity       int             `firestore:"max_capacity" json:"max_capacity"`
-	CurrentOccupancy  int             `firestore:"current_occupancy" json:"current_occupancy"`
-	IsActive          bool            `firestore:"is_active" json:"is_active"`
-	IsPublic          bool            `firestore:"is_public" json:"is_public"`
-	Tags              []string        `firestore:"tags" json:"tags"`
-	ScheduledTime     *time.Time      `firestore:"scheduled_time" json:"scheduled_time,omitempty"`
-	EndTime           *time.Time      `firestore:"end_time" json:"end_time,omitempty"`
-	ImageURL          string          `firestore:"image_url" json:"image_url"`
-	Attendees         []string        `firestore:"attendees" json:"attendees"`
-	CreatedAt         time.Time       `firestore:"created_at" json:"created_at"`
-	UpdatedAt         time.Time       `firestore:"updated_at" json:"updated_at"`
+	ID                string           `firestore:"id" json:"id"`
+	Name              string           `firestore:"name" json:"name"`
+	Description       string           `firestore:"description" json:"description"`
+	Category          HotspotCategory  `firestore:"category" json:"category"`
+	Subcategory       string           `firestore:"subcategory" json:"subcategory,omitempty"`
+	Location          HotspotLocation  `firestore:"location" json:"location"`
+	Address           HotspotAddress   `firestore:"address" json:"address"`
+	CityKey           string           `firestore:"city_key" json:"-"` // Normalized Address.City, indexed for browse-by-city
+	VenueID           string           `firestore:"venue_id" json:"venue_id,omitempty"`
+	OrgID             string           `firestore:"org_id" json:"org_id,omitempty"`   // The host's organization; only its members see the hotspot
+	ClubID            string           `firestore:"club_id" json:"club_id,omitempty"` // Club hosting the hotspot; its followers are told when it is published
+	CreatedBy         string           `firestore:"created_by" json:"created_by"`
+	CreatedByNickname string           `firestore:"created_by_nickname" json:"created_by_nickname"`
+	HostVerified      bool             `firestore:"-" json:"host_verified"` // Computed per response, see services.HostVerificationService
+	MaxCapacity       int              `firestore:"max_capacity" json:"max_capacity"`
+	CurrentOccupancy  int              `firestore:"current_occupancy" json:"current_occupancy"`
+	IsActive          bool             `firestore:"is_active" json:"is_active"`
+	IsPublic          bool             `firestore:"is_public" json:"is_public"`
+	IsDraft           bool             `firestore:"is_draft" json:"is_draft"` // Hidden from search until published
+	PublishedAt       *time.Time       `firestore:"published_at" json:"published_at,omitempty"`
+	Tags              []string         `firestore:"tags" json:"tags"`
+	ScheduledTime     *time.Time       `firestore:"scheduled_time" json:"scheduled_time,omitempty"`
+	EndTime           *time.Time       `firestore:"end_time" json:"end_time,omitempty"`
+	ImageURL          string           `firestore:"image_url" json:"image_url"`
+	Attendees         []string         `firestore:"attendees" json:"attendees"`
+	CheckedIn         []string         `firestore:"checked_in" json:"checked_in,omitempty"`       // Attendees who checked in on site
+	QRCheckedIn       []string         `firestore:"qr_checked_in" json:"qr_checked_in,omitempty"` // Attendees who scanned the host's check-in QR code
+	CoHosts           []string         `firestore:"co_hosts" json:"co_hosts,omitempty"`           // Attendees first in line to take over when the host leaves
+	OwnershipOffer    *OwnershipOffer  `firestore:"ownership_offer" json:"ownership_offer,omitempty"`
+	FriendListID      string           `firestore:"friend_list_id" json:"friend_list_id,omitempty"` // When set, only the host's list members and invitees can see or join
+	InvitedUserIDs    []string         `firestore:"invited_user_ids" json:"invited_user_ids,omitempty"`
+	Audience          *HotspotAudience `firestore:"audience" json:"audience,omitempty"`         // Who may join, e.g. women-only or 18-25
+	EphemeralChat     bool             `firestore:"ephemeral_chat" json:"ephemeral_chat"`       // Chat messages expire 24 hours after they are sent
+	TicketTiers       []TicketTier     `firestore:"ticket_tiers" json:"ticket_tiers,omitempty"` // When set, joining needs a paid ticket
+	FlaggedForReview  bool             `firestore:"flagged_for_review" json:"-"`                // Set by SOS alerts and safety reports for moderators
+	FlaggedAt         *time.Time       `firestore:"flagged_at" json:"-"`
+	Sequence          int              `firestore:"sequence" json:"sequence"`                   // Incremented on schedule changes for calendar clients
+	ArchivedAt        *time.Time       `firestore:"archived_at" json:"archived_at,omitempty"`   // Set once a long-ended hotspot is dropped from search
+	CancelledAt       *time.Time       `firestore:"cancelled_at" json:"cancelled_at,omitempty"` // Set when the host or its source feed cancelled it; cleared if it is reactivated
+	Version           int64            `firestore:"version" json:"version"`                     // Incremented on every write; updates must send the version they were based on
+	External          *ExternalSource  `firestore:"external" json:"external,omitempty"`         // Set on hotspots imported from public event feeds
+	CreatedAt         time.Time        `firestore:"created_at" json:"created_at"`
+	UpdatedAt         time.Time        `firestore:"updated_at" json:"updated_at"`
+}
+
+// OwnershipOffer is a pending hand-over of a hotspot to an attendee, who has to accept it
+type OwnershipOffer struct {
+	ToUserID  string    `firestore:"to_user_id" json:"to_user_id"`
+	OfferedBy string    `firestore:"offered_by" json:"offered_by"`
+	OfferedAt time.Time `firestore:"offered_at" json:"offered_at"`
+	ExpiresAt time.Time `firestore:"expires_at" json:"expires_at"`
+}
+
+// HotspotMemberRequest names an attendee for co-host and ownership changes
+type HotspotMemberRequest struct {
+	UserID string `json:"user_id" binding:"required"`
 }
 
 // CreateHotspotRequest represents the request to create a new hotspot
 type CreateHotspotRequest struct {
-	Name          string          `json:"name" binding:"required,min=3,max=100"`
-	Description   string          `json:"description" binding:"required,min=10,max=500"`
-	Category      HotspotCategory `json:"category" binding:"required,oneof=cafe restaurant park gym library beach bar event study sports shopping entertainment other"`
-	Location      HotspotLocation `json:"location" binding:"required"`
-	Address       HotspotAddress  `json:"address" binding:"required"`
-	MaxCapacity   int             `json:"max_capacity" binding:"min=1,max=1000"`
-	IsPublic      bool            `json:"is_public"`
-	Tags          []string        `json:"tags" binding:"max=10"`
-	ScheduledTime *time.Time      `json:"scheduled_time"`
-	EndTime       *time.Time      `json:"end_time"`
-	ImageURL      string          `json:"image_url" binding:"omitempty,url"`
+	Name          string            `json:"name" binding:"required,min=3,max=100"`
+	Description   string            `json:"description" binding:"required,min=10,max=500"`
+	Category      HotspotCategory   `json:"category" binding:"required,max=50"` // Validated against the category taxonomy
+	Subcategory   string            `json:"subcategory" binding:"max=50"`
+	Location      HotspotLocation   `json:"location" binding:"required"`
+	Address       HotspotAddress    `json:"address" binding:"required"`
+	MaxCapacity   int               `json:"max_capacity" binding:"min=1,max=1000"`
+	IsPublic      bool              `json:"is_public"`
+	IsDraft       bool              `json:"is_draft"`
+	Tags          []string          `json:"tags" binding:"max=10"`
+	ScheduledTime *time.Time        `json:"scheduled_time"`
+	EndTime       *time.Time        `json:"end_time"`
+	ImageURL      string            `json:"image_url" binding:"omitempty,url"`
+	FriendListID  string            `json:"friend_list_id"` // Restrict the hotspot to one of the host's friend lists
+	Audience      *HotspotAudience  `json:"audience"`
+	EphemeralChat bool              `json:"ephemeral_chat"`
+	VenueID       string            `json:"venue_id"`                          // Link to a known venue; otherwise one is matched or created
+	VenueName     string            `json:"venue_name" binding:"max=100"`      // Place name for venue matching; defaults to the street address
+	ClubID        string            `json:"club_id"`                           // Host on behalf of a club you are an admin of
+	TicketTiers   []TicketTierInput `json:"ticket_tiers" binding:"max=5,dive"` // Sell tickets; joining then needs one
 }
 
 // UpdateHotspotRequest represents the request to update a hotspot
 type UpdateHotspotRequest struct {
 	Name          *string          `json:"name" binding:"omitempty,min=3,max=100"`
 	Description   *string          `json:"description" binding:"omitempty,min=10,max=500"`
-	Category      *HotspotCategory `json:"category" binding:"omitempty,oneof=cafe restaurant park gym library beach bar event study sports shopping entertainment other"`
+	Category      *HotspotCategory `json:"category" binding:"omitempty,max=50"` // Validated against the category taxonomy
+	Subcategory   *string          `json:"subcategory" binding:"omitempty,max=50"`
 	Location      *HotspotLocation `json:"location"`
 	Address       *HotspotAddress  `json:"address"`
 	MaxCapacity   *int             `json:"max_capacity" binding:"omitempty,min=1,max=1000"`
@@ -89,6 +137,16 @@ type UpdateHotspotRequest struct {
 	EndTime       *time.Time       `json:"end_time"`
 	ImageURL      *string          `json:"image_url" binding:"omitempty,url"`
 	IsActive      *bool            `json:"is_active"`
+	FriendListID  *string          `json:"friend_list_id"` // Empty string opens the hotspot up again
+	Audience      *HotspotAudience `json:"audience"`       // Send {} to lift the restriction
+	EphemeralChat *bool            `json:"ephemeral_chat"` // Applies to messages sent afterwards
+	Version       *int64           `json:"version"`        // Version being edited; the If-Match header takes precedence
+}
+
+// CloneHotspotRequest represents the request to clone a hotspot with a new schedule
+type CloneHotspotRequest struct {
+	ScheduledTime *time.Time `json:"scheduled_time" binding:"required"`
+	EndTime       *time.Time `json:"end_time"`
 }
 
 // JoinHotspotRequest represents the request to join a hotspot
@@ -96,6 +154,18 @@ type JoinHotspotRequest struct {
 	HotspotID string `json:"hotspot_id" binding:"required"`
 }
 
+// BatchHotspotsRequest asks for several hotspots by ID, e.g. when expanding a map cluster
+type BatchHotspotsRequest struct {
+	IDs []string `json:"ids" binding:"required,min=1,max=50,dive,required"`
+}
+
+// BatchHotspotsResponse lists the found hotspots in request order; Missing holds IDs that
+// do not exist or are not visible to the caller
+type BatchHotspotsResponse struct {
+	Hotspots []Hotspot `json:"hotspots"`
+	Missing  []string  `json:"missing"`
+}
+
 // HotspotSearchRequest represents search parameters for hotspots
 type HotspotSearchRequest struct {
 	Latitude          float64          `json:"latitude" binding:"required"`
@@ -109,6 +179,8 @@ type HotspotSearchRequest struct {
 	EndTime           *time.Time       `json:"end_time"`
 	Limit             int              `json:"limit" binding:"omitempty,min=1,max=100"`
 	Offset            int              `json:"offset" binding:"min=0"`
+	VerifiedHostsOnly bool             `json:"verified_hosts_only"`
+	OrgID             string           `json:"-"` // The searcher's organization, set by the server
 }
 
 // HotspotSearchResponse represents the response for hotspot search
@@ -120,28 +192,118 @@ type HotspotSearchResponse struct {
 
 // HotspotWithDistance includes distance information
 type HotspotWithDistance struct {
+	Hotspot  Hotspot         `json:"hotspot"`
+	Distance float64         `json:"distance"`         // in kilometers
+	Bearing  float64         `json:"bearing"`          // degrees clockwise from north, from the search origin
+	Travel   *TravelEstimate `json:"travel,omitempty"` // Only when requested with travel=true
+}
+
+// TravelEstimate is the time to reach a hotspot from the search origin
+type TravelEstimate struct {
+	WalkingMinutes int    `json:"walking_minutes"` // -1 when no route was found
+	DrivingMinutes int    `json:"driving_minutes"`
+	Source         string `json:"source"` // Routing provider, or "estimate" for straight-line approximations
+}
+
+// TrendingHotspot represents a hotspot ranked by recent activity
+type TrendingHotspot struct {
 	Hotspot  Hotspot `json:"hotspot"`
 	Distance float64 `json:"distance"` // in kilometers
+	Score    float64 `json:"score"`
+	Joins    int     `json:"recent_joins"`
+	Messages int     `json:"recent_messages"`
+	Views    int     `json:"recent_views"`
 }
 
+// CityHotspotCount represents the number of browsable hotspots in a city
+type CityHotspotCount struct {
+	City    string `json:"city"`
+	Country string `json:"country"`
+	Count   int    `json:"count"`
+}
+
+// Hotspot activity actions
+const (
+	ActivityCreated              = "created"
+	ActivityUpdated              = "updated"
+	ActivityPublished            = "published"
+	ActivityDeleted              = "deleted"
+	ActivityArchived             = "archived"
+	ActivityJoined               = "joined"
+	ActivityLeft                 = "left"
+	ActivityCheckedIn            = "checked_in"
+	ActivityCheckedInQR          = "checked_in_qr"
+	ActivityInvited              = "invited"
+	ActivityOwnershipOffered     = "ownership_offered"
+	ActivityOwnershipDeclined    = "ownership_declined"
+	ActivityOwnershipTransferred = "ownership_transferred"
+	ActivityCoHostAdded          = "cohost_added"
+	ActivityCoHostRemoved        = "cohost_removed"
+)
+
 // HotspotActivity represents user activity at a hotspot
 type HotspotActivity struct {
 	ID        string                 `firestore:"id" json:"id"`
 	HotspotID string                 `firestore:"hotspot_id" json:"hotspot_id"`
-	UserID    string                 `firestore:"user_id" json:"user_id"`
-	Action    string                 `firestore:"action" json:"action"` // joined, left, created, updated
+	UserID    string                 `firestore:"user_id" json:"user_id"` // Empty for system changes such as archival
+	Action    string                 `firestore:"action" json:"action"`   // One of the Activity* actions
 	Timestamp time.Time              `firestore:"timestamp" json:"timestamp"`
 	Metadata  map[string]interface{} `firestore:"metadata" json:"metadata,omitempty"`
 }
 
+// HotspotActivityResponse is one page of a hotspot's activity log, newest first
+type HotspotActivityResponse struct {
+	Activities []*HotspotActivity `json:"activities"`
+	Total      int                `json:"total"`
+	HasMore    bool               `json:"has_more"`
+}
+
 // HotspotStats represents statistics for a hotspot
 type HotspotStats struct {
 	HotspotID      string         `json:"hotspot_id"`
-	TotalVisits    int            `json:"total_visits"`
-	UniqueVisitors int            `json:"unique_visitors"`
+	TotalVisits    int            `json:"total_visits"`    // detail views, deduplicated per user per day
+	UniqueVisitors int            `json:"unique_visitors"` // distinct users who viewed the detail page
+	Impressions    int            `json:"impressions"`     // search appearances, deduplicated per user per day
 	AverageRating  float64        `json:"average_rating"`
 	TotalRatings   int            `json:"total_ratings"`
 	PopularTimes   map[string]int `json:"popular_times"` // hour -> count
+	DailyViews     map[string]int `json:"daily_views"`   // YYYY-MM-DD -> count
+}
+
+// HotspotAnalytics is the host dashboard document produced by the nightly aggregation job
+type HotspotAnalytics struct {
+	HotspotID    string            `firestore:"hotspot_id" json:"hotspot_id"`
+	Funnel       AnalyticsFunnel   `firestore:"funnel" json:"funnel"`
+	Retention    AttendeeRetention `firestore:"retention" json:"retention"`
+	PopularTimes map[string]int    `firestore:"popular_times" json:"popular_times"` // hour -> views
+	Chat         ChatEngagement    `firestore:"chat" json:"chat"`
+	Feedback     FeedbackSummary   `firestore:"feedback" json:"feedback"`
+	GeneratedAt  time.Time         `firestore:"generated_at" json:"generated_at"`
+}
+
+// AnalyticsFunnel tracks how many users progress from viewing to joining to checking in
+type AnalyticsFunnel struct {
+	Views             int     `firestore:"views" json:"views"` // distinct viewers
+	Joins             int     `firestore:"joins" json:"joins"` // distinct joiners, excluding the host
+	CheckIns          int     `firestore:"check_ins" json:"check_ins"`
+	ViewToJoinRate    float64 `firestore:"view_to_join_rate" json:"view_to_join_rate"`
+	JoinToCheckInRate float64 `firestore:"join_to_check_in_rate" json:"join_to_check_in_rate"`
+}
+
+// AttendeeRetention measures how many attendees come back across a host's events
+type AttendeeRetention struct {
+	HostedEvents       int     `firestore:"hosted_events" json:"hosted_events"`
+	TotalAttendees     int     `firestore:"total_attendees" json:"total_attendees"`
+	ReturningAttendees int     `firestore:"returning_attendees" json:"returning_attendees"` // attended 2+ events
+	ReturnRate         float64 `firestore:"return_rate" json:"return_rate"`
+}
+
+// ChatEngagement summarizes chat activity in a hotspot
+type ChatEngagement struct {
+	Messages            int     `firestore:"messages" json:"messages"`
+	ActiveChatters      int     `firestore:"active_chatters" json:"active_chatters"`
+	MessagesPerAttendee float64 `firestore:"messages_per_attendee" json:"messages_per_attendee"`
+	ParticipationRate   float64 `firestore:"participation_rate" json:"participation_rate"` // chatters / attendees
 }
 
 // === Geospatial Optimization Models ===
@@ -158,6 +320,21 @@ type HotspotCluster struct {
 	ZoomLevel      int             `json:"zoom_level"`
 	Radius         float64         `json:"radius_km"`
 	Hotspots       []string        `json:"hotspot_ids,omitempty"` // Only included when expanded
+	// Previews holds the top public hotspots when ClusterConfig.IncludeHotspots is set
+	Previews []ClusterHotspotPreview `json:"previews,omitempty"`
+}
+
+// ClusterHotspotPreview is a compact hotspot summary embedded in a cluster so the map can
+// show a few entries without fetching each hotspot
+type ClusterHotspotPreview struct {
+	ID               string          `json:"id"`
+	Name             string          `json:"name"`
+	Category         HotspotCategory `json:"category"`
+	Location         HotspotLocation `json:"location"`
+	CurrentOccupancy int             `json:"current_occupancy"`
+	MaxCapacity      int             `json:"max_capacity"`
+	ScheduledTime    *time.Time      `json:"scheduled_time,omitempty"`
+	ImageURL         string          `json:"image_url,omitempty"`
 }
 
 // BoundingBox represents a rectangular area on the map
@@ -166,6 +343,21 @@ type BoundingBox struct {
 	SouthWest HotspotLocation `json:"south_west"`
 }
 
+// GeohashCount is the number of active public hotspots in one geohash cell
+type GeohashCount struct {
+	Geohash string          `json:"geohash"`
+	Count   int64           `json:"count"`
+	Center  HotspotLocation `json:"center"`
+	Bounds  BoundingBox     `json:"bounds"`
+}
+
+// HotspotCountsResponse holds per-cell hotspot counts for a map viewport; empty cells are left out
+type HotspotCountsResponse struct {
+	Precision int            `json:"precision"`
+	Cells     []GeohashCount `json:"cells"`
+	Total     int64          `json:"total"`
+}
+
 // GeospatialQuery represents optimized geospatial search parameters
 type GeospatialQuery struct {
 	Center           HotspotLocation   `json:"center"`
@@ -210,6 +402,8 @@ type HotspotSearchResultOptimized struct {
 	QueryTime     int64                 `json:"query_time_ms"`
 	CacheHit      bool                  `json:"cache_hit"`
 	ZoomLevel     int                   `json:"zoom_level"`
+	// Partial is set when some candidates could not be fetched before the search deadline
+	Partial bool `json:"partial,omitempty"`
 }
 
 // GeospatialIndex represents an index entry for efficient lookups
@@ -244,6 +438,7 @@ type OptimizedHotspotSearchRequest struct {
 	Filters         SearchFilters   `json:"filters"`
 	Pagination      Pagination      `json:"pagination"`
 	Clustering      ClusterConfig   `json:"clustering"`
+	IncludeTravel   bool            `json:"include_travel"` // Add walking/driving times to individual hotspots
 }
 
 // SearchFilters represents various filtering options
@@ -257,6 +452,8 @@ type SearchFilters struct {
 	TimeFilter        *TimeFilter       `json:"time_filter,omitempty"`
 	CreatedBy         string            `json:"created_by,omitempty"`
 	IsPublic          *bool             `json:"is_public,omitempty"`
+	VerifiedHostsOnly bool              `json:"verified_hosts_only,omitempty"`
+	OrgID             string            `json:"-"` // The searcher's organization, set by the server
 }
 
 // Pagination represents pagination parameters
@@ -276,4 +473,12 @@ type ClusterConfig struct {
 	GridSize        float64        `json:"grid_size_km,omitempty"`
 	ZoomLevel       int            `json:"zoom_level"`
 	IncludeHotspots bool           `json:"include_hotspot_details"`
+	PreviewCount    int            `json:"preview_count,omitempty"` // Previews per cluster, 1-10 (default 3)
+	PreviewSort     string         `json:"preview_sort,omitempty"`  // ClusterPreviewSort* value (default occupancy)
 }
+
+// Orders for cluster previews
+const (
+	ClusterPreviewSortOccupancy = "occupancy"  // Most attendees first
+	ClusterPreviewSortStartTime = "start_time" // Soonest scheduled first
+)
@@ -0,0 +1,28 @@
+// Interest taxonomy models
+package models
+
+// InterestLabel is a canonical interest with its label in the requested language
+type InterestLabel struct {
+	ID       string `json:"id"` // Slug stored on profiles, e.g. "board-games"
+	Label    string `json:"label"`
+	Category string `json:"category"`
+}
+
+// InterestCategory groups catalog interests for pickers
+type InterestCategory struct {
+	ID        string          `json:"id"`
+	Label     string          `json:"label"`
+	Interests []InterestLabel `json:"interests"`
+}
+
+// InterestSuggestion is a catalog interest matching typed text, best matches first
+type InterestSuggestion struct {
+	InterestLabel
+	Match string `json:"match"` // exact, prefix, contains or fuzzy
+}
+
+// SuggestInterestsRequest holds the query parameters for interest suggestions
+type SuggestInterestsRequest struct {
+	Q     string `form:"q" binding:"required,max=50"`
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=20"`
+}
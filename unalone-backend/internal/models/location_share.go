@@ -0,0 +1,38 @@
+// Live location sharing models for attendees of an active hotspot
+package models
+
+import "time"
+
+// Hotspot WebSocket frame types for live attendee locations
+const (
+	ChatFrameMessage         = "message"
+	ChatFrameLocation        = "location"
+	ChatFrameLocationStopped = "location_stopped"
+)
+
+// LocationShare is an attendee's opt-in, time-limited live location within a hotspot.
+// Shares are ephemeral and never persisted.
+type LocationShare struct {
+	HotspotID string   `json:"hotspot_id"`
+	UserID    string   `json:"user_id"`
+	Nickname  string   `json:"nickname"`
+	Latitude  *float64 `json:"latitude,omitempty"`
+	Longitude *float64 `json:"longitude,omitempty"`
+	// Grid size in meters the coordinates were snapped to for this viewer; 0 is exact
+	AccuracyMeters int        `json:"accuracy_meters,omitempty"`
+	StartedAt      time.Time  `json:"started_at"`
+	UpdatedAt      *time.Time `json:"updated_at,omitempty"`
+	ExpiresAt      time.Time  `json:"expires_at"`
+}
+
+// LocationShareFrame is broadcast over the hotspot WebSocket when an attendee's
+// shared location moves or sharing stops
+type LocationShareFrame struct {
+	Type  string         `json:"type"`
+	Share *LocationShare `json:"share"`
+}
+
+// StartLocationSharingRequest opts in to sharing for a limited number of minutes
+type StartLocationSharingRequest struct {
+	DurationMinutes int `json:"duration_minutes" binding:"required,min=5,max=240"`
+}
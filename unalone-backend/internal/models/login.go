@@ -0,0 +1,14 @@
+// Login security models for known devices
+package models
+
+import "time"
+
+// LoginDevice is a device and network a user has signed in from before
+type LoginDevice struct {
+	Fingerprint string    `firestore:"fingerprint" json:"fingerprint"` // Hash of the user agent
+	UserAgent   string    `firestore:"user_agent" json:"user_agent"`
+	Network     string    `firestore:"network" json:"network"` // Client IP truncated to /24 (IPv4) or /48 (IPv6)
+	LastIP      string    `firestore:"last_ip" json:"last_ip"`
+	FirstSeenAt time.Time `firestore:"first_seen_at" json:"first_seen_at"`
+	LastSeenAt  time.Time `firestore:"last_seen_at" json:"last_seen_at"`
+}
@@ -0,0 +1,26 @@
+// Nickname history models
+package models
+
+import "time"
+
+// NicknameChange records one nickname change
+type NicknameChange struct {
+	UserID      string    `firestore:"user_id" json:"-"`
+	OldNickname string    `firestore:"old_nickname" json:"old_nickname"`
+	NewNickname string    `firestore:"new_nickname" json:"new_nickname"`
+	ChangedAt   time.Time `firestore:"changed_at" json:"changed_at"`
+}
+
+// NicknameReservation holds a released nickname for its previous owner so nobody else can take it over
+type NicknameReservation struct {
+	Nickname   string    `firestore:"nickname" json:"nickname"` // Lowercased
+	ReleasedBy string    `firestore:"released_by" json:"-"`
+	ExpiresAt  time.Time `firestore:"expires_at" json:"expires_at"`
+}
+
+// NicknameStatus is the current user's nickname history and when they may change it next
+type NicknameStatus struct {
+	Nickname     string           `json:"nickname"`
+	History      []NicknameChange `json:"history"` // Newest first
+	NextChangeAt *time.Time       `json:"next_change_at,omitempty"`
+}
@@ -0,0 +1,82 @@
+// Notification models for push/email delivery and preferences
+package models
+
+import "time"
+
+// NotificationCategory groups notifications so users can tune them separately
+type NotificationCategory string
+
+const (
+	NotificationCategoryFriends  NotificationCategory = "friends"
+	NotificationCategoryHotspots NotificationCategory = "hotspots"
+	NotificationCategoryChat     NotificationCategory = "chat"
+	NotificationCategoryNearby   NotificationCategory = "nearby"
+	NotificationCategorySafety   NotificationCategory = "safety"
+	// Points, levels and other achievements
+	NotificationCategoryAchievements NotificationCategory = "achievements"
+)
+
+// NotificationCategories lists every category users can configure
+var NotificationCategories = []NotificationCategory{
+	NotificationCategoryFriends,
+	NotificationCategoryHotspots,
+	NotificationCategoryChat,
+	NotificationCategoryNearby,
+	NotificationCategorySafety,
+	NotificationCategoryAchievements,
+}
+
+// Notification types identify what happened; they double as realtime event types
+const (
+	NotificationTypeFriendRequest   = "friend_request"
+	NotificationTypeFriendAccepted  = "friend_accepted"
+	NotificationTypeHotspotJoined   = "hotspot_joined"
+	NotificationTypeHotspotUpdated  = "hotspot_updated"
+	NotificationTypeLevelUp         = "level_up"
+	NotificationTypeFriendNearby    = "friend_nearby"
+	NotificationTypeHotspotInvite   = "hotspot_invite"
+	NotificationTypeFeedbackRequest = "feedback_request"
+	NotificationTypeSafetyAlert     = "safety_alert"
+	NotificationTypeSafetyConfirmed = "safety_confirmed"
+	NotificationTypeSOS             = "sos_alert"
+	NotificationTypeOwnershipOffer  = "ownership_offer"
+	NotificationTypeNewHost         = "hotspot_new_host"
+	NotificationTypeChatMessage     = "chat_message"
+	NotificationTypeAnnouncement    = "hotspot_announcement"
+	NotificationTypeClubHotspot     = "club_hotspot"
+)
+
+// NotificationChannelPrefs enables or disables each delivery channel for a category
+type NotificationChannelPrefs struct {
+	Push  bool `firestore:"push" json:"push"`
+	Email bool `firestore:"email" json:"email"`
+}
+
+// QuietHours is a daily window, in the user's timezone, during which push notifications are held back
+type QuietHours struct {
+	Enabled  bool   `firestore:"enabled" json:"enabled"`
+	Start    string `firestore:"start" json:"start"`       // HH:MM
+	End      string `firestore:"end" json:"end"`           // HH:MM; may be earlier than Start to span midnight
+	Timezone string `firestore:"timezone" json:"timezone"` // IANA name, e.g. Asia/Kolkata
+}
+
+// Notification represents a message sent to a user
+type Notification struct {
+	ID        string               `firestore:"id" json:"id"`
+	UserID    string               `firestore:"user_id" json:"user_id"`
+	Category  NotificationCategory `firestore:"category" json:"category"`
+	Type      string               `firestore:"type" json:"type"`
+	Title     string               `firestore:"title" json:"title"`
+	Body      string               `firestore:"body" json:"body"`
+	Data      map[string]string    `firestore:"data" json:"data,omitempty"`
+	Read      bool                 `firestore:"read" json:"read"`
+	ReadAt    *time.Time           `firestore:"read_at" json:"read_at,omitempty"`
+	CreatedAt time.Time            `firestore:"created_at" json:"created_at"`
+}
+
+// NotificationListResponse represents a page of the notification inbox
+type NotificationListResponse struct {
+	Notifications []*Notification `json:"notifications"`
+	UnreadCount   int             `json:"unread_count"`
+	TotalCount    int             `json:"total_count"`
+}
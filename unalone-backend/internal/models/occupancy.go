@@ -0,0 +1,44 @@
+// Occupancy timeline models for host capacity analytics
+package models
+
+import "time"
+
+// Occupancy change kinds
+const (
+	OccupancyJoined    = "joined"
+	OccupancyLeft      = "left"
+	OccupancyCheckedIn = "checked_in"
+)
+
+// OccupancyPoint is a hotspot's RSVP and check-in counts right after one change
+type OccupancyPoint struct {
+	At        time.Time `json:"at"`
+	Kind      string    `json:"kind"`
+	RSVPs     int       `json:"rsvps"` // Attendees, including the host
+	CheckedIn int       `json:"checked_in"`
+}
+
+// OccupancyBucket sums the changes within one interval of the timeline.
+// RSVPs and CheckedIn are the counts at the end of the interval.
+type OccupancyBucket struct {
+	Start     time.Time `json:"start"`
+	Joins     int       `json:"joins"`
+	Leaves    int       `json:"leaves"`
+	CheckIns  int       `json:"check_ins"`
+	RSVPs     int       `json:"rsvps"`
+	CheckedIn int       `json:"checked_in"`
+}
+
+// OccupancyTimeline shows how a hotspot filled up over time and how many RSVPs actually showed up
+type OccupancyTimeline struct {
+	HotspotID      string            `json:"hotspot_id"`
+	MaxCapacity    int               `json:"max_capacity"`
+	RSVPs          int               `json:"rsvps"`
+	CheckedIn      int               `json:"checked_in"`
+	ShowUpRate     float64           `json:"show_up_rate"` // CheckedIn / RSVPs
+	PeakRSVPs      int               `json:"peak_rsvps"`
+	PeakAt         *time.Time        `json:"peak_at,omitempty"`
+	FirstCheckInAt *time.Time        `json:"first_check_in_at,omitempty"`
+	Points         []OccupancyPoint  `json:"points"`
+	Buckets        []OccupancyBucket `json:"buckets,omitempty"` // Only when an interval is requested
+}
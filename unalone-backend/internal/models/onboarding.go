@@ -0,0 +1,47 @@
+// Onboarding checklist models
+package models
+
+import "time"
+
+// Onboarding steps, in the order clients show them
+const (
+	OnboardingEmailVerified      = "email_verified"
+	OnboardingPhoneVerified      = "phone_verified"
+	OnboardingProfileCompleted   = "profile_completed"
+	OnboardingInterestsPicked    = "interests_picked"
+	OnboardingFirstHotspotJoined = "first_hotspot_joined"
+)
+
+// OnboardingSteps lists every step in checklist order
+var OnboardingSteps = []string{
+	OnboardingEmailVerified,
+	OnboardingPhoneVerified,
+	OnboardingProfileCompleted,
+	OnboardingInterestsPicked,
+	OnboardingFirstHotspotJoined,
+}
+
+// OnboardingState records when each step was first completed; steps never revert, so each is rewarded once
+type OnboardingState struct {
+	UserID      string               `firestore:"user_id" json:"-"`
+	CompletedAt map[string]time.Time `firestore:"completed_at" json:"completed_at"`
+	FinishedAt  *time.Time           `firestore:"finished_at" json:"finished_at,omitempty"`
+}
+
+// OnboardingStep is one checklist item
+type OnboardingStep struct {
+	Key         string     `json:"key"`
+	Completed   bool       `json:"completed"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	Points      int        `json:"points"` // Awarded when the step completes
+}
+
+// OnboardingProgress is the user's checklist; CurrentStep is the first incomplete step
+type OnboardingProgress struct {
+	Steps       []OnboardingStep `json:"steps"`
+	CurrentStep string           `json:"current_step,omitempty"`
+	Completed   int              `json:"completed"`
+	Total       int              `json:"total"`
+	Finished    bool             `json:"finished"`
+	FinishedAt  *time.Time       `json:"finished_at,omitempty"`
+}
@@ -0,0 +1,53 @@
+// Organization models for campus and organization deployments
+package models
+
+import "time"
+
+// Roles of a user within their organization
+const (
+	OrgRoleAdmin  = "admin"  // Manages members and the invite code
+	OrgRoleMember = "member" // Sees and joins the organization's hotspots
+)
+
+// Organization is an isolated community, such as a university. Its members only see each
+// other and the organization's hotspots; users outside any organization share the public network.
+type Organization struct {
+	ID          string    `firestore:"id" json:"id"`
+	Name        string    `firestore:"name" json:"name"`
+	InviteCode  string    `firestore:"invite_code" json:"invite_code,omitempty"` // Shown to organization admins only
+	CreatedBy   string    `firestore:"created_by" json:"created_by"`
+	MemberCount int       `firestore:"member_count" json:"member_count"`
+	CreatedAt   time.Time `firestore:"created_at" json:"created_at"`
+	UpdatedAt   time.Time `firestore:"updated_at" json:"updated_at"`
+}
+
+// OrgMembership is the current user's organization and role
+type OrgMembership struct {
+	Organization Organization `json:"organization"`
+	Role         string       `json:"role"`
+	JoinedAt     *time.Time   `json:"joined_at,omitempty"`
+}
+
+// OrgMember is one member as listed to organization admins
+type OrgMember struct {
+	UserID   string     `json:"user_id"`
+	Nickname string     `json:"nickname"`
+	Role     string     `json:"role"`
+	JoinedAt *time.Time `json:"joined_at,omitempty"`
+}
+
+// CreateOrganizationRequest creates an organization with its first admin (platform admin)
+type CreateOrganizationRequest struct {
+	Name        string `json:"name" binding:"required,min=2,max=100"`
+	AdminUserID string `json:"admin_user_id" binding:"required"`
+}
+
+// JoinOrganizationRequest joins an organization with its invite code
+type JoinOrganizationRequest struct {
+	InviteCode string `json:"invite_code" binding:"required"`
+}
+
+// SetOrgRoleRequest changes a member's role
+type SetOrgRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=admin member"`
+}
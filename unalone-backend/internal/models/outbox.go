@@ -0,0 +1,17 @@
+// Outbox models for domain events awaiting delivery to the event bus
+package models
+
+import "time"
+
+// OutboxEntry is a domain event stored alongside the change that produced it,
+// kept until the dispatcher hands it to the event bus
+type OutboxEntry struct {
+	ID            string      `firestore:"id" json:"id"`
+	Event         DomainEvent `firestore:"event" json:"event"`
+	Attempts      int         `firestore:"attempts" json:"attempts"`
+	NextAttemptAt time.Time   `firestore:"next_attempt_at" json:"next_attempt_at"`
+	LastError     string      `firestore:"last_error,omitempty" json:"last_error,omitempty"`
+	CreatedAt     time.Time   `firestore:"created_at" json:"created_at"`
+	DispatchedAt  *time.Time  `firestore:"dispatched_at" json:"dispatched_at,omitempty"`
+	FailedAt      *time.Time  `firestore:"failed_at" json:"failed_at,omitempty"` // Set when retries are exhausted
+}
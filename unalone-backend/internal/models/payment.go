@@ -0,0 +1,95 @@
+// Payment models for ticketed hotspots
+package models
+
+import "time"
+
+// Ticket statuses
+const (
+	TicketStatusPending       = "pending"        // Checkout started; the provider has not confirmed payment
+	TicketStatusPaid          = "paid"           // Valid for joining the hotspot
+	TicketStatusFailed        = "failed"         // The provider reported a failed payment
+	TicketStatusExpired       = "expired"        // Checkout was not completed in time
+	TicketStatusRefundPending = "refund_pending" // The hotspot was cancelled; the refund is being retried
+	TicketStatusRefunded      = "refunded"
+)
+
+// TicketTier is a priced kind of ticket for a hotspot, such as "Early bird" or "Standard"
+type TicketTier struct {
+	ID       string `firestore:"id" json:"id"`
+	Name     string `firestore:"name" json:"name"`
+	Price    int64  `firestore:"price" json:"price"`       // In the currency's smallest unit, e.g. paise
+	Currency string `firestore:"currency" json:"currency"` // ISO 4217, e.g. INR
+	Capacity int    `firestore:"capacity" json:"capacity"` // 0 for no limit beyond the hotspot's own
+}
+
+// TicketTierInput describes a tier when creating a hotspot or replacing its tiers.
+// An ID keeps an existing tier; tiers without one are created.
+type TicketTierInput struct {
+	ID       string `json:"id"`
+	Name     string `json:"name" binding:"required,min=1,max=50"`
+	Price    int64  `json:"price" binding:"required,min=1"`
+	Currency string `json:"currency" binding:"omitempty,len=3"` // Defaults to INR
+	Capacity int    `json:"capacity" binding:"min=0,max=10000"`
+}
+
+// SetTicketTiersRequest replaces a hotspot's ticket tiers; an empty list makes it free again
+type SetTicketTiersRequest struct {
+	Tiers []TicketTierInput `json:"tiers" binding:"max=5,dive"`
+}
+
+// TicketTierAvailability is a tier with how many tickets are left
+type TicketTierAvailability struct {
+	TicketTier
+	Available *int `json:"available"` // Nil when the tier has no capacity of its own
+}
+
+// HotspotTickets lists a hotspot's tiers and the current user's valid ticket, if any
+type HotspotTickets struct {
+	HotspotID string                   `json:"hotspot_id"`
+	Tiers     []TicketTierAvailability `json:"tiers"`
+	MyTicket  *Ticket                  `json:"my_ticket,omitempty"`
+}
+
+// Ticket is one user's purchase of a tier
+type Ticket struct {
+	ID                string     `firestore:"id" json:"id"`
+	HotspotID         string     `firestore:"hotspot_id" json:"hotspot_id"`
+	HotspotName       string     `firestore:"hotspot_name" json:"hotspot_name"`
+	TierID            string     `firestore:"tier_id" json:"tier_id"`
+	TierName          string     `firestore:"tier_name" json:"tier_name"`
+	UserID            string     `firestore:"user_id" json:"user_id"`
+	Amount            int64      `firestore:"amount" json:"amount"`
+	Currency          string     `firestore:"currency" json:"currency"`
+	Status            string     `firestore:"status" json:"status"`
+	Provider          string     `firestore:"provider" json:"provider"`
+	ProviderOrderID   string     `firestore:"provider_order_id" json:"-"`
+	ProviderPaymentID string     `firestore:"provider_payment_id" json:"-"`
+	ProviderRefundID  string     `firestore:"provider_refund_id" json:"-"`
+	HoldExpiresAt     time.Time  `firestore:"hold_expires_at" json:"hold_expires_at"` // Pending tickets hold a place until then
+	PaidAt            *time.Time `firestore:"paid_at" json:"paid_at,omitempty"`
+	RefundedAt        *time.Time `firestore:"refunded_at" json:"refunded_at,omitempty"`
+	RefundError       string     `firestore:"refund_error" json:"-"`
+	CreatedAt         time.Time  `firestore:"created_at" json:"created_at"`
+	UpdatedAt         time.Time  `firestore:"updated_at" json:"updated_at"`
+}
+
+// PurchaseTicketRequest starts buying a ticket
+type PurchaseTicketRequest struct {
+	TierID string `json:"tier_id" binding:"required"`
+}
+
+// PaymentCheckout is what the app needs to open the provider's checkout for a pending ticket
+type PaymentCheckout struct {
+	Ticket       *Ticket `json:"ticket"`
+	Provider     string  `json:"provider"`
+	OrderID      string  `json:"order_id"`                // Razorpay order ID or Stripe PaymentIntent ID
+	KeyID        string  `json:"key_id,omitempty"`        // Razorpay public key for the checkout widget
+	ClientSecret string  `json:"client_secret,omitempty"` // Stripe PaymentIntent client secret
+}
+
+// PaymentEvent is a payment update read from a provider webhook
+type PaymentEvent struct {
+	OrderID   string
+	PaymentID string
+	Status    string // TicketStatusPaid or TicketStatusFailed
+}
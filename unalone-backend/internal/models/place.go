@@ -0,0 +1,25 @@
+// Place models for venue autocomplete
+package models
+
+// PlaceSuggestion represents a single venue suggestion from the places provider
+type PlaceSuggestion struct {
+	PlaceID       string `json:"place_id"`
+	Description   string `json:"description"`
+	MainText      string `json:"main_text"`
+	SecondaryText string `json:"secondary_text,omitempty"`
+}
+
+// PlacesAutocompleteRequest represents autocomplete query parameters
+type PlacesAutocompleteRequest struct {
+	Input        string   `form:"input" binding:"required,min=2,max=100"`
+	SessionToken string   `form:"session_token" binding:"max=64"`
+	Latitude     *float64 `form:"latitude"`
+	Longitude    *float64 `form:"longitude"`
+}
+
+// PlacesAutocompleteResponse represents the autocomplete response
+type PlacesAutocompleteResponse struct {
+	Suggestions  []PlaceSuggestion `json:"suggestions"`
+	SessionToken string            `json:"session_token"`
+	CacheHit     bool              `json:"cache_hit"`
+}
@@ -0,0 +1,36 @@
+// Platform-wide usage metrics for the admin dashboard
+package models
+
+import "time"
+
+// DailyStats is one day of platform usage, rolled up nightly into the stats collection
+type DailyStats struct {
+	Day               string          `firestore:"day" json:"day"` // YYYY-MM-DD, UTC
+	ActiveUsers       int             `firestore:"active_users" json:"active_users"`
+	WeeklyActiveUsers int             `firestore:"weekly_active_users" json:"weekly_active_users"` // Distinct users over the 7 days ending on Day
+	NewRegistrations  int             `firestore:"new_registrations" json:"new_registrations"`
+	HotspotsCreated   int             `firestore:"hotspots_created" json:"hotspots_created"`
+	HotspotJoins      int             `firestore:"hotspot_joins" json:"hotspot_joins"`
+	ChatMessages      int             `firestore:"chat_messages" json:"chat_messages"`
+	AISessions        int             `firestore:"ai_sessions" json:"ai_sessions"`
+	AIMessages        int             `firestore:"ai_messages" json:"ai_messages"`
+	Wellbeing         *WellbeingStats `firestore:"wellbeing" json:"wellbeing,omitempty"` // Omitted for small cohorts
+	GeneratedAt       time.Time       `firestore:"generated_at" json:"generated_at"`
+}
+
+// WellbeingStats is the anonymous mood of AI users who opted in to insights: counts only,
+// with no user IDs or text
+type WellbeingStats struct {
+	Users    int            `firestore:"users" json:"users"`
+	Messages int            `firestore:"messages" json:"messages"`
+	Positive int            `firestore:"positive" json:"positive"`
+	Neutral  int            `firestore:"neutral" json:"neutral"`
+	Negative int            `firestore:"negative" json:"negative"`
+	Moods    map[string]int `firestore:"moods" json:"moods,omitempty"`
+}
+
+// MetricsDashboard is the admin view of recent platform usage
+type MetricsDashboard struct {
+	Days  []DailyStats `json:"days"`  // Rolled-up days, oldest first
+	Today DailyStats   `json:"today"` // Live counts for the current day so far
+}
@@ -0,0 +1,28 @@
+// Presence and availability models
+package models
+
+import "time"
+
+// PresenceHeartbeat acknowledges a heartbeat and tells the client when to send the next one
+type PresenceHeartbeat struct {
+	IntervalSeconds int `json:"interval_seconds"`
+}
+
+// Availability statuses
+const (
+	AvailabilityAvailable = "available" // Open to meeting up
+	AvailabilityBusy      = "busy"      // Non-critical pushes are held back
+	AvailabilityInvisible = "invisible" // Appears offline and shows no status
+)
+
+// Availability is the status a user picked, optionally until ExpiresAt
+type Availability struct {
+	Status    string     `firestore:"status" json:"status"`
+	ExpiresAt *time.Time `firestore:"expires_at" json:"expires_at,omitempty"` // Back to available after this; nil until changed
+}
+
+// SetAvailabilityRequest sets the user's status, for a number of minutes or until changed
+type SetAvailabilityRequest struct {
+	Status          string `json:"status" binding:"required,oneof=available busy invisible"`
+	DurationMinutes int    `json:"duration_minutes" binding:"omitempty,min=15,max=10080"`
+}
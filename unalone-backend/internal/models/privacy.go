@@ -0,0 +1,90 @@
+// Privacy models: data retention policy and consents
+package models
+
+import "time"
+
+// Kinds of personal data with a retention rule
+const (
+	RetentionAISessions     = "ai_sessions"
+	RetentionAIInsights     = "ai_insights"
+	RetentionLocation       = "location"
+	RetentionHotspotChats   = "hotspot_chats"
+	RetentionEphemeralChats = "ephemeral_chats"
+	RetentionPresence       = "presence"
+)
+
+// RetentionRule is how long one kind of personal data is kept before it is deleted
+type RetentionRule struct {
+	Data        string `json:"data"`
+	Days        int    `json:"days,omitempty"`
+	Hours       int    `json:"hours,omitempty"` // Set instead of Days for rules shorter than a day
+	After       string `json:"after"`           // What the period counts from
+	Description string `json:"description"`
+}
+
+// PrivacyPolicy describes how the service handles personal data
+type PrivacyPolicy struct {
+	Retention []RetentionRule `json:"retention"`
+}
+
+// Consent purposes: processing a user must agree to
+const (
+	ConsentLocation       = "location"        // Storing and using their location
+	ConsentAnalytics      = "analytics"       // Counting them in usage metrics
+	ConsentAIProcessing   = "ai_processing"   // Sending their messages to the AI assistant
+	ConsentMarketingEmail = "marketing_email" // Promotional email such as the weekly digest
+)
+
+// Consent record sources
+const (
+	ConsentSourceDefault     = "default" // No record; the purpose's default applies
+	ConsentSourceAPI         = "api"
+	ConsentSourceEmailDigest = "email_digest" // Turning the weekly digest on or off
+)
+
+// ConsentPurpose describes one purpose. Version goes up whenever the text users agree to
+// changes; grants of an older version no longer count. Default applies to users who have
+// not decided, and matches what the service did before consents were tracked.
+type ConsentPurpose struct {
+	ID          string `json:"id"`
+	Version     int    `json:"version"`
+	Default     bool   `json:"default"`
+	Description string `json:"description"`
+}
+
+// ConsentPurposes lists every purpose users can grant or revoke
+var ConsentPurposes = []ConsentPurpose{
+	{ID: ConsentLocation, Version: 1, Default: true, Description: "Store your last known location for nearby hotspots, friend proximity alerts, crisis helplines near you and live location sharing"},
+	{ID: ConsentAnalytics, Version: 1, Default: true, Description: "Count you in anonymous daily usage and wellbeing statistics"},
+	{ID: ConsentAIProcessing, Version: 1, Default: true, Description: "Send your messages to the AI assistant, and its model provider, to generate replies and hotspot drafts"},
+	{ID: ConsentMarketingEmail, Version: 1, Default: false, Description: "Email you the weekly digest of upcoming hotspots and friend activity"},
+}
+
+// ConsentRecord is one grant or revocation. Records are append-only; the latest per
+// purpose is the user's current choice.
+type ConsentRecord struct {
+	ID        string    `firestore:"id" json:"id"`
+	UserID    string    `firestore:"user_id" json:"user_id"`
+	Purpose   string    `firestore:"purpose" json:"purpose"`
+	Granted   bool      `firestore:"granted" json:"granted"`
+	Version   int       `firestore:"version" json:"version"` // Purpose version the user saw
+	Source    string    `firestore:"source" json:"source"`
+	CreatedAt time.Time `firestore:"created_at" json:"created_at"`
+}
+
+// ConsentState is a user's current standing for one purpose
+type ConsentState struct {
+	Purpose        string     `json:"purpose"`
+	Description    string     `json:"description"`
+	Granted        bool       `json:"granted"`           // Whether processing is allowed now
+	Version        int        `json:"version,omitempty"` // Version of the latest record
+	CurrentVersion int        `json:"current_version"`
+	Outdated       bool       `json:"outdated,omitempty"` // Granted for an older version; ask again
+	Source         string     `json:"source"`
+	UpdatedAt      *time.Time `json:"updated_at,omitempty"`
+}
+
+// GrantConsentRequest grants a purpose at the version shown to the user
+type GrantConsentRequest struct {
+	Version int `json:"version" binding:"required,min=1"`
+}
@@ -16,15 +16,20 @@ type UpdateProfileRequest struct {
 
 // PhoneVerificationRequest represents phone verification request
 type PhoneVerificationRequest struct {
-	PhoneNumber string `json:"phone_number" binding:"required,min=10,max=15"`
+	PhoneNumber string `json:"phone_number" binding:"required,max=32"`
 }
 
 // VerifyPhoneRequest represents phone verification code submission
 type VerifyPhoneRequest struct {
-	PhoneNumber string `json:"phone_number" binding:"required"`
+	PhoneNumber string `json:"phone_number" binding:"required,max=32"`
 	Code        string `json:"code" binding:"required,len=6"`
 }
 
+// UpdateProfileImageRequest sets the profile image by URL until uploads are supported
+type UpdateProfileImageRequest struct {
+	ImageURL string `json:"image_url" binding:"required,url"`
+}
+
 // ProfileImageUploadResponse represents response after image upload
 type ProfileImageUploadResponse struct {
 	ImageURL string `json:"image_url"`
@@ -56,6 +61,9 @@ type UserSettings struct {
 	DistanceRadius        int    `firestore:"distance_radius" json:"distance_radius"`        // in kilometers
 	AgeRangeMin           int    `firestore:"age_range_min" json:"age_range_min"`
 	AgeRangeMax           int    `firestore:"age_range_max" json:"age_range_max"`
+	// Per-category channel overrides; categories not listed follow the global flags
+	CategoryPreferences   map[NotificationCategory]NotificationChannelPrefs `firestore:"category_preferences" json:"category_preferences,omitempty"`
+	QuietHours            QuietHours `firestore:"quiet_hours" json:"quiet_hours"`
 	CreatedAt             time.Time `firestore:"created_at" json:"created_at"`
 	UpdatedAt             time.Time `firestore:"updated_at" json:"updated_at"`
 }
@@ -71,4 +79,6 @@ type UpdateSettingsRequest struct {
 	DistanceRadius       int    `json:"distance_radius" binding:"min=1,max=100"`
 	AgeRangeMin          int    `json:"age_range_min" binding:"min=18,max=100"`
 	AgeRangeMax          int    `json:"age_range_max" binding:"min=18,max=100"`
+	CategoryPreferences  map[NotificationCategory]NotificationChannelPrefs `json:"category_preferences"`
+	QuietHours           QuietHours `json:"quiet_hours"`
 }
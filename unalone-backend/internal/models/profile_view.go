@@ -0,0 +1,33 @@
+// Models for viewing another user's profile
+package models
+
+import "time"
+
+// UserProfileView is what one user sees when opening another user's profile.
+// Social details are omitted when the owner's profile visibility does not allow them.
+type UserProfileView struct {
+	ID                 string          `json:"id"`
+	Nickname           string          `json:"nickname"`
+	ProfileImageURL    string          `json:"profile_image_url,omitempty"`
+	Bio                string          `json:"bio,omitempty"`
+	Interests          []string        `json:"interests,omitempty"`
+	Level              int             `json:"level"`
+	Relationship       FriendStatus    `json:"relationship"`
+	Online             *bool           `json:"online,omitempty"` // Presence, omitted when the owner hides their online status or is invisible
+	LastSeenAt         *time.Time      `json:"last_seen_at,omitempty"`
+	Availability       string          `json:"availability,omitempty"`
+	PreviousNicknames  []string        `json:"previous_nicknames,omitempty"` // Most recent first; shown to friends only
+	Restricted         bool            `json:"restricted"`                   // True when visibility settings hid the details below
+	MutualFriendCount  int             `json:"mutual_friend_count"`
+	MutualFriends      []PublicUser    `json:"mutual_friends"`
+	SharedHotspotCount int             `json:"shared_hotspot_count"`
+	SharedHotspots     []SharedHotspot `json:"shared_hotspots"`
+}
+
+// SharedHotspot is a hotspot both users attended
+type SharedHotspot struct {
+	ID            string          `json:"id"`
+	Name          string          `json:"name"`
+	Category      HotspotCategory `json:"category"`
+	ScheduledTime *time.Time      `json:"scheduled_time,omitempty"`
+}
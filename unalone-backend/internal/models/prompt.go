@@ -0,0 +1,47 @@
+// Prompt template models for the AI assistant
+package models
+
+import "time"
+
+// Prompt template keys
+const (
+	PromptKeyAISystem = "ai_system" // The assistant's system instruction
+)
+
+// Prompt template variables, written as {{name}} in a template body
+const (
+	PromptVarUserName = "user_name" // The user's nickname
+	PromptVarLanguage = "language"  // The user's preferred language, e.g. "Hindi"
+	PromptVarMood     = "mood"      // Their most frequent recent mood, only with insights on; empty otherwise
+	PromptVarDate     = "date"      // Today, e.g. "Monday, 2 March 2026"
+)
+
+// PromptTemplate is one published version of a prompt. A version with ExperimentKey and
+// Variant only applies to users in that variant; the latest version without one is the default.
+type PromptTemplate struct {
+	Key           string    `firestore:"key" json:"key"`
+	Version       int       `firestore:"version" json:"version"`
+	Body          string    `firestore:"body" json:"body"`
+	Description   string    `firestore:"description" json:"description,omitempty"`
+	Variables     []string  `firestore:"variables" json:"variables,omitempty"` // Variables the body uses
+	ExperimentKey string    `firestore:"experiment_key" json:"experiment_key,omitempty"`
+	Variant       string    `firestore:"variant" json:"variant,omitempty"`
+	PublishedBy   string    `firestore:"published_by" json:"published_by"`
+	PublishedAt   time.Time `firestore:"published_at" json:"published_at"`
+}
+
+// PromptSummary is a prompt key with its current default version and experiment overrides
+type PromptSummary struct {
+	Key       string           `json:"key"`
+	Current   PromptTemplate   `json:"current"`
+	Overrides []PromptTemplate `json:"overrides,omitempty"` // Latest version per experiment variant
+	Versions  int              `json:"versions"`
+}
+
+// PublishPromptRequest publishes a new version of a prompt (admin)
+type PublishPromptRequest struct {
+	Body          string `json:"body" binding:"required,min=20,max=20000"`
+	Description   string `json:"description" binding:"max=500"`
+	ExperimentKey string `json:"experiment_key" binding:"max=50"`
+	Variant       string `json:"variant" binding:"max=50"` // Required with experiment_key
+}
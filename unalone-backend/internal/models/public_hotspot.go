@@ -0,0 +1,32 @@
+// Public hotspot models served to visitors who are not signed in
+package models
+
+import "time"
+
+// PublicHotspot is a hotspot as shown on the website and app preview. It names nobody:
+// there are no host or attendee IDs or nicknames, only how many people are going.
+type PublicHotspot struct {
+	ID            string          `json:"id"`
+	Name          string          `json:"name"`
+	Description   string          `json:"description"`
+	Category      HotspotCategory `json:"category"`
+	Subcategory   string          `json:"subcategory,omitempty"`
+	Location      HotspotLocation `json:"location"`
+	Address       HotspotAddress  `json:"address"`
+	VenueID       string          `json:"venue_id,omitempty"`
+	Tags          []string        `json:"tags"`
+	ScheduledTime *time.Time      `json:"scheduled_time,omitempty"`
+	EndTime       *time.Time      `json:"end_time,omitempty"`
+	ImageURL      string          `json:"image_url,omitempty"`
+	AttendeeCount int             `json:"attendee_count"`
+	MaxCapacity   int             `json:"max_capacity"`
+	SourceURL     string          `json:"source_url,omitempty"` // Original listing of an imported event
+	Distance      *float64        `json:"distance,omitempty"`   // Kilometers from the search point
+}
+
+// PublicHotspotList is a page of public hotspots
+type PublicHotspotList struct {
+	Hotspots []PublicHotspot `json:"hotspots"`
+	Total    int             `json:"total"`
+	HasMore  bool            `json:"has_more"`
+}
@@ -0,0 +1,24 @@
+// Quota models for external provider usage and spend
+package models
+
+// ProviderUsage is one external provider's calls and estimated spend on one UTC day
+type ProviderUsage struct {
+	Provider  string  `json:"provider"` // gemini, sms or geocoding
+	Calls     int64   `json:"calls"`
+	Denied    int64   `json:"denied"` // Calls skipped or degraded because the daily budget was spent
+	CostUSD   float64 `json:"cost_usd"`
+	BudgetUSD float64 `json:"budget_usd"` // 0 when the provider has no daily budget
+	Exhausted bool    `json:"exhausted"`
+}
+
+// DailyProviderUsage is every provider's usage on one UTC day
+type DailyProviderUsage struct {
+	Date      string          `json:"date"` // YYYY-MM-DD
+	Providers []ProviderUsage `json:"providers"`
+}
+
+// ProviderUsageReport is returned to admins, today first
+type ProviderUsageReport struct {
+	Shared bool                 `json:"shared"` // False when Redis is unavailable and counts cover this instance only
+	Days   []DailyProviderUsage `json:"days"`
+}
@@ -0,0 +1,59 @@
+// Wellbeing resource models for the curated helpline and support directory
+package models
+
+import "time"
+
+// Wellbeing resource kinds
+const (
+	ResourceKindHelpline         = "helpline"
+	ResourceKindCounselingCenter = "counseling_center"
+	ResourceKindNGO              = "ngo"
+)
+
+// WellbeingResource is a curated helpline, campus counseling center or NGO.
+// Country and Region scope it; a resource with a Location only applies within RadiusKm of it.
+type WellbeingResource struct {
+	ID          string           `firestore:"id" json:"id"` // Slug, e.g. "tele-manas"
+	Name        string           `firestore:"name" json:"name"`
+	Kind        string           `firestore:"kind" json:"kind"`
+	Description string           `firestore:"description" json:"description,omitempty"`
+	Phone       string           `firestore:"phone" json:"phone,omitempty"`
+	URL         string           `firestore:"url" json:"url,omitempty"`
+	Hours       string           `firestore:"hours" json:"hours,omitempty"` // e.g. "24/7" or "Mon-Fri 10:00-17:00"
+	Languages   []string         `firestore:"languages" json:"languages,omitempty"`
+	Country     string           `firestore:"country" json:"country,omitempty"` // ISO 3166-1 alpha-2; empty for international services
+	Region      string           `firestore:"region" json:"region,omitempty"`   // ISO 3166-2 subdivision, e.g. "IN-KA"; empty for nationwide
+	Location    *HotspotLocation `firestore:"location" json:"location,omitempty"`
+	RadiusKm    float64          `firestore:"radius_km" json:"radius_km,omitempty"`
+	Crisis      bool             `firestore:"crisis" json:"crisis"` // Answers people in crisis; included in AI crisis responses
+	SortOrder   int              `firestore:"sort_order" json:"sort_order"`
+	IsActive    bool             `firestore:"is_active" json:"is_active"`
+	DistanceKm  float64          `firestore:"-" json:"distance_km,omitempty"` // Set for resources with a Location
+	CreatedAt   time.Time        `firestore:"created_at" json:"created_at"`
+	UpdatedAt   time.Time        `firestore:"updated_at" json:"updated_at"`
+}
+
+// ResourceDirectory lists the resources that apply at a point
+type ResourceDirectory struct {
+	Country   string              `json:"country,omitempty"` // Empty when the point is outside every curated country
+	Region    string              `json:"region,omitempty"`
+	Resources []WellbeingResource `json:"resources"`
+}
+
+// UpsertResourceRequest represents an admin request to create or update a wellbeing resource
+type UpsertResourceRequest struct {
+	Name        string           `json:"name" binding:"required,min=2,max=100"`
+	Kind        string           `json:"kind" binding:"required,oneof=helpline counseling_center ngo"`
+	Description string           `json:"description" binding:"max=500"`
+	Phone       string           `json:"phone" binding:"max=30"`
+	URL         string           `json:"url" binding:"omitempty,url,max=300"`
+	Hours       string           `json:"hours" binding:"max=100"`
+	Languages   []string         `json:"languages" binding:"max=20,dive,min=2,max=10"`
+	Country     string           `json:"country" binding:"omitempty,len=2"`
+	Region      string           `json:"region" binding:"max=10"`
+	Location    *HotspotLocation `json:"location"`
+	RadiusKm    float64          `json:"radius_km" binding:"min=0,max=500"`
+	Crisis      bool             `json:"crisis"`
+	SortOrder   int              `json:"sort_order"`
+	IsActive    *bool            `json:"is_active"`
+}
@@ -11,9 +11,11 @@ type APIResponse struct {
 
 // ErrorResponse represents an error response
 type ErrorResponse struct {
-	Success bool     `json:"success"`
-	Message string   `json:"message"`
-	Errors  []string `json:"errors,omitempty"`
+	Success bool        `json:"success"`
+	Code    string      `json:"code,omitempty"` // Machine-readable reason for errors clients handle specially
+	Message string      `json:"message"`
+	Errors  []string    `json:"errors,omitempty"`
+	Data    interface{} `json:"data,omitempty"` // Details for the client to recover, e.g. the current version on a conflict
 }
 
 // SuccessResponse creates a successful API response
@@ -33,6 +35,15 @@ func ErrorResponseWithMessage(message string) ErrorResponse {
 	}
 }
 
+// ErrorResponseWithCode creates an error response with a machine-readable code
+func ErrorResponseWithCode(code, message string) ErrorResponse {
+	return ErrorResponse{
+		Success: false,
+		Code:    code,
+		Message: message,
+	}
+}
+
 // ErrorResponseWithErrors creates an error response with multiple errors
 func ErrorResponseWithErrors(message string, errors []string) ErrorResponse {
 	return ErrorResponse{
@@ -0,0 +1,96 @@
+// Safety models for trusted contacts and check-in safety timers
+package models
+
+import "time"
+
+// TrustedContact is someone a user wants alerted if they do not check in safely.
+// Contacts are either friends on the app (UserID) or reached by phone or email.
+type TrustedContact struct {
+	ID          string    `firestore:"id" json:"id"`
+	Name        string    `firestore:"name" json:"name"`
+	UserID      string    `firestore:"user_id" json:"user_id,omitempty"`
+	PhoneNumber string    `firestore:"phone_number" json:"phone_number,omitempty"`
+	Email       string    `firestore:"email" json:"email,omitempty"`
+	CreatedAt   time.Time `firestore:"created_at" json:"created_at"`
+}
+
+// AddTrustedContactRequest adds a trusted contact; at least one of user_id, phone_number or email is required
+type AddTrustedContactRequest struct {
+	Name        string `json:"name" binding:"required,min=1,max=100"`
+	UserID      string `json:"user_id"`
+	PhoneNumber string `json:"phone_number" binding:"omitempty,max=32"`
+	Email       string `json:"email" binding:"omitempty,email"`
+}
+
+// Safety timer statuses
+const (
+	SafetyTimerActive    = "active"
+	SafetyTimerConfirmed = "confirmed" // The user confirmed they are safe
+	SafetyTimerAlerted   = "alerted"   // The deadline passed and trusted contacts were alerted
+)
+
+// SafetyTimer asks a user to confirm they are safe by a deadline after checking into a hotspot
+type SafetyTimer struct {
+	ID          string          `firestore:"id" json:"id"`
+	UserID      string          `firestore:"user_id" json:"user_id"`
+	HotspotID   string          `firestore:"hotspot_id" json:"hotspot_id"`
+	HotspotName string          `firestore:"hotspot_name" json:"hotspot_name"`
+	Address     string          `firestore:"address" json:"address,omitempty"` // Venue details shared with contacts if the timer runs out
+	Location    HotspotLocation `firestore:"location" json:"location"`
+	Deadline    time.Time       `firestore:"deadline" json:"deadline"`
+	Status      string          `firestore:"status" json:"status"`
+	CreatedAt   time.Time       `firestore:"created_at" json:"created_at"`
+	ConfirmedAt *time.Time      `firestore:"confirmed_at" json:"confirmed_at,omitempty"`
+	AlertedAt   *time.Time      `firestore:"alerted_at" json:"alerted_at,omitempty"`
+}
+
+// CheckInRequest checks the user in at a hotspot from their current position,
+// optionally starting a safety timer
+type CheckInRequest struct {
+	Latitude           float64 `json:"latitude" binding:"required,min=-90,max=90"`
+	Longitude          float64 `json:"longitude" binding:"required,min=-180,max=180"`
+	SafetyTimerMinutes int     `json:"safety_timer_minutes" binding:"omitempty,min=15,max=720"`
+}
+
+// CheckInResponse is returned after a successful check-in
+type CheckInResponse struct {
+	HotspotID   string       `json:"hotspot_id"`
+	CheckedInAt time.Time    `json:"checked_in_at"`
+	SafetyTimer *SafetyTimer `json:"safety_timer,omitempty"`
+}
+
+// QRCheckInRequest checks the user in with the token scanned from the host's screen
+type QRCheckInRequest struct {
+	Token string `json:"token" binding:"required,max=128"` // The token or the whole scanned payload
+}
+
+// CheckInQRCode is the rotating check-in code a host displays at the venue
+type CheckInQRCode struct {
+	HotspotID     string    `json:"hotspot_id"`
+	Token         string    `json:"token"`
+	QRPayload     string    `json:"qr_payload"` // Text to render as the QR code
+	ExpiresAt     time.Time `json:"expires_at"` // Fetch a new code after this; scans stay valid a little longer
+	RotateSeconds int       `json:"rotate_seconds"`
+}
+
+// SOSRequest raises an emergency alert from inside a hotspot
+type SOSRequest struct {
+	HotspotID string  `json:"hotspot_id" binding:"required"`
+	Latitude  float64 `json:"latitude" binding:"required,min=-90,max=90"`
+	Longitude float64 `json:"longitude" binding:"required,min=-180,max=180"`
+	Message   string  `json:"message" binding:"max=500"`
+}
+
+// SOSAlert records an emergency alert with the user's position and hotspot context
+type SOSAlert struct {
+	ID              string          `firestore:"id" json:"id"`
+	UserID          string          `firestore:"user_id" json:"user_id"`
+	HotspotID       string          `firestore:"hotspot_id" json:"hotspot_id"`
+	HotspotName     string          `firestore:"hotspot_name" json:"hotspot_name"`
+	HostID          string          `firestore:"host_id" json:"host_id"`
+	Address         string          `firestore:"address" json:"address,omitempty"`
+	Location        HotspotLocation `firestore:"location" json:"location"` // Where the user was when they raised the alert
+	Message         string          `firestore:"message" json:"message,omitempty"`
+	ContactsAlerted int             `firestore:"contacts_alerted" json:"contacts_alerted"`
+	CreatedAt       time.Time       `firestore:"created_at" json:"created_at"`
+}
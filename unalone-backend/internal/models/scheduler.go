@@ -0,0 +1,25 @@
+// Scheduler models for maintenance job metrics
+package models
+
+import "time"
+
+// ScheduledJobStats reports how a maintenance job has been running on this instance
+type ScheduledJobStats struct {
+	Name           string     `json:"name"`
+	Interval       string     `json:"interval"`
+	Local          bool       `json:"local,omitempty"` // Runs on every instance
+	Runs           int        `json:"runs"`
+	Failures       int        `json:"failures"`
+	Skipped        int        `json:"skipped"` // Runs left to the leader instance
+	LastRunAt      *time.Time `json:"last_run_at,omitempty"`
+	LastDurationMs int64      `json:"last_duration_ms"`
+	LastError      string     `json:"last_error,omitempty"`
+	NextRunAt      *time.Time `json:"next_run_at,omitempty"`
+}
+
+// SchedulerStatus is the scheduler state returned to admins
+type SchedulerStatus struct {
+	InstanceID string              `json:"instance_id"`
+	Leader     bool                `json:"leader"`
+	Jobs       []ScheduledJobStats `json:"jobs"`
+}
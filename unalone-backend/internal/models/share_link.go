@@ -0,0 +1,49 @@
+// Share link models for hotspot and profile deep links
+package models
+
+import "time"
+
+// Share link target kinds
+const (
+	ShareKindHotspot = "hotspot"
+	ShareKindProfile = "profile"
+)
+
+// ShareLink is a short code that opens a hotspot or profile in the app
+type ShareLink struct {
+	Code         string     `firestore:"code" json:"code"`
+	Kind         string     `firestore:"kind" json:"kind"`
+	TargetID     string     `firestore:"target_id" json:"target_id"`
+	CreatedBy    string     `firestore:"created_by" json:"created_by"`
+	GrantsAccess bool       `firestore:"grants_access" json:"grants_access"` // Made by a host for a friend-list hotspot; opens it to whoever holds the link
+	CreatedAt    time.Time  `firestore:"created_at" json:"created_at"`
+	ExpiresAt    *time.Time `firestore:"expires_at" json:"expires_at,omitempty"`
+	RevokedAt    *time.Time `firestore:"revoked_at" json:"-"`
+}
+
+// CreateShareLinkRequest configures a new share link
+type CreateShareLinkRequest struct {
+	ExpiresInHours int  `json:"expires_in_hours" binding:"omitempty,min=1,max=720"` // Hotspot links also expire when the hotspot ends
+	IncludeQR      bool `json:"include_qr"`                                         // Also return the text to render as a QR code
+}
+
+// ShareLinkResponse is a created share link
+type ShareLinkResponse struct {
+	Code      string     `json:"code"`
+	URL       string     `json:"url"`       // Short web URL that opens the app when installed
+	DeepLink  string     `json:"deep_link"` // App URL scheme link
+	QRPayload string     `json:"qr_payload,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// ShareLinkPreview is what a share link resolves to for the user opening it
+type ShareLinkPreview struct {
+	Code    string           `json:"code"`
+	Kind    string           `json:"kind"`
+	Hotspot *PublicHotspot   `json:"hotspot,omitempty"`
+	Host    *PublicUser      `json:"host,omitempty"`
+	Joined  bool             `json:"joined"`   // Already attending
+	CanJoin bool             `json:"can_join"` // Join with POST /hotspots/:id/join?share=<code>
+	Reason  string           `json:"reason,omitempty"`
+	Profile *UserProfileView `json:"profile,omitempty"`
+}
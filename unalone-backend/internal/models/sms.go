@@ -0,0 +1,48 @@
+// SMS models for outbound messages and provider delivery reports
+package models
+
+import "time"
+
+// SMS message kinds; providers that require pre-registered templates pick one per kind
+const (
+	SMSKindVerification = "verification"
+	SMSKindSafetyAlert  = "safety_alert"
+)
+
+// SMS delivery statuses, normalized across providers
+const (
+	SMSStatusQueued      = "queued"
+	SMSStatusSent        = "sent"
+	SMSStatusDelivered   = "delivered"
+	SMSStatusFailed      = "failed"
+	SMSStatusUndelivered = "undelivered"
+)
+
+// SMSMessage is an outbound text message
+type SMSMessage struct {
+	To   string
+	Kind string
+	Body string
+	// Params carries template variables (e.g. "code") for providers that send by template
+	Params map[string]string
+}
+
+// SMSDelivery tracks a sent message until the provider reports a final status
+type SMSDelivery struct {
+	ID                string    `firestore:"id" json:"id"`
+	Provider          string    `firestore:"provider" json:"provider"`
+	ProviderMessageID string    `firestore:"provider_message_id" json:"provider_message_id"`
+	PhoneNumber       string    `firestore:"phone_number" json:"phone_number"`
+	Kind              string    `firestore:"kind" json:"kind"`
+	Status            string    `firestore:"status" json:"status"`
+	ErrorCode         string    `firestore:"error_code,omitempty" json:"error_code,omitempty"`
+	CreatedAt         time.Time `firestore:"created_at" json:"created_at"`
+	UpdatedAt         time.Time `firestore:"updated_at" json:"updated_at"`
+}
+
+// SMSStatusReport is a provider delivery callback normalized to one message
+type SMSStatusReport struct {
+	ProviderMessageID string
+	Status            string
+	ErrorCode         string
+}
@@ -0,0 +1,15 @@
+// Tag models for hotspot tag statistics
+package models
+
+// TagCount represents how many browsable hotspots use a tag
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+// PopularTagsRequest represents popular-tags query parameters
+type PopularTagsRequest struct {
+	City   string `form:"city" binding:"max=100"`  // Optional region; global counts when empty
+	Prefix string `form:"prefix" binding:"max=30"` // Optional autocomplete prefix
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=50"`
+}
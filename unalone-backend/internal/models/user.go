@@ -24,24 +24,66 @@ type User struct {
 	IsBlocked       bool      `firestore:"is_blocked" json:"is_blocked"`
 	BlockedBy       []string  `firestore:"blocked_by" json:"-"`   // Never send in JSON
 	ReportCount     int       `firestore:"report_count" json:"-"` // Never send in JSON
+	// Encrypted date of birth in Firestore, which then leaves date_of_birth zero
+	DateOfBirthSealed string `firestore:"date_of_birth_sealed,omitempty" json:"-"`
 	// Social graph
 	Friends                []string `firestore:"friends" json:"friends,omitempty"`
 	FriendRequestsReceived []string `firestore:"friend_requests_received" json:"friend_requests_received,omitempty"`
 	FriendRequestsSent     []string `firestore:"friend_requests_sent" json:"friend_requests_sent,omitempty"`
+	MutedFriends           []string `firestore:"muted_friends" json:"muted_friends,omitempty"` // Friends whose nearby activity is not alerted
+	// Note and timestamp for each received request, keyed by requester ID
+	FriendRequestDetails map[string]FriendRequestDetail `firestore:"friend_request_details" json:"friend_request_details,omitempty"`
 	// Gamification
 	Points     int       `firestore:"points" json:"points"`
 	Level      int       `firestore:"level" json:"level"`
 	LastActive time.Time `firestore:"last_active" json:"last_active"`
 	CreatedAt  time.Time `firestore:"created_at" json:"created_at"`
 	UpdatedAt  time.Time `firestore:"updated_at" json:"updated_at"`
+	// Status the user picked; see services.EffectiveAvailability for expiry
+	Availability *Availability `firestore:"availability" json:"availability,omitempty"`
+	// Computed for the user's own profile responses, see services.ComputeProfileCompleteness
+	Completeness *ProfileCompleteness `firestore:"-" json:"completeness,omitempty"`
+	// Organization the user belongs to, if any; see services.OrganizationService
+	OrgID       string     `firestore:"org_id" json:"org_id,omitempty"`
+	OrgRole     string     `firestore:"org_role" json:"org_role,omitempty"`
+	OrgJoinedAt *time.Time `firestore:"org_joined_at" json:"-"`
+}
+
+// CorrectDateOfBirthRequest is a support correction of a locked date of birth (admin)
+type CorrectDateOfBirthRequest struct {
+	DateOfBirth time.Time `json:"date_of_birth" binding:"required"`
+	Reason      string    `json:"reason" binding:"required,min=5,max=500"`
+}
+
+// Profile completeness items
+const (
+	ProfileItemPhoto         = "photo"
+	ProfileItemBio           = "bio"
+	ProfileItemInterests     = "interests"
+	ProfileItemPhoneVerified = "phone_verified"
+)
+
+// ProfileCompleteness is how much of the profile is filled in, with what is still missing
+type ProfileCompleteness struct {
+	Percent int      `json:"percent"`
+	Missing []string `json:"missing"` // Profile items in the order clients should prompt for them
 }
 
 // Location represents user's location
 type Location struct {
-	Latitude  float64 `firestore:"latitude" json:"latitude"`
-	Longitude float64 `firestore:"longitude" json:"longitude"`
-	City      string  `firestore:"city" json:"city,omitempty"`
-	Country   string  `firestore:"country" json:"country,omitempty"`
+	Latitude  float64    `firestore:"latitude" json:"latitude"`
+	Longitude float64    `firestore:"longitude" json:"longitude"`
+	City      string     `firestore:"city" json:"city,omitempty"`
+	Country   string     `firestore:"country" json:"country,omitempty"`
+	UpdatedAt *time.Time `firestore:"updated_at" json:"updated_at,omitempty"` // When the app last reported it
+}
+
+// UpdateLocationRequest reports the user's current position
+type UpdateLocationRequest struct {
+	Latitude  *float64 `json:"latitude" binding:"required,min=-90,max=90"`
+	Longitude *float64 `json:"longitude" binding:"required,min=-180,max=180"`
+	City      string   `json:"city" binding:"max=100"`
+	Country   string   `json:"country" binding:"max=100"`
 }
 
 // UserReport represents a user report
@@ -73,6 +115,52 @@ type PhoneVerification struct {
 type PublicUser struct {
 	ID       string `json:"id"`
 	Nickname string `json:"nickname"`
+	// Presence, omitted when the user hides their online status or is invisible
+	Online       *bool      `json:"online,omitempty"`
+	LastSeenAt   *time.Time `json:"last_seen_at,omitempty"`
+	Availability string     `json:"availability,omitempty"`
+}
+
+// FriendRequestDetail holds the optional note and send time of a pending friend request
+type FriendRequestDetail struct {
+	Note   string    `firestore:"note" json:"note,omitempty"`
+	SentAt time.Time `firestore:"sent_at" json:"sent_at"`
+}
+
+// FriendRequestEntry is a pending friend request as listed to either side
+type FriendRequestEntry struct {
+	ID        string    `json:"id"`
+	Nickname  string    `json:"nickname"`
+	Note      string    `json:"note,omitempty"`
+	SentAt    time.Time `json:"sent_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// FriendStatus describes how another user relates to the current user
+type FriendStatus string
+
+const (
+	FriendStatusFriend          FriendStatus = "friend"
+	FriendStatusRequestSent     FriendStatus = "request_sent"
+	FriendStatusRequestReceived FriendStatus = "request_received"
+	FriendStatusBlocked         FriendStatus = "blocked"
+	FriendStatusNone            FriendStatus = "none"
+)
+
+// FriendStatusRequest asks for the relationship with several users at once
+type FriendStatusRequest struct {
+	UserIDs []string `json:"user_ids" binding:"required,min=1,max=100"`
+}
+
+// SendFriendRequestRequest sends a friend request to a user ID or nickname
+type SendFriendRequestRequest struct {
+	Target string `json:"target" binding:"required"` // ID or nickname
+	Note   string `json:"note"`                      // Optional short message, see services.MaxFriendRequestNoteLength
+}
+
+// FriendActionRequest names the other user for accept, reject, remove, mute and unmute
+type FriendActionRequest struct {
+	UserID string `json:"user_id" binding:"required"`
 }
 
 // AuthRequest represents login/register request
@@ -0,0 +1,25 @@
+// Venue models: canonical places that hotspots are linked to
+package models
+
+import "time"
+
+// Venue is a canonical place such as a cafe or park. Hotspots created close to an
+// existing venue with a similar name are linked to it instead of creating a new one.
+type Venue struct {
+	ID        string          `firestore:"id" json:"id"`
+	Name      string          `firestore:"name" json:"name"`
+	NameKey   string          `firestore:"name_key" json:"-"` // Normalized name used for similarity matching
+	Location  HotspotLocation `firestore:"location" json:"location"`
+	Address   HotspotAddress  `firestore:"address" json:"address"`
+	Geohash   string          `firestore:"geohash" json:"-"` // 6-character cell, indexed for proximity lookups
+	CreatedBy string          `firestore:"created_by" json:"created_by"`
+	CreatedAt time.Time       `firestore:"created_at" json:"created_at"`
+}
+
+// VenueHotspotsResponse lists the browsable hotspots at a venue, soonest first
+type VenueHotspotsResponse struct {
+	Venue    *Venue                `json:"venue"`
+	Hotspots []HotspotWithDistance `json:"hotspots"`
+	Total    int                   `json:"total"`
+	HasMore  bool                  `json:"has_more"`
+}
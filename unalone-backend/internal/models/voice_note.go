@@ -0,0 +1,52 @@
+// Voice note models for audio messages in hotspot chats
+package models
+
+import "time"
+
+// Voice note processing states
+const (
+	VoiceNoteProcessing = "processing" // Uploaded, waiting to be transcoded
+	VoiceNoteReady      = "ready"      // Transcoded and posted to the chat
+	VoiceNoteFailed     = "failed"
+)
+
+// Voice note limits
+const (
+	MaxVoiceNoteBytes    = 512 << 10
+	MaxVoiceNoteDuration = 60 * time.Second
+)
+
+// VoiceNote tracks an uploaded voice note until it is posted to the chat
+type VoiceNote struct {
+	ID          string    `firestore:"id" json:"id"`
+	HotspotID   string    `firestore:"hotspot_id" json:"hotspot_id"`
+	UserID      string    `firestore:"user_id" json:"user_id"`
+	Status      string    `firestore:"status" json:"status"`
+	ContentType string    `firestore:"content_type" json:"content_type"` // Of the stored file; audio/ogg once transcoded
+	SizeBytes   int       `firestore:"size_bytes" json:"size_bytes"`
+	DurationMs  int       `firestore:"duration_ms" json:"duration_ms"`
+	ObjectKey   string    `firestore:"object_key" json:"-"`
+	MessageID   string    `firestore:"message_id" json:"message_id,omitempty"` // Chat message carrying the note, once ready
+	Error       string    `firestore:"error" json:"error,omitempty"`
+	CreatedAt   time.Time `firestore:"created_at" json:"created_at"`
+	UpdatedAt   time.Time `firestore:"updated_at" json:"updated_at"`
+}
+
+// UploadVoiceNoteRequest is the multipart form of a voice note upload; the file goes in "audio"
+type UploadVoiceNoteRequest struct {
+	HotspotID  string `form:"hotspot_id" binding:"required"`
+	DurationMs int    `form:"duration_ms" binding:"required,min=500,max=60000"`
+}
+
+// ChatAttachmentVoice is the attachment type of voice notes
+const ChatAttachmentVoice = "voice"
+
+// ChatAttachment is media attached to a chat message
+type ChatAttachment struct {
+	ID          string `firestore:"id" json:"id"`
+	Type        string `firestore:"type" json:"type"` // See ChatAttachmentVoice
+	ContentType string `firestore:"content_type" json:"content_type"`
+	SizeBytes   int    `firestore:"size_bytes" json:"size_bytes"`
+	DurationMs  int    `firestore:"duration_ms" json:"duration_ms"`
+	URL         string `firestore:"url" json:"url"` // API path that streams the file to hotspot members
+}
@@ -0,0 +1,427 @@
+// OpenAPI 3 document generation from route descriptions and Go request/response types
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Operation describes one route. Body, Query and Response are zero values of
+// the Go types the handler binds and returns; their schemas come from json,
+// form and binding tags, so the spec follows the structs.
+type Operation struct {
+	Method   string
+	Path     string // Gin syntax, e.g. /hotspots/:id
+	Tag      string
+	Summary  string
+	Public   bool        // No bearer token required
+	Query    interface{} // Struct with form tags
+	Params   []Param     // Query parameters read by hand
+	Body     interface{}
+	Response interface{} // Payload of the "data" envelope field; nil when there is none
+	Status   int         // Success status, 200 when zero
+	Bare     bool        // Response is returned as is, without the success envelope
+	Produces string      // Non-JSON response media type, e.g. text/calendar
+}
+
+// Param is a query parameter that is not described by a struct
+type Param struct {
+	Name        string
+	Type        string // string, integer, number or boolean
+	Required    bool
+	Description string
+}
+
+// Document is an OpenAPI 3.0 document
+type Document struct {
+	OpenAPI    string                           `json:"openapi"`
+	Info       Info                             `json:"info"`
+	Servers    []Server                         `json:"servers,omitempty"`
+	Paths      map[string]map[string]*PathEntry `json:"paths"`
+	Components Components                       `json:"components"`
+}
+
+// Info describes the API
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// Server is a base URL for the paths
+type Server struct {
+	URL string `json:"url"`
+}
+
+// PathEntry is an operation on a path
+type PathEntry struct {
+	Tags        []string              `json:"tags,omitempty"`
+	Summary     string                `json:"summary,omitempty"`
+	OperationID string                `json:"operationId"`
+	Parameters  []*Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+// Parameter is a path or query parameter
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Required    bool    `json:"required,omitempty"`
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody is a JSON request body
+type RequestBody struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+// Response is one response of an operation
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+// MediaType wraps a schema for a content type
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components holds the named schemas and security schemes
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes"`
+}
+
+// SecurityScheme describes bearer authentication
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// Schema is a JSON schema subset
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+}
+
+// errorSchema mirrors models.ErrorResponse without importing the models package
+var errorSchema = &Schema{
+	Type: "object",
+	Properties: map[string]*Schema{
+		"success": {Type: "boolean"},
+		"code":    {Type: "string"},
+		"message": {Type: "string"},
+		"errors":  {Type: "array", Items: &Schema{Type: "string"}},
+		"data":    {},
+	},
+	Required: []string{"success", "message"},
+}
+
+// Build generates the document for operations served under basePath
+func Build(title, version, basePath string, operations []Operation) *Document {
+	g := &generator{schemas: map[string]*Schema{"ErrorResponse": errorSchema}}
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info:    Info{Title: title, Version: version},
+		Servers: []Server{{URL: basePath}},
+		Paths:   make(map[string]map[string]*PathEntry),
+		Components: Components{
+			Schemas: g.schemas,
+			SecuritySchemes: map[string]*SecurityScheme{
+				"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+	}
+
+	for _, op := range operations {
+		path, pathParams := convertPath(op.Path)
+		entry := &PathEntry{
+			Summary:     op.Summary,
+			OperationID: operationID(op.Method, op.Path),
+			Parameters:  pathParams,
+			Responses:   map[string]*Response{"default": {Description: "Error", Content: jsonContent(&Schema{Ref: "#/components/schemas/ErrorResponse"})}},
+		}
+		if op.Tag != "" {
+			entry.Tags = []string{op.Tag}
+		}
+		if !op.Public {
+			entry.Security = []map[string][]string{{"bearerAuth": {}}}
+		}
+		if op.Query != nil {
+			entry.Parameters = append(entry.Parameters, g.queryParams(reflect.TypeOf(op.Query))...)
+		}
+		for _, p := range op.Params {
+			entry.Parameters = append(entry.Parameters, &Parameter{Name: p.Name, In: "query", Required: p.Required, Description: p.Description, Schema: &Schema{Type: p.Type}})
+		}
+		if op.Body != nil {
+			entry.RequestBody = &RequestBody{Required: true, Content: jsonContent(g.schemaFor(reflect.TypeOf(op.Body)))}
+		}
+
+		status := op.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		success := &Response{Description: http.StatusText(status)}
+		switch {
+		case op.Produces != "":
+			success.Content = map[string]*MediaType{op.Produces: {Schema: &Schema{Type: "string"}}}
+		case op.Bare && op.Response != nil:
+			success.Content = jsonContent(g.schemaFor(reflect.TypeOf(op.Response)))
+		case !op.Bare:
+			envelope := &Schema{
+				Type: "object",
+				Properties: map[string]*Schema{
+					"success": {Type: "boolean"},
+					"message": {Type: "string"},
+				},
+				Required: []string{"success", "message"},
+			}
+			if op.Response != nil {
+				envelope.Properties["data"] = g.schemaFor(reflect.TypeOf(op.Response))
+			}
+			success.Content = jsonContent(envelope)
+		}
+		entry.Responses[strconv.Itoa(status)] = success
+
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = make(map[string]*PathEntry)
+		}
+		doc.Paths[path][strings.ToLower(op.Method)] = entry
+	}
+	return doc
+}
+
+// generator turns Go types into schemas, registering named structs as components
+type generator struct {
+	schemas map[string]*Schema
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func (g *generator) schemaFor(t reflect.Type) *Schema {
+	if t.Kind() == reflect.Ptr {
+		return g.schemaFor(t.Elem())
+	}
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		name := t.Name()
+		if _, ok := g.schemas[name]; !ok {
+			// Register before recursing so self-referencing types terminate
+			g.schemas[name] = &Schema{}
+			*g.schemas[name] = *g.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	default:
+		// interface{} and anything else accept any JSON value
+		return &Schema{}
+	}
+}
+
+func (g *generator) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	g.addFields(schema, t)
+	sort.Strings(schema.Required)
+	return schema
+}
+
+func (g *generator) addFields(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, skip := jsonName(field)
+		if skip {
+			continue
+		}
+		if field.Anonymous && field.Tag.Get("json") == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				g.addFields(schema, embedded)
+				continue
+			}
+		}
+
+		property := g.schemaFor(field.Type)
+		if property.Ref == "" {
+			applyBinding(property, field.Tag.Get("binding"))
+			property.Nullable = field.Type.Kind() == reflect.Ptr
+		}
+		schema.Properties[name] = property
+		if bindingRequired(field.Tag.Get("binding")) {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+func (g *generator) queryParams(t reflect.Type) []*Parameter {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	var params []*Parameter
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("form"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		schema := g.schemaFor(field.Type)
+		applyBinding(schema, field.Tag.Get("binding"))
+		params = append(params, &Parameter{Name: name, In: "query", Required: bindingRequired(field.Tag.Get("binding")), Schema: schema})
+	}
+	return params
+}
+
+// jsonName returns the JSON property name of a field, or skip for fields never serialized
+func jsonName(field reflect.StructField) (name string, skip bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", true
+	}
+	name = strings.Split(tag, ",")[0]
+	if name == "" {
+		name = field.Name
+	}
+	return name, false
+}
+
+func bindingRequired(binding string) bool {
+	for _, rule := range strings.Split(binding, ",") {
+		if rule == "required" {
+			return true
+		}
+		if rule == "dive" {
+			break
+		}
+	}
+	return false
+}
+
+// applyBinding maps validator rules onto schema constraints
+func applyBinding(schema *Schema, binding string) {
+	for _, rule := range strings.Split(binding, ",") {
+		if rule == "dive" {
+			return
+		}
+		key, value, _ := strings.Cut(rule, "=")
+		switch key {
+		case "email":
+			schema.Format = "email"
+		case "url":
+			schema.Format = "uri"
+		case "oneof":
+			schema.Enum = strings.Fields(value)
+		case "min", "max", "len", "gte", "lte":
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			setBound(schema, key, n)
+		}
+	}
+}
+
+func setBound(schema *Schema, key string, n float64) {
+	lower := key == "min" || key == "gte" || key == "len"
+	upper := key == "max" || key == "lte" || key == "len"
+	count := int(n)
+	switch schema.Type {
+	case "string":
+		if lower {
+			schema.MinLength = &count
+		}
+		if upper {
+			schema.MaxLength = &count
+		}
+	case "array":
+		if lower {
+			schema.MinItems = &count
+		}
+		if upper {
+			schema.MaxItems = &count
+		}
+	case "integer", "number":
+		if lower {
+			schema.Minimum = &n
+		}
+		if upper {
+			schema.Maximum = &n
+		}
+	}
+}
+
+// convertPath turns /hotspots/:id into /hotspots/{id} and returns its path parameters
+func convertPath(path string) (string, []*Parameter) {
+	var params []*Parameter
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			name := segment[1:]
+			segments[i] = "{" + name + "}"
+			params = append(params, &Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}})
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// operationID derives a stable identifier such as postHotspotsIdJoin
+func operationID(method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, segment := range strings.FieldsFunc(path, func(r rune) bool { return r == '/' || r == ':' || r == '-' || r == '.' || r == '*' }) {
+		b.WriteString(strings.ToUpper(segment[:1]) + segment[1:])
+	}
+	return b.String()
+}
+
+func jsonContent(schema *Schema) map[string]*MediaType {
+	return map[string]*MediaType{"application/json": {Schema: schema}}
+}
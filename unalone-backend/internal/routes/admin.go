@@ -0,0 +1,36 @@
+// Admin routes
+package routes
+
+import "github.com/gin-gonic/gin"
+
+// RegisterAdminRoutes mounts operator routes (protected, ADMIN_EMAILS only)
+func RegisterAdminRoutes(rg *gin.RouterGroup, d *Deps) {
+	admin := rg.Group("/admin", d.Auth, d.Admin)
+	{
+		admin.PUT("/categories/:id", d.CategoryHandler.UpsertCategory)
+		admin.DELETE("/categories/:id", d.CategoryHandler.DeactivateCategory)
+		admin.PUT("/resources/:id", d.ResourceHandler.UpsertResource)
+		admin.DELETE("/resources/:id", d.ResourceHandler.DeactivateResource)
+		admin.PUT("/users/:id/date-of-birth", d.ProfileHandler.CorrectDateOfBirth)
+		admin.GET("/sos", d.SafetyHandler.ListSOSAlerts)
+		admin.GET("/jobs", d.SchedulerHandler.GetStatus)
+		admin.GET("/health", d.HealthHandler.GetStatus)
+		admin.PUT("/health/:dependency", d.HealthHandler.ForceDependency)
+		admin.GET("/queue", d.SchedulerHandler.GetQueueStats)
+		admin.GET("/queue/dead", d.SchedulerHandler.ListDeadJobs)
+		admin.POST("/queue/dead/:id/retry", d.SchedulerHandler.RetryDeadJob)
+		admin.GET("/metrics", d.MetricsHandler.GetMetrics)
+		admin.GET("/provider-usage", d.MetricsHandler.GetProviderUsage)
+		admin.GET("/firestore-usage", d.MetricsHandler.GetFirestoreUsage)
+		admin.GET("/experiments", d.ExperimentHandler.ListExperiments)
+		admin.PUT("/experiments/:key", d.ExperimentHandler.UpsertExperiment)
+		admin.GET("/experiments/:key/results", d.ExperimentHandler.GetResults)
+		admin.GET("/prompts", d.PromptHandler.ListPrompts)
+		admin.GET("/prompts/:key/versions", d.PromptHandler.ListVersions)
+		admin.POST("/prompts/:key/versions", d.PromptHandler.PublishVersion)
+		admin.GET("/imports/feeds", d.EventImportHandler.ListFeeds)
+		admin.POST("/imports/feeds", d.EventImportHandler.AddFeed)
+		admin.DELETE("/imports/feeds/:id", d.EventImportHandler.DeleteFeed)
+		admin.POST("/imports/feeds/:id/run", d.EventImportHandler.RunFeed)
+	}
+}
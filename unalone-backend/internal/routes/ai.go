@@ -0,0 +1,33 @@
+// AI assistant routes
+package routes
+
+import (
+	"log"
+
+	"unalone-backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterAIRoutes mounts the AI assistant (protected)
+func RegisterAIRoutes(rg *gin.RouterGroup, d *Deps) {
+	ai := rg.Group("/ai", d.Auth, middleware.BodyLimitMiddleware(32<<10)) // Prompts are short; keep model input bounded
+	{
+		ai.POST("/sessions", d.AIHandler.CreateSession)
+		ai.GET("/sessions", d.AIHandler.ListSessions)
+		ai.GET("/sessions/:id", d.AIHandler.GetSession)
+		ai.DELETE("/sessions/:id", d.AIHandler.DeleteSession)
+		ai.GET("/sessions/:id/export", d.AIHandler.ExportSession)
+		ai.GET("/sessions/:id/messages", d.AIHandler.GetMessages)
+		ai.POST("/sessions/:id/messages", d.AIHandler.SendMessage)
+		ai.POST("/sessions/:id/participants", d.AIHandler.AddParticipant)
+		ai.PUT("/sessions/:id/participants/:userId", d.AIHandler.UpdateParticipant)
+		ai.DELETE("/sessions/:id/participants/:userId", d.AIHandler.RemoveParticipant)
+		ai.POST("/sessions/:id/hotspot-drafts", d.AIHandler.DraftHotspot)
+		ai.POST("/sessions/:id/hotspot-drafts/:draftId/confirm", d.AIHandler.ConfirmHotspotDraft)
+		ai.GET("/insights", d.AIHandler.GetInsights)
+		ai.PUT("/insights/consent", d.AIHandler.UpdateInsightsConsent)
+	}
+
+	log.Printf("AI routes registered under /api/v1/ai (Create/List/Get/Delete/Export sessions, Get/Send messages, participants, hotspot drafts, insights)")
+}
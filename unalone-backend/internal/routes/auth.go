@@ -0,0 +1,14 @@
+// Authentication routes
+package routes
+
+import "github.com/gin-gonic/gin"
+
+// RegisterAuthRoutes mounts registration, login and token refresh
+func RegisterAuthRoutes(rg *gin.RouterGroup, d *Deps) {
+	auth := rg.Group("/auth")
+	{
+		auth.POST("/register", d.AuthHandler.Register)
+		auth.POST("/login", d.AuthHandler.Login)
+		auth.POST("/refresh", d.AuthHandler.RefreshToken)
+	}
+}
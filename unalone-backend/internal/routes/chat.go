@@ -0,0 +1,33 @@
+// Chat and live location routes
+package routes
+
+import "github.com/gin-gonic/gin"
+
+// RegisterChatRoutes mounts hotspot chat and live location sharing
+func RegisterChatRoutes(rg *gin.RouterGroup, d *Deps) {
+	// Chat WebSocket route WITHOUT auth middleware (validates a subprotocol token or ticket)
+	rg.GET("/hotspots/:id/chat/ws", d.ChatHandler.ChatWebSocket)
+
+	chat := rg.Group("/hotspots", d.Auth)
+	{
+		chat.GET("/:id/chat/messages", d.ChatHandler.GetRecentMessages)
+		chat.GET("/:id/chat/pins", d.ChatHandler.GetPins)
+		chat.POST("/:id/chat/pins", d.ChatHandler.PinMessage)
+		chat.DELETE("/:id/chat/pins/:messageId", d.ChatHandler.UnpinMessage)
+		chat.POST("/:id/chat/announcements", d.ChatHandler.PostAnnouncement)
+		chat.GET("/:id/chat/mute", d.ChatHandler.GetChatPrefs)
+		chat.POST("/:id/chat/mute", d.ChatHandler.MuteChat)
+		chat.DELETE("/:id/chat/mute", d.ChatHandler.UnmuteChat)
+		chat.POST("/:id/location-sharing", d.ChatHandler.StartLocationSharing)
+		chat.DELETE("/:id/location-sharing", d.ChatHandler.StopLocationSharing)
+		chat.GET("/:id/locations", d.ChatHandler.GetSharedLocations)
+	}
+
+	// Voice notes are uploaded as multipart forms and posted to the chat once transcoded
+	voiceNotes := rg.Group("/voice-notes", d.Auth)
+	{
+		voiceNotes.POST("", d.ChatHandler.UploadVoiceNote)
+		voiceNotes.GET("/:id", d.ChatHandler.GetVoiceNote)
+		voiceNotes.GET("/:id/audio", d.ChatHandler.DownloadVoiceNote)
+	}
+}
@@ -0,0 +1,21 @@
+// Club routes
+package routes
+
+import "github.com/gin-gonic/gin"
+
+// RegisterClubRoutes mounts club pages, club admin management and following (protected)
+func RegisterClubRoutes(rg *gin.RouterGroup, d *Deps) {
+	clubs := rg.Group("/clubs", d.Auth)
+	{
+		clubs.GET("", d.ClubHandler.ListClubs)
+		clubs.POST("", d.ClubHandler.CreateClub)
+		clubs.GET("/following", d.ClubHandler.ListFollowedClubs)
+		clubs.GET("/:id", d.ClubHandler.GetClub)
+		clubs.PUT("/:id", d.ClubHandler.UpdateClub)
+		clubs.GET("/:id/hotspots", d.ClubHandler.GetClubHotspots)
+		clubs.POST("/:id/admins", d.ClubHandler.AddClubAdmin)
+		clubs.DELETE("/:id/admins/:userId", d.ClubHandler.RemoveClubAdmin)
+		clubs.POST("/:id/follow", d.ClubHandler.FollowClub)
+		clubs.DELETE("/:id/follow", d.ClubHandler.UnfollowClub)
+	}
+}
@@ -0,0 +1,17 @@
+// Email digest routes
+package routes
+
+import "github.com/gin-gonic/gin"
+
+// RegisterDigestRoutes mounts weekly digest settings (protected) and the unsubscribe link (public)
+func RegisterDigestRoutes(rg *gin.RouterGroup, d *Deps) {
+	digest := rg.Group("/profile/email-digest", d.Auth)
+	{
+		digest.GET("", d.DigestHandler.GetDigest)
+		digest.PUT("", d.DigestHandler.UpdateDigest)
+		digest.GET("/preview", d.DigestHandler.PreviewDigest)
+	}
+
+	// Opened from the email, so it is authenticated by the signed token instead of a session
+	rg.GET("/email/unsubscribe", d.PublicRateLimit, d.DigestHandler.Unsubscribe)
+}
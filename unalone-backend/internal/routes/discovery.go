@@ -0,0 +1,27 @@
+// Category, interest, tag, place and wellbeing resource routes
+package routes
+
+import "github.com/gin-gonic/gin"
+
+// RegisterDiscoveryRoutes mounts categories, interests, tags, place autocomplete and wellbeing resources
+func RegisterDiscoveryRoutes(rg *gin.RouterGroup, d *Deps) {
+	// Category taxonomy (public reference data)
+	rg.GET("/categories", d.CategoryHandler.ListCategories)
+
+	// Interest catalog for profile pickers (public reference data)
+	rg.GET("/interests", d.InterestHandler.ListInterests)
+	rg.GET("/interests/suggest", d.InterestHandler.SuggestInterests)
+
+	// Helplines and support services, open to anyone who needs them
+	rg.GET("/resources", d.PublicRateLimit, d.ResourceHandler.GetResources)
+
+	tags := rg.Group("/tags", d.Auth)
+	{
+		tags.GET("/popular", d.TagHandler.GetPopularTags)
+	}
+
+	places := rg.Group("/places", d.Auth)
+	{
+		places.GET("/autocomplete", d.PlacesHandler.Autocomplete)
+	}
+}
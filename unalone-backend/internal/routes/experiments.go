@@ -0,0 +1,13 @@
+// A/B experiment routes
+package routes
+
+import "github.com/gin-gonic/gin"
+
+// RegisterExperimentRoutes mounts variant lookup and exposure logging (protected)
+func RegisterExperimentRoutes(rg *gin.RouterGroup, d *Deps) {
+	experiments := rg.Group("/experiments", d.Auth)
+	{
+		experiments.GET("", d.ExperimentHandler.GetAssignments)
+		experiments.POST("/exposures", d.ExperimentHandler.RecordExposure)
+	}
+}
@@ -0,0 +1,26 @@
+// Friends routes
+package routes
+
+import "github.com/gin-gonic/gin"
+
+// RegisterFriendsRoutes mounts friend requests, muting and friend lists (protected)
+func RegisterFriendsRoutes(rg *gin.RouterGroup, d *Deps) {
+	friends := rg.Group("/friends", d.Auth)
+	{
+		friends.GET("/", d.FriendsHandler.ListFriends)
+		friends.GET("/requests", d.FriendsHandler.ListRequests)
+		friends.POST("/status", d.FriendsHandler.GetStatuses)
+		friends.POST("/requests", d.FriendsHandler.SendRequest)
+		friends.POST("/accept", d.FriendsHandler.Accept)
+		friends.POST("/reject", d.FriendsHandler.Reject)
+		friends.POST("/remove", d.FriendsHandler.Remove)
+		friends.GET("/muted", d.FriendsHandler.ListMuted)
+		friends.POST("/mute", d.FriendsHandler.Mute)
+		friends.POST("/unmute", d.FriendsHandler.Unmute)
+		friends.GET("/lists", d.FriendListHandler.ListLists)
+		friends.POST("/lists", d.FriendListHandler.CreateList)
+		friends.GET("/lists/:id", d.FriendListHandler.GetList)
+		friends.PUT("/lists/:id", d.FriendListHandler.UpdateList)
+		friends.DELETE("/lists/:id", d.FriendListHandler.DeleteList)
+	}
+}
@@ -0,0 +1,60 @@
+// Hotspot routes
+package routes
+
+import "github.com/gin-gonic/gin"
+
+// RegisterHotspotRoutes mounts hotspot management, discovery, check-in, feedback and calendars
+func RegisterHotspotRoutes(rg *gin.RouterGroup, d *Deps) {
+	// Calendar subscription feed WITHOUT auth middleware (validates feed token in URL)
+	rg.GET("/calendar/feeds/:token", d.CalendarHandler.Feed)
+
+	hotspots := rg.Group("/hotspots", d.Auth)
+	{
+		hotspots.POST("/", d.HotspotHandler.CreateHotspot)
+		hotspots.POST("/batch", d.HotspotHandler.BatchGetHotspots)
+		hotspots.GET("/search", d.HotspotHandler.SearchHotspots)
+		hotspots.POST("/search/optimized", d.HotspotHandler.SearchHotspotsOptimized) // New optimized search
+		hotspots.GET("/nearby", d.HotspotHandler.GetNearbyHotspots)
+		hotspots.GET("/trending", d.HotspotHandler.GetTrendingHotspots)
+		hotspots.GET("/counts", d.HotspotHandler.GetHotspotCounts)
+		hotspots.GET("/my", d.HotspotHandler.GetUserHotspots)
+		hotspots.GET("/cities", d.HotspotHandler.ListCities)
+		hotspots.GET("/by-city/:city", d.HotspotHandler.GetHotspotsByCity)
+		hotspots.GET("/calendar/feed", d.CalendarHandler.GetFeedURL)
+		hotspots.POST("/calendar/feed/rotate", d.CalendarHandler.RotateFeedURL)
+		hotspots.GET("/:id", d.HotspotHandler.GetHotspot)
+		hotspots.PUT("/:id", d.HotspotHandler.UpdateHotspot)
+		hotspots.DELETE("/:id", d.HotspotHandler.DeleteHotspot)
+		hotspots.POST("/:id/clone", d.HotspotHandler.CloneHotspot)
+		hotspots.POST("/:id/publish", d.HotspotHandler.PublishHotspot)
+		hotspots.POST("/:id/join", d.HotspotHandler.JoinHotspot)
+		hotspots.POST("/:id/leave", d.HotspotHandler.LeaveHotspot)
+		hotspots.GET("/:id/attendees", d.HotspotHandler.GetAttendees)
+		hotspots.POST("/:id/checkin", d.SafetyHandler.CheckIn)
+		hotspots.GET("/:id/checkin/qr", d.SafetyHandler.GetCheckInQR)
+		hotspots.POST("/:id/checkin/qr", d.SafetyHandler.CheckInQR)
+		hotspots.POST("/:id/invite", d.HotspotHandler.InviteToHotspot)
+		hotspots.POST("/:id/share", d.ShareHandler.ShareHotspot)
+		hotspots.POST("/:id/cohosts", d.HotspotHandler.AddCoHost)
+		hotspots.DELETE("/:id/cohosts/:userId", d.HotspotHandler.RemoveCoHost)
+		hotspots.POST("/:id/transfer-ownership", d.HotspotHandler.TransferOwnership)
+		hotspots.DELETE("/:id/transfer-ownership", d.HotspotHandler.CancelOwnershipTransfer)
+		hotspots.POST("/:id/transfer-ownership/accept", d.HotspotHandler.AcceptOwnership)
+		hotspots.POST("/:id/transfer-ownership/decline", d.HotspotHandler.DeclineOwnership)
+		hotspots.POST("/:id/feedback", d.FeedbackHandler.SubmitFeedback)
+		hotspots.GET("/:id/calendar.ics", d.CalendarHandler.HotspotCalendar)
+		hotspots.GET("/:id/stats", d.HotspotHandler.GetHotspotStats)
+		hotspots.GET("/:id/analytics", d.HotspotHandler.GetHotspotAnalytics)
+		hotspots.GET("/:id/occupancy", d.HotspotHandler.GetOccupancyTimeline)
+		hotspots.GET("/:id/activity", d.HotspotHandler.GetHotspotActivity)
+
+		// Performance and debugging endpoints
+		hotspots.GET("/cache/stats", d.HotspotHandler.GetCacheStats)
+	}
+
+	// Post-event feedback
+	feedback := rg.Group("/feedback", d.Auth)
+	{
+		feedback.GET("/pending", d.FeedbackHandler.ListPending)
+	}
+}
@@ -0,0 +1,19 @@
+// Notification routes
+package routes
+
+import "github.com/gin-gonic/gin"
+
+// RegisterNotificationRoutes mounts the notification inbox and the realtime events WebSocket
+func RegisterNotificationRoutes(rg *gin.RouterGroup, d *Deps) {
+	// Per-user realtime events WebSocket WITHOUT auth middleware (validates a subprotocol token or ticket)
+	rg.GET("/ws", d.EventsHandler.UserEventsWebSocket)
+	// Single-use tickets for opening either WebSocket without putting the access token in the URL
+	rg.POST("/ws/tickets", d.Auth, d.EventsHandler.IssueTicket)
+
+	notifications := rg.Group("/notifications", d.Auth)
+	{
+		notifications.GET("/", d.NotificationHandler.ListNotifications)
+		notifications.POST("/read-all", d.NotificationHandler.MarkAllRead)
+		notifications.POST("/:id/read", d.NotificationHandler.MarkRead)
+	}
+}
@@ -0,0 +1,339 @@
+// OpenAPI document describing every route module
+package routes
+
+import (
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+
+	"unalone-backend/internal/jobs"
+	"unalone-backend/internal/models"
+	"unalone-backend/internal/openapi"
+	"unalone-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIVersion is reported in the OpenAPI document; bump it with breaking changes
+const APIVersion = "1.0.0"
+
+var (
+	locationParams = []openapi.Param{
+		{Name: "latitude", Type: "number", Required: true},
+		{Name: "longitude", Type: "number", Required: true},
+		{Name: "radius", Type: "number", Description: "Kilometers"},
+	}
+	pageParams = []openapi.Param{
+		{Name: "limit", Type: "integer"},
+		{Name: "offset", Type: "integer"},
+	}
+	searchParams = append(append([]openapi.Param{}, locationParams...), append([]openapi.Param{
+		{Name: "category", Type: "string"},
+		{Name: "is_active", Type: "boolean"},
+		{Name: "has_available_spots", Type: "boolean"},
+		{Name: "verified_hosts_only", Type: "boolean"},
+		{Name: "travel", Type: "boolean", Description: "Add walking and driving times to the first 25 results"},
+	}, pageParams...)...)
+	nearbyParams = []openapi.Param{
+		{Name: "latitude", Type: "number", Description: "Defaults to your last known location"},
+		{Name: "longitude", Type: "number", Description: "Defaults to your last known location"},
+		{Name: "verified_hosts_only", Type: "boolean"},
+		{Name: "travel", Type: "boolean", Description: "Add walking and driving times to the first 25 results"},
+	}
+	tokenParam = []openapi.Param{
+		{Name: "ticket", Type: "string", Description: "Single-use ticket from POST /ws/tickets"},
+		{Name: "token", Type: "string", Description: "Access token (deprecated; offer the subprotocols unalone.v1 and bearer.<token> instead)"},
+	}
+	chatSocketParams = append(append([]openapi.Param{}, tokenParam...), openapi.Param{
+		Name: "resume", Type: "string", Description: "ID of the last message received; replays the messages sent since before going live",
+	})
+)
+
+// Operations documents every route mounted by Modules. Add an entry with each new route;
+// NewRouter logs routes that are missing here.
+var Operations = []openapi.Operation{
+	// Auth
+	{Method: "POST", Path: "/auth/register", Tag: "auth", Summary: "Register", Public: true, Body: models.AuthRequest{}, Response: models.AuthResponse{}, Status: http.StatusCreated},
+	{Method: "POST", Path: "/auth/login", Tag: "auth", Summary: "Log in; 429 with Retry-After while locked out", Public: true, Body: models.AuthRequest{}, Response: models.AuthResponse{}},
+	{Method: "POST", Path: "/auth/refresh", Tag: "auth", Summary: "Refresh the access token", Public: true, Response: struct {
+		Token string `json:"token"`
+	}{}},
+
+	// Users and profile
+	{Method: "GET", Path: "/users/profile", Tag: "users", Summary: "Get your profile", Response: models.User{}},
+	{Method: "PUT", Path: "/users/profile", Tag: "users", Summary: "Update your profile", Body: models.UpdateProfileRequest{}, Response: models.User{}},
+	{Method: "PUT", Path: "/users/location", Tag: "users", Summary: "Update your last known location", Body: models.UpdateLocationRequest{}, Response: models.Location{}},
+	{Method: "DELETE", Path: "/users/location", Tag: "users", Summary: "Forget your last known location"},
+	{Method: "POST", Path: "/users/me/heartbeat", Tag: "users", Summary: "Mark yourself online; send every interval_seconds when not connected to the events WebSocket", Response: models.PresenceHeartbeat{}},
+	{Method: "GET", Path: "/users/me/onboarding", Tag: "users", Summary: "Your onboarding checklist; steps award points as they complete", Response: models.OnboardingProgress{}},
+	{Method: "GET", Path: "/users/:id", Tag: "users", Summary: "Get another user's public profile", Response: models.UserProfileView{}},
+	{Method: "PUT", Path: "/profile/update", Tag: "users", Summary: "Update your profile", Body: models.UpdateProfileRequest{}, Response: models.User{}},
+	{Method: "POST", Path: "/profile/image", Tag: "users", Summary: "Set your profile image", Body: models.UpdateProfileImageRequest{}, Response: models.ProfileImageUploadResponse{}},
+	{Method: "POST", Path: "/profile/phone/verify", Tag: "users", Summary: "Send a phone verification code", Body: models.PhoneVerificationRequest{}},
+	{Method: "POST", Path: "/profile/phone/confirm", Tag: "users", Summary: "Confirm a phone verification code", Body: models.VerifyPhoneRequest{}},
+	{Method: "GET", Path: "/profile/settings", Tag: "users", Summary: "Get your settings", Response: models.UserSettings{}},
+	{Method: "PUT", Path: "/profile/settings", Tag: "users", Summary: "Update your settings", Body: models.UpdateSettingsRequest{}, Response: models.UserSettings{}},
+	{Method: "GET", Path: "/profile/host-verification", Tag: "users", Summary: "Host verification progress", Response: models.HostVerification{}},
+	{Method: "PUT", Path: "/profile/availability", Tag: "users", Summary: "Set your status: available, busy (holds back non-safety pushes) or invisible", Body: models.SetAvailabilityRequest{}, Response: models.Availability{}},
+	{Method: "GET", Path: "/profile/nickname", Tag: "users", Summary: "Your nickname history and when you may change it next", Response: models.NicknameStatus{}},
+	{Method: "POST", Path: "/profile/share", Tag: "users", Summary: "Create a share link to your profile", Body: models.CreateShareLinkRequest{}, Response: models.ShareLinkResponse{}, Status: http.StatusCreated},
+	{Method: "GET", Path: "/profile/email-digest", Tag: "users", Summary: "Whether you get the weekly email digest", Response: models.DigestSubscription{}},
+	{Method: "PUT", Path: "/profile/email-digest", Tag: "users", Summary: "Turn the weekly email digest on or off", Body: models.UpdateDigestRequest{}, Response: models.DigestSubscription{}},
+	{Method: "GET", Path: "/profile/email-digest/preview", Tag: "users", Summary: "Render your weekly digest as it would be sent now", Response: struct {
+		Subject string `json:"subject"`
+		Body    string `json:"body"`
+	}{}},
+	{Method: "GET", Path: "/privacy/policy", Tag: "users", Summary: "How long each kind of personal data is kept before it is deleted", Public: true, Response: models.PrivacyPolicy{}},
+	{Method: "GET", Path: "/privacy/consents", Tag: "users", Summary: "Your standing for each consent purpose: location, analytics, ai_processing and marketing_email", Response: []models.ConsentState{}},
+	{Method: "GET", Path: "/privacy/consents/history", Tag: "users", Summary: "Every consent you granted or revoked, newest first", Response: []models.ConsentRecord{}},
+	{Method: "POST", Path: "/privacy/consents/:purpose", Tag: "users", Summary: "Grant a purpose at the version shown to you (409 if the text has changed)", Body: models.GrantConsentRequest{}, Response: models.ConsentState{}},
+	{Method: "DELETE", Path: "/privacy/consents/:purpose", Tag: "users", Summary: "Revoke a purpose; data collected under it is removed", Response: models.ConsentState{}},
+	{Method: "GET", Path: "/email/unsubscribe", Tag: "users", Summary: "Unsubscribe from the weekly digest with the signed link from the email", Public: true, Params: []openapi.Param{{Name: "user", Type: "string", Required: true}, {Name: "token", Type: "string", Required: true}}},
+	{Method: "GET", Path: "/experiments", Tag: "users", Summary: "Your variant of each active A/B experiment", Params: []openapi.Param{{Name: "keys", Type: "string", Description: "Comma-separated experiment keys to limit the result to"}}, Response: models.ExperimentAssignments{}},
+	{Method: "POST", Path: "/experiments/exposures", Tag: "users", Summary: "Log that you were shown your experiment variant", Body: models.RecordExposureRequest{}, Response: models.ExperimentExposure{}},
+	{Method: "GET", Path: "/history/events", Tag: "users", Summary: "Hotspots you attended", Params: pageParams, Response: []models.AttendanceRecord{}},
+	{Method: "GET", Path: "/history/people-met", Tag: "users", Summary: "People you met at hotspots", Params: pageParams, Response: []models.PersonMet{}},
+
+	// Friends
+	{Method: "GET", Path: "/friends/", Tag: "friends", Summary: "List friends", Response: []models.PublicUser{}},
+	{Method: "GET", Path: "/friends/requests", Tag: "friends", Summary: "List received and sent requests", Response: services.FriendRequests{}},
+	{Method: "POST", Path: "/friends/status", Tag: "friends", Summary: "Relationship with several users", Body: models.FriendStatusRequest{}, Response: map[string]models.FriendStatus{}},
+	{Method: "POST", Path: "/friends/requests", Tag: "friends", Summary: "Send a friend request", Body: models.SendFriendRequestRequest{}},
+	{Method: "POST", Path: "/friends/accept", Tag: "friends", Summary: "Accept a friend request", Body: models.FriendActionRequest{}},
+	{Method: "POST", Path: "/friends/reject", Tag: "friends", Summary: "Reject a friend request", Body: models.FriendActionRequest{}},
+	{Method: "POST", Path: "/friends/remove", Tag: "friends", Summary: "Remove a friend", Body: models.FriendActionRequest{}},
+	{Method: "GET", Path: "/friends/muted", Tag: "friends", Summary: "List muted friends", Response: []models.PublicUser{}},
+	{Method: "POST", Path: "/friends/mute", Tag: "friends", Summary: "Mute a friend's nearby alerts", Body: models.FriendActionRequest{}},
+	{Method: "POST", Path: "/friends/unmute", Tag: "friends", Summary: "Unmute a friend", Body: models.FriendActionRequest{}},
+	{Method: "GET", Path: "/friends/lists", Tag: "friends", Summary: "List your friend lists", Response: []models.FriendList{}},
+	{Method: "POST", Path: "/friends/lists", Tag: "friends", Summary: "Create a friend list", Body: models.CreateFriendListRequest{}, Response: models.FriendList{}, Status: http.StatusCreated},
+	{Method: "GET", Path: "/friends/lists/:id", Tag: "friends", Summary: "Get a friend list", Response: models.FriendList{}},
+	{Method: "PUT", Path: "/friends/lists/:id", Tag: "friends", Summary: "Update a friend list", Body: models.UpdateFriendListRequest{}, Response: models.FriendList{}},
+	{Method: "DELETE", Path: "/friends/lists/:id", Tag: "friends", Summary: "Delete a friend list"},
+
+	// Safety
+	{Method: "POST", Path: "/safety/block", Tag: "safety", Summary: "Block a user", Body: models.BlockUserRequest{}},
+	{Method: "POST", Path: "/safety/unblock", Tag: "safety", Summary: "Unblock a user", Body: models.BlockUserRequest{}},
+	{Method: "POST", Path: "/safety/report", Tag: "safety", Summary: "Report a user", Body: models.UserReportRequest{}},
+	{Method: "GET", Path: "/safety/contacts", Tag: "safety", Summary: "List trusted contacts", Response: []models.TrustedContact{}},
+	{Method: "POST", Path: "/safety/contacts", Tag: "safety", Summary: "Add a trusted contact", Body: models.AddTrustedContactRequest{}, Response: models.TrustedContact{}, Status: http.StatusCreated},
+	{Method: "DELETE", Path: "/safety/contacts/:id", Tag: "safety", Summary: "Remove a trusted contact"},
+	{Method: "GET", Path: "/safety/timers", Tag: "safety", Summary: "List safety timers", Response: []models.SafetyTimer{}},
+	{Method: "POST", Path: "/safety/timers/:id/safe", Tag: "safety", Summary: "Confirm you are safe", Response: models.SafetyTimer{}},
+	{Method: "POST", Path: "/safety/sos", Tag: "safety", Summary: "Raise an SOS", Body: models.SOSRequest{}, Response: models.SOSAlert{}, Status: http.StatusCreated},
+
+	// Organizations
+	{Method: "GET", Path: "/orgs/me", Tag: "organizations", Summary: "Your organization and role; the invite code is shown to organization admins", Response: models.OrgMembership{}},
+	{Method: "POST", Path: "/orgs/join", Tag: "organizations", Summary: "Join an organization with its invite code (409 if you already belong to one)", Body: models.JoinOrganizationRequest{}, Response: models.OrgMembership{}},
+	{Method: "POST", Path: "/orgs/leave", Tag: "organizations", Summary: "Leave your organization and return to the public network"},
+	{Method: "GET", Path: "/orgs/me/members", Tag: "organizations", Summary: "List your organization's members (organization admins)", Response: []models.OrgMember{}},
+	{Method: "PUT", Path: "/orgs/me/members/:userId/role", Tag: "organizations", Summary: "Make a member an admin or a regular member (organization admins)", Body: models.SetOrgRoleRequest{}, Response: models.OrgMember{}},
+	{Method: "DELETE", Path: "/orgs/me/members/:userId", Tag: "organizations", Summary: "Remove a member (organization admins)"},
+	{Method: "POST", Path: "/orgs/me/invite-code", Tag: "organizations", Summary: "Replace the invite code; the old one stops working (organization admins)", Response: models.Organization{}},
+
+	// Admin
+	{Method: "PUT", Path: "/admin/categories/:id", Tag: "admin", Summary: "Create or update a category", Body: models.UpsertCategoryRequest{}, Response: models.Category{}},
+	{Method: "DELETE", Path: "/admin/categories/:id", Tag: "admin", Summary: "Deactivate a category"},
+	{Method: "PUT", Path: "/admin/resources/:id", Tag: "admin", Summary: "Create or update a wellbeing resource", Body: models.UpsertResourceRequest{}, Response: models.WellbeingResource{}},
+	{Method: "DELETE", Path: "/admin/resources/:id", Tag: "admin", Summary: "Deactivate a wellbeing resource"},
+	{Method: "PUT", Path: "/admin/users/:id/date-of-birth", Tag: "admin", Summary: "Correct a locked date of birth (support)", Body: models.CorrectDateOfBirthRequest{}, Response: models.User{}},
+	{Method: "GET", Path: "/admin/sos", Tag: "admin", Summary: "List SOS alerts", Params: pageParams, Response: []models.SOSAlert{}},
+	{Method: "GET", Path: "/admin/jobs", Tag: "admin", Summary: "Scheduled job status", Response: models.SchedulerStatus{}},
+	{Method: "GET", Path: "/admin/health", Tag: "admin", Summary: "Service mode and the state of each dependency", Response: models.HealthReport{}},
+	{Method: "PUT", Path: "/admin/health/:dependency", Tag: "admin", Summary: "Force a dependency down on every instance, or hand it back to its health check", Body: models.ForceDependencyRequest{}, Response: models.HealthReport{}},
+	{Method: "GET", Path: "/admin/queue", Tag: "admin", Summary: "Background job queue counts and per-type results on this instance", Response: jobs.Stats{}},
+	{Method: "GET", Path: "/admin/queue/dead", Tag: "admin", Summary: "Dead-lettered jobs, newest first", Params: []openapi.Param{{Name: "limit", Type: "integer", Description: "1-200 (default 50)"}}, Response: []jobs.Job{}},
+	{Method: "POST", Path: "/admin/queue/dead/:id/retry", Tag: "admin", Summary: "Queue a dead-lettered job again with its attempts reset", Response: jobs.Job{}},
+	{Method: "GET", Path: "/admin/metrics", Tag: "admin", Summary: "Daily and weekly active users, registrations, hotspot, chat and AI activity", Params: []openapi.Param{{Name: "days", Type: "integer", Description: "Finished days to include, 1-90 (default 30)"}}, Response: models.MetricsDashboard{}},
+	{Method: "GET", Path: "/admin/provider-usage", Tag: "admin", Summary: "Gemini, SMS and geocoding calls, estimated cost and budget state per UTC day", Params: []openapi.Param{{Name: "days", Type: "integer", Description: "Days including today, 1-7 (default 7)"}}, Response: models.ProviderUsageReport{}},
+	{Method: "GET", Path: "/admin/firestore-usage", Tag: "admin", Summary: "Firestore documents read and written, rejected queries and daily budget state per UTC day", Params: []openapi.Param{{Name: "days", Type: "integer", Description: "Days including today, 1-7 (default 7)"}}, Response: models.FirestoreUsageReport{}},
+	{Method: "GET", Path: "/admin/experiments", Tag: "admin", Summary: "List A/B experiments", Response: []models.Experiment{}},
+	{Method: "PUT", Path: "/admin/experiments/:key", Tag: "admin", Summary: "Create or update an A/B experiment", Body: models.UpsertExperimentRequest{}, Response: models.Experiment{}},
+	{Method: "GET", Path: "/admin/experiments/:key/results", Tag: "admin", Summary: "Exposed users per experiment variant", Response: models.ExperimentResults{}},
+	{Method: "GET", Path: "/admin/prompts", Tag: "admin", Summary: "AI prompts with their current version and experiment overrides", Response: []models.PromptSummary{}},
+	{Method: "GET", Path: "/admin/prompts/:key/versions", Tag: "admin", Summary: "Every published version of an AI prompt, newest first", Response: []models.PromptTemplate{}},
+	{Method: "POST", Path: "/admin/prompts/:key/versions", Tag: "admin", Summary: "Publish a new AI prompt version, optionally only for an experiment variant", Body: models.PublishPromptRequest{}, Response: models.PromptTemplate{}, Status: http.StatusCreated},
+	{Method: "GET", Path: "/admin/imports/feeds", Tag: "admin", Summary: "List event import feeds", Response: []models.ImportFeed{}},
+	{Method: "POST", Path: "/admin/imports/feeds", Tag: "admin", Summary: "Add an ICS or JSON event import feed", Body: models.CreateImportFeedRequest{}, Response: models.ImportFeed{}},
+	{Method: "DELETE", Path: "/admin/imports/feeds/:id", Tag: "admin", Summary: "Stop importing from a feed"},
+	{Method: "POST", Path: "/admin/imports/feeds/:id/run", Tag: "admin", Summary: "Import a feed now", Response: models.ImportRun{}},
+	{Method: "GET", Path: "/admin/orgs", Tag: "admin", Summary: "List organizations", Response: []models.Organization{}},
+	{Method: "POST", Path: "/admin/orgs", Tag: "admin", Summary: "Create an organization with its first admin", Body: models.CreateOrganizationRequest{}, Response: models.Organization{}, Status: http.StatusCreated},
+
+	// Hotspots
+	{Method: "GET", Path: "/calendar/feeds/:token", Tag: "hotspots", Summary: "iCalendar feed of your hotspots", Public: true, Produces: "text/calendar"},
+	{Method: "POST", Path: "/hotspots/", Tag: "hotspots", Summary: "Create a hotspot", Body: models.CreateHotspotRequest{}, Response: models.Hotspot{}, Status: http.StatusCreated},
+	{Method: "POST", Path: "/hotspots/batch", Tag: "hotspots", Summary: "Fetch up to 50 hotspots by ID", Body: models.BatchHotspotsRequest{}, Response: models.BatchHotspotsResponse{}},
+	{Method: "GET", Path: "/hotspots/search", Tag: "hotspots", Summary: "Search hotspots near a point", Params: searchParams, Response: models.HotspotSearchResponse{}},
+	{Method: "POST", Path: "/hotspots/search/optimized", Tag: "hotspots", Summary: "Geo-indexed search with clustering", Body: models.OptimizedHotspotSearchRequest{}, Response: models.HotspotSearchResultOptimized{}},
+	{Method: "GET", Path: "/hotspots/nearby", Tag: "hotspots", Summary: "Hotspots near a point or your last known location", Params: nearbyParams, Response: models.HotspotSearchResponse{}},
+	{Method: "GET", Path: "/hotspots/trending", Tag: "hotspots", Summary: "Trending hotspots near a point", Params: append(append([]openapi.Param{}, locationParams...), openapi.Param{Name: "limit", Type: "integer"}), Response: []models.TrendingHotspot{}},
+	{Method: "GET", Path: "/hotspots/counts", Tag: "hotspots", Summary: "Hotspot counts per geohash cell for map badges", Params: []openapi.Param{
+		{Name: "bbox", Type: "string", Required: true, Description: "swLat,swLng,neLat,neLng"},
+		{Name: "precision", Type: "integer", Description: "Geohash length 1-6; picked from the bbox size when omitted"},
+	}, Response: models.HotspotCountsResponse{}},
+	{Method: "GET", Path: "/hotspots/my", Tag: "hotspots", Summary: "Hotspots you host", Response: []models.Hotspot{}},
+	{Method: "GET", Path: "/hotspots/cities", Tag: "hotspots", Summary: "Cities with browsable hotspots", Response: []models.CityHotspotCount{}},
+	{Method: "GET", Path: "/hotspots/by-city/:city", Tag: "hotspots", Summary: "Browse hotspots in a city", Params: append([]openapi.Param{{Name: "country", Type: "string"}, {Name: "verified_hosts_only", Type: "boolean"}}, pageParams...), Response: models.HotspotSearchResponse{}},
+	{Method: "GET", Path: "/hotspots/calendar/feed", Tag: "hotspots", Summary: "Your calendar feed URL", Response: struct {
+		URL string `json:"url"`
+	}{}},
+	{Method: "POST", Path: "/hotspots/calendar/feed/rotate", Tag: "hotspots", Summary: "Replace your calendar feed URL", Response: struct {
+		URL string `json:"url"`
+	}{}},
+	{Method: "GET", Path: "/hotspots/:id", Tag: "hotspots", Summary: "Get a hotspot; the ETag header carries its version", Response: models.Hotspot{}},
+	{Method: "PUT", Path: "/hotspots/:id", Tag: "hotspots", Summary: "Update a hotspot; send If-Match or version, 409 on conflict", Body: models.UpdateHotspotRequest{}, Response: models.Hotspot{}},
+	{Method: "DELETE", Path: "/hotspots/:id", Tag: "hotspots", Summary: "Delete a hotspot"},
+	{Method: "GET", Path: "/hotspots/:id/attendees", Tag: "hotspots", Summary: "Who is going, with availability and online status where shared", Response: []models.PublicUser{}},
+	{Method: "POST", Path: "/hotspots/:id/clone", Tag: "hotspots", Summary: "Clone a hotspot into a draft", Body: models.CloneHotspotRequest{}, Response: models.Hotspot{}, Status: http.StatusCreated},
+	{Method: "POST", Path: "/hotspots/:id/publish", Tag: "hotspots", Summary: "Publish a draft", Response: models.Hotspot{}},
+	{Method: "POST", Path: "/hotspots/:id/join", Tag: "hotspots", Summary: "Join a hotspot; ticketed hotspots answer 402 until you hold a paid ticket", Params: []openapi.Param{{Name: "share", Type: "string", Description: "Share link code, which lets you join a friend-list hotspot"}}, Response: models.Hotspot{}},
+	{Method: "POST", Path: "/hotspots/:id/leave", Tag: "hotspots", Summary: "Leave a hotspot", Response: models.Hotspot{}},
+	{Method: "POST", Path: "/hotspots/:id/checkin", Tag: "hotspots", Summary: "Check in on site", Body: models.CheckInRequest{}, Response: models.CheckInResponse{}},
+	{Method: "GET", Path: "/hotspots/:id/checkin/qr", Tag: "hotspots", Summary: "Rotating check-in QR code (host or co-host)", Response: models.CheckInQRCode{}},
+	{Method: "POST", Path: "/hotspots/:id/checkin/qr", Tag: "hotspots", Summary: "Check in by scanning the host's QR code", Body: models.QRCheckInRequest{}, Response: models.CheckInResponse{}},
+	{Method: "POST", Path: "/hotspots/:id/invite", Tag: "hotspots", Summary: "Invite friends", Body: models.InviteToHotspotRequest{}, Response: models.Hotspot{}},
+	{Method: "POST", Path: "/hotspots/:id/share", Tag: "hotspots", Summary: "Create a share link; host links to friend-list hotspots let the people who open them join", Body: models.CreateShareLinkRequest{}, Response: models.ShareLinkResponse{}, Status: http.StatusCreated},
+	{Method: "POST", Path: "/hotspots/:id/cohosts", Tag: "hotspots", Summary: "Make an attendee a co-host", Body: models.HotspotMemberRequest{}, Response: models.Hotspot{}},
+	{Method: "DELETE", Path: "/hotspots/:id/cohosts/:userId", Tag: "hotspots", Summary: "Remove a co-host", Response: models.Hotspot{}},
+	{Method: "POST", Path: "/hotspots/:id/transfer-ownership", Tag: "hotspots", Summary: "Offer the hotspot to an attendee", Body: models.HotspotMemberRequest{}, Response: models.Hotspot{}},
+	{Method: "DELETE", Path: "/hotspots/:id/transfer-ownership", Tag: "hotspots", Summary: "Withdraw your ownership offer", Response: models.Hotspot{}},
+	{Method: "POST", Path: "/hotspots/:id/transfer-ownership/accept", Tag: "hotspots", Summary: "Accept an ownership offer", Response: models.Hotspot{}},
+	{Method: "POST", Path: "/hotspots/:id/transfer-ownership/decline", Tag: "hotspots", Summary: "Decline an ownership offer", Response: models.Hotspot{}},
+	{Method: "POST", Path: "/hotspots/:id/feedback", Tag: "hotspots", Summary: "Rate a hotspot you attended", Body: models.SubmitFeedbackRequest{}, Response: models.HotspotFeedback{}, Status: http.StatusCreated},
+	{Method: "GET", Path: "/hotspots/:id/calendar.ics", Tag: "hotspots", Summary: "iCalendar event for a hotspot", Produces: "text/calendar"},
+	{Method: "GET", Path: "/hotspots/:id/stats", Tag: "hotspots", Summary: "View and impression counts (host only)", Response: models.HotspotStats{}},
+	{Method: "GET", Path: "/hotspots/:id/analytics", Tag: "hotspots", Summary: "Host analytics dashboard", Response: models.HotspotAnalytics{}},
+	{Method: "GET", Path: "/hotspots/:id/occupancy", Tag: "hotspots", Summary: "RSVP and check-in timeline (host only)", Params: []openapi.Param{
+		{Name: "interval_minutes", Type: "integer", Description: "Also sum changes into buckets of this many minutes (5-1440)"},
+	}, Response: models.OccupancyTimeline{}},
+	{Method: "GET", Path: "/venues/:id/hotspots", Tag: "hotspots", Summary: "Hotspots at a venue", Params: append([]openapi.Param{{Name: "verified_hosts_only", Type: "boolean"}}, pageParams...), Response: models.VenueHotspotsResponse{}},
+	{Method: "GET", Path: "/clubs", Tag: "clubs", Summary: "Clubs, most followed first", Params: append([]openapi.Param{{Name: "q", Type: "string", Description: "Only clubs whose name contains this"}}, pageParams...), Response: models.ClubListResponse{}},
+	{Method: "POST", Path: "/clubs", Tag: "clubs", Summary: "Create a club with you as its first admin", Body: models.CreateClubRequest{}, Response: models.Club{}, Status: http.StatusCreated},
+	{Method: "GET", Path: "/clubs/following", Tag: "clubs", Summary: "Clubs you follow", Response: []models.Club{}},
+	{Method: "GET", Path: "/clubs/:id", Tag: "clubs", Summary: "Club page with its admins and upcoming hotspots", Response: models.ClubProfile{}},
+	{Method: "PUT", Path: "/clubs/:id", Tag: "clubs", Summary: "Update a club's name, description or logo (club admins)", Body: models.UpdateClubRequest{}, Response: models.Club{}},
+	{Method: "GET", Path: "/clubs/:id/hotspots", Tag: "clubs", Summary: "Upcoming hotspots the club hosts, soonest first", Params: pageParams, Response: models.ClubHotspotsResponse{}},
+	{Method: "POST", Path: "/clubs/:id/admins", Tag: "clubs", Summary: "Make a user a club admin (club admins)", Body: models.ClubAdminRequest{}, Response: models.Club{}},
+	{Method: "DELETE", Path: "/clubs/:id/admins/:userId", Tag: "clubs", Summary: "Remove a club admin; the last one cannot be removed (club admins)", Response: models.Club{}},
+	{Method: "POST", Path: "/clubs/:id/follow", Tag: "clubs", Summary: "Follow a club to hear when it publishes a hotspot", Response: models.Club{}},
+	{Method: "DELETE", Path: "/clubs/:id/follow", Tag: "clubs", Summary: "Unfollow a club", Response: models.Club{}},
+	{Method: "GET", Path: "/hotspots/:id/ticket-tiers", Tag: "tickets", Summary: "Ticket tiers with how many are left, and your ticket", Response: models.HotspotTickets{}},
+	{Method: "PUT", Path: "/hotspots/:id/ticket-tiers", Tag: "tickets", Summary: "Replace ticket tiers; an empty list makes the hotspot free (host only)", Body: models.SetTicketTiersRequest{}, Response: models.Hotspot{}},
+	{Method: "POST", Path: "/hotspots/:id/tickets", Tag: "tickets", Summary: "Hold a ticket for 15 minutes and start checkout with the payment provider", Body: models.PurchaseTicketRequest{}, Response: models.PaymentCheckout{}, Status: http.StatusCreated},
+	{Method: "GET", Path: "/hotspots/:id/tickets", Tag: "tickets", Summary: "Ticket sales (host only)", Response: []models.Ticket{}},
+	{Method: "GET", Path: "/tickets", Tag: "tickets", Summary: "Your tickets, newest first", Response: []models.Ticket{}},
+	{Method: "GET", Path: "/tickets/:id", Tag: "tickets", Summary: "One of your tickets, e.g. to poll for payment confirmation", Response: models.Ticket{}},
+	{Method: "GET", Path: "/public/hotspots/nearby", Tag: "public", Summary: "Public hotspots nearby, without attendee identities (radius at most 25 km)", Public: true, Params: append(append([]openapi.Param{}, locationParams...), pageParams...), Response: models.PublicHotspotList{}},
+	{Method: "GET", Path: "/public/hotspots/by-city/:city", Tag: "public", Summary: "Public hotspots in a city", Public: true, Params: append([]openapi.Param{{Name: "country", Type: "string"}}, pageParams...), Response: models.PublicHotspotList{}},
+	{Method: "GET", Path: "/public/hotspots/:id", Tag: "public", Summary: "A public hotspot", Public: true, Response: models.PublicHotspot{}},
+	{Method: "GET", Path: "/share/:code", Tag: "share", Summary: "Resolve a share link to a hotspot preview with join eligibility, or a profile", Response: models.ShareLinkPreview{}},
+	{Method: "DELETE", Path: "/share/:code", Tag: "share", Summary: "Revoke a share link you created"},
+	{Method: "GET", Path: "/hotspots/:id/activity", Tag: "hotspots", Summary: "Audit log of changes (host only)", Params: pageParams, Response: models.HotspotActivityResponse{}},
+	{Method: "GET", Path: "/hotspots/cache/stats", Tag: "hotspots", Summary: "Search cache statistics", Response: map[string]interface{}{}},
+	{Method: "GET", Path: "/feedback/pending", Tag: "hotspots", Summary: "Hotspots awaiting your feedback", Response: []models.FeedbackRequest{}},
+
+	// Chat and live location
+	{Method: "GET", Path: "/hotspots/:id/chat/ws", Tag: "chat", Summary: "Chat WebSocket", Public: true, Params: chatSocketParams, Status: http.StatusSwitchingProtocols, Bare: true},
+	{Method: "GET", Path: "/hotspots/:id/chat/messages", Tag: "chat", Summary: "Recent chat messages", Response: []models.ChatMessage{}},
+	{Method: "GET", Path: "/hotspots/:id/chat/pins", Tag: "chat", Summary: "Pinned chat messages, earliest pinned first", Response: []models.ChatMessage{}},
+	{Method: "POST", Path: "/hotspots/:id/chat/pins", Tag: "chat", Summary: "Pin a chat message (host only, up to 3)", Body: models.PinMessageRequest{}, Response: models.ChatMessage{}},
+	{Method: "DELETE", Path: "/hotspots/:id/chat/pins/:messageId", Tag: "chat", Summary: "Unpin a chat message (host only)", Response: models.ChatMessage{}},
+	{Method: "POST", Path: "/hotspots/:id/chat/announcements", Tag: "chat", Summary: "Post a host announcement, pushed to every attendee", Body: models.SendMessageRequest{}, Response: models.ChatMessage{}},
+	{Method: "POST", Path: "/voice-notes", Tag: "chat", Summary: "Upload a voice note as multipart/form-data: audio file (up to 512 KB), hotspot_id and duration_ms (up to 60000); posted to the chat once transcoded", Status: 202, Response: models.VoiceNote{}},
+	{Method: "GET", Path: "/voice-notes/:id", Tag: "chat", Summary: "Voice note processing status", Response: models.VoiceNote{}},
+	{Method: "GET", Path: "/voice-notes/:id/audio", Tag: "chat", Summary: "Voice note audio for hotspot members", Bare: true, Produces: "audio/ogg"},
+	{Method: "GET", Path: "/hotspots/:id/chat/mute", Tag: "chat", Summary: "Your notification settings for this hotspot's chat", Response: models.ChatNotificationPrefs{}},
+	{Method: "POST", Path: "/hotspots/:id/chat/mute", Tag: "chat", Summary: "Mute chat notifications for 1h, 8h or forever", Body: models.MuteChatRequest{}, Response: models.ChatNotificationPrefs{}},
+	{Method: "DELETE", Path: "/hotspots/:id/chat/mute", Tag: "chat", Summary: "Unmute chat notifications", Response: models.ChatNotificationPrefs{}},
+	{Method: "POST", Path: "/hotspots/:id/location-sharing", Tag: "chat", Summary: "Start sharing your location", Body: models.StartLocationSharingRequest{}, Response: models.LocationShare{}},
+	{Method: "DELETE", Path: "/hotspots/:id/location-sharing", Tag: "chat", Summary: "Stop sharing your location"},
+	{Method: "GET", Path: "/hotspots/:id/locations", Tag: "chat", Summary: "Locations shared in a hotspot", Response: []models.LocationShare{}},
+
+	// Notifications
+	{Method: "POST", Path: "/ws/tickets", Tag: "notifications", Summary: "Issue a single-use WebSocket ticket, valid for 30 seconds", Response: models.WebSocketTicket{}},
+	{Method: "GET", Path: "/ws", Tag: "notifications", Summary: "Realtime events WebSocket", Public: true, Params: tokenParam, Status: http.StatusSwitchingProtocols, Bare: true},
+	{Method: "GET", Path: "/notifications/", Tag: "notifications", Summary: "Notification inbox", Params: append([]openapi.Param{{Name: "unread_only", Type: "boolean"}}, pageParams...), Response: models.NotificationListResponse{}},
+	{Method: "POST", Path: "/notifications/read-all", Tag: "notifications", Summary: "Mark all notifications read", Response: struct {
+		Updated int `json:"updated"`
+	}{}},
+	{Method: "POST", Path: "/notifications/:id/read", Tag: "notifications", Summary: "Mark a notification read"},
+
+	// Discovery
+	{Method: "GET", Path: "/categories", Tag: "discovery", Summary: "Hotspot categories", Public: true, Response: []models.Category{}},
+	{Method: "GET", Path: "/interests", Tag: "discovery", Summary: "Curated interest catalog by category, labelled in your language", Public: true, Response: []models.InterestCategory{}},
+	{Method: "GET", Path: "/interests/suggest", Tag: "discovery", Summary: "Catalog interests matching typed text, tolerating typos", Public: true, Query: models.SuggestInterestsRequest{}, Response: []models.InterestSuggestion{}},
+	{Method: "GET", Path: "/resources", Tag: "discovery", Summary: "Helplines, counseling centers and NGOs for a location, crisis services first", Public: true, Params: locationParams[:2], Response: models.ResourceDirectory{}},
+	{Method: "GET", Path: "/tags/popular", Tag: "discovery", Summary: "Popular tags", Query: models.PopularTagsRequest{}, Response: []models.TagCount{}},
+	{Method: "GET", Path: "/places/autocomplete", Tag: "discovery", Summary: "Place suggestions", Query: models.PlacesAutocompleteRequest{}, Response: models.PlacesAutocompleteResponse{}},
+
+	// AI assistant (responses are not wrapped in the success envelope)
+	{Method: "POST", Path: "/ai/sessions", Tag: "ai", Summary: "Start a session", Body: models.CreateAISessionRequest{}, Response: models.AIChatSession{}, Bare: true},
+	{Method: "GET", Path: "/ai/sessions", Tag: "ai", Summary: "List sessions", Response: []models.AIChatSession{}, Bare: true},
+	{Method: "GET", Path: "/ai/sessions/:id", Tag: "ai", Summary: "Get a session", Response: models.AIChatSession{}, Bare: true},
+	{Method: "DELETE", Path: "/ai/sessions/:id", Tag: "ai", Summary: "Permanently delete a session with its messages, drafts and mood insights (owner only)", Response: struct {
+		ID      string `json:"id"`
+		Deleted bool   `json:"deleted"`
+	}{}, Bare: true},
+	{Method: "GET", Path: "/ai/sessions/:id/export", Tag: "ai", Summary: "Download the full transcript as JSON or markdown (text/markdown)", Params: []openapi.Param{{Name: "format", Type: "string", Description: "json (default) or markdown"}}, Response: models.AISessionExport{}, Bare: true},
+	{Method: "GET", Path: "/ai/sessions/:id/messages", Tag: "ai", Summary: "Session messages", Params: []openapi.Param{{Name: "limit", Type: "integer"}}, Response: []models.AIMessage{}, Bare: true},
+	{Method: "POST", Path: "/ai/sessions/:id/messages", Tag: "ai", Summary: "Send a message and get the reply", Body: models.SendAIMessageRequest{}, Response: struct {
+		User *models.AIMessage `json:"user"`
+		AI   *models.AIMessage `json:"ai"`
+	}{}, Bare: true},
+	{Method: "POST", Path: "/ai/sessions/:id/participants", Tag: "ai", Summary: "Share a session with a friend, read_only or co_chat (owner only, up to 7)", Body: models.AddAIParticipantRequest{}, Response: models.AIChatSession{}, Bare: true},
+	{Method: "PUT", Path: "/ai/sessions/:id/participants/:userId", Tag: "ai", Summary: "Change a participant's role (owner only)", Body: models.UpdateAIParticipantRequest{}, Response: models.AIChatSession{}, Bare: true},
+	{Method: "POST", Path: "/ai/sessions/:id/hotspot-drafts", Tag: "ai", Summary: "Have the assistant draft a hotspot from the conversation; missing lists fields to fill before confirming", Body: models.DraftHotspotRequest{}, Response: models.AIHotspotDraft{}, Bare: true},
+	{Method: "POST", Path: "/ai/sessions/:id/hotspot-drafts/:draftId/confirm", Tag: "ai", Summary: "Create the drafted hotspot; an optional body replaces the draft with the edited request", Body: models.CreateHotspotRequest{}, Response: models.Hotspot{}, Status: http.StatusCreated, Bare: true},
+	{Method: "GET", Path: "/ai/insights", Tag: "ai", Summary: "Your private mood trend from AI sessions, by day and session (only while opted in)", Params: []openapi.Param{{Name: "days", Type: "integer", Description: "1-90, default 90"}}, Response: models.AIInsights{}, Bare: true},
+	{Method: "PUT", Path: "/ai/insights/consent", Tag: "ai", Summary: "Opt in to mood insights, or out, deleting them", Body: models.UpdateAIInsightsConsentRequest{}, Response: models.AIInsights{}, Bare: true},
+	{Method: "DELETE", Path: "/ai/sessions/:id/participants/:userId", Tag: "ai", Summary: "Remove a participant; participants can remove themselves to leave", Response: models.AIChatSession{}, Bare: true},
+
+	// SMS provider callbacks
+	{Method: "POST", Path: "/sms/status/:provider", Tag: "sms", Summary: "Delivery report webhook (form or JSON, provider specific)", Public: true, Params: []openapi.Param{{Name: "token", Type: "string", Required: true}}},
+
+	// Payment provider webhooks
+	{Method: "POST", Path: "/payments/webhook/:provider", Tag: "tickets", Summary: "Payment webhook (razorpay or stripe), verified by the provider's signature header", Public: true},
+
+	// This document
+	{Method: "GET", Path: "/openapi.json", Tag: "meta", Summary: "OpenAPI document", Public: true, Bare: true},
+}
+
+var (
+	specOnce sync.Once
+	spec     *openapi.Document
+)
+
+// Spec returns the OpenAPI document for Operations
+func Spec() *openapi.Document {
+	specOnce.Do(func() {
+		spec = openapi.Build("Unalone API", APIVersion, "/api/v1", Operations)
+	})
+	return spec
+}
+
+// RegisterDocsRoutes serves the OpenAPI document (public)
+func RegisterDocsRoutes(rg *gin.RouterGroup, d *Deps) {
+	rg.GET("/openapi.json", func(c *gin.Context) {
+		c.JSON(http.StatusOK, Spec())
+	})
+}
+
+// logUndocumentedRoutes warns about API routes that Operations does not describe
+func logUndocumentedRoutes(router *gin.Engine, basePath string) {
+	documented := make(map[string]bool, len(Operations))
+	for _, op := range Operations {
+		documented[op.Method+" "+basePath+op.Path] = true
+	}
+	var missing []string
+	for _, route := range router.Routes() {
+		if len(route.Path) > len(basePath) && route.Path[:len(basePath)] == basePath && !documented[route.Method+" "+route.Path] {
+			missing = append(missing, route.Method+" "+route.Path)
+		}
+	}
+	sort.Strings(missing)
+	for _, route := range missing {
+		log.Printf("OpenAPI: route %s is not documented in routes.Operations", route)
+	}
+}
@@ -0,0 +1,29 @@
+// Organization routes
+package routes
+
+import "github.com/gin-gonic/gin"
+
+// RegisterOrganizationRoutes mounts organization membership (protected), member management
+// (organization admins only) and organization setup (ADMIN_EMAILS only)
+func RegisterOrganizationRoutes(rg *gin.RouterGroup, d *Deps) {
+	orgs := rg.Group("/orgs", d.Auth)
+	{
+		orgs.GET("/me", d.OrganizationHandler.GetMyOrganization)
+		orgs.POST("/join", d.OrganizationHandler.JoinOrganization)
+		orgs.POST("/leave", d.OrganizationHandler.LeaveOrganization)
+	}
+
+	manage := rg.Group("/orgs/me", d.Auth, d.OrgAdmin)
+	{
+		manage.GET("/members", d.OrganizationHandler.ListMembers)
+		manage.PUT("/members/:userId/role", d.OrganizationHandler.SetMemberRole)
+		manage.DELETE("/members/:userId", d.OrganizationHandler.RemoveMember)
+		manage.POST("/invite-code", d.OrganizationHandler.RotateInviteCode)
+	}
+
+	admin := rg.Group("/admin/orgs", d.Auth, d.Admin)
+	{
+		admin.GET("", d.OrganizationHandler.ListOrganizations)
+		admin.POST("", d.OrganizationHandler.CreateOrganization)
+	}
+}
@@ -0,0 +1,24 @@
+// Ticket and payment routes
+package routes
+
+import "github.com/gin-gonic/gin"
+
+// RegisterPaymentRoutes mounts hotspot ticket tiers, ticket purchases and payment provider webhooks
+func RegisterPaymentRoutes(rg *gin.RouterGroup, d *Deps) {
+	// Payment webhooks WITHOUT auth middleware (validates the provider's signature)
+	rg.POST("/payments/webhook/:provider", d.PaymentHandler.Webhook)
+
+	hotspots := rg.Group("/hotspots", d.Auth)
+	{
+		hotspots.GET("/:id/ticket-tiers", d.PaymentHandler.GetTicketTiers)
+		hotspots.PUT("/:id/ticket-tiers", d.PaymentHandler.SetTicketTiers)
+		hotspots.POST("/:id/tickets", d.PaymentHandler.PurchaseTicket)
+		hotspots.GET("/:id/tickets", d.PaymentHandler.GetHotspotSales)
+	}
+
+	tickets := rg.Group("/tickets", d.Auth)
+	{
+		tickets.GET("", d.PaymentHandler.ListMyTickets)
+		tickets.GET("/:id", d.PaymentHandler.GetTicket)
+	}
+}
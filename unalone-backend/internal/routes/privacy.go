@@ -0,0 +1,18 @@
+// Privacy routes
+package routes
+
+import "github.com/gin-gonic/gin"
+
+// RegisterPrivacyRoutes mounts the public privacy policy and consent management (protected)
+func RegisterPrivacyRoutes(rg *gin.RouterGroup, d *Deps) {
+	// Readable before signing up
+	rg.GET("/privacy/policy", d.PublicRateLimit, d.PrivacyHandler.GetPolicy)
+
+	consents := rg.Group("/privacy/consents", d.Auth)
+	{
+		consents.GET("", d.PrivacyHandler.ListConsents)
+		consents.GET("/history", d.PrivacyHandler.ConsentHistory)
+		consents.POST("/:purpose", d.PrivacyHandler.GrantConsent)
+		consents.DELETE("/:purpose", d.PrivacyHandler.RevokeConsent)
+	}
+}
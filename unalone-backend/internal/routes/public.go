@@ -0,0 +1,14 @@
+// Public routes: read-only hotspot browsing without an account
+package routes
+
+import "github.com/gin-gonic/gin"
+
+// RegisterPublicRoutes mounts unauthenticated, rate-limited hotspot reads for the website and app preview
+func RegisterPublicRoutes(rg *gin.RouterGroup, d *Deps) {
+	public := rg.Group("/public", d.PublicRateLimit)
+	{
+		public.GET("/hotspots/nearby", d.PublicHandler.GetNearbyHotspots)
+		public.GET("/hotspots/by-city/:city", d.PublicHandler.GetHotspotsByCity)
+		public.GET("/hotspots/:id", d.PublicHandler.GetHotspot)
+	}
+}
@@ -0,0 +1,123 @@
+// Route registration: each domain module mounts its handlers on the API group
+package routes
+
+import (
+	"net/http"
+
+	"unalone-backend/internal/handlers"
+	"unalone-backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Deps carries the handlers and middleware the route modules need
+type Deps struct {
+	// Auth authenticates a request with its bearer token; Admin and OrgAdmin must run after it
+	Auth     gin.HandlerFunc
+	Admin    gin.HandlerFunc
+	OrgAdmin gin.HandlerFunc
+	CORS     *middleware.CORSPolicy
+	// PublicRateLimit throttles unauthenticated endpoints per client IP
+	PublicRateLimit gin.HandlerFunc
+	// ServiceMode reports degraded dependencies in response headers
+	ServiceMode gin.HandlerFunc
+	// FirestoreBudget gives each request its Firestore read budget
+	FirestoreBudget gin.HandlerFunc
+
+	AuthHandler         *handlers.AuthHandler
+	UserHandler         *handlers.UserHandler
+	ProfileHandler      *handlers.ProfileHandler
+	FriendsHandler      *handlers.FriendsHandler
+	FriendListHandler   *handlers.FriendListHandler
+	HistoryHandler      *handlers.HistoryHandler
+	FeedbackHandler     *handlers.FeedbackHandler
+	SafetyHandler       *handlers.SafetyHandler
+	HotspotHandler      *handlers.HotspotHandler
+	ChatHandler         *handlers.ChatHandler
+	AIHandler           *handlers.AIChatHandler
+	PlacesHandler       *handlers.PlacesHandler
+	CalendarHandler     *handlers.CalendarHandler
+	CategoryHandler     *handlers.CategoryHandler
+	ResourceHandler     *handlers.ResourceHandler
+	TagHandler          *handlers.TagHandler
+	NotificationHandler *handlers.NotificationHandler
+	EventsHandler       *handlers.EventsHandler
+	SMSHandler          *handlers.SMSHandler
+	SchedulerHandler    *handlers.SchedulerHandler
+	EventImportHandler  *handlers.EventImportHandler
+	PublicHandler       *handlers.PublicHandler
+	ShareHandler        *handlers.ShareHandler
+	DigestHandler       *handlers.DigestHandler
+	MetricsHandler      *handlers.MetricsHandler
+	ExperimentHandler   *handlers.ExperimentHandler
+	PromptHandler       *handlers.PromptHandler
+	InterestHandler     *handlers.InterestHandler
+	PresenceHandler     *handlers.PresenceHandler
+	PrivacyHandler      *handlers.PrivacyHandler
+	OrganizationHandler *handlers.OrganizationHandler
+	ClubHandler         *handlers.ClubHandler
+	PaymentHandler      *handlers.PaymentHandler
+	HealthHandler       *handlers.HealthHandler
+}
+
+// Module registers one domain's routes under the /api/v1 group
+type Module func(rg *gin.RouterGroup, d *Deps)
+
+// Modules lists every domain mounted by NewRouter
+var Modules = []Module{
+	RegisterAuthRoutes,
+	RegisterUserRoutes,
+	RegisterFriendsRoutes,
+	RegisterSafetyRoutes,
+	RegisterAdminRoutes,
+	RegisterOrganizationRoutes,
+	RegisterHotspotRoutes,
+	RegisterVenueRoutes,
+	RegisterClubRoutes,
+	RegisterPaymentRoutes,
+	RegisterPublicRoutes,
+	RegisterShareRoutes,
+	RegisterDigestRoutes,
+	RegisterPrivacyRoutes,
+	RegisterExperimentRoutes,
+	RegisterChatRoutes,
+	RegisterNotificationRoutes,
+	RegisterDiscoveryRoutes,
+	RegisterAIRoutes,
+	RegisterSMSRoutes,
+	RegisterDocsRoutes,
+}
+
+// NewRouter builds the engine with global middleware, the health check and all modules
+func NewRouter(d *Deps) *gin.Engine {
+	router := gin.Default()
+	middleware.ConfigureClientIP(router)
+
+	// Add middleware
+	handlers.SetWebSocketOriginCheck(d.CORS.CheckWebSocketOrigin)
+	router.Use(middleware.CORSMiddleware(d.CORS))
+	router.Use(d.ServiceMode)
+	router.Use(d.FirestoreBudget)
+	router.Use(middleware.LoggerMiddleware())
+	router.Use(middleware.LanguageMiddleware())
+	router.Use(middleware.BodyLimitMiddleware(middleware.MaxBodyBytes()))
+	router.Use(middleware.JSONContentTypeMiddleware("/api/v1/sms/status/", "/api/v1/voice-notes"))
+
+	// Health check endpoint
+	router.GET("/health", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"status":  "healthy",
+			"service": "unalone-backend",
+		})
+	})
+	// Readiness: the service mode and each dependency, 503 while a required one is down
+	router.GET("/health/ready", d.HealthHandler.Ready)
+
+	// API routes
+	v1 := router.Group("/api/v1")
+	for _, register := range Modules {
+		register(v1, d)
+	}
+	logUndocumentedRoutes(router, v1.BasePath())
+	return router
+}
@@ -0,0 +1,20 @@
+// Safety routes
+package routes
+
+import "github.com/gin-gonic/gin"
+
+// RegisterSafetyRoutes mounts blocking, reports, trusted contacts, safety timers and SOS (protected)
+func RegisterSafetyRoutes(rg *gin.RouterGroup, d *Deps) {
+	safety := rg.Group("/safety", d.Auth)
+	{
+		safety.POST("/block", d.ProfileHandler.BlockUser)
+		safety.POST("/unblock", d.ProfileHandler.UnblockUser)
+		safety.POST("/report", d.ProfileHandler.ReportUser)
+		safety.GET("/contacts", d.SafetyHandler.ListTrustedContacts)
+		safety.POST("/contacts", d.SafetyHandler.AddTrustedContact)
+		safety.DELETE("/contacts/:id", d.SafetyHandler.RemoveTrustedContact)
+		safety.GET("/timers", d.SafetyHandler.ListTimers)
+		safety.POST("/timers/:id/safe", d.SafetyHandler.ConfirmSafe)
+		safety.POST("/sos", d.SafetyHandler.RaiseSOS)
+	}
+}
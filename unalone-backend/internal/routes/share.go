@@ -0,0 +1,13 @@
+// Share link routes
+package routes
+
+import "github.com/gin-gonic/gin"
+
+// RegisterShareRoutes mounts share link resolution and revocation (protected)
+func RegisterShareRoutes(rg *gin.RouterGroup, d *Deps) {
+	share := rg.Group("/share", d.Auth)
+	{
+		share.GET("/:code", d.ShareHandler.ResolveShareLink)
+		share.DELETE("/:code", d.ShareHandler.RevokeShareLink)
+	}
+}
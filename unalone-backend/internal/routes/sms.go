@@ -0,0 +1,10 @@
+// SMS callback routes
+package routes
+
+import "github.com/gin-gonic/gin"
+
+// RegisterSMSRoutes mounts provider delivery reports
+func RegisterSMSRoutes(rg *gin.RouterGroup, d *Deps) {
+	// SMS delivery reports WITHOUT auth middleware (validates callback token in URL)
+	rg.POST("/sms/status/:provider", d.SMSHandler.StatusCallback)
+}
@@ -0,0 +1,39 @@
+// User, profile and history routes
+package routes
+
+import "github.com/gin-gonic/gin"
+
+// RegisterUserRoutes mounts user and profile routes (protected)
+func RegisterUserRoutes(rg *gin.RouterGroup, d *Deps) {
+	users := rg.Group("/users", d.Auth)
+	{
+		users.GET("/profile", d.UserHandler.GetProfile)
+		users.PUT("/profile", d.ProfileHandler.UpdateProfile)
+		users.PUT("/location", d.UserHandler.UpdateLocation)
+		users.DELETE("/location", d.UserHandler.ClearLocation)
+		users.GET("/me/onboarding", d.ProfileHandler.GetOnboarding)
+		users.POST("/me/heartbeat", d.PresenceHandler.Heartbeat)
+		users.GET("/:id", d.UserHandler.GetUserProfile)
+	}
+
+	profile := rg.Group("/profile", d.Auth)
+	{
+		profile.PUT("/update", d.ProfileHandler.UpdateProfile)
+		profile.POST("/image", d.ProfileHandler.UpdateProfileImage)
+		profile.POST("/phone/verify", d.ProfileHandler.SendPhoneVerification)
+		profile.POST("/phone/confirm", d.ProfileHandler.VerifyPhone)
+		profile.GET("/settings", d.ProfileHandler.GetSettings)
+		profile.PUT("/settings", d.ProfileHandler.UpdateSettings)
+		profile.GET("/host-verification", d.ProfileHandler.GetHostVerification)
+		profile.GET("/nickname", d.ProfileHandler.GetNickname)
+		profile.PUT("/availability", d.ProfileHandler.SetAvailability)
+		profile.POST("/share", d.ShareHandler.ShareProfile)
+	}
+
+	// Attendance history
+	history := rg.Group("/history", d.Auth)
+	{
+		history.GET("/events", d.HistoryHandler.GetEventHistory)
+		history.GET("/people-met", d.HistoryHandler.GetPeopleMet)
+	}
+}
@@ -0,0 +1,12 @@
+// Venue routes
+package routes
+
+import "github.com/gin-gonic/gin"
+
+// RegisterVenueRoutes mounts venue browsing (protected)
+func RegisterVenueRoutes(rg *gin.RouterGroup, d *Deps) {
+	venues := rg.Group("/venues", d.Auth)
+	{
+		venues.GET("/:id/hotspots", d.HotspotHandler.GetVenueHotspots)
+	}
+}
@@ -0,0 +1,98 @@
+// Date of birth validation and age-range discovery preferences
+package services
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"unalone-backend/internal/models"
+)
+
+const (
+	// MinUserAge is the youngest a user may be
+	MinUserAge = 18
+	// maxUserAge rejects dates of birth that are almost certainly typos
+	maxUserAge = 120
+	// fullAgeRangeMax is the top of the age range users can choose; a range up to it has no upper limit
+	fullAgeRangeMax = 100
+)
+
+// ErrDateOfBirthLocked is returned when a phone-verified user tries to change their date of birth
+var ErrDateOfBirthLocked = errors.New("date of birth cannot be changed after phone verification; contact support to correct it")
+
+// validateDateOfBirth checks that a date of birth is in the past and the user is an adult today
+func validateDateOfBirth(dateOfBirth, now time.Time) error {
+	if dateOfBirth.After(now) {
+		return errors.New("date of birth cannot be in the future")
+	}
+	age := ageOn(dateOfBirth, now)
+	if age < MinUserAge {
+		return errors.New("user must be at least 18 years old")
+	}
+	if age > maxUserAge {
+		return errors.New("invalid date of birth")
+	}
+	return nil
+}
+
+// DateOfBirthLocked reports whether a user's date of birth is locked: once their phone is verified the
+// age they gave backs audience checks, so only support can change it
+func DateOfBirthLocked(user *models.User) bool {
+	return user.IsPhoneVerified && !user.DateOfBirth.IsZero()
+}
+
+// sameDate compares two dates of birth by calendar day, ignoring time of day and zone
+func sameDate(a, b time.Time) bool {
+	ay, am, ad := a.UTC().Date()
+	by, bm, bd := b.UTC().Date()
+	return ay == by && am == bm && ad == bd
+}
+
+// UsersOutsideAgeRange returns which of userIDs are outside the viewer's preferred age range so
+// discovery can leave them out. Users without a date of birth are never excluded.
+func (ps *ProfileService) UsersOutsideAgeRange(viewerID string, userIDs []string) map[string]bool {
+	outside := make(map[string]bool)
+	settings, err := ps.GetUserSettings(viewerID)
+	if err != nil || (settings.AgeRangeMin <= MinUserAge && settings.AgeRangeMax >= fullAgeRangeMax) {
+		return outside
+	}
+
+	now := clock.Now()
+	checked := make(map[string]bool, len(userIDs))
+	for _, userID := range userIDs {
+		if userID == "" || userID == viewerID || checked[userID] {
+			continue
+		}
+		checked[userID] = true
+		user, err := ps.userService.GetUserByID(userID)
+		if err != nil || user.DateOfBirth.IsZero() {
+			continue
+		}
+		age := ageOn(user.DateOfBirth, now)
+		if age < settings.AgeRangeMin || (settings.AgeRangeMax < fullAgeRangeMax && age > settings.AgeRangeMax) {
+			outside[userID] = true
+		}
+	}
+	return outside
+}
+
+// CorrectDateOfBirth lets support change a locked date of birth, recording who changed it and why
+func (ps *ProfileService) CorrectDateOfBirth(userID string, req *models.CorrectDateOfBirthRequest, correctedBy string) (*models.User, error) {
+	if err := validateDateOfBirth(req.DateOfBirth, clock.Now()); err != nil {
+		return nil, err
+	}
+	if _, err := ps.userService.GetUserByID(userID); err != nil {
+		return nil, err
+	}
+
+	user, err := ps.userService.UpdateUser(userID, map[string]interface{}{
+		"date_of_birth": req.DateOfBirth,
+		"updated_at":    clock.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[support] date of birth for %s corrected by %s: %s", userID, correctedBy, req.Reason)
+	return user, nil
+}
@@ -0,0 +1,114 @@
+// Redis cache for AI replies that do not depend on the conversation: stub replies and
+// FAQ-like prompts such as "who are you?"
+package services
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"log"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	defaultAIStubCacheTTL = time.Hour
+	defaultAIFAQCacheTTL  = 24 * time.Hour
+)
+
+// aiFAQPrompts are normalized prompts whose answer only depends on the assistant's persona
+var aiFAQPrompts = map[string]bool{
+	"hi": true, "hello": true, "hey": true, "help": true, "namaste": true,
+	"who are you": true, "what are you": true, "what is your name": true, "whats your name": true,
+	"what can you do": true, "how can you help": true, "how can you help me": true,
+	"what is unalone": true, "whats unalone": true, "how does unalone work": true,
+	"are you a bot": true, "are you human": true, "are you a human": true, "are you real": true,
+	"are you a therapist": true, "are you a doctor": true,
+	"aap kaun ho": true, "tum kaun ho": true, "आप कौन हो": true, "तुम कौन हो": true,
+}
+
+// AIReplyCache keeps generated AI replies in Redis keyed by a hash of the normalized prompt
+// and everything else that shapes the reply. Without Redis it passes through.
+type AIReplyCache struct {
+	redis   *RedisService
+	stubTTL time.Duration
+	faqTTL  time.Duration
+
+	// loads lets one request per key reach the provider while identical ones wait for it
+	loads  singleflight.Group
+	hits   int64
+	misses int64
+}
+
+// aiCachedReply is the cached value
+type aiCachedReply struct {
+	Text string `json:"text"`
+}
+
+// NewAIReplyCache creates the cache; AI_STUB_CACHE_TTL and AI_FAQ_CACHE_TTL (e.g. "1h")
+// override the defaults, and "0" disables that kind of entry
+func NewAIReplyCache(rs *RedisService) *AIReplyCache {
+	return &AIReplyCache{
+		redis:   rs,
+		stubTTL: envTTL("AI_STUB_CACHE_TTL", defaultAIStubCacheTTL),
+		faqTTL:  envTTL("AI_FAQ_CACHE_TTL", defaultAIFAQCacheTTL),
+	}
+}
+
+// Reply returns the cached reply for a prompt, or generates and caches it. scope identifies
+// what else shapes the reply, such as the provider, model and system instruction. Only replies
+// generate reports as usable are cached; hit reports a cache hit.
+func (c *AIReplyCache) Reply(stub bool, scope, prompt string, generate func() (string, bool)) (text string, hit bool) {
+	if c == nil {
+		text, _ = generate()
+		return text, false
+	}
+	ttl := c.faqTTL
+	if stub {
+		ttl = c.stubTTL
+	}
+	if ttl <= 0 || !c.redis.IsAvailable() {
+		text, _ = generate()
+		return text, false
+	}
+
+	sum := sha1.Sum([]byte(scope + "\x00" + normalizeAIPrompt(prompt)))
+	key := "ai:reply:" + hex.EncodeToString(sum[:])
+	var cached aiCachedReply
+	if found, err := c.redis.GetCachedJSON(key, &cached); err == nil && found {
+		atomic.AddInt64(&c.hits, 1)
+		return cached.Text, true
+	}
+	atomic.AddInt64(&c.misses, 1)
+
+	value, _, _ := c.loads.Do(key, func() (interface{}, error) {
+		text, ok := generate()
+		if ok {
+			if err := c.redis.CacheJSON(key, aiCachedReply{Text: text}, ttl); err != nil {
+				log.Printf("Failed to cache AI reply: %v", err)
+			}
+		}
+		return text, nil
+	})
+	return value.(string), false
+}
+
+// isAIFAQPrompt reports whether a prompt is a stock question about the assistant
+func isAIFAQPrompt(prompt string) bool {
+	return aiFAQPrompts[normalizeAIPrompt(prompt)]
+}
+
+// normalizeAIPrompt lowercases a prompt, drops punctuation and collapses whitespace, so
+// "Who are you?" and "who are  you" share a cache entry
+func normalizeAIPrompt(prompt string) string {
+	words := strings.FieldsFunc(strings.ToLower(prompt), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsMark(r) && r != '\''
+	})
+	for i, word := range words {
+		words[i] = strings.ReplaceAll(word, "'", "")
+	}
+	return strings.Join(words, " ")
+}
@@ -17,6 +17,7 @@ import (
 	"sync"
 	"time"
 
+	"unalone-backend/internal/i18n"
 	"unalone-backend/internal/models"
 )
 
@@ -26,27 +27,98 @@ type AIChatService interface {
 	GetSession(ctx context.Context, userID, sessionID string) (*models.AIChatSession, error)
 	SendMessage(ctx context.Context, userID, sessionID, content string) (*models.AIMessage, *models.AIMessage, error)
 	GetMessages(ctx context.Context, userID, sessionID string, limit int) ([]*models.AIMessage, error)
+	AddParticipant(ctx context.Context, ownerID, sessionID, friendID, role string) (*models.AIChatSession, error)
+	UpdateParticipant(ctx context.Context, ownerID, sessionID, participantID, role string) (*models.AIChatSession, error)
+	RemoveParticipant(ctx context.Context, userID, sessionID, participantID string) (*models.AIChatSession, error)
+	DraftHotspot(ctx context.Context, userID, sessionID string, req *models.DraftHotspotRequest) (*models.AIHotspotDraft, error)
+	ConfirmHotspotDraft(ctx context.Context, userID, sessionID, draftID string, edited *models.CreateHotspotRequest, create func(*models.CreateHotspotRequest) (*models.Hotspot, error)) (*models.Hotspot, error)
+	GetInsights(ctx context.Context, userID string, days int) (*models.AIInsights, error)
+	SetInsightsConsent(ctx context.Context, userID string, enabled bool) (*models.AIInsights, error)
+	ExportSession(ctx context.Context, userID, sessionID string) (*models.AISessionExport, error)
+	DeleteSession(ctx context.Context, userID, sessionID string) error
 }
 
+// AI session errors
+var (
+	ErrAISessionNotFound     = errors.New("session not found")
+	ErrAINotSessionOwner     = errors.New("only the session owner can manage participants")
+	ErrAIReadOnly            = errors.New("this session is shared with you read-only")
+	ErrAINotFriend           = errors.New("sessions can only be shared with friends")
+	ErrAITooManyParticipants = fmt.Errorf("a session can be shared with at most %d friends", models.MaxAIParticipants)
+	ErrAIParticipantNotFound = errors.New("participant not found")
+	ErrAINotOwnerDelete      = errors.New("only the session owner can delete it; participants can leave instead")
+	ErrAIConsentRequired     = errors.New("you have not consented to AI processing of your messages")
+)
+
 type InMemoryAIChatService struct {
 	mu             sync.RWMutex
-	sessionsByUser map[string]map[string]*models.AIChatSession // userID -> sessionID -> session
+	sessionsByUser map[string]map[string]*models.AIChatSession // userID -> sessionID -> session, for owners and participants
 	messages       map[string][]*models.AIMessage              // sessionID -> messages
+	drafts         map[string]*models.AIHotspotDraft           // draftID -> hotspot draft
+	confirming     map[string]bool                             // draftIDs whose hotspot is being created
+	insightConsent map[string]time.Time                        // userID -> when they opted in to mood insights
+	insights       map[string]map[string]*aiMoodEntry          // userID -> sessionID/day -> mood aggregate
+	onMoodScored   func(userID string, score models.AIMoodScore)
 	geminiAPIKey   string
+	geminiBudget   time.Duration // Whole-call timeout, retries included
+	geminiAttempts int
+	contextTokens  int // Token budget for the system instruction plus history
+	breaker        geminiBreaker
+	httpClient     *http.Client
+	friends        *FriendsService
+	users          *UserService
+	categories     *CategoryService
+	resources      *ResourceService
+	prompts        *PromptService
+	replyCache     *AIReplyCache
+	locations      *UserLocationService
+	consents       *ConsentService
+	quota          *QuotaService
+	health         *HealthRegistry
 }
 
-func NewInMemoryAIChatService() *InMemoryAIChatService {
-	return &InMemoryAIChatService{
+func NewInMemoryAIChatService(fs *FriendsService, us *UserService, cs *CategoryService, rs *ResourceService, uls *UserLocationService, ps *PromptService, rc *AIReplyCache, consents *ConsentService, secrets SecretsProvider, quota *QuotaService, health *HealthRegistry) *InMemoryAIChatService {
+	budget, attempts := geminiSettings()
+	s := &InMemoryAIChatService{
 		sessionsByUser: make(map[string]map[string]*models.AIChatSession),
 		messages:       make(map[string][]*models.AIMessage),
-		geminiAPIKey:   strings.TrimSpace(os.Getenv("GEMINI_API_KEY")),
+		drafts:         make(map[string]*models.AIHotspotDraft),
+		confirming:     make(map[string]bool),
+		insightConsent: make(map[string]time.Time),
+		insights:       make(map[string]map[string]*aiMoodEntry),
+		geminiAPIKey:   lookupSecret(context.Background(), secrets, "GEMINI_API_KEY"),
+		geminiBudget:   budget,
+		geminiAttempts: attempts,
+		contextTokens:  aiContextTokens(),
+		httpClient:     &http.Client{},
+		friends:        fs,
+		users:          us,
+		categories:     cs,
+		resources:      rs,
+		prompts:        ps,
+		replyCache:     rc,
+		locations:      uls,
+		consents:       consents,
+		quota:          quota,
+		health:         health,
 	}
+	if s.GeminiConfigured() {
+		// The circuit breaker is Gemini's health check
+		health.Register(DependencyGemini, false, "AI replies and hotspot drafts come from the stub provider", nil)
+		s.breaker.report = func(err error) { health.Report(DependencyGemini, err) }
+	}
+	return s
+}
+
+// GeminiConfigured reports whether replies come from Gemini rather than the stub
+func (s *InMemoryAIChatService) GeminiConfigured() bool {
+	return s.geminiAPIKey != ""
 }
 
 func genID(n int) string {
 	b := make([]byte, n)
 	if _, err := rand.Read(b); err != nil {
-		return time.Now().Format("20060102150405")
+		return clock.Now().Format("20060102150405")
 	}
 	return hex.EncodeToString(b)
 }
@@ -58,7 +130,7 @@ func (s *InMemoryAIChatService) CreateSession(ctx context.Context, userID string
 	if strings.TrimSpace(title) == "" {
 		title = "New Chat"
 	}
-	now := time.Now()
+	now := clock.Now()
 	sess := &models.AIChatSession{
 		ID:        genID(8),
 		UserID:    userID,
@@ -98,6 +170,39 @@ func (s *InMemoryAIChatService) ListSessions(ctx context.Context, userID string)
 	return res, nil
 }
 
+// PurgeStaleSessions deletes sessions, and their messages, not updated since before cutoff.
+// It returns how many sessions were removed.
+func (s *InMemoryAIChatService) PurgeStaleSessions(cutoff time.Time) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	purged := 0
+	for userID, sessions := range s.sessionsByUser {
+		for sessionID, sess := range sessions {
+			if sess.UpdatedAt.Before(cutoff) {
+				delete(sessions, sessionID)
+				delete(s.messages, sessionID)
+				// Shared sessions are also listed under each participant; count them once
+				if sess.UserID == userID {
+					purged++
+				}
+			}
+		}
+		if len(sessions) == 0 {
+			delete(s.sessionsByUser, userID)
+		}
+	}
+	// Drafts go with their session, or once they can no longer be confirmed
+	now := clock.Now()
+	for draftID, draft := range s.drafts {
+		if _, ok := s.messages[draft.SessionID]; !ok || now.After(draft.ExpiresAt) {
+			delete(s.drafts, draftID)
+		}
+	}
+	// Insights outlive their sessions, holding no text, but not the trend window
+	s.purgeInsightsLocked(now)
+	return purged
+}
+
 func (s *InMemoryAIChatService) GetSession(ctx context.Context, userID, sessionID string) (*models.AIChatSession, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -116,26 +221,47 @@ func (s *InMemoryAIChatService) SendMessage(ctx context.Context, userID, session
 	if strings.TrimSpace(content) == "" {
 		return nil, nil, errors.New("content required")
 	}
+	if !s.consents.Allowed(userID, models.ConsentAIProcessing) {
+		return nil, nil, ErrAIConsentRequired
+	}
+	nickname := s.nickname(userID)
 	s.mu.Lock()
-	// verify session ownership
+	// verify the sender owns the session or co-chats in it
 	sessMap := s.sessionsByUser[userID]
 	if sessMap == nil || sessMap[sessionID] == nil {
 		s.mu.Unlock()
-		return nil, nil, errors.New("session not found")
+		return nil, nil, ErrAISessionNotFound
 	}
-	now := time.Now()
-	userMsg := &models.AIMessage{ID: genID(6), SessionID: sessionID, Role: "user", Content: content, CreatedAt: now}
+	sess := sessMap[sessionID]
+	if !aiCanChat(sess, userID) {
+		s.mu.Unlock()
+		return nil, nil, ErrAIReadOnly
+	}
+	group := len(sess.Participants) > 0
+	now := clock.Now()
+	userMsg := &models.AIMessage{ID: genID(6), SessionID: sessionID, Role: "user", AuthorID: userID, AuthorNickname: nickname, Content: content, CreatedAt: now}
 	s.messages[sessionID] = append(s.messages[sessionID], userMsg)
-	sessMap[sessionID].UpdatedAt = now
+	opening := len(s.messages[sessionID]) == 1
+	sess.UpdatedAt = now
 	s.mu.Unlock()
 
+	s.recordMood(userID, sessionID, content)
+
 	// Call Gemini or return a stubbed response
-	aiText := s.generateAIResponse(ctx, sessionID, content)
+	aiText, cached := s.reply(ctx, userID, sessionID, content, group, opening)
+
+	// Messages that suggest self-harm always get local helplines, whatever the model said
+	var resources []models.WellbeingResource
+	if isCrisisMessage(content) {
+		if resources = s.crisisResources(userID); len(resources) > 0 {
+			aiText = appendCrisisResources(aiText, resources)
+		}
+	}
 
 	s.mu.Lock()
-	aiMsg := &models.AIMessage{ID: genID(6), SessionID: sessionID, Role: "ai", Content: aiText, CreatedAt: time.Now()}
+	aiMsg := &models.AIMessage{ID: genID(6), SessionID: sessionID, Role: "ai", Content: aiText, Resources: resources, Cached: cached, CreatedAt: clock.Now()}
 	s.messages[sessionID] = append(s.messages[sessionID], aiMsg)
-	sessMap[sessionID].UpdatedAt = aiMsg.CreatedAt
+	sess.UpdatedAt = aiMsg.CreatedAt
 	s.mu.Unlock()
 
 	return userMsg, aiMsg, nil
@@ -146,7 +272,7 @@ func (s *InMemoryAIChatService) GetMessages(ctx context.Context, userID, session
 	defer s.mu.RUnlock()
 	sessMap := s.sessionsByUser[userID]
 	if sessMap == nil || sessMap[sessionID] == nil {
-		return nil, errors.New("session not found")
+		return nil, ErrAISessionNotFound
 	}
 	arr := s.messages[sessionID]
 	if limit <= 0 || limit > len(arr) {
@@ -155,11 +281,196 @@ func (s *InMemoryAIChatService) GetMessages(ctx context.Context, userID, session
 	return arr[len(arr)-limit:], nil
 }
 
+// AddParticipant shares an owner's session with one of their friends
+func (s *InMemoryAIChatService) AddParticipant(ctx context.Context, ownerID, sessionID, friendID, role string) (*models.AIChatSession, error) {
+	if friendID == ownerID {
+		return nil, ErrAINotFriend
+	}
+	statuses, err := s.friends.GetFriendStatuses(ownerID, []string{friendID})
+	if err != nil {
+		return nil, err
+	}
+	if statuses[friendID] != models.FriendStatusFriend {
+		return nil, ErrAINotFriend
+	}
+	nickname := s.nickname(friendID)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess, err := s.ownedSession(ownerID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	participants := append([]models.AIParticipant{}, sess.Participants...)
+	if p := findAIParticipant(sess, friendID); p != nil {
+		for i := range participants {
+			if participants[i].UserID == friendID {
+				participants[i].Role = role
+			}
+		}
+	} else {
+		if len(participants) >= models.MaxAIParticipants {
+			return nil, ErrAITooManyParticipants
+		}
+		participants = append(participants, models.AIParticipant{UserID: friendID, Nickname: nickname, Role: role, AddedAt: clock.Now()})
+		if s.sessionsByUser[friendID] == nil {
+			s.sessionsByUser[friendID] = make(map[string]*models.AIChatSession)
+		}
+		s.sessionsByUser[friendID][sessionID] = sess
+	}
+	sess.Participants = participants
+	return sess, nil
+}
+
+// UpdateParticipant changes a participant's role
+func (s *InMemoryAIChatService) UpdateParticipant(ctx context.Context, ownerID, sessionID, participantID, role string) (*models.AIChatSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess, err := s.ownedSession(ownerID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if findAIParticipant(sess, participantID) == nil {
+		return nil, ErrAIParticipantNotFound
+	}
+	participants := append([]models.AIParticipant{}, sess.Participants...)
+	for i := range participants {
+		if participants[i].UserID == participantID {
+			participants[i].Role = role
+		}
+	}
+	sess.Participants = participants
+	return sess, nil
+}
+
+// RemoveParticipant unshares a session. The owner can remove anyone; participants can remove themselves.
+func (s *InMemoryAIChatService) RemoveParticipant(ctx context.Context, userID, sessionID, participantID string) (*models.AIChatSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess := s.sessionsByUser[userID][sessionID]
+	if sess == nil {
+		return nil, ErrAISessionNotFound
+	}
+	if sess.UserID != userID && participantID != userID {
+		return nil, ErrAINotSessionOwner
+	}
+	if findAIParticipant(sess, participantID) == nil {
+		return nil, ErrAIParticipantNotFound
+	}
+	participants := make([]models.AIParticipant, 0, len(sess.Participants)-1)
+	for _, p := range sess.Participants {
+		if p.UserID != participantID {
+			participants = append(participants, p)
+		}
+	}
+	sess.Participants = participants
+	delete(s.sessionsByUser[participantID], sessionID)
+	if len(s.sessionsByUser[participantID]) == 0 {
+		delete(s.sessionsByUser, participantID)
+	}
+	return sess, nil
+}
+
+// ownedSession returns a session owned by ownerID; callers hold s.mu
+func (s *InMemoryAIChatService) ownedSession(ownerID, sessionID string) (*models.AIChatSession, error) {
+	sess := s.sessionsByUser[ownerID][sessionID]
+	if sess == nil {
+		return nil, ErrAISessionNotFound
+	}
+	if sess.UserID != ownerID {
+		return nil, ErrAINotSessionOwner
+	}
+	return sess, nil
+}
+
+// consentedAuthors returns a filter for history sent to the model: messages by users who
+// have since revoked AI processing consent are left out. Lookups are memoized per call.
+func (s *InMemoryAIChatService) consentedAuthors() func(m *models.AIMessage) bool {
+	allowed := make(map[string]bool)
+	return func(m *models.AIMessage) bool {
+		if m.Role != "user" || m.AuthorID == "" {
+			return true
+		}
+		ok, seen := allowed[m.AuthorID]
+		if !seen {
+			ok = s.consents.Allowed(m.AuthorID, models.ConsentAIProcessing)
+			allowed[m.AuthorID] = ok
+		}
+		return ok
+	}
+}
+
+// findAIParticipant returns a session's participant entry for userID, or nil
+func findAIParticipant(sess *models.AIChatSession, userID string) *models.AIParticipant {
+	for i := range sess.Participants {
+		if sess.Participants[i].UserID == userID {
+			return &sess.Participants[i]
+		}
+	}
+	return nil
+}
+
+// nickname looks up the name shown on a user's messages; lookups that fail leave it empty
+func (s *InMemoryAIChatService) nickname(userID string) string {
+	if s.users == nil {
+		return ""
+	}
+	user, err := s.users.GetUserByID(userID)
+	if err != nil {
+		return ""
+	}
+	return user.Nickname
+}
+
+// promptVars fills in the prompt template variables for a user
+func (s *InMemoryAIChatService) promptVars(ctx context.Context, userID string) map[string]string {
+	return map[string]string{
+		models.PromptVarUserName: s.nickname(userID),
+		models.PromptVarLanguage: i18n.Name(i18n.FromContext(ctx)),
+		models.PromptVarMood:     s.recentMood(userID),
+		models.PromptVarDate:     clock.Now().UTC().Format("Monday, 2 January 2006"),
+	}
+}
+
+// systemInstruction builds the system prompt for a user: the published template that applies
+// to them, plus notes for group sessions and their preferred language
+func (s *InMemoryAIChatService) systemInstruction(ctx context.Context, userID string, group bool) string {
+	sys := s.prompts.Render(models.PromptKeyAISystem, userID, s.promptVars(ctx, userID))
+	if group {
+		sys += "\n\nThis session is shared by a small group of friends planning something together. Each of their messages starts with the sender's nickname. Address people by nickname, keep track of what each person wants, and help the group agree on a plan."
+	}
+	// Reply in the user's preferred language from Accept-Language
+	if lang := i18n.FromContext(ctx); lang != i18n.English {
+		sys += "\n\nThe user's preferred language is " + i18n.Name(lang) + ". Reply in that language unless the user writes in another one."
+	}
+	return sys
+}
+
+// reply answers a user message, from the cache when the answer cannot depend on the
+// conversation: every stub reply, and FAQ-like prompts opening a one-on-one session.
+// Crisis messages always get a fresh reply. cached reports a cache hit.
+func (s *InMemoryAIChatService) reply(ctx context.Context, userID, sessionID, content string, group, opening bool) (text string, cached bool) {
+	generate := func() (string, bool) {
+		return s.generateAIResponse(ctx, userID, sessionID, content, group)
+	}
+	switch {
+	case isCrisisMessage(content):
+	case s.geminiAPIKey == "":
+		return s.replyCache.Reply(true, "stub", content, generate)
+	case !group && opening && isAIFAQPrompt(content):
+		return s.replyCache.Reply(false, "gemini\x00"+geminiModel()+"\x00"+s.systemInstruction(ctx, userID, false), content, generate)
+	}
+	text, _ = generate()
+	return text, false
+}
+
 // generateAIResponse produces a response using Gemini if configured, otherwise returns a simple echo.
-func (s *InMemoryAIChatService) generateAIResponse(ctx context.Context, sessionID string, content string) string {
+// In group sessions each user turn is prefixed with its author's nickname. ok is false for
+// the apologies returned when Gemini fails, so they are never cached.
+func (s *InMemoryAIChatService) generateAIResponse(ctx context.Context, userID, sessionID string, content string, group bool) (text string, ok bool) {
 	if s.geminiAPIKey == "" {
 		// Fallback local response when no key is configured
-		return "(AI) You said: " + content
+		return "(AI) You said: " + content, true
 	}
 
 	// Build conversation context with last few messages
@@ -194,50 +505,32 @@ func (s *InMemoryAIChatService) generateAIResponse(ctx context.Context, sessionI
 		// Ignore other fields
 	}
 
-	contents := make([]gemContent, 0, 12)
-	// Include up to last 10 messages of context
+	turns := make([]aiTurn, 0, 10)
+	// Include up to last 10 messages of context, as many as fit the token budget
 	start := 0
 	if len(history) > 10 {
 		start = len(history) - 10
 	}
+	allowed := s.consentedAuthors()
 	for _, m := range history[start:] {
+		if !allowed(m) {
+			continue
+		}
 		role := m.Role
 		if role == "ai" {
 			role = "model"
 		}
-		contents = append(contents, gemContent{Role: role, Parts: []gemPart{{Text: m.Content}}})
+		text := m.Content
+		if group && m.Role == "user" && m.AuthorNickname != "" {
+			text = m.AuthorNickname + ": " + text
+		}
+		turns = append(turns, aiTurn{Role: role, Text: text})
 	}
 
-	// System prompt emphasizing culturally sensitive mental health support (override with AI_SYSTEM_PROMPT)
-	sys := strings.TrimSpace(os.Getenv("AI_SYSTEM_PROMPT"))
-	if sys == "" {
-		// Detailed, culturally-sensitive persona for Indian students and young adults
-		sys = strings.TrimSpace(`
-You are Unalone’s Wellbeing Guide — a warm, non-clinical companion for students and young adults in India.
-
-Core persona and tone:
-- Speak like a caring senior, mentor, or friend: warm, respectful, and stigma-free.
-- Be concise, practical, and hopeful. Prefer short paragraphs and lists over long lectures.
-- Use simple English that’s comfortable for an Indian audience; you may acknowledge local context (family, exams, hostels, placements, finances, societal pressure, culture).
-
-Scope and boundaries:
-- You offer emotional support, reflection, coping skills, and psychoeducation.
-- You are NOT a medical professional; do not diagnose or prescribe. Add a gentle reminder: “I’m not a substitute for a professional.”
-- If you detect crisis, self-harm, harm to others, severe abuse, or immediate danger, encourage reaching out to trusted people and local emergency services/helplines. Do not provide step-by-step methods of self-harm.
-
-How to respond:
-- Start with brief, empathetic validation (reflect feelings without clichés).
-- Offer 2–5 concrete, low-effort steps (e.g., paced breathing, grounding, micro-breaks, journaling prompts, sleep hygiene tweaks) tailored to what they said.
-- Where relevant, suggest approachable, low-cost options (campus counselor, peer groups, government or NGO services, tele-helplines, college dean/mentor), framed as choices, never commands.
-- Encourage small check-ins, routines, and self-compassion rather than perfection.
-- When asked factual questions, answer simply and practically; cite if needed; avoid medical or legal claims.
-
-Style guidelines:
-- Prefer “Could we try…”, “One gentle option…”, “If it helps…”.
-- Avoid judgment, labels, or pathologizing language.
-- Keep most answers under ~180 words unless the user asks for depth.
-
-If the user asks “Who are you?”, answer as this persona (Unalone’s Wellbeing Guide) instead of calling yourself a generic large language model.`)
+	sys, turns := s.fitContext(s.systemInstruction(ctx, userID, group), turns)
+	contents := make([]gemContent, 0, len(turns))
+	for _, turn := range turns {
+		contents = append(contents, gemContent{Role: turn.Role, Parts: []gemPart{{Text: turn.Text}}})
 	}
 	sysContent := &struct {
 		Parts []gemPart `json:"parts"`
@@ -250,42 +543,65 @@ If the user asks “Who are you?”, answer as this persona (Unalone’s Wellbei
 			TopP        float64 `json:"topP,omitempty"`
 		}{Temperature: 0.6, TopP: 0.9},
 	}
-	reqBody, _ := json.Marshal(req)
-
-	model := strings.TrimSpace(os.Getenv("GEMINI_MODEL"))
-	if model == "" {
-		model = "gemini-2.5-flash"
+	body, err := s.postGemini(ctx, req)
+	if errors.Is(err, ErrGeminiUnavailable) {
+		// Circuit open: answer like the stub provider, without caching it
+		return "(AI) You said: " + content, false
 	}
-	url := fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/models/%s:generateContent", model)
-
-	client := &http.Client{Timeout: 20 * time.Second}
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(reqBody))
-	if err != nil {
-		log.Printf("gemini request build error: %v", err)
-		return "I'm having trouble reaching AI right now. Please try again."
-	}
-	httpReq.Header.Set("Content-Type", "application/json")
-	// Per latest docs, pass API key via header
-	httpReq.Header.Set("x-goog-api-key", s.geminiAPIKey)
-
-	resp, err := client.Do(httpReq)
 	if err != nil {
-		log.Printf("gemini http error: %v", err)
-		return "I'm having trouble reaching AI right now. Please try again."
-	}
-	defer resp.Body.Close()
-	body, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		log.Printf("gemini non-200: %d body=%s", resp.StatusCode, string(body))
-		return "I'm having trouble reaching AI right now. Please try again."
+		log.Printf("gemini: %v", err)
+		return "I'm having trouble reaching AI right now. Please try again.", false
 	}
 	var gr gemResponse
 	if err := json.Unmarshal(body, &gr); err != nil {
 		log.Printf("gemini parse error: %v body=%s", err, string(body))
-		return "I'm having trouble reading AI's response."
+		return "I'm having trouble reading AI's response.", false
 	}
 	if len(gr.Candidates) == 0 || len(gr.Candidates[0].Content.Parts) == 0 {
-		return "I'm not sure how to respond to that yet. Could you rephrase?"
+		return "I'm not sure how to respond to that yet. Could you rephrase?", false
+	}
+	return gr.Candidates[0].Content.Parts[0].Text, true
+}
+
+// geminiModel returns GEMINI_MODEL, defaulting to gemini-2.5-flash
+func geminiModel() string {
+	if model := strings.TrimSpace(os.Getenv("GEMINI_MODEL")); model != "" {
+		return model
+	}
+	return "gemini-2.5-flash"
+}
+
+// postGemini sends a generateContent request and returns the response body, retrying
+// transient failures within the timeout budget. It returns ErrGeminiUnavailable while the
+// circuit breaker is open.
+func (s *InMemoryAIChatService) postGemini(ctx context.Context, payload interface{}) ([]byte, error) {
+	reqBody, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
 	}
-	return gr.Candidates[0].Content.Parts[0].Text
+	url := fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/models/%s:generateContent", geminiModel())
+
+	return s.withGeminiRetries(ctx, func(ctx context.Context) ([]byte, error) {
+		httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(reqBody))
+		if err != nil {
+			return nil, fmt.Errorf("request build error: %w", err)
+		}
+		httpReq.Header.Set("Content-Type", "application/json")
+		// Per latest docs, pass API key via header
+		httpReq.Header.Set("x-goog-api-key", s.geminiAPIKey)
+
+		resp, err := s.httpClient.Do(httpReq)
+		if err != nil {
+			return nil, fmt.Errorf("http error: %w", err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("read error: %w", err)
+		}
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return nil, &geminiStatusError{StatusCode: resp.StatusCode, Body: string(body), RetryAfter: geminiRetryAfter(resp.Header.Get("Retry-After"))}
+		}
+		return body, nil
+	})
 }
@@ -0,0 +1,155 @@
+// Token-aware context for AI requests: estimation, truncation and summaries of history that does not fit
+package services
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// defaultAIContextTokens bounds the system instruction plus history sent with each request
+	defaultAIContextTokens = 8000
+	// minAIContextTokens keeps a misconfigured budget from dropping the conversation entirely
+	minAIContextTokens = 1000
+	// aiTurnOverheadTokens approximates the role and framing around each message
+	aiTurnOverheadTokens = 4
+	// aiSummaryLineRunes bounds each message's line in the summary of older history
+	aiSummaryLineRunes = 160
+	aiTruncationMarker = " … [truncated] … "
+)
+
+// aiTurn is one history message as sent to the model, role "user" or "model"
+type aiTurn struct {
+	Role string
+	Text string
+}
+
+// aiContextTokens reads AI_CONTEXT_TOKENS
+func aiContextTokens() int {
+	raw := os.Getenv("AI_CONTEXT_TOKENS")
+	if raw == "" {
+		return defaultAIContextTokens
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < minAIContextTokens {
+		log.Printf("Invalid AI_CONTEXT_TOKENS %q, using %d", raw, defaultAIContextTokens)
+		return defaultAIContextTokens
+	}
+	return n
+}
+
+// estimateTokens approximates the model's token count without a tokenizer: about four
+// ASCII characters per token, and one token per character of other scripts such as
+// Devanagari, which tokenize far less densely. It errs on the high side.
+func estimateTokens(text string) int {
+	ascii, other := 0, 0
+	for _, r := range text {
+		if r < utf8.RuneSelf {
+			ascii++
+		} else {
+			other++
+		}
+	}
+	return (ascii+3)/4 + other
+}
+
+// truncateToTokens shortens text to about the given tokens, keeping its start and end
+// since a long message usually states its point in one of them
+func truncateToTokens(text string, tokens int) string {
+	estimate := estimateTokens(text)
+	if estimate <= tokens {
+		return text
+	}
+	runes := []rune(text)
+	keep := len(runes) * (tokens - estimateTokens(aiTruncationMarker)) / estimate
+	if keep <= 0 {
+		return ""
+	}
+	head := keep * 2 / 3
+	return strings.TrimSpace(string(runes[:head])) + aiTruncationMarker + strings.TrimSpace(string(runes[len(runes)-(keep-head):]))
+}
+
+// fitContext fits history into the AI_CONTEXT_TOKENS budget alongside the system instruction.
+// The newest message is always kept, truncated if it alone is too long; older messages are
+// kept newest first, each truncated to a third of the budget, until the budget runs out.
+// Messages that do not fit are summarized into the returned system instruction instead.
+func (s *InMemoryAIChatService) fitContext(sys string, turns []aiTurn) (string, []aiTurn) {
+	budget := s.contextTokens - estimateTokens(sys)
+	if budget < minAIContextTokens/2 {
+		budget = minAIContextTokens / 2
+	}
+	// An eighth of the budget is set aside for the summary
+	summaryBudget := budget / 8
+	remaining := budget - summaryBudget
+	turnCap := budget / 3
+
+	cut := 0
+	kept := make([]aiTurn, 0, len(turns))
+	for i := len(turns) - 1; i >= 0; i-- {
+		turn := turns[i]
+		limit := remaining - aiTurnOverheadTokens
+		if i < len(turns)-1 && limit > turnCap {
+			limit = turnCap
+		}
+		if i == len(turns)-1 {
+			turn.Text = truncateToTokens(turn.Text, limit)
+		} else if estimateTokens(turn.Text) > limit {
+			truncated := truncateToTokens(turn.Text, limit)
+			if limit < turnCap || truncated == "" {
+				// Out of budget rather than an overlong message: summarize from here back
+				cut = i + 1
+				break
+			}
+			turn.Text = truncated
+		}
+		remaining -= estimateTokens(turn.Text) + aiTurnOverheadTokens
+		kept = append(kept, turn)
+	}
+	// Conversations sent to the model start with the user, so a leading reply joins the summary
+	if len(kept) > 1 && kept[len(kept)-1].Role == "model" {
+		kept = kept[:len(kept)-1]
+		cut++
+	}
+	for i, j := 0, len(kept)-1; i < j; i, j = i+1, j-1 {
+		kept[i], kept[j] = kept[j], kept[i]
+	}
+
+	if cut == 0 {
+		return sys, kept
+	}
+	return sys + "\n\n" + summarizeTurns(turns[:cut], summaryBudget), kept
+}
+
+// summarizeTurns condenses older messages into one line each, keeping the most recent
+// that fit within tokens
+func summarizeTurns(turns []aiTurn, tokens int) string {
+	const header = "Earlier in this conversation (summarized, oldest first):"
+	remaining := tokens - estimateTokens(header)
+	lines := make([]string, 0, len(turns))
+	for i := len(turns) - 1; i >= 0; i-- {
+		speaker := "User"
+		if turns[i].Role == "model" {
+			speaker = "You"
+		}
+		text := strings.Join(strings.Fields(turns[i].Text), " ")
+		if runes := []rune(text); len(runes) > aiSummaryLineRunes {
+			text = strings.TrimSpace(string(runes[:aiSummaryLineRunes])) + "…"
+		}
+		line := "- " + speaker + ": " + text
+		if estimateTokens(line) > remaining {
+			break
+		}
+		remaining -= estimateTokens(line)
+		lines = append(lines, line)
+	}
+	if omitted := len(turns) - len(lines); omitted > 0 {
+		lines = append(lines, "- ("+strconv.Itoa(omitted)+" earlier messages omitted)")
+	}
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+	return header + "\n" + strings.Join(lines, "\n")
+}
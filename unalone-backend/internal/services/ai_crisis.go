@@ -0,0 +1,66 @@
+// Crisis detection and helpline suggestions for AI chat replies
+package services
+
+import (
+	"strings"
+
+	"unalone-backend/internal/models"
+)
+
+// crisisPhrases mark a message that may come from someone at risk. Matching is deliberately
+// broad: showing helplines to someone who did not need them costs little.
+var crisisPhrases = []string{
+	"suicide", "suicidal", "kill myself", "killing myself", "end my life", "end it all",
+	"want to die", "wanna die", "better off dead", "no reason to live", "don't want to live",
+	"dont want to live", "self harm", "self-harm", "hurt myself", "cut myself", "overdose",
+	"aatmahatya", "marna chahta", "marna chahti", "jeena nahi chahta", "jeena nahi chahti",
+	"आत्महत्या", "मरना चाहता", "मरना चाहती", "जीना नहीं चाहता", "जीना नहीं चाहती",
+}
+
+// isCrisisMessage reports whether a user message suggests self-harm or suicidal thoughts
+func isCrisisMessage(content string) bool {
+	text := strings.ToLower(content)
+	for _, phrase := range crisisPhrases {
+		if strings.Contains(text, phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+// crisisResources looks up crisis helplines near the user, or in the default country
+// when they have no shared location
+func (s *InMemoryAIChatService) crisisResources(userID string) []models.WellbeingResource {
+	if s.resources == nil {
+		return nil
+	}
+	var at *models.HotspotLocation
+	if s.locations != nil {
+		if location, ok := s.locations.LastKnownLocation(userID); ok {
+			at = &models.HotspotLocation{Latitude: location.Latitude, Longitude: location.Longitude}
+		}
+	}
+	return s.resources.CrisisResources(at)
+}
+
+// appendCrisisResources adds a short list of helplines to an AI reply
+func appendCrisisResources(text string, resources []models.WellbeingResource) string {
+	var b strings.Builder
+	b.WriteString(strings.TrimRight(text, "\n"))
+	b.WriteString("\n\nIf you are in danger or thinking about ending your life, please reach out now:")
+	for _, r := range resources {
+		b.WriteString("\n- ")
+		b.WriteString(r.Name)
+		if r.Phone != "" {
+			b.WriteString(": ")
+			b.WriteString(r.Phone)
+		} else if r.URL != "" {
+			b.WriteString(": ")
+			b.WriteString(r.URL)
+		}
+		if r.Hours != "" {
+			b.WriteString(" (" + r.Hours + ")")
+		}
+	}
+	return b.String()
+}
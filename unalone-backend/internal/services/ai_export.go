@@ -0,0 +1,116 @@
+// Export and permanent deletion of AI sessions
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"unalone-backend/internal/models"
+)
+
+// ExportSession returns a session with its whole transcript for its owner or a participant
+func (s *InMemoryAIChatService) ExportSession(ctx context.Context, userID, sessionID string) (*models.AISessionExport, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	sess := s.sessionsByUser[userID][sessionID]
+	if sess == nil {
+		return nil, ErrAISessionNotFound
+	}
+	export := &models.AISessionExport{
+		Session:    *sess,
+		Messages:   append([]*models.AIMessage{}, s.messages[sessionID]...),
+		ExportedAt: clock.Now(),
+	}
+	export.Session.Participants = append([]models.AIParticipant(nil), sess.Participants...)
+	return export, nil
+}
+
+// DeleteSession permanently removes an owner's session: its messages, hotspot drafts and the
+// mood insights scored from it, for everyone it was shared with. Nothing is kept for recovery,
+// and only the session ID is logged.
+func (s *InMemoryAIChatService) DeleteSession(ctx context.Context, userID, sessionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess := s.sessionsByUser[userID][sessionID]
+	if sess == nil {
+		return ErrAISessionNotFound
+	}
+	if sess.UserID != userID {
+		return ErrAINotOwnerDelete
+	}
+
+	delete(s.sessionsByUser[userID], sessionID)
+	for _, p := range sess.Participants {
+		delete(s.sessionsByUser[p.UserID], sessionID)
+	}
+	delete(s.messages, sessionID)
+	for draftID, draft := range s.drafts {
+		if draft.SessionID == sessionID {
+			delete(s.drafts, draftID)
+		}
+	}
+	for authorID, entries := range s.insights {
+		for key, entry := range entries {
+			if entry.sessionID == sessionID {
+				delete(entries, key)
+			}
+		}
+		if len(entries) == 0 {
+			delete(s.insights, authorID)
+		}
+	}
+	log.Printf("[ai] session %s deleted by its owner", sessionID)
+	return nil
+}
+
+// RenderSessionMarkdown formats an export as a readable transcript. Messages from viewerID
+// are labelled "You".
+func RenderSessionMarkdown(export *models.AISessionExport, viewerID string) string {
+	var b strings.Builder
+	title := export.Session.Title
+	if strings.TrimSpace(title) == "" {
+		title = "AI conversation"
+	}
+	fmt.Fprintf(&b, "# %s\n\n", title)
+	fmt.Fprintf(&b, "Started %s · exported %s\n", export.Session.CreatedAt.UTC().Format(time.RFC1123), export.ExportedAt.UTC().Format(time.RFC1123))
+	if len(export.Session.Participants) > 0 {
+		names := make([]string, 0, len(export.Session.Participants))
+		for _, p := range export.Session.Participants {
+			names = append(names, p.Nickname)
+		}
+		fmt.Fprintf(&b, "Shared with %s\n", strings.Join(names, ", "))
+	}
+	b.WriteString("\n> The assistant is not a substitute for a professional. Keep this file somewhere private.\n")
+
+	for _, m := range export.Messages {
+		author := "Assistant"
+		if m.Role == "user" {
+			switch {
+			case m.AuthorID == viewerID || (m.AuthorID == "" && export.Session.UserID == viewerID):
+				author = "You"
+			case m.AuthorNickname != "":
+				author = m.AuthorNickname
+			default:
+				author = "User"
+			}
+		}
+		fmt.Fprintf(&b, "\n---\n\n**%s** · %s\n\n%s\n", author, m.CreatedAt.UTC().Format("2006-01-02 15:04 MST"), m.Content)
+		if len(m.Resources) > 0 {
+			b.WriteString("\nSupport available:\n")
+			for _, r := range m.Resources {
+				line := "- " + r.Name
+				if r.Phone != "" {
+					line += ": " + r.Phone
+				}
+				if r.URL != "" {
+					line += " (" + r.URL + ")"
+				}
+				b.WriteString(line + "\n")
+			}
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,353 @@
+// AI hotspot drafts: the assistant proposes a hotspot from a conversation and the client confirms it
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"unalone-backend/internal/models"
+)
+
+const (
+	// aiHotspotDraftTTL is how long a draft can be confirmed
+	aiHotspotDraftTTL = 24 * time.Hour
+	// aiProposeHotspotFunction is the tool the assistant calls to propose a hotspot
+	aiProposeHotspotFunction = "propose_hotspot"
+	// aiDraftDefaultCapacity is used when the conversation does not say how many can join
+	aiDraftDefaultCapacity = 10
+)
+
+// AI hotspot draft errors
+var (
+	ErrAIDraftNotFound    = errors.New("draft not found")
+	ErrAIDraftConfirmed   = errors.New("draft already confirmed")
+	ErrAIDraftUnavailable = errors.New("the assistant could not draft a hotspot from this conversation")
+)
+
+// hotspotProposal holds the arguments of the propose_hotspot tool call
+type hotspotProposal struct {
+	Name          string   `json:"name"`
+	Description   string   `json:"description"`
+	Category      string   `json:"category"`
+	ScheduledTime string   `json:"scheduled_time"`
+	EndTime       string   `json:"end_time"`
+	PlaceName     string   `json:"place_name"`
+	Street        string   `json:"street"`
+	City          string   `json:"city"`
+	Country       string   `json:"country"`
+	MaxCapacity   int      `json:"max_capacity"`
+	Tags          []string `json:"tags"`
+}
+
+// DraftHotspot asks the assistant to propose a hotspot from the session's conversation.
+// Owners and co-chat participants can draft; the draft is stored for confirmation.
+func (s *InMemoryAIChatService) DraftHotspot(ctx context.Context, userID, sessionID string, req *models.DraftHotspotRequest) (*models.AIHotspotDraft, error) {
+	if !s.consents.Allowed(userID, models.ConsentAIProcessing) {
+		return nil, ErrAIConsentRequired
+	}
+	s.mu.RLock()
+	sess := s.sessionsByUser[userID][sessionID]
+	if sess == nil {
+		s.mu.RUnlock()
+		return nil, ErrAISessionNotFound
+	}
+	if !aiCanChat(sess, userID) {
+		s.mu.RUnlock()
+		return nil, ErrAIReadOnly
+	}
+	history := append([]*models.AIMessage{}, s.messages[sessionID]...)
+	title := sess.Title
+	s.mu.RUnlock()
+
+	now := clock.Now()
+	var proposal *hotspotProposal
+	if s.geminiAPIKey != "" {
+		var err error
+		proposal, err = s.proposeWithGemini(ctx, history, now)
+		if errors.Is(err, ErrGeminiUnavailable) {
+			proposal = stubProposal(title, history, now)
+		} else if err != nil {
+			log.Printf("gemini hotspot draft: %v", err)
+			return nil, ErrAIDraftUnavailable
+		}
+	} else {
+		proposal = stubProposal(title, history, now)
+	}
+
+	draft := &models.AIHotspotDraft{
+		ID:        genID(8),
+		SessionID: sessionID,
+		CreatedBy: userID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(aiHotspotDraftTTL),
+	}
+	draft.Hotspot, draft.Missing = s.draftRequest(proposal, req, now)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.drafts[draft.ID] = draft
+	return draft, nil
+}
+
+// ConfirmHotspotDraft creates the hotspot a draft describes, or the client's edited version of it,
+// by calling create. The draft is held while create runs so it only ever makes one hotspot.
+func (s *InMemoryAIChatService) ConfirmHotspotDraft(ctx context.Context, userID, sessionID, draftID string, edited *models.CreateHotspotRequest, create func(*models.CreateHotspotRequest) (*models.Hotspot, error)) (*models.Hotspot, error) {
+	s.mu.Lock()
+	sess := s.sessionsByUser[userID][sessionID]
+	if sess == nil {
+		s.mu.Unlock()
+		return nil, ErrAISessionNotFound
+	}
+	if !aiCanChat(sess, userID) {
+		s.mu.Unlock()
+		return nil, ErrAIReadOnly
+	}
+	draft := s.drafts[draftID]
+	if draft == nil || draft.SessionID != sessionID || clock.Now().After(draft.ExpiresAt) {
+		s.mu.Unlock()
+		return nil, ErrAIDraftNotFound
+	}
+	if draft.HotspotID != "" || s.confirming[draftID] {
+		s.mu.Unlock()
+		return nil, ErrAIDraftConfirmed
+	}
+	s.confirming[draftID] = true
+	req := draft.Hotspot
+	s.mu.Unlock()
+
+	if edited != nil {
+		req = *edited
+	}
+	hotspot, err := create(&req)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.confirming, draftID)
+	if err != nil {
+		return nil, err
+	}
+	draft.HotspotID = hotspot.ID
+	// Tell everyone in the session what was created
+	now := clock.Now()
+	s.messages[sessionID] = append(s.messages[sessionID], &models.AIMessage{
+		ID:        genID(6),
+		SessionID: sessionID,
+		Role:      "ai",
+		Content:   fmt.Sprintf("Created the hotspot “%s”.", hotspot.Name),
+		CreatedAt: now,
+	})
+	sess.UpdatedAt = now
+	return hotspot, nil
+}
+
+// draftRequest turns a proposal into a create request, filling in what the client supplied and
+// listing the required fields that are still missing
+func (s *InMemoryAIChatService) draftRequest(p *hotspotProposal, req *models.DraftHotspotRequest, now time.Time) (models.CreateHotspotRequest, []string) {
+	var missing []string
+	draft := models.CreateHotspotRequest{
+		Name:        truncateRunes(strings.TrimSpace(p.Name), 100),
+		Description: truncateRunes(strings.TrimSpace(p.Description), 500),
+		Category:    models.CategoryOther,
+		MaxCapacity: aiDraftDefaultCapacity,
+		VenueName:   truncateRunes(strings.TrimSpace(p.PlaceName), 100),
+		Address: models.HotspotAddress{
+			Street:  strings.TrimSpace(p.Street),
+			City:    strings.TrimSpace(p.City),
+			Country: strings.TrimSpace(p.Country),
+		},
+	}
+	if utf8.RuneCountInString(draft.Name) < 3 {
+		missing = append(missing, "name")
+	}
+	if utf8.RuneCountInString(draft.Description) < 10 {
+		missing = append(missing, "description")
+	}
+	if category := models.HotspotCategory(strings.TrimSpace(p.Category)); category != "" && s.categories != nil && s.categories.ValidateCategory(category, "") == nil {
+		draft.Category = category
+	}
+	if p.MaxCapacity >= 1 && p.MaxCapacity <= 1000 {
+		draft.MaxCapacity = p.MaxCapacity
+	}
+	if start, err := time.Parse(time.RFC3339, strings.TrimSpace(p.ScheduledTime)); err == nil && start.After(now) {
+		draft.ScheduledTime = &start
+		if end, err := time.Parse(time.RFC3339, strings.TrimSpace(p.EndTime)); err == nil && end.After(start) {
+			draft.EndTime = &end
+		}
+	} else {
+		missing = append(missing, "scheduled_time")
+	}
+	for _, tag := range p.Tags {
+		if tag = strings.TrimSpace(tag); tag != "" && len(draft.Tags) < 10 {
+			draft.Tags = append(draft.Tags, tag)
+		}
+	}
+
+	if req.Latitude != nil && req.Longitude != nil {
+		draft.Location = models.HotspotLocation{Latitude: *req.Latitude, Longitude: *req.Longitude}
+	} else {
+		missing = append(missing, "location")
+	}
+	if draft.Address.City == "" {
+		draft.Address.City = strings.TrimSpace(req.City)
+	}
+	if draft.Address.Country == "" {
+		draft.Address.Country = strings.TrimSpace(req.Country)
+	}
+	if draft.Address.City == "" {
+		missing = append(missing, "address.city")
+	}
+	if draft.Address.Country == "" {
+		missing = append(missing, "address.country")
+	}
+	return draft, missing
+}
+
+// proposeWithGemini has Gemini call propose_hotspot with the plan the conversation settled on
+func (s *InMemoryAIChatService) proposeWithGemini(ctx context.Context, history []*models.AIMessage, now time.Time) (*hotspotProposal, error) {
+	type gemPart struct {
+		Text         string `json:"text,omitempty"`
+		FunctionCall *struct {
+			Name string          `json:"name"`
+			Args json.RawMessage `json:"args"`
+		} `json:"functionCall,omitempty"`
+	}
+	type gemContent struct {
+		Role  string    `json:"role,omitempty"`
+		Parts []gemPart `json:"parts"`
+	}
+	type gemResponse struct {
+		Candidates []struct {
+			Content gemContent `json:"content"`
+		} `json:"candidates"`
+	}
+
+	// The whole recent conversation, with authors, so the plan reflects everyone's input
+	turns := make([]aiTurn, 0, 21)
+	start := 0
+	if len(history) > 20 {
+		start = len(history) - 20
+	}
+	allowed := s.consentedAuthors()
+	for _, m := range history[start:] {
+		if !allowed(m) {
+			continue
+		}
+		role, text := "user", m.Content
+		if m.Role == "ai" {
+			role = "model"
+		} else if m.AuthorNickname != "" {
+			text = m.AuthorNickname + ": " + text
+		}
+		turns = append(turns, aiTurn{Role: role, Text: text})
+	}
+	turns = append(turns, aiTurn{Role: "user", Text: "Draft a hotspot for the plan we discussed."})
+
+	var categories []string
+	if s.categories != nil {
+		if list, err := s.categories.ListCategories(); err == nil {
+			for _, c := range list {
+				categories = append(categories, c.ID)
+			}
+		}
+	}
+	categorySchema := map[string]interface{}{"type": "STRING", "description": "Kind of place or activity"}
+	if len(categories) > 0 {
+		categorySchema["enum"] = categories
+	}
+	str := func(description string) map[string]interface{} {
+		return map[string]interface{}{"type": "STRING", "description": description}
+	}
+	tool := map[string]interface{}{
+		"functionDeclarations": []map[string]interface{}{{
+			"name":        aiProposeHotspotFunction,
+			"description": "Propose a hotspot (a meetup others can join) for the plan agreed in the conversation",
+			"parameters": map[string]interface{}{
+				"type": "OBJECT",
+				"properties": map[string]interface{}{
+					"name":           str("Short title, 3-100 characters"),
+					"description":    str("What the meetup is about, 10-500 characters"),
+					"category":       categorySchema,
+					"scheduled_time": str("Start time in RFC 3339 with a UTC offset"),
+					"end_time":       str("End time in RFC 3339 with a UTC offset, if known"),
+					"place_name":     str("Name of the venue or place"),
+					"street":         str("Street address, if known"),
+					"city":           str("City"),
+					"country":        str("Country"),
+					"max_capacity":   map[string]interface{}{"type": "INTEGER", "description": "How many people can join, 1-1000"},
+					"tags":           map[string]interface{}{"type": "ARRAY", "items": map[string]interface{}{"type": "STRING"}, "description": "Up to 10 short tags"},
+				},
+				"required": []string{"name", "description"},
+			},
+		}},
+	}
+	sys := fmt.Sprintf("You turn a planning conversation into a hotspot draft by calling %s. It is now %s (%s). "+
+		"Resolve relative dates such as \"Saturday evening\" from that. Only fill in details the conversation settles; leave the rest out.",
+		aiProposeHotspotFunction, now.UTC().Format(time.RFC3339), now.UTC().Weekday())
+	sys, turns = s.fitContext(sys, turns)
+	contents := make([]gemContent, 0, len(turns))
+	for _, turn := range turns {
+		contents = append(contents, gemContent{Role: turn.Role, Parts: []gemPart{{Text: turn.Text}}})
+	}
+
+	body, err := s.postGemini(ctx, map[string]interface{}{
+		"contents":          contents,
+		"systemInstruction": map[string]interface{}{"parts": []gemPart{{Text: sys}}},
+		"tools":             []interface{}{tool},
+		"toolConfig": map[string]interface{}{
+			"functionCallingConfig": map[string]interface{}{"mode": "ANY", "allowedFunctionNames": []string{aiProposeHotspotFunction}},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var gr gemResponse
+	if err := json.Unmarshal(body, &gr); err != nil {
+		return nil, fmt.Errorf("parse error: %w", err)
+	}
+	for _, candidate := range gr.Candidates {
+		for _, part := range candidate.Content.Parts {
+			if part.FunctionCall == nil || part.FunctionCall.Name != aiProposeHotspotFunction {
+				continue
+			}
+			var proposal hotspotProposal
+			if err := json.Unmarshal(part.FunctionCall.Args, &proposal); err != nil {
+				return nil, fmt.Errorf("parse %s args: %w", aiProposeHotspotFunction, err)
+			}
+			return &proposal, nil
+		}
+	}
+	return nil, errors.New("no " + aiProposeHotspotFunction + " call in response")
+}
+
+// stubProposal drafts from the session title and the latest user message when Gemini is not configured
+func stubProposal(title string, history []*models.AIMessage, now time.Time) *hotspotProposal {
+	proposal := &hotspotProposal{Name: title}
+	if title == "" || title == "New Chat" {
+		proposal.Name = "Meetup"
+	}
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].Role == "user" {
+			proposal.Description = history[i].Content
+			break
+		}
+	}
+	// Tomorrow at 18:00 UTC
+	tomorrow := now.UTC().AddDate(0, 0, 1)
+	proposal.ScheduledTime = time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 18, 0, 0, 0, time.UTC).Format(time.RFC3339)
+	return proposal
+}
+
+// aiCanChat reports whether a user may send messages in a session: its owner or a co-chat participant
+func aiCanChat(sess *models.AIChatSession, userID string) bool {
+	if sess.UserID == userID {
+		return true
+	}
+	p := findAIParticipant(sess, userID)
+	return p != nil && p.Role == models.AIParticipantCoChat
+}
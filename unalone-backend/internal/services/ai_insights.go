@@ -0,0 +1,311 @@
+// Private mood insights from AI sessions: per-message scores aggregated by session and day
+package services
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"unalone-backend/internal/models"
+)
+
+// aiMoodEntry aggregates one user's messages in one session on one day. Only the counts
+// are kept; the scored text is never stored here.
+type aiMoodEntry struct {
+	sessionID    string
+	day          string
+	messages     int
+	sentimentSum float64
+	moods        map[string]int
+}
+
+// aiMoodWords maps single words to a mood; phrases live in aiMoodPhrases
+var aiMoodWords = map[string]string{
+	"anxious": models.AIMoodAnxious, "anxiety": models.AIMoodAnxious, "nervous": models.AIMoodAnxious,
+	"worried": models.AIMoodAnxious, "worry": models.AIMoodAnxious, "panic": models.AIMoodAnxious,
+	"scared": models.AIMoodAnxious, "afraid": models.AIMoodAnxious, "ghabrahat": models.AIMoodAnxious,
+	"घबराहट": models.AIMoodAnxious, "चिंता": models.AIMoodAnxious,
+
+	"stress": models.AIMoodStressed, "stressed": models.AIMoodStressed, "pressure": models.AIMoodStressed,
+	"overwhelmed": models.AIMoodStressed, "burnout": models.AIMoodStressed, "tension": models.AIMoodStressed,
+	"तनाव": models.AIMoodStressed,
+
+	"sad": models.AIMoodSad, "unhappy": models.AIMoodSad, "depressed": models.AIMoodSad,
+	"crying": models.AIMoodSad, "hopeless": models.AIMoodSad, "heartbroken": models.AIMoodSad,
+	"udaas": models.AIMoodSad, "उदास": models.AIMoodSad, "दुखी": models.AIMoodSad,
+
+	"lonely": models.AIMoodLonely, "alone": models.AIMoodLonely, "isolated": models.AIMoodLonely,
+	"akela": models.AIMoodLonely, "akeli": models.AIMoodLonely, "अकेला": models.AIMoodLonely, "अकेली": models.AIMoodLonely,
+
+	"angry": models.AIMoodAngry, "furious": models.AIMoodAngry, "annoyed": models.AIMoodAngry,
+	"frustrated": models.AIMoodAngry, "irritated": models.AIMoodAngry, "gussa": models.AIMoodAngry,
+	"गुस्सा": models.AIMoodAngry,
+
+	"tired": models.AIMoodTired, "exhausted": models.AIMoodTired, "drained": models.AIMoodTired,
+	"insomnia": models.AIMoodTired, "थका": models.AIMoodTired, "थकी": models.AIMoodTired,
+
+	"hopeful": models.AIMoodHopeful, "optimistic": models.AIMoodHopeful, "excited": models.AIMoodHopeful,
+	"motivated": models.AIMoodHopeful, "ummeed": models.AIMoodHopeful, "उम्मीद": models.AIMoodHopeful,
+
+	"calm": models.AIMoodCalm, "relaxed": models.AIMoodCalm, "peaceful": models.AIMoodCalm,
+	"rested": models.AIMoodCalm, "sukoon": models.AIMoodCalm, "सुकून": models.AIMoodCalm,
+
+	"happy": models.AIMoodHappy, "glad": models.AIMoodHappy, "great": models.AIMoodHappy,
+	"good": models.AIMoodHappy, "joy": models.AIMoodHappy, "khush": models.AIMoodHappy, "खुश": models.AIMoodHappy,
+
+	"grateful": models.AIMoodGrateful, "thankful": models.AIMoodGrateful, "thanks": models.AIMoodGrateful,
+	"shukriya": models.AIMoodGrateful, "dhanyavad": models.AIMoodGrateful,
+	"धन्यवाद": models.AIMoodGrateful, "शुक्रिया": models.AIMoodGrateful,
+}
+
+var aiMoodPhrases = map[string]string{
+	"left out":          models.AIMoodLonely,
+	"no friends":        models.AIMoodLonely,
+	"can't sleep":       models.AIMoodTired,
+	"cant sleep":        models.AIMoodTired,
+	"looking forward":   models.AIMoodHopeful,
+	"feel better":       models.AIMoodHopeful,
+	"feeling better":    models.AIMoodHopeful,
+	"thank you":         models.AIMoodGrateful,
+	"fed up":            models.AIMoodAngry,
+	"falling behind":    models.AIMoodStressed,
+	"too much work":     models.AIMoodStressed,
+	"no one cares":      models.AIMoodLonely,
+	"nobody cares":      models.AIMoodLonely,
+	"feel empty":        models.AIMoodSad,
+	"feeling empty":     models.AIMoodSad,
+	"mann nahi lagta":   models.AIMoodSad,
+	"man nahi lag raha": models.AIMoodSad,
+}
+
+// aiPositiveMoods are the moods that count towards positive sentiment
+var aiPositiveMoods = map[string]bool{
+	models.AIMoodHopeful: true, models.AIMoodCalm: true, models.AIMoodHappy: true, models.AIMoodGrateful: true,
+}
+
+// aiNegators flip off the mood of the word that follows, as in "not happy"
+var aiNegators = map[string]bool{
+	"not": true, "never": true, "no": true, "don't": true, "dont": true, "isn't": true,
+	"nahi": true, "nahin": true, "नहीं": true,
+}
+
+// scoreMood tags a message with moods from a small English, Hinglish and Hindi lexicon.
+// Sentiment is the balance of positive and negative tags, 0 when none matched.
+func scoreMood(content string) models.AIMoodScore {
+	text := strings.ToLower(content)
+	tokens := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsMark(r) && r != '\''
+	})
+
+	hits := make(map[string]int)
+	for i, token := range tokens {
+		mood, ok := aiMoodWords[token]
+		if !ok || (i > 0 && aiNegators[tokens[i-1]]) {
+			continue
+		}
+		hits[mood]++
+	}
+	joined := " " + strings.Join(tokens, " ") + " "
+	for phrase, mood := range aiMoodPhrases {
+		if strings.Contains(joined, " "+phrase+" ") {
+			hits[mood]++
+		}
+	}
+
+	score := models.AIMoodScore{Moods: make([]string, 0, len(hits))}
+	positive, negative := 0, 0
+	for mood, n := range hits {
+		score.Moods = append(score.Moods, mood)
+		if aiPositiveMoods[mood] {
+			positive += n
+		} else {
+			negative += n
+		}
+	}
+	sort.Strings(score.Moods)
+	if positive+negative > 0 {
+		score.Sentiment = float64(positive-negative) / float64(positive+negative)
+	}
+	return score
+}
+
+// OnMoodScored registers a callback for each message scored with its author's consent,
+// used to feed anonymous platform aggregates
+func (s *InMemoryAIChatService) OnMoodScored(fn func(userID string, score models.AIMoodScore)) {
+	s.onMoodScored = fn
+}
+
+// SetInsightsConsent turns a user's mood insights on or off. Turning them off deletes
+// everything collected so far.
+func (s *InMemoryAIChatService) SetInsightsConsent(ctx context.Context, userID string, enabled bool) (*models.AIInsights, error) {
+	s.mu.Lock()
+	if enabled {
+		if _, ok := s.insightConsent[userID]; !ok {
+			s.insightConsent[userID] = clock.Now()
+		}
+	} else {
+		delete(s.insightConsent, userID)
+		delete(s.insights, userID)
+	}
+	s.mu.Unlock()
+	return s.GetInsights(ctx, userID, models.MaxAIInsightDays)
+}
+
+// GetInsights returns the user's mood trend over the last days and per-session aggregates
+func (s *InMemoryAIChatService) GetInsights(ctx context.Context, userID string, days int) (*models.AIInsights, error) {
+	if days < 1 || days > models.MaxAIInsightDays {
+		days = models.MaxAIInsightDays
+	}
+	since := metricsDay(clock.Now().AddDate(0, 0, -(days - 1)))
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := &models.AIInsights{Days: []models.AIInsightDay{}, Sessions: []models.AISessionInsight{}}
+	consentedAt, ok := s.insightConsent[userID]
+	if !ok {
+		return result, nil
+	}
+	result.Enabled = true
+	result.ConsentedAt = &consentedAt
+
+	byDay := make(map[string]*models.AIInsightDay)
+	bySession := make(map[string]*models.AISessionInsight)
+	daySums := make(map[string]float64)
+	sessionSums := make(map[string]float64)
+	for _, entry := range s.insights[userID] {
+		if entry.day < since {
+			continue
+		}
+		day, ok := byDay[entry.day]
+		if !ok {
+			day = &models.AIInsightDay{Day: entry.day, Moods: make(map[string]int)}
+			byDay[entry.day] = day
+		}
+		day.Sessions++
+		day.Messages += entry.messages
+		daySums[entry.day] += entry.sentimentSum
+
+		session, ok := bySession[entry.sessionID]
+		if !ok {
+			session = &models.AISessionInsight{SessionID: entry.sessionID, Moods: make(map[string]int), FirstDay: entry.day, LastDay: entry.day}
+			bySession[entry.sessionID] = session
+		}
+		session.Messages += entry.messages
+		sessionSums[entry.sessionID] += entry.sentimentSum
+		if entry.day < session.FirstDay {
+			session.FirstDay = entry.day
+		}
+		if entry.day > session.LastDay {
+			session.LastDay = entry.day
+		}
+
+		for mood, n := range entry.moods {
+			day.Moods[mood] += n
+			session.Moods[mood] += n
+		}
+	}
+
+	for key, day := range byDay {
+		day.Sentiment = daySums[key] / float64(day.Messages)
+		result.Days = append(result.Days, *day)
+	}
+	for key, session := range bySession {
+		session.Sentiment = sessionSums[key] / float64(session.Messages)
+		result.Sessions = append(result.Sessions, *session)
+	}
+	sort.Slice(result.Days, func(i, j int) bool { return result.Days[i].Day < result.Days[j].Day })
+	sort.Slice(result.Sessions, func(i, j int) bool {
+		if result.Sessions[i].LastDay != result.Sessions[j].LastDay {
+			return result.Sessions[i].LastDay > result.Sessions[j].LastDay
+		}
+		return result.Sessions[i].SessionID < result.Sessions[j].SessionID
+	})
+	return result, nil
+}
+
+// recordMood scores a user's message into their insights if they consented
+func (s *InMemoryAIChatService) recordMood(userID, sessionID, content string) {
+	s.mu.RLock()
+	_, consented := s.insightConsent[userID]
+	s.mu.RUnlock()
+	if !consented {
+		return
+	}
+
+	score := scoreMood(content)
+	day := metricsDay(clock.Now())
+	key := sessionID + "/" + day
+
+	s.mu.Lock()
+	// Consent may have been revoked while scoring
+	if _, ok := s.insightConsent[userID]; !ok {
+		s.mu.Unlock()
+		return
+	}
+	entries, ok := s.insights[userID]
+	if !ok {
+		entries = make(map[string]*aiMoodEntry)
+		s.insights[userID] = entries
+	}
+	entry, ok := entries[key]
+	if !ok {
+		entry = &aiMoodEntry{sessionID: sessionID, day: day, moods: make(map[string]int)}
+		entries[key] = entry
+	}
+	entry.messages++
+	entry.sentimentSum += score.Sentiment
+	for _, mood := range score.Moods {
+		entry.moods[mood]++
+	}
+	fn := s.onMoodScored
+	s.mu.Unlock()
+
+	if fn != nil {
+		fn(userID, score)
+	}
+}
+
+// recentMood returns the user's most frequent mood over the last week, or "" without insights
+func (s *InMemoryAIChatService) recentMood(userID string) string {
+	since := metricsDay(clock.Now().AddDate(0, 0, -6))
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if _, ok := s.insightConsent[userID]; !ok {
+		return ""
+	}
+	counts := make(map[string]int)
+	for _, entry := range s.insights[userID] {
+		if entry.day < since {
+			continue
+		}
+		for mood, n := range entry.moods {
+			counts[mood] += n
+		}
+	}
+	best := ""
+	for _, mood := range models.AIMoodTags {
+		if counts[mood] > counts[best] {
+			best = mood
+		}
+	}
+	return best
+}
+
+// purgeInsightsLocked drops insights older than MaxAIInsightDays; callers hold s.mu
+func (s *InMemoryAIChatService) purgeInsightsLocked(now time.Time) {
+	oldest := metricsDay(now.AddDate(0, 0, -models.MaxAIInsightDays))
+	for userID, entries := range s.insights {
+		for key, entry := range entries {
+			if entry.day < oldest {
+				delete(entries, key)
+			}
+		}
+		if len(entries) == 0 {
+			delete(s.insights, userID)
+		}
+	}
+}
@@ -0,0 +1,508 @@
+// Analytics service for hotspot views, search impressions and host dashboards
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"unalone-backend/internal/jobs"
+	"unalone-backend/internal/models"
+)
+
+const analyticsDayFormat = "2006-01-02"
+
+// Analytics write kinds
+const (
+	analyticsView        = "view"
+	analyticsImpressions = "impressions"
+	analyticsJoin        = "join"
+	analyticsCheckIn     = "check_in"
+	analyticsMessage     = "message"
+)
+
+// AnalyticsRecordJob applies an analytics write that was queued while Firestore was down
+var AnalyticsRecordJob = jobs.Type[AnalyticsRecord]{Name: "analytics.record", MaxAttempts: 10}
+
+// AnalyticsRecord is one analytics write
+type AnalyticsRecord struct {
+	Kind       string    `json:"kind"`
+	HotspotID  string    `json:"hotspot_id,omitempty"`
+	HotspotIDs []string  `json:"hotspot_ids,omitempty"`
+	UserID     string    `json:"user_id"`
+	At         time.Time `json:"at"` // Views and impressions are deduplicated per day of this time
+}
+
+// errFirestoreDown defers a queued write until Firestore is back
+var errFirestoreDown = errors.New("firestore is down")
+
+// AnalyticsService records hotspot views and impressions for host statistics
+type AnalyticsService struct {
+	firestoreService *FirestoreService
+	hotspotService   *HotspotService
+	feedbackService  *FeedbackService
+	health           *HealthRegistry
+	queue            *jobs.Queue
+}
+
+// NewAnalyticsService creates a new analytics service. While Firestore is down its writes are
+// queued and applied once it is back.
+func NewAnalyticsService(fs *FirestoreService, hs *HotspotService, fbs *FeedbackService, health *HealthRegistry, queue *jobs.Queue) *AnalyticsService {
+	as := &AnalyticsService{
+		firestoreService: fs,
+		hotspotService:   hs,
+		feedbackService:  fbs,
+		health:           health,
+		queue:            queue,
+	}
+	AnalyticsRecordJob.Handle(queue, func(ctx context.Context, rec AnalyticsRecord) error {
+		if !health.Up(DependencyFirestore) {
+			return errFirestoreDown
+		}
+		return as.write(rec)
+	})
+	return as
+}
+
+// RecordView records a detail view; repeat views by the same user on the same day count once
+func (as *AnalyticsService) RecordView(hotspotID, userID string) {
+	if userID == "" {
+		return
+	}
+	as.record(AnalyticsRecord{Kind: analyticsView, HotspotID: hotspotID, UserID: userID, At: clock.Now()})
+}
+
+// RecordImpressions records search impressions for a set of hotspots, deduplicated per user per day
+func (as *AnalyticsService) RecordImpressions(userID string, hotspotIDs []string) {
+	if userID == "" || len(hotspotIDs) == 0 {
+		return
+	}
+	as.record(AnalyticsRecord{Kind: analyticsImpressions, HotspotIDs: hotspotIDs, UserID: userID, At: clock.Now()})
+}
+
+// GetHotspotStats returns aggregate statistics for a hotspot; only the host may view them
+func (as *AnalyticsService) GetHotspotStats(userID, hotspotID string) (*models.HotspotStats, error) {
+	hotspot, err := as.hotspotService.GetHotspot(hotspotID)
+	if err != nil {
+		return nil, err
+	}
+	if hotspot.CreatedBy != userID {
+		return nil, errors.New("only the host can view hotspot stats")
+	}
+
+	if as.isTestMode() {
+		return as.getHotspotStatsMock(hotspotID), nil
+	}
+
+	// TODO: Implement Firestore aggregation
+	return nil, errors.New("firestore implementation needed")
+}
+
+// RecordJoin records a user joining a hotspot for the RSVP funnel
+func (as *AnalyticsService) RecordJoin(hotspotID, userID string) {
+	if userID == "" {
+		return
+	}
+	as.record(AnalyticsRecord{Kind: analyticsJoin, HotspotID: hotspotID, UserID: userID, At: clock.Now()})
+}
+
+// RecordCheckIn records an attendee checking in at a hotspot
+func (as *AnalyticsService) RecordCheckIn(hotspotID, userID string) {
+	if userID == "" {
+		return
+	}
+	as.record(AnalyticsRecord{Kind: analyticsCheckIn, HotspotID: hotspotID, UserID: userID, At: clock.Now()})
+}
+
+// RecordMessage records a chat message for engagement analytics
+func (as *AnalyticsService) RecordMessage(hotspotID, userID string) {
+	if userID == "" {
+		return
+	}
+	as.record(AnalyticsRecord{Kind: analyticsMessage, HotspotID: hotspotID, UserID: userID, At: clock.Now()})
+}
+
+// record applies an analytics write now, or queues it while Firestore is down
+func (as *AnalyticsService) record(rec AnalyticsRecord) {
+	if !as.health.Up(DependencyFirestore) {
+		_, err := AnalyticsRecordJob.Enqueue(context.Background(), as.queue, rec)
+		if err == nil {
+			return
+		}
+		log.Printf("Queueing %s analytics failed, writing now: %v", rec.Kind, err)
+	}
+	if err := as.write(rec); err != nil {
+		log.Printf("Recording %s analytics failed: %v", rec.Kind, err)
+	}
+}
+
+// write stores one analytics record
+func (as *AnalyticsService) write(rec AnalyticsRecord) error {
+	if !as.isTestMode() {
+		// TODO: Implement Firestore writes to hotspot_stats/{hotspotID}: views/{userID_day},
+		// impressions/{userID_day}, joins/{userID}, check_ins/{userID} and the message counter
+		return nil
+	}
+
+	switch rec.Kind {
+	case analyticsView:
+		as.recordViewMock(rec.HotspotID, rec.UserID, rec.At)
+	case analyticsImpressions:
+		as.recordImpressionsMock(rec.UserID, rec.HotspotIDs, rec.At)
+	default:
+		mockAnalyticsMu.Lock()
+		counters := mockCountersFor(rec.HotspotID)
+		switch rec.Kind {
+		case analyticsJoin:
+			counters.joiners[rec.UserID] = true
+		case analyticsCheckIn:
+			counters.checkIns[rec.UserID] = true
+		case analyticsMessage:
+			counters.messages++
+			counters.chatters[rec.UserID] = true
+		}
+		mockAnalyticsMu.Unlock()
+	}
+	return nil
+}
+
+// occupancyKinds maps the domain events that change who is at a hotspot to timeline kinds
+var occupancyKinds = map[string]string{
+	models.DomainEventUserJoined:    models.OccupancyJoined,
+	models.DomainEventUserLeft:      models.OccupancyLeft,
+	models.DomainEventUserCheckedIn: models.OccupancyCheckedIn,
+}
+
+// RecordOccupancy adds a join, leave or check-in event to the hotspot's occupancy timeline.
+// It is an event bus handler; redelivered events are recorded once.
+func (as *AnalyticsService) RecordOccupancy(ctx context.Context, event *models.DomainEvent) error {
+	kind, ok := occupancyKinds[event.Type]
+	if !ok || event.Hotspot == nil {
+		return nil
+	}
+	point := models.OccupancyPoint{
+		At:        event.OccurredAt,
+		Kind:      kind,
+		RSVPs:     len(event.Hotspot.Attendees),
+		CheckedIn: len(event.Hotspot.CheckedIn),
+	}
+
+	if as.isTestMode() {
+		mockAnalyticsMu.Lock()
+		defer mockAnalyticsMu.Unlock()
+		counters := mockCountersFor(event.HotspotID)
+		if counters.occupancyEvents[event.ID] {
+			return nil
+		}
+		counters.occupancyEvents[event.ID] = true
+		counters.occupancy = append(counters.occupancy, point)
+		return nil
+	}
+	// TODO: Implement Firestore write to hotspot_stats/{hotspotID}/occupancy/{eventID}
+	return nil
+}
+
+// GetOccupancyTimeline returns how a hotspot's RSVPs and check-ins changed over time; only
+// the host may view it. A positive interval also sums the changes into buckets of that size.
+func (as *AnalyticsService) GetOccupancyTimeline(userID, hotspotID string, interval time.Duration) (*models.OccupancyTimeline, error) {
+	hotspot, err := as.hotspotService.GetHotspot(hotspotID)
+	if err != nil {
+		return nil, err
+	}
+	if hotspot.CreatedBy != userID {
+		return nil, errors.New("only the host can view the occupancy timeline")
+	}
+
+	var points []models.OccupancyPoint
+	if as.isTestMode() {
+		mockAnalyticsMu.Lock()
+		points = append(points, mockCountersFor(hotspotID).occupancy...)
+		mockAnalyticsMu.Unlock()
+	} else {
+		// TODO: Query Firestore hotspot_stats/{hotspotID}/occupancy ordered by occurred_at
+		return nil, errors.New("firestore implementation needed")
+	}
+	// The bus may deliver events out of order
+	sort.SliceStable(points, func(i, j int) bool { return points[i].At.Before(points[j].At) })
+
+	timeline := &models.OccupancyTimeline{
+		HotspotID:   hotspotID,
+		MaxCapacity: hotspot.MaxCapacity,
+		RSVPs:       len(hotspot.Attendees),
+		CheckedIn:   len(hotspot.CheckedIn),
+		Points:      points,
+	}
+	if timeline.Points == nil {
+		timeline.Points = []models.OccupancyPoint{}
+	}
+	timeline.ShowUpRate = ratio(timeline.CheckedIn, timeline.RSVPs)
+	for i := range points {
+		point := &points[i]
+		if point.RSVPs > timeline.PeakRSVPs {
+			timeline.PeakRSVPs = point.RSVPs
+			timeline.PeakAt = &point.At
+		}
+		if point.Kind == models.OccupancyCheckedIn && timeline.FirstCheckInAt == nil {
+			timeline.FirstCheckInAt = &point.At
+		}
+	}
+	if interval > 0 {
+		timeline.Buckets = occupancyBuckets(points, interval)
+	}
+	return timeline, nil
+}
+
+// occupancyBuckets groups sorted timeline points into fixed intervals, leaving out empty ones
+func occupancyBuckets(points []models.OccupancyPoint, interval time.Duration) []models.OccupancyBucket {
+	buckets := []models.OccupancyBucket{}
+	for _, point := range points {
+		start := point.At.UTC().Truncate(interval)
+		if len(buckets) == 0 || !buckets[len(buckets)-1].Start.Equal(start) {
+			buckets = append(buckets, models.OccupancyBucket{Start: start})
+		}
+		bucket := &buckets[len(buckets)-1]
+		switch point.Kind {
+		case models.OccupancyJoined:
+			bucket.Joins++
+		case models.OccupancyLeft:
+			bucket.Leaves++
+		case models.OccupancyCheckedIn:
+			bucket.CheckIns++
+		}
+		bucket.RSVPs, bucket.CheckedIn = point.RSVPs, point.CheckedIn
+	}
+	return buckets
+}
+
+// GetHotspotAnalytics returns the host dashboard document for a hotspot. The
+// document is built by the nightly job; hotspots it has not reached yet are
+// aggregated on demand.
+func (as *AnalyticsService) GetHotspotAnalytics(userID, hotspotID string) (*models.HotspotAnalytics, error) {
+	hotspot, err := as.hotspotService.GetHotspot(hotspotID)
+	if err != nil {
+		return nil, err
+	}
+	if hotspot.CreatedBy != userID {
+		return nil, errors.New("only the host can view hotspot analytics")
+	}
+
+	if as.isTestMode() {
+		mockAnalyticsMu.Lock()
+		doc, ok := mockAnalyticsDocs[hotspotID]
+		mockAnalyticsMu.Unlock()
+		if ok {
+			return doc, nil
+		}
+		return as.aggregateHotspot(hotspot)
+	}
+
+	// TODO: Read Firestore document hotspot_analytics/{hotspotID}
+	return nil, errors.New("firestore implementation needed")
+}
+
+// AggregateAll rebuilds the dashboard document for every hotspot; the daily
+// analytics_aggregation job runs it
+func (as *AnalyticsService) AggregateAll() error {
+	if !as.isTestMode() {
+		// TODO: Page through Firestore hotspots and write hotspot_analytics documents
+		return errors.New("firestore implementation needed")
+	}
+
+	hotspots := mockHotspotList()
+	for _, hotspot := range hotspots {
+		if _, err := as.aggregateHotspot(hotspot); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// aggregateHotspot builds and stores the dashboard document for one hotspot
+func (as *AnalyticsService) aggregateHotspot(hotspot *models.Hotspot) (*models.HotspotAnalytics, error) {
+	hosted, err := as.hotspotService.GetUserHotspots(hotspot.CreatedBy)
+	if err != nil {
+		return nil, err
+	}
+	var feedback models.FeedbackSummary
+	if as.feedbackService != nil {
+		feedback = as.feedbackService.Summary(hotspot.ID)
+	}
+
+	mockAnalyticsMu.Lock()
+	defer mockAnalyticsMu.Unlock()
+
+	counters := mockCountersFor(hotspot.ID)
+	attendees := attendeeSet(hotspot, counters)
+
+	doc := &models.HotspotAnalytics{
+		HotspotID:    hotspot.ID,
+		PopularTimes: make(map[string]int, len(counters.popularTimes)),
+		GeneratedAt:  clock.Now(),
+	}
+	for hour, n := range counters.popularTimes {
+		doc.PopularTimes[hour] = n
+	}
+
+	// RSVP funnel
+	doc.Funnel.Views = len(counters.viewers)
+	doc.Funnel.Joins = len(attendees)
+	doc.Funnel.CheckIns = len(counters.checkIns)
+	doc.Funnel.ViewToJoinRate = ratio(doc.Funnel.Joins, doc.Funnel.Views)
+	doc.Funnel.JoinToCheckInRate = ratio(doc.Funnel.CheckIns, doc.Funnel.Joins)
+
+	// Retention across all of the host's published events
+	eventsAttended := make(map[string]int)
+	for _, h := range hosted {
+		if h.IsDraft {
+			continue
+		}
+		doc.Retention.HostedEvents++
+		for userID := range attendeeSet(h, mockCountersFor(h.ID)) {
+			eventsAttended[userID]++
+		}
+	}
+	doc.Retention.TotalAttendees = len(eventsAttended)
+	for _, n := range eventsAttended {
+		if n > 1 {
+			doc.Retention.ReturningAttendees++
+		}
+	}
+	doc.Retention.ReturnRate = ratio(doc.Retention.ReturningAttendees, doc.Retention.TotalAttendees)
+
+	// Chat engagement
+	doc.Chat.Messages = counters.messages
+	doc.Chat.ActiveChatters = len(counters.chatters)
+	doc.Chat.MessagesPerAttendee = ratio(counters.messages, len(hotspot.Attendees))
+	doc.Chat.ParticipationRate = ratio(len(counters.chatters), len(hotspot.Attendees))
+
+	// Post-event feedback
+	doc.Feedback = feedback
+
+	mockAnalyticsDocs[hotspot.ID] = doc
+	return doc, nil
+}
+
+// attendeeSet returns everyone who joined a hotspot at any point, excluding the host
+func attendeeSet(hotspot *models.Hotspot, counters *mockHotspotCounters) map[string]bool {
+	set := make(map[string]bool, len(counters.joiners)+len(hotspot.Attendees))
+	for userID := range counters.joiners {
+		set[userID] = true
+	}
+	for _, userID := range hotspot.Attendees {
+		set[userID] = true
+	}
+	delete(set, hotspot.CreatedBy)
+	return set
+}
+
+// ratio returns num/den rounded to two decimals, or 0 when den is 0
+func ratio(num, den int) float64 {
+	if den == 0 {
+		return 0
+	}
+	return math.Round(float64(num)/float64(den)*100) / 100
+}
+
+func (as *AnalyticsService) isTestMode() bool {
+	return as.firestoreService.client == nil
+}
+
+// === Mock storage in-memory for development/test ===
+
+type mockHotspotCounters struct {
+	views        map[string]bool // userID|day -> seen
+	impressions  map[string]bool // userID|day -> seen
+	viewers      map[string]bool // userID -> seen
+	popularTimes map[string]int  // hour -> views
+	dailyViews   map[string]int  // day -> views
+	joiners      map[string]bool // userID -> joined at some point
+	checkIns     map[string]bool // userID -> checked in
+	chatters     map[string]bool // userID -> sent a message
+	messages     int
+
+	occupancy       []models.OccupancyPoint
+	occupancyEvents map[string]bool // domain event ID -> recorded
+}
+
+var (
+	mockAnalyticsMu   sync.Mutex
+	mockAnalytics     = make(map[string]*mockHotspotCounters)     // hotspotID -> counters
+	mockAnalyticsDocs = make(map[string]*models.HotspotAnalytics) // hotspotID -> nightly dashboard document
+)
+
+func mockCountersFor(hotspotID string) *mockHotspotCounters {
+	counters, ok := mockAnalytics[hotspotID]
+	if !ok {
+		counters = &mockHotspotCounters{
+			views:        make(map[string]bool),
+			impressions:  make(map[string]bool),
+			viewers:      make(map[string]bool),
+			popularTimes: make(map[string]int),
+			dailyViews:   make(map[string]int),
+			joiners:      make(map[string]bool),
+			checkIns:     make(map[string]bool),
+			chatters:     make(map[string]bool),
+
+			occupancyEvents: make(map[string]bool),
+		}
+		mockAnalytics[hotspotID] = counters
+	}
+	return counters
+}
+
+func (as *AnalyticsService) recordViewMock(hotspotID, userID string, at time.Time) {
+	mockAnalyticsMu.Lock()
+	defer mockAnalyticsMu.Unlock()
+
+	counters := mockCountersFor(hotspotID)
+	day := at.Format(analyticsDayFormat)
+	key := userID + "|" + day
+	if counters.views[key] {
+		return
+	}
+	counters.views[key] = true
+	counters.viewers[userID] = true
+	counters.popularTimes[strconv.Itoa(at.Hour())]++
+	counters.dailyViews[day]++
+}
+
+func (as *AnalyticsService) recordImpressionsMock(userID string, hotspotIDs []string, at time.Time) {
+	mockAnalyticsMu.Lock()
+	defer mockAnalyticsMu.Unlock()
+
+	key := userID + "|" + at.Format(analyticsDayFormat)
+	for _, id := range hotspotIDs {
+		mockCountersFor(id).impressions[key] = true
+	}
+}
+
+func (as *AnalyticsService) getHotspotStatsMock(hotspotID string) *models.HotspotStats {
+	mockAnalyticsMu.Lock()
+	defer mockAnalyticsMu.Unlock()
+
+	stats := &models.HotspotStats{
+		HotspotID:    hotspotID,
+		PopularTimes: map[string]int{},
+		DailyViews:   map[string]int{},
+	}
+	counters, ok := mockAnalytics[hotspotID]
+	if !ok {
+		return stats
+	}
+
+	stats.TotalVisits = len(counters.views)
+	stats.UniqueVisitors = len(counters.viewers)
+	stats.Impressions = len(counters.impressions)
+	for hour, n := range counters.popularTimes {
+		stats.PopularTimes[hour] = n
+	}
+	for day, n := range counters.dailyViews {
+		stats.DailyViews[day] = n
+	}
+	return stats
+}
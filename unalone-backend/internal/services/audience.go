@@ -0,0 +1,95 @@
+// Audience restriction checks for hotspots
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"unalone-backend/internal/models"
+)
+
+// AudienceError explains why a user does not meet a hotspot's audience restriction.
+// Messages describe the requirement only and never echo the user's profile values.
+type AudienceError struct {
+	Code    string
+	Message string
+}
+
+func (e *AudienceError) Error() string {
+	return e.Message
+}
+
+// academicEmailSuffixes identify email domains issued by schools and universities
+var academicEmailSuffixes = []string{".edu", ".ac.in", ".edu.in", ".ac.uk"}
+
+// normalizeAudience validates an audience restriction, returning nil when it restricts nothing
+func normalizeAudience(audience *models.HotspotAudience) (*models.HotspotAudience, error) {
+	if audience == nil || *audience == (models.HotspotAudience{}) {
+		return nil, nil
+	}
+	if audience.MinAge > 0 && audience.MaxAge > 0 && audience.MinAge > audience.MaxAge {
+		return nil, errors.New("audience minimum age cannot be greater than maximum age")
+	}
+	normalized := *audience
+	return &normalized, nil
+}
+
+// CheckAudience reports whether a user meets an audience restriction. Restricted
+// hotspots are only open to phone-verified users whose profile has the attributes being checked.
+func CheckAudience(user *models.User, audience *models.HotspotAudience, now time.Time) error {
+	if audience == nil {
+		return nil
+	}
+	if !user.IsPhoneVerified {
+		return &AudienceError{Code: models.AudienceErrorVerificationRequired, Message: "verify your phone number to join this hotspot"}
+	}
+
+	if audience.Gender != "" {
+		if user.Gender == "" || user.Gender == "prefer-not-to-say" {
+			return &AudienceError{Code: models.AudienceErrorProfileIncomplete, Message: "add your gender to your profile to join this hotspot"}
+		}
+		if user.Gender != audience.Gender {
+			return &AudienceError{Code: models.AudienceErrorGender, Message: "this hotspot is limited to a specific audience"}
+		}
+	}
+
+	if audience.MinAge > 0 || audience.MaxAge > 0 {
+		if user.DateOfBirth.IsZero() {
+			return &AudienceError{Code: models.AudienceErrorProfileIncomplete, Message: "add your date of birth to your profile to join this hotspot"}
+		}
+		age := ageOn(user.DateOfBirth, now)
+		if (audience.MinAge > 0 && age < audience.MinAge) || (audience.MaxAge > 0 && age > audience.MaxAge) {
+			return &AudienceError{Code: models.AudienceErrorAge, Message: "this hotspot is limited to a different age group"}
+		}
+	}
+
+	if audience.StudentsOnly && !isAcademicEmail(user.Email) {
+		return &AudienceError{Code: models.AudienceErrorStudentsOnly, Message: "this hotspot is limited to students with a school or university email"}
+	}
+	return nil
+}
+
+// ageOn returns someone's age in whole years on the given date
+func ageOn(dateOfBirth, now time.Time) int {
+	age := now.Year() - dateOfBirth.Year()
+	if now.Month() < dateOfBirth.Month() || (now.Month() == dateOfBirth.Month() && now.Day() < dateOfBirth.Day()) {
+		age--
+	}
+	return age
+}
+
+// isAcademicEmail reports whether an email address belongs to a school or university domain
+func isAcademicEmail(email string) bool {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	domain := strings.ToLower(email[at+1:])
+	for _, suffix := range academicEmailSuffixes {
+		if strings.HasSuffix(domain, suffix) {
+			return true
+		}
+	}
+	return false
+}
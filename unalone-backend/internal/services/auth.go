@@ -2,18 +2,40 @@
 package services
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
-	"os"
+	"log"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
 	"golang.org/x/crypto/bcrypt"
 )
 
+const (
+	// tokenLifetime is how long an issued JWT is valid, and so how long a replaced signing key
+	// keeps verifying tokens
+	tokenLifetime = 24 * time.Hour
+	// defaultJWTSecretRefresh is how often a secret store is checked for a new signing key
+	defaultJWTSecretRefresh = 5 * time.Minute
+)
+
 // AuthService handles authentication operations
 type AuthService struct {
 	firestoreService *FirestoreService
-	jwtSecret        []byte
+	secrets          SecretsProvider
+
+	mu   sync.RWMutex
+	keys []jwtSigningKey // Current signing key first, then replaced keys still verifying
+}
+
+// jwtSigningKey is an HMAC key with the ID sent in the kid header of the tokens it signs
+type jwtSigningKey struct {
+	id        string
+	secret    []byte
+	expiresAt time.Time // Zero for keys that never retire on their own
 }
 
 // Claims represents JWT claims
@@ -23,17 +45,102 @@ type Claims struct {
 	jwt.RegisteredClaims
 }
 
-// NewAuthService creates a new authentication service
-func NewAuthService(fs *FirestoreService) *AuthService {
-	// Load JWT secret from environment, fall back to a default for dev
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		secret = "unalone-secret-key-change-in-production"
+// NewAuthService creates a new authentication service. JWT_SECRET signs tokens and
+// JWT_SECRET_PREVIOUS, if set, still verifies tokens signed before the last rotation.
+func NewAuthService(fs *FirestoreService, secrets SecretsProvider) (*AuthService, error) {
+	// Load JWT secret from the secrets provider; only local development falls back to a default
+	secret, err := signingSecret(context.Background(), secrets, "unalone-secret-key-change-in-production", "JWT_SECRET")
+	if err != nil {
+		return nil, err
 	}
-	return &AuthService{
+	as := &AuthService{
 		firestoreService: fs,
-		jwtSecret:        []byte(secret),
+		secrets:          secrets,
+		keys:             []jwtSigningKey{newJWTSigningKey(secret)},
+	}
+	if previous := lookupSecret(context.Background(), secrets, "JWT_SECRET_PREVIOUS"); previous != "" && previous != secret {
+		key := newJWTSigningKey(previous)
+		key.expiresAt = time.Now().Add(tokenLifetime)
+		as.keys = append(as.keys, key)
+	}
+	return as, nil
+}
+
+func newJWTSigningKey(secret string) jwtSigningKey {
+	sum := sha256.Sum256([]byte(secret))
+	return jwtSigningKey{id: hex.EncodeToString(sum[:8]), secret: []byte(secret)}
+}
+
+// RotateSigningKey makes secret the signing key. The replaced key keeps verifying for one
+// token lifetime, so signed-in users are not logged out. It reports whether the key changed.
+func (as *AuthService) RotateSigningKey(secret string) bool {
+	if secret == "" {
+		return false
+	}
+	key := newJWTSigningKey(secret)
+	now := time.Now()
+
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	if as.keys[0].id == key.id {
+		return false
+	}
+	keys := []jwtSigningKey{key}
+	for i, old := range as.keys {
+		if i == 0 {
+			old.expiresAt = now.Add(tokenLifetime)
+		}
+		if old.id != key.id && (old.expiresAt.IsZero() || old.expiresAt.After(now)) {
+			keys = append(keys, old)
+		}
 	}
+	as.keys = keys
+	return true
+}
+
+// StartKeyRefresh checks the secrets provider for a new JWT_SECRET every JWT_SECRET_REFRESH
+// (default 5m) and rotates to it. Each instance refreshes on its own. Environment variables
+// cannot change at runtime, so nothing runs with the env provider.
+func (as *AuthService) StartKeyRefresh(ctx context.Context) {
+	if as.secrets == nil || as.secrets.Name() == "env" {
+		return
+	}
+	interval := envTTL("JWT_SECRET_REFRESH", defaultJWTSecretRefresh)
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if as.RotateSigningKey(lookupSecret(ctx, as.secrets, "JWT_SECRET")) {
+					log.Printf("JWT signing key rotated")
+				}
+			}
+		}
+	}()
+}
+
+// verificationKeys returns the keys still verifying that may have signed a token: the one
+// named by its kid header, or all of them for tokens issued before key IDs were sent
+func (as *AuthService) verificationKeys(kid string) [][]byte {
+	now := time.Now()
+	as.mu.RLock()
+	defer as.mu.RUnlock()
+	var secrets [][]byte
+	for _, key := range as.keys {
+		if !key.expiresAt.IsZero() && !key.expiresAt.After(now) {
+			continue
+		}
+		if kid == "" || key.id == kid {
+			secrets = append(secrets, key.secret)
+		}
+	}
+	return secrets
 }
 
 // HashPassword hashes a password using bcrypt
@@ -52,7 +159,7 @@ func (as *AuthService) VerifyPassword(hashedPassword, password string) error {
 
 // GenerateToken generates a JWT token for a user
 func (as *AuthService) GenerateToken(userID, email string) (string, error) {
-	expirationTime := time.Now().Add(24 * time.Hour) // Token expires in 24 hours
+	expirationTime := time.Now().Add(tokenLifetime) // Token expires in 24 hours
 
 	claims := &Claims{
 		UserID: userID,
@@ -64,8 +171,13 @@ func (as *AuthService) GenerateToken(userID, email string) (string, error) {
 		},
 	}
 
+	as.mu.RLock()
+	key := as.keys[0]
+	as.mu.RUnlock()
+
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(as.jwtSecret)
+	token.Header["kid"] = key.id
+	tokenString, err := token.SignedString(key.secret)
 	if err != nil {
 		return "", err
 	}
@@ -75,24 +187,34 @@ func (as *AuthService) GenerateToken(userID, email string) (string, error) {
 
 // ValidateToken validates a JWT token and returns the claims
 func (as *AuthService) ValidateToken(tokenString string) (*Claims, error) {
-	claims := &Claims{}
-
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("invalid token signing method")
-		}
-		return as.jwtSecret, nil
-	})
-
-	if err != nil {
-		return nil, err
+	var kid string
+	if unverified, _, err := jwt.NewParser().ParseUnverified(tokenString, &Claims{}); err == nil {
+		kid, _ = unverified.Header["kid"].(string)
 	}
-
-	if !token.Valid {
-		return nil, errors.New("invalid token")
+	secrets := as.verificationKeys(kid)
+	if len(secrets) == 0 {
+		return nil, errors.New("unknown token signing key")
 	}
 
-	return claims, nil
+	var err error
+	for _, secret := range secrets {
+		claims := &Claims{}
+		var token *jwt.Token
+		token, err = jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, errors.New("invalid token signing method")
+			}
+			return secret, nil
+		})
+		if err != nil {
+			continue
+		}
+		if !token.Valid {
+			return nil, errors.New("invalid token")
+		}
+		return claims, nil
+	}
+	return nil, err
 }
 
 // RefreshToken generates a new token for an existing user
@@ -0,0 +1,36 @@
+// Availability status users set to show whether they are up for meeting
+package services
+
+import (
+	"time"
+
+	"unalone-backend/internal/models"
+)
+
+// SetAvailability stores the user's availability status; a duration makes it revert to available afterwards
+func (ps *ProfileService) SetAvailability(userID string, req *models.SetAvailabilityRequest) (*models.Availability, error) {
+	availability := &models.Availability{Status: req.Status}
+	if req.DurationMinutes > 0 {
+		expiresAt := clock.Now().Add(time.Duration(req.DurationMinutes) * time.Minute)
+		availability.ExpiresAt = &expiresAt
+	}
+
+	if _, err := ps.userService.UpdateUser(userID, map[string]interface{}{
+		"availability": availability,
+		"updated_at":   clock.Now(),
+	}); err != nil {
+		return nil, err
+	}
+	return availability, nil
+}
+
+// EffectiveAvailability returns the user's current status; users who never set one, or whose timed status ran out, are available
+func EffectiveAvailability(user *models.User, now time.Time) string {
+	if user == nil || user.Availability == nil || user.Availability.Status == "" {
+		return models.AvailabilityAvailable
+	}
+	if user.Availability.ExpiresAt != nil && !now.Before(*user.Availability.ExpiresAt) {
+		return models.AvailabilityAvailable
+	}
+	return user.Availability.Status
+}
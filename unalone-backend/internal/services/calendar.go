@@ -0,0 +1,357 @@
+// Calendar service for iCalendar (ICS) export of hotspots
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"unalone-backend/internal/models"
+)
+
+const (
+	icsTimeFormat          = "20060102T150405Z"
+	icsDefaultDuration     = time.Hour
+	icsMaxLineOctets       = 75
+	calendarProductID      = "-//Unalone//Hotspots//EN"
+	calendarFeedTokenBytes = 24
+	// CalendarFeedsCollection holds one feed token per user, with tombstones beneath it
+	CalendarFeedsCollection = "calendar_feeds"
+	// calendarTombstoneRetention is how long after its end a deleted hotspot stays in feeds
+	calendarTombstoneRetention = 30 * 24 * time.Hour
+	// calendarMaxTombstones caps the deleted hotspots read for one feed
+	calendarMaxTombstones = 100
+)
+
+// ErrCalendarFeedNotFound is returned for a feed token that was never issued or was rotated
+var ErrCalendarFeedNotFound = errors.New("calendar feed not found")
+
+// CalendarService builds ICS documents and manages per-user feed tokens
+type CalendarService struct {
+	firestoreService *FirestoreService
+	hotspotService   *HotspotService
+}
+
+// NewCalendarService creates a new calendar service
+func NewCalendarService(fs *FirestoreService, hs *HotspotService) *CalendarService {
+	return &CalendarService{
+		firestoreService: fs,
+		hotspotService:   hs,
+	}
+}
+
+// GetFeedToken returns the user's calendar feed token, creating one if needed
+func (cs *CalendarService) GetFeedToken(userID string) (string, error) {
+	if cs.isTestMode() {
+		return getFeedTokenMock(userID, false), nil
+	}
+
+	ctx := cs.firestoreService.GetContext()
+	ref := cs.firestoreService.Collection(CalendarFeedsCollection).Doc(userID)
+	doc, err := cs.firestoreService.GetDoc(ctx, ref)
+	if err == nil {
+		var feed models.CalendarFeed
+		if err := doc.DataTo(&feed); err != nil {
+			return "", err
+		}
+		if feed.Token != "" {
+			return feed.Token, nil
+		}
+	} else if status.Code(err) != codes.NotFound {
+		return "", err
+	}
+	return cs.issueFeedToken(ctx, userID)
+}
+
+// RotateFeedToken invalidates the current feed token and issues a new one
+func (cs *CalendarService) RotateFeedToken(userID string) (string, error) {
+	if cs.isTestMode() {
+		return getFeedTokenMock(userID, true), nil
+	}
+	return cs.issueFeedToken(cs.firestoreService.GetContext(), userID)
+}
+
+// issueFeedToken stores a new token for the user, replacing any earlier one
+func (cs *CalendarService) issueFeedToken(ctx context.Context, userID string) (string, error) {
+	feed := models.CalendarFeed{UserID: userID, Token: genID(calendarFeedTokenBytes), CreatedAt: clock.Now()}
+	ref := cs.firestoreService.Collection(CalendarFeedsCollection).Doc(userID)
+	if _, err := cs.firestoreService.SetDoc(ctx, ref, feed); err != nil {
+		return "", err
+	}
+	return feed.Token, nil
+}
+
+// UserIDForFeedToken resolves a feed token to its owner
+func (cs *CalendarService) UserIDForFeedToken(token string) (string, error) {
+	if token == "" {
+		return "", ErrCalendarFeedNotFound
+	}
+	if cs.isTestMode() {
+		mockCalendarMu.Lock()
+		defer mockCalendarMu.Unlock()
+		userID, ok := mockFeedUsersByToken[token]
+		if !ok {
+			return "", ErrCalendarFeedNotFound
+		}
+		return userID, nil
+	}
+
+	ctx := cs.firestoreService.GetContext()
+	query := cs.firestoreService.Collection(CalendarFeedsCollection).Where("token", "==", token)
+	docs, err := cs.firestoreService.Query(ctx, query, 1)
+	if err != nil {
+		return "", err
+	}
+	if len(docs) == 0 {
+		return "", ErrCalendarFeedNotFound
+	}
+	return docs[0].Ref.ID, nil
+}
+
+// HandleDomainEvent keeps deleted hotspots in their attendees' feeds as cancelled events, so
+// calendar apps remove them instead of keeping the last copy they fetched
+func (cs *CalendarService) HandleDomainEvent(ctx context.Context, event *models.DomainEvent) error {
+	hotspot := event.Hotspot
+	if event.Type != models.DomainEventHotspotDeleted || hotspot == nil || hotspot.ScheduledTime == nil || hotspot.IsDraft {
+		return nil
+	}
+	snapshot := *hotspot
+	snapshot.IsActive = false
+	snapshot.Sequence++
+	snapshot.UpdatedAt = event.OccurredAt
+	tombstone := &models.CalendarTombstone{Hotspot: &snapshot, DeletedAt: event.OccurredAt}
+
+	if cs.isTestMode() {
+		mockCalendarMu.Lock()
+		defer mockCalendarMu.Unlock()
+		for _, userID := range hotspot.Attendees {
+			if mockCalendarTombstones[userID] == nil {
+				mockCalendarTombstones[userID] = make(map[string]*models.CalendarTombstone)
+			}
+			mockCalendarTombstones[userID][hotspot.ID] = tombstone
+		}
+		return nil
+	}
+
+	feeds := cs.firestoreService.Collection(CalendarFeedsCollection)
+	for _, userID := range hotspot.Attendees {
+		ref := feeds.Doc(userID).Collection("tombstones").Doc(hotspot.ID)
+		if _, err := cs.firestoreService.SetDoc(ctx, ref, tombstone); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tombstones returns the deleted hotspots still shown in a user's feed
+func (cs *CalendarService) tombstones(userID string, now time.Time) ([]*models.Hotspot, error) {
+	var stored []*models.CalendarTombstone
+	if cs.isTestMode() {
+		mockCalendarMu.Lock()
+		for hotspotID, tombstone := range mockCalendarTombstones[userID] {
+			if tombstoneExpired(tombstone, now) {
+				delete(mockCalendarTombstones[userID], hotspotID)
+				continue
+			}
+			stored = append(stored, tombstone)
+		}
+		mockCalendarMu.Unlock()
+	} else {
+		ctx := cs.firestoreService.GetContext()
+		query := cs.firestoreService.Collection(CalendarFeedsCollection).Doc(userID).Collection("tombstones").
+			OrderBy("deleted_at", firestore.Desc)
+		docs, err := cs.firestoreService.Query(ctx, query, calendarMaxTombstones)
+		if err != nil {
+			return nil, err
+		}
+		for _, doc := range docs {
+			var tombstone models.CalendarTombstone
+			if err := doc.DataTo(&tombstone); err != nil {
+				return nil, err
+			}
+			if tombstone.Hotspot != nil && !tombstoneExpired(&tombstone, now) {
+				stored = append(stored, &tombstone)
+			}
+		}
+	}
+
+	hotspots := make([]*models.Hotspot, 0, len(stored))
+	for _, tombstone := range stored {
+		hotspots = append(hotspots, tombstone.Hotspot)
+	}
+	return hotspots, nil
+}
+
+// tombstoneExpired reports whether a deleted hotspot ended long enough ago to leave feeds
+func tombstoneExpired(tombstone *models.CalendarTombstone, now time.Time) bool {
+	end := tombstone.Hotspot.ScheduledTime.Add(icsDefaultDuration)
+	if tombstone.Hotspot.EndTime != nil {
+		end = *tombstone.Hotspot.EndTime
+	}
+	return now.Sub(end) > calendarTombstoneRetention
+}
+
+// HotspotICS renders a single hotspot as an ICS document
+func (cs *CalendarService) HotspotICS(hotspot *models.Hotspot) (string, error) {
+	if hotspot.ScheduledTime == nil {
+		return "", errors.New("hotspot has no scheduled time")
+	}
+	return cs.render("Unalone hotspot", []*models.Hotspot{hotspot}), nil
+}
+
+// FeedICS renders all scheduled hotspots the user has joined, and those deleted since, as an
+// ICS document
+func (cs *CalendarService) FeedICS(userID string) (string, error) {
+	hotspots, err := cs.hotspotService.GetJoinedHotspots(userID)
+	if err != nil {
+		return "", err
+	}
+
+	scheduled := make([]*models.Hotspot, 0, len(hotspots))
+	listed := make(map[string]bool, len(hotspots))
+	for _, h := range hotspots {
+		if h.ScheduledTime != nil && !h.IsDraft {
+			scheduled = append(scheduled, h)
+			listed[h.ID] = true
+		}
+	}
+	// Deleted hotspots stay in the feed as cancelled
+	deleted, err := cs.tombstones(userID, clock.Now())
+	if err != nil {
+		return "", err
+	}
+	for _, h := range deleted {
+		if !listed[h.ID] {
+			scheduled = append(scheduled, h)
+		}
+	}
+	sort.Slice(scheduled, func(i, j int) bool {
+		return scheduled[i].ScheduledTime.Before(*scheduled[j].ScheduledTime)
+	})
+
+	return cs.render("Unalone hotspots", scheduled), nil
+}
+
+// render builds a VCALENDAR containing one VEVENT per hotspot
+func (cs *CalendarService) render(name string, hotspots []*models.Hotspot) string {
+	var b strings.Builder
+	now := clock.Now().UTC().Format(icsTimeFormat)
+
+	writeICSLine(&b, "BEGIN:VCALENDAR")
+	writeICSLine(&b, "VERSION:2.0")
+	writeICSLine(&b, "PRODID:"+calendarProductID)
+	writeICSLine(&b, "CALSCALE:GREGORIAN")
+	writeICSLine(&b, "METHOD:PUBLISH")
+	writeICSLine(&b, "X-WR-CALNAME:"+escapeICSText(name))
+
+	for _, h := range hotspots {
+		start := h.ScheduledTime.UTC()
+		end := start.Add(icsDefaultDuration)
+		if h.EndTime != nil {
+			end = h.EndTime.UTC()
+		}
+
+		// Cancelled hotspots stay in the feed so subscribers see the cancellation
+		status := "CONFIRMED"
+		if !h.IsActive {
+			status = "CANCELLED"
+		}
+
+		writeICSLine(&b, "BEGIN:VEVENT")
+		writeICSLine(&b, "UID:"+h.ID+"@unalone")
+		writeICSLine(&b, "DTSTAMP:"+now)
+		writeICSLine(&b, "DTSTART:"+start.Format(icsTimeFormat))
+		writeICSLine(&b, "DTEND:"+end.Format(icsTimeFormat))
+		writeICSLine(&b, "LAST-MODIFIED:"+h.UpdatedAt.UTC().Format(icsTimeFormat))
+		writeICSLine(&b, fmt.Sprintf("SEQUENCE:%d", h.Sequence))
+		writeICSLine(&b, "STATUS:"+status)
+		writeICSLine(&b, "SUMMARY:"+escapeICSText(h.Name))
+		writeICSLine(&b, "DESCRIPTION:"+escapeICSText(h.Description))
+		writeICSLine(&b, "LOCATION:"+escapeICSText(formatICSAddress(h.Address)))
+		writeICSLine(&b, fmt.Sprintf("GEO:%.6f;%.6f", h.Location.Latitude, h.Location.Longitude))
+		writeICSLine(&b, "END:VEVENT")
+	}
+
+	writeICSLine(&b, "END:VCALENDAR")
+	return b.String()
+}
+
+// formatICSAddress joins the non-empty address parts
+func formatICSAddress(addr models.HotspotAddress) string {
+	parts := make([]string, 0, 5)
+	for _, p := range []string{addr.Street, addr.City, addr.Region, addr.PostalCode, addr.Country} {
+		if strings.TrimSpace(p) != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// escapeICSText escapes TEXT values per RFC 5545
+func escapeICSText(s string) string {
+	r := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	)
+	return r.Replace(s)
+}
+
+// writeICSLine writes a content line folded at 75 octets with CRLF endings
+func writeICSLine(b *strings.Builder, line string) {
+	limit := icsMaxLineOctets
+	for len(line) > limit {
+		cut := limit
+		// Avoid splitting a multi-byte UTF-8 sequence
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts toward the limit
+		limit = icsMaxLineOctets - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
+
+// isTestMode checks if we're running with mocked database
+func (cs *CalendarService) isTestMode() bool {
+	return cs.firestoreService.client == nil
+}
+
+// === Mock storage in-memory for development/test ===
+
+var (
+	mockCalendarMu         sync.Mutex
+	mockFeedTokensByUser   = make(map[string]string)                               // userID -> feed token
+	mockFeedUsersByToken   = make(map[string]string)                               // feed token -> userID
+	mockCalendarTombstones = make(map[string]map[string]*models.CalendarTombstone) // userID -> hotspotID -> tombstone
+)
+
+// getFeedTokenMock returns the user's token, issuing a new one when there is none or rotate is set
+func getFeedTokenMock(userID string, rotate bool) string {
+	mockCalendarMu.Lock()
+	defer mockCalendarMu.Unlock()
+	if token, ok := mockFeedTokensByUser[userID]; ok {
+		if !rotate {
+			return token
+		}
+		delete(mockFeedUsersByToken, token)
+	}
+	token := genID(calendarFeedTokenBytes)
+	mockFeedTokensByUser[userID] = token
+	mockFeedUsersByToken[token] = userID
+	return token
+}
@@ -0,0 +1,227 @@
+// Category service for the data-driven hotspot taxonomy
+package services
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"sync"
+
+	"unalone-backend/internal/models"
+)
+
+var categoryIDPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// CategoryService manages hotspot categories and subcategories
+type CategoryService struct {
+	firestoreService *FirestoreService
+}
+
+// NewCategoryService creates a new category service
+func NewCategoryService(fs *FirestoreService) *CategoryService {
+	return &CategoryService{firestoreService: fs}
+}
+
+// ListCategories returns active top-level categories with their active subcategories
+func (cs *CategoryService) ListCategories() ([]models.Category, error) {
+	if cs.isTestMode() {
+		return cs.listCategoriesMock(), nil
+	}
+
+	// TODO: Query Firestore categories collection
+	return nil, errors.New("firestore implementation needed")
+}
+
+// ValidateCategory checks that a category (and optional subcategory) exists and is active
+func (cs *CategoryService) ValidateCategory(category models.HotspotCategory, subcategory string) error {
+	parent, err := cs.getCategory(string(category))
+	if err != nil || !parent.IsActive || parent.ParentID != "" {
+		return errors.New("invalid category: " + string(category))
+	}
+	if subcategory == "" {
+		return nil
+	}
+
+	child, err := cs.getCategory(subcategory)
+	if err != nil || !child.IsActive || child.ParentID != parent.ID {
+		return errors.New("invalid subcategory for " + string(category) + ": " + subcategory)
+	}
+	return nil
+}
+
+// UpsertCategory creates or updates a category; the taxonomy is limited to two levels
+func (cs *CategoryService) UpsertCategory(id string, req *models.UpsertCategoryRequest) (*models.Category, error) {
+	if !categoryIDPattern.MatchString(id) || len(id) > 50 {
+		return nil, errors.New("category ID must be a lowercase slug")
+	}
+	if req.ParentID == id {
+		return nil, errors.New("category cannot be its own parent")
+	}
+	if req.ParentID != "" {
+		parent, err := cs.getCategory(req.ParentID)
+		if err != nil {
+			return nil, errors.New("parent category not found")
+		}
+		if parent.ParentID != "" {
+			return nil, errors.New("subcategories cannot have subcategories")
+		}
+	}
+
+	if !cs.isTestMode() {
+		// TODO: Upsert Firestore document categories/{id}
+		return nil, errors.New("firestore implementation needed")
+	}
+
+	mockCategoriesMu.Lock()
+	defer mockCategoriesMu.Unlock()
+
+	now := clock.Now()
+	category, exists := mockCategories[id]
+	if !exists {
+		category = &models.Category{ID: id, IsActive: true, CreatedAt: now}
+	}
+	if req.ParentID != "" && exists {
+		for _, other := range mockCategories {
+			if other.ParentID == id {
+				return nil, errors.New("category with subcategories cannot become a subcategory")
+			}
+		}
+	}
+
+	category.Name = req.Name
+	category.Icon = req.Icon
+	category.ParentID = req.ParentID
+	category.SortOrder = req.SortOrder
+	if req.IsActive != nil {
+		category.IsActive = *req.IsActive
+	}
+	category.UpdatedAt = now
+	mockCategories[id] = category
+
+	result := *category
+	return &result, nil
+}
+
+// DeactivateCategory hides a category from clients and validation. Existing
+// hotspots keep their category, so categories are never hard-deleted.
+func (cs *CategoryService) DeactivateCategory(id string) error {
+	if !cs.isTestMode() {
+		// TODO: Update Firestore document categories/{id}
+		return errors.New("firestore implementation needed")
+	}
+
+	mockCategoriesMu.Lock()
+	defer mockCategoriesMu.Unlock()
+
+	category, exists := mockCategories[id]
+	if !exists {
+		return errors.New("category not found")
+	}
+	category.IsActive = false
+	category.UpdatedAt = clock.Now()
+	return nil
+}
+
+// getCategory returns a copy of a single category by ID
+func (cs *CategoryService) getCategory(id string) (*models.Category, error) {
+	if !cs.isTestMode() {
+		// TODO: Read Firestore document categories/{id}
+		return nil, errors.New("firestore implementation needed")
+	}
+
+	mockCategoriesMu.RLock()
+	defer mockCategoriesMu.RUnlock()
+
+	category, exists := mockCategories[id]
+	if !exists {
+		return nil, errors.New("category not found")
+	}
+	result := *category
+	return &result, nil
+}
+
+// isTestMode checks if we're running with mocked database
+func (cs *CategoryService) isTestMode() bool {
+	return cs.firestoreService.client == nil
+}
+
+// === Mock storage in-memory for development/test ===
+
+// defaultCategories seeds the taxonomy with the original fixed categories
+var defaultCategories = []models.Category{
+	{ID: string(models.CategoryCafe), Name: "Cafe", Icon: "coffee"},
+	{ID: string(models.CategoryRestaurant), Name: "Restaurant", Icon: "utensils"},
+	{ID: string(models.CategoryPark), Name: "Park", Icon: "tree"},
+	{ID: string(models.CategoryGym), Name: "Gym", Icon: "dumbbell"},
+	{ID: string(models.CategoryLibrary), Name: "Library", Icon: "book"},
+	{ID: string(models.CategoryBeach), Name: "Beach", Icon: "umbrella-beach"},
+	{ID: string(models.CategoryBar), Name: "Bar", Icon: "glass"},
+	{ID: string(models.CategoryEvent), Name: "Event", Icon: "calendar"},
+	{ID: string(models.CategoryStudy), Name: "Study", Icon: "graduation-cap"},
+	{ID: string(models.CategorySports), Name: "Sports", Icon: "futbol"},
+	{ID: string(models.CategoryShopping), Name: "Shopping", Icon: "shopping-bag"},
+	{ID: string(models.CategoryEntertainment), Name: "Entertainment", Icon: "film"},
+	{ID: string(models.CategoryOther), Name: "Other", Icon: "ellipsis"},
+	{ID: "board-games", Name: "Board Games", Icon: "dice", ParentID: string(models.CategoryCafe)},
+	{ID: "football", Name: "Football", Icon: "futbol", ParentID: string(models.CategorySports)},
+	{ID: "cricket", Name: "Cricket", Icon: "baseball", ParentID: string(models.CategorySports)},
+	{ID: "badminton", Name: "Badminton", Icon: "table-tennis", ParentID: string(models.CategorySports)},
+	{ID: "workshop", Name: "Workshop", Icon: "chalkboard", ParentID: string(models.CategoryEvent)},
+	{ID: "live-music", Name: "Live Music", Icon: "music", ParentID: string(models.CategoryEvent)},
+	{ID: "book-club", Name: "Book Club", Icon: "book-open", ParentID: string(models.CategoryLibrary)},
+}
+
+var (
+	mockCategoriesMu sync.RWMutex
+	mockCategories   = seedMockCategories()
+)
+
+func seedMockCategories() map[string]*models.Category {
+	now := clock.Now()
+	categories := make(map[string]*models.Category, len(defaultCategories))
+	for i, c := range defaultCategories {
+		category := c
+		category.SortOrder = i
+		category.IsActive = true
+		category.CreatedAt = now
+		category.UpdatedAt = now
+		categories[category.ID] = &category
+	}
+	return categories
+}
+
+func (cs *CategoryService) listCategoriesMock() []models.Category {
+	mockCategoriesMu.RLock()
+	defer mockCategoriesMu.RUnlock()
+
+	parents := make([]models.Category, 0)
+	children := make(map[string][]models.Category)
+	for _, c := range mockCategories {
+		if !c.IsActive {
+			continue
+		}
+		if c.ParentID == "" {
+			parents = append(parents, *c)
+		} else {
+			children[c.ParentID] = append(children[c.ParentID], *c)
+		}
+	}
+
+	sortCategories(parents)
+	for i := range parents {
+		subs := children[parents[i].ID]
+		sortCategories(subs)
+		parents[i].Subcategories = subs
+	}
+	return parents
+}
+
+// sortCategories orders categories by sort order, then name
+func sortCategories(categories []models.Category) {
+	sort.Slice(categories, func(i, j int) bool {
+		if categories[i].SortOrder != categories[j].SortOrder {
+			return categories[i].SortOrder < categories[j].SortOrder
+		}
+		return categories[i].Name < categories[j].Name
+	})
+}
@@ -4,34 +4,135 @@ package services
 import (
 	"errors"
 	"sort"
+	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	"unalone-backend/internal/models"
 
 	"github.com/google/uuid"
 )
 
+const (
+	// EphemeralChatTTL is how long messages last in hotspots with ephemeral chat
+	EphemeralChatTTL = 24 * time.Hour
+	// defaultChatRetentionDays is how long after a hotspot ends its chat is kept
+	defaultChatRetentionDays = 30
+)
+
 // ChatService provides methods to interact with chat messages
 type ChatService struct {
 	firestoreService *FirestoreService
 	userService      *UserService
 	hotspotService   *HotspotService
+	retention        time.Duration
 }
 
-// NewChatService creates a new chat service
+// NewChatService creates a new chat service. CHAT_RETENTION_DAYS overrides how long a
+// hotspot's chat is kept after the hotspot ends.
 func NewChatService(fs *FirestoreService, us *UserService, hs *HotspotService) *ChatService {
 	return &ChatService{
 		firestoreService: fs,
 		userService:      us,
 		hotspotService:   hs,
+		retention:        retentionDays("CHAT_RETENTION_DAYS", defaultChatRetentionDays),
 	}
 }
 
 // SendMessage sends a chat message to a hotspot chat room after verifying membership
-func (cs *ChatService) SendMessage(userID, hotspotID, content string) (*models.ChatMessage, error) {
-	if content == "" {
+func (cs *ChatService) SendMessage(userID, hotspotID, clientID, content string) (*models.ChatMessage, error) {
+	return cs.sendMessage(userID, hotspotID, &models.ChatMessage{Content: content, ClientID: clientID})
+}
+
+// SendAnnouncement posts a host announcement to a hotspot chat room
+func (cs *ChatService) SendAnnouncement(userID, hotspotID, clientID, content string) (*models.ChatMessage, error) {
+	return cs.sendMessage(userID, hotspotID, &models.ChatMessage{Content: content, Announcement: true, ClientID: clientID})
+}
+
+// SendLocationPin drops a labelled map pin in a hotspot chat room, e.g. "I'm at the north entrance"
+func (cs *ChatService) SendLocationPin(userID, hotspotID, clientID string, latitude, longitude float64, label string) (*models.ChatMessage, error) {
+	label = strings.TrimSpace(label)
+	if err := validateLocationPin(latitude, longitude, label); err != nil {
+		return nil, err
+	}
+	return cs.sendMessage(userID, hotspotID, &models.ChatMessage{
+		Kind:     models.ChatMessageKindLocationPin,
+		Content:  label, // Clients that do not render pins still show the label
+		Location: &models.ChatLocationPin{Latitude: latitude, Longitude: longitude, Label: label},
+		ClientID: clientID,
+	})
+}
+
+// SendVoiceNote posts a transcoded voice note to a hotspot chat room
+func (cs *ChatService) SendVoiceNote(userID, hotspotID string, attachment *models.ChatAttachment) (*models.ChatMessage, error) {
+	return cs.sendMessage(userID, hotspotID, &models.ChatMessage{
+		Kind:       models.ChatMessageKindVoiceNote,
+		Content:    "Voice note",
+		Attachment: attachment,
+	})
+}
+
+// RequireMember returns the hotspot when the user has joined it
+func (cs *ChatService) RequireMember(userID, hotspotID string) (*models.Hotspot, error) {
+	hotspot, err := cs.hotspotService.GetHotspot(hotspotID)
+	if err != nil {
+		return nil, err
+	}
+	if !containsString(hotspot.Attendees, userID) {
+		return nil, ErrNotHotspotMember
+	}
+	return hotspot, nil
+}
+
+// HasMessage reports whether a message is still in a hotspot's chat, i.e. not purged or expired
+func (cs *ChatService) HasMessage(hotspotID, messageID string) (bool, error) {
+	if !cs.isTestMode() {
+		// TODO: Read chats/{hotspotID}/messages/{messageID}
+		return false, errors.New("firestore implementation needed")
+	}
+
+	now := clock.Now()
+	mockChatMu.Lock()
+	defer mockChatMu.Unlock()
+	for _, msg := range mockChatMessages[hotspotID] {
+		if msg.ID == messageID {
+			return !chatMessageExpired(msg, now), nil
+		}
+	}
+	return false, nil
+}
+
+// validateLocationPin checks a pin's coordinates and label
+func validateLocationPin(latitude, longitude float64, label string) error {
+	if latitude < -90 || latitude > 90 || longitude < -180 || longitude > 180 {
+		return ErrInvalidLocationPin
+	}
+	if latitude == 0 && longitude == 0 {
+		return ErrInvalidLocationPin
+	}
+	if label == "" || len([]rune(label)) > models.MaxLocationPinLabel {
+		return ErrInvalidLocationPin
+	}
+	return nil
+}
+
+// chatClientIDNamespace derives message IDs from client-generated IDs
+var chatClientIDNamespace = uuid.MustParse("5b0e3f2c-6f57-4d0e-9a53-3f6a3c1d2b7e")
+
+// sendMessage fills in and stores a message whose content fields are set. A message with a
+// client ID gets an ID derived from it, so a retried send finds the stored original and
+// returns it with ErrDuplicateChatMessage instead of creating a second message.
+func (cs *ChatService) sendMessage(userID, hotspotID string, msg *models.ChatMessage) (*models.ChatMessage, error) {
+	if msg.Content == "" {
 		return nil, errors.New("message content cannot be empty")
 	}
+	if utf8.RuneCountInString(msg.Content) > models.MaxChatMessageLength {
+		return nil, ErrChatMessageTooLong
+	}
+	if len(msg.ClientID) > models.MaxChatClientIDLength {
+		return nil, ErrInvalidChatClientID
+	}
 
 	// Verify hotspot exists and user is an attendee
 	hotspot, err := cs.hotspotService.GetHotspot(hotspotID)
@@ -47,7 +148,10 @@ func (cs *ChatService) SendMessage(userID, hotspotID, content string) (*models.C
 		}
 	}
 	if !isMember {
-		return nil, errors.New("user is not a member of this hotspot")
+		return nil, ErrNotHotspotMember
+	}
+	if msg.Announcement && !isChatHost(hotspot, userID) {
+		return nil, ErrNotChatHost
 	}
 
 	// Get user to load nickname
@@ -56,20 +160,25 @@ func (cs *ChatService) SendMessage(userID, hotspotID, content string) (*models.C
 		return nil, err
 	}
 
-	msg := &models.ChatMessage{
-		ID:        uuid.New().String(),
-		HotspotID: hotspotID,
-		UserID:    userID,
-		Nickname:  user.Nickname,
-		Content:   content,
-		CreatedAt: time.Now(),
+	msg.ID = uuid.New().String()
+	if msg.ClientID != "" {
+		msg.ID = uuid.NewSHA1(chatClientIDNamespace, []byte(hotspotID+"\x00"+userID+"\x00"+msg.ClientID)).String()
+	}
+	msg.HotspotID = hotspotID
+	msg.UserID = userID
+	msg.Nickname = user.Nickname
+	msg.CreatedAt = clock.Now()
+	if hotspot.EphemeralChat {
+		expiresAt := msg.CreatedAt.Add(EphemeralChatTTL)
+		msg.ExpiresAt = &expiresAt
 	}
 
 	if cs.isTestMode() {
 		return cs.saveMessageMock(msg)
 	}
 
-	// TODO: Save to Firestore collection structure: chats/{hotspotID}/messages/{messageID}
+	// TODO: Create chats/{hotspotID}/messages/{messageID}; on AlreadyExists return the stored
+	// message with ErrDuplicateChatMessage
 	return nil, errors.New("firestore implementation needed")
 }
 
@@ -80,43 +189,390 @@ func (cs *ChatService) GetRecentMessages(hotspotID string, limit int) ([]*models
 	}
 
 	if cs.isTestMode() {
-		return cs.getRecentMessagesMock(hotspotID, limit)
+		return cs.getRecentMessagesMock(hotspotID, limit, clock.Now())
 	}
 
-	// TODO: Query Firestore ordered by created_at desc, limit N
+	// TODO: Query Firestore ordered by created_at desc, limit N, skipping messages past expires_at
 	return nil, errors.New("firestore implementation needed")
 }
 
+// MessagesAfter returns the unexpired messages sent after messageID, oldest first, up to limit.
+// complete is false when messageID is no longer in the history or more than limit messages followed it.
+func (cs *ChatService) MessagesAfter(hotspotID, messageID string, limit int) ([]*models.ChatMessage, bool, error) {
+	if limit <= 0 {
+		limit = models.ChatResumeLimit
+	}
+
+	if cs.isTestMode() {
+		msgs, complete := cs.messagesAfterMock(hotspotID, messageID, limit, clock.Now())
+		return msgs, complete, nil
+	}
+
+	// TODO: Read the created_at of chats/{hotspotID}/messages/{messageID}, then query messages
+	// ordered by created_at after it, limit+1, skipping messages past expires_at
+	return nil, false, errors.New("firestore implementation needed")
+}
+
+// PurgeExpiredMessages deletes ephemeral messages past their expiry and the whole chat of
+// hotspots that ended longer ago than the retention period, returning how many messages went
+func (cs *ChatService) PurgeExpiredMessages(now time.Time) (int, error) {
+	if !cs.isTestMode() {
+		// TODO: Delete chats/{hotspotID}/messages where expires_at < now, and the chats of hotspots
+		// whose end is before now - retention, in batches
+		return 0, errors.New("firestore implementation needed")
+	}
+
+	mockChatMu.Lock()
+	hotspotIDs := make([]string, 0, len(mockChatMessages))
+	for hotspotID := range mockChatMessages {
+		hotspotIDs = append(hotspotIDs, hotspotID)
+	}
+	mockChatMu.Unlock()
+
+	cutoff := now.Add(-cs.retention)
+	purged := 0
+	for _, hotspotID := range hotspotIDs {
+		hotspot, err := cs.hotspotService.GetHotspot(hotspotID)
+		if err != nil {
+			// The hotspot was deleted, so nobody can reach its chat any more
+			purged += cs.deleteChatMock(hotspotID)
+			continue
+		}
+		if end := hotspotEndsAt(hotspot); end != nil && end.Before(cutoff) {
+			purged += cs.deleteChatMock(hotspotID)
+			continue
+		}
+		purged += cs.dropExpiredMock(hotspotID, now)
+	}
+	return purged, nil
+}
+
+// chatMuteDurations maps the timed mute options to their length
+var chatMuteDurations = map[string]time.Duration{
+	models.ChatMuteOneHour:    time.Hour,
+	models.ChatMuteEightHours: 8 * time.Hour,
+}
+
+// Chat errors
+var (
+	// ErrNotHotspotMember is returned when a user who has not joined a hotspot uses its chat
+	ErrNotHotspotMember = errors.New("user is not a member of this hotspot")
+	// ErrNotChatHost is returned when someone other than the host or a co-host pins or announces
+	ErrNotChatHost = errors.New("only the host can pin messages or post announcements")
+	// ErrTooManyPins is returned when a chat already has MaxPinnedMessages pinned
+	ErrTooManyPins = errors.New("a chat can have at most 3 pinned messages")
+	// ErrChatMessageNotFound is returned when a message is not in the hotspot's chat
+	ErrChatMessageNotFound = errors.New("message not found")
+	// ErrInvalidLocationPin is returned for pins with bad coordinates or a missing or long label
+	ErrInvalidLocationPin = errors.New("location pins need valid coordinates and a label of up to 100 characters")
+	// ErrChatMessageTooLong is returned for messages over MaxChatMessageLength characters
+	ErrChatMessageTooLong = errors.New("messages can be at most 2000 characters")
+	// ErrInvalidChatClientID is returned for client message IDs over MaxChatClientIDLength
+	ErrInvalidChatClientID = errors.New("client_id can be at most 64 characters")
+	// ErrDuplicateChatMessage is returned, with the original message, when a client ID was already sent
+	ErrDuplicateChatMessage = errors.New("message already sent")
+)
+
+// PinMessage pins a message in a hotspot chat; pinning a pinned message is a no-op
+func (cs *ChatService) PinMessage(userID, hotspotID, messageID string) (*models.ChatMessage, error) {
+	if err := cs.requireHost(userID, hotspotID); err != nil {
+		return nil, err
+	}
+	if !cs.isTestMode() {
+		// TODO: Set pinned_at on chats/{hotspotID}/messages/{messageID} in a transaction that counts pinned messages
+		return nil, errors.New("firestore implementation needed")
+	}
+	return cs.setPinnedMock(hotspotID, messageID, true, clock.Now())
+}
+
+// UnpinMessage unpins a message in a hotspot chat
+func (cs *ChatService) UnpinMessage(userID, hotspotID, messageID string) (*models.ChatMessage, error) {
+	if err := cs.requireHost(userID, hotspotID); err != nil {
+		return nil, err
+	}
+	if !cs.isTestMode() {
+		// TODO: Clear pinned_at on chats/{hotspotID}/messages/{messageID}
+		return nil, errors.New("firestore implementation needed")
+	}
+	return cs.setPinnedMock(hotspotID, messageID, false, clock.Now())
+}
+
+// GetPinnedMessages returns a hotspot chat's pinned messages, earliest pinned first
+func (cs *ChatService) GetPinnedMessages(hotspotID string) ([]*models.ChatMessage, error) {
+	if !cs.isTestMode() {
+		// TODO: Query chats/{hotspotID}/messages where pinned_at != null ordered by pinned_at
+		return nil, errors.New("firestore implementation needed")
+	}
+
+	now := clock.Now()
+	mockChatMu.Lock()
+	defer mockChatMu.Unlock()
+	pins := make([]*models.ChatMessage, 0, len(mockChatPins[hotspotID]))
+	for _, msg := range mockChatPins[hotspotID] {
+		if chatMessageExpired(msg, now) {
+			continue
+		}
+		pinned := *msg
+		pins = append(pins, &pinned)
+	}
+	return pins, nil
+}
+
+// chatMessageExpired reports whether an ephemeral message has expired
+func chatMessageExpired(msg *models.ChatMessage, now time.Time) bool {
+	return msg.ExpiresAt != nil && !now.Before(*msg.ExpiresAt)
+}
+
+// requireHost checks that the user hosts or co-hosts the hotspot
+func (cs *ChatService) requireHost(userID, hotspotID string) error {
+	hotspot, err := cs.hotspotService.GetHotspot(hotspotID)
+	if err != nil {
+		return err
+	}
+	if !isChatHost(hotspot, userID) {
+		return ErrNotChatHost
+	}
+	return nil
+}
+
+// isChatHost reports whether the user may moderate the hotspot's chat
+func isChatHost(hotspot *models.Hotspot, userID string) bool {
+	return hotspot.CreatedBy == userID || containsString(hotspot.CoHosts, userID)
+}
+
+// MuteChat silences chat notifications from a hotspot for the user, for a while or until unmuted
+func (cs *ChatService) MuteChat(userID, hotspotID, duration string) (*models.ChatNotificationPrefs, error) {
+	hotspot, err := cs.hotspotService.GetHotspot(hotspotID)
+	if err != nil {
+		return nil, err
+	}
+	if !containsString(hotspot.Attendees, userID) {
+		return nil, ErrNotHotspotMember
+	}
+
+	now := clock.Now()
+	prefs := &models.ChatNotificationPrefs{HotspotID: hotspotID, UserID: userID, Muted: true, UpdatedAt: now}
+	if length, ok := chatMuteDurations[duration]; ok {
+		until := now.Add(length)
+		prefs.MutedUntil = &until
+	} else if duration != models.ChatMuteForever {
+		return nil, errors.New("duration must be 1h, 8h or forever")
+	}
+	return prefs, cs.savePrefs(prefs)
+}
+
+// UnmuteChat turns chat notifications from a hotspot back on
+func (cs *ChatService) UnmuteChat(userID, hotspotID string) (*models.ChatNotificationPrefs, error) {
+	prefs := &models.ChatNotificationPrefs{HotspotID: hotspotID, UserID: userID, UpdatedAt: clock.Now()}
+	return prefs, cs.savePrefs(prefs)
+}
+
+// GetChatPrefs returns the user's notification settings for a hotspot's chat
+func (cs *ChatService) GetChatPrefs(userID, hotspotID string) (*models.ChatNotificationPrefs, error) {
+	if !cs.isTestMode() {
+		// TODO: Read Firestore document chats/{hotspotID}/prefs/{userID}
+		return nil, errors.New("firestore implementation needed")
+	}
+
+	mockChatPrefsMu.Lock()
+	defer mockChatPrefsMu.Unlock()
+	if prefs, ok := mockChatPrefs[hotspotID][userID]; ok {
+		result := *prefs
+		return &result, nil
+	}
+	return &models.ChatNotificationPrefs{HotspotID: hotspotID, UserID: userID}, nil
+}
+
+// ChatMuted reports whether the user has muted a hotspot's chat as of now
+func (cs *ChatService) ChatMuted(userID, hotspotID string, now time.Time) bool {
+	prefs, err := cs.GetChatPrefs(userID, hotspotID)
+	if err != nil || !prefs.Muted {
+		return false
+	}
+	return prefs.MutedUntil == nil || now.Before(*prefs.MutedUntil)
+}
+
+func (cs *ChatService) savePrefs(prefs *models.ChatNotificationPrefs) error {
+	if !cs.isTestMode() {
+		// TODO: Set Firestore document chats/{hotspotID}/prefs/{userID}
+		return errors.New("firestore implementation needed")
+	}
+
+	mockChatPrefsMu.Lock()
+	defer mockChatPrefsMu.Unlock()
+	if mockChatPrefs[prefs.HotspotID] == nil {
+		mockChatPrefs[prefs.HotspotID] = make(map[string]*models.ChatNotificationPrefs)
+	}
+	stored := *prefs
+	mockChatPrefs[prefs.HotspotID][prefs.UserID] = &stored
+	return nil
+}
+
 // isTestMode checks if we're running with mocked database
 func (cs *ChatService) isTestMode() bool {
 	return cs.firestoreService.client == nil
 }
 
 // === Mock storage in-memory for development/test ===
-var mockChatMessages = make(map[string][]*models.ChatMessage) // hotspotID -> messages
+var (
+	mockChatMu       sync.Mutex
+	mockChatMessages = make(map[string][]*models.ChatMessage) // hotspotID -> messages
+	mockChatPins     = make(map[string][]*models.ChatMessage) // hotspotID -> pinned messages, kept when trimmed from history
+)
+
+var (
+	mockChatPrefsMu sync.Mutex
+	mockChatPrefs   = make(map[string]map[string]*models.ChatNotificationPrefs) // hotspotID -> userID -> prefs
+)
 
 func (cs *ChatService) saveMessageMock(msg *models.ChatMessage) (*models.ChatMessage, error) {
+	mockChatMu.Lock()
+	defer mockChatMu.Unlock()
 	list := mockChatMessages[msg.HotspotID]
+	if msg.ClientID != "" {
+		for _, existing := range list {
+			if existing.ID == msg.ID {
+				stored := *existing
+				return &stored, ErrDuplicateChatMessage
+			}
+		}
+	}
 	list = append(list, msg)
 	// Keep only the most recent 200 to limit memory
 	if len(list) > 200 {
 		list = list[len(list)-200:]
 	}
 	mockChatMessages[msg.HotspotID] = list
-	return msg, nil
+	stored := *msg
+	return &stored, nil
 }
 
-func (cs *ChatService) getRecentMessagesMock(hotspotID string, limit int) ([]*models.ChatMessage, error) {
+func (cs *ChatService) getRecentMessagesMock(hotspotID string, limit int, now time.Time) ([]*models.ChatMessage, error) {
+	mockChatMu.Lock()
+	defer mockChatMu.Unlock()
 	list := mockChatMessages[hotspotID]
 	// Sort by CreatedAt ascending for UI
 	sort.Slice(list, func(i, j int) bool {
 		return list[i].CreatedAt.Before(list[j].CreatedAt)
 	})
-	if len(list) > limit {
-		list = list[len(list)-limit:]
+	// Return copies of unexpired messages to avoid external mutation
+	out := make([]*models.ChatMessage, 0, len(list))
+	for _, msg := range list {
+		if chatMessageExpired(msg, now) {
+			continue
+		}
+		copied := *msg
+		out = append(out, &copied)
+	}
+	if len(out) > limit {
+		out = out[len(out)-limit:]
 	}
-	// Return a copy to avoid external mutation
-	out := make([]*models.ChatMessage, len(list))
-	copy(out, list)
 	return out, nil
 }
+
+func (cs *ChatService) messagesAfterMock(hotspotID, messageID string, limit int, now time.Time) ([]*models.ChatMessage, bool) {
+	mockChatMu.Lock()
+	defer mockChatMu.Unlock()
+	list := mockChatMessages[hotspotID]
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].CreatedAt.Before(list[j].CreatedAt)
+	})
+	start := -1
+	for i, msg := range list {
+		if msg.ID == messageID {
+			start = i + 1
+			break
+		}
+	}
+	if start < 0 {
+		return nil, false
+	}
+	out := make([]*models.ChatMessage, 0, len(list)-start)
+	for _, msg := range list[start:] {
+		if chatMessageExpired(msg, now) {
+			continue
+		}
+		if len(out) == limit {
+			return out, false
+		}
+		copied := *msg
+		out = append(out, &copied)
+	}
+	return out, true
+}
+
+// deleteChatMock removes a hotspot's messages, pins and notification settings
+func (cs *ChatService) deleteChatMock(hotspotID string) int {
+	mockChatMu.Lock()
+	purged := len(mockChatMessages[hotspotID])
+	delete(mockChatMessages, hotspotID)
+	delete(mockChatPins, hotspotID)
+	mockChatMu.Unlock()
+
+	mockChatPrefsMu.Lock()
+	delete(mockChatPrefs, hotspotID)
+	mockChatPrefsMu.Unlock()
+	return purged
+}
+
+// dropExpiredMock removes a hotspot's expired ephemeral messages, including pinned ones
+func (cs *ChatService) dropExpiredMock(hotspotID string, now time.Time) int {
+	mockChatMu.Lock()
+	defer mockChatMu.Unlock()
+	kept := mockChatMessages[hotspotID][:0]
+	for _, msg := range mockChatMessages[hotspotID] {
+		if !chatMessageExpired(msg, now) {
+			kept = append(kept, msg)
+		}
+	}
+	purged := len(mockChatMessages[hotspotID]) - len(kept)
+	mockChatMessages[hotspotID] = kept
+
+	pins := mockChatPins[hotspotID][:0]
+	for _, msg := range mockChatPins[hotspotID] {
+		if !chatMessageExpired(msg, now) {
+			pins = append(pins, msg)
+		}
+	}
+	mockChatPins[hotspotID] = pins
+	return purged
+}
+
+// setPinnedMock pins or unpins a stored message; the pin list shares the message so history shows pinned_at
+func (cs *ChatService) setPinnedMock(hotspotID, messageID string, pinned bool, now time.Time) (*models.ChatMessage, error) {
+	mockChatMu.Lock()
+	defer mockChatMu.Unlock()
+
+	pins := mockChatPins[hotspotID]
+	for i, msg := range pins {
+		if msg.ID != messageID {
+			continue
+		}
+		if !pinned {
+			msg.PinnedAt = nil
+			mockChatPins[hotspotID] = append(pins[:i:i], pins[i+1:]...)
+		}
+		result := *msg
+		return &result, nil
+	}
+
+	var target *models.ChatMessage
+	for _, msg := range mockChatMessages[hotspotID] {
+		if msg.ID == messageID {
+			target = msg
+			break
+		}
+	}
+	if target == nil {
+		return nil, ErrChatMessageNotFound
+	}
+	if pinned {
+		if len(pins) >= models.MaxPinnedMessages {
+			return nil, ErrTooManyPins
+		}
+		target.PinnedAt = &now
+		mockChatPins[hotspotID] = append(pins, target)
+	}
+	result := *target
+	return &result, nil
+}
@@ -0,0 +1,117 @@
+// Rotating QR codes hosts display so attendees can prove they are at the venue
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"unalone-backend/internal/models"
+)
+
+const (
+	// defaultQRRotation is how often the host's check-in code changes
+	defaultQRRotation = 30 * time.Second
+	// qrGraceWindows is how many previous codes are still accepted, covering a scan just before rotation
+	qrGraceWindows = 1
+	// qrTokenBytes is how much of the HMAC goes into a token
+	qrTokenBytes = 12
+	// qrPayloadPrefix starts the text encoded in the QR code so the app knows what it scanned
+	qrPayloadPrefix = "unalone://checkin/"
+)
+
+var (
+	ErrInvalidCheckInCode  = errors.New("check-in code is invalid or has expired")
+	ErrCheckInCodeHostOnly = errors.New("only the host or a co-host can show the check-in code")
+)
+
+// CheckInQRService issues and verifies rotating check-in codes. Codes are derived from the hotspot
+// ID and the current time window with an HMAC, so nothing is stored and any replica can verify them.
+type CheckInQRService struct {
+	hotspotService *HotspotService
+	secret         []byte
+	rotation       time.Duration
+}
+
+// NewCheckInQRService creates a new check-in code service. CHECKIN_QR_SECRET signs the codes
+// (falling back to JWT_SECRET), read through the secrets provider, and CHECKIN_QR_ROTATE_SECONDS
+// overrides how often they change.
+func NewCheckInQRService(hs *HotspotService, secrets SecretsProvider) (*CheckInQRService, error) {
+	secret, err := signingSecret(context.Background(), secrets, "unalone-checkin-secret-change-in-production", "CHECKIN_QR_SECRET", "JWT_SECRET")
+	if err != nil {
+		return nil, err
+	}
+	rotation := defaultQRRotation
+	if n, err := strconv.Atoi(strings.TrimSpace(os.Getenv("CHECKIN_QR_ROTATE_SECONDS"))); err == nil && n >= 10 {
+		rotation = time.Duration(n) * time.Second
+	}
+	return &CheckInQRService{hotspotService: hs, secret: []byte(secret), rotation: rotation}, nil
+}
+
+// CurrentCode returns the code the host or a co-host shows at the venue. It is only available
+// while attendees can check in.
+func (qs *CheckInQRService) CurrentCode(userID, hotspotID string) (*models.CheckInQRCode, error) {
+	hotspot, err := qs.hotspotService.GetHotspot(hotspotID)
+	if err != nil {
+		return nil, err
+	}
+	if hotspot.CreatedBy != userID && !containsString(hotspot.CoHosts, userID) {
+		return nil, ErrCheckInCodeHostOnly
+	}
+	if hotspot.IsDraft || !hotspot.IsActive {
+		return nil, errors.New("hotspot is not active")
+	}
+	now := clock.Now()
+	if err := CheckInWindowOpen(hotspot, now); err != nil {
+		return nil, err
+	}
+
+	window := now.UnixNano() / int64(qs.rotation)
+	token := qs.token(hotspotID, window)
+	return &models.CheckInQRCode{
+		HotspotID:     hotspotID,
+		Token:         token,
+		QRPayload:     qrPayloadPrefix + hotspotID + "/" + token,
+		ExpiresAt:     time.Unix(0, (window+1)*int64(qs.rotation)),
+		RotateSeconds: int(qs.rotation / time.Second),
+	}, nil
+}
+
+// CheckIn verifies a scanned code and checks the attendee in. It reports whether this was their
+// first check-in and their first QR check-in at the hotspot.
+func (qs *CheckInQRService) CheckIn(userID, hotspotID, token string) (*models.Hotspot, bool, bool, error) {
+	if !qs.valid(hotspotID, parseQRToken(hotspotID, token), clock.Now()) {
+		return nil, false, false, ErrInvalidCheckInCode
+	}
+	return qs.hotspotService.CheckInQR(userID, hotspotID)
+}
+
+// valid accepts the current window's token and the ones just before it
+func (qs *CheckInQRService) valid(hotspotID, token string, now time.Time) bool {
+	window := now.UnixNano() / int64(qs.rotation)
+	for i := int64(0); i <= qrGraceWindows; i++ {
+		if hmac.Equal([]byte(token), []byte(qs.token(hotspotID, window-i))) {
+			return true
+		}
+	}
+	return false
+}
+
+func (qs *CheckInQRService) token(hotspotID string, window int64) string {
+	mac := hmac.New(sha256.New, qs.secret)
+	fmt.Fprintf(mac, "checkin:%s:%d", hotspotID, window)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:qrTokenBytes])
+}
+
+// parseQRToken accepts either the bare token or the whole scanned payload for this hotspot
+func parseQRToken(hotspotID, scanned string) string {
+	scanned = strings.TrimSpace(scanned)
+	return strings.TrimPrefix(scanned, qrPayloadPrefix+hotspotID+"/")
+}
@@ -0,0 +1,31 @@
+// Clock the services read the current time from
+package services
+
+import "time"
+
+// Clock tells services what time it is. The server runs on the system clock; the end-to-end
+// tests install one they can move forward to reach check-in windows, expiries and purges.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock reads the wall clock
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// clock is read by every service for timestamps, windows and expiries. Latency timers, job
+// scheduling, quotas, rate limits and token expiry stay on the wall clock.
+var clock = SystemClock
+
+// SetClock replaces the clock the services read. Call it before the services are built.
+func SetClock(c Clock) {
+	clock = c
+}
+
+// Now returns the current time on the services' clock, for handlers that stamp or compare times
+func Now() time.Time {
+	return clock.Now()
+}
@@ -0,0 +1,328 @@
+// Club service: organizer pages with admins who host hotspots, and users who follow them
+package services
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"unalone-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrClubNotFound  = errors.New("club not found")
+	ErrNotClubAdmin  = errors.New("only club admins can do this")
+	ErrClubLastAdmin = errors.New("a club needs at least one admin")
+)
+
+// ClubService stores clubs and their followers
+type ClubService struct {
+	firestoreService *FirestoreService
+	userService      *UserService
+}
+
+// NewClubService creates a new club service
+func NewClubService(fs *FirestoreService, us *UserService) *ClubService {
+	return &ClubService{firestoreService: fs, userService: us}
+}
+
+// CreateClub creates a club in the user's organization with the user as its first admin
+func (cs *ClubService) CreateClub(userID string, req *models.CreateClubRequest) (*models.Club, error) {
+	user, err := cs.userService.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	now := clock.Now()
+	club := &models.Club{
+		ID:          uuid.New().String(),
+		Name:        strings.TrimSpace(req.Name),
+		Description: strings.TrimSpace(req.Description),
+		LogoURL:     req.LogoURL,
+		AdminIDs:    []string{userID},
+		OrgID:       user.OrgID,
+		CreatedBy:   userID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if !cs.isTestMode() {
+		// TODO: Implement Firestore write of clubs/{clubID}
+		return nil, errors.New("firestore implementation needed")
+	}
+
+	mockClubsMu.Lock()
+	defer mockClubsMu.Unlock()
+	mockClubs[club.ID] = club
+	copied := *club
+	return &copied, nil
+}
+
+// GetClub returns a club as seen from an organization; clubs of other organizations are not found
+func (cs *ClubService) GetClub(clubID, orgID string) (*models.Club, error) {
+	if !cs.isTestMode() {
+		// TODO: Implement Firestore read of clubs/{clubID}
+		return nil, errors.New("firestore implementation needed")
+	}
+
+	mockClubsMu.Lock()
+	defer mockClubsMu.Unlock()
+	club, ok := mockClubs[clubID]
+	if !ok || club.OrgID != orgID {
+		return nil, ErrClubNotFound
+	}
+	return copyClub(club), nil
+}
+
+// ListClubs returns an organization's clubs whose name contains query, most followed first
+func (cs *ClubService) ListClubs(orgID, query string, limit, offset int) ([]models.Club, int, error) {
+	if !cs.isTestMode() {
+		// TODO: Query Firestore clubs where org_id == orgID ordered by follower_count
+		return nil, 0, errors.New("firestore implementation needed")
+	}
+
+	query = strings.ToLower(strings.TrimSpace(query))
+	mockClubsMu.Lock()
+	clubs := []models.Club{}
+	for _, club := range mockClubs {
+		if club.OrgID == orgID && strings.Contains(strings.ToLower(club.Name), query) {
+			clubs = append(clubs, *copyClub(club))
+		}
+	}
+	mockClubsMu.Unlock()
+
+	sortClubs(clubs)
+	total := len(clubs)
+	start := min(offset, total)
+	end := min(start+limit, total)
+	return clubs[start:end], total, nil
+}
+
+// Profile builds a club page for a viewer: its admins and whether the viewer follows or runs it
+func (cs *ClubService) Profile(viewerID string, club *models.Club) (*models.ClubProfile, error) {
+	profile := &models.ClubProfile{
+		Club:             *club,
+		Admins:           make([]models.PublicUser, 0, len(club.AdminIDs)),
+		IsAdmin:          containsString(club.AdminIDs, viewerID),
+		UpcomingHotspots: []models.HotspotWithDistance{},
+	}
+	for _, adminID := range club.AdminIDs {
+		if admin, err := cs.userService.GetUserByID(adminID); err == nil {
+			profile.Admins = append(profile.Admins, models.PublicUser{ID: admin.ID, Nickname: admin.Nickname})
+		}
+	}
+	following, err := cs.IsFollowing(viewerID, club.ID)
+	if err != nil {
+		return nil, err
+	}
+	profile.Following = following
+	return profile, nil
+}
+
+// UpdateClub changes a club's name, description or logo (admins only)
+func (cs *ClubService) UpdateClub(userID, clubID, orgID string, req *models.UpdateClubRequest) (*models.Club, error) {
+	return cs.updateClub(userID, clubID, orgID, func(club *models.Club) error {
+		if req.Name != nil {
+			club.Name = strings.TrimSpace(*req.Name)
+		}
+		if req.Description != nil {
+			club.Description = strings.TrimSpace(*req.Description)
+		}
+		if req.LogoURL != nil {
+			club.LogoURL = *req.LogoURL
+		}
+		return nil
+	})
+}
+
+// AddAdmin makes another member of the club's organization an admin (admins only)
+func (cs *ClubService) AddAdmin(userID, clubID, orgID, adminID string) (*models.Club, error) {
+	admin, err := cs.userService.GetUserByID(adminID)
+	if err != nil || admin.OrgID != orgID {
+		return nil, errors.New("user not found")
+	}
+	return cs.updateClub(userID, clubID, orgID, func(club *models.Club) error {
+		if !containsString(club.AdminIDs, adminID) {
+			club.AdminIDs = append(club.AdminIDs, adminID)
+		}
+		return nil
+	})
+}
+
+// RemoveAdmin takes away a user's admin role (admins only); admins may also step down themselves
+func (cs *ClubService) RemoveAdmin(userID, clubID, orgID, adminID string) (*models.Club, error) {
+	return cs.updateClub(userID, clubID, orgID, func(club *models.Club) error {
+		if !containsString(club.AdminIDs, adminID) {
+			return errors.New("user not found")
+		}
+		if len(club.AdminIDs) == 1 {
+			return ErrClubLastAdmin
+		}
+		club.AdminIDs = removeString(club.AdminIDs, adminID)
+		return nil
+	})
+}
+
+// CheckHost returns an error unless the user may host hotspots for the club
+func (cs *ClubService) CheckHost(userID, orgID, clubID string) error {
+	club, err := cs.GetClub(clubID, orgID)
+	if err != nil {
+		return err
+	}
+	if !containsString(club.AdminIDs, userID) {
+		return ErrNotClubAdmin
+	}
+	return nil
+}
+
+// Follow subscribes the user to the club's new hotspots; following twice is a no-op
+func (cs *ClubService) Follow(userID, clubID, orgID string) (*models.Club, error) {
+	if !cs.isTestMode() {
+		// TODO: Create clubs/{clubID}/followers/{userID} and increment follower_count in a transaction
+		return nil, errors.New("firestore implementation needed")
+	}
+
+	mockClubsMu.Lock()
+	defer mockClubsMu.Unlock()
+	club, ok := mockClubs[clubID]
+	if !ok || club.OrgID != orgID {
+		return nil, ErrClubNotFound
+	}
+	followers := mockClubFollowers[clubID]
+	if followers == nil {
+		followers = make(map[string]time.Time)
+		mockClubFollowers[clubID] = followers
+	}
+	if _, ok := followers[userID]; !ok {
+		followers[userID] = clock.Now()
+		club.FollowerCount = len(followers)
+	}
+	return copyClub(club), nil
+}
+
+// Unfollow stops the user's notifications from the club; unfollowing a club not followed is a no-op
+func (cs *ClubService) Unfollow(userID, clubID, orgID string) (*models.Club, error) {
+	if !cs.isTestMode() {
+		// TODO: Delete clubs/{clubID}/followers/{userID} and decrement follower_count in a transaction
+		return nil, errors.New("firestore implementation needed")
+	}
+
+	mockClubsMu.Lock()
+	defer mockClubsMu.Unlock()
+	club, ok := mockClubs[clubID]
+	if !ok || club.OrgID != orgID {
+		return nil, ErrClubNotFound
+	}
+	delete(mockClubFollowers[clubID], userID)
+	club.FollowerCount = len(mockClubFollowers[clubID])
+	return copyClub(club), nil
+}
+
+// IsFollowing reports whether the user follows the club
+func (cs *ClubService) IsFollowing(userID, clubID string) (bool, error) {
+	if !cs.isTestMode() {
+		// TODO: Implement Firestore read of clubs/{clubID}/followers/{userID}
+		return false, errors.New("firestore implementation needed")
+	}
+
+	mockClubsMu.Lock()
+	defer mockClubsMu.Unlock()
+	_, ok := mockClubFollowers[clubID][userID]
+	return ok, nil
+}
+
+// FollowedClubs lists the clubs the user follows, most followed first
+func (cs *ClubService) FollowedClubs(userID string) ([]models.Club, error) {
+	if !cs.isTestMode() {
+		// TODO: Collection group query on followers where user_id == userID
+		return nil, errors.New("firestore implementation needed")
+	}
+
+	mockClubsMu.Lock()
+	clubs := []models.Club{}
+	for clubID, followers := range mockClubFollowers {
+		if club, ok := mockClubs[clubID]; ok {
+			if _, follows := followers[userID]; follows {
+				clubs = append(clubs, *copyClub(club))
+			}
+		}
+	}
+	mockClubsMu.Unlock()
+
+	sortClubs(clubs)
+	return clubs, nil
+}
+
+// Followers returns the IDs of the users following the club
+func (cs *ClubService) Followers(clubID string) ([]string, error) {
+	if !cs.isTestMode() {
+		// TODO: Query Firestore clubs/{clubID}/followers
+		return nil, errors.New("firestore implementation needed")
+	}
+
+	mockClubsMu.Lock()
+	defer mockClubsMu.Unlock()
+	followers := make([]string, 0, len(mockClubFollowers[clubID]))
+	for userID := range mockClubFollowers[clubID] {
+		followers = append(followers, userID)
+	}
+	sort.Strings(followers)
+	return followers, nil
+}
+
+// updateClub applies change to a club the user is an admin of
+func (cs *ClubService) updateClub(userID, clubID, orgID string, change func(club *models.Club) error) (*models.Club, error) {
+	if !cs.isTestMode() {
+		// TODO: Implement Firestore transaction on clubs/{clubID}
+		return nil, errors.New("firestore implementation needed")
+	}
+
+	mockClubsMu.Lock()
+	defer mockClubsMu.Unlock()
+	club, ok := mockClubs[clubID]
+	if !ok || club.OrgID != orgID {
+		return nil, ErrClubNotFound
+	}
+	if !containsString(club.AdminIDs, userID) {
+		return nil, ErrNotClubAdmin
+	}
+	updated := copyClub(club)
+	if err := change(updated); err != nil {
+		return nil, err
+	}
+	updated.UpdatedAt = clock.Now()
+	mockClubs[clubID] = updated
+	return copyClub(updated), nil
+}
+
+// isTestMode checks if we're running with mocked database
+func (cs *ClubService) isTestMode() bool {
+	return cs.firestoreService.client == nil
+}
+
+// copyClub copies a club so callers cannot change the stored admin list
+func copyClub(club *models.Club) *models.Club {
+	copied := *club
+	copied.AdminIDs = append([]string(nil), club.AdminIDs...)
+	return &copied
+}
+
+// sortClubs orders clubs most followed first, then by name
+func sortClubs(clubs []models.Club) {
+	sort.Slice(clubs, func(i, j int) bool {
+		if clubs[i].FollowerCount != clubs[j].FollowerCount {
+			return clubs[i].FollowerCount > clubs[j].FollowerCount
+		}
+		return clubs[i].Name < clubs[j].Name
+	})
+}
+
+// === Mock storage in-memory for development/test ===
+
+var (
+	mockClubsMu       sync.Mutex
+	mockClubs         = make(map[string]*models.Club)         // clubID -> club
+	mockClubFollowers = make(map[string]map[string]time.Time) // clubID -> userID -> followed at
+)
@@ -0,0 +1,219 @@
+// Consent service for versioned, append-only records of what users allow us to process
+package services
+
+import (
+	"errors"
+	"sync"
+
+	"unalone-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrConsentPurposeNotFound = errors.New("consent purpose not found")
+	ErrConsentVersionOutdated = errors.New("the consent text has changed; show the current version and ask again")
+)
+
+// ConsentService records grants and revocations and answers whether a purpose is allowed.
+// Services check Allowed before processing; revocations also notify OnRevoked callbacks so
+// data collected under the consent can be removed.
+type ConsentService struct {
+	firestoreService *FirestoreService
+
+	mu        sync.RWMutex
+	onRevoked []func(userID, purpose string)
+}
+
+// NewConsentService creates a new consent service
+func NewConsentService(fs *FirestoreService) *ConsentService {
+	return &ConsentService{firestoreService: fs}
+}
+
+// OnRevoked registers a callback invoked after a user revokes a purpose
+func (cs *ConsentService) OnRevoked(fn func(userID, purpose string)) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.onRevoked = append(cs.onRevoked, fn)
+}
+
+// Allowed reports whether a purpose may be processed for a user. A revocation always holds;
+// a grant counts only at the purpose's current version, and otherwise the default applies.
+// If the records cannot be read, only default-granted purposes are allowed.
+func (cs *ConsentService) Allowed(userID, purpose string) bool {
+	state, err := cs.state(userID, purpose)
+	if err != nil {
+		p, ok := consentPurpose(purpose)
+		return ok && p.Default
+	}
+	return state.Granted
+}
+
+// ListConsents returns the user's current standing for every purpose
+func (cs *ConsentService) ListConsents(userID string) ([]models.ConsentState, error) {
+	records, err := cs.History(userID)
+	if err != nil {
+		return nil, err
+	}
+	states := make([]models.ConsentState, 0, len(models.ConsentPurposes))
+	for _, purpose := range models.ConsentPurposes {
+		states = append(states, consentState(purpose, latestConsent(records, purpose.ID)))
+	}
+	return states, nil
+}
+
+// History returns every consent record of a user, newest first
+func (cs *ConsentService) History(userID string) ([]models.ConsentRecord, error) {
+	if !cs.isTestMode() {
+		// TODO: Query Firestore consents where user_id == userID ordered by created_at desc
+		return nil, errors.New("firestore implementation needed")
+	}
+
+	mockConsentsMu.RLock()
+	defer mockConsentsMu.RUnlock()
+	stored := mockConsents[userID]
+	records := make([]models.ConsentRecord, 0, len(stored))
+	for i := len(stored) - 1; i >= 0; i-- {
+		records = append(records, stored[i])
+	}
+	return records, nil
+}
+
+// Grant records the user's consent to a purpose at the version they were shown
+func (cs *ConsentService) Grant(userID, purpose string, version int, source string) (*models.ConsentState, error) {
+	p, ok := consentPurpose(purpose)
+	if !ok {
+		return nil, ErrConsentPurposeNotFound
+	}
+	if version != p.Version {
+		return nil, ErrConsentVersionOutdated
+	}
+	return cs.record(userID, p, true, source)
+}
+
+// GrantCurrent records consent at the purpose's current version, for grants given through
+// another setting such as turning the weekly digest on
+func (cs *ConsentService) GrantCurrent(userID, purpose, source string) (*models.ConsentState, error) {
+	p, ok := consentPurpose(purpose)
+	if !ok {
+		return nil, ErrConsentPurposeNotFound
+	}
+	return cs.record(userID, p, true, source)
+}
+
+// Revoke records that the user withdrew consent to a purpose
+func (cs *ConsentService) Revoke(userID, purpose, source string) (*models.ConsentState, error) {
+	p, ok := consentPurpose(purpose)
+	if !ok {
+		return nil, ErrConsentPurposeNotFound
+	}
+	state, err := cs.record(userID, p, false, source)
+	if err != nil {
+		return nil, err
+	}
+
+	cs.mu.RLock()
+	callbacks := append([]func(userID, purpose string){}, cs.onRevoked...)
+	cs.mu.RUnlock()
+	for _, fn := range callbacks {
+		fn(userID, purpose)
+	}
+	return state, nil
+}
+
+// record appends a grant or revocation at the purpose's current version
+func (cs *ConsentService) record(userID string, purpose models.ConsentPurpose, granted bool, source string) (*models.ConsentState, error) {
+	record := models.ConsentRecord{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Purpose:   purpose.ID,
+		Granted:   granted,
+		Version:   purpose.Version,
+		Source:    source,
+		CreatedAt: clock.Now(),
+	}
+	if !cs.isTestMode() {
+		// TODO: Create Firestore document consents/{record.ID}
+		return nil, errors.New("firestore implementation needed")
+	}
+
+	mockConsentsMu.Lock()
+	mockConsents[userID] = append(mockConsents[userID], record)
+	mockConsentsMu.Unlock()
+
+	state := consentState(purpose, &record)
+	return &state, nil
+}
+
+// state returns the user's current standing for one purpose
+func (cs *ConsentService) state(userID, purpose string) (*models.ConsentState, error) {
+	p, ok := consentPurpose(purpose)
+	if !ok {
+		return nil, ErrConsentPurposeNotFound
+	}
+	records, err := cs.History(userID)
+	if err != nil {
+		return nil, err
+	}
+	state := consentState(p, latestConsent(records, purpose))
+	return &state, nil
+}
+
+// isTestMode checks if we're running with mocked database
+func (cs *ConsentService) isTestMode() bool {
+	return cs.firestoreService.client == nil
+}
+
+// consentPurpose looks up a purpose by ID
+func consentPurpose(id string) (models.ConsentPurpose, bool) {
+	for _, purpose := range models.ConsentPurposes {
+		if purpose.ID == id {
+			return purpose, true
+		}
+	}
+	return models.ConsentPurpose{}, false
+}
+
+// latestConsent returns the newest record for a purpose from records sorted newest first
+func latestConsent(records []models.ConsentRecord, purpose string) *models.ConsentRecord {
+	for i := range records {
+		if records[i].Purpose == purpose {
+			return &records[i]
+		}
+	}
+	return nil
+}
+
+// consentState derives the standing for a purpose from its latest record, if any
+func consentState(purpose models.ConsentPurpose, latest *models.ConsentRecord) models.ConsentState {
+	state := models.ConsentState{
+		Purpose:        purpose.ID,
+		Description:    purpose.Description,
+		Granted:        purpose.Default,
+		CurrentVersion: purpose.Version,
+		Source:         models.ConsentSourceDefault,
+	}
+	if latest == nil {
+		return state
+	}
+	createdAt := latest.CreatedAt
+	state.Version = latest.Version
+	state.Source = latest.Source
+	state.UpdatedAt = &createdAt
+	switch {
+	case !latest.Granted:
+		state.Granted = false
+	case latest.Version >= purpose.Version:
+		state.Granted = true
+	default:
+		state.Outdated = true
+	}
+	return state
+}
+
+// === Mock storage in-memory for development/test ===
+
+var (
+	mockConsentsMu sync.RWMutex
+	mockConsents   = make(map[string][]models.ConsentRecord) // userID -> records, oldest first
+)
@@ -0,0 +1,429 @@
+// Weekly email digest of upcoming hotspots, nearby picks and friend activity
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"log"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"unalone-backend/internal/jobs"
+	"unalone-backend/internal/models"
+)
+
+const (
+	// digestInterval is the least time between two digests to the same user
+	digestInterval = 7 * 24 * time.Hour
+	// digestHorizon is how far ahead the digest looks for hotspots
+	digestHorizon = 7 * 24 * time.Hour
+
+	digestMaxUpcoming       = 5
+	digestMaxRecommended    = 3
+	digestMaxFriendActivity = 5
+	// digestMaxRadiusKm caps the search for nearby picks whatever the user's distance setting
+	digestMaxRadiusKm = 25
+
+	defaultUnsubscribeURL = "https://unalone.app/api/v1/email/unsubscribe"
+)
+
+var ErrInvalidUnsubscribeLink = errors.New("unsubscribe link is invalid")
+
+// DigestSendJob sends one user's digest if it is still due when the job runs
+var DigestSendJob = jobs.Type[DigestJob]{Name: "digest.send", MaxAttempts: 3, Timeout: time.Minute}
+
+// DigestJob is the payload of DigestSendJob
+type DigestJob struct {
+	UserID string `json:"user_id"`
+}
+
+var digestSubjectTemplate = template.Must(template.New("subject").Parse(
+	`{{if .Upcoming}}Your week on Unalone: {{len .Upcoming}} upcoming {{if eq (len .Upcoming) 1}}hotspot{{else}}hotspots{{end}}{{else}}Your week on Unalone{{end}}`))
+
+var digestBodyTemplate = template.Must(template.New("body").Parse(`Hi {{.Nickname}},
+{{if .Upcoming}}
+Coming up for you:
+{{range .Upcoming}}- {{.Name}}, {{.When}}{{if .City}} in {{.City}}{{end}}
+{{end}}{{end}}{{if .Recommended}}
+Picked for you nearby:
+{{range .Recommended}}- {{.Name}}, {{.When}} ({{printf "%.1f" .DistanceKm}} km away)
+{{end}}{{end}}{{if .FriendActivity}}
+What your friends are up to:
+{{range .FriendActivity}}- {{.Friend}} is going to {{.Name}}, {{.When}}
+{{end}}{{end}}
+Open the app to join in.
+
+You get this weekly digest because email notifications are on.
+Unsubscribe: {{.UnsubscribeURL}}
+`))
+
+// DigestService composes and sends the weekly digest email. Users are opted in until they
+// unsubscribe; the email, hotspot and master notification switches are honored on every send.
+type DigestService struct {
+	firestoreService *FirestoreService
+	userService      *UserService
+	profileService   *ProfileService
+	hotspotService   *HotspotService
+	email            EmailSender
+	consents         *ConsentService
+	queue            *jobs.Queue
+
+	secret         []byte
+	unsubscribeURL string
+}
+
+// NewDigestService creates a new digest service. DIGEST_UNSUBSCRIBE_SECRET signs unsubscribe
+// links (falling back to JWT_SECRET), read through the secrets provider, and
+// DIGEST_UNSUBSCRIBE_URL is where they point.
+func NewDigestService(fs *FirestoreService, us *UserService, ps *ProfileService, hs *HotspotService, email EmailSender, cs *ConsentService, queue *jobs.Queue, secrets SecretsProvider) (*DigestService, error) {
+	secret, err := signingSecret(context.Background(), secrets, "unalone-digest-secret-change-in-production", "DIGEST_UNSUBSCRIBE_SECRET", "JWT_SECRET")
+	if err != nil {
+		return nil, err
+	}
+	unsubscribeURL := strings.TrimSpace(os.Getenv("DIGEST_UNSUBSCRIBE_URL"))
+	if unsubscribeURL == "" {
+		unsubscribeURL = defaultUnsubscribeURL
+	}
+	ds := &DigestService{
+		firestoreService: fs,
+		userService:      us,
+		profileService:   ps,
+		hotspotService:   hs,
+		email:            email,
+		consents:         cs,
+		queue:            queue,
+		secret:           []byte(secret),
+		unsubscribeURL:   unsubscribeURL,
+	}
+	DigestSendJob.Handle(queue, func(ctx context.Context, job DigestJob) error {
+		_, err := ds.sendTo(job.UserID, clock.Now())
+		return err
+	})
+	return ds, nil
+}
+
+// GetSubscription returns the user's digest subscription. It is only enabled while the user
+// consents to marketing email.
+func (ds *DigestService) GetSubscription(userID string) (*models.DigestSubscription, error) {
+	if !ds.isTestMode() {
+		// TODO: Implement Firestore read of digest_subscriptions/{userID}
+		return nil, errors.New("firestore implementation needed")
+	}
+	consented := ds.consents.Allowed(userID, models.ConsentMarketingEmail)
+	mockDigestMu.Lock()
+	defer mockDigestMu.Unlock()
+	if sub, ok := mockDigestSubscriptions[userID]; ok {
+		copied := *sub
+		copied.Enabled = copied.Enabled && consented
+		return &copied, nil
+	}
+	return &models.DigestSubscription{UserID: userID, Enabled: consented}, nil
+}
+
+// SetEnabled turns the weekly digest on or off for a user, granting or revoking marketing
+// email consent with it since the digest is the only marketing email
+func (ds *DigestService) SetEnabled(userID string, enabled bool) (*models.DigestSubscription, error) {
+	var err error
+	if enabled {
+		_, err = ds.consents.GrantCurrent(userID, models.ConsentMarketingEmail, models.ConsentSourceEmailDigest)
+	} else {
+		_, err = ds.consents.Revoke(userID, models.ConsentMarketingEmail, models.ConsentSourceEmailDigest)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ds.update(userID, func(sub *models.DigestSubscription) {
+		sub.Enabled = enabled
+		if enabled {
+			sub.UnsubscribedAt = nil
+		} else if sub.UnsubscribedAt == nil {
+			now := clock.Now()
+			sub.UnsubscribedAt = &now
+		}
+	})
+}
+
+// Unsubscribe turns the digest off from an email link, without signing in
+func (ds *DigestService) Unsubscribe(userID, token string) error {
+	if userID == "" || !hmac.Equal([]byte(token), []byte(ds.unsubscribeToken(userID))) {
+		return ErrInvalidUnsubscribeLink
+	}
+	if _, err := ds.userService.GetUserByID(userID); err != nil {
+		return ErrInvalidUnsubscribeLink
+	}
+	_, err := ds.SetEnabled(userID, false)
+	return err
+}
+
+// Preview renders the digest the user would get now, without sending it or honoring opt-outs
+func (ds *DigestService) Preview(userID string) (*models.EmailMessage, error) {
+	user, err := ds.userService.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	content, err := ds.compose(user, clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	return ds.render(user, content)
+}
+
+// EnqueueDue queues a send job for every subscribed user who has not had a digest in the last
+// week; it runs hourly so a missed run only delays digests slightly. Each job checks again that
+// the digest is due and has something to read, and failed sends are retried by the job queue.
+func (ds *DigestService) EnqueueDue(ctx context.Context) (*models.DigestRun, error) {
+	userIDs, err := ds.userService.ListUserIDs()
+	if err != nil {
+		return nil, err
+	}
+	run := &models.DigestRun{}
+	now := clock.Now()
+	for _, userID := range userIDs {
+		if ctx.Err() != nil {
+			break
+		}
+		run.Checked++
+		sub, err := ds.GetSubscription(userID)
+		if err != nil {
+			run.Failed++
+			log.Printf("Digest for %s failed: %v", userID, err)
+			continue
+		}
+		if !sub.Enabled || (sub.LastSentAt != nil && now.Sub(*sub.LastSentAt) < digestInterval) {
+			run.Skipped++
+			continue
+		}
+		if _, err := DigestSendJob.Enqueue(ctx, ds.queue, DigestJob{UserID: userID}); err != nil {
+			run.Failed++
+			log.Printf("Digest for %s failed: %v", userID, err)
+			continue
+		}
+		run.Queued++
+	}
+	if run.Queued > 0 || run.Failed > 0 {
+		log.Printf("Digest: queued %d, skipped %d, failed %d", run.Queued, run.Skipped, run.Failed)
+	}
+	return run, ctx.Err()
+}
+
+// sendTo sends one user's digest when it is due, reporting whether it went out
+func (ds *DigestService) sendTo(userID string, now time.Time) (bool, error) {
+	sub, err := ds.GetSubscription(userID)
+	if err != nil {
+		return false, err
+	}
+	if !sub.Enabled || (sub.LastSentAt != nil && now.Sub(*sub.LastSentAt) < digestInterval) {
+		return false, nil
+	}
+	settings, err := ds.profileService.GetUserSettings(userID)
+	if err != nil {
+		return false, err
+	}
+	if _, email := resolveChannels(settings, models.NotificationCategoryHotspots, now); !email {
+		return false, nil
+	}
+	user, err := ds.userService.GetUserByID(userID)
+	if err != nil || user.Email == "" {
+		return false, err
+	}
+
+	content, err := ds.compose(user, now)
+	if err != nil {
+		return false, err
+	}
+	if len(content.Upcoming) == 0 && len(content.Recommended) == 0 && len(content.FriendActivity) == 0 {
+		return false, nil
+	}
+	msg, err := ds.render(user, content)
+	if err != nil {
+		return false, err
+	}
+	if err := ds.email.SendEmail(msg); err != nil {
+		return false, err
+	}
+	_, err = ds.update(userID, func(sub *models.DigestSubscription) { sub.LastSentAt = &now })
+	return true, err
+}
+
+// compose gathers the hotspots for a user's digest
+func (ds *DigestService) compose(user *models.User, now time.Time) (*models.DigestContent, error) {
+	settings, err := ds.profileService.GetUserSettings(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	loc := time.UTC
+	if settings.QuietHours.Timezone != "" {
+		if l, err := time.LoadLocation(settings.QuietHours.Timezone); err == nil {
+			loc = l
+		}
+	}
+	content := &models.DigestContent{Nickname: user.Nickname, UnsubscribeURL: ds.UnsubscribeLink(user.ID)}
+
+	joined, err := ds.hotspotService.GetJoinedHotspots(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, hotspot := range upcomingHotspots(joined, now, digestMaxUpcoming) {
+		content.Upcoming = append(content.Upcoming, digestHotspotOf(hotspot, loc))
+	}
+
+	// Friend activity goes first so its hotspots are not repeated as nearby picks
+	var listed map[string]bool
+	content.FriendActivity, listed = ds.friendActivity(user, now, loc)
+
+	if user.Location.Latitude != 0 || user.Location.Longitude != 0 {
+		radius := float64(min(max(settings.DistanceRadius, 1), digestMaxRadiusKm))
+		results, err := ds.hotspotService.SearchHotspots(&models.HotspotSearchRequest{
+			Latitude: user.Location.Latitude, Longitude: user.Location.Longitude, Radius: radius, Limit: 100, OrgID: user.OrgID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, result := range results.Hotspots {
+			hotspot := result.Hotspot
+			if listed[hotspot.ID] || !isBrowsable(&hotspot) || !startsWithin(&hotspot, now, digestHorizon) || ds.hotspotService.CheckJoin(&hotspot, user.ID, false) != nil {
+				continue
+			}
+			item := digestHotspotOf(&hotspot, loc)
+			item.DistanceKm = result.Distance
+			content.Recommended = append(content.Recommended, item)
+			if len(content.Recommended) == digestMaxRecommended {
+				break
+			}
+		}
+	}
+	return content, nil
+}
+
+// friendActivity lists upcoming hotspots friends are going to that the user can see and has not
+// joined, along with the IDs of the hotspots listed
+func (ds *DigestService) friendActivity(user *models.User, now time.Time, loc *time.Location) ([]models.DigestHotspot, map[string]bool) {
+	type going struct {
+		friend  string
+		hotspot *models.Hotspot
+	}
+	var all []going
+	seen := make(map[string]bool)
+	for _, friendID := range user.Friends {
+		if containsString(user.MutedFriends, friendID) {
+			continue
+		}
+		friend, err := ds.userService.GetUserByID(friendID)
+		if err != nil {
+			continue
+		}
+		hotspots, err := ds.hotspotService.GetJoinedHotspots(friendID)
+		if err != nil {
+			continue
+		}
+		for _, hotspot := range upcomingHotspots(hotspots, now, 0) {
+			if seen[hotspot.ID] || hotspot.IsDraft || containsString(hotspot.Attendees, user.ID) || !CanViewHotspot(hotspot, user.ID, user.OrgID) {
+				continue
+			}
+			seen[hotspot.ID] = true
+			all = append(all, going{friend: friend.Nickname, hotspot: hotspot})
+		}
+	}
+	sort.SliceStable(all, func(i, j int) bool { return all[i].hotspot.ScheduledTime.Before(*all[j].hotspot.ScheduledTime) })
+
+	var items []models.DigestHotspot
+	listed := make(map[string]bool)
+	for _, g := range all {
+		item := digestHotspotOf(g.hotspot, loc)
+		item.Friend = g.friend
+		items = append(items, item)
+		listed[g.hotspot.ID] = true
+		if len(items) == digestMaxFriendActivity {
+			break
+		}
+	}
+	return items, listed
+}
+
+func (ds *DigestService) render(user *models.User, content *models.DigestContent) (*models.EmailMessage, error) {
+	var subject, body strings.Builder
+	if err := digestSubjectTemplate.Execute(&subject, content); err != nil {
+		return nil, err
+	}
+	if err := digestBodyTemplate.Execute(&body, content); err != nil {
+		return nil, err
+	}
+	return &models.EmailMessage{To: user.Email, Subject: subject.String(), Body: body.String()}, nil
+}
+
+// UnsubscribeLink returns the signed link that turns off a user's digest
+func (ds *DigestService) UnsubscribeLink(userID string) string {
+	query := url.Values{"user": {userID}, "token": {ds.unsubscribeToken(userID)}}
+	return ds.unsubscribeURL + "?" + query.Encode()
+}
+
+func (ds *DigestService) unsubscribeToken(userID string) string {
+	mac := hmac.New(sha256.New, ds.secret)
+	mac.Write([]byte("digest-unsubscribe:" + userID))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:16])
+}
+
+func (ds *DigestService) update(userID string, fn func(sub *models.DigestSubscription)) (*models.DigestSubscription, error) {
+	if !ds.isTestMode() {
+		// TODO: Implement Firestore merge into digest_subscriptions/{userID}
+		return nil, errors.New("firestore implementation needed")
+	}
+	mockDigestMu.Lock()
+	defer mockDigestMu.Unlock()
+	sub, ok := mockDigestSubscriptions[userID]
+	if !ok {
+		sub = &models.DigestSubscription{UserID: userID, Enabled: true}
+		mockDigestSubscriptions[userID] = sub
+	}
+	fn(sub)
+	copied := *sub
+	return &copied, nil
+}
+
+func (ds *DigestService) isTestMode() bool {
+	return ds.firestoreService.client == nil
+}
+
+// upcomingHotspots returns active hotspots starting within the digest horizon, soonest first;
+// limit 0 keeps them all
+func upcomingHotspots(hotspots []*models.Hotspot, now time.Time, limit int) []*models.Hotspot {
+	var upcoming []*models.Hotspot
+	for _, hotspot := range hotspots {
+		if hotspot.IsActive && !hotspot.IsDraft && startsWithin(hotspot, now, digestHorizon) {
+			upcoming = append(upcoming, hotspot)
+		}
+	}
+	sort.SliceStable(upcoming, func(i, j int) bool { return upcoming[i].ScheduledTime.Before(*upcoming[j].ScheduledTime) })
+	if limit > 0 && len(upcoming) > limit {
+		upcoming = upcoming[:limit]
+	}
+	return upcoming
+}
+
+// startsWithin reports whether a scheduled hotspot starts between now and now+horizon
+func startsWithin(hotspot *models.Hotspot, now time.Time, horizon time.Duration) bool {
+	return hotspot.ScheduledTime != nil && hotspot.ScheduledTime.After(now) && hotspot.ScheduledTime.Before(now.Add(horizon))
+}
+
+func digestHotspotOf(hotspot *models.Hotspot, loc *time.Location) models.DigestHotspot {
+	return models.DigestHotspot{
+		Name: hotspot.Name,
+		When: hotspot.ScheduledTime.In(loc).Format("Mon 2 Jan, 15:04 MST"),
+		City: hotspot.Address.City,
+	}
+}
+
+// === Mock storage in-memory for development/test ===
+
+var (
+	mockDigestMu            sync.Mutex
+	mockDigestSubscriptions = make(map[string]*models.DigestSubscription) // userID -> subscription
+)
@@ -0,0 +1,78 @@
+// Email delivery through SMTP, falling back to the server log
+package services
+
+import (
+	"fmt"
+	"log"
+	"net/smtp"
+	"os"
+	"strings"
+
+	"unalone-backend/internal/models"
+)
+
+// EmailSender delivers plain-text emails
+type EmailSender interface {
+	SendEmail(msg *models.EmailMessage) error
+}
+
+// NewEmailSender returns an SMTP sender when SMTP_HOST is set, otherwise one that logs messages
+func NewEmailSender() EmailSender {
+	host := strings.TrimSpace(os.Getenv("SMTP_HOST"))
+	if host == "" {
+		log.Printf("Email: SMTP_HOST not set, logging emails instead of sending")
+		return logEmailSender{}
+	}
+	port := os.Getenv("SMTP_PORT")
+	if port == "" {
+		port = "587"
+	}
+	from := os.Getenv("EMAIL_FROM")
+	if from == "" {
+		from = "Unalone <no-reply@unalone.app>"
+	}
+	return &smtpEmailSender{
+		addr:     host + ":" + port,
+		host:     host,
+		username: os.Getenv("SMTP_USERNAME"),
+		password: os.Getenv("SMTP_PASSWORD"),
+		from:     from,
+	}
+}
+
+// logEmailSender writes emails to the server log for development
+type logEmailSender struct{}
+
+func (logEmailSender) SendEmail(msg *models.EmailMessage) error {
+	log.Printf("[email] to=%s subject=%q body=%q", msg.To, msg.Subject, msg.Body)
+	return nil
+}
+
+// smtpEmailSender sends through an SMTP relay, using STARTTLS when the server offers it
+type smtpEmailSender struct {
+	addr     string
+	host     string
+	username string
+	password string
+	from     string
+}
+
+func (s *smtpEmailSender) SendEmail(msg *models.EmailMessage) error {
+	var auth smtp.Auth
+	if s.username != "" {
+		auth = smtp.PlainAuth("", s.username, s.password, s.host)
+	}
+	// Header values come from our own templates; strip line breaks so they cannot add headers
+	subject := strings.NewReplacer("\r", " ", "\n", " ").Replace(msg.Subject)
+	body := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s",
+		s.from, msg.To, subject, strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return smtp.SendMail(s.addr, auth, envelopeAddress(s.from), []string{msg.To}, []byte(body))
+}
+
+// envelopeAddress extracts the bare address from "Name <address>"
+func envelopeAddress(from string) string {
+	if start, end := strings.LastIndex(from, "<"), strings.LastIndex(from, ">"); start >= 0 && end > start {
+		return from[start+1 : end]
+	}
+	return from
+}
@@ -0,0 +1,175 @@
+// Event bus delivering domain events to subscribers in the background
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"unalone-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+const (
+	eventBusBuffer = 1024
+	// eventStreamKey and eventStreamGroup are used by the Redis Streams backend
+	eventStreamKey    = "events:domain"
+	eventStreamGroup  = "domain-subscribers"
+	eventStreamMaxLen = 10000
+)
+
+// EventHandler reacts to a domain event; errors are logged
+type EventHandler func(ctx context.Context, event *models.DomainEvent) error
+
+type eventSubscription struct {
+	name    string
+	handler EventHandler
+}
+
+// EventBus lets services publish domain events without knowing who reacts to them.
+// Handlers run one event at a time on a background worker, in publish order. With
+// EVENT_BUS_BACKEND=redis and Redis available, events go through a Redis Stream
+// consumer group so each event is handled once across replicas.
+type EventBus struct {
+	redisService *RedisService
+	useStream    bool
+	consumerID   string
+
+	mu          sync.RWMutex
+	subscribers map[string][]eventSubscription // event type -> subscribers
+	queue       chan *models.DomainEvent
+}
+
+// NewEventBus creates a new event bus
+func NewEventBus(rs *RedisService) *EventBus {
+	useStream := strings.EqualFold(strings.TrimSpace(os.Getenv("EVENT_BUS_BACKEND")), "redis")
+	if useStream && !rs.IsAvailable() {
+		log.Printf("Event bus: Redis unavailable, using in-process delivery")
+		useStream = false
+	}
+	return &EventBus{
+		redisService: rs,
+		useStream:    useStream,
+		consumerID:   uuid.New().String(),
+		subscribers:  make(map[string][]eventSubscription),
+		queue:        make(chan *models.DomainEvent, eventBusBuffer),
+	}
+}
+
+// Backend returns "redis" or "memory"
+func (eb *EventBus) Backend() string {
+	if eb.useStream {
+		return "redis"
+	}
+	return "memory"
+}
+
+// Subscribe registers a named handler for an event type. Subscribe before Start.
+func (eb *EventBus) Subscribe(eventType, name string, handler EventHandler) {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+	eb.subscribers[eventType] = append(eb.subscribers[eventType], eventSubscription{name: name, handler: handler})
+}
+
+// ErrEventBusFull is returned when the in-process queue cannot take another event
+var ErrEventBusFull = errors.New("event bus queue is full")
+
+// Publish queues an event for delivery without blocking the caller. It fails with
+// ErrEventBusFull when the in-process queue is full; services publish through the
+// Outbox, which retries. A nil bus is a no-op.
+func (eb *EventBus) Publish(event *models.DomainEvent) error {
+	if eb == nil {
+		return nil
+	}
+	if event.ID == "" {
+		event.ID = uuid.New().String()
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = clock.Now()
+	}
+
+	if eb.useStream {
+		err := eb.redisService.AppendToStream(eventStreamKey, event, eventStreamMaxLen)
+		if err == nil {
+			return nil
+		}
+		log.Printf("Event bus: stream append failed, delivering %s in-process: %v", event.Type, err)
+	}
+
+	select {
+	case eb.queue <- event:
+		return nil
+	default:
+		return ErrEventBusFull
+	}
+}
+
+// Start delivers events to subscribers until ctx is cancelled
+func (eb *EventBus) Start(ctx context.Context) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event := <-eb.queue:
+				eb.dispatch(ctx, event)
+			}
+		}
+	}()
+
+	if eb.useStream {
+		go eb.consumeStream(ctx)
+	}
+}
+
+// consumeStream reads the shared stream as part of the consumer group and acknowledges handled events
+func (eb *EventBus) consumeStream(ctx context.Context) {
+	if err := eb.redisService.EnsureStreamGroup(eventStreamKey, eventStreamGroup); err != nil {
+		log.Printf("Event bus: consumer group setup failed: %v", err)
+	}
+	for ctx.Err() == nil {
+		entries, err := eb.redisService.ReadStreamGroup(eventStreamKey, eventStreamGroup, eb.consumerID, 50, 5*time.Second)
+		if err != nil {
+			log.Printf("Event bus: stream read failed: %v", err)
+			time.Sleep(time.Second)
+			continue
+		}
+		for _, entry := range entries {
+			var event models.DomainEvent
+			if err := json.Unmarshal(entry.Payload, &event); err != nil {
+				log.Printf("Event bus: skipping malformed stream entry %s: %v", entry.ID, err)
+			} else {
+				eb.dispatch(ctx, &event)
+			}
+			if err := eb.redisService.AckStream(eventStreamKey, eventStreamGroup, entry.ID); err != nil {
+				log.Printf("Event bus: ack of %s failed: %v", entry.ID, err)
+			}
+		}
+	}
+}
+
+// dispatch runs every subscriber of an event, logging failures and recovering panics
+func (eb *EventBus) dispatch(ctx context.Context, event *models.DomainEvent) {
+	eb.mu.RLock()
+	subs := eb.subscribers[event.Type]
+	eb.mu.RUnlock()
+
+	for _, sub := range subs {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					log.Printf("Event bus: subscriber %s panicked on %s: %v", sub.name, event.Type, r)
+				}
+			}()
+			if err := sub.handler(ctx, event); err != nil {
+				log.Printf("Event bus: subscriber %s failed on %s %s: %v", sub.name, event.Type, event.ID, err)
+			}
+		}()
+	}
+}
@@ -0,0 +1,196 @@
+// Parsers for the ICS and JSON event feeds read by the event importer
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"unalone-backend/internal/models"
+)
+
+// parseICSFeed reads the VEVENTs of an iCalendar document. Only the first occurrence of a
+// recurring event is read; feeds such as Meetup's list each occurrence as its own VEVENT.
+func parseICSFeed(body []byte) ([]*models.ExternalEvent, error) {
+	lines := unfoldICSLines(string(body))
+	if len(lines) == 0 || !strings.EqualFold(strings.TrimSpace(lines[0]), "BEGIN:VCALENDAR") {
+		return nil, errors.New("feed is not an iCalendar document")
+	}
+
+	var events []*models.ExternalEvent
+	var current *models.ExternalEvent
+	for _, line := range lines {
+		name, params, value, ok := splitICSLine(line)
+		if !ok {
+			continue
+		}
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			current = &models.ExternalEvent{}
+		case name == "END" && strings.EqualFold(value, "VEVENT"):
+			if current != nil {
+				events = append(events, current)
+			}
+			current = nil
+		case current == nil:
+			continue
+		case name == "UID":
+			current.SourceID = strings.TrimSpace(value)
+		case name == "SUMMARY":
+			current.Title = unescapeICSText(value)
+		case name == "DESCRIPTION":
+			current.Description = unescapeICSText(value)
+		case name == "URL":
+			current.URL = strings.TrimSpace(value)
+		case name == "DTSTART":
+			current.Start = parseICSTime(value, params)
+		case name == "DTEND":
+			current.End = parseICSTime(value, params)
+		case name == "STATUS":
+			current.Cancelled = strings.EqualFold(strings.TrimSpace(value), "CANCELLED")
+		case name == "GEO":
+			if lat, lng, ok := parseICSGeo(value); ok {
+				current.Latitude, current.Longitude = &lat, &lng
+			}
+		case name == "LOCATION":
+			// Usually "Venue, street, city"; the first part names the venue
+			parts := strings.SplitN(unescapeICSText(value), ",", 2)
+			current.Venue = strings.TrimSpace(parts[0])
+			if len(parts) == 2 {
+				current.Address = strings.TrimSpace(parts[1])
+			}
+		}
+	}
+	return events, nil
+}
+
+// unfoldICSLines joins continuation lines, which start with a space or tab (RFC 5545 3.1)
+func unfoldICSLines(doc string) []string {
+	doc = strings.ReplaceAll(doc, "\r\n", "\n")
+	var lines []string
+	for _, line := range strings.Split(doc, "\n") {
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// splitICSLine splits "NAME;PARAM=x:VALUE" into its upper-cased name, parameters and value.
+// Colons inside quoted parameter values do not end the name.
+func splitICSLine(line string) (string, map[string]string, string, bool) {
+	quoted := false
+	colon := -1
+	for i, r := range line {
+		if r == '"' {
+			quoted = !quoted
+		} else if r == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon <= 0 {
+		return "", nil, "", false
+	}
+	head := strings.Split(line[:colon], ";")
+	params := make(map[string]string, len(head)-1)
+	for _, param := range head[1:] {
+		if key, value, ok := strings.Cut(param, "="); ok {
+			params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+		}
+	}
+	return strings.ToUpper(head[0]), params, line[colon+1:], true
+}
+
+// parseICSTime reads UTC, zoned (TZID) and floating date-times, and all-day dates.
+// Floating times and unknown zones are read as UTC.
+func parseICSTime(value string, params map[string]string) *time.Time {
+	value = strings.TrimSpace(value)
+	loc := time.UTC
+	if tzid := params["TZID"]; tzid != "" {
+		if zone, err := time.LoadLocation(tzid); err == nil {
+			loc = zone
+		}
+	}
+	var t time.Time
+	var err error
+	switch {
+	case strings.HasSuffix(value, "Z"):
+		t, err = time.Parse(icsTimeFormat, value)
+	case len(value) == len("20060102"):
+		t, err = time.ParseInLocation("20060102", value, loc)
+	default:
+		t, err = time.ParseInLocation("20060102T150405", value, loc)
+	}
+	if err != nil {
+		return nil
+	}
+	t = t.UTC()
+	return &t
+}
+
+// parseICSGeo reads "latitude;longitude"
+func parseICSGeo(value string) (float64, float64, bool) {
+	latText, lngText, ok := strings.Cut(value, ";")
+	if !ok {
+		return 0, 0, false
+	}
+	lat, err := strconv.ParseFloat(strings.TrimSpace(latText), 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	lng, err := strconv.ParseFloat(strings.TrimSpace(lngText), 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return lat, lng, true
+}
+
+// unescapeICSText reverses the TEXT escaping applied by escapeICSText
+func unescapeICSText(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' || i+1 == len(value) {
+			b.WriteByte(value[i])
+			continue
+		}
+		i++
+		switch value[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(value[i])
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// parseJSONFeed reads either {"events": [...]} or a bare array of events, with RFC 3339 times:
+//
+//	{"id": "...", "title": "...", "description": "...", "url": "...", "start": "...", "end": "...",
+//	 "latitude": 12.97, "longitude": 77.59, "venue": "...", "address": "...", "city": "...",
+//	 "country": "...", "cancelled": false}
+func parseJSONFeed(body []byte) ([]*models.ExternalEvent, error) {
+	body = bytes.TrimSpace(body)
+	var events []*models.ExternalEvent
+	if bytes.HasPrefix(body, []byte("[")) {
+		if err := json.Unmarshal(body, &events); err != nil {
+			return nil, errors.New("invalid JSON feed: " + err.Error())
+		}
+		return events, nil
+	}
+	var wrapped struct {
+		Events []*models.ExternalEvent `json:"events"`
+	}
+	if err := json.Unmarshal(body, &wrapped); err != nil {
+		return nil, errors.New("invalid JSON feed: " + err.Error())
+	}
+	return wrapped.Events, nil
+}
@@ -0,0 +1,286 @@
+// Event import: seeds hotspots from public ICS and JSON event feeds
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"unalone-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// importMaxFeedBytes caps how much of a feed is read
+	importMaxFeedBytes = 5 << 20
+	// importHorizon is how far ahead events are imported; later ones are picked up by later runs
+	importHorizon      = 60 * 24 * time.Hour
+	importFetchTimeout = 20 * time.Second
+	importUserAgent    = "UnaloneEventImport/1.0"
+)
+
+// ErrImportFeedNotFound is returned for an unknown feed ID
+var ErrImportFeedNotFound = errors.New("import feed not found")
+
+// EventImportService polls registered feeds and turns their events into hotspots
+type EventImportService struct {
+	firestoreService *FirestoreService
+	hotspotService   *HotspotService
+	categoryService  *CategoryService
+	httpClient       *http.Client
+}
+
+// NewEventImportService creates a new event import service
+func NewEventImportService(fs *FirestoreService, hs *HotspotService, cs *CategoryService) *EventImportService {
+	return &EventImportService{
+		firestoreService: fs,
+		hotspotService:   hs,
+		categoryService:  cs,
+		httpClient:       &http.Client{Timeout: importFetchTimeout},
+	}
+}
+
+// ListFeeds returns every registered feed with its last run
+func (eis *EventImportService) ListFeeds() ([]*models.ImportFeed, error) {
+	if !eis.isTestMode() {
+		// TODO: Implement Firestore query of import_feeds
+		return nil, errors.New("firestore implementation needed")
+	}
+	mockImportFeedsMu.Lock()
+	defer mockImportFeedsMu.Unlock()
+	feeds := make([]*models.ImportFeed, 0, len(mockImportFeeds))
+	for _, feed := range mockImportFeeds {
+		copied := *feed
+		feeds = append(feeds, &copied)
+	}
+	sort.Slice(feeds, func(i, j int) bool { return feeds[i].CreatedAt.Before(feeds[j].CreatedAt) })
+	return feeds, nil
+}
+
+// AddFeed registers a feed; it is first imported on the next scheduled run or a manual run
+func (eis *EventImportService) AddFeed(adminID string, req *models.CreateImportFeedRequest) (*models.ImportFeed, error) {
+	if !strings.HasPrefix(req.URL, "https://") && !strings.HasPrefix(req.URL, "http://") {
+		return nil, errors.New("feed URL must be http or https")
+	}
+	category := req.Category
+	if category == "" {
+		category = models.CategoryEvent
+	}
+	if err := eis.categoryService.ValidateCategory(category, ""); err != nil {
+		return nil, err
+	}
+
+	feed := &models.ImportFeed{
+		ID:        uuid.New().String(),
+		Name:      strings.TrimSpace(req.Name),
+		Provider:  strings.ToLower(strings.TrimSpace(req.Provider)),
+		URL:       req.URL,
+		Format:    req.Format,
+		Category:  category,
+		City:      strings.TrimSpace(req.City),
+		Country:   strings.TrimSpace(req.Country),
+		CreatedBy: adminID,
+		CreatedAt: clock.Now(),
+	}
+
+	if !eis.isTestMode() {
+		// TODO: Implement Firestore storage in import_feeds
+		return nil, errors.New("firestore implementation needed")
+	}
+	mockImportFeedsMu.Lock()
+	mockImportFeeds[feed.ID] = feed
+	mockImportFeedsMu.Unlock()
+	copied := *feed
+	return &copied, nil
+}
+
+// DeleteFeed stops importing from a feed. Hotspots it created stay until they end.
+func (eis *EventImportService) DeleteFeed(feedID string) error {
+	if !eis.isTestMode() {
+		// TODO: Implement Firestore delete of import_feeds/{feedID}
+		return errors.New("firestore implementation needed")
+	}
+	mockImportFeedsMu.Lock()
+	defer mockImportFeedsMu.Unlock()
+	if _, ok := mockImportFeeds[feedID]; !ok {
+		return ErrImportFeedNotFound
+	}
+	delete(mockImportFeeds, feedID)
+	return nil
+}
+
+// RunAll imports every registered feed, continuing past feeds that fail
+func (eis *EventImportService) RunAll(ctx context.Context) error {
+	feeds, err := eis.ListFeeds()
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for _, feed := range feeds {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if run := eis.runFeed(ctx, feed); run.Error != "" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d import feeds failed", failed, len(feeds))
+	}
+	return nil
+}
+
+// RunFeed imports one feed now and returns the run summary
+func (eis *EventImportService) RunFeed(ctx context.Context, feedID string) (*models.ImportRun, error) {
+	feed, err := eis.getFeed(feedID)
+	if err != nil {
+		return nil, err
+	}
+	return eis.runFeed(ctx, feed), nil
+}
+
+// runFeed fetches, parses and upserts one feed, recording the summary on the feed
+func (eis *EventImportService) runFeed(ctx context.Context, feed *models.ImportFeed) *models.ImportRun {
+	run := &models.ImportRun{FeedID: feed.ID, StartedAt: clock.Now()}
+	defer func() {
+		run.FinishedAt = clock.Now()
+		eis.saveRun(feed.ID, run)
+		log.Printf("[import] feed=%s fetched=%d created=%d updated=%d cancelled=%d skipped=%d error=%q",
+			feed.ID, run.Fetched, run.Created, run.Updated, run.Cancelled, run.Skipped, run.Error)
+	}()
+
+	body, err := eis.fetch(ctx, feed.URL)
+	if err != nil {
+		run.Error = err.Error()
+		return run
+	}
+	var events []*models.ExternalEvent
+	if feed.Format == models.ImportFormatJSON {
+		events, err = parseJSONFeed(body)
+	} else {
+		events, err = parseICSFeed(body)
+	}
+	if err != nil {
+		run.Error = err.Error()
+		return run
+	}
+
+	run.Fetched = len(events)
+	now := clock.Now()
+	for _, event := range events {
+		if !importable(event, now) {
+			run.Skipped++
+			continue
+		}
+		_, outcome, err := eis.hotspotService.UpsertExternalHotspot(feed, event)
+		if err != nil {
+			// One bad event should not stop the rest of the feed
+			log.Printf("[import] feed=%s event=%s: %v", feed.ID, event.SourceID, err)
+			run.Skipped++
+			continue
+		}
+		switch outcome {
+		case importCreated:
+			run.Created++
+		case importUpdated:
+			run.Updated++
+		case importCancelled:
+			run.Cancelled++
+		default:
+			run.Unchanged++
+		}
+	}
+	return run
+}
+
+// importable reports whether an event can become a hotspot: it needs an ID, a usable title,
+// a start time and coordinates, and must not have ended or be beyond the import horizon
+func importable(event *models.ExternalEvent, now time.Time) bool {
+	if event.SourceID == "" || len([]rune(strings.TrimSpace(event.Title))) < 3 || event.Start == nil {
+		return false
+	}
+	if event.Latitude == nil || event.Longitude == nil ||
+		*event.Latitude < -90 || *event.Latitude > 90 || *event.Longitude < -180 || *event.Longitude > 180 {
+		return false
+	}
+	if event.End != nil && event.End.Before(*event.Start) {
+		event.End = nil
+	}
+	ends := event.Start.Add(icsDefaultDuration)
+	if event.End != nil {
+		ends = *event.End
+	}
+	return ends.After(now) && event.Start.Before(now.Add(importHorizon))
+}
+
+// fetch downloads a feed, reading at most importMaxFeedBytes
+func (eis *EventImportService) fetch(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", importUserAgent)
+	resp, err := eis.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("feed returned HTTP %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, importMaxFeedBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > importMaxFeedBytes {
+		return nil, errors.New("feed is larger than 5 MB")
+	}
+	return body, nil
+}
+
+func (eis *EventImportService) getFeed(feedID string) (*models.ImportFeed, error) {
+	if !eis.isTestMode() {
+		// TODO: Implement Firestore read of import_feeds/{feedID}
+		return nil, errors.New("firestore implementation needed")
+	}
+	mockImportFeedsMu.Lock()
+	defer mockImportFeedsMu.Unlock()
+	feed, ok := mockImportFeeds[feedID]
+	if !ok {
+		return nil, ErrImportFeedNotFound
+	}
+	copied := *feed
+	return &copied, nil
+}
+
+func (eis *EventImportService) saveRun(feedID string, run *models.ImportRun) {
+	if !eis.isTestMode() {
+		// TODO: Implement Firestore update of import_feeds/{feedID}.last_run
+		return
+	}
+	mockImportFeedsMu.Lock()
+	defer mockImportFeedsMu.Unlock()
+	if feed, ok := mockImportFeeds[feedID]; ok {
+		copied := *run
+		feed.LastRun = &copied
+	}
+}
+
+func (eis *EventImportService) isTestMode() bool {
+	return eis.firestoreService.client == nil
+}
+
+// === Mock storage in-memory for development/test ===
+
+var (
+	mockImportFeedsMu sync.Mutex
+	mockImportFeeds   = make(map[string]*models.ImportFeed) // feedID -> feed
+)
@@ -0,0 +1,69 @@
+// Event service fanning out realtime events to connected users
+package services
+
+import (
+	"sync"
+
+	"unalone-backend/internal/models"
+)
+
+const userEventBufferSize = 32
+
+// EventService delivers realtime events to each user's open WebSocket connections.
+// Subscribers live in process memory, so events only reach connections on this instance.
+type EventService struct {
+	mu          sync.RWMutex
+	subscribers map[string]map[chan *models.UserEvent]struct{} // userID -> connection channels
+}
+
+// NewEventService creates a new event service
+func NewEventService() *EventService {
+	return &EventService{
+		subscribers: make(map[string]map[chan *models.UserEvent]struct{}),
+	}
+}
+
+// Subscribe registers a connection for a user and returns its event channel and an unsubscribe func
+func (es *EventService) Subscribe(userID string) (<-chan *models.UserEvent, func()) {
+	ch := make(chan *models.UserEvent, userEventBufferSize)
+
+	es.mu.Lock()
+	if es.subscribers[userID] == nil {
+		es.subscribers[userID] = make(map[chan *models.UserEvent]struct{})
+	}
+	es.subscribers[userID][ch] = struct{}{}
+	es.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			es.mu.Lock()
+			defer es.mu.Unlock()
+			if subs, ok := es.subscribers[userID]; ok {
+				delete(subs, ch)
+				if len(subs) == 0 {
+					delete(es.subscribers, userID)
+				}
+			}
+			close(ch)
+		})
+	}
+	return ch, unsubscribe
+}
+
+// Publish sends an event to every connection of a user; slow connections drop events
+func (es *EventService) Publish(userID, eventType string, data interface{}) {
+	if es == nil {
+		return
+	}
+	event := &models.UserEvent{Type: eventType, Data: data, CreatedAt: clock.Now()}
+
+	es.mu.RLock()
+	defer es.mu.RUnlock()
+	for ch := range es.subscribers[userID] {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
@@ -0,0 +1,226 @@
+// Experiment service for deterministic A/B bucketing and exposure logging
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"sort"
+	"sync"
+
+	"unalone-backend/internal/models"
+)
+
+// experimentBuckets is the resolution of the traffic split (0.01%)
+const experimentBuckets = 10000
+
+var ErrExperimentNotFound = errors.New("experiment not found")
+
+// ExperimentService assigns users to experiment variants and logs exposures. Assignment is a
+// pure function of the user ID and experiment key, so it needs no storage and is stable across
+// devices and replicas; changing an experiment's variants or weights reshuffles its users.
+type ExperimentService struct {
+	firestoreService *FirestoreService
+}
+
+// NewExperimentService creates a new experiment service
+func NewExperimentService(fs *FirestoreService) *ExperimentService {
+	return &ExperimentService{firestoreService: fs}
+}
+
+// ListExperiments returns every experiment, active or not, by key
+func (es *ExperimentService) ListExperiments() ([]models.Experiment, error) {
+	if !es.isTestMode() {
+		// TODO: Read Firestore collection experiments
+		return nil, errors.New("firestore implementation needed")
+	}
+
+	mockExperimentsMu.Lock()
+	defer mockExperimentsMu.Unlock()
+	list := make([]models.Experiment, 0, len(mockExperiments))
+	for _, experiment := range mockExperiments {
+		list = append(list, *experiment)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Key < list[j].Key })
+	return list, nil
+}
+
+// UpsertExperiment creates or updates an experiment; keys are lowercase slugs
+func (es *ExperimentService) UpsertExperiment(key string, req *models.UpsertExperimentRequest) (*models.Experiment, error) {
+	if !categoryIDPattern.MatchString(key) || len(key) > 50 {
+		return nil, errors.New("experiment key must be a lowercase slug")
+	}
+	seen := make(map[string]bool, len(req.Variants))
+	for _, variant := range req.Variants {
+		if seen[variant.Name] {
+			return nil, errors.New("variant names must be unique")
+		}
+		seen[variant.Name] = true
+	}
+
+	if !es.isTestMode() {
+		// TODO: Upsert Firestore document experiments/{key}
+		return nil, errors.New("firestore implementation needed")
+	}
+
+	mockExperimentsMu.Lock()
+	defer mockExperimentsMu.Unlock()
+
+	now := clock.Now()
+	experiment, exists := mockExperiments[key]
+	if !exists {
+		experiment = &models.Experiment{Key: key, TrafficPercent: 100, IsActive: true, CreatedAt: now}
+	}
+	experiment.Description = req.Description
+	experiment.Variants = append([]models.ExperimentVariant(nil), req.Variants...)
+	if req.TrafficPercent != nil {
+		experiment.TrafficPercent = *req.TrafficPercent
+	}
+	if req.IsActive != nil {
+		experiment.IsActive = *req.IsActive
+	}
+	experiment.UpdatedAt = now
+	mockExperiments[key] = experiment
+
+	result := *experiment
+	return &result, nil
+}
+
+// GetAssignments returns the user's variant of every active experiment they are enrolled in
+func (es *ExperimentService) GetAssignments(userID string) (*models.ExperimentAssignments, error) {
+	experiments, err := es.ListExperiments()
+	if err != nil {
+		return nil, err
+	}
+	assignments := &models.ExperimentAssignments{Variants: make(map[string]string)}
+	for i := range experiments {
+		if variant := AssignVariant(&experiments[i], userID); variant != "" {
+			assignments.Variants[experiments[i].Key] = variant
+		}
+	}
+	return assignments, nil
+}
+
+// Variant returns the user's variant of an experiment for server-side code paths, or "" when the
+// experiment is missing, inactive or the user is not enrolled so the caller keeps its default
+func (es *ExperimentService) Variant(userID, key string) string {
+	experiment, err := es.getExperiment(key)
+	if err != nil {
+		return ""
+	}
+	return AssignVariant(experiment, userID)
+}
+
+// RecordExposure logs the first time a user saw their variant. The variant is recomputed
+// here rather than trusted from the client; later exposures return the first one.
+func (es *ExperimentService) RecordExposure(userID, key string) (*models.ExperimentExposure, error) {
+	experiment, err := es.getExperiment(key)
+	if err != nil {
+		return nil, err
+	}
+	variant := AssignVariant(experiment, userID)
+	if variant == "" {
+		return nil, errors.New("user is not enrolled in this experiment")
+	}
+
+	if !es.isTestMode() {
+		// TODO: Create Firestore document experiments/{key}/exposures/{userID} if missing
+		return nil, errors.New("firestore implementation needed")
+	}
+
+	mockExperimentsMu.Lock()
+	defer mockExperimentsMu.Unlock()
+	exposures, ok := mockExperimentExposures[key]
+	if !ok {
+		exposures = make(map[string]*models.ExperimentExposure)
+		mockExperimentExposures[key] = exposures
+	}
+	exposure, ok := exposures[userID]
+	if !ok {
+		exposure = &models.ExperimentExposure{ExperimentKey: key, UserID: userID, Variant: variant, ExposedAt: clock.Now()}
+		exposures[userID] = exposure
+	}
+	result := *exposure
+	return &result, nil
+}
+
+// GetResults counts exposed users per variant of an experiment
+func (es *ExperimentService) GetResults(key string) (*models.ExperimentResults, error) {
+	experiment, err := es.getExperiment(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if !es.isTestMode() {
+		// TODO: Aggregate Firestore experiments/{key}/exposures by variant
+		return nil, errors.New("firestore implementation needed")
+	}
+
+	results := &models.ExperimentResults{Experiment: *experiment, Exposures: make(map[string]int, len(experiment.Variants))}
+	for _, variant := range experiment.Variants {
+		results.Exposures[variant.Name] = 0
+	}
+	mockExperimentsMu.Lock()
+	defer mockExperimentsMu.Unlock()
+	for _, exposure := range mockExperimentExposures[key] {
+		results.Exposures[exposure.Variant]++
+		results.Total++
+	}
+	return results, nil
+}
+
+// AssignVariant deterministically buckets a user: the hash of user ID and experiment key decides
+// whether they fall inside the traffic share, then picks a variant in proportion to its weight
+func AssignVariant(experiment *models.Experiment, userID string) string {
+	if !experiment.IsActive || userID == "" || len(experiment.Variants) == 0 {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(userID + ":" + experiment.Key))
+	if binary.BigEndian.Uint64(sum[:8])%experimentBuckets >= uint64(experiment.TrafficPercent*experimentBuckets/100) {
+		return ""
+	}
+
+	total := 0
+	for _, variant := range experiment.Variants {
+		total += variant.Weight
+	}
+	if total <= 0 {
+		return ""
+	}
+	point := int(binary.BigEndian.Uint64(sum[8:16]) % uint64(total))
+	for _, variant := range experiment.Variants {
+		if point < variant.Weight {
+			return variant.Name
+		}
+		point -= variant.Weight
+	}
+	return ""
+}
+
+func (es *ExperimentService) getExperiment(key string) (*models.Experiment, error) {
+	if !es.isTestMode() {
+		// TODO: Read Firestore document experiments/{key}
+		return nil, errors.New("firestore implementation needed")
+	}
+
+	mockExperimentsMu.Lock()
+	defer mockExperimentsMu.Unlock()
+	experiment, ok := mockExperiments[key]
+	if !ok {
+		return nil, ErrExperimentNotFound
+	}
+	result := *experiment
+	return &result, nil
+}
+
+func (es *ExperimentService) isTestMode() bool {
+	return es.firestoreService.client == nil
+}
+
+// === Mock storage in-memory for development/test ===
+
+var (
+	mockExperimentsMu       sync.Mutex
+	mockExperiments         = make(map[string]*models.Experiment)                    // key -> experiment
+	mockExperimentExposures = make(map[string]map[string]*models.ExperimentExposure) // key -> userID -> first exposure
+)
@@ -0,0 +1,258 @@
+// Feedback service prompts attendees after a hotspot ends and aggregates their answers
+package services
+
+import (
+	"errors"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"unalone-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// feedbackWindow is how long attendees can answer a feedback prompt after a hotspot ends
+const feedbackWindow = 14 * 24 * time.Hour
+
+// FeedbackService stores post-event feedback and turns safety issues into reports
+type FeedbackService struct {
+	firestoreService *FirestoreService
+	profileService   *ProfileService
+	notifications    *NotificationService
+}
+
+// NewFeedbackService creates a new feedback service
+func NewFeedbackService(fs *FirestoreService, ps *ProfileService, ns *NotificationService) *FeedbackService {
+	return &FeedbackService{firestoreService: fs, profileService: ps, notifications: ns}
+}
+
+// RequestFeedback creates a feedback prompt for every attendee of an ended hotspot except the host
+func (fbs *FeedbackService) RequestFeedback(hotspot *models.Hotspot) {
+	now := clock.Now()
+	requested := make([]string, 0, len(hotspot.Attendees))
+	for _, userID := range hotspot.Attendees {
+		if userID == hotspot.CreatedBy {
+			continue
+		}
+		req := &models.FeedbackRequest{
+			HotspotID:   hotspot.ID,
+			HotspotName: hotspot.Name,
+			UserID:      userID,
+			RequestedAt: now,
+			ExpiresAt:   now.Add(feedbackWindow),
+		}
+		if fbs.isTestMode() {
+			if fbs.saveRequestMock(req) {
+				requested = append(requested, userID)
+			}
+			continue
+		}
+		// TODO: Write Firestore document feedback_requests/{hotspotID}_{userID}
+		log.Printf("Feedback request for hotspot %s not stored: firestore implementation needed", hotspot.ID)
+		return
+	}
+
+	if fbs.notifications != nil {
+		fbs.notifications.NotifyFeedbackRequested(hotspot, requested)
+	}
+}
+
+// ListPendingFeedback returns unanswered, unexpired feedback prompts for a user, newest first
+func (fbs *FeedbackService) ListPendingFeedback(userID string) ([]*models.FeedbackRequest, error) {
+	if !fbs.isTestMode() {
+		// TODO: Query Firestore feedback_requests where user_id == userID and expires_at > now
+		return nil, errors.New("firestore implementation needed")
+	}
+
+	now := clock.Now()
+	mockFeedbackMu.Lock()
+	defer mockFeedbackMu.Unlock()
+	pending := make([]*models.FeedbackRequest, 0)
+	for _, req := range mockFeedbackRequests {
+		if req.UserID == userID && now.Before(req.ExpiresAt) {
+			pending = append(pending, req)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].RequestedAt.After(pending[j].RequestedAt)
+	})
+	return pending, nil
+}
+
+// SubmitFeedback answers a pending prompt. Safety and harassment issues are also filed as a report against the host.
+func (fbs *FeedbackService) SubmitFeedback(userID string, hotspot *models.Hotspot, req *models.SubmitFeedbackRequest) (*models.HotspotFeedback, error) {
+	attended := *req.Attended
+	if !attended && req.Rating != 0 {
+		return nil, errors.New("only attendees can rate a hotspot")
+	}
+
+	feedback := &models.HotspotFeedback{
+		ID:        uuid.New().String(),
+		HotspotID: hotspot.ID,
+		HostID:    hotspot.CreatedBy,
+		UserID:    userID,
+		Attended:  attended,
+		Rating:    req.Rating,
+		Comment:   strings.TrimSpace(req.Comment),
+		Issues:    dedupeStrings(req.Issues),
+		CreatedAt: clock.Now(),
+	}
+
+	if !fbs.isTestMode() {
+		// TODO: Transactionally delete the feedback_requests document and write to the feedback collection
+		return nil, errors.New("firestore implementation needed")
+	}
+	if err := fbs.submitFeedbackMock(feedback); err != nil {
+		return nil, err
+	}
+
+	fbs.reportSafetyIssues(hotspot, feedback)
+	return feedback, nil
+}
+
+// Summary aggregates all feedback submitted for a hotspot
+func (fbs *FeedbackService) Summary(hotspotID string) models.FeedbackSummary {
+	summary := models.FeedbackSummary{IssueCounts: make(map[string]int)}
+	if !fbs.isTestMode() {
+		// TODO: Query Firestore feedback where hotspot_id == hotspotID
+		return summary
+	}
+
+	mockFeedbackMu.Lock()
+	defer mockFeedbackMu.Unlock()
+	ratingTotal, ratings := 0, 0
+	for _, feedback := range mockFeedback {
+		if feedback.HotspotID != hotspotID {
+			continue
+		}
+		summary.Responses++
+		if feedback.Attended {
+			summary.Attended++
+		} else {
+			summary.NoShows++
+		}
+		if feedback.Rating > 0 {
+			ratingTotal += feedback.Rating
+			ratings++
+		}
+		for _, issue := range feedback.Issues {
+			summary.IssueCounts[issue]++
+		}
+	}
+	if ratings > 0 {
+		summary.AverageRating = math.Round(float64(ratingTotal)/float64(ratings)*100) / 100
+	}
+	return summary
+}
+
+// SafetyIssueCount counts feedback submitted since the given time that flagged a safety or harassment issue for a host
+func (fbs *FeedbackService) SafetyIssueCount(hostID string, since time.Time) int {
+	if !fbs.isTestMode() {
+		// TODO: Query Firestore feedback where host_id == hostID and created_at >= since
+		return 0
+	}
+
+	mockFeedbackMu.Lock()
+	defer mockFeedbackMu.Unlock()
+	count := 0
+	for _, feedback := range mockFeedback {
+		if feedback.HostID == hostID && !feedback.CreatedAt.Before(since) && isSafetyFeedback(feedback) {
+			count++
+		}
+	}
+	return count
+}
+
+// reportSafetyIssues files a user report against the host for safety-related feedback (best-effort)
+func (fbs *FeedbackService) reportSafetyIssues(hotspot *models.Hotspot, feedback *models.HotspotFeedback) {
+	if fbs.profileService == nil || !isSafetyFeedback(feedback) {
+		return
+	}
+	reason := "other"
+	if containsString(feedback.Issues, models.FeedbackIssueHarassment) {
+		reason = "harassment"
+	}
+	description := "Post-event feedback for " + hotspot.Name
+	if feedback.Comment != "" {
+		description += ": " + feedback.Comment
+	}
+	if len(description) > 1000 {
+		description = description[:1000]
+	}
+	if err := fbs.profileService.ReportUser(feedback.UserID, &models.UserReportRequest{
+		ReportedUserID: hotspot.CreatedBy,
+		Reason:         reason,
+		Description:    description,
+	}); err != nil {
+		log.Printf("Failed to file report from feedback %s: %v", feedback.ID, err)
+	}
+}
+
+// isSafetyFeedback reports whether feedback flagged a safety or harassment issue
+func isSafetyFeedback(feedback *models.HotspotFeedback) bool {
+	return containsString(feedback.Issues, models.FeedbackIssueSafety) ||
+		containsString(feedback.Issues, models.FeedbackIssueHarassment)
+}
+
+// dedupeStrings returns values with duplicates removed, keeping the first occurrence
+func dedupeStrings(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool, len(values))
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// isTestMode checks if we're running with mocked database
+func (fbs *FeedbackService) isTestMode() bool {
+	return fbs.firestoreService.client == nil
+}
+
+// Mock storage for testing
+var (
+	mockFeedbackMu       sync.Mutex
+	mockFeedbackRequests = make(map[string]*models.FeedbackRequest) // hotspotID:userID -> pending prompt
+	mockFeedback         = make(map[string]*models.HotspotFeedback) // hotspotID:userID -> answer
+)
+
+// saveRequestMock stores a prompt unless one was already sent or answered; it reports whether it was new
+func (fbs *FeedbackService) saveRequestMock(req *models.FeedbackRequest) bool {
+	key := req.HotspotID + ":" + req.UserID
+	mockFeedbackMu.Lock()
+	defer mockFeedbackMu.Unlock()
+	if _, ok := mockFeedbackRequests[key]; ok {
+		return false
+	}
+	if _, ok := mockFeedback[key]; ok {
+		return false
+	}
+	mockFeedbackRequests[key] = req
+	return true
+}
+
+func (fbs *FeedbackService) submitFeedbackMock(feedback *models.HotspotFeedback) error {
+	key := feedback.HotspotID + ":" + feedback.UserID
+	mockFeedbackMu.Lock()
+	defer mockFeedbackMu.Unlock()
+	if _, ok := mockFeedback[key]; ok {
+		return errors.New("feedback already submitted")
+	}
+	req, ok := mockFeedbackRequests[key]
+	if !ok || clock.Now().After(req.ExpiresAt) {
+		return errors.New("no pending feedback request for this hotspot")
+	}
+	delete(mockFeedbackRequests, key)
+	mockFeedback[key] = feedback
+	return nil
+}
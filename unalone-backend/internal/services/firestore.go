@@ -10,12 +10,15 @@ import (
 	"cloud.google.com/go/firestore"
 	firebase "firebase.google.com/go/v4"
 	"google.golang.org/api/option"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // FirestoreService handles Firestore database operations
 type FirestoreService struct {
 	client *firestore.Client
 	ctx    context.Context
+	guard  *FirestoreGuard // Counts reads and writes and enforces query limits; see SetGuard
 }
 
 // NewFirestoreService creates a new Firestore service instance
@@ -29,22 +32,23 @@ func NewFirestoreService(ctx context.Context) (*FirestoreService, error) {
 		}, nil
 	}
 
-	// Initialize Firebase app for production
-	var app *firebase.App
-	var err error
-
-	// Prefer explicit credentials via env
-	if jsonCreds := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS_JSON"); strings.TrimSpace(jsonCreds) != "" {
-		opt := option.WithCredentialsJSON([]byte(jsonCreds))
-		app, err = firebase.NewApp(ctx, nil, opt)
-	} else if credentialsFile := getServiceAccountPath(); credentialsFile != "" {
-		opt := option.WithCredentialsFile(credentialsFile)
-		app, err = firebase.NewApp(ctx, nil, opt)
-	} else {
-		// Default credentials (useful for local dev with gcloud auth)
-		app, err = firebase.NewApp(ctx, nil)
+	// Local emulator: no credentials needed, the client library reads FIRESTORE_EMULATOR_HOST itself
+	if host := emulatorHost(); host != "" {
+		projectID := emulatorProjectID()
+		client, err := firestore.NewClient(ctx, projectID)
+		if err != nil {
+			log.Printf("Error connecting to Firestore emulator at %s: %v", host, err)
+			return nil, err
+		}
+		log.Printf("Using Firestore emulator at %s (project %s)", host, projectID)
+		return &FirestoreService{
+			client: client,
+			ctx:    ctx,
+		}, nil
 	}
 
+	// Initialize Firebase app for production
+	app, err := firebase.NewApp(ctx, nil, credentialOptions()...)
 	if err != nil {
 		log.Printf("Error initializing Firebase app: %v", err)
 		return nil, err
@@ -69,6 +73,10 @@ func isTestMode() bool {
 	if mode := os.Getenv("APP_MODE"); strings.ToLower(mode) == "test" || strings.ToLower(mode) == "mock" {
 		return true
 	}
+	// The emulator runs the real Firestore code paths without credentials
+	if emulatorHost() != "" {
+		return false
+	}
 	// If credentials are provided, not test mode
 	if strings.TrimSpace(os.Getenv("GOOGLE_APPLICATION_CREDENTIALS_JSON")) != "" {
 		return false
@@ -80,6 +88,18 @@ func isTestMode() bool {
 	return true
 }
 
+// credentialOptions returns the Google Cloud credentials from env: inline JSON, a key file,
+// or none to use default credentials (useful for local dev with gcloud auth)
+func credentialOptions() []option.ClientOption {
+	if jsonCreds := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS_JSON"); strings.TrimSpace(jsonCreds) != "" {
+		return []option.ClientOption{option.WithCredentialsJSON([]byte(jsonCreds))}
+	}
+	if credentialsFile := getServiceAccountPath(); credentialsFile != "" {
+		return []option.ClientOption{option.WithCredentialsFile(credentialsFile)}
+	}
+	return nil
+}
+
 // getServiceAccountPath returns the path to service account key file
 func getServiceAccountPath() string {
 	// Allow overriding via env var
@@ -89,6 +109,33 @@ func getServiceAccountPath() string {
 	return ""
 }
 
+// emulatorHost returns FIRESTORE_EMULATOR_HOST (host:port) when a local emulator is configured
+func emulatorHost() string {
+	return strings.TrimSpace(os.Getenv("FIRESTORE_EMULATOR_HOST"))
+}
+
+// emulatorProjectID names the emulator project; any ID works as long as every process uses the same one
+func emulatorProjectID() string {
+	for _, key := range []string{"FIRESTORE_PROJECT_ID", "GOOGLE_CLOUD_PROJECT"} {
+		if id := strings.TrimSpace(os.Getenv(key)); id != "" {
+			return id
+		}
+	}
+	return "unalone-local"
+}
+
+// Ping checks that Firestore answers; mock storage always does
+func (fs *FirestoreService) Ping(ctx context.Context) error {
+	if fs.client == nil {
+		return nil
+	}
+	_, err := fs.GetDoc(ctx, fs.client.Collection("health").Doc("ping"))
+	if status.Code(err) == codes.NotFound {
+		return nil
+	}
+	return err
+}
+
 // Close closes the Firestore client
 func (fs *FirestoreService) Close() error {
 	// In test mode, client may be nil; safely no-op
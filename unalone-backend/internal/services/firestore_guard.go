@@ -0,0 +1,460 @@
+// Firestore guardrails: mandatory query limits, per-request read budgets, and daily read and
+// write counters with budget alerts
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"cloud.google.com/go/firestore"
+
+	"unalone-backend/internal/models"
+)
+
+const (
+	// defaultMaxReadsPerRequest caps the documents one API request may read unless
+	// FIRESTORE_MAX_READS_PER_REQUEST says otherwise
+	defaultMaxReadsPerRequest = 1000
+	// defaultMaxQueryLimit is the largest limit a collection query may ask for unless
+	// FIRESTORE_MAX_QUERY_LIMIT says otherwise
+	defaultMaxQueryLimit = 500
+	// firestoreBudgetAlertRatio is the share of a daily budget that raises the first alert
+	firestoreBudgetAlertRatio = 0.8
+	// firestoreUsageFlushInterval is how often counted reads and writes are added to the shared counters
+	firestoreUsageFlushInterval = 10 * time.Second
+)
+
+var (
+	// ErrQueryLimitRequired is returned for collection queries without a limit or above the max query limit
+	ErrQueryLimitRequired = errors.New("collection queries need a limit")
+	// ErrReadBudgetExceeded is returned once a request has read as many documents as it may
+	ErrReadBudgetExceeded = errors.New("this request reads too much data, narrow it down and try again")
+)
+
+type readBudgetKey struct{}
+
+// ReadBudget caps the Firestore documents read on behalf of one request
+type ReadBudget struct {
+	max  int64
+	used atomic.Int64
+}
+
+// WithReadBudget returns a context whose Firestore reads may total at most max documents.
+// Contexts without a budget, such as those of scheduled jobs, only have their queries limited.
+func WithReadBudget(ctx context.Context, max int) (context.Context, *ReadBudget) {
+	budget := &ReadBudget{max: int64(max)}
+	return context.WithValue(ctx, readBudgetKey{}, budget), budget
+}
+
+// Used returns how many documents the request has read
+func (b *ReadBudget) Used() int64 {
+	return b.used.Load()
+}
+
+// Max returns how many documents the request may read
+func (b *ReadBudget) Max() int64 {
+	return b.max
+}
+
+// Approaching reports whether the request has read at least 80% of its budget
+func (b *ReadBudget) Approaching() bool {
+	return float64(b.used.Load()) >= float64(b.max)*firestoreBudgetAlertRatio
+}
+
+// remainingReads returns how many more documents the budget in ctx allows, or -1 when there is none
+func remainingReads(ctx context.Context) int64 {
+	budget, _ := ctx.Value(readBudgetKey{}).(*ReadBudget)
+	if budget == nil {
+		return -1
+	}
+	if left := budget.max - budget.used.Load(); left > 0 {
+		return left
+	}
+	return 0
+}
+
+// chargeReads takes n documents from the budget in ctx, failing when that overdraws it
+func chargeReads(ctx context.Context, n int) error {
+	budget, _ := ctx.Value(readBudgetKey{}).(*ReadBudget)
+	if budget == nil {
+		return nil
+	}
+	if budget.used.Add(int64(n)) > budget.max {
+		return ErrReadBudgetExceeded
+	}
+	return nil
+}
+
+// FirestoreGuard keeps Firestore billing bounded: every collection query needs a limit, each
+// request has a read budget, and documents read and written are counted per UTC day against
+// optional daily budgets that raise alerts as they are approached. Counts live in Redis so
+// every instance shares them; without Redis each instance counts alone.
+type FirestoreGuard struct {
+	redisService       *RedisService
+	maxReadsPerRequest int
+	maxQueryLimit      int
+	readBudget         int64 // Documents per UTC day, 0 for none
+	writeBudget        int64
+
+	mu      sync.Mutex
+	pending map[string]*firestoreCounters // day -> counts not yet added to Redis
+	local   map[string]*firestoreCounters // day -> counts when Redis is unavailable
+	alerted map[string]bool               // day:reads|writes:state already alerted
+}
+
+type firestoreCounters struct {
+	reads, writes, rejected int64
+}
+
+func (c *firestoreCounters) add(delta firestoreCounters) {
+	c.reads += delta.reads
+	c.writes += delta.writes
+	c.rejected += delta.rejected
+}
+
+// NewFirestoreGuard creates the guard. FIRESTORE_MAX_READS_PER_REQUEST (default 1000) and
+// FIRESTORE_MAX_QUERY_LIMIT (default 500) bound single requests and queries;
+// FIRESTORE_DAILY_READ_BUDGET and FIRESTORE_DAILY_WRITE_BUDGET, in documents, raise alerts
+// at 80% and 100% of a day's reads or writes; unset means no daily budget.
+func NewFirestoreGuard(rs *RedisService) *FirestoreGuard {
+	return &FirestoreGuard{
+		redisService:       rs,
+		maxReadsPerRequest: envCount("FIRESTORE_MAX_READS_PER_REQUEST", defaultMaxReadsPerRequest, 1),
+		maxQueryLimit:      envCount("FIRESTORE_MAX_QUERY_LIMIT", defaultMaxQueryLimit, 1),
+		readBudget:         int64(envCount("FIRESTORE_DAILY_READ_BUDGET", 0, 0)),
+		writeBudget:        int64(envCount("FIRESTORE_DAILY_WRITE_BUDGET", 0, 0)),
+		pending:            make(map[string]*firestoreCounters),
+		local:              make(map[string]*firestoreCounters),
+		alerted:            make(map[string]bool),
+	}
+}
+
+// envCount reads a whole number of at least min, falling back when it is unset or invalid
+func envCount(key string, fallback, min int) int {
+	raw := strings.TrimSpace(os.Getenv(key))
+	if raw == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < min {
+		log.Printf("Ignoring invalid %s=%q", key, raw)
+		return fallback
+	}
+	return n
+}
+
+// MaxReadsPerRequest returns how many documents one API request may read
+func (g *FirestoreGuard) MaxReadsPerRequest() int {
+	if g == nil {
+		return defaultMaxReadsPerRequest
+	}
+	return g.maxReadsPerRequest
+}
+
+// MaxQueryLimit returns the largest limit a collection query may ask for
+func (g *FirestoreGuard) MaxQueryLimit() int {
+	if g == nil {
+		return defaultMaxQueryLimit
+	}
+	return g.maxQueryLimit
+}
+
+// count adds reads, writes or rejections to today's counters
+func (g *FirestoreGuard) count(delta firestoreCounters) {
+	if g == nil {
+		return
+	}
+	day := quotaDay(time.Now())
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	counters := g.pending[day]
+	if counters == nil {
+		counters = &firestoreCounters{}
+		g.pending[day] = counters
+	}
+	counters.add(delta)
+}
+
+// Start adds counted reads and writes to the shared counters every 10 seconds, and once more
+// when ctx is cancelled
+func (g *FirestoreGuard) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(firestoreUsageFlushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				g.flush()
+				return
+			case <-ticker.C:
+				g.flush()
+			}
+		}
+	}()
+}
+
+// flush moves pending counts to Redis, or to this instance's counters when Redis is unavailable
+func (g *FirestoreGuard) flush() {
+	g.mu.Lock()
+	pending := g.pending
+	g.pending = make(map[string]*firestoreCounters)
+	if !g.redisService.IsAvailable() {
+		for day, delta := range pending {
+			g.localFor(day).add(*delta)
+		}
+		g.mu.Unlock()
+		return
+	}
+	g.mu.Unlock()
+
+	for day, delta := range pending {
+		deltas := make(map[string]int64)
+		for field, value := range map[string]int64{"reads": delta.reads, "writes": delta.writes, "rejected": delta.rejected} {
+			if value != 0 {
+				deltas[field] = value
+			}
+		}
+		if err := g.redisService.HashIncrementMany(firestoreUsageKey(day), deltas, (MaxUsageDays+1)*24*time.Hour); err != nil {
+			log.Printf("Firestore usage write failed: %v", err)
+			g.mu.Lock()
+			g.localFor(day).add(*delta)
+			g.mu.Unlock()
+		}
+	}
+}
+
+// localFor returns this instance's counters for a day; callers hold g.mu
+func (g *FirestoreGuard) localFor(day string) *firestoreCounters {
+	counters := g.local[day]
+	if counters == nil {
+		counters = &firestoreCounters{}
+		g.local[day] = counters
+	}
+	return counters
+}
+
+// read returns a day's counts, including this instance's counts not yet flushed
+func (g *FirestoreGuard) read(day string) (firestoreCounters, error) {
+	var counters firestoreCounters
+	if g.redisService.IsAvailable() {
+		values, err := g.redisService.HashGetMany(firestoreUsageKey(day), []string{"reads", "writes", "rejected"})
+		if err != nil {
+			return firestoreCounters{}, err
+		}
+		counters.reads, _ = strconv.ParseInt(values[0], 10, 64)
+		counters.writes, _ = strconv.ParseInt(values[1], 10, 64)
+		counters.rejected, _ = strconv.ParseInt(values[2], 10, 64)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if local := g.local[day]; local != nil {
+		counters.add(*local)
+	}
+	if pending := g.pending[day]; pending != nil {
+		counters.add(*pending)
+	}
+	return counters, nil
+}
+
+// CheckBudgets logs an alert the first time in a UTC day that reads or writes reach 80% and
+// then 100% of their daily budget. Budgets only alert; reads and writes are never refused for them.
+func (g *FirestoreGuard) CheckBudgets(ctx context.Context) error {
+	if g.readBudget == 0 && g.writeBudget == 0 {
+		return nil
+	}
+	day := quotaDay(time.Now())
+	usage, err := g.read(day)
+	if err != nil {
+		return err
+	}
+	for _, check := range []struct {
+		kind         string
+		used, budget int64
+	}{
+		{"reads", usage.reads, g.readBudget},
+		{"writes", usage.writes, g.writeBudget},
+	} {
+		state := firestoreBudgetState(check.used, check.budget)
+		if state == models.FirestoreBudgetOK {
+			continue
+		}
+		key := day + ":" + check.kind + ":" + state
+		g.mu.Lock()
+		alerted := g.alerted[key]
+		g.alerted[key] = true
+		g.mu.Unlock()
+		if !alerted {
+			log.Printf("[firestore] ALERT: daily %s budget %s: %d of %d documents (%d%%)",
+				check.kind, state, check.used, check.budget, int(math.Round(float64(check.used)*100/float64(check.budget))))
+		}
+	}
+	return nil
+}
+
+// firestoreBudgetState tells how close used is to budget
+func firestoreBudgetState(used, budget int64) string {
+	switch {
+	case budget <= 0:
+		return models.FirestoreBudgetOK
+	case used >= budget:
+		return models.FirestoreBudgetExceeded
+	case float64(used) >= float64(budget)*firestoreBudgetAlertRatio:
+		return models.FirestoreBudgetApproaching
+	default:
+		return models.FirestoreBudgetOK
+	}
+}
+
+// Usage reports documents read and written per UTC day for the last days days, today first
+func (g *FirestoreGuard) Usage(days int) (*models.FirestoreUsageReport, error) {
+	if days < 1 || days > MaxUsageDays {
+		return nil, fmt.Errorf("days must be between 1 and %d", MaxUsageDays)
+	}
+
+	report := &models.FirestoreUsageReport{
+		Shared:             g.redisService.IsAvailable(),
+		MaxReadsPerRequest: g.maxReadsPerRequest,
+		MaxQueryLimit:      g.maxQueryLimit,
+		Days:               make([]models.FirestoreDailyUsage, 0, days),
+	}
+	now := time.Now()
+	for i := 0; i < days; i++ {
+		day := quotaDay(now.AddDate(0, 0, -i))
+		usage, err := g.read(day)
+		if err != nil {
+			return nil, err
+		}
+		report.Days = append(report.Days, models.FirestoreDailyUsage{
+			Date:        day,
+			Reads:       usage.reads,
+			Writes:      usage.writes,
+			Rejected:    usage.rejected,
+			ReadBudget:  g.readBudget,
+			WriteBudget: g.writeBudget,
+			ReadState:   firestoreBudgetState(usage.reads, g.readBudget),
+			WriteState:  firestoreBudgetState(usage.writes, g.writeBudget),
+		})
+	}
+	return report, nil
+}
+
+// PurgeExpired drops in-process counters and alert marks older than MaxUsageDays
+func (g *FirestoreGuard) PurgeExpired() {
+	oldest := quotaDay(time.Now().AddDate(0, 0, -MaxUsageDays))
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for day := range g.local {
+		if day < oldest {
+			delete(g.local, day)
+		}
+	}
+	for key := range g.alerted {
+		if key[:len(oldest)] < oldest {
+			delete(g.alerted, key)
+		}
+	}
+}
+
+func firestoreUsageKey(day string) string {
+	return "firestore_usage:" + day
+}
+
+// SetGuard makes the service count its reads and writes and enforce the guard's limits
+func (fs *FirestoreService) SetGuard(g *FirestoreGuard) {
+	fs.guard = g
+}
+
+// Query runs a collection query for at most limit documents, which must be between 1 and the
+// max query limit, charging what it reads to the request budget in ctx
+func (fs *FirestoreService) Query(ctx context.Context, q firestore.Query, limit int) ([]*firestore.DocumentSnapshot, error) {
+	if limit < 1 || limit > fs.guard.MaxQueryLimit() {
+		fs.guard.count(firestoreCounters{rejected: 1})
+		return nil, fmt.Errorf("%w of 1 to %d, got %d", ErrQueryLimitRequired, fs.guard.MaxQueryLimit(), limit)
+	}
+	// Ask for one document more than the budget allows, so going over is noticed without
+	// reading the whole page
+	remaining := remainingReads(ctx)
+	if remaining == 0 {
+		fs.guard.count(firestoreCounters{rejected: 1})
+		return nil, ErrReadBudgetExceeded
+	}
+	if remaining > 0 && int64(limit) > remaining+1 {
+		limit = int(remaining + 1)
+	}
+
+	docs, err := q.Limit(limit).Documents(ctx).GetAll()
+	// A query is billed at least one read even when it matches nothing
+	fs.guard.count(firestoreCounters{reads: int64(max(len(docs), 1))})
+	if err != nil {
+		return nil, err
+	}
+	if err := chargeReads(ctx, len(docs)); err != nil {
+		fs.guard.count(firestoreCounters{rejected: 1})
+		return nil, err
+	}
+	return docs, nil
+}
+
+// Scan runs a query page by page in document ID order, pageSize documents at a time, for
+// jobs that need every match. It stops at the first error, from Firestore or from fn.
+func (fs *FirestoreService) Scan(ctx context.Context, q firestore.Query, pageSize int, fn func(docs []*firestore.DocumentSnapshot) error) error {
+	q = q.OrderBy(firestore.DocumentID, firestore.Asc)
+	var last *firestore.DocumentSnapshot
+	for {
+		page := q
+		if last != nil {
+			page = q.StartAfter(last)
+		}
+		docs, err := fs.Query(ctx, page, pageSize)
+		if err != nil {
+			return err
+		}
+		if len(docs) > 0 {
+			if err := fn(docs); err != nil {
+				return err
+			}
+		}
+		if len(docs) < pageSize {
+			return nil
+		}
+		last = docs[len(docs)-1]
+	}
+}
+
+// GetDoc reads one document, charging it to the request budget in ctx
+func (fs *FirestoreService) GetDoc(ctx context.Context, ref *firestore.DocumentRef) (*firestore.DocumentSnapshot, error) {
+	if err := chargeReads(ctx, 1); err != nil {
+		fs.guard.count(firestoreCounters{rejected: 1})
+		return nil, err
+	}
+	// Missing documents are billed as a read too
+	fs.guard.count(firestoreCounters{reads: 1})
+	return ref.Get(ctx)
+}
+
+// GetDocs reads several documents in one call, in the order of refs, charging them to the
+// request budget in ctx before reading
+func (fs *FirestoreService) GetDocs(ctx context.Context, refs []*firestore.DocumentRef) ([]*firestore.DocumentSnapshot, error) {
+	if err := chargeReads(ctx, len(refs)); err != nil {
+		fs.guard.count(firestoreCounters{rejected: 1})
+		return nil, err
+	}
+	fs.guard.count(firestoreCounters{reads: int64(len(refs))})
+	return fs.client.GetAll(ctx, refs)
+}
+
+// SetDoc writes one document, counting the write
+func (fs *FirestoreService) SetDoc(ctx context.Context, ref *firestore.DocumentRef, data interface{}, opts ...firestore.SetOption) (*firestore.WriteResult, error) {
+	fs.guard.count(firestoreCounters{writes: 1})
+	return ref.Set(ctx, data, opts...)
+}
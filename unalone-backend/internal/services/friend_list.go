@@ -0,0 +1,241 @@
+// Friend list service manages named groups of a user's friends
+package services
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+
+	"unalone-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// maxFriendListsPerUser keeps list management and invitation fan-out bounded
+const maxFriendListsPerUser = 20
+
+// FriendListService provides CRUD for friend lists
+type FriendListService struct {
+	firestoreService *FirestoreService
+	userService      *UserService
+}
+
+// NewFriendListService creates a new friend list service
+func NewFriendListService(fs *FirestoreService, us *UserService) *FriendListService {
+	return &FriendListService{firestoreService: fs, userService: us}
+}
+
+// CreateList creates a named list from the owner's friends
+func (fls *FriendListService) CreateList(ownerID string, req *models.CreateFriendListRequest) (*models.FriendList, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("list name is required")
+	}
+	members, err := fls.validateMembers(ownerID, req.MemberIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	now := clock.Now()
+	list := &models.FriendList{
+		ID:        uuid.New().String(),
+		OwnerID:   ownerID,
+		Name:      name,
+		MemberIDs: members,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if fls.isTestMode() {
+		return fls.createListMock(list)
+	}
+
+	// TODO: Save to Firestore friend_lists/{listID}
+	return nil, errors.New("firestore implementation needed")
+}
+
+// ListLists returns the owner's friend lists sorted by name
+func (fls *FriendListService) ListLists(ownerID string) ([]*models.FriendList, error) {
+	if fls.isTestMode() {
+		return fls.listListsMock(ownerID), nil
+	}
+
+	// TODO: Query Firestore friend_lists where owner_id == ownerID
+	return nil, errors.New("firestore implementation needed")
+}
+
+// GetList returns one of the owner's lists
+func (fls *FriendListService) GetList(ownerID, listID string) (*models.FriendList, error) {
+	if fls.isTestMode() {
+		return fls.getListMock(ownerID, listID)
+	}
+
+	// TODO: Load Firestore friend_lists/{listID} and check owner_id
+	return nil, errors.New("firestore implementation needed")
+}
+
+// UpdateList renames a list and/or replaces its members
+func (fls *FriendListService) UpdateList(ownerID, listID string, req *models.UpdateFriendListRequest) (*models.FriendList, error) {
+	list, err := fls.GetList(ownerID, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, errors.New("list name is required")
+		}
+		list.Name = name
+	}
+	if req.MemberIDs != nil {
+		members, err := fls.validateMembers(ownerID, req.MemberIDs)
+		if err != nil {
+			return nil, err
+		}
+		list.MemberIDs = members
+	}
+	list.UpdatedAt = clock.Now()
+
+	if fls.isTestMode() {
+		return fls.updateListMock(list)
+	}
+
+	// TODO: Update Firestore friend_lists/{listID}
+	return nil, errors.New("firestore implementation needed")
+}
+
+// DeleteList removes one of the owner's lists
+func (fls *FriendListService) DeleteList(ownerID, listID string) error {
+	if fls.isTestMode() {
+		return fls.deleteListMock(ownerID, listID)
+	}
+
+	// TODO: Delete Firestore friend_lists/{listID} after checking owner_id
+	return errors.New("firestore implementation needed")
+}
+
+// Members returns the IDs of a list's members who are still the owner's friends
+func (fls *FriendListService) Members(ownerID, listID string) ([]string, error) {
+	list, err := fls.GetList(ownerID, listID)
+	if err != nil {
+		return nil, err
+	}
+	owner, err := fls.userService.GetUserByID(ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]string, 0, len(list.MemberIDs))
+	for _, id := range list.MemberIDs {
+		if containsString(owner.Friends, id) {
+			members = append(members, id)
+		}
+	}
+	return members, nil
+}
+
+// validateMembers de-duplicates member IDs and checks each is one of the owner's friends
+func (fls *FriendListService) validateMembers(ownerID string, memberIDs []string) ([]string, error) {
+	owner, err := fls.userService.GetUserByID(ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(memberIDs))
+	members := make([]string, 0, len(memberIDs))
+	for _, id := range memberIDs {
+		if seen[id] {
+			continue
+		}
+		if !containsString(owner.Friends, id) {
+			return nil, errors.New("list members must be your friends")
+		}
+		seen[id] = true
+		members = append(members, id)
+	}
+	return members, nil
+}
+
+// isTestMode checks if we're running with mocked database
+func (fls *FriendListService) isTestMode() bool {
+	return fls.firestoreService.client == nil
+}
+
+// === Mock storage in-memory for development/test ===
+
+var (
+	mockFriendListsMu sync.Mutex
+	mockFriendLists   = make(map[string]*models.FriendList) // listID -> list
+)
+
+func (fls *FriendListService) createListMock(list *models.FriendList) (*models.FriendList, error) {
+	mockFriendListsMu.Lock()
+	defer mockFriendListsMu.Unlock()
+
+	count := 0
+	for _, existing := range mockFriendLists {
+		if existing.OwnerID == list.OwnerID {
+			count++
+		}
+	}
+	if count >= maxFriendListsPerUser {
+		return nil, errors.New("friend list limit reached")
+	}
+
+	mockFriendLists[list.ID] = list
+	copied := *list
+	return &copied, nil
+}
+
+func (fls *FriendListService) listListsMock(ownerID string) []*models.FriendList {
+	mockFriendListsMu.Lock()
+	defer mockFriendListsMu.Unlock()
+
+	lists := []*models.FriendList{}
+	for _, list := range mockFriendLists {
+		if list.OwnerID == ownerID {
+			copied := *list
+			lists = append(lists, &copied)
+		}
+	}
+	sort.Slice(lists, func(i, j int) bool { return strings.ToLower(lists[i].Name) < strings.ToLower(lists[j].Name) })
+	return lists
+}
+
+func (fls *FriendListService) getListMock(ownerID, listID string) (*models.FriendList, error) {
+	mockFriendListsMu.Lock()
+	defer mockFriendListsMu.Unlock()
+
+	list, ok := mockFriendLists[listID]
+	if !ok || list.OwnerID != ownerID {
+		return nil, errors.New("friend list not found")
+	}
+	copied := *list
+	return &copied, nil
+}
+
+func (fls *FriendListService) updateListMock(list *models.FriendList) (*models.FriendList, error) {
+	mockFriendListsMu.Lock()
+	defer mockFriendListsMu.Unlock()
+
+	if _, ok := mockFriendLists[list.ID]; !ok {
+		return nil, errors.New("friend list not found")
+	}
+	mockFriendLists[list.ID] = list
+	copied := *list
+	return &copied, nil
+}
+
+func (fls *FriendListService) deleteListMock(ownerID, listID string) error {
+	mockFriendListsMu.Lock()
+	defer mockFriendListsMu.Unlock()
+
+	list, ok := mockFriendLists[listID]
+	if !ok || list.OwnerID != ownerID {
+		return errors.New("friend list not found")
+	}
+	delete(mockFriendLists, listID)
+	return nil
+}
@@ -3,35 +3,76 @@ package services
 
 import (
 	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
 	"unalone-backend/internal/models"
 )
 
+const (
+	// MaxFriendRequestNoteLength caps the note sent with a friend request, in characters
+	MaxFriendRequestNoteLength = 200
+	defaultFriendRequestExpiry = 30 * 24 * time.Hour
+)
+
 // FriendsService provides operations for friend requests and lists
 type FriendsService struct {
 	firestoreService *FirestoreService
 	userService      *UserService
+	requestExpiry    time.Duration
+	outbox           *Outbox
 }
 
-func NewFriendsService(fs *FirestoreService, us *UserService) *FriendsService {
-	return &FriendsService{firestoreService: fs, userService: us}
+func NewFriendsService(fs *FirestoreService, us *UserService, ob *Outbox) *FriendsService {
+	expiry := defaultFriendRequestExpiry
+	if days, err := strconv.Atoi(strings.TrimSpace(os.Getenv("FRIEND_REQUEST_EXPIRY_DAYS"))); err == nil && days > 0 {
+		expiry = time.Duration(days) * 24 * time.Hour
+	}
+	return &FriendsService{firestoreService: fs, userService: us, requestExpiry: expiry, outbox: ob}
 }
 
-// SendFriendRequest sends a friend request from requesterID to targetNickname or targetUserID
-func (fs *FriendsService) SendFriendRequest(requesterID, targetIdentifier string) error {
+// SendFriendRequest sends a friend request from requesterID to targetNickname or targetUserID,
+// with an optional short note. It returns the resolved target ID and whether a mutual request
+// made them friends immediately.
+func (fs *FriendsService) SendFriendRequest(requesterID, targetIdentifier, note string) (string, bool, error) {
+	note = strings.TrimSpace(note)
+	if utf8.RuneCountInString(note) > MaxFriendRequestNoteLength {
+		return "", false, errors.New("note is too long")
+	}
 	if fs.isTestMode() {
-		return fs.sendFriendRequestMock(requesterID, targetIdentifier)
+		targetID, accepted, err := fs.sendFriendRequestMock(requesterID, targetIdentifier, note)
+		if err == nil && accepted {
+			fs.publishAccepted(requesterID, targetID)
+		}
+		return targetID, accepted, err
 	}
-	return errors.New("firestore implementation needed")
+	return "", false, errors.New("firestore implementation needed")
 }
 
 // AcceptFriendRequest accepts a pending request
 func (fs *FriendsService) AcceptFriendRequest(userID, requesterID string) error {
 	if fs.isTestMode() {
-		return fs.acceptFriendRequestMock(userID, requesterID)
+		if err := fs.acceptFriendRequestMock(userID, requesterID); err != nil {
+			return err
+		}
+		fs.publishAccepted(userID, requesterID)
+		return nil
 	}
 	return errors.New("firestore implementation needed")
 }
 
+// publishAccepted records a friend.accepted event; userID accepted requesterID's request
+func (fs *FriendsService) publishAccepted(userID, requesterID string) {
+	fs.outbox.Record(&models.DomainEvent{
+		Type:         models.DomainEventFriendAccepted,
+		ActorID:      userID,
+		TargetUserID: requesterID,
+	})
+}
+
 // RejectFriendRequest rejects a pending request
 func (fs *FriendsService) RejectFriendRequest(userID, requesterID string) error {
 	if fs.isTestMode() {
@@ -56,10 +97,44 @@ func (fs *FriendsService) ListFriends(userID string) ([]models.PublicUser, error
 	return nil, errors.New("firestore implementation needed")
 }
 
+// GetFriendStatuses returns the relationship between userID and each of the given users.
+// Only blocks made by userID are reported; being blocked by someone else reads as none.
+func (fs *FriendsService) GetFriendStatuses(userID string, otherIDs []string) (map[string]models.FriendStatus, error) {
+	if fs.isTestMode() {
+		return fs.getFriendStatusesMock(userID, otherIDs)
+	}
+	// TODO: Load the user once and batch-get the others' blocked_by lists from Firestore
+	return nil, errors.New("firestore implementation needed")
+}
+
+// MuteFriend stops proximity alerts about a friend's hotspot activity
+func (fs *FriendsService) MuteFriend(userID, friendID string) error {
+	if fs.isTestMode() {
+		return fs.setFriendMutedMock(userID, friendID, true)
+	}
+	return errors.New("firestore implementation needed")
+}
+
+// UnmuteFriend resumes proximity alerts about a friend
+func (fs *FriendsService) UnmuteFriend(userID, friendID string) error {
+	if fs.isTestMode() {
+		return fs.setFriendMutedMock(userID, friendID, false)
+	}
+	return errors.New("firestore implementation needed")
+}
+
+// ListMutedFriends returns public info for the friends a user has muted
+func (fs *FriendsService) ListMutedFriends(userID string) ([]models.PublicUser, error) {
+	if fs.isTestMode() {
+		return fs.listMutedFriendsMock(userID)
+	}
+	return nil, errors.New("firestore implementation needed")
+}
+
 // ListFriendRequests returns pending requests (received and sent)
 type FriendRequests struct {
-	Received []models.PublicUser `json:"received"`
-	Sent     []models.PublicUser `json:"sent"`
+	Received []models.FriendRequestEntry `json:"received"`
+	Sent     []models.FriendRequestEntry `json:"sent"`
 }
 
 func (fs *FriendsService) ListFriendRequests(userID string) (*FriendRequests, error) {
@@ -69,5 +144,14 @@ func (fs *FriendsService) ListFriendRequests(userID string) (*FriendRequests, er
 	return nil, errors.New("firestore implementation needed")
 }
 
+// ExpireFriendRequests withdraws pending requests older than the expiry window and returns how many were removed
+func (fs *FriendsService) ExpireFriendRequests() (int, error) {
+	if fs.isTestMode() {
+		return fs.expireFriendRequestsMock(clock.Now())
+	}
+	// TODO: Query users with friend_request_details.sent_at older than the window and remove them in batches
+	return 0, errors.New("firestore implementation needed")
+}
+
 // Helpers
 func (fs *FriendsService) isTestMode() bool { return fs.firestoreService.client == nil }
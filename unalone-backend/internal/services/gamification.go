@@ -3,16 +3,19 @@ package services
 
 import (
 	"errors"
-	"time"
+
+	"unalone-backend/internal/models"
 )
 
 type GamificationService struct {
 	firestoreService *FirestoreService
 	userService      *UserService
+	notifications    *NotificationService
+	events           *EventService
 }
 
-func NewGamificationService(fs *FirestoreService, us *UserService) *GamificationService {
-	return &GamificationService{firestoreService: fs, userService: us}
+func NewGamificationService(fs *FirestoreService, us *UserService, ns *NotificationService, es *EventService) *GamificationService {
+	return &GamificationService{firestoreService: fs, userService: us, notifications: ns, events: es}
 }
 
 // Level curve: simple step every 100 points for now (L = floor(P/100) + 1)
@@ -25,10 +28,22 @@ func (gs *GamificationService) computeLevel(points int) int {
 
 // AwardPoints to a user; returns new points and level
 func (gs *GamificationService) AwardPoints(userID string, delta int, reason string) (int, int, error) {
-	if gs.isTestMode() {
-		return gs.awardPointsMock(userID, delta)
+	if !gs.isTestMode() {
+		return 0, 0, errors.New("firestore implementation needed")
+	}
+
+	points, level, err := gs.awardPointsMock(userID, delta)
+	if err != nil {
+		return 0, 0, err
+	}
+	gs.events.Publish(userID, models.UserEventPointsAwarded, models.PointsAwardedEvent{
+		Delta: delta, Points: points, Level: level, Reason: reason,
+	})
+	// Celebrate level-ups in the user's inbox (best-effort)
+	if gs.notifications != nil && delta > 0 && level > gs.computeLevel(points-delta) {
+		_ = gs.notifications.NotifyLevelUp(userID, level)
 	}
-	return 0, 0, errors.New("firestore implementation needed")
+	return points, level, nil
 }
 
 // Convenience wrappers for common actions
@@ -42,27 +57,32 @@ func (gs *GamificationService) AwardForHotspotJoin(userID string) (int, int, err
 	return gs.AwardPoints(userID, 20, "hotspot_join")
 }
 
+func (gs *GamificationService) AwardForQRCheckIn(userID string) (int, int, error) {
+	// 15 points for proving presence at a hotspot with the host's QR code
+	return gs.AwardPoints(userID, 15, "hotspot_checkin")
+}
+
 func (gs *GamificationService) isTestMode() bool { return gs.firestoreService.client == nil }
 
-// Mock path: update points and level in users.json via UserService
+// Mock path: update points and level in the mock user store via UserService
 func (gs *GamificationService) awardPointsMock(userID string, delta int) (int, int, error) {
-	users, err := gs.userService.loadMockUsers()
+	var points, level int
+	err := gs.userService.updateMockUsers(func(users map[string]*models.User) error {
+		u, ok := users[userID]
+		if !ok {
+			return errors.New("user not found")
+		}
+		u.Points += delta
+		if u.Points < 0 {
+			u.Points = 0
+		}
+		u.Level = gs.computeLevel(u.Points)
+		u.UpdatedAt = clock.Now()
+		points, level = u.Points, u.Level
+		return nil
+	})
 	if err != nil {
 		return 0, 0, err
 	}
-	u, ok := users[userID]
-	if !ok {
-		return 0, 0, errors.New("user not found")
-	}
-	u.Points += delta
-	if u.Points < 0 {
-		u.Points = 0
-	}
-	u.Level = gs.computeLevel(u.Points)
-	u.UpdatedAt = time.Now()
-	users[u.ID] = u
-	if err := gs.userService.saveMockUsers(users); err != nil {
-		return 0, 0, err
-	}
-	return u.Points, u.Level, nil
+	return points, level, nil
 }
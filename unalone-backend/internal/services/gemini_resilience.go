@@ -0,0 +1,225 @@
+// Resilience for Gemini calls: retries with backoff, a circuit breaker and a timeout budget
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultGeminiBudget bounds a whole Gemini call, retries included
+	defaultGeminiBudget = 20 * time.Second
+	// geminiAttemptTimeout bounds one HTTP attempt within the budget
+	geminiAttemptTimeout = 10 * time.Second
+	// geminiMinAttempt is the least time worth starting another attempt with
+	geminiMinAttempt = 2 * time.Second
+	// defaultGeminiMaxAttempts includes the first try
+	defaultGeminiMaxAttempts = 3
+	// geminiBaseBackoff doubles per retry, with up to half again of jitter
+	geminiBaseBackoff = 250 * time.Millisecond
+
+	// geminiBreakerThreshold consecutive failed calls open the breaker
+	geminiBreakerThreshold = 5
+	// geminiBreakerCooldown is how long the breaker stays open before letting one call through
+	geminiBreakerCooldown = 30 * time.Second
+)
+
+// ErrGeminiUnavailable is returned without calling Gemini while the circuit breaker is open
+var ErrGeminiUnavailable = errors.New("gemini is unavailable")
+
+// geminiStatusError is a non-2xx response from Gemini
+type geminiStatusError struct {
+	StatusCode int
+	Body       string
+	RetryAfter time.Duration // From the Retry-After header, if any
+}
+
+func (e *geminiStatusError) Error() string {
+	return fmt.Sprintf("non-200: %d body=%s", e.StatusCode, e.Body)
+}
+
+// transient reports whether a retry may succeed
+func (e *geminiStatusError) transient() bool {
+	return e.StatusCode == http.StatusRequestTimeout || e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// isTransientGeminiError reports whether a failed attempt is worth retrying and counts
+// against the breaker: network errors, attempt timeouts, 408, 429 and 5xx
+func isTransientGeminiError(err error) bool {
+	var statusErr *geminiStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.transient()
+	}
+	return true
+}
+
+// geminiRetryAfter parses a Retry-After header given in seconds
+func geminiRetryAfter(header string) time.Duration {
+	seconds, err := strconv.Atoi(header)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// geminiBackoff is the wait before retry n (1-based): 250ms, 500ms, 1s... plus jitter
+func geminiBackoff(retry int) time.Duration {
+	backoff := geminiBaseBackoff << (retry - 1)
+	return backoff + time.Duration(rand.Int63n(int64(backoff)/2+1))
+}
+
+// geminiBreaker stops calls to Gemini after repeated failures so replies fall back to the
+// stub provider at once instead of waiting out timeouts. After a cooldown one trial call is
+// let through; its success closes the breaker and its failure reopens it.
+type geminiBreaker struct {
+	mu       sync.Mutex
+	failures int       // Consecutive failed calls
+	openedAt time.Time // Zero while closed
+	probing  bool      // A trial call is in flight while half-open
+	// report tells the health registry when the breaker opens (with the reason) and closes
+	report func(err error)
+}
+
+// allow reports whether a call may go out
+func (b *geminiBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.openedAt.IsZero() {
+		return true
+	}
+	if b.probing || time.Since(b.openedAt) < geminiBreakerCooldown {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+// record closes the breaker after a healthy call or counts a failure, opening it at the threshold
+func (b *geminiBreaker) record(healthy bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	wasOpen := !b.openedAt.IsZero()
+	b.probing = false
+	if healthy {
+		if wasOpen {
+			log.Printf("[gemini] circuit closed")
+			b.notify(nil)
+		}
+		b.failures = 0
+		b.openedAt = time.Time{}
+		return
+	}
+	b.failures++
+	if wasOpen || b.failures >= geminiBreakerThreshold {
+		if !wasOpen {
+			log.Printf("[gemini] circuit opened after %d failed calls; using stub replies for %s", b.failures, geminiBreakerCooldown)
+			b.notify(fmt.Errorf("circuit opened after %d failed calls", b.failures))
+		}
+		b.openedAt = time.Now()
+	}
+}
+
+func (b *geminiBreaker) notify(err error) {
+	if b.report != nil {
+		b.report(err)
+	}
+}
+
+// abandon releases a trial call whose outcome says nothing about Gemini, e.g. the caller left
+func (b *geminiBreaker) abandon() {
+	b.mu.Lock()
+	b.probing = false
+	b.mu.Unlock()
+}
+
+// geminiSettings reads GEMINI_TIMEOUT (a duration such as "20s") and GEMINI_MAX_ATTEMPTS
+func geminiSettings() (budget time.Duration, attempts int) {
+	budget = envTTL("GEMINI_TIMEOUT", defaultGeminiBudget)
+	if budget <= 0 {
+		budget = defaultGeminiBudget
+	}
+	attempts = defaultGeminiMaxAttempts
+	if raw := os.Getenv("GEMINI_MAX_ATTEMPTS"); raw != "" {
+		if n, err := strconv.Atoi(raw); err == nil && n >= 1 && n <= 10 {
+			attempts = n
+		} else {
+			log.Printf("Invalid GEMINI_MAX_ATTEMPTS %q, using %d", raw, defaultGeminiMaxAttempts)
+		}
+	}
+	return budget, attempts
+}
+
+// withGeminiRetries runs attempt until it succeeds, fails for good, or the budget runs out.
+// The budget is GEMINI_TIMEOUT or the caller's own deadline, whichever comes first, and each
+// attempt gets at most geminiAttemptTimeout of it. Once the daily budget is spent, or while
+// an admin has forced Gemini down, it returns ErrGeminiUnavailable, like an open circuit, so
+// callers fall back to stub replies.
+func (s *InMemoryAIChatService) withGeminiRetries(ctx context.Context, attempt func(context.Context) ([]byte, error)) ([]byte, error) {
+	if s.health.ForcedDown(DependencyGemini) || !s.breaker.allow() || !s.quota.Allow(QuotaGemini) {
+		return nil, ErrGeminiUnavailable
+	}
+	budgetCtx, cancel := context.WithTimeout(ctx, s.geminiBudget)
+	defer cancel()
+
+	var lastErr error
+	for try := 1; ; try++ {
+		attemptCtx, cancelAttempt := context.WithTimeout(budgetCtx, geminiAttemptTimeout)
+		body, err := attempt(attemptCtx)
+		cancelAttempt()
+		s.quota.Record(QuotaGemini, 1, geminiCostMicros(body))
+		if err == nil {
+			s.breaker.record(true)
+			return body, nil
+		}
+		lastErr = err
+
+		if ctx.Err() != nil {
+			// The caller gave up; that says nothing about Gemini
+			s.breaker.abandon()
+			return nil, lastErr
+		}
+		if !isTransientGeminiError(err) {
+			// Gemini answered, just not with success (bad request, key or model)
+			s.breaker.record(true)
+			return nil, lastErr
+		}
+		if try >= s.geminiAttempts {
+			break
+		}
+
+		wait := geminiBackoff(try)
+		var statusErr *geminiStatusError
+		if errors.As(err, &statusErr) && statusErr.RetryAfter > wait {
+			wait = statusErr.RetryAfter
+		}
+		deadline, _ := budgetCtx.Deadline()
+		if time.Until(deadline) < wait+geminiMinAttempt {
+			break
+		}
+		log.Printf("[gemini] attempt %d failed, retrying in %s: %v", try, wait.Round(time.Millisecond), err)
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-budgetCtx.Done():
+			timer.Stop()
+		}
+		if budgetCtx.Err() != nil {
+			break
+		}
+	}
+
+	if ctx.Err() != nil {
+		s.breaker.abandon()
+	} else {
+		s.breaker.record(false)
+	}
+	return nil, lastErr
+}
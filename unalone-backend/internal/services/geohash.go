@@ -0,0 +1,93 @@
+// Geohash encoding and cell coverage for map badge counts
+package services
+
+import (
+	"math"
+
+	"unalone-backend/internal/models"
+)
+
+const geohashBase32 = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// EncodeGeohash encodes a coordinate as a standard base32 geohash of the given length
+func EncodeGeohash(lat, lon float64, precision int) string {
+	latRange := [2]float64{-90, 90}
+	lonRange := [2]float64{-180, 180}
+	hash := make([]byte, 0, precision)
+	evenBit := true
+	bit, ch := 0, 0
+	for len(hash) < precision {
+		// Bits alternate between longitude and latitude, starting with longitude
+		value, rng := lon, &lonRange
+		if !evenBit {
+			value, rng = lat, &latRange
+		}
+		mid := (rng[0] + rng[1]) / 2
+		ch <<= 1
+		if value >= mid {
+			ch |= 1
+			rng[0] = mid
+		} else {
+			rng[1] = mid
+		}
+		evenBit = !evenBit
+
+		if bit++; bit == 5 {
+			hash = append(hash, geohashBase32[ch])
+			bit, ch = 0, 0
+		}
+	}
+	return string(hash)
+}
+
+// geohashCellSize returns the height and width in degrees of a cell at the given precision
+func geohashCellSize(precision int) (latDeg, lonDeg float64) {
+	bits := 5 * precision
+	lonBits := (bits + 1) / 2
+	latBits := bits / 2
+	return 180 / math.Pow(2, float64(latBits)), 360 / math.Pow(2, float64(lonBits))
+}
+
+// geohashCell returns the bounds of the cell a coordinate falls in at the given precision
+func geohashCell(lat, lon float64, precision int) models.BoundingBox {
+	latSize, lonSize := geohashCellSize(precision)
+	south := math.Floor((lat+90)/latSize)*latSize - 90
+	west := math.Floor((lon+180)/lonSize)*lonSize - 180
+	return models.BoundingBox{
+		SouthWest: models.HotspotLocation{Latitude: south, Longitude: west},
+		NorthEast: models.HotspotLocation{Latitude: south + latSize, Longitude: west + lonSize},
+	}
+}
+
+// geohashesCovering lists the cells at precision that intersect box, or false when there
+// would be more than limit of them
+func geohashesCovering(box models.BoundingBox, precision, limit int) ([]models.GeohashCount, bool) {
+	latSize, lonSize := geohashCellSize(precision)
+	first := geohashCell(box.SouthWest.Latitude, box.SouthWest.Longitude, precision)
+	rows := max(int(math.Ceil((box.NorthEast.Latitude-first.SouthWest.Latitude)/latSize)), 1)
+	cols := max(int(math.Ceil((box.NorthEast.Longitude-first.SouthWest.Longitude)/lonSize)), 1)
+	if rows*cols > limit {
+		return nil, false
+	}
+
+	// Encode each cell's center, which cannot land on a shared edge
+	cells := make([]models.GeohashCount, 0, rows*cols)
+	for r := 0; r < rows; r++ {
+		lat := first.SouthWest.Latitude + (float64(r)+0.5)*latSize
+		if lat > 90 {
+			break
+		}
+		for col := 0; col < cols; col++ {
+			lon := first.SouthWest.Longitude + (float64(col)+0.5)*lonSize
+			if lon > 180 {
+				break
+			}
+			cells = append(cells, models.GeohashCount{
+				Geohash: EncodeGeohash(lat, lon, precision),
+				Center:  models.HotspotLocation{Latitude: lat, Longitude: lon},
+				Bounds:  geohashCell(lat, lon, precision),
+			})
+		}
+	}
+	return cells, true
+}
@@ -2,13 +2,19 @@
 package services
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"math"
 	"sort"
+	"sync/atomic"
 	"time"
 
 	"unalone-backend/internal/models"
+
+	"golang.org/x/sync/singleflight"
 )
 
 // GeospatialService handles advanced geospatial operations
@@ -16,6 +22,13 @@ type GeospatialService struct {
 	redisService     *RedisService
 	firestoreService *FirestoreService
 	userService      *UserService
+
+	// rebuilds lets one search per key query storage and refill the region/cluster
+	// cache while identical concurrent searches wait for its result
+	rebuilds  singleflight.Group
+	coalesced int64
+
+	fetch candidateFetch
 }
 
 // NewGeospatialService creates a new geospatial service
@@ -24,7 +37,106 @@ func NewGeospatialService(rs *RedisService, fs *FirestoreService, us *UserServic
 		redisService:     rs,
 		firestoreService: fs,
 		userService:      us,
+		fetch:            candidateFetchFromEnv(),
+	}
+}
+
+// === Cache Maintenance ===
+
+// SyncHotspotCache keeps the Redis geo index and search caches in line with a hotspot event.
+// Drafts and archived hotspots are removed from the index since search never returns them.
+func (gs *GeospatialService) SyncHotspotCache(_ context.Context, event *models.DomainEvent) error {
+	hotspot := event.Hotspot
+	if !gs.redisService.IsAvailable() || hotspot == nil {
+		return nil
+	}
+
+	if event.Type == models.DomainEventHotspotDeleted || hotspot.IsDraft || hotspot.ArchivedAt != nil {
+		if err := gs.redisService.ClearHotspotCountCell(hotspot.ID); err != nil {
+			return err
+		}
+		return gs.redisService.InvalidateHotspotCache(hotspot)
+	}
+
+	// Map badges are public, so list-only and organization hotspots are left out of the counts
+	var err error
+	if hotspot.FriendListID == "" && hotspot.OrgID == "" {
+		err = gs.redisService.SetHotspotCountCell(hotspot)
+	} else {
+		err = gs.redisService.ClearHotspotCountCell(hotspot.ID)
+	}
+	if err != nil {
+		return err
 	}
+
+	if err := gs.redisService.AddHotspotToGeoIndex(hotspot); err != nil {
+		return err
+	}
+	return gs.redisService.InvalidateSearchCaches()
+}
+
+// === Map Badge Counts ===
+
+// maxCountCells bounds how many cells one counts request may read
+const maxCountCells = 256
+
+var (
+	ErrHotspotCountsUnavailable = errors.New("hotspot counts are unavailable right now")
+	ErrInvalidBoundingBox       = errors.New("bbox must be south-west and north-east corners: swLat,swLng,neLat,neLng")
+	ErrBoundingBoxTooLarge      = errors.New("bbox covers too many cells at this precision")
+)
+
+// CountHotspots returns active public hotspot counts per geohash cell inside box. A precision of 0
+// picks the finest one that keeps the viewport under maxCountCells cells. Counts only cover
+// hotspots outside any organization, so organization members get ErrHotspotCountsUnavailable.
+func (gs *GeospatialService) CountHotspots(orgID string, box models.BoundingBox, precision int) (*models.HotspotCountsResponse, error) {
+	if !gs.redisService.IsAvailable() || orgID != "" {
+		return nil, ErrHotspotCountsUnavailable
+	}
+	sw, ne := box.SouthWest, box.NorthEast
+	if sw.Latitude < -90 || ne.Latitude > 90 || sw.Longitude < -180 || ne.Longitude > 180 ||
+		sw.Latitude >= ne.Latitude || sw.Longitude >= ne.Longitude {
+		return nil, ErrInvalidBoundingBox
+	}
+
+	var cells []models.GeohashCount
+	if precision == 0 {
+		for p := maxCountPrecision; p >= 1; p-- {
+			if covering, ok := geohashesCovering(box, p, maxCountCells); ok {
+				cells, precision = covering, p
+				break
+			}
+		}
+	} else {
+		if precision < 1 || precision > maxCountPrecision {
+			return nil, fmt.Errorf("precision must be between 1 and %d", maxCountPrecision)
+		}
+		covering, ok := geohashesCovering(box, precision, maxCountCells)
+		if !ok {
+			return nil, ErrBoundingBoxTooLarge
+		}
+		cells = covering
+	}
+
+	geohashes := make([]string, len(cells))
+	for i, cell := range cells {
+		geohashes[i] = cell.Geohash
+	}
+	counts, err := gs.redisService.GetHotspotCounts(geohashes)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.HotspotCountsResponse{Precision: precision, Cells: []models.GeohashCount{}}
+	for i, cell := range cells {
+		if counts[i] <= 0 {
+			continue
+		}
+		cell.Count = counts[i]
+		response.Cells = append(response.Cells, cell)
+		response.Total += counts[i]
+	}
+	return response, nil
 }
 
 // === Optimized Search Methods ===
@@ -34,14 +146,20 @@ func (gs *GeospatialService) SearchHotspotsOptimized(req *models.OptimizedHotspo
 	startTime := time.Now()
 	var cacheHit bool
 
-	// Step 1: Try cache first
-	if gs.redisService.IsAvailable() {
+	// Match the normalized form tags are stored in
+	req.Filters.Tags = NormalizeTags(req.Filters.Tags)
+
+	// Step 1: Try cache first. Cached results are shared by everyone, so organization
+	// searches always go to storage.
+	cacheable := gs.redisService.IsAvailable() && req.Filters.OrgID == ""
+	if cacheable {
 		if req.Clustering.Mode != models.ClusteringModeNone {
 			cachedClusters, err := gs.redisService.GetCachedClusterResults(
 				req.GeospatialQuery.Center.Latitude,
 				req.GeospatialQuery.Center.Longitude,
 				req.GeospatialQuery.Radius,
 				req.GeospatialQuery.ZoomLevel,
+				clusterCacheVariant(req.Clustering),
 			)
 			if err == nil && cachedClusters != nil {
 				cacheHit = true
@@ -79,26 +197,55 @@ func (gs *GeospatialService) SearchHotspotsOptimized(req *models.OptimizedHotspo
 		}
 	}
 
-	// Step 2: Query database with optimizations
-	hotspots, err := gs.queryHotspotsOptimized(req)
+	// Step 2: Rebuild once per identical search; concurrent misses share the result
+	key, err := json.Marshal(req)
+	if err != nil {
+		return gs.rebuildSearch(req, startTime)
+	}
+	value, err, shared := gs.rebuilds.Do(req.Filters.OrgID+"|"+string(key), func() (interface{}, error) {
+		return gs.rebuildSearch(req, startTime)
+	})
 	if err != nil {
 		return nil, err
 	}
+	if shared {
+		atomic.AddInt64(&gs.coalesced, 1)
+	}
 
-	// Step 3: Apply clustering if requested
+	// Callers may rewrite fields of their result, so each gets its own copy
+	result := *value.(*models.HotspotSearchResultOptimized)
+	result.QueryTime = time.Since(startTime).Milliseconds()
+	return &result, nil
+}
+
+// CoalescedSearches reports how many searches waited on another caller's rebuild instead of querying storage
+func (gs *GeospatialService) CoalescedSearches() int64 {
+	return atomic.LoadInt64(&gs.coalesced)
+}
+
+// rebuildSearch queries storage, clusters the results and refills the cache
+func (gs *GeospatialService) rebuildSearch(req *models.OptimizedHotspotSearchRequest, startTime time.Time) (*models.HotspotSearchResultOptimized, error) {
+	// Query database with optimizations
+	hotspots, partial, err := gs.queryHotspotsOptimized(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply clustering if requested
 	var clusters []models.HotspotCluster
 	var individualHotspots []models.HotspotWithDistance
 
 	if req.Clustering.Mode != models.ClusteringModeNone && len(hotspots) > req.Clustering.MinClusterSize {
 		clusters = gs.clusterHotspots(hotspots, req.Clustering, req.GeospatialQuery.ZoomLevel)
 
-		// Cache clusters if Redis is available
-		if gs.redisService.IsAvailable() {
+		// Cache clusters if Redis is available; partial and organization results are not kept
+		if gs.redisService.IsAvailable() && !partial && req.Filters.OrgID == "" {
 			gs.redisService.CacheClusterResults(
 				req.GeospatialQuery.Center.Latitude,
 				req.GeospatialQuery.Center.Longitude,
 				req.GeospatialQuery.Radius,
 				req.GeospatialQuery.ZoomLevel,
+				clusterCacheVariant(req.Clustering),
 				clusters,
 				5*time.Minute,
 			)
@@ -107,7 +254,7 @@ func (gs *GeospatialService) SearchHotspotsOptimized(req *models.OptimizedHotspo
 		individualHotspots = hotspots
 
 		// Cache individual hotspots if Redis is available
-		if gs.redisService.IsAvailable() {
+		if gs.redisService.IsAvailable() && !partial && req.Filters.OrgID == "" {
 			hotspotPointers := make([]*models.Hotspot, len(hotspots))
 			for i, h := range hotspots {
 				hotspotPointers[i] = &h.Hotspot
@@ -122,7 +269,7 @@ func (gs *GeospatialService) SearchHotspotsOptimized(req *models.OptimizedHotspo
 		}
 	}
 
-	// Step 4: Build result
+	// Build result
 	result := &models.HotspotSearchResultOptimized{
 		Clusters:     clusters,
 		Hotspots:     individualHotspots,
@@ -130,8 +277,9 @@ func (gs *GeospatialService) SearchHotspotsOptimized(req *models.OptimizedHotspo
 		ClusterCount: len(clusters),
 		HasMore:      len(hotspots) >= req.Pagination.Limit,
 		QueryTime:    time.Since(startTime).Milliseconds(),
-		CacheHit:     cacheHit,
+		CacheHit:     false,
 		ZoomLevel:    req.GeospatialQuery.ZoomLevel,
+		Partial:      partial,
 	}
 
 	return result, nil
@@ -139,8 +287,9 @@ func (gs *GeospatialService) SearchHotspotsOptimized(req *models.OptimizedHotspo
 
 // === Database Query Optimization ===
 
-// queryHotspotsOptimized performs optimized database queries
-func (gs *GeospatialService) queryHotspotsOptimized(req *models.OptimizedHotspotSearchRequest) ([]models.HotspotWithDistance, error) {
+// queryHotspotsOptimized performs optimized database queries. partial reports that some
+// candidates were dropped because their documents could not be fetched in time.
+func (gs *GeospatialService) queryHotspotsOptimized(req *models.OptimizedHotspotSearchRequest) ([]models.HotspotWithDistance, bool, error) {
 	// Use Redis geospatial index for initial filtering if available
 	var candidateIDs []string
 	var err error
@@ -158,13 +307,14 @@ func (gs *GeospatialService) queryHotspotsOptimized(req *models.OptimizedHotspot
 
 	// Fallback to traditional search if Redis is not available or returned no results
 	if len(candidateIDs) == 0 {
-		return gs.traditionalGeospatialSearch(req)
+		hotspots, err := gs.traditionalGeospatialSearch(req)
+		return hotspots, false, err
 	}
 
-	// Query specific hotspots by ID (more efficient than geospatial query)
-	hotspots, err := gs.getHotspotsByIDs(candidateIDs)
+	// Query specific hotspots by ID (more efficient than geospatial query), fanned out in parallel
+	hotspots, partial, err := fetchHotspotsConcurrently(gs.firestoreService, candidateIDs, gs.fetch)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	// Apply additional filters
@@ -191,7 +341,7 @@ func (gs *GeospatialService) queryHotspotsOptimized(req *models.OptimizedHotspot
 		start = len(finalHotspots)
 	}
 
-	return finalHotspots[start:end], nil
+	return finalHotspots[start:end], partial, nil
 }
 
 // traditionalGeospatialSearch performs traditional database geospatial search
@@ -210,6 +360,7 @@ func (gs *GeospatialService) traditionalGeospatialSearch(req *models.OptimizedHo
 		Tags:              req.Filters.Tags,
 		StartTime:         getTimeFilterStart(req.Filters.TimeFilter),
 		EndTime:           getTimeFilterEnd(req.Filters.TimeFilter),
+		OrgID:             req.Filters.OrgID,
 	}
 
 	// Use mock search for now (in production, this would use Firestore)
@@ -220,18 +371,162 @@ func (gs *GeospatialService) traditionalGeospatialSearch(req *models.OptimizedHo
 
 // clusterHotspots applies clustering algorithm based on configuration
 func (gs *GeospatialService) clusterHotspots(hotspots []models.HotspotWithDistance, config models.ClusterConfig, zoomLevel int) []models.HotspotCluster {
+	// Results arrive sorted by distance from the viewport center; cluster them in a fixed
+	// spatial order instead so panning does not change which hotspot seeds each cluster
+	ordered := make([]models.HotspotWithDistance, len(hotspots))
+	copy(ordered, hotspots)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		a, b := &ordered[i].Hotspot, &ordered[j].Hotspot
+		ga := EncodeGeohash(a.Location.Latitude, a.Location.Longitude, 9)
+		gb := EncodeGeohash(b.Location.Latitude, b.Location.Longitude, 9)
+		if ga != gb {
+			return ga < gb
+		}
+		return a.ID < b.ID
+	})
+
+	var clusters []models.HotspotCluster
 	switch config.Mode {
 	case models.ClusteringModeGrid:
-		return gs.gridBasedClustering(hotspots, config, zoomLevel)
+		clusters = gs.gridBasedClustering(ordered, config, zoomLevel)
 	case models.ClusteringModeDistance:
-		return gs.distanceBasedClustering(hotspots, config, zoomLevel)
+		clusters = gs.distanceBasedClustering(ordered, config, zoomLevel)
 	case models.ClusteringModeKMeans:
-		return gs.kMeansClustering(hotspots, config, zoomLevel)
+		clusters = gs.kMeansClustering(ordered, config, zoomLevel)
 	case models.ClusteringModeAuto:
-		return gs.autoClustering(hotspots, config, zoomLevel)
+		clusters = gs.autoClustering(ordered, config, zoomLevel)
 	default:
 		return []models.HotspotCluster{}
 	}
+	clusters = assignStableClusterIDs(clusters, zoomLevel)
+	if config.IncludeHotspots {
+		attachClusterPreviews(clusters, ordered, config)
+	}
+	return clusters
+}
+
+// clusterCacheVariant keys cached clusters by the preview options they were built with
+func clusterCacheVariant(config models.ClusterConfig) string {
+	if !config.IncludeHotspots {
+		return ""
+	}
+	return fmt.Sprintf(":p%d:%s", config.PreviewCount, config.PreviewSort)
+}
+
+// attachClusterPreviews embeds each cluster's top hotspots. Clusters are cached and shared
+// between users, so only public hotspots are previewed.
+func attachClusterPreviews(clusters []models.HotspotCluster, hotspots []models.HotspotWithDistance, config models.ClusterConfig) {
+	byID := make(map[string]*models.Hotspot, len(hotspots))
+	for i := range hotspots {
+		byID[hotspots[i].Hotspot.ID] = &hotspots[i].Hotspot
+	}
+
+	for i := range clusters {
+		members := make([]*models.Hotspot, 0, len(clusters[i].Hotspots))
+		for _, id := range clusters[i].Hotspots {
+			if hotspot, ok := byID[id]; ok && hotspot.FriendListID == "" {
+				members = append(members, hotspot)
+			}
+		}
+		sort.SliceStable(members, func(a, b int) bool {
+			return previewBefore(members[a], members[b], config.PreviewSort)
+		})
+		if len(members) > config.PreviewCount {
+			members = members[:config.PreviewCount]
+		}
+
+		previews := make([]models.ClusterHotspotPreview, len(members))
+		for j, hotspot := range members {
+			previews[j] = models.ClusterHotspotPreview{
+				ID:               hotspot.ID,
+				Name:             hotspot.Name,
+				Category:         hotspot.Category,
+				Location:         hotspot.Location,
+				CurrentOccupancy: hotspot.CurrentOccupancy,
+				MaxCapacity:      hotspot.MaxCapacity,
+				ScheduledTime:    hotspot.ScheduledTime,
+				ImageURL:         hotspot.ImageURL,
+			}
+		}
+		clusters[i].Previews = previews
+	}
+}
+
+// previewBefore orders previews by the requested key, breaking ties by ID so results are stable
+func previewBefore(a, b *models.Hotspot, order string) bool {
+	if order == models.ClusterPreviewSortStartTime {
+		switch {
+		case a.ScheduledTime == nil && b.ScheduledTime != nil:
+			return false
+		case a.ScheduledTime != nil && b.ScheduledTime == nil:
+			return true
+		case a.ScheduledTime != nil && !a.ScheduledTime.Equal(*b.ScheduledTime):
+			return a.ScheduledTime.Before(*b.ScheduledTime)
+		}
+	} else if a.CurrentOccupancy != b.CurrentOccupancy {
+		return a.CurrentOccupancy > b.CurrentOccupancy
+	}
+	return a.ID < b.ID
+}
+
+// clusterGeohashPrecision picks a geohash length whose cells are about one map tile wide at zoomLevel
+func clusterGeohashPrecision(zoomLevel int) int {
+	return min(max((2*zoomLevel+4)/5, 1), 9)
+}
+
+// assignStableClusterIDs names each cluster after the geohash cell holding its center and the
+// zoom level, e.g. "tdr1v:z12", so the same cluster keeps its ID across adjacent viewports.
+// Clusters sharing a cell are told apart by a suffix, ordered by their smallest hotspot ID.
+func assignStableClusterIDs(clusters []models.HotspotCluster, zoomLevel int) []models.HotspotCluster {
+	precision := clusterGeohashPrecision(zoomLevel)
+	cells := make([]string, len(clusters))
+	anchors := make([]string, len(clusters))
+	for i, cluster := range clusters {
+		cells[i] = EncodeGeohash(cluster.CenterLocation.Latitude, cluster.CenterLocation.Longitude, precision)
+		anchors[i] = minString(cluster.Hotspots)
+	}
+
+	order := make([]int, len(clusters))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		a, b := order[i], order[j]
+		if cells[a] != cells[b] {
+			return cells[a] < cells[b]
+		}
+		return anchors[a] < anchors[b]
+	})
+
+	stable := make([]models.HotspotCluster, 0, len(clusters))
+	for n, i := range order {
+		cluster := clusters[i]
+		cluster.ID = fmt.Sprintf("%s:z%d", cells[i], zoomLevel)
+		if n > 0 && cells[order[n-1]] == cells[i] {
+			// Count how many earlier clusters share the cell
+			dup := 1
+			for k := n - 1; k >= 0 && cells[order[k]] == cells[i]; k-- {
+				dup++
+			}
+			cluster.ID = fmt.Sprintf("%s-%d", cluster.ID, dup)
+		}
+		stable = append(stable, cluster)
+	}
+	return stable
+}
+
+// minString returns the smallest value, or "" for an empty slice
+func minString(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	smallest := values[0]
+	for _, value := range values[1:] {
+		if value < smallest {
+			smallest = value
+		}
+	}
+	return smallest
 }
 
 // gridBasedClustering implements grid-based clustering
@@ -245,14 +540,11 @@ func (gs *GeospatialService) gridBasedClustering(hotspots []models.HotspotWithDi
 		gridMap[gridKey] = append(gridMap[gridKey], hotspot)
 	}
 
+	// IDs are assigned by clusterHotspots once all clusters are known
 	var clusters []models.HotspotCluster
-	clusterID := 0
-
 	for _, gridHotspots := range gridMap {
 		if len(gridHotspots) >= config.MinClusterSize {
-			cluster := gs.createClusterFromHotspots(gridHotspots, fmt.Sprintf("grid_%d", clusterID), zoomLevel)
-			clusters = append(clusters, cluster)
-			clusterID++
+			clusters = append(clusters, gs.createClusterFromHotspots(gridHotspots, "", zoomLevel))
 		}
 	}
 
@@ -264,7 +556,6 @@ func (gs *GeospatialService) distanceBasedClustering(hotspots []models.HotspotWi
 	maxDistance := gs.calculateOptimalClusterDistance(zoomLevel)
 	var clusters []models.HotspotCluster
 	used := make([]bool, len(hotspots))
-	clusterID := 0
 
 	for i, hotspot := range hotspots {
 		if used[i] {
@@ -296,9 +587,7 @@ func (gs *GeospatialService) distanceBasedClustering(hotspots []models.HotspotWi
 
 		// Create cluster if we have enough hotspots
 		if len(clusterHotspots) >= config.MinClusterSize {
-			cluster := gs.createClusterFromHotspots(clusterHotspots, fmt.Sprintf("dist_%d", clusterID), zoomLevel)
-			clusters = append(clusters, cluster)
-			clusterID++
+			clusters = append(clusters, gs.createClusterFromHotspots(clusterHotspots, "", zoomLevel))
 		}
 	}
 
@@ -389,10 +678,9 @@ func (gs *GeospatialService) kMeansClustering(hotspots []models.HotspotWithDista
 	}
 
 	var clusters []models.HotspotCluster
-	for i, group := range clusterGroups {
+	for _, group := range clusterGroups {
 		if len(group) >= config.MinClusterSize {
-			cluster := gs.createClusterFromHotspots(group, fmt.Sprintf("kmeans_%d", i), zoomLevel)
-			clusters = append(clusters, cluster)
+			clusters = append(clusters, gs.createClusterFromHotspots(group, "", zoomLevel))
 		}
 	}
 
@@ -660,6 +948,12 @@ func (gs *GeospatialService) applyFilters(hotspots []*models.Hotspot, filters mo
 	var filtered []*models.Hotspot
 
 	for _, hotspot := range hotspots {
+		// Drafts and archived hotspots are invisible to search, and each organization
+		// only sees its own hotspots
+		if hotspot.IsDraft || hotspot.ArchivedAt != nil || hotspot.OrgID != filters.OrgID {
+			continue
+		}
+
 		// Category filter
 		if len(filters.Categories) > 0 {
 			categoryMatch := false
@@ -726,14 +1020,6 @@ func (gs *GeospatialService) applyFilters(hotspots []*models.Hotspot, filters mo
 
 // === Mock Methods (for testing without Firestore) ===
 
-// getHotspotsByIDs retrieves hotspots by their IDs (mock implementation)
-func (gs *GeospatialService) getHotspotsByIDs(ids []string) ([]*models.Hotspot, error) {
-	// In a real implementation, this would query Firestore by document IDs
-	// For now, we'll return empty results since we don't have access to the mock storage
-	var hotspots []*models.Hotspot
-	return hotspots, nil
-}
-
 // searchHotspotsMock performs mock search (simplified version)
 func (gs *GeospatialService) searchHotspotsMock(req *models.HotspotSearchRequest) ([]models.HotspotWithDistance, error) {
 	// In a real implementation, this would query Firestore with geospatial filters
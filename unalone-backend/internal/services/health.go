@@ -0,0 +1,294 @@
+// Dependency health registry: probes Firestore and Redis, tracks Gemini, and tells services
+// when to take their degraded paths
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"unalone-backend/internal/models"
+)
+
+// Dependencies tracked by the health registry
+const (
+	DependencyFirestore = "firestore"
+	DependencyRedis     = "redis"
+	DependencyGemini    = "gemini"
+)
+
+const (
+	healthProbeInterval = 10 * time.Second
+	healthProbeTimeout  = 3 * time.Second
+	// healthForcedKey holds the dependencies admins forced down, shared by every instance
+	healthForcedKey = "health:forced_down"
+)
+
+// ErrUnknownDependency is returned when forcing a dependency the registry does not track
+var ErrUnknownDependency = errors.New("unknown dependency")
+
+type dependencyState struct {
+	required    bool
+	degradation string
+	probe       func(ctx context.Context) error // Nil for dependencies their callers report on
+	up          bool                            // As last probed or reported
+	forced      bool
+	lastError   string
+	checkedAt   time.Time
+	since       time.Time
+	onChange    []func(up bool)
+}
+
+func (d *dependencyState) available() bool {
+	return d.up && !d.forced
+}
+
+// HealthRegistry knows which dependencies are reachable so services can choose degraded paths
+// instead of failing: Redis is probed and, while it is down, RedisService reports itself
+// unavailable so callers use their in-process fallbacks; Firestore is probed; Gemini is
+// reported by the AI circuit breaker. Admins can force a dependency down on every instance.
+type HealthRegistry struct {
+	redisService *RedisService
+
+	mu    sync.RWMutex
+	deps  map[string]*dependencyState
+	order []string
+}
+
+// NewHealthRegistry creates the registry with Firestore and, when it was configured, Redis
+func NewHealthRegistry(fs *FirestoreService, rs *RedisService) *HealthRegistry {
+	h := &HealthRegistry{redisService: rs, deps: make(map[string]*dependencyState)}
+	h.Register(DependencyFirestore, true, "Hotspot reads are served from the stale cache and analytics writes are queued", fs.Ping)
+	if rs != nil && rs.client != nil {
+		h.Register(DependencyRedis, false, "Caches, rate limits, counters and events fall back to each instance", rs.Ping)
+		h.OnChange(DependencyRedis, rs.setReachable)
+	}
+	return h
+}
+
+// Register tracks a dependency, up until a probe or report says otherwise. Required
+// dependencies make the instance unready while they are down. Register before Start.
+func (h *HealthRegistry) Register(name string, required bool, degradation string, probe func(ctx context.Context) error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, exists := h.deps[name]; !exists {
+		h.order = append(h.order, name)
+	}
+	h.deps[name] = &dependencyState{required: required, degradation: degradation, probe: probe, up: true, since: time.Now()}
+}
+
+// OnChange registers a callback for when a dependency goes down or comes back
+func (h *HealthRegistry) OnChange(name string, fn func(up bool)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if dep := h.deps[name]; dep != nil {
+		dep.onChange = append(dep.onChange, fn)
+	}
+}
+
+// Up reports whether a dependency is usable; untracked dependencies and a nil registry count as up
+func (h *HealthRegistry) Up(name string) bool {
+	if h == nil {
+		return true
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	dep := h.deps[name]
+	return dep == nil || dep.available()
+}
+
+// ForcedDown reports whether an admin forced a dependency down
+func (h *HealthRegistry) ForcedDown(name string) bool {
+	if h == nil {
+		return false
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	dep := h.deps[name]
+	return dep != nil && dep.forced
+}
+
+// Report records the outcome of a call to a dependency that is not probed
+func (h *HealthRegistry) Report(name string, err error) {
+	if h == nil {
+		return
+	}
+	h.update(name, func(dep *dependencyState) {
+		dep.up = err == nil
+		dep.lastError = ""
+		if err != nil {
+			dep.lastError = err.Error()
+		}
+		dep.checkedAt = time.Now()
+	})
+}
+
+// Force marks a dependency down, or clears that, on every instance sharing Redis
+func (h *HealthRegistry) Force(ctx context.Context, name string, down bool) error {
+	h.mu.RLock()
+	_, known := h.deps[name]
+	h.mu.RUnlock()
+	if !known {
+		return ErrUnknownDependency
+	}
+
+	// Forcing Redis down must not stop the flag itself from reaching Redis
+	if h.redisService != nil && h.redisService.client != nil {
+		var err error
+		if down {
+			err = h.redisService.client.HSet(ctx, healthForcedKey, name, time.Now().Unix()).Err()
+		} else {
+			err = h.redisService.client.HDel(ctx, healthForcedKey, name).Err()
+		}
+		if err != nil {
+			log.Printf("[health] sharing forced state of %s failed: %v", name, err)
+		}
+	}
+	h.update(name, func(dep *dependencyState) { dep.forced = down })
+	return nil
+}
+
+// update changes a dependency's state and runs its callbacks when it goes down or comes back
+func (h *HealthRegistry) update(name string, change func(*dependencyState)) {
+	h.mu.Lock()
+	dep := h.deps[name]
+	if dep == nil {
+		h.mu.Unlock()
+		return
+	}
+	was := dep.available()
+	change(dep)
+	now := dep.available()
+	var callbacks []func(bool)
+	if was != now {
+		dep.since = time.Now()
+		callbacks = append(callbacks, dep.onChange...)
+	}
+	lastError, forced := dep.lastError, dep.forced
+	h.mu.Unlock()
+
+	if was == now {
+		return
+	}
+	switch {
+	case now:
+		log.Printf("[health] %s is back; leaving degraded mode for it", name)
+	case forced:
+		log.Printf("[health] %s forced down by an admin; degrading", name)
+	default:
+		log.Printf("[health] %s is down, degrading: %s", name, lastError)
+	}
+	for _, fn := range callbacks {
+		fn(now)
+	}
+}
+
+// Start probes the dependencies right away and then every 10 seconds until ctx is cancelled
+func (h *HealthRegistry) Start(ctx context.Context) {
+	h.probeAll(ctx)
+	go func() {
+		ticker := time.NewTicker(healthProbeInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				h.probeAll(ctx)
+			}
+		}
+	}()
+}
+
+func (h *HealthRegistry) probeAll(ctx context.Context) {
+	h.syncForced(ctx)
+
+	h.mu.RLock()
+	probes := make(map[string]func(context.Context) error)
+	for name, dep := range h.deps {
+		if dep.probe != nil {
+			probes[name] = dep.probe
+		}
+	}
+	h.mu.RUnlock()
+
+	for name, probe := range probes {
+		probeCtx, cancel := context.WithTimeout(ctx, healthProbeTimeout)
+		err := probe(probeCtx)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+		h.Report(name, err)
+	}
+}
+
+// syncForced picks up dependencies forced down or cleared by admins on other instances
+func (h *HealthRegistry) syncForced(ctx context.Context) {
+	if h.redisService == nil || h.redisService.client == nil {
+		return
+	}
+	forced, err := h.redisService.client.HGetAll(ctx, healthForcedKey).Result()
+	if err != nil {
+		return // Keep the local state while Redis cannot say
+	}
+	h.mu.RLock()
+	names := append([]string{}, h.order...)
+	h.mu.RUnlock()
+	for _, name := range names {
+		_, down := forced[name]
+		h.update(name, func(dep *dependencyState) { dep.forced = down })
+	}
+}
+
+// Status returns the instance's mode and the state of each dependency
+func (h *HealthRegistry) Status() *models.HealthReport {
+	mode, down := h.Mode()
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	report := &models.HealthReport{Status: mode, Degraded: down, Dependencies: make([]models.DependencyHealth, 0, len(h.order))}
+	for _, name := range h.order {
+		dep := h.deps[name]
+		health := models.DependencyHealth{
+			Name:        name,
+			Status:      models.DependencyUp,
+			Required:    dep.required,
+			Forced:      dep.forced,
+			Degradation: dep.degradation,
+			LastError:   dep.lastError,
+		}
+		if !dep.available() {
+			health.Status = models.DependencyDown
+		}
+		if !dep.checkedAt.IsZero() {
+			checkedAt := dep.checkedAt
+			health.CheckedAt = &checkedAt
+		}
+		since := dep.since
+		health.Since = &since
+		report.Dependencies = append(report.Dependencies, health)
+	}
+	return report
+}
+
+// Mode returns normal, degraded or unavailable, with the dependencies that are down
+func (h *HealthRegistry) Mode() (string, []string) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	mode, down := models.ServiceModeNormal, []string{}
+	for _, name := range h.order {
+		dep := h.deps[name]
+		if dep.available() {
+			continue
+		}
+		down = append(down, name)
+		if dep.required {
+			mode = models.ServiceModeUnavailable
+		} else if mode == models.ServiceModeNormal {
+			mode = models.ServiceModeDegraded
+		}
+	}
+	return mode, down
+}
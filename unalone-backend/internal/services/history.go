@@ -0,0 +1,238 @@
+// History service records attendance once hotspots end and tracks who met whom
+package services
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"unalone-backend/internal/models"
+)
+
+// HistoryService builds event attendance history and co-attendance edges
+type HistoryService struct {
+	firestoreService *FirestoreService
+	userService      *UserService
+	friendsService   *FriendsService
+	onEnded          func(*models.Hotspot)
+}
+
+// NewHistoryService creates a new history service
+func NewHistoryService(fs *FirestoreService, us *UserService, frs *FriendsService) *HistoryService {
+	return &HistoryService{firestoreService: fs, userService: us, friendsService: frs}
+}
+
+// OnHotspotEnded registers a callback invoked once for each hotspot as it is recorded
+func (hs *HistoryService) OnHotspotEnded(fn func(*models.Hotspot)) {
+	hs.onEnded = fn
+}
+
+// RecordEndedHotspots writes history for every hotspot that ended before now and was not recorded yet.
+// It returns how many hotspots were recorded.
+func (hs *HistoryService) RecordEndedHotspots(now time.Time) (int, error) {
+	if !hs.isTestMode() {
+		// TODO: Query Firestore hotspots with end time before now and history_recorded == false
+		return 0, errors.New("firestore implementation needed")
+	}
+
+	hotspots := mockHotspotList()
+
+	recorded := 0
+	for _, hotspot := range hotspots {
+		// Cancelled and unpublished hotspots never happened
+		if hotspot.IsDraft || !hotspot.IsActive {
+			continue
+		}
+		end := hotspotEndsAt(hotspot)
+		if end == nil || end.After(now) {
+			continue
+		}
+		if hs.recordHotspotMock(hotspot, *end) {
+			recorded++
+			if hs.onEnded != nil {
+				hs.onEnded(hotspot)
+			}
+		}
+	}
+	return recorded, nil
+}
+
+// GetEventHistory returns the hotspots a user attended, most recent first
+func (hs *HistoryService) GetEventHistory(userID string, limit, offset int) ([]*models.AttendanceRecord, error) {
+	if hs.isTestMode() {
+		return hs.getEventHistoryMock(userID, limit, offset), nil
+	}
+
+	// TODO: Query Firestore attendance/{userID}/events ordered by ended_at desc
+	return nil, errors.New("firestore implementation needed")
+}
+
+// GetPeopleMet returns people the user attended hotspots with, most recently met first.
+// Blocked users in either direction are left out.
+func (hs *HistoryService) GetPeopleMet(userID string, limit, offset int) ([]*models.PersonMet, error) {
+	var edges []*models.CoAttendance
+	if hs.isTestMode() {
+		edges = hs.getCoAttendanceMock(userID)
+	} else {
+		// TODO: Query Firestore attendance/{userID}/met ordered by last_met_at desc
+		return nil, errors.New("firestore implementation needed")
+	}
+
+	otherIDs := make([]string, len(edges))
+	for i, edge := range edges {
+		otherIDs[i] = edge.OtherUserID
+	}
+	statuses, err := hs.friendsService.GetFriendStatuses(userID, otherIDs)
+	if err != nil {
+		return nil, err
+	}
+	viewer, err := hs.userService.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	people := []*models.PersonMet{}
+	for _, edge := range edges {
+		if statuses[edge.OtherUserID] == models.FriendStatusBlocked {
+			continue
+		}
+		other, err := hs.userService.GetUserByID(edge.OtherUserID)
+		// BlockedBy lists who blocked a user, so this skips people who blocked the viewer
+		if err != nil || containsString(viewer.BlockedBy, other.ID) {
+			continue
+		}
+		people = append(people, &models.PersonMet{
+			UserID:          other.ID,
+			Nickname:        other.Nickname,
+			TimesMet:        edge.TimesMet,
+			LastMetAt:       edge.LastMetAt,
+			LastHotspotID:   edge.LastHotspotID,
+			LastHotspotName: edge.LastHotspotName,
+			Relationship:    statuses[edge.OtherUserID],
+		})
+	}
+
+	if offset >= len(people) {
+		return []*models.PersonMet{}, nil
+	}
+	end := offset + limit
+	if end > len(people) {
+		end = len(people)
+	}
+	return people[offset:end], nil
+}
+
+// CountSuccessfulEvents returns how many recorded hotspots a user hosted that at least one guest attended
+func (hs *HistoryService) CountSuccessfulEvents(hostID string) (int, error) {
+	if hs.isTestMode() {
+		mockHistoryMu.Lock()
+		defer mockHistoryMu.Unlock()
+		return mockHostedEvents[hostID], nil
+	}
+
+	// TODO: Count Firestore hosted_events/{hostID}/events where guest_count > 0
+	return 0, errors.New("firestore implementation needed")
+}
+
+// isTestMode checks if we're running with mocked database
+func (hs *HistoryService) isTestMode() bool {
+	return hs.firestoreService.client == nil
+}
+
+// === Mock storage in-memory for development/test ===
+
+var (
+	mockHistoryMu        sync.Mutex
+	mockRecordedHotspots = make(map[string]bool)                            // hotspotID -> recorded
+	mockAttendance       = make(map[string][]*models.AttendanceRecord)      // userID -> records
+	mockCoAttendance     = make(map[string]map[string]*models.CoAttendance) // userID -> otherUserID -> edge
+	mockHostedEvents     = make(map[string]int)                             // hostID -> ended hotspots with at least one guest
+)
+
+// recordHotspotMock stores attendance and co-attendance for an ended hotspot, once
+func (hs *HistoryService) recordHotspotMock(hotspot *models.Hotspot, endedAt time.Time) bool {
+	mockHistoryMu.Lock()
+	defer mockHistoryMu.Unlock()
+
+	if mockRecordedHotspots[hotspot.ID] {
+		return false
+	}
+	mockRecordedHotspots[hotspot.ID] = true
+	for _, userID := range hotspot.Attendees {
+		if userID != hotspot.CreatedBy {
+			mockHostedEvents[hotspot.CreatedBy]++
+			break
+		}
+	}
+
+	record := models.AttendanceRecord{
+		HotspotID:     hotspot.ID,
+		HotspotName:   hotspot.Name,
+		Category:      hotspot.Category,
+		City:          hotspot.Address.City,
+		HostID:        hotspot.CreatedBy,
+		ScheduledTime: hotspot.ScheduledTime,
+		EndedAt:       endedAt,
+		AttendeeCount: len(hotspot.Attendees),
+	}
+	for _, userID := range hotspot.Attendees {
+		copied := record
+		mockAttendance[userID] = append(mockAttendance[userID], &copied)
+
+		for _, otherID := range hotspot.Attendees {
+			if otherID == userID {
+				continue
+			}
+			if mockCoAttendance[userID] == nil {
+				mockCoAttendance[userID] = make(map[string]*models.CoAttendance)
+			}
+			edge, ok := mockCoAttendance[userID][otherID]
+			if !ok {
+				edge = &models.CoAttendance{UserID: userID, OtherUserID: otherID}
+				mockCoAttendance[userID][otherID] = edge
+			}
+			edge.TimesMet++
+			if !endedAt.Before(edge.LastMetAt) {
+				edge.LastMetAt = endedAt
+				edge.LastHotspotID = hotspot.ID
+				edge.LastHotspotName = hotspot.Name
+			}
+		}
+	}
+	return true
+}
+
+func (hs *HistoryService) getEventHistoryMock(userID string, limit, offset int) []*models.AttendanceRecord {
+	mockHistoryMu.Lock()
+	defer mockHistoryMu.Unlock()
+
+	records := make([]*models.AttendanceRecord, 0, len(mockAttendance[userID]))
+	for _, record := range mockAttendance[userID] {
+		copied := *record
+		records = append(records, &copied)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].EndedAt.After(records[j].EndedAt) })
+
+	if offset >= len(records) {
+		return []*models.AttendanceRecord{}
+	}
+	end := offset + limit
+	if end > len(records) {
+		end = len(records)
+	}
+	return records[offset:end]
+}
+
+func (hs *HistoryService) getCoAttendanceMock(userID string) []*models.CoAttendance {
+	mockHistoryMu.Lock()
+	defer mockHistoryMu.Unlock()
+
+	edges := make([]*models.CoAttendance, 0, len(mockCoAttendance[userID]))
+	for _, edge := range mockCoAttendance[userID] {
+		copied := *edge
+		edges = append(edges, &copied)
+	}
+	sort.Slice(edges, func(i, j int) bool { return edges[i].LastMetAt.After(edges[j].LastMetAt) })
+	return edges
+}
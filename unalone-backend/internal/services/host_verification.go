@@ -0,0 +1,147 @@
+// Host verification service decides which organizers earn the verified host badge
+package services
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"unalone-backend/internal/models"
+)
+
+const (
+	defaultHostVerificationMinEvents = 3
+	// hostReportWindow is how far back reports against a host count against verification
+	hostReportWindow = 90 * 24 * time.Hour
+	// hostVerificationCacheTTL bounds how stale a badge may be in search results
+	hostVerificationCacheTTL = 10 * time.Minute
+)
+
+type hostVerificationCacheEntry struct {
+	verification models.HostVerification
+	expiresAt    time.Time
+}
+
+// HostVerificationService computes the verified host flag from phone verification,
+// successful past events and recent reports
+type HostVerificationService struct {
+	userService    *UserService
+	profileService *ProfileService
+	historyService *HistoryService
+	redisService   *RedisService
+	minEvents      int
+
+	mu    sync.Mutex
+	cache map[string]hostVerificationCacheEntry // used when Redis is unavailable
+}
+
+// NewHostVerificationService creates a new host verification service.
+// HOST_VERIFICATION_MIN_EVENTS overrides how many successful events a host needs.
+func NewHostVerificationService(us *UserService, ps *ProfileService, hs *HistoryService, rs *RedisService) *HostVerificationService {
+	minEvents := defaultHostVerificationMinEvents
+	if n, err := strconv.Atoi(strings.TrimSpace(os.Getenv("HOST_VERIFICATION_MIN_EVENTS"))); err == nil && n > 0 {
+		minEvents = n
+	}
+	return &HostVerificationService{
+		userService:    us,
+		profileService: ps,
+		historyService: hs,
+		redisService:   rs,
+		minEvents:      minEvents,
+		cache:          make(map[string]hostVerificationCacheEntry),
+	}
+}
+
+// GetVerification returns a user's verification status, computing it on a cache miss
+func (hvs *HostVerificationService) GetVerification(userID string) (*models.HostVerification, error) {
+	key := "host:verification:" + userID
+	if cached, ok := hvs.getCached(key); ok {
+		return &cached, nil
+	}
+
+	user, err := hvs.userService.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	events, err := hvs.historyService.CountSuccessfulEvents(userID)
+	if err != nil {
+		return nil, err
+	}
+	now := clock.Now()
+	reports, err := hvs.profileService.CountReportsAgainst(userID, now.Add(-hostReportWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	verification := models.HostVerification{
+		UserID:           userID,
+		PhoneVerified:    user.IsPhoneVerified,
+		SuccessfulEvents: events,
+		RequiredEvents:   hvs.minEvents,
+		RecentReports:    reports,
+		CheckedAt:        now,
+	}
+	verification.Verified = verification.PhoneVerified && events >= hvs.minEvents && reports == 0
+
+	hvs.setCached(key, verification)
+	return &verification, nil
+}
+
+// VerifiedHosts returns which of the given hosts are verified. Hosts whose status
+// cannot be computed are treated as unverified.
+func (hvs *HostVerificationService) VerifiedHosts(hostIDs []string) map[string]bool {
+	verified := make(map[string]bool, len(hostIDs))
+	for _, hostID := range hostIDs {
+		if _, seen := verified[hostID]; seen {
+			continue
+		}
+		verification, err := hvs.GetVerification(hostID)
+		verified[hostID] = err == nil && verification.Verified
+	}
+	return verified
+}
+
+func (hvs *HostVerificationService) getCached(key string) (models.HostVerification, bool) {
+	if hvs.redisService.IsAvailable() {
+		var verification models.HostVerification
+		hit, err := hvs.redisService.GetCachedJSON(key, &verification)
+		return verification, err == nil && hit
+	}
+
+	hvs.mu.Lock()
+	defer hvs.mu.Unlock()
+	entry, ok := hvs.cache[key]
+	if !ok || clock.Now().After(entry.expiresAt) {
+		delete(hvs.cache, key)
+		return models.HostVerification{}, false
+	}
+	return entry.verification, true
+}
+
+func (hvs *HostVerificationService) setCached(key string, verification models.HostVerification) {
+	if hvs.redisService.IsAvailable() {
+		if err := hvs.redisService.CacheJSON(key, verification, hostVerificationCacheTTL); err != nil {
+			log.Printf("host verification cache write failed: %v", err)
+		}
+		return
+	}
+
+	hvs.mu.Lock()
+	defer hvs.mu.Unlock()
+	hvs.cache[key] = hostVerificationCacheEntry{verification: verification, expiresAt: clock.Now().Add(hostVerificationCacheTTL)}
+}
+
+// PurgeExpiredCache drops expired entries from the in-process fallback cache
+func (hvs *HostVerificationService) PurgeExpiredCache() {
+	hvs.mu.Lock()
+	defer hvs.mu.Unlock()
+	now := clock.Now()
+	for key, entry := range hvs.cache {
+		if now.After(entry.expiresAt) {
+			delete(hvs.cache, key)
+		}
+	}
+}
@@ -3,8 +3,11 @@ package services
 
 import (
 	"errors"
+	"log"
 	"math"
 	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"unalone-backend/internal/models"
@@ -12,20 +15,70 @@ import (
 	"github.com/google/uuid"
 )
 
+// ErrHotspotVersionRequired is returned when an update does not say which version it edits
+var ErrHotspotVersionRequired = errors.New("hotspot version is required, send If-Match or version")
+
+// VersionConflictError is returned when an update was based on an outdated version of the hotspot
+type VersionConflictError struct {
+	Current int64
+}
+
+func (e *VersionConflictError) Error() string {
+	return "hotspot was changed by someone else, reload and try again"
+}
+
 // HotspotService handles hotspot-related operations
 type HotspotService struct {
 	firestoreService *FirestoreService
 	userService      *UserService
+	categoryService  *CategoryService
+	tagService       *TagService
+	friendLists      *FriendListService
+	outbox           *Outbox
+	readCache        *HotspotReadCache
+	venues           *VenueService
+	clubs            *ClubService
+	tickets          TicketGate
+}
+
+// TicketGate tells whether a user holds a paid ticket for a ticketed hotspot
+type TicketGate interface {
+	HasValidTicket(hotspotID, userID string) bool
 }
 
 // NewHotspotService creates a new hotspot service
-func NewHotspotService(fs *FirestoreService, us *UserService) *HotspotService {
+func NewHotspotService(fs *FirestoreService, us *UserService, cs *CategoryService, ts *TagService, fl *FriendListService, ob *Outbox, rc *HotspotReadCache, vs *VenueService, clubs *ClubService) *HotspotService {
 	return &HotspotService{
 		firestoreService: fs,
 		userService:      us,
+		categoryService:  cs,
+		tagService:       ts,
+		friendLists:      fl,
+		outbox:           ob,
+		readCache:        rc,
+		venues:           vs,
+		clubs:            clubs,
 	}
 }
 
+// SetTicketGate sets how ticketed hotspots check a joiner's ticket; until it is set nobody can join them
+func (hs *HotspotService) SetTicketGate(gate TicketGate) {
+	hs.tickets = gate
+}
+
+// publishChange records a hotspot event carrying a snapshot of the hotspot after a successful write.
+// Cached reads are dropped right away so the writer's next read sees the change.
+func (hs *HotspotService) publishChange(eventType, actorID string, hotspot *models.Hotspot) {
+	hs.readCache.Invalidate(hotspot.ID)
+	snapshot := *hotspot
+	hs.outbox.Record(&models.DomainEvent{
+		Type:      eventType,
+		ActorID:   actorID,
+		HotspotID: hotspot.ID,
+		Hotspot:   &snapshot,
+	})
+}
+
 // CreateHotspot creates a new hotspot
 func (hs *HotspotService) CreateHotspot(userID string, req *models.CreateHotspotRequest) (*models.Hotspot, error) {
 	// Get user information
@@ -41,8 +94,35 @@ func (hs *HotspotService) CreateHotspot(userID string, req *models.CreateHotspot
 		}
 	}
 
+	// Validate category against the taxonomy
+	if err := hs.categoryService.ValidateCategory(req.Category, req.Subcategory); err != nil {
+		return nil, err
+	}
+
+	audience, err := hs.hostAudience(user, req.Audience)
+	if err != nil {
+		return nil, err
+	}
+	if req.ClubID != "" {
+		if err := hs.clubs.CheckHost(userID, user.OrgID, req.ClubID); err != nil {
+			return nil, err
+		}
+	}
+
+	ticketTiers, err := BuildTicketTiers(req.TicketTiers, nil)
+	if err != nil {
+		return nil, err
+	}
+
 	// Generate hotspot ID
 	hotspotID := uuid.New().String()
+	now := clock.Now()
+
+	// Drafts are not published until the host explicitly publishes them
+	var publishedAt *time.Time
+	if !req.IsDraft {
+		publishedAt = &now
+	}
 
 	// Create hotspot
 	hotspot := &models.Hotspot{
@@ -50,31 +130,109 @@ func (hs *HotspotService) CreateHotspot(userID string, req *models.CreateHotspot
 		Name:              req.Name,
 		Description:       req.Description,
 		Category:          req.Category,
+		Subcategory:       req.Subcategory,
 		Location:          req.Location,
 		Address:           req.Address,
+		CityKey:           NormalizeCity(req.Address.City),
+		OrgID:             user.OrgID,
+		ClubID:            req.ClubID,
 		CreatedBy:         userID,
 		CreatedByNickname: user.Nickname,
 		MaxCapacity:       req.MaxCapacity,
 		CurrentOccupancy:  1, // Creator is automatically added
 		IsActive:          true,
 		IsPublic:          req.IsPublic,
-		Tags:              req.Tags,
+		IsDraft:           req.IsDraft,
+		PublishedAt:       publishedAt,
+		Tags:              NormalizeTags(req.Tags),
 		ScheduledTime:     req.ScheduledTime,
 		EndTime:           req.EndTime,
 		ImageURL:          req.ImageURL,
 		Attendees:         []string{userID}, // Creator is first attendee
-		CreatedAt:         time.Now(),
-		UpdatedAt:         time.Now(),
+		Audience:          audience,
+		EphemeralChat:     req.EphemeralChat,
+		TicketTiers:       ticketTiers,
+		Version:           1,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+	if req.FriendListID != "" {
+		if err := hs.restrictToFriendList(hotspot, req.FriendListID); err != nil {
+			return nil, err
+		}
+	}
+	if err := hs.linkVenue(hotspot, userID, req.VenueID, req.VenueName); err != nil {
+		return nil, err
 	}
 
 	if hs.isTestMode() {
-		return hs.createHotspotMock(hotspot)
+		created, err := hs.createHotspotMock(hotspot)
+		if err == nil {
+			hs.tagService.applyContribution(nil, tagContributionOf(created))
+			hs.publishChange(models.DomainEventHotspotCreated, userID, created)
+			if !created.IsDraft {
+				hs.publishChange(models.DomainEventHotspotPublished, userID, created)
+			}
+			hs.recordActivity(created.ID, userID, models.ActivityCreated, nil)
+		}
+		return created, err
 	}
 
 	// TODO: Implement Firestore storage
 	return nil, errors.New("firestore implementation needed")
 }
 
+// linkVenue links the hotspot to the venue the host picked, or to a venue matched or created
+// from venueName (the street address when blank). Matching is best-effort: when it fails
+// the hotspot is left without a venue rather than refusing the write.
+func (hs *HotspotService) linkVenue(hotspot *models.Hotspot, userID, venueID, venueName string) error {
+	if venueID != "" {
+		venue, err := hs.venues.LinkVenue(venueID, hotspot.Location)
+		if err != nil {
+			return err
+		}
+		hotspot.VenueID = venue.ID
+		return nil
+	}
+
+	if strings.TrimSpace(venueName) == "" {
+		venueName = hotspot.Address.Street
+	}
+	hotspot.VenueID = ""
+	venue, err := hs.venues.ResolveVenue(userID, venueName, hotspot.Location, hotspot.Address)
+	if err != nil {
+		log.Printf("Venue matching failed for hotspot %s: %v", hotspot.ID, err)
+		return nil
+	}
+	if venue != nil {
+		hotspot.VenueID = venue.ID
+	}
+	return nil
+}
+
+// venueNameOf returns the name of the hotspot's current venue, or its street when it has none
+func (hs *HotspotService) venueNameOf(hotspot *models.Hotspot) string {
+	if hotspot.VenueID != "" {
+		if venue, err := hs.venues.GetVenue(hotspot.VenueID); err == nil {
+			return venue.Name
+		}
+	}
+	return hotspot.Address.Street
+}
+
+// hostAudience validates an audience restriction for a hotspot the host is setting up.
+// Hosts must belong to the audience themselves so a women-only hotspot is hosted by a woman.
+func (hs *HotspotService) hostAudience(host *models.User, audience *models.HotspotAudience) (*models.HotspotAudience, error) {
+	audience, err := normalizeAudience(audience)
+	if err != nil || audience == nil {
+		return nil, err
+	}
+	if err := CheckAudience(host, audience, clock.Now()); err != nil {
+		return nil, errors.New("you must be part of a hotspot's audience to restrict it: " + err.Error())
+	}
+	return audience, nil
+}
+
 // GetHotspot retrieves a hotspot by ID
 func (hs *HotspotService) GetHotspot(hotspotID string) (*models.Hotspot, error) {
 	if hs.isTestMode() {
@@ -85,18 +243,38 @@ func (hs *HotspotService) GetHotspot(hotspotID string) (*models.Hotspot, error)
 	return nil, errors.New("firestore implementation needed")
 }
 
+// GetHotspotsByIDs fetches several hotspots at once, in the order given. IDs that do not
+// exist are skipped, so the result may be shorter than ids.
+func (hs *HotspotService) GetHotspotsByIDs(ids []string) ([]*models.Hotspot, error) {
+	return getHotspotsByIDs(hs.firestoreService, ids)
+}
+
 // UpdateHotspot updates an existing hotspot
 func (hs *HotspotService) UpdateHotspot(userID, hotspotID string, req *models.UpdateHotspotRequest) (*models.Hotspot, error) {
 	// Get existing hotspot
-	hotspot, err := hs.GetHotspot(hotspotID)
+	stored, err := hs.GetHotspot(hotspotID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Check if user is the creator
-	if hotspot.CreatedBy != userID {
-		return nil, errors.New("only the creator can update this hotspot")
+	// Check if user is the host or a co-host
+	if stored.CreatedBy != userID && !containsString(stored.CoHosts, userID) {
+		return nil, errors.New("only the host or a co-host can update this hotspot")
+	}
+	// Reject edits based on a stale copy instead of overwriting someone else's change
+	if req.Version == nil {
+		return nil, ErrHotspotVersionRequired
 	}
+	if *req.Version != stored.Version {
+		return nil, &VersionConflictError{Current: stored.Version}
+	}
+	tagsBefore := tagContributionOf(stored)
+
+	// Edit a copy so a failed validation leaves the stored hotspot untouched
+	edited := *stored
+	edited.InvitedUserIDs = append([]string(nil), stored.InvitedUserIDs...)
+	hotspot := &edited
+	locationBefore, streetBefore := hotspot.Location, hotspot.Address.Street
 
 	// Update fields if provided
 	if req.Name != nil {
@@ -105,14 +283,30 @@ func (hs *HotspotService) UpdateHotspot(userID, hotspotID string, req *models.Up
 	if req.Description != nil {
 		hotspot.Description = *req.Description
 	}
-	if req.Category != nil {
-		hotspot.Category = *req.Category
+	if req.Category != nil || req.Subcategory != nil {
+		category, subcategory := hotspot.Category, hotspot.Subcategory
+		if req.Category != nil && *req.Category != category {
+			// A new category invalidates the old subcategory unless one is given
+			category, subcategory = *req.Category, ""
+		}
+		if req.Subcategory != nil {
+			subcategory = *req.Subcategory
+		}
+		if err := hs.categoryService.ValidateCategory(category, subcategory); err != nil {
+			return nil, err
+		}
+		hotspot.Category, hotspot.Subcategory = category, subcategory
 	}
+	// Track changes calendar subscribers need to see (bumps the iCalendar SEQUENCE)
+	scheduleChanged := false
 	if req.Location != nil {
+		scheduleChanged = scheduleChanged || *req.Location != hotspot.Location
 		hotspot.Location = *req.Location
 	}
 	if req.Address != nil {
+		scheduleChanged = scheduleChanged || *req.Address != hotspot.Address
 		hotspot.Address = *req.Address
+		hotspot.CityKey = NormalizeCity(req.Address.City)
 	}
 	if req.MaxCapacity != nil {
 		if *req.MaxCapacity < hotspot.CurrentOccupancy {
@@ -124,19 +318,61 @@ func (hs *HotspotService) UpdateHotspot(userID, hotspotID string, req *models.Up
 		hotspot.IsPublic = *req.IsPublic
 	}
 	if req.Tags != nil {
-		hotspot.Tags = req.Tags
+		hotspot.Tags = NormalizeTags(req.Tags)
 	}
 	if req.ScheduledTime != nil {
+		scheduleChanged = scheduleChanged || !timesEqual(hotspot.ScheduledTime, req.ScheduledTime)
 		hotspot.ScheduledTime = req.ScheduledTime
 	}
 	if req.EndTime != nil {
+		scheduleChanged = scheduleChanged || !timesEqual(hotspot.EndTime, req.EndTime)
 		hotspot.EndTime = req.EndTime
 	}
 	if req.ImageURL != nil {
 		hotspot.ImageURL = *req.ImageURL
 	}
+	if req.EphemeralChat != nil {
+		hotspot.EphemeralChat = *req.EphemeralChat
+	}
+	cancelled := false
 	if req.IsActive != nil {
+		scheduleChanged = scheduleChanged || *req.IsActive != hotspot.IsActive
+		// Only the host switching a hotspot off cancels it; it also goes inactive when everyone leaves
+		cancelled = hotspot.IsActive && !*req.IsActive
 		hotspot.IsActive = *req.IsActive
+		if cancelled {
+			now := clock.Now()
+			hotspot.CancelledAt = &now
+		} else if hotspot.IsActive {
+			hotspot.CancelledAt = nil
+		}
+	}
+	if req.FriendListID != nil {
+		if *req.FriendListID == "" {
+			hotspot.FriendListID = ""
+		} else if err := hs.restrictToFriendList(hotspot, *req.FriendListID); err != nil {
+			return nil, err
+		}
+	}
+	if req.Audience != nil {
+		host, err := hs.userService.GetUserByID(userID)
+		if err != nil {
+			return nil, err
+		}
+		audience, err := hs.hostAudience(host, req.Audience)
+		if err != nil {
+			return nil, err
+		}
+		hotspot.Audience = audience
+	}
+	if scheduleChanged {
+		hotspot.Sequence++
+	}
+	// A hotspot that moved may now be at a different venue
+	if hotspot.Location != locationBefore || hotspot.Address.Street != streetBefore {
+		if err := hs.linkVenue(hotspot, userID, "", hs.venueNameOf(hotspot)); err != nil {
+			return nil, err
+		}
 	}
 
 	// Validate scheduled time
@@ -146,13 +382,22 @@ func (hs *HotspotService) UpdateHotspot(userID, hotspotID string, req *models.Up
 		}
 	}
 
-	hotspot.UpdatedAt = time.Now()
+	hotspot.UpdatedAt = clock.Now()
 
 	if hs.isTestMode() {
-		return hs.updateHotspotMock(hotspot)
+		updated, err := hs.replaceHotspotMock(hotspot, *req.Version)
+		if err == nil {
+			hs.tagService.applyContribution(tagsBefore, tagContributionOf(updated))
+			hs.publishChange(models.DomainEventHotspotUpdated, userID, updated)
+			if cancelled {
+				hs.publishChange(models.DomainEventHotspotCancelled, userID, updated)
+			}
+			hs.recordActivity(updated.ID, userID, models.ActivityUpdated, map[string]interface{}{"fields": updatedFields(req)})
+		}
+		return updated, err
 	}
 
-	// TODO: Implement Firestore update
+	// TODO: Implement Firestore update in a transaction that re-checks version and increments it
 	return nil, errors.New("firestore implementation needed")
 }
 
@@ -170,53 +415,433 @@ func (hs *HotspotService) DeleteHotspot(userID, hotspotID string) error {
 	}
 
 	if hs.isTestMode() {
-		return hs.deleteHotspotMock(hotspotID)
+		if err := hs.deleteHotspotMock(hotspotID); err != nil {
+			return err
+		}
+		hs.tagService.applyContribution(tagContributionOf(hotspot), nil)
+		hs.publishChange(models.DomainEventHotspotDeleted, userID, hotspot)
+		hs.recordActivity(hotspot.ID, userID, models.ActivityDeleted, nil)
+		return nil
 	}
 
 	// TODO: Implement Firestore deletion
 	return errors.New("firestore implementation needed")
 }
 
+// CloneHotspot copies an existing hotspot into a new, inactive hotspot with a new schedule
+func (hs *HotspotService) CloneHotspot(userID, hotspotID string, req *models.CloneHotspotRequest) (*models.Hotspot, error) {
+	// Get source hotspot
+	source, err := hs.GetHotspot(hotspotID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if user is the host or a co-host
+	if source.CreatedBy != userID && !containsString(source.CoHosts, userID) {
+		return nil, errors.New("only the host or a co-host can clone this hotspot")
+	}
+	// Friend lists belong to the host, so a co-host cannot carry one over
+	if source.FriendListID != "" && source.CreatedBy != userID {
+		return nil, errors.New("only the host can clone a hotspot limited to their friend list")
+	}
+
+	// Validate scheduled time
+	if req.ScheduledTime != nil && req.EndTime != nil {
+		if req.EndTime.Before(*req.ScheduledTime) {
+			return nil, errors.New("end time cannot be before scheduled time")
+		}
+	}
+
+	user, err := hs.userService.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Copy tags so the clone does not share the source slice
+	tags := make([]string, len(source.Tags))
+	copy(tags, source.Tags)
+
+	// The clone starts as a draft so the host can review it before publishing
+	hotspot := &models.Hotspot{
+		ID:                uuid.New().String(),
+		Name:              source.Name,
+		Description:       source.Description,
+		Category:          source.Category,
+		Subcategory:       source.Subcategory,
+		Location:          source.Location,
+		Address:           source.Address,
+		CityKey:           source.CityKey,
+		VenueID:           source.VenueID,
+		OrgID:             user.OrgID,
+		CreatedBy:         userID,
+		CreatedByNickname: user.Nickname,
+		MaxCapacity:       source.MaxCapacity,
+		CurrentOccupancy:  1, // Creator is automatically added
+		IsActive:          true,
+		IsPublic:          source.IsPublic,
+		IsDraft:           true,
+		Tags:              tags,
+		ScheduledTime:     req.ScheduledTime,
+		EndTime:           req.EndTime,
+		ImageURL:          source.ImageURL,
+		Attendees:         []string{userID}, // Creator is first attendee
+		EphemeralChat:     source.EphemeralChat,
+		TicketTiers:       append([]models.TicketTier(nil), source.TicketTiers...),
+		Version:           1,
+		CreatedAt:         clock.Now(),
+		UpdatedAt:         clock.Now(),
+	}
+	if source.Audience != nil {
+		audience := *source.Audience
+		hotspot.Audience = &audience
+	}
+	// The clone stays with the club while the host is still one of its admins
+	if source.ClubID != "" && hs.clubs.CheckHost(userID, user.OrgID, source.ClubID) == nil {
+		hotspot.ClubID = source.ClubID
+	}
+	// Keep the clone limited to the same friend list, invited afresh from its current members
+	if source.FriendListID != "" {
+		if err := hs.restrictToFriendList(hotspot, source.FriendListID); err != nil {
+			return nil, err
+		}
+	}
+
+	if hs.isTestMode() {
+		created, err := hs.createHotspotMock(hotspot)
+		if err == nil {
+			hs.publishChange(models.DomainEventHotspotCreated, userID, created)
+			hs.recordActivity(created.ID, userID, models.ActivityCreated, map[string]interface{}{"cloned_from": source.ID})
+		}
+		return created, err
+	}
+
+	// TODO: Implement Firestore storage
+	return nil, errors.New("firestore implementation needed")
+}
+
+// PublishHotspot validates a draft hotspot and makes it visible to search
+func (hs *HotspotService) PublishHotspot(userID, hotspotID string) (*models.Hotspot, error) {
+	// Get existing hotspot
+	hotspot, err := hs.GetHotspot(hotspotID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if user is the creator
+	if hotspot.CreatedBy != userID {
+		return nil, errors.New("only the creator can publish this hotspot")
+	}
+
+	if !hotspot.IsDraft {
+		return nil, errors.New("hotspot is already published")
+	}
+
+	// Validate completeness before going live
+	if missing := hs.missingPublishFields(hotspot); len(missing) > 0 {
+		return nil, errors.New("hotspot is incomplete: missing " + strings.Join(missing, ", "))
+	}
+	if hotspot.ScheduledTime.Before(clock.Now()) {
+		return nil, errors.New("scheduled time must be in the future")
+	}
+
+	now := clock.Now()
+	hotspot.IsDraft = false
+	hotspot.IsActive = true
+	hotspot.PublishedAt = &now
+	hotspot.UpdatedAt = now
+
+	if hs.isTestMode() {
+		published, err := hs.updateHotspotMock(hotspot)
+		if err == nil {
+			hs.tagService.applyContribution(nil, tagContributionOf(published))
+			hs.publishChange(models.DomainEventHotspotUpdated, userID, published)
+			hs.publishChange(models.DomainEventHotspotPublished, userID, published)
+			hs.recordActivity(published.ID, userID, models.ActivityPublished, nil)
+		}
+		return published, err
+	}
+
+	// TODO: Implement Firestore update
+	return nil, errors.New("firestore implementation needed")
+}
+
+// missingPublishFields lists the fields a hotspot needs before it can be published
+func (hs *HotspotService) missingPublishFields(hotspot *models.Hotspot) []string {
+	var missing []string
+	if hotspot.Location.Latitude == 0 && hotspot.Location.Longitude == 0 {
+		missing = append(missing, "location")
+	}
+	if hotspot.ScheduledTime == nil {
+		missing = append(missing, "scheduled time")
+	}
+	if hotspot.MaxCapacity <= 0 {
+		missing = append(missing, "capacity")
+	}
+	return missing
+}
+
 // JoinHotspot adds a user to a hotspot
 func (hs *HotspotService) JoinHotspot(userID, hotspotID string) (*models.Hotspot, error) {
+	return retryOnConflict(func() (*models.Hotspot, error) {
+		return hs.joinHotspot(userID, hotspotID, false)
+	})
+}
+
+// JoinHotspotWithAccess joins a hotspot through a host's share link, which also opens
+// friend-list hotspots to the user by adding them to the invitees
+func (hs *HotspotService) JoinHotspotWithAccess(userID, hotspotID string) (*models.Hotspot, error) {
+	return retryOnConflict(func() (*models.Hotspot, error) {
+		return hs.joinHotspot(userID, hotspotID, true)
+	})
+}
+
+func (hs *HotspotService) joinHotspot(userID, hotspotID string, grantAccess bool) (*models.Hotspot, error) {
 	// Get hotspot
 	hotspot, err := hs.GetHotspot(hotspotID)
 	if err != nil {
 		return nil, err
 	}
+	if err := hs.CheckJoin(hotspot, userID, grantAccess); err != nil {
+		return nil, err
+	}
+	if grantAccess && !CanViewHotspot(hotspot, userID, hotspot.OrgID) {
+		hotspot.InvitedUserIDs = append(hotspot.InvitedUserIDs, userID)
+	}
+
+	// Add user to attendees
+	hotspot.Attendees = append(hotspot.Attendees, userID)
+	hotspot.CurrentOccupancy = len(hotspot.Attendees)
+	hotspot.UpdatedAt = clock.Now()
+
+	if hs.isTestMode() {
+		updated, err := hs.updateHotspotMock(hotspot)
+		if err == nil {
+			hs.publishChange(models.DomainEventHotspotUpdated, userID, updated)
+			hs.publishChange(models.DomainEventUserJoined, userID, updated)
+			hs.recordActivity(updated.ID, userID, models.ActivityJoined, nil)
+		}
+		return updated, err
+	}
 
-	// Check if hotspot is active
+	// TODO: Implement Firestore update
+	return nil, errors.New("firestore implementation needed")
+}
+
+// CheckJoin reports why userID cannot join the hotspot right now, or nil when they can.
+// hasAccess is true when a host's share link opens a friend-list hotspot to them.
+func (hs *HotspotService) CheckJoin(hotspot *models.Hotspot, userID string, hasAccess bool) error {
+	// Check if hotspot is published and active
+	if hotspot.IsDraft {
+		return errors.New("hotspot is not published yet")
+	}
 	if !hotspot.IsActive {
-		return nil, errors.New("hotspot is not active")
+		return errors.New("hotspot is not active")
 	}
 
 	// Check if user is already in the hotspot
-	for _, attendeeID := range hotspot.Attendees {
-		if attendeeID == userID {
-			return nil, errors.New("user is already in this hotspot")
+	if containsString(hotspot.Attendees, userID) {
+		return errors.New("user is already in this hotspot")
+	}
+
+	// Hotspots are only open within their organization, whatever link led here
+	orgID := userOrgID(hs.userService, userID)
+	if hotspot.OrgID != orgID {
+		return errors.New("hotspot not found")
+	}
+
+	// List-only hotspots are open to invitees alone
+	if !hasAccess && !CanViewHotspot(hotspot, userID, orgID) {
+		return errors.New("this hotspot is only open to invited friends")
+	}
+
+	// Audience-restricted hotspots check the joiner's verified profile
+	if hotspot.Audience != nil {
+		user, err := hs.userService.GetUserByID(userID)
+		if err != nil {
+			return err
+		}
+		if err := CheckAudience(user, hotspot.Audience, clock.Now()); err != nil {
+			return err
 		}
 	}
 
+	// Ticketed hotspots need a paid ticket
+	if len(hotspot.TicketTiers) > 0 && (hs.tickets == nil || !hs.tickets.HasValidTicket(hotspot.ID, userID)) {
+		return ErrTicketRequired
+	}
+
 	// Check capacity
 	if hotspot.MaxCapacity > 0 && hotspot.CurrentOccupancy >= hotspot.MaxCapacity {
-		return nil, errors.New("hotspot is at maximum capacity")
+		return errors.New("hotspot is at maximum capacity")
 	}
+	return nil
+}
 
-	// Add user to attendees
-	hotspot.Attendees = append(hotspot.Attendees, userID)
-	hotspot.CurrentOccupancy = len(hotspot.Attendees)
-	hotspot.UpdatedAt = time.Now()
+// UpdateTicketTiers stores a hotspot's ticket tiers; PaymentService.SetTicketTiers checks them against sales first
+func (hs *HotspotService) UpdateTicketTiers(userID string, hotspot *models.Hotspot, tiers []models.TicketTier) (*models.Hotspot, error) {
+	hotspot.TicketTiers = tiers
+	hotspot.UpdatedAt = clock.Now()
 
 	if hs.isTestMode() {
-		return hs.updateHotspotMock(hotspot)
+		updated, err := hs.updateHotspotMock(hotspot)
+		if err == nil {
+			hs.publishChange(models.DomainEventHotspotUpdated, userID, updated)
+		}
+		return updated, err
 	}
 
 	// TODO: Implement Firestore update
 	return nil, errors.New("firestore implementation needed")
 }
 
+// CheckIn marks an attendee as present once they are at the venue while the hotspot is on.
+// It reports whether this was the user's first check-in; checking in again is not an error.
+func (hs *HotspotService) CheckIn(userID, hotspotID string, latitude, longitude float64) (*models.Hotspot, bool, error) {
+	var first bool
+	hotspot, err := retryOnConflict(func() (*models.Hotspot, error) {
+		hotspot, checkedIn, err := hs.checkIn(userID, hotspotID, latitude, longitude)
+		first = checkedIn
+		return hotspot, err
+	})
+	return hotspot, first, err
+}
+
+func (hs *HotspotService) checkIn(userID, hotspotID string, latitude, longitude float64) (*models.Hotspot, bool, error) {
+	hotspot, err := hs.checkInTarget(userID, hotspotID)
+	if err != nil {
+		return nil, false, err
+	}
+	if hs.calculateDistance(latitude, longitude, hotspot.Location.Latitude, hotspot.Location.Longitude) > checkInRadiusKm {
+		return nil, false, errors.New("you are too far from the hotspot to check in")
+	}
+
+	if containsString(hotspot.CheckedIn, userID) {
+		return hotspot, false, nil
+	}
+	hotspot.CheckedIn = append(hotspot.CheckedIn, userID)
+	hotspot.UpdatedAt = clock.Now()
+
+	if hs.isTestMode() {
+		updated, err := hs.updateHotspotMock(hotspot)
+		if err == nil {
+			hs.publishChange(models.DomainEventHotspotUpdated, userID, updated)
+			hs.publishChange(models.DomainEventUserCheckedIn, userID, updated)
+			hs.recordActivity(updated.ID, userID, models.ActivityCheckedIn, nil)
+		}
+		return updated, err == nil, err
+	}
+
+	// TODO: Implement Firestore update with ArrayUnion on checked_in
+	return nil, false, errors.New("firestore implementation needed")
+}
+
+// CheckInQR marks an attendee as present after they scanned the host's check-in code, which the
+// caller has already verified. It reports whether this was the user's first check-in and whether
+// it was their first QR check-in; an earlier GPS check-in still counts as the first check-in.
+func (hs *HotspotService) CheckInQR(userID, hotspotID string) (*models.Hotspot, bool, bool, error) {
+	var first, firstQR bool
+	hotspot, err := retryOnConflict(func() (*models.Hotspot, error) {
+		hotspot, checkedIn, checkedInQR, err := hs.checkInQR(userID, hotspotID)
+		first, firstQR = checkedIn, checkedInQR
+		return hotspot, err
+	})
+	return hotspot, first, firstQR, err
+}
+
+func (hs *HotspotService) checkInQR(userID, hotspotID string) (*models.Hotspot, bool, bool, error) {
+	hotspot, err := hs.checkInTarget(userID, hotspotID)
+	if err != nil {
+		return nil, false, false, err
+	}
+	if hotspot.CreatedBy == userID {
+		return nil, false, false, errors.New("hosts cannot scan their own check-in code")
+	}
+
+	if containsString(hotspot.QRCheckedIn, userID) {
+		return hotspot, false, false, nil
+	}
+	first := !containsString(hotspot.CheckedIn, userID)
+	if first {
+		hotspot.CheckedIn = append(hotspot.CheckedIn, userID)
+	}
+	hotspot.QRCheckedIn = append(hotspot.QRCheckedIn, userID)
+	hotspot.UpdatedAt = clock.Now()
+
+	if hs.isTestMode() {
+		updated, err := hs.updateHotspotMock(hotspot)
+		if err != nil {
+			return nil, false, false, err
+		}
+		hs.publishChange(models.DomainEventHotspotUpdated, userID, updated)
+		if first {
+			hs.publishChange(models.DomainEventUserCheckedIn, userID, updated)
+		}
+		hs.publishChange(models.DomainEventUserCheckedInQR, userID, updated)
+		hs.recordActivity(updated.ID, userID, models.ActivityCheckedInQR, nil)
+		return updated, first, true, nil
+	}
+
+	// TODO: Implement Firestore update with ArrayUnion on checked_in and qr_checked_in
+	return nil, false, false, errors.New("firestore implementation needed")
+}
+
+// checkInTarget loads a hotspot the user may check in to right now: they attend it and it is
+// between shortly before its start and its end
+func (hs *HotspotService) checkInTarget(userID, hotspotID string) (*models.Hotspot, error) {
+	hotspot, err := hs.GetHotspot(hotspotID)
+	if err != nil {
+		return nil, err
+	}
+	if hotspot.IsDraft || !hotspot.IsActive {
+		return nil, errors.New("hotspot is not active")
+	}
+	if !containsString(hotspot.Attendees, userID) {
+		return nil, errors.New("user is not in this hotspot")
+	}
+	if err := CheckInWindowOpen(hotspot, clock.Now()); err != nil {
+		return nil, err
+	}
+	return hotspot, nil
+}
+
+// CheckInWindowOpen reports why check-ins are closed at now, or nil while they are open
+func CheckInWindowOpen(hotspot *models.Hotspot, now time.Time) error {
+	if hotspot.ScheduledTime != nil && now.Before(hotspot.ScheduledTime.Add(-checkInEarlyStart)) {
+		return errors.New("hotspot has not started yet")
+	}
+	if end := hotspotEndsAt(hotspot); end != nil && !now.Before(*end) {
+		return errors.New("hotspot has already ended")
+	}
+	return nil
+}
+
+// FlagForReview marks a hotspot for moderator review
+func (hs *HotspotService) FlagForReview(hotspotID string) error {
+	hotspot, err := hs.GetHotspot(hotspotID)
+	if err != nil {
+		return err
+	}
+	now := clock.Now()
+	hotspot.FlaggedForReview = true
+	hotspot.FlaggedAt = &now
+
+	if hs.isTestMode() {
+		_, err := hs.updateHotspotMock(hotspot)
+		return err
+	}
+
+	// TODO: Implement Firestore update of flagged_for_review and flagged_at
+	return errors.New("firestore implementation needed")
+}
+
 // LeaveHotspot removes a user from a hotspot
 func (hs *HotspotService) LeaveHotspot(userID, hotspotID string) (*models.Hotspot, error) {
+	return retryOnConflict(func() (*models.Hotspot, error) {
+		return hs.leaveHotspot(userID, hotspotID)
+	})
+}
+
+func (hs *HotspotService) leaveHotspot(userID, hotspotID string) (*models.Hotspot, error) {
 	// Get hotspot
 	hotspot, err := hs.GetHotspot(hotspotID)
 	if err != nil {
@@ -236,40 +861,91 @@ func (hs *HotspotService) LeaveHotspot(userID, hotspotID string) (*models.Hotspo
 		return nil, errors.New("user is not in this hotspot")
 	}
 
+	tagsBefore := tagContributionOf(hotspot)
+
 	// Remove user from attendees
 	hotspot.Attendees = append(hotspot.Attendees[:userIndex], hotspot.Attendees[userIndex+1:]...)
 	hotspot.CurrentOccupancy = len(hotspot.Attendees)
-	hotspot.UpdatedAt = time.Now()
+	hotspot.UpdatedAt = clock.Now()
+
+	hotspot.CoHosts = removeString(hotspot.CoHosts, userID)
+	if offer := hotspot.OwnershipOffer; offer != nil && (offer.ToUserID == userID || offer.OfferedBy == userID) {
+		hotspot.OwnershipOffer = nil
+	}
 
-	// If creator leaves and there are other attendees, transfer ownership to the first attendee
+	// If the host leaves and there are other attendees, hand over to a co-host or the most engaged attendee
+	transferredTo := ""
 	if hotspot.CreatedBy == userID && len(hotspot.Attendees) > 0 {
-		newOwner, err := hs.userService.GetUserByID(hotspot.Attendees[0])
+		newOwner, err := hs.userService.GetUserByID(successorFor(hotspot))
 		if err == nil {
-			hotspot.CreatedBy = newOwner.ID
-			hotspot.CreatedByNickname = newOwner.Nickname
+			handOver(hotspot, newOwner)
+			transferredTo = newOwner.ID
 		}
 	}
 
-	// If no one is left, deactivate the hotspot
-	if len(hotspot.Attendees) == 0 {
+	// If no one is left, deactivate the hotspot; imported events stay listed until they end
+	if len(hotspot.Attendees) == 0 && hotspot.External == nil {
 		hotspot.IsActive = false
 	}
 
 	if hs.isTestMode() {
-		return hs.updateHotspotMock(hotspot)
+		updated, err := hs.updateHotspotMock(hotspot)
+		if err == nil {
+			hs.tagService.applyContribution(tagsBefore, tagContributionOf(updated))
+			hs.publishChange(models.DomainEventHotspotUpdated, userID, updated)
+			hs.publishChange(models.DomainEventUserLeft, userID, updated)
+			hs.recordActivity(updated.ID, userID, models.ActivityLeft, nil)
+			if transferredTo != "" {
+				hs.publishChange(models.DomainEventOwnershipTransferred, userID, updated)
+				hs.recordActivity(updated.ID, userID, models.ActivityOwnershipTransferred, map[string]interface{}{"from": userID, "to": transferredTo})
+			}
+		}
+		return updated, err
 	}
 
 	// TODO: Implement Firestore update
 	return nil, errors.New("firestore implementation needed")
 }
 
+// ArchiveEndedHotspots drops hotspots that ended before cutoff from search and browse.
+// Archived hotspots stay readable by ID for history, feedback and calendars. It returns how many were archived.
+func (hs *HotspotService) ArchiveEndedHotspots(cutoff time.Time) (int, error) {
+	if !hs.isTestMode() {
+		// TODO: Query Firestore hotspots with end time before cutoff and archived_at == nil, updating in batches
+		return 0, errors.New("firestore implementation needed")
+	}
+
+	now := clock.Now()
+	archived := 0
+	for _, stored := range mockHotspotList() {
+		if stored.ArchivedAt != nil {
+			continue
+		}
+		if end := hotspotEndsAt(stored); end != nil && end.Before(cutoff) {
+			hotspot := copyHotspot(stored)
+			hotspot.ArchivedAt = &now
+			// A hotspot saved since the list was taken is archived on the next run
+			if _, err := hs.updateHotspotMock(hotspot); err != nil {
+				continue
+			}
+			archived++
+			hs.publishChange(models.DomainEventHotspotUpdated, "", hotspot)
+			hs.recordActivity(hotspot.ID, "", models.ActivityArchived, nil)
+		}
+	}
+	return archived, nil
+}
+
 // SearchHotspots searches for hotspots based on location and filters
 func (hs *HotspotService) SearchHotspots(req *models.HotspotSearchRequest) (*models.HotspotSearchResponse, error) {
+	// Match the normalized form tags are stored in
+	req.Tags = NormalizeTags(req.Tags)
+
 	if hs.isTestMode() {
 		return hs.searchHotspotsMock(req)
 	}
 
-	// TODO: Implement Firestore geospatial search
+	// TODO: Implement Firestore geospatial search (scoped by org_id == req.OrgID)
 	return nil, errors.New("firestore implementation needed")
 }
 
@@ -283,6 +959,267 @@ func (hs *HotspotService) GetUserHotspots(userID string) ([]*models.Hotspot, err
 	return nil, errors.New("firestore implementation needed")
 }
 
+// GetJoinedHotspots gets hotspots the user is attending
+func (hs *HotspotService) GetJoinedHotspots(userID string) ([]*models.Hotspot, error) {
+	if hs.isTestMode() {
+		return hs.getJoinedHotspotsMock(userID)
+	}
+
+	// TODO: Implement Firestore query (array-contains on attendees)
+	return nil, errors.New("firestore implementation needed")
+}
+
+// GetAttendees returns the public info of a hotspot's attendees in join order, skipping deleted accounts
+func (hs *HotspotService) GetAttendees(hotspot *models.Hotspot) []models.PublicUser {
+	attendees := make([]models.PublicUser, 0, len(hotspot.Attendees))
+	for _, id := range hotspot.Attendees {
+		if user, err := hs.userService.GetUserByID(id); err == nil {
+			attendees = append(attendees, models.PublicUser{ID: user.ID, Nickname: user.Nickname})
+		}
+	}
+	return attendees
+}
+
+// ListCities returns browsable hotspot counts grouped by city, most popular first, counting
+// only hotspots of the caller's organization ("" for no organization)
+func (hs *HotspotService) ListCities(orgID string) ([]models.CityHotspotCount, error) {
+	if hs.isTestMode() {
+		return hs.listCitiesMock(orgID)
+	}
+
+	// TODO: Implement Firestore aggregation over city_key
+	return nil, errors.New("firestore implementation needed")
+}
+
+// GetHotspotsByCity returns an organization's browsable hotspots in a city, soonest first
+func (hs *HotspotService) GetHotspotsByCity(orgID, city, country string, limit, offset int) (*models.HotspotSearchResponse, error) {
+	cityKey := NormalizeCity(city)
+	if cityKey == "" {
+		return nil, errors.New("city is required")
+	}
+
+	if hs.isTestMode() {
+		return hs.getHotspotsByCityMock(orgID, cityKey, country, limit, offset)
+	}
+
+	// TODO: Implement Firestore query on city_key
+	return nil, errors.New("firestore implementation needed")
+}
+
+// NormalizeCity produces the indexed city key (case- and whitespace-insensitive)
+func NormalizeCity(city string) string {
+	return strings.ToLower(strings.Join(strings.Fields(city), " "))
+}
+
+// InviteToHotspot invites a friend list and/or individual friends to a host's hotspot.
+// It returns the hotspot and the users who were not invited before.
+func (hs *HotspotService) InviteToHotspot(hostID, hotspotID string, req *models.InviteToHotspotRequest) (*models.Hotspot, []string, error) {
+	var added []string
+	hotspot, err := retryOnConflict(func() (*models.Hotspot, error) {
+		hotspot, invited, err := hs.inviteToHotspot(hostID, hotspotID, req)
+		added = invited
+		return hotspot, err
+	})
+	return hotspot, added, err
+}
+
+func (hs *HotspotService) inviteToHotspot(hostID, hotspotID string, req *models.InviteToHotspotRequest) (*models.Hotspot, []string, error) {
+	hotspot, err := hs.GetHotspot(hotspotID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if hotspot.CreatedBy != hostID {
+		return nil, nil, errors.New("only the creator can invite to this hotspot")
+	}
+	if req.FriendListID == "" && len(req.UserIDs) == 0 {
+		return nil, nil, errors.New("friend list or user IDs are required")
+	}
+
+	host, err := hs.userService.GetUserByID(hostID)
+	if err != nil {
+		return nil, nil, err
+	}
+	invitees := make([]string, 0, len(req.UserIDs))
+	for _, id := range req.UserIDs {
+		if !containsString(host.Friends, id) {
+			return nil, nil, errors.New("you can only invite your friends")
+		}
+		invitees = append(invitees, id)
+	}
+	if req.FriendListID != "" {
+		members, err := hs.friendLists.Members(hostID, req.FriendListID)
+		if err != nil {
+			return nil, nil, err
+		}
+		invitees = append(invitees, members...)
+	}
+
+	added := hs.addInvitees(hotspot, invitees)
+	hotspot.UpdatedAt = clock.Now()
+
+	if hs.isTestMode() {
+		updated, err := hs.updateHotspotMock(hotspot)
+		if err == nil {
+			hs.publishChange(models.DomainEventHotspotUpdated, hostID, updated)
+			if len(added) > 0 {
+				hs.recordActivity(updated.ID, hostID, models.ActivityInvited, map[string]interface{}{"user_ids": added})
+			}
+		}
+		return updated, added, err
+	}
+
+	// TODO: Implement Firestore update
+	return nil, nil, errors.New("firestore implementation needed")
+}
+
+// restrictToFriendList limits a hotspot to the host's friend list and invites its members
+func (hs *HotspotService) restrictToFriendList(hotspot *models.Hotspot, listID string) error {
+	members, err := hs.friendLists.Members(hotspot.CreatedBy, listID)
+	if err != nil {
+		return err
+	}
+	hotspot.FriendListID = listID
+	hotspot.IsPublic = false
+	hs.addInvitees(hotspot, members)
+	return nil
+}
+
+// addInvitees appends users not yet invited or attending and returns them
+func (hs *HotspotService) addInvitees(hotspot *models.Hotspot, userIDs []string) []string {
+	added := []string{}
+	for _, id := range userIDs {
+		if id == hotspot.CreatedBy || containsString(hotspot.InvitedUserIDs, id) || containsString(hotspot.Attendees, id) {
+			continue
+		}
+		hotspot.InvitedUserIDs = append(hotspot.InvitedUserIDs, id)
+		added = append(added, id)
+	}
+	return added
+}
+
+// CanViewHotspot reports whether a user in orgID ("" outside any organization) may see and
+// join a hotspot. Hotspots are only visible within their organization, and list-only hotspots
+// are limited to the host, attendees and invitees.
+func CanViewHotspot(hotspot *models.Hotspot, userID, orgID string) bool {
+	if hotspot.OrgID != orgID {
+		return false
+	}
+	if hotspot.FriendListID == "" {
+		return true
+	}
+	return hotspot.CreatedBy == userID ||
+		containsString(hotspot.Attendees, userID) ||
+		containsString(hotspot.InvitedUserIDs, userID)
+}
+
+// defaultEventLength is assumed for hotspots that have a start time but no end time
+const defaultEventLength = 6 * time.Hour
+
+// hotspotWriteAttempts bounds how often a join, leave, check-in or invite starts over after
+// another request saved the hotspot first
+const hotspotWriteAttempts = 5
+
+const (
+	// checkInEarlyStart lets attendees check in shortly before the scheduled time
+	checkInEarlyStart = 30 * time.Minute
+	// checkInRadiusKm is how close to the venue an attendee must be to check in
+	checkInRadiusKm = 0.5
+)
+
+// hotspotEndsAt returns when a hotspot is over, or nil if it is open-ended
+func hotspotEndsAt(hotspot *models.Hotspot) *time.Time {
+	if hotspot.EndTime != nil {
+		return hotspot.EndTime
+	}
+	if hotspot.ScheduledTime != nil {
+		end := hotspot.ScheduledTime.Add(defaultEventLength)
+		return &end
+	}
+	return nil
+}
+
+// GetVenueHotspots returns an organization's browsable hotspots at a venue, soonest first
+func (hs *HotspotService) GetVenueHotspots(venueID, orgID string, limit, offset int) (*models.VenueHotspotsResponse, error) {
+	venue, err := hs.venues.GetVenue(venueID)
+	if err != nil {
+		return nil, err
+	}
+	if !hs.isTestMode() {
+		// TODO: Query Firestore hotspots where venue_id == venueID ordered by scheduled_time
+		return nil, errors.New("firestore implementation needed")
+	}
+
+	results := []models.HotspotWithDistance{}
+	for _, hotspot := range mockHotspotList() {
+		if isBrowsable(hotspot) && hotspot.VenueID == venueID && hotspot.OrgID == orgID {
+			results = append(results, models.HotspotWithDistance{Hotspot: *hotspot})
+		}
+	}
+	// Sort soonest first; unscheduled hotspots go last
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i].Hotspot.ScheduledTime, results[j].Hotspot.ScheduledTime
+		if a == nil || b == nil {
+			return a != nil
+		}
+		return a.Before(*b)
+	})
+
+	total := len(results)
+	start := min(offset, total)
+	end := min(start+limit, total)
+	return &models.VenueHotspotsResponse{
+		Venue:    venue,
+		Hotspots: results[start:end],
+		Total:    total,
+		HasMore:  end < total,
+	}, nil
+}
+
+// GetClubHotspots returns the upcoming hotspots a club hosts that the viewer may see, soonest first
+func (hs *HotspotService) GetClubHotspots(club *models.Club, viewerID, orgID string, limit, offset int) (*models.ClubHotspotsResponse, error) {
+	if !hs.isTestMode() {
+		// TODO: Query Firestore hotspots where club_id == club.ID ordered by scheduled_time
+		return nil, errors.New("firestore implementation needed")
+	}
+
+	now := clock.Now()
+	results := []models.HotspotWithDistance{}
+	for _, hotspot := range mockHotspotList() {
+		if hotspot.ClubID != club.ID || !isBrowsable(hotspot) {
+			continue
+		}
+		if ends := hotspotEndsAt(hotspot); ends != nil && ends.Before(now) {
+			continue
+		}
+		if CanViewHotspot(hotspot, viewerID, orgID) {
+			results = append(results, models.HotspotWithDistance{Hotspot: *hotspot})
+		}
+	}
+	// Sort soonest first; unscheduled hotspots go last
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i].Hotspot.ScheduledTime, results[j].Hotspot.ScheduledTime
+		if a == nil || b == nil {
+			return a != nil
+		}
+		return a.Before(*b)
+	})
+
+	total := len(results)
+	start := min(offset, total)
+	end := min(start+limit, total)
+	return &models.ClubHotspotsResponse{
+		Club:     club,
+		Hotspots: results[start:end],
+		Total:    total,
+		HasMore:  end < total,
+	}, nil
+}
+
+// isBrowsable reports whether a hotspot may be listed without a location query
+func isBrowsable(hotspot *models.Hotspot) bool {
+	return hotspot.IsActive && hotspot.IsPublic && !hotspot.IsDraft && hotspot.ArchivedAt == nil
+}
+
 // calculateDistance calculates the distance between two points in kilometers
 func (hs *HotspotService) calculateDistance(lat1, lon1, lat2, lon2 float64) float64 {
 	const R = 6371 // Earth's radius in kilometers
@@ -300,40 +1237,119 @@ func (hs *HotspotService) calculateDistance(lat1, lon1, lat2, lon2 float64) floa
 	return R * c
 }
 
+// timesEqual compares two optional timestamps
+func timesEqual(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
 // isTestMode checks if we're running in test mode
 func (hs *HotspotService) isTestMode() bool {
 	return hs.firestoreService.client == nil
 }
 
 // Mock storage for testing
-var mockHotspots = make(map[string]*models.Hotspot)
+var (
+	mockHotspots   = make(map[string]*models.Hotspot)
+	mockHotspotsMu sync.RWMutex
+)
+
+// mockHotspotList returns the stored hotspots, so callers can range over them while
+// requests add and remove others
+func mockHotspotList() []*models.Hotspot {
+	mockHotspotsMu.RLock()
+	defer mockHotspotsMu.RUnlock()
+	hotspots := make([]*models.Hotspot, 0, len(mockHotspots))
+	for _, hotspot := range mockHotspots {
+		hotspots = append(hotspots, hotspot)
+	}
+	return hotspots
+}
 
 func (hs *HotspotService) createHotspotMock(hotspot *models.Hotspot) (*models.Hotspot, error) {
-	mockHotspots[hotspot.ID] = hotspot
+	mockHotspotsMu.Lock()
+	defer mockHotspotsMu.Unlock()
+	mockHotspots[hotspot.ID] = copyHotspot(hotspot)
 	return hotspot, nil
 }
 
+// getHotspotMock returns a copy of the stored hotspot, so callers can change it and save it
+// with updateHotspotMock without racing other requests
 func (hs *HotspotService) getHotspotMock(hotspotID string) (*models.Hotspot, error) {
+	mockHotspotsMu.RLock()
 	hotspot, exists := mockHotspots[hotspotID]
+	mockHotspotsMu.RUnlock()
 	if !exists {
 		return nil, errors.New("hotspot not found")
 	}
-	return hotspot, nil
+	return copyHotspot(hotspot), nil
 }
 
+// updateHotspotMock stores a changed copy of a hotspot, unless another write bumped the
+// version since the copy was read
 func (hs *HotspotService) updateHotspotMock(hotspot *models.Hotspot) (*models.Hotspot, error) {
-	mockHotspots[hotspot.ID] = hotspot
+	return hs.replaceHotspotMock(hotspot, hotspot.Version)
+}
+
+// replaceHotspotMock stores an edited copy in place of the hotspot it was made from, unless
+// another write bumped the version since. Stored hotspots are never changed in place, so
+// readers holding one from mockHotspotList keep a consistent snapshot.
+func (hs *HotspotService) replaceHotspotMock(hotspot *models.Hotspot, version int64) (*models.Hotspot, error) {
+	mockHotspotsMu.Lock()
+	defer mockHotspotsMu.Unlock()
+	current, exists := mockHotspots[hotspot.ID]
+	if !exists {
+		return nil, errors.New("hotspot not found")
+	}
+	if current.Version != version {
+		return nil, &VersionConflictError{Current: current.Version}
+	}
+	hotspot.Version = version + 1
+	mockHotspots[hotspot.ID] = copyHotspot(hotspot)
 	return hotspot, nil
 }
 
+// copyHotspot copies a hotspot along with the lists and nested values writers change
+func copyHotspot(hotspot *models.Hotspot) *models.Hotspot {
+	copied := *hotspot
+	copied.Tags = append([]string(nil), hotspot.Tags...)
+	copied.Attendees = append([]string(nil), hotspot.Attendees...)
+	copied.CheckedIn = append([]string(nil), hotspot.CheckedIn...)
+	copied.QRCheckedIn = append([]string(nil), hotspot.QRCheckedIn...)
+	copied.CoHosts = append([]string(nil), hotspot.CoHosts...)
+	copied.InvitedUserIDs = append([]string(nil), hotspot.InvitedUserIDs...)
+	copied.TicketTiers = append([]models.TicketTier(nil), hotspot.TicketTiers...)
+	if hotspot.External != nil {
+		external := *hotspot.External
+		copied.External = &external
+	}
+	return &copied
+}
+
+// retryOnConflict runs a read-modify-write again when another request saved the hotspot
+// in between, so checks such as capacity always see the latest attendees
+func retryOnConflict(write func() (*models.Hotspot, error)) (*models.Hotspot, error) {
+	for attempt := 1; ; attempt++ {
+		hotspot, err := write()
+		var conflict *VersionConflictError
+		if attempt == hotspotWriteAttempts || !errors.As(err, &conflict) {
+			return hotspot, err
+		}
+	}
+}
+
 func (hs *HotspotService) deleteHotspotMock(hotspotID string) error {
+	mockHotspotsMu.Lock()
+	defer mockHotspotsMu.Unlock()
 	delete(mockHotspots, hotspotID)
 	return nil
 }
 
 func (hs *HotspotService) getUserHotspotsMock(userID string) ([]*models.Hotspot, error) {
 	var userHotspots []*models.Hotspot
-	for _, hotspot := range mockHotspots {
+	for _, hotspot := range mockHotspotList() {
 		if hotspot.CreatedBy == userID {
 			userHotspots = append(userHotspots, hotspot)
 		}
@@ -341,10 +1357,95 @@ func (hs *HotspotService) getUserHotspotsMock(userID string) ([]*models.Hotspot,
 	return userHotspots, nil
 }
 
+func (hs *HotspotService) getJoinedHotspotsMock(userID string) ([]*models.Hotspot, error) {
+	var joined []*models.Hotspot
+	for _, hotspot := range mockHotspotList() {
+		for _, attendeeID := range hotspot.Attendees {
+			if attendeeID == userID {
+				joined = append(joined, hotspot)
+				break
+			}
+		}
+	}
+	return joined, nil
+}
+
+func (hs *HotspotService) listCitiesMock(orgID string) ([]models.CityHotspotCount, error) {
+	counts := make(map[string]*models.CityHotspotCount)
+	for _, hotspot := range mockHotspotList() {
+		if !isBrowsable(hotspot) || hotspot.CityKey == "" || hotspot.OrgID != orgID {
+			continue
+		}
+		key := hotspot.CityKey + "|" + strings.ToLower(hotspot.Address.Country)
+		entry, ok := counts[key]
+		if !ok {
+			entry = &models.CityHotspotCount{City: hotspot.Address.City, Country: hotspot.Address.Country}
+			counts[key] = entry
+		}
+		entry.Count++
+	}
+
+	cities := make([]models.CityHotspotCount, 0, len(counts))
+	for _, entry := range counts {
+		cities = append(cities, *entry)
+	}
+	sort.Slice(cities, func(i, j int) bool {
+		if cities[i].Count != cities[j].Count {
+			return cities[i].Count > cities[j].Count
+		}
+		return cities[i].City < cities[j].City
+	})
+	return cities, nil
+}
+
+func (hs *HotspotService) getHotspotsByCityMock(orgID, cityKey, country string, limit, offset int) (*models.HotspotSearchResponse, error) {
+	results := []models.HotspotWithDistance{}
+	for _, hotspot := range mockHotspotList() {
+		if !isBrowsable(hotspot) || hotspot.CityKey != cityKey || hotspot.OrgID != orgID {
+			continue
+		}
+		if country != "" && !strings.EqualFold(hotspot.Address.Country, country) {
+			continue
+		}
+		results = append(results, models.HotspotWithDistance{Hotspot: *hotspot})
+	}
+
+	// Sort soonest first; unscheduled hotspots go last
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i].Hotspot.ScheduledTime, results[j].Hotspot.ScheduledTime
+		if a == nil || b == nil {
+			return a != nil
+		}
+		return a.Before(*b)
+	})
+
+	total := len(results)
+	start := offset
+	end := start + limit
+	if start > total {
+		start = total
+	}
+	if end > total {
+		end = total
+	}
+
+	return &models.HotspotSearchResponse{
+		Hotspots: results[start:end],
+		Total:    total,
+		HasMore:  end < total,
+	}, nil
+}
+
 func (hs *HotspotService) searchHotspotsMock(req *models.HotspotSearchRequest) (*models.HotspotSearchResponse, error) {
 	var results []models.HotspotWithDistance
 
-	for _, hotspot := range mockHotspots {
+	for _, hotspot := range mockHotspotList() {
+		// Drafts and archived hotspots are invisible to search, and each organization
+		// only sees its own hotspots
+		if hotspot.IsDraft || hotspot.ArchivedAt != nil || hotspot.OrgID != req.OrgID {
+			continue
+		}
+
 		// Calculate distance
 		distance := hs.calculateDistance(
 			req.Latitude, req.Longitude,
@@ -0,0 +1,97 @@
+// Hotspot activity log: an audit trail of every change to a hotspot
+package services
+
+import (
+	"errors"
+	"log"
+	"sort"
+	"sync"
+
+	"unalone-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+var (
+	mockActivityMu      sync.Mutex
+	mockHotspotActivity = make(map[string][]*models.HotspotActivity) // hotspotID -> activity, oldest first
+)
+
+// recordActivity appends an entry to a hotspot's activity log. It runs after the change is
+// stored and only logs failures, so the audit trail never blocks the change it describes.
+func (hs *HotspotService) recordActivity(hotspotID, userID, action string, metadata map[string]interface{}) {
+	activity := &models.HotspotActivity{
+		ID:        uuid.New().String(),
+		HotspotID: hotspotID,
+		UserID:    userID,
+		Action:    action,
+		Timestamp: clock.Now(),
+		Metadata:  metadata,
+	}
+
+	if hs.isTestMode() {
+		mockActivityMu.Lock()
+		mockHotspotActivity[hotspotID] = append(mockHotspotActivity[hotspotID], activity)
+		mockActivityMu.Unlock()
+		return
+	}
+
+	// TODO: Write Firestore hotspot_activity/{id}
+	log.Printf("Hotspot activity %s for %s not stored: firestore implementation needed", action, hotspotID)
+}
+
+// GetHotspotActivity returns a page of a hotspot's activity log, newest first, and the total
+// number of entries; only the host may view it
+func (hs *HotspotService) GetHotspotActivity(userID, hotspotID string, limit, offset int) (*models.HotspotActivityResponse, error) {
+	hotspot, err := hs.GetHotspot(hotspotID)
+	if err != nil {
+		return nil, err
+	}
+	if hotspot.CreatedBy != userID {
+		return nil, errors.New("only the host can view hotspot activity")
+	}
+
+	if !hs.isTestMode() {
+		// TODO: Query Firestore hotspot_activity where hotspot_id == hotspotID ordered by timestamp desc
+		return nil, errors.New("firestore implementation needed")
+	}
+
+	mockActivityMu.Lock()
+	all := append([]*models.HotspotActivity(nil), mockHotspotActivity[hotspotID]...)
+	mockActivityMu.Unlock()
+	sort.SliceStable(all, func(i, j int) bool { return all[i].Timestamp.After(all[j].Timestamp) })
+
+	response := &models.HotspotActivityResponse{Activities: []*models.HotspotActivity{}, Total: len(all)}
+	if offset < len(all) {
+		end := min(offset+limit, len(all))
+		response.Activities = all[offset:end]
+		response.HasMore = end < len(all)
+	}
+	return response, nil
+}
+
+// updatedFields lists the fields an update request sets, by their JSON names
+func updatedFields(req *models.UpdateHotspotRequest) []string {
+	fields := []string{}
+	add := func(set bool, name string) {
+		if set {
+			fields = append(fields, name)
+		}
+	}
+	add(req.Name != nil, "name")
+	add(req.Description != nil, "description")
+	add(req.Category != nil, "category")
+	add(req.Subcategory != nil, "subcategory")
+	add(req.Location != nil, "location")
+	add(req.Address != nil, "address")
+	add(req.MaxCapacity != nil, "max_capacity")
+	add(req.IsPublic != nil, "is_public")
+	add(req.Tags != nil, "tags")
+	add(req.ScheduledTime != nil, "scheduled_time")
+	add(req.EndTime != nil, "end_time")
+	add(req.ImageURL != nil, "image_url")
+	add(req.IsActive != nil, "is_active")
+	add(req.FriendListID != nil, "friend_list_id")
+	add(req.Audience != nil, "audience")
+	return fields
+}
@@ -0,0 +1,178 @@
+// Multi-get for hotspots shared by batch fetches and geo index lookups
+package services
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/firestore"
+
+	"unalone-backend/internal/models"
+)
+
+// hotspotGetAllChunk bounds the documents requested per Firestore GetAll call
+const hotspotGetAllChunk = 100
+
+// getHotspotsByIDs loads hotspots in the order of ids, skipping duplicates and IDs that do not exist
+func getHotspotsByIDs(fs *FirestoreService, ids []string) ([]*models.Hotspot, error) {
+	ids = dedupeStrings(ids)
+	if fs.client == nil {
+		// IDs still in the geo index after a restart may no longer exist in mock storage
+		hotspots := make([]*models.Hotspot, 0, len(ids))
+		mockHotspotsMu.RLock()
+		defer mockHotspotsMu.RUnlock()
+		for _, id := range ids {
+			if hotspot, ok := mockHotspots[id]; ok {
+				hotspots = append(hotspots, hotspot)
+			}
+		}
+		return hotspots, nil
+	}
+
+	hotspots := make([]*models.Hotspot, 0, len(ids))
+	for start := 0; start < len(ids); start += hotspotGetAllChunk {
+		end := start + hotspotGetAllChunk
+		if end > len(ids) {
+			end = len(ids)
+		}
+		chunk, err := getHotspotChunk(fs.GetContext(), fs, ids[start:end])
+		if err != nil {
+			return nil, err
+		}
+		hotspots = append(hotspots, chunk...)
+	}
+	return hotspots, nil
+}
+
+// candidateFetch bounds the parallel document reads behind a geo index search
+type candidateFetch struct {
+	Workers   int           // chunks fetched at once
+	ChunkSize int           // documents per GetAll call
+	Timeout   time.Duration // deadline for the whole fetch
+}
+
+// Defaults for candidateFetch; SEARCH_FETCH_WORKERS and SEARCH_FETCH_TIMEOUT override them
+const (
+	defaultCandidateFetchWorkers = 8
+	defaultCandidateFetchChunk   = 20
+	defaultCandidateFetchTimeout = 2 * time.Second
+)
+
+// candidateFetchFromEnv reads the fetch limits, falling back to the defaults
+func candidateFetchFromEnv() candidateFetch {
+	workers := defaultCandidateFetchWorkers
+	if value := os.Getenv("SEARCH_FETCH_WORKERS"); value != "" {
+		if n, err := strconv.Atoi(value); err == nil && n > 0 {
+			workers = n
+		} else {
+			log.Printf("Invalid SEARCH_FETCH_WORKERS %q, using %d", value, workers)
+		}
+	}
+	return candidateFetch{
+		Workers:   workers,
+		ChunkSize: defaultCandidateFetchChunk,
+		Timeout:   envTTL("SEARCH_FETCH_TIMEOUT", defaultCandidateFetchTimeout),
+	}
+}
+
+// fetchHotspotsConcurrently loads candidates in chunks spread over a bounded pool of workers.
+// Chunks that fail or miss the deadline are dropped and reported through partial, so a few
+// slow documents cost some results instead of the whole search; it only errors when no chunk
+// could be read.
+func fetchHotspotsConcurrently(fs *FirestoreService, ids []string, limits candidateFetch) (hotspots []*models.Hotspot, partial bool, err error) {
+	ids = dedupeStrings(ids)
+	if fs.client == nil || len(ids) <= limits.ChunkSize {
+		hotspots, err := getHotspotsByIDs(fs, ids)
+		return hotspots, false, err
+	}
+
+	ctx, cancel := context.WithTimeout(fs.GetContext(), limits.Timeout)
+	defer cancel()
+
+	var chunks [][]string
+	for start := 0; start < len(ids); start += limits.ChunkSize {
+		end := start + limits.ChunkSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		chunks = append(chunks, ids[start:end])
+	}
+
+	// Each chunk writes only its own slot, so results keep the candidate order
+	results := make([][]*models.Hotspot, len(chunks))
+	errs := make([]error, len(chunks))
+	slots := make(chan struct{}, limits.Workers)
+	var wg sync.WaitGroup
+	for i, chunk := range chunks {
+		wg.Add(1)
+		go func(i int, chunk []string) {
+			defer wg.Done()
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+				return
+			}
+			results[i], errs[i] = getHotspotChunk(ctx, fs, chunk)
+		}(i, chunk)
+	}
+	wg.Wait()
+
+	failed := 0
+	for i, chunk := range results {
+		if errs[i] != nil {
+			failed++
+			continue
+		}
+		hotspots = append(hotspots, chunk...)
+	}
+	if failed == len(chunks) {
+		return nil, false, errs[0]
+	}
+	if failed > 0 {
+		log.Printf("Candidate fetch: %d of %d chunks failed or timed out (first: %v)", failed, len(chunks), firstError(errs))
+	}
+	return hotspots, failed > 0, nil
+}
+
+// getHotspotChunk reads hotspot documents with a single GetAll call, which returns
+// snapshots in the order of refs with missing documents marked as not existing
+func getHotspotChunk(ctx context.Context, fs *FirestoreService, ids []string) ([]*models.Hotspot, error) {
+	hotspotsRef := fs.Collection(HotspotsCollection)
+	refs := make([]*firestore.DocumentRef, len(ids))
+	for i, id := range ids {
+		refs[i] = hotspotsRef.Doc(id)
+	}
+	docs, err := fs.GetDocs(ctx, refs)
+	if err != nil {
+		return nil, err
+	}
+
+	hotspots := make([]*models.Hotspot, 0, len(docs))
+	for _, doc := range docs {
+		if !doc.Exists() {
+			continue
+		}
+		var hotspot models.Hotspot
+		if err := doc.DataTo(&hotspot); err != nil {
+			return nil, err
+		}
+		hotspot.ID = doc.Ref.ID
+		hotspots = append(hotspots, &hotspot)
+	}
+	return hotspots, nil
+}
+
+func firstError(errs []error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+// Redis read-through cache for hotspot detail and search reads served by the public GET endpoints
+package services
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"unalone-backend/internal/models"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	defaultHotspotCacheTTL = 30 * time.Second
+	defaultSearchCacheTTL  = 15 * time.Second
+	// hotspotStaleTTL is how long a copy of each entry is kept to serve while Firestore is down
+	hotspotStaleTTL = 6 * time.Hour
+)
+
+// HotspotReadCache keeps hotspot documents and search results in Redis for a short time.
+// It caches what was read from storage, before per-viewer filtering, so one entry serves
+// every user. HotspotService invalidates it on every write; without Redis it passes through.
+// While Firestore is down, reads that fail are answered from a stale copy kept for 6 hours.
+type HotspotReadCache struct {
+	redis     *RedisService
+	health    *HealthRegistry
+	detailTTL time.Duration
+	searchTTL time.Duration
+
+	// loads lets one request per key hit storage on a miss while the others wait for it
+	loads     singleflight.Group
+	hits      int64
+	misses    int64
+	coalesced int64
+	stale     int64
+}
+
+// NewHotspotReadCache creates the cache; HOTSPOT_CACHE_TTL and SEARCH_CACHE_TTL (e.g. "30s")
+// override the defaults, and "0" disables that kind of entry
+func NewHotspotReadCache(rs *RedisService, health *HealthRegistry) *HotspotReadCache {
+	return &HotspotReadCache{
+		redis:     rs,
+		health:    health,
+		detailTTL: envTTL("HOTSPOT_CACHE_TTL", defaultHotspotCacheTTL),
+		searchTTL: envTTL("SEARCH_CACHE_TTL", defaultSearchCacheTTL),
+	}
+}
+
+func envTTL(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl < 0 {
+		log.Printf("Invalid %s %q, using %s", key, value, fallback)
+		return fallback
+	}
+	return ttl
+}
+
+func (c *HotspotReadCache) enabled(ttl time.Duration) bool {
+	return c != nil && ttl > 0 && c.redis.IsAvailable()
+}
+
+// Hotspot returns the hotspot from the cache, or loads and caches it. hit reports a cache hit.
+func (c *HotspotReadCache) Hotspot(hotspotID string, load func(string) (*models.Hotspot, error)) (*models.Hotspot, bool, error) {
+	if !c.enabled(c.detailTTL) {
+		hotspot, err := load(hotspotID)
+		return hotspot, false, err
+	}
+
+	key := "hotspots:detail:" + hotspotID
+	var cached models.Hotspot
+	if found, err := c.redis.GetCachedJSON(key, &cached); err == nil && found {
+		atomic.AddInt64(&c.hits, 1)
+		return &cached, true, nil
+	}
+	atomic.AddInt64(&c.misses, 1)
+
+	value, err := c.fill(key, func() (interface{}, error) {
+		hotspot, err := load(hotspotID)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.redis.CacheJSON(key, hotspot, c.detailTTL); err != nil {
+			log.Printf("Failed to cache hotspot %s: %v", hotspotID, err)
+		}
+		c.keepStale(key, hotspot)
+		return hotspot, nil
+	})
+	if err != nil {
+		var stale models.Hotspot
+		if c.serveStale(key, &stale) {
+			return &stale, true, nil
+		}
+		return nil, false, err
+	}
+	hotspot := *value.(*models.Hotspot)
+	return &hotspot, false, nil
+}
+
+// Search returns a cached result for an identical search, or runs and caches it
+func (c *HotspotReadCache) Search(req *models.HotspotSearchRequest, load func(*models.HotspotSearchRequest) (*models.HotspotSearchResponse, error)) (*models.HotspotSearchResponse, bool, error) {
+	if !c.enabled(c.searchTTL) {
+		response, err := load(req)
+		return response, false, err
+	}
+
+	// Keys embed the search cache version, so InvalidateSearchCaches drops these too. The
+	// organization is not serialized with the request, so it is hashed in separately.
+	raw, err := json.Marshal(req)
+	if err != nil {
+		response, err := load(req)
+		return response, false, err
+	}
+	sum := sha1.Sum(append([]byte(req.OrgID+"|"), raw...))
+	key := "hotspots:search:v" + c.redis.cacheVersion() + ":" + hex.EncodeToString(sum[:])
+
+	var cached models.HotspotSearchResponse
+	if found, err := c.redis.GetCachedJSON(key, &cached); err == nil && found {
+		atomic.AddInt64(&c.hits, 1)
+		return &cached, true, nil
+	}
+	atomic.AddInt64(&c.misses, 1)
+
+	value, err := c.fill(key, func() (interface{}, error) {
+		response, err := load(req)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.redis.CacheJSON(key, response, c.searchTTL); err != nil {
+			log.Printf("Failed to cache hotspot search: %v", err)
+		}
+		c.keepStale(key, response)
+		return response, nil
+	})
+	if err != nil {
+		var stale models.HotspotSearchResponse
+		if c.serveStale(key, &stale) {
+			return &stale, true, nil
+		}
+		return nil, false, err
+	}
+	// Handlers replace fields of the response, so each caller gets its own copy
+	response := *value.(*models.HotspotSearchResponse)
+	return &response, false, nil
+}
+
+// fill runs load once per key across concurrent misses and hands every caller its result
+func (c *HotspotReadCache) fill(key string, load func() (interface{}, error)) (interface{}, error) {
+	value, err, shared := c.loads.Do(key, load)
+	if shared {
+		atomic.AddInt64(&c.coalesced, 1)
+	}
+	return value, err
+}
+
+// keepStale stores a long-lived copy of an entry to fall back on while Firestore is down
+func (c *HotspotReadCache) keepStale(key string, value interface{}) {
+	if err := c.redis.CacheJSON(key+":stale", value, hotspotStaleTTL); err != nil {
+		log.Printf("Failed to keep stale copy of %s: %v", key, err)
+	}
+}
+
+// serveStale reads the stale copy of an entry when a load failed because Firestore is down
+func (c *HotspotReadCache) serveStale(key string, dest interface{}) bool {
+	if c.health.Up(DependencyFirestore) {
+		return false
+	}
+	found, err := c.redis.GetCachedJSON(key+":stale", dest)
+	if err != nil || !found {
+		return false
+	}
+	atomic.AddInt64(&c.stale, 1)
+	return true
+}
+
+// Invalidate drops the hotspot's cached document, its stale copy and every cached search result
+func (c *HotspotReadCache) Invalidate(hotspotID string) {
+	if c == nil || !c.redis.IsAvailable() {
+		return
+	}
+	if err := c.redis.Delete("hotspots:detail:"+hotspotID, "hotspots:detail:"+hotspotID+":stale"); err != nil {
+		log.Printf("Failed to invalidate cached hotspot %s: %v", hotspotID, err)
+	}
+	if err := c.redis.InvalidateSearchCaches(); err != nil {
+		log.Printf("Failed to invalidate cached searches: %v", err)
+	}
+}
+
+// Stats reports hit and miss counts since startup
+func (c *HotspotReadCache) Stats() map[string]interface{} {
+	if c == nil {
+		return map[string]interface{}{"enabled": false}
+	}
+	hits, misses := atomic.LoadInt64(&c.hits), atomic.LoadInt64(&c.misses)
+	hitRate := 0.0
+	if hits+misses > 0 {
+		hitRate = float64(hits) / float64(hits+misses)
+	}
+	return map[string]interface{}{
+		"enabled":    c.redis.IsAvailable(),
+		"detail_ttl": c.detailTTL.String(),
+		"search_ttl": c.searchTTL.String(),
+		"hits":       hits,
+		"misses":     misses,
+		"hit_rate":   hitRate,
+		"coalesced":  atomic.LoadInt64(&c.coalesced),
+		"stale":      atomic.LoadInt64(&c.stale),
+	}
+}
@@ -0,0 +1,241 @@
+// Hotspots created from events imported out of public feeds
+package services
+
+import (
+	"errors"
+	"strings"
+	"sync"
+
+	"unalone-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// Outcomes of upserting one imported event
+const (
+	importCreated   = "created"
+	importUpdated   = "updated"
+	importCancelled = "cancelled"
+	importUnchanged = "unchanged"
+)
+
+// UpsertExternalHotspot creates the hotspot for an imported event, or refreshes the one created
+// by an earlier import of the same provider and source ID. Imported hotspots are public, have no
+// in-app host and no capacity limit; attendance is managed on the original listing.
+func (hs *HotspotService) UpsertExternalHotspot(feed *models.ImportFeed, event *models.ExternalEvent) (*models.Hotspot, string, error) {
+	key := externalSourceKey(feed, event.SourceID)
+	hotspot := hs.externalHotspotFromEvent(feed, event)
+
+	existing, err := hs.findExternalHotspot(key)
+	if err != nil {
+		return nil, "", err
+	}
+	if existing == nil {
+		if event.Cancelled {
+			return nil, importUnchanged, nil
+		}
+		if err := hs.linkVenue(hotspot, models.ExternalHostID, "", event.Venue); err != nil {
+			return nil, "", err
+		}
+		if !hs.isTestMode() {
+			// TODO: Implement Firestore storage keyed by the source key so concurrent imports cannot duplicate
+			return nil, "", errors.New("firestore implementation needed")
+		}
+		created, err := hs.createHotspotMock(hotspot)
+		if err != nil {
+			return nil, "", err
+		}
+		mockExternalHotspotsMu.Lock()
+		mockExternalHotspots[key] = created.ID
+		mockExternalHotspotsMu.Unlock()
+		hs.tagService.applyContribution(nil, tagContributionOf(created))
+		hs.publishChange(models.DomainEventHotspotCreated, models.ExternalHostID, created)
+		hs.recordActivity(created.ID, models.ExternalHostID, models.ActivityCreated, map[string]interface{}{"source": feed.Provider})
+		return created, importCreated, nil
+	}
+
+	outcome := importUpdated
+	if event.Cancelled {
+		if !existing.IsActive {
+			return existing, importUnchanged, nil
+		}
+		outcome = importCancelled
+	}
+	fields := externalChanges(existing, hotspot)
+	if len(fields) == 0 {
+		return existing, importUnchanged, nil
+	}
+
+	// Schedule, place and cancellation changes bump the calendar SEQUENCE
+	if !timesEqual(existing.ScheduledTime, hotspot.ScheduledTime) || !timesEqual(existing.EndTime, hotspot.EndTime) ||
+		existing.Location != hotspot.Location || existing.Address != hotspot.Address || existing.IsActive != hotspot.IsActive {
+		existing.Sequence++
+	}
+	moved := existing.Location != hotspot.Location || existing.Address.Street != hotspot.Address.Street
+	existing.Name, existing.Description = hotspot.Name, hotspot.Description
+	existing.Location, existing.Address, existing.CityKey = hotspot.Location, hotspot.Address, hotspot.CityKey
+	existing.ScheduledTime, existing.EndTime = hotspot.ScheduledTime, hotspot.EndTime
+	existing.IsActive, existing.CreatedByNickname = hotspot.IsActive, hotspot.CreatedByNickname
+	if outcome == importCancelled {
+		now := clock.Now()
+		existing.CancelledAt = &now
+	} else if existing.IsActive {
+		existing.CancelledAt = nil
+	}
+	existing.External.URL, existing.External.ImportedAt = hotspot.External.URL, hotspot.External.ImportedAt
+	if moved {
+		if err := hs.linkVenue(existing, models.ExternalHostID, "", event.Venue); err != nil {
+			return nil, "", err
+		}
+	}
+	existing.UpdatedAt = clock.Now()
+
+	if hs.isTestMode() {
+		updated, err := hs.updateHotspotMock(existing)
+		if err != nil {
+			return nil, "", err
+		}
+		hs.publishChange(models.DomainEventHotspotUpdated, models.ExternalHostID, updated)
+		if outcome == importCancelled {
+			hs.publishChange(models.DomainEventHotspotCancelled, models.ExternalHostID, updated)
+		}
+		hs.recordActivity(updated.ID, models.ExternalHostID, models.ActivityUpdated, map[string]interface{}{"fields": fields})
+		return updated, outcome, nil
+	}
+
+	// TODO: Implement Firestore update
+	return nil, "", errors.New("firestore implementation needed")
+}
+
+// externalHotspotFromEvent maps an imported event onto a new hotspot
+func (hs *HotspotService) externalHotspotFromEvent(feed *models.ImportFeed, event *models.ExternalEvent) *models.Hotspot {
+	now := clock.Now()
+	description := truncateRunes(strings.TrimSpace(event.Description), 500)
+	if len([]rune(description)) < 10 {
+		description = "Event listed on " + feed.Name
+	}
+	address := models.HotspotAddress{
+		Street:  strings.TrimSpace(event.Address),
+		City:    firstNonEmpty(event.City, feed.City),
+		Country: firstNonEmpty(event.Country, feed.Country),
+	}
+	category := feed.Category
+	if category == "" {
+		category = models.CategoryEvent
+	}
+
+	return &models.Hotspot{
+		ID:                uuid.New().String(),
+		Name:              truncateRunes(strings.TrimSpace(event.Title), 100),
+		Description:       description,
+		Category:          category,
+		Location:          models.HotspotLocation{Latitude: *event.Latitude, Longitude: *event.Longitude},
+		Address:           address,
+		CityKey:           NormalizeCity(address.City),
+		CreatedBy:         models.ExternalHostID,
+		CreatedByNickname: feed.Name,
+		IsActive:          !event.Cancelled,
+		IsPublic:          true,
+		PublishedAt:       &now,
+		Tags:              []string{},
+		ScheduledTime:     event.Start,
+		EndTime:           event.End,
+		Attendees:         []string{},
+		Version:           1,
+		External: &models.ExternalSource{
+			FeedID:     feed.ID,
+			Provider:   feed.Provider,
+			SourceID:   event.SourceID,
+			URL:        event.URL,
+			ImportedAt: now,
+		},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// externalChanges lists the fields an import would change on an existing hotspot
+func externalChanges(existing, incoming *models.Hotspot) []string {
+	var fields []string
+	if existing.Name != incoming.Name {
+		fields = append(fields, "name")
+	}
+	if existing.Description != incoming.Description {
+		fields = append(fields, "description")
+	}
+	if existing.Location != incoming.Location {
+		fields = append(fields, "location")
+	}
+	if existing.Address != incoming.Address {
+		fields = append(fields, "address")
+	}
+	if !timesEqual(existing.ScheduledTime, incoming.ScheduledTime) {
+		fields = append(fields, "scheduled_time")
+	}
+	if !timesEqual(existing.EndTime, incoming.EndTime) {
+		fields = append(fields, "end_time")
+	}
+	if existing.IsActive != incoming.IsActive {
+		fields = append(fields, "is_active")
+	}
+	if existing.External == nil || existing.External.URL != incoming.External.URL {
+		fields = append(fields, "url")
+	}
+	return fields
+}
+
+// findExternalHotspot returns the hotspot imported under key, or nil when there is none
+func (hs *HotspotService) findExternalHotspot(key string) (*models.Hotspot, error) {
+	if !hs.isTestMode() {
+		// TODO: Query Firestore hotspots by external.provider and external.source_id
+		return nil, errors.New("firestore implementation needed")
+	}
+	mockExternalHotspotsMu.Lock()
+	hotspotID, ok := mockExternalHotspots[key]
+	mockExternalHotspotsMu.Unlock()
+	if !ok {
+		return nil, nil
+	}
+	hotspot, err := hs.getHotspotMock(hotspotID)
+	if err != nil {
+		// Deleted since the last import; import it again
+		return nil, nil
+	}
+	return hotspot, nil
+}
+
+// externalSourceKey identifies an event across feeds of the same provider, so a listing that
+// appears in several feeds becomes one hotspot
+func externalSourceKey(feed *models.ImportFeed, sourceID string) string {
+	provider := strings.ToLower(strings.TrimSpace(feed.Provider))
+	if provider == "" {
+		provider = "feed:" + feed.ID
+	}
+	return provider + "|" + sourceID
+}
+
+// truncateRunes shortens s to at most n characters
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return strings.TrimSpace(string(runes[:n]))
+}
+
+// firstNonEmpty returns the first value that is not blank
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// === Mock storage in-memory for development/test ===
+
+var (
+	mockExternalHotspotsMu sync.Mutex
+	mockExternalHotspots   = make(map[string]string) // provider|sourceID -> hotspotID
+)
@@ -0,0 +1,248 @@
+// Hotspot co-hosts and ownership hand-over between attendees
+package services
+
+import (
+	"errors"
+	"time"
+
+	"unalone-backend/internal/models"
+)
+
+// ownershipOfferTTL is how long an attendee has to accept a hotspot hand-over
+const ownershipOfferTTL = 48 * time.Hour
+
+var (
+	ErrNoOwnershipOffer      = errors.New("there is no ownership offer for you on this hotspot")
+	ErrOwnershipOfferExpired = errors.New("the ownership offer has expired")
+)
+
+// AddCoHost makes an attendee a co-host, first in line to take over if the host leaves
+func (hs *HotspotService) AddCoHost(hostID, hotspotID, userID string) (*models.Hotspot, error) {
+	hotspot, err := hs.hostedAttendee(hostID, hotspotID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if containsString(hotspot.CoHosts, userID) {
+		return hotspot, nil
+	}
+	hotspot.CoHosts = append(hotspot.CoHosts, userID)
+	hotspot.UpdatedAt = clock.Now()
+
+	if hs.isTestMode() {
+		updated, err := hs.updateHotspotMock(hotspot)
+		if err == nil {
+			hs.publishChange(models.DomainEventHotspotUpdated, hostID, updated)
+			hs.recordActivity(updated.ID, hostID, models.ActivityCoHostAdded, map[string]interface{}{"user_id": userID})
+		}
+		return updated, err
+	}
+
+	// TODO: Implement Firestore update with ArrayUnion on co_hosts
+	return nil, errors.New("firestore implementation needed")
+}
+
+// RemoveCoHost takes an attendee off the co-host list; removing someone who is not a co-host is a no-op
+func (hs *HotspotService) RemoveCoHost(hostID, hotspotID, userID string) (*models.Hotspot, error) {
+	hotspot, err := hs.hostedHotspot(hostID, hotspotID)
+	if err != nil {
+		return nil, err
+	}
+	if !containsString(hotspot.CoHosts, userID) {
+		return hotspot, nil
+	}
+	hotspot.CoHosts = removeString(hotspot.CoHosts, userID)
+	hotspot.UpdatedAt = clock.Now()
+
+	if hs.isTestMode() {
+		updated, err := hs.updateHotspotMock(hotspot)
+		if err == nil {
+			hs.publishChange(models.DomainEventHotspotUpdated, hostID, updated)
+			hs.recordActivity(updated.ID, hostID, models.ActivityCoHostRemoved, map[string]interface{}{"user_id": userID})
+		}
+		return updated, err
+	}
+
+	// TODO: Implement Firestore update with ArrayRemove on co_hosts
+	return nil, errors.New("firestore implementation needed")
+}
+
+// OfferOwnership offers the hotspot to an attendee. Ownership only moves once they accept;
+// a new offer replaces any pending one.
+func (hs *HotspotService) OfferOwnership(hostID, hotspotID, userID string) (*models.Hotspot, error) {
+	hotspot, err := hs.hostedAttendee(hostID, hotspotID, userID)
+	if err != nil {
+		return nil, err
+	}
+	now := clock.Now()
+	hotspot.OwnershipOffer = &models.OwnershipOffer{
+		ToUserID:  userID,
+		OfferedBy: hostID,
+		OfferedAt: now,
+		ExpiresAt: now.Add(ownershipOfferTTL),
+	}
+	hotspot.UpdatedAt = now
+
+	if hs.isTestMode() {
+		updated, err := hs.updateHotspotMock(hotspot)
+		if err == nil {
+			hs.publishChange(models.DomainEventHotspotUpdated, hostID, updated)
+			hs.recordActivity(updated.ID, hostID, models.ActivityOwnershipOffered, map[string]interface{}{"to": userID})
+		}
+		return updated, err
+	}
+
+	// TODO: Implement Firestore update of ownership_offer
+	return nil, errors.New("firestore implementation needed")
+}
+
+// CancelOwnershipOffer withdraws the host's pending offer, if any
+func (hs *HotspotService) CancelOwnershipOffer(hostID, hotspotID string) (*models.Hotspot, error) {
+	hotspot, err := hs.hostedHotspot(hostID, hotspotID)
+	if err != nil {
+		return nil, err
+	}
+	if hotspot.OwnershipOffer == nil {
+		return hotspot, nil
+	}
+	return hs.closeOwnershipOffer(hotspot, hostID, "")
+}
+
+// DeclineOwnership turns down an ownership offer made to the user
+func (hs *HotspotService) DeclineOwnership(userID, hotspotID string) (*models.Hotspot, error) {
+	hotspot, err := hs.GetHotspot(hotspotID)
+	if err != nil {
+		return nil, err
+	}
+	if hotspot.OwnershipOffer == nil || hotspot.OwnershipOffer.ToUserID != userID {
+		return nil, ErrNoOwnershipOffer
+	}
+	return hs.closeOwnershipOffer(hotspot, userID, models.ActivityOwnershipDeclined)
+}
+
+// AcceptOwnership makes the user the host of a hotspot they were offered. The previous
+// host stays on as a co-host if they still attend.
+func (hs *HotspotService) AcceptOwnership(userID, hotspotID string) (*models.Hotspot, error) {
+	hotspot, err := hs.GetHotspot(hotspotID)
+	if err != nil {
+		return nil, err
+	}
+	offer := hotspot.OwnershipOffer
+	if offer == nil || offer.ToUserID != userID || offer.OfferedBy != hotspot.CreatedBy {
+		return nil, ErrNoOwnershipOffer
+	}
+	if !offer.ExpiresAt.After(clock.Now()) {
+		return nil, ErrOwnershipOfferExpired
+	}
+	if !containsString(hotspot.Attendees, userID) {
+		return nil, errors.New("user is not in this hotspot")
+	}
+	newOwner, err := hs.userService.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	previousOwner := hotspot.CreatedBy
+	handOver(hotspot, newOwner)
+	if containsString(hotspot.Attendees, previousOwner) && !containsString(hotspot.CoHosts, previousOwner) {
+		hotspot.CoHosts = append(hotspot.CoHosts, previousOwner)
+	}
+	hotspot.UpdatedAt = clock.Now()
+
+	if hs.isTestMode() {
+		updated, err := hs.updateHotspotMock(hotspot)
+		if err == nil {
+			hs.publishChange(models.DomainEventHotspotUpdated, userID, updated)
+			hs.publishChange(models.DomainEventOwnershipTransferred, previousOwner, updated)
+			hs.recordActivity(updated.ID, userID, models.ActivityOwnershipTransferred, map[string]interface{}{"from": previousOwner, "to": userID})
+		}
+		return updated, err
+	}
+
+	// TODO: Implement Firestore update in a transaction that re-checks the offer
+	return nil, errors.New("firestore implementation needed")
+}
+
+// closeOwnershipOffer clears the pending offer, recording action when one is given
+func (hs *HotspotService) closeOwnershipOffer(hotspot *models.Hotspot, actorID, action string) (*models.Hotspot, error) {
+	hotspot.OwnershipOffer = nil
+	hotspot.UpdatedAt = clock.Now()
+
+	if hs.isTestMode() {
+		updated, err := hs.updateHotspotMock(hotspot)
+		if err == nil {
+			hs.publishChange(models.DomainEventHotspotUpdated, actorID, updated)
+			if action != "" {
+				hs.recordActivity(updated.ID, actorID, action, nil)
+			}
+		}
+		return updated, err
+	}
+
+	// TODO: Implement Firestore update clearing ownership_offer
+	return nil, errors.New("firestore implementation needed")
+}
+
+// hostedHotspot loads a hotspot and checks that userID hosts it
+func (hs *HotspotService) hostedHotspot(userID, hotspotID string) (*models.Hotspot, error) {
+	hotspot, err := hs.GetHotspot(hotspotID)
+	if err != nil {
+		return nil, err
+	}
+	if hotspot.CreatedBy != userID {
+		return nil, errors.New("only the host can manage co-hosts and ownership")
+	}
+	return hotspot, nil
+}
+
+// hostedAttendee loads a hotspot hosted by hostID and checks that userID is another attendee
+func (hs *HotspotService) hostedAttendee(hostID, hotspotID, userID string) (*models.Hotspot, error) {
+	hotspot, err := hs.hostedHotspot(hostID, hotspotID)
+	if err != nil {
+		return nil, err
+	}
+	if userID == hostID {
+		return nil, errors.New("you already host this hotspot")
+	}
+	if !containsString(hotspot.Attendees, userID) {
+		return nil, errors.New("user is not in this hotspot")
+	}
+	return hotspot, nil
+}
+
+// successorFor picks who takes over when the host leaves: the first co-host still attending,
+// then the earliest attendee who checked in, then the earliest attendee to join
+func successorFor(hotspot *models.Hotspot) string {
+	for _, id := range hotspot.CoHosts {
+		if containsString(hotspot.Attendees, id) {
+			return id
+		}
+	}
+	for _, id := range hotspot.Attendees {
+		if containsString(hotspot.CheckedIn, id) {
+			return id
+		}
+	}
+	if len(hotspot.Attendees) > 0 {
+		return hotspot.Attendees[0]
+	}
+	return ""
+}
+
+// handOver makes newOwner the host, dropping them from co-hosts and clearing any pending offer
+func handOver(hotspot *models.Hotspot, newOwner *models.User) {
+	hotspot.CreatedBy = newOwner.ID
+	hotspot.CreatedByNickname = newOwner.Nickname
+	hotspot.CoHosts = removeString(hotspot.CoHosts, newOwner.ID)
+	hotspot.OwnershipOffer = nil
+}
+
+// removeString returns values without any occurrence of value
+func removeString(values []string, value string) []string {
+	result := values[:0:0]
+	for _, v := range values {
+		if v != value {
+			result = append(result, v)
+		}
+	}
+	return result
+}
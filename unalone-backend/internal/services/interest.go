@@ -0,0 +1,288 @@
+// Interest taxonomy: the curated catalog, suggestions and mapping free text onto canonical interests
+package services
+
+import (
+	"sort"
+	"strings"
+
+	"unalone-backend/internal/i18n"
+	"unalone-backend/internal/models"
+)
+
+const (
+	// MaxProfileInterests is how many interests a profile keeps
+	MaxProfileInterests = 10
+	// defaultInterestSuggestions is how many suggestions are returned when no limit is given
+	defaultInterestSuggestions = 10
+)
+
+// catalogInterest is one curated interest; Aliases are extra spellings that map onto it
+type catalogInterest struct {
+	ID       string
+	Category string
+	Labels   map[string]string // Language -> label
+	Aliases  []string
+}
+
+// interestCategories lists catalog categories in display order
+var interestCategories = []struct {
+	ID     string
+	Labels map[string]string
+}{
+	{"sports", map[string]string{i18n.English: "Sports", i18n.Hindi: "खेल"}},
+	{"outdoors", map[string]string{i18n.English: "Outdoors", i18n.Hindi: "आउटडोर"}},
+	{"games", map[string]string{i18n.English: "Games", i18n.Hindi: "गेम्स"}},
+	{"arts", map[string]string{i18n.English: "Arts & Culture", i18n.Hindi: "कला और संस्कृति"}},
+	{"music", map[string]string{i18n.English: "Music", i18n.Hindi: "संगीत"}},
+	{"food", map[string]string{i18n.English: "Food & Drink", i18n.Hindi: "खान-पान"}},
+	{"learning", map[string]string{i18n.English: "Learning & Tech", i18n.Hindi: "सीखना और तकनीक"}},
+	{"wellness", map[string]string{i18n.English: "Wellness", i18n.Hindi: "सेहत"}},
+}
+
+// interestCatalog is the curated interest list; IDs are stored on profiles, so never rename one
+var interestCatalog = []catalogInterest{
+	{"cricket", "sports", map[string]string{i18n.English: "Cricket", i18n.Hindi: "क्रिकेट"}, nil},
+	{"football", "sports", map[string]string{i18n.English: "Football", i18n.Hindi: "फ़ुटबॉल"}, []string{"soccer"}},
+	{"badminton", "sports", map[string]string{i18n.English: "Badminton", i18n.Hindi: "बैडमिंटन"}, nil},
+	{"basketball", "sports", map[string]string{i18n.English: "Basketball", i18n.Hindi: "बास्केटबॉल"}, []string{"hoops"}},
+	{"running", "sports", map[string]string{i18n.English: "Running", i18n.Hindi: "दौड़"}, []string{"jogging", "marathon"}},
+	{"cycling", "sports", map[string]string{i18n.English: "Cycling", i18n.Hindi: "साइकिलिंग"}, []string{"biking", "bicycle"}},
+	{"swimming", "sports", map[string]string{i18n.English: "Swimming", i18n.Hindi: "तैराकी"}, nil},
+	{"hiking", "outdoors", map[string]string{i18n.English: "Hiking", i18n.Hindi: "हाइकिंग"}, []string{"trekking", "trek"}},
+	{"camping", "outdoors", map[string]string{i18n.English: "Camping", i18n.Hindi: "कैंपिंग"}, nil},
+	{"photography", "outdoors", map[string]string{i18n.English: "Photography", i18n.Hindi: "फ़ोटोग्राफ़ी"}, []string{"photos", "camera"}},
+	{"travel", "outdoors", map[string]string{i18n.English: "Travel", i18n.Hindi: "यात्रा"}, []string{"travelling", "traveling", "backpacking"}},
+	{"board-games", "games", map[string]string{i18n.English: "Board games", i18n.Hindi: "बोर्ड गेम्स"}, []string{"boardgames", "tabletop"}},
+	{"video-games", "games", map[string]string{i18n.English: "Video games", i18n.Hindi: "वीडियो गेम्स"}, []string{"gaming", "videogames", "esports"}},
+	{"chess", "games", map[string]string{i18n.English: "Chess", i18n.Hindi: "शतरंज"}, nil},
+	{"quiz", "games", map[string]string{i18n.English: "Quizzes & trivia", i18n.Hindi: "क्विज़"}, []string{"trivia", "pub-quiz"}},
+	{"painting", "arts", map[string]string{i18n.English: "Painting", i18n.Hindi: "चित्रकारी"}, []string{"drawing", "art"}},
+	{"theatre", "arts", map[string]string{i18n.English: "Theatre", i18n.Hindi: "रंगमंच"}, []string{"theater", "drama", "improv"}},
+	{"movies", "arts", map[string]string{i18n.English: "Movies", i18n.Hindi: "फ़िल्में"}, []string{"films", "cinema", "film"}},
+	{"reading", "arts", map[string]string{i18n.English: "Reading & books", i18n.Hindi: "पढ़ना"}, []string{"books", "book-club"}},
+	{"writing", "arts", map[string]string{i18n.English: "Writing", i18n.Hindi: "लेखन"}, []string{"poetry"}},
+	{"live-music", "music", map[string]string{i18n.English: "Live music", i18n.Hindi: "लाइव संगीत"}, []string{"concerts", "gigs"}},
+	{"singing", "music", map[string]string{i18n.English: "Singing", i18n.Hindi: "गायन"}, []string{"karaoke"}},
+	{"guitar", "music", map[string]string{i18n.English: "Guitar", i18n.Hindi: "गिटार"}, nil},
+	{"dancing", "music", map[string]string{i18n.English: "Dancing", i18n.Hindi: "नृत्य"}, []string{"dance", "salsa"}},
+	{"cooking", "food", map[string]string{i18n.English: "Cooking", i18n.Hindi: "खाना बनाना"}, []string{"baking"}},
+	{"coffee", "food", map[string]string{i18n.English: "Coffee", i18n.Hindi: "कॉफ़ी"}, []string{"cafes", "cafe"}},
+	{"street-food", "food", map[string]string{i18n.English: "Street food", i18n.Hindi: "स्ट्रीट फ़ूड"}, []string{"foodie", "food"}},
+	{"technology", "learning", map[string]string{i18n.English: "Technology", i18n.Hindi: "तकनीक"}, []string{"tech", "gadgets"}},
+	{"coding", "learning", map[string]string{i18n.English: "Coding", i18n.Hindi: "कोडिंग"}, []string{"programming", "software"}},
+	{"startups", "learning", map[string]string{i18n.English: "Startups", i18n.Hindi: "स्टार्टअप"}, []string{"entrepreneurship"}},
+	{"languages", "learning", map[string]string{i18n.English: "Languages", i18n.Hindi: "भाषाएँ"}, []string{"language-exchange"}},
+	{"yoga", "wellness", map[string]string{i18n.English: "Yoga", i18n.Hindi: "योग"}, nil},
+	{"meditation", "wellness", map[string]string{i18n.English: "Meditation", i18n.Hindi: "ध्यान"}, []string{"mindfulness"}},
+	{"fitness", "wellness", map[string]string{i18n.English: "Fitness", i18n.Hindi: "फ़िटनेस"}, []string{"gym", "workout"}},
+	{"volunteering", "wellness", map[string]string{i18n.English: "Volunteering", i18n.Hindi: "स्वयंसेवा"}, []string{"volunteer", "charity"}},
+}
+
+// interestIndex maps every ID, alias and label (slugified, in any language) to its catalog entry
+var interestIndex = buildInterestIndex()
+
+func buildInterestIndex() map[string]*catalogInterest {
+	index := make(map[string]*catalogInterest)
+	for i := range interestCatalog {
+		interest := &interestCatalog[i]
+		for _, key := range interestKeys(interest) {
+			if _, taken := index[key]; !taken {
+				index[key] = interest
+			}
+		}
+	}
+	return index
+}
+
+// interestKeys returns the normalized spellings that name an interest
+func interestKeys(interest *catalogInterest) []string {
+	keys := []string{interest.ID}
+	for _, alias := range interest.Aliases {
+		keys = append(keys, normalizeInterest(alias))
+	}
+	for _, label := range interest.Labels {
+		keys = append(keys, normalizeInterest(label))
+	}
+	return keys
+}
+
+// normalizeInterest lowercases free text and joins words with hyphens; Devanagari is compared as typed
+func normalizeInterest(text string) string {
+	text = strings.ToLower(strings.Join(strings.Fields(text), " "))
+	if slug := slugifyTag(text); slug != "" && isASCII(text) {
+		return slug
+	}
+	return text
+}
+
+// ListInterestCatalog returns the catalog grouped by category with labels in lang
+func ListInterestCatalog(lang string) []models.InterestCategory {
+	categories := make([]models.InterestCategory, 0, len(interestCategories))
+	for _, category := range interestCategories {
+		group := models.InterestCategory{ID: category.ID, Label: localizedLabel(category.Labels, lang), Interests: []models.InterestLabel{}}
+		for i := range interestCatalog {
+			if interestCatalog[i].Category == category.ID {
+				group.Interests = append(group.Interests, interestLabel(&interestCatalog[i], lang))
+			}
+		}
+		categories = append(categories, group)
+	}
+	return categories
+}
+
+// SuggestInterests ranks catalog interests against typed text: exact, then prefix, then
+// substring matches on any label or alias, then close misspellings
+func SuggestInterests(query, lang string, limit int) []models.InterestSuggestion {
+	if limit <= 0 {
+		limit = defaultInterestSuggestions
+	}
+	query = normalizeInterest(query)
+	if query == "" {
+		return []models.InterestSuggestion{}
+	}
+
+	type ranked struct {
+		interest *catalogInterest
+		rank     int
+		match    string
+	}
+	var matches []ranked
+	for i := range interestCatalog {
+		interest := &interestCatalog[i]
+		best := ranked{interest: interest, rank: -1}
+		for _, key := range interestKeys(interest) {
+			var rank int
+			var match string
+			switch {
+			case key == query:
+				rank, match = 0, "exact"
+			case strings.HasPrefix(key, query):
+				rank, match = 1, "prefix"
+			case strings.Contains(key, query):
+				rank, match = 2, "contains"
+			default:
+				distance := editDistance(key, query)
+				if distance > fuzzyInterestDistance(query) {
+					continue
+				}
+				rank, match = 2+distance, "fuzzy"
+			}
+			if best.rank == -1 || rank < best.rank {
+				best.rank, best.match = rank, match
+			}
+		}
+		if best.rank != -1 {
+			matches = append(matches, best)
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].rank < matches[j].rank })
+
+	suggestions := make([]models.InterestSuggestion, 0, min(limit, len(matches)))
+	for _, m := range matches {
+		if len(suggestions) == limit {
+			break
+		}
+		suggestions = append(suggestions, models.InterestSuggestion{InterestLabel: interestLabel(m.interest, lang), Match: m.match})
+	}
+	return suggestions
+}
+
+// CanonicalizeInterests maps free-text interests onto catalog IDs, correcting small typos, and
+// keeps anything unrecognized as a normalized custom interest. Duplicates are dropped.
+func CanonicalizeInterests(raw []string) []string {
+	result := make([]string, 0, len(raw))
+	seen := make(map[string]bool, len(raw))
+	for _, text := range raw {
+		interest := canonicalInterest(text)
+		if interest == "" || seen[interest] {
+			continue
+		}
+		seen[interest] = true
+		result = append(result, interest)
+		if len(result) == MaxProfileInterests {
+			break
+		}
+	}
+	return result
+}
+
+// canonicalInterest returns the catalog ID text names, or its normalized form when nothing is close
+func canonicalInterest(text string) string {
+	key := normalizeInterest(text)
+	if key == "" {
+		return ""
+	}
+	if interest, ok := interestIndex[key]; ok {
+		return interest.ID
+	}
+
+	var best *catalogInterest
+	bestDistance := fuzzyInterestDistance(key) + 1
+	for indexed, interest := range interestIndex {
+		if d := editDistance(indexed, key); d < bestDistance || (d == bestDistance && best != nil && interest.ID < best.ID) {
+			best, bestDistance = interest, d
+		}
+	}
+	if best != nil && bestDistance <= fuzzyInterestDistance(key) {
+		return best.ID
+	}
+	if len(key) > maxTagLength {
+		key = strings.TrimRight(truncateUTF8(key, maxTagLength), "-")
+	}
+	return key
+}
+
+// fuzzyInterestDistance is how many edits still count as a misspelling; short words must match exactly
+func fuzzyInterestDistance(key string) int {
+	switch n := len([]rune(key)); {
+	case n < 4:
+		return 0
+	case n < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// editDistance is the Levenshtein distance between two strings, by rune
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+func interestLabel(interest *catalogInterest, lang string) models.InterestLabel {
+	return models.InterestLabel{ID: interest.ID, Label: localizedLabel(interest.Labels, lang), Category: interest.Category}
+}
+
+// localizedLabel returns the label in lang, falling back to the default language
+func localizedLabel(labels map[string]string, lang string) string {
+	if label, ok := labels[lang]; ok {
+		return label
+	}
+	return labels[i18n.DefaultLanguage]
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,237 @@
+// Job queue storage in Redis, shared by every instance, and the queue's configuration
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"unalone-backend/internal/jobs"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	// defaultJobWorkers is how many jobs an instance runs at once unless JOB_WORKERS says otherwise
+	defaultJobWorkers = 4
+	// JobShutdownGrace is how long shutdown waits for running jobs before cancelling them
+	JobShutdownGrace = 20 * time.Second
+
+	jobDataKey    = "jobs:data"    // job ID -> job JSON
+	jobReadyKey   = "jobs:ready"   // Job IDs waiting for a worker, pushed left and claimed right
+	jobDelayedKey = "jobs:delayed" // Job ID scored by run time in Unix milliseconds
+	jobLeasedKey  = "jobs:leased"  // Job ID scored by lease expiry in Unix milliseconds
+	jobDeadKey    = "jobs:dead"    // Dead-lettered job IDs, newest first
+)
+
+// NewJobQueue creates the background job queue. Jobs go through Redis when it is available so
+// any instance can run them and they survive restarts; otherwise they stay in this process.
+// JOB_WORKERS sets how many jobs this instance runs at once (default 4); 0 only enqueues.
+func NewJobQueue(rs *RedisService) *jobs.Queue {
+	workers := defaultJobWorkers
+	if raw := strings.TrimSpace(os.Getenv("JOB_WORKERS")); raw != "" {
+		if n, err := strconv.Atoi(raw); err == nil && n >= 0 {
+			workers = n
+		} else {
+			log.Printf("Ignoring invalid JOB_WORKERS=%q", raw)
+		}
+	}
+	var store jobs.Store = jobs.NewMemoryStore()
+	if rs.IsAvailable() {
+		store = &RedisJobStore{redisService: rs}
+	}
+	return jobs.New(store, workers)
+}
+
+// RedisJobStore keeps jobs in Redis. Claiming, promoting and dead-lettering are Lua scripts
+// so a job is never in two places, whichever instance touches it.
+type RedisJobStore struct {
+	redisService *RedisService
+}
+
+// claimJobScript moves the oldest ready job to the leased set and returns it
+var claimJobScript = redis.NewScript(`
+local id = redis.call("RPOP", KEYS[1])
+if not id then return false end
+local body = redis.call("HGET", KEYS[3], id)
+if not body then return false end
+redis.call("ZADD", KEYS[2], ARGV[1], id)
+return body`)
+
+// promoteJobsScript readies delayed jobs that are due and leased jobs whose lease ran out
+var promoteJobsScript = redis.NewScript(`
+local moved = 0
+for i = 2, 3 do
+	local ids = redis.call("ZRANGEBYSCORE", KEYS[i], "-inf", ARGV[1], "LIMIT", 0, 100)
+	for _, id in ipairs(ids) do
+		redis.call("ZREM", KEYS[i], id)
+		redis.call("LPUSH", KEYS[1], id)
+		moved = moved + 1
+	end
+end
+return moved`)
+
+// buryJobScript dead-letters a leased job and drops the oldest dead letters beyond the cap
+var buryJobScript = redis.NewScript(`
+redis.call("HSET", KEYS[1], ARGV[1], ARGV[2])
+redis.call("ZREM", KEYS[2], ARGV[1])
+redis.call("LPUSH", KEYS[3], ARGV[1])
+local dropped = redis.call("LRANGE", KEYS[3], ARGV[3], -1)
+if #dropped > 0 then
+	redis.call("HDEL", KEYS[1], unpack(dropped))
+	redis.call("LTRIM", KEYS[3], 0, tonumber(ARGV[3]) - 1)
+end
+return 1`)
+
+// reviveJobScript moves a dead letter back to the ready list
+var reviveJobScript = redis.NewScript(`
+if redis.call("LREM", KEYS[3], 1, ARGV[1]) == 0 then return 0 end
+redis.call("HSET", KEYS[1], ARGV[1], ARGV[2])
+redis.call("LPUSH", KEYS[2], ARGV[1])
+return 1`)
+
+// Backend returns "redis"
+func (s *RedisJobStore) Backend() string {
+	return "redis"
+}
+
+func (s *RedisJobStore) Push(ctx context.Context, job *jobs.Job) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	_, err = s.redisService.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, jobDataKey, job.ID, data)
+		if job.RunAt.After(time.Now()) {
+			pipe.ZAdd(ctx, jobDelayedKey, &redis.Z{Score: float64(job.RunAt.UnixMilli()), Member: job.ID})
+		} else {
+			pipe.LPush(ctx, jobReadyKey, job.ID)
+		}
+		return nil
+	})
+	return err
+}
+
+func (s *RedisJobStore) Claim(ctx context.Context, lease time.Duration) (*jobs.Job, error) {
+	body, err := claimJobScript.Run(ctx, s.redisService.client, []string{jobReadyKey, jobLeasedKey, jobDataKey},
+		time.Now().Add(lease).UnixMilli()).Text()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var job jobs.Job
+	if err := json.Unmarshal([]byte(body), &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (s *RedisJobStore) Ack(ctx context.Context, job *jobs.Job) error {
+	_, err := s.redisService.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRem(ctx, jobLeasedKey, job.ID)
+		pipe.HDel(ctx, jobDataKey, job.ID)
+		return nil
+	})
+	return err
+}
+
+func (s *RedisJobStore) Retry(ctx context.Context, job *jobs.Job) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	_, err = s.redisService.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, jobDataKey, job.ID, data)
+		pipe.ZRem(ctx, jobLeasedKey, job.ID)
+		pipe.ZAdd(ctx, jobDelayedKey, &redis.Z{Score: float64(job.RunAt.UnixMilli()), Member: job.ID})
+		return nil
+	})
+	return err
+}
+
+func (s *RedisJobStore) Bury(ctx context.Context, job *jobs.Job) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return buryJobScript.Run(ctx, s.redisService.client, []string{jobDataKey, jobLeasedKey, jobDeadKey},
+		job.ID, data, jobs.MaxDeadLetters).Err()
+}
+
+func (s *RedisJobStore) Promote(ctx context.Context, now time.Time) (int, error) {
+	return promoteJobsScript.Run(ctx, s.redisService.client, []string{jobReadyKey, jobDelayedKey, jobLeasedKey},
+		now.UnixMilli()).Int()
+}
+
+func (s *RedisJobStore) Counts(ctx context.Context) (jobs.Counts, error) {
+	var ready, delayed, leased, dead *redis.IntCmd
+	_, err := s.redisService.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		ready = pipe.LLen(ctx, jobReadyKey)
+		delayed = pipe.ZCard(ctx, jobDelayedKey)
+		leased = pipe.ZCard(ctx, jobLeasedKey)
+		dead = pipe.LLen(ctx, jobDeadKey)
+		return nil
+	})
+	if err != nil {
+		return jobs.Counts{}, err
+	}
+	return jobs.Counts{Ready: ready.Val(), Delayed: delayed.Val(), Leased: leased.Val(), Dead: dead.Val()}, nil
+}
+
+func (s *RedisJobStore) DeadLetters(ctx context.Context, limit int) ([]*jobs.Job, error) {
+	ids, err := s.redisService.client.LRange(ctx, jobDeadKey, 0, int64(limit)-1).Result()
+	if err != nil || len(ids) == 0 {
+		return []*jobs.Job{}, err
+	}
+	bodies, err := s.redisService.client.HMGet(ctx, jobDataKey, ids...).Result()
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*jobs.Job, 0, len(bodies))
+	for _, body := range bodies {
+		raw, ok := body.(string)
+		if !ok {
+			continue
+		}
+		var job jobs.Job
+		if json.Unmarshal([]byte(raw), &job) == nil {
+			list = append(list, &job)
+		}
+	}
+	return list, nil
+}
+
+func (s *RedisJobStore) Revive(ctx context.Context, id string) (*jobs.Job, error) {
+	raw, err := s.redisService.client.HGet(ctx, jobDataKey, id).Result()
+	if err == redis.Nil {
+		return nil, jobs.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var job jobs.Job
+	if err := json.Unmarshal([]byte(raw), &job); err != nil {
+		return nil, err
+	}
+	job.Attempt = 0
+	job.FailedAt = nil
+	job.RunAt = time.Now()
+	data, err := json.Marshal(&job)
+	if err != nil {
+		return nil, err
+	}
+	revived, err := reviveJobScript.Run(ctx, s.redisService.client, []string{jobDataKey, jobReadyKey, jobDeadKey}, id, data).Int()
+	if err != nil {
+		return nil, err
+	}
+	if revived == 0 {
+		return nil, jobs.ErrNotFound
+	}
+	return &job, nil
+}
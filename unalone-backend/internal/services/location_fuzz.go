@@ -0,0 +1,134 @@
+// Location fuzzing so other users only ever see approximate user positions
+package services
+
+import (
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"unalone-backend/internal/models"
+)
+
+const (
+	// minLocationGridMeters keeps misconfiguration from exposing exact positions
+	minLocationGridMeters = 10
+	metersPerDegreeLat    = 111320.0
+)
+
+// LocationFuzzer snaps user coordinates to a grid before they are shown to anyone else.
+// The cell size follows the owner's profile visibility; exact coordinates stay server-side
+// for distance math. Snapping is deterministic, so repeated updates from the same spot
+// cannot be averaged back to the real position the way random noise can.
+type LocationFuzzer struct {
+	userService    *UserService
+	profileService *ProfileService
+
+	publicMeters  int // Anyone viewing a public profile, and friends viewing a friends-only one
+	friendsMeters int // Non-friends viewing a friends-only profile
+	privateMeters int // Everyone viewing a private profile
+}
+
+// NewLocationFuzzer creates a location fuzzer with grid sizes from the environment
+func NewLocationFuzzer(us *UserService, ps *ProfileService) *LocationFuzzer {
+	return &LocationFuzzer{
+		userService:    us,
+		profileService: ps,
+		publicMeters:   envGridMeters("LOCATION_GRID_PUBLIC_METERS", 100),
+		friendsMeters:  envGridMeters("LOCATION_GRID_FRIENDS_METERS", 500),
+		privateMeters:  envGridMeters("LOCATION_GRID_PRIVATE_METERS", 1000),
+	}
+}
+
+// envGridMeters reads a grid size in meters, never going below minLocationGridMeters
+func envGridMeters(key string, fallback int) int {
+	n, err := strconv.Atoi(strings.TrimSpace(os.Getenv(key)))
+	if err != nil || n <= 0 {
+		return fallback
+	}
+	return max(n, minLocationGridMeters)
+}
+
+// LocationPolicy is how one user's location may be shown to each viewer
+type LocationPolicy struct {
+	ownerID    string
+	visibility string
+	friends    map[string]bool
+	fuzzer     *LocationFuzzer
+}
+
+// PolicyFor loads the owner's visibility and friends once so it can be applied to many viewers.
+// When settings cannot be read the owner is treated as private.
+func (lf *LocationFuzzer) PolicyFor(ownerID string) *LocationPolicy {
+	policy := &LocationPolicy{ownerID: ownerID, visibility: "private", friends: map[string]bool{}, fuzzer: lf}
+	if settings, err := lf.profileService.GetUserSettings(ownerID); err == nil && settings.ProfileVisibility != "" {
+		policy.visibility = settings.ProfileVisibility
+	}
+	if policy.visibility == "friends" {
+		if owner, err := lf.userService.GetUserByID(ownerID); err == nil {
+			for _, id := range owner.Friends {
+				policy.friends[id] = true
+			}
+		}
+	}
+	return policy
+}
+
+// GridMeters is the cell size used for the viewer; 0 means the exact position (the owner themself)
+func (p *LocationPolicy) GridMeters(viewerID string) int {
+	switch {
+	case viewerID == p.ownerID:
+		return 0
+	case p.visibility == "public":
+		return p.fuzzer.publicMeters
+	case p.visibility == "friends" && p.friends[viewerID]:
+		return p.fuzzer.publicMeters
+	case p.visibility == "friends":
+		return p.fuzzer.friendsMeters
+	default:
+		return p.fuzzer.privateMeters
+	}
+}
+
+// Apply returns the coordinates the viewer may see and the grid size they were snapped to
+func (p *LocationPolicy) Apply(viewerID string, latitude, longitude float64) (float64, float64, int) {
+	meters := p.GridMeters(viewerID)
+	if meters == 0 {
+		return latitude, longitude, 0
+	}
+	lat, lng := snapToGrid(latitude, longitude, float64(meters))
+	return lat, lng, meters
+}
+
+// Share returns a copy of a live location share as the viewer may see it
+func (p *LocationPolicy) Share(viewerID string, share *models.LocationShare) *models.LocationShare {
+	copied := *share
+	if share.Latitude != nil && share.Longitude != nil {
+		lat, lng, meters := p.Apply(viewerID, *share.Latitude, *share.Longitude)
+		copied.Latitude, copied.Longitude, copied.AccuracyMeters = &lat, &lng, meters
+	}
+	return &copied
+}
+
+// snapToGrid moves a point to the center of its grid cell. Longitude cells widen with
+// latitude so cells stay roughly square, measured at the snapped latitude.
+func snapToGrid(latitude, longitude, meters float64) (float64, float64) {
+	latStep := meters / metersPerDegreeLat
+	lat := (math.Floor(latitude/latStep) + 0.5) * latStep
+	lat = math.Max(-90, math.Min(90, lat))
+
+	lngStep := 360.0
+	if cos := math.Cos(lat * math.Pi / 180); cos > 1e-6 {
+		lngStep = math.Min(360, meters/(metersPerDegreeLat*cos))
+	}
+	lng := (math.Floor((longitude+180)/lngStep)+0.5)*lngStep - 180
+	if lng > 180 {
+		lng -= 360
+	}
+	return roundCoordinate(lat), roundCoordinate(lng)
+}
+
+// roundCoordinate trims snapped coordinates to about a meter so they do not look precise
+func roundCoordinate(value float64) float64 {
+	return math.Round(value*1e5) / 1e5
+}
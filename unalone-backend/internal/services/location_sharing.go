@@ -0,0 +1,270 @@
+// Location sharing service for live attendee locations during an active hotspot
+package services
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"unalone-backend/internal/models"
+)
+
+const (
+	// locationShareEarlyStart lets attendees start sharing shortly before the scheduled time
+	locationShareEarlyStart = 30 * time.Minute
+)
+
+// LocationSharingService keeps opt-in attendee locations for hotspots that are happening now.
+// Shares are deliberately kept in memory only and vanish once they expire.
+type LocationSharingService struct {
+	hotspotService *HotspotService
+	profileService *ProfileService
+	userService    *UserService
+	fuzzer         *LocationFuzzer
+	consents       *ConsentService
+
+	mu     sync.Mutex
+	shares map[string]map[string]*models.LocationShare // hotspotID -> userID -> share
+	onStop func(*models.LocationShare)
+}
+
+// NewLocationSharingService creates a new location sharing service
+func NewLocationSharingService(hs *HotspotService, ps *ProfileService, us *UserService, lf *LocationFuzzer, cs *ConsentService) *LocationSharingService {
+	return &LocationSharingService{
+		hotspotService: hs,
+		profileService: ps,
+		userService:    us,
+		fuzzer:         lf,
+		consents:       cs,
+		shares:         make(map[string]map[string]*models.LocationShare),
+	}
+}
+
+// OnStop registers a callback invoked whenever a share ends, whether stopped or expired
+func (ls *LocationSharingService) OnStop(fn func(*models.LocationShare)) {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	ls.onStop = fn
+}
+
+// StartSharing opts an attendee in to sharing their location for the given duration,
+// capped at the end of the event
+func (ls *LocationSharingService) StartSharing(userID, hotspotID string, duration time.Duration) (*models.LocationShare, error) {
+	hotspot, err := ls.checkEligible(userID, hotspotID)
+	if err != nil {
+		return nil, err
+	}
+	user, err := ls.userService.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := clock.Now()
+	expiresAt := now.Add(duration)
+	if end := hotspotEndsAt(hotspot); end != nil && end.Before(expiresAt) {
+		expiresAt = *end
+	}
+
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	share := &models.LocationShare{
+		HotspotID: hotspotID,
+		UserID:    userID,
+		Nickname:  user.Nickname,
+		StartedAt: now,
+		ExpiresAt: expiresAt,
+	}
+	// Keep the last known position when an attendee extends an existing share
+	if existing, ok := ls.shares[hotspotID][userID]; ok {
+		share.Latitude, share.Longitude, share.UpdatedAt = existing.Latitude, existing.Longitude, existing.UpdatedAt
+	}
+	if ls.shares[hotspotID] == nil {
+		ls.shares[hotspotID] = make(map[string]*models.LocationShare)
+	}
+	ls.shares[hotspotID][userID] = share
+
+	copied := *share
+	return &copied, nil
+}
+
+// UpdateLocation records a new position for an attendee who is currently sharing
+func (ls *LocationSharingService) UpdateLocation(userID, hotspotID string, latitude, longitude float64) (*models.LocationShare, error) {
+	if latitude < -90 || latitude > 90 || longitude < -180 || longitude > 180 {
+		return nil, errors.New("invalid coordinates")
+	}
+
+	ls.mu.Lock()
+	share, ok := ls.shares[hotspotID][userID]
+	ls.mu.Unlock()
+	if !ok || !share.ExpiresAt.After(clock.Now()) {
+		return nil, errors.New("location sharing is not active")
+	}
+
+	// Re-check on every update so leaving the hotspot or turning sharing off takes effect immediately
+	if _, err := ls.checkEligible(userID, hotspotID); err != nil {
+		ls.StopSharing(userID, hotspotID)
+		return nil, err
+	}
+
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	share, ok = ls.shares[hotspotID][userID]
+	if !ok {
+		return nil, errors.New("location sharing is not active")
+	}
+	now := clock.Now()
+	share.Latitude = &latitude
+	share.Longitude = &longitude
+	share.UpdatedAt = &now
+
+	copied := *share
+	return &copied, nil
+}
+
+// StopSharing ends an attendee's share; stopping when not sharing is a no-op
+func (ls *LocationSharingService) StopSharing(userID, hotspotID string) {
+	ls.mu.Lock()
+	share, ok := ls.shares[hotspotID][userID]
+	if ok {
+		ls.removeLocked(hotspotID, userID)
+	}
+	onStop := ls.onStop
+	ls.mu.Unlock()
+
+	if ok && onStop != nil {
+		onStop(share)
+	}
+}
+
+// PolicyFor returns how the owner's shared positions may be shown to other attendees
+func (ls *LocationSharingService) PolicyFor(ownerID string) *LocationPolicy {
+	return ls.fuzzer.PolicyFor(ownerID)
+}
+
+// ActiveSharesFor returns the active shares for a hotspot with positions fuzzed for the viewer
+func (ls *LocationSharingService) ActiveSharesFor(viewerID, hotspotID string) []*models.LocationShare {
+	shares := ls.ActiveShares(hotspotID)
+	for i, share := range shares {
+		shares[i] = ls.PolicyFor(share.UserID).Share(viewerID, share)
+	}
+	return shares
+}
+
+// ActiveShares returns the unexpired shares with a known position for a hotspot,
+// leaving out anyone who has since left it. Positions are exact; use ActiveSharesFor
+// for anything sent to clients.
+func (ls *LocationSharingService) ActiveShares(hotspotID string) []*models.LocationShare {
+	attendees := make(map[string]bool)
+	if hotspot, err := ls.hotspotService.GetHotspot(hotspotID); err == nil {
+		for _, id := range hotspot.Attendees {
+			attendees[id] = true
+		}
+	}
+
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	now := clock.Now()
+	result := []*models.LocationShare{}
+	for _, share := range ls.shares[hotspotID] {
+		if share.Latitude == nil || !share.ExpiresAt.After(now) || !attendees[share.UserID] {
+			continue
+		}
+		copied := *share
+		result = append(result, &copied)
+	}
+	return result
+}
+
+// StopAllFor ends every share of a user, e.g. when they revoke location consent
+func (ls *LocationSharingService) StopAllFor(userID string) {
+	ls.mu.Lock()
+	var stopped []*models.LocationShare
+	for hotspotID, users := range ls.shares {
+		if share, ok := users[userID]; ok {
+			stopped = append(stopped, share)
+			ls.removeLocked(hotspotID, userID)
+		}
+	}
+	onStop := ls.onStop
+	ls.mu.Unlock()
+
+	if onStop != nil {
+		for _, share := range stopped {
+			onStop(share)
+		}
+	}
+}
+
+// ExpireShares drops every share that expired before now, so clients are told sharing stopped
+func (ls *LocationSharingService) ExpireShares(now time.Time) {
+	ls.mu.Lock()
+	var expired []*models.LocationShare
+	for hotspotID, users := range ls.shares {
+		for userID, share := range users {
+			if !share.ExpiresAt.After(now) {
+				expired = append(expired, share)
+				ls.removeLocked(hotspotID, userID)
+			}
+		}
+	}
+	onStop := ls.onStop
+	ls.mu.Unlock()
+
+	if onStop != nil {
+		for _, share := range expired {
+			onStop(share)
+		}
+	}
+}
+
+// removeLocked deletes a share; callers must hold ls.mu
+func (ls *LocationSharingService) removeLocked(hotspotID, userID string) {
+	delete(ls.shares[hotspotID], userID)
+	if len(ls.shares[hotspotID]) == 0 {
+		delete(ls.shares, hotspotID)
+	}
+}
+
+// checkEligible verifies the user attends a hotspot that is happening now and allows location sharing
+func (ls *LocationSharingService) checkEligible(userID, hotspotID string) (*models.Hotspot, error) {
+	hotspot, err := ls.hotspotService.GetHotspot(hotspotID)
+	if err != nil {
+		return nil, err
+	}
+	if !hotspot.IsActive {
+		return nil, errors.New("hotspot is not active")
+	}
+
+	isMember := false
+	for _, id := range hotspot.Attendees {
+		if id == userID {
+			isMember = true
+			break
+		}
+	}
+	if !isMember {
+		return nil, errors.New("user is not a member of this hotspot")
+	}
+
+	now := clock.Now()
+	if hotspot.ScheduledTime != nil && now.Before(hotspot.ScheduledTime.Add(-locationShareEarlyStart)) {
+		return nil, errors.New("hotspot has not started yet")
+	}
+	if end := hotspotEndsAt(hotspot); end != nil && !now.Before(*end) {
+		return nil, errors.New("hotspot has already ended")
+	}
+
+	settings, err := ls.profileService.GetUserSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !settings.LocationSharing {
+		return nil, errors.New("location sharing is disabled in your settings")
+	}
+	if !ls.consents.Allowed(userID, models.ConsentLocation) {
+		return nil, ErrLocationConsentRequired
+	}
+	return hotspot, nil
+}
@@ -0,0 +1,280 @@
+// Login security: failed attempt tracking, temporary lockouts and new sign-in alerts
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"unalone-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+const (
+	loginFailureWindow = 15 * time.Minute
+	// Failures allowed before a lockout; each further failure doubles it
+	accountLockThreshold = 5
+	ipLockThreshold      = 20
+	loginBaseLockout     = time.Minute
+	loginMaxLockout      = time.Hour
+	maxKnownLoginDevices = 20
+)
+
+// LoginLockedError is returned while an account or IP is locked out after repeated failures
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginLockedError) Error() string {
+	return "too many failed login attempts, please try again later"
+}
+
+// LoginSecurityService slows down password guessing and tells users about sign-ins from new devices
+type LoginSecurityService struct {
+	firestoreService *FirestoreService
+	redisService     *RedisService
+	email            EmailSender
+
+	// Fallback counters when Redis is unavailable (single instance only)
+	mu       sync.Mutex
+	failures map[string][]time.Time
+	locks    map[string]time.Time
+}
+
+// NewLoginSecurityService creates a new login security service
+func NewLoginSecurityService(fs *FirestoreService, rs *RedisService, email EmailSender) *LoginSecurityService {
+	return &LoginSecurityService{
+		firestoreService: fs,
+		redisService:     rs,
+		email:            email,
+		failures:         make(map[string][]time.Time),
+		locks:            make(map[string]time.Time),
+	}
+}
+
+// CheckAllowed returns a *LoginLockedError while the account or the client IP is locked out
+func (ls *LoginSecurityService) CheckAllowed(email, ip string) error {
+	now := clock.Now()
+	var retryAfter time.Duration
+	for _, key := range ls.keys(email, ip) {
+		if until, ok := ls.lockedUntil(key); ok && until.After(now) && until.Sub(now) > retryAfter {
+			retryAfter = until.Sub(now)
+		}
+	}
+	if retryAfter > 0 {
+		return &LoginLockedError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// RecordFailure counts a failed attempt against the account and the IP, locking either
+// once it passes its threshold
+func (ls *LoginSecurityService) RecordFailure(email, ip string) {
+	now := clock.Now()
+	thresholds := []int{accountLockThreshold, ipLockThreshold}
+	for i, key := range ls.keys(email, ip) {
+		count := ls.countFailure(key, now)
+		if count < thresholds[i] {
+			continue
+		}
+		lockout := loginBaseLockout << (count - thresholds[i])
+		if lockout > loginMaxLockout || lockout <= 0 {
+			lockout = loginMaxLockout
+		}
+		ls.lock(key, now.Add(lockout))
+		log.Printf("Login security: %s locked for %s after %d failures", key, lockout, count)
+	}
+}
+
+// RecordSuccess clears the account's failures and emails the user when the sign-in
+// comes from a device or network they have not used before
+func (ls *LoginSecurityService) RecordSuccess(user *models.User, ip, userAgent string) {
+	ls.clear(accountLoginKey(user.Email))
+
+	device := models.LoginDevice{
+		Fingerprint: deviceFingerprint(userAgent),
+		UserAgent:   userAgent,
+		Network:     ipNetwork(ip),
+		LastIP:      ip,
+		LastSeenAt:  clock.Now(),
+	}
+	isNew, err := ls.rememberDevice(user.ID, device)
+	if err != nil {
+		log.Printf("Login security: device lookup failed for %s: %v", user.ID, err)
+		return
+	}
+	if isNew && ls.email != nil {
+		go ls.sendNewSignInAlert(user, device)
+	}
+}
+
+// keys returns the account key followed by the IP key, if the IP is known
+func (ls *LoginSecurityService) keys(email, ip string) []string {
+	keys := []string{accountLoginKey(email)}
+	if ip != "" {
+		keys = append(keys, "login:ip:"+ip)
+	}
+	return keys
+}
+
+func accountLoginKey(email string) string {
+	return "login:account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+// countFailure records a failure and returns the failures in the current window
+func (ls *LoginSecurityService) countFailure(key string, now time.Time) int {
+	if ls.redisService.IsAvailable() {
+		failuresKey := key + ":failures"
+		if err := ls.redisService.RecordWindowEvent(failuresKey, uuid.New().String(), now, loginFailureWindow); err != nil {
+			log.Printf("Login security: failure write failed: %v", err)
+		}
+		count, err := ls.redisService.CountWindowEvents(failuresKey, now.Add(-loginFailureWindow))
+		if err != nil {
+			return 0
+		}
+		return int(count)
+	}
+
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	recent := ls.failures[key][:0]
+	for _, at := range ls.failures[key] {
+		if now.Sub(at) < loginFailureWindow {
+			recent = append(recent, at)
+		}
+	}
+	ls.failures[key] = append(recent, now)
+	return len(ls.failures[key])
+}
+
+func (ls *LoginSecurityService) lock(key string, until time.Time) {
+	if ls.redisService.IsAvailable() {
+		if err := ls.redisService.CacheJSON(key+":lock", until, until.Sub(clock.Now())); err != nil {
+			log.Printf("Login security: lock write failed: %v", err)
+		}
+		return
+	}
+	ls.mu.Lock()
+	ls.locks[key] = until
+	ls.mu.Unlock()
+}
+
+func (ls *LoginSecurityService) lockedUntil(key string) (time.Time, bool) {
+	if ls.redisService.IsAvailable() {
+		var until time.Time
+		found, err := ls.redisService.GetCachedJSON(key+":lock", &until)
+		return until, found && err == nil
+	}
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	until, ok := ls.locks[key]
+	return until, ok
+}
+
+func (ls *LoginSecurityService) clear(key string) {
+	if ls.redisService.IsAvailable() {
+		if err := ls.redisService.Delete(key+":failures", key+":lock"); err != nil {
+			log.Printf("Login security: reset failed: %v", err)
+		}
+		return
+	}
+	ls.mu.Lock()
+	delete(ls.failures, key)
+	delete(ls.locks, key)
+	ls.mu.Unlock()
+}
+
+// PurgeExpired drops fallback counters whose failures all left the window and locks that ended
+func (ls *LoginSecurityService) PurgeExpired() {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	now := clock.Now()
+	for key, times := range ls.failures {
+		if len(times) == 0 || now.Sub(times[len(times)-1]) >= loginFailureWindow {
+			delete(ls.failures, key)
+		}
+	}
+	for key, until := range ls.locks {
+		if !now.Before(until) {
+			delete(ls.locks, key)
+		}
+	}
+}
+
+// rememberDevice stores the device and reports whether its user agent or network is new.
+// A user's first recorded sign-in is not reported.
+func (ls *LoginSecurityService) rememberDevice(userID string, device models.LoginDevice) (bool, error) {
+	if !ls.isTestMode() {
+		// TODO: Read and update users/{id}/login_devices in a transaction
+		return false, errors.New("firestore implementation needed")
+	}
+
+	mockLoginDevicesMu.Lock()
+	defer mockLoginDevicesMu.Unlock()
+	devices := mockLoginDevices[userID]
+	knownDevice, knownNetwork := false, false
+	for _, d := range devices {
+		knownDevice = knownDevice || d.Fingerprint == device.Fingerprint
+		knownNetwork = knownNetwork || d.Network == device.Network
+	}
+	for i, d := range devices {
+		if d.Fingerprint == device.Fingerprint && d.Network == device.Network {
+			devices[i].LastIP, devices[i].LastSeenAt = device.LastIP, device.LastSeenAt
+			return false, nil
+		}
+	}
+
+	device.FirstSeenAt = device.LastSeenAt
+	devices = append(devices, &device)
+	if len(devices) > maxKnownLoginDevices {
+		devices = devices[len(devices)-maxKnownLoginDevices:]
+	}
+	mockLoginDevices[userID] = devices
+	return len(devices) > 1 && !(knownDevice && knownNetwork), nil
+}
+
+func (ls *LoginSecurityService) sendNewSignInAlert(user *models.User, device models.LoginDevice) {
+	body := fmt.Sprintf("Hi %s,\n\nYour Unalone account was just signed in to from a new device or location.\n\n"+
+		"Device: %s\nIP address: %s\nTime: %s\n\n"+
+		"If this was you, there is nothing to do. If not, change your password right away.",
+		user.Nickname, device.UserAgent, device.LastIP, device.LastSeenAt.UTC().Format("2 Jan 2006 15:04 MST"))
+	err := ls.email.SendEmail(&models.EmailMessage{To: user.Email, Subject: "New sign-in to your Unalone account", Body: body})
+	if err != nil {
+		log.Printf("Login security: sign-in alert to %s failed: %v", user.ID, err)
+	}
+}
+
+// deviceFingerprint identifies a device by its user agent
+func deviceFingerprint(userAgent string) string {
+	sum := sha256.Sum256([]byte(userAgent))
+	return hex.EncodeToString(sum[:8])
+}
+
+// ipNetwork truncates an IP to its /24 (IPv4) or /48 (IPv6) network as a coarse location
+func ipNetwork(ip string) string {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return ip
+	}
+	if v4 := parsed.To4(); v4 != nil {
+		return v4.Mask(net.CIDRMask(24, 32)).String() + "/24"
+	}
+	return parsed.Mask(net.CIDRMask(48, 128)).String() + "/48"
+}
+
+func (ls *LoginSecurityService) isTestMode() bool {
+	return ls.firestoreService.client == nil
+}
+
+// Mock storage for testing
+var (
+	mockLoginDevicesMu sync.Mutex
+	mockLoginDevices   = make(map[string][]*models.LoginDevice) // user ID -> known devices
+)
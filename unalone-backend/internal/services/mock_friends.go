@@ -9,185 +9,187 @@ import (
 	"unalone-backend/internal/models"
 )
 
-func (fs *FriendsService) sendFriendRequestMock(requesterID, targetIdentifier string) error {
-	users, err := fs.userService.loadMockUsers()
-	if err != nil {
-		return err
-	}
+func (fs *FriendsService) sendFriendRequestMock(requesterID, targetIdentifier, note string) (string, bool, error) {
+	var targetID string
+	mutual := false
+	err := fs.userService.updateMockUsers(func(users map[string]*models.User) error {
+		requester, ok := users[requesterID]
+		if !ok {
+			return errors.New("requester not found")
+		}
 
-	requester, ok := users[requesterID]
-	if !ok {
-		return errors.New("requester not found")
-	}
+		// Resolve target by ID or nickname
+		var target *models.User
+		for _, u := range users {
+			if u.ID == targetIdentifier || u.Nickname == targetIdentifier {
+				target = u
+				break
+			}
+		}
+		// Users of other organizations cannot be found
+		if target == nil || target.OrgID != requester.OrgID {
+			return errors.New("target user not found")
+		}
+		if target.ID == requesterID {
+			return errors.New("cannot friend yourself")
+		}
+		targetID = target.ID
 
-	// Resolve target by ID or nickname
-	var target *models.User
-	for _, u := range users {
-		if u.ID == targetIdentifier || u.Nickname == targetIdentifier {
-			target = u
-			break
+		// Already friends
+		for _, fid := range requester.Friends {
+			if fid == target.ID {
+				return errors.New("already friends")
+			}
 		}
-	}
-	if target == nil {
-		return errors.New("target user not found")
-	}
-	if target.ID == requesterID {
-		return errors.New("cannot friend yourself")
-	}
 
-	// Already friends
-	for _, fid := range requester.Friends {
-		if fid == target.ID {
-			return errors.New("already friends")
+		// If inverse request exists, auto-accept
+		for _, rid := range requester.FriendRequestsSent {
+			if rid == target.ID {
+				// They already have a pending request from me; keep as sent
+				return errors.New("request already sent")
+			}
+		}
+		for _, rid := range requester.FriendRequestsReceived {
+			if rid == target.ID {
+				// Mutual request -> accept once this transaction ends
+				mutual = true
+				return nil
+			}
 		}
-	}
 
-	// If inverse request exists, auto-accept
-	for _, rid := range requester.FriendRequestsSent {
-		if rid == target.ID {
-			// They already have a pending request from me; keep as sent
-			return errors.New("request already sent")
+		// Add to sent/received lists
+		requester.FriendRequestsSent = append(requester.FriendRequestsSent, target.ID)
+		target.FriendRequestsReceived = append(target.FriendRequestsReceived, requester.ID)
+		if target.FriendRequestDetails == nil {
+			target.FriendRequestDetails = make(map[string]models.FriendRequestDetail)
 		}
+		target.FriendRequestDetails[requester.ID] = models.FriendRequestDetail{Note: note, SentAt: clock.Now()}
+		requester.UpdatedAt = clock.Now()
+		target.UpdatedAt = clock.Now()
+		return nil
+	})
+	if err != nil {
+		return "", false, err
 	}
-	for _, rid := range requester.FriendRequestsReceived {
-		if rid == target.ID {
-			// Mutual request -> accept
-			return fs.acceptFriendRequestMock(requesterID, target.ID)
-		}
+	if mutual {
+		return targetID, true, fs.acceptFriendRequestMock(requesterID, targetID)
 	}
-
-	// Add to sent/received lists
-	requester.FriendRequestsSent = append(requester.FriendRequestsSent, target.ID)
-	target.FriendRequestsReceived = append(target.FriendRequestsReceived, requester.ID)
-	requester.UpdatedAt = time.Now()
-	target.UpdatedAt = time.Now()
-	users[requester.ID] = requester
-	users[target.ID] = target
-	return fs.userService.saveMockUsers(users)
+	return targetID, false, nil
 }
 
 func (fs *FriendsService) acceptFriendRequestMock(userID, requesterID string) error {
-	users, err := fs.userService.loadMockUsers()
-	if err != nil {
-		return err
-	}
-
-	user, ok := users[userID]
-	if !ok {
-		return errors.New("user not found")
-	}
-	req, ok := users[requesterID]
-	if !ok {
-		return errors.New("requester not found")
-	}
+	return fs.userService.updateMockUsers(func(users map[string]*models.User) error {
+		user, ok := users[userID]
+		if !ok {
+			return errors.New("user not found")
+		}
+		req, ok := users[requesterID]
+		if !ok {
+			return errors.New("requester not found")
+		}
 
-	// Verify pending
-	found := false
-	newRecv := make([]string, 0, len(user.FriendRequestsReceived))
-	for _, id := range user.FriendRequestsReceived {
-		if id == requesterID {
-			found = true
-			continue
+		// Verify pending
+		found := false
+		newRecv := make([]string, 0, len(user.FriendRequestsReceived))
+		for _, id := range user.FriendRequestsReceived {
+			if id == requesterID {
+				found = true
+				continue
+			}
+			newRecv = append(newRecv, id)
 		}
-		newRecv = append(newRecv, id)
-	}
-	if !found {
-		return errors.New("no pending request")
-	}
-	user.FriendRequestsReceived = newRecv
+		if !found {
+			return errors.New("no pending request")
+		}
+		user.FriendRequestsReceived = newRecv
+		delete(user.FriendRequestDetails, requesterID)
 
-	// Remove from requester's sent
-	newSent := make([]string, 0, len(req.FriendRequestsSent))
-	for _, id := range req.FriendRequestsSent {
-		if id == userID {
-			continue
+		// Remove from requester's sent
+		newSent := make([]string, 0, len(req.FriendRequestsSent))
+		for _, id := range req.FriendRequestsSent {
+			if id == userID {
+				continue
+			}
+			newSent = append(newSent, id)
 		}
-		newSent = append(newSent, id)
-	}
-	req.FriendRequestsSent = newSent
+		req.FriendRequestsSent = newSent
 
-	// Add friends
-	user.Friends = append(user.Friends, requesterID)
-	req.Friends = append(req.Friends, userID)
-	user.UpdatedAt = time.Now()
-	req.UpdatedAt = time.Now()
-	users[user.ID] = user
-	users[req.ID] = req
-	return fs.userService.saveMockUsers(users)
+		// Add friends
+		user.Friends = append(user.Friends, requesterID)
+		req.Friends = append(req.Friends, userID)
+		user.UpdatedAt = clock.Now()
+		req.UpdatedAt = clock.Now()
+		return nil
+	})
 }
 
 func (fs *FriendsService) rejectFriendRequestMock(userID, requesterID string) error {
-	users, err := fs.userService.loadMockUsers()
-	if err != nil {
-		return err
-	}
-	user, ok := users[userID]
-	if !ok {
-		return errors.New("user not found")
-	}
-	req, ok := users[requesterID]
-	if !ok {
-		return errors.New("requester not found")
-	}
+	return fs.userService.updateMockUsers(func(users map[string]*models.User) error {
+		user, ok := users[userID]
+		if !ok {
+			return errors.New("user not found")
+		}
+		req, ok := users[requesterID]
+		if !ok {
+			return errors.New("requester not found")
+		}
 
-	// Remove from received
-	newRecv := make([]string, 0, len(user.FriendRequestsReceived))
-	for _, id := range user.FriendRequestsReceived {
-		if id == requesterID {
-			continue
+		// Remove from received
+		newRecv := make([]string, 0, len(user.FriendRequestsReceived))
+		for _, id := range user.FriendRequestsReceived {
+			if id == requesterID {
+				continue
+			}
+			newRecv = append(newRecv, id)
 		}
-		newRecv = append(newRecv, id)
-	}
-	user.FriendRequestsReceived = newRecv
+		user.FriendRequestsReceived = newRecv
+		delete(user.FriendRequestDetails, requesterID)
 
-	// Remove from requester's sent
-	newSent := make([]string, 0, len(req.FriendRequestsSent))
-	for _, id := range req.FriendRequestsSent {
-		if id == userID {
-			continue
+		// Remove from requester's sent
+		newSent := make([]string, 0, len(req.FriendRequestsSent))
+		for _, id := range req.FriendRequestsSent {
+			if id == userID {
+				continue
+			}
+			newSent = append(newSent, id)
 		}
-		newSent = append(newSent, id)
-	}
-	req.FriendRequestsSent = newSent
+		req.FriendRequestsSent = newSent
 
-	user.UpdatedAt = time.Now()
-	req.UpdatedAt = time.Now()
-	users[user.ID] = user
-	users[req.ID] = req
-	return fs.userService.saveMockUsers(users)
+		user.UpdatedAt = clock.Now()
+		req.UpdatedAt = clock.Now()
+		return nil
+	})
 }
 
 func (fs *FriendsService) removeFriendMock(userID, friendID string) error {
-	users, err := fs.userService.loadMockUsers()
-	if err != nil {
-		return err
-	}
-	u, ok := users[userID]
-	if !ok {
-		return errors.New("user not found")
-	}
-	f, ok := users[friendID]
-	if !ok {
-		return errors.New("friend not found")
-	}
+	return fs.userService.updateMockUsers(func(users map[string]*models.User) error {
+		u, ok := users[userID]
+		if !ok {
+			return errors.New("user not found")
+		}
+		f, ok := users[friendID]
+		if !ok {
+			return errors.New("friend not found")
+		}
 
-	// Remove from both lists
-	filter := func(list []string, id string) []string {
-		out := make([]string, 0, len(list))
-		for _, v := range list {
-			if v != id {
-				out = append(out, v)
+		// Remove from both lists
+		filter := func(list []string, id string) []string {
+			out := make([]string, 0, len(list))
+			for _, v := range list {
+				if v != id {
+					out = append(out, v)
+				}
 			}
+			return out
 		}
-		return out
-	}
-	u.Friends = filter(u.Friends, friendID)
-	f.Friends = filter(f.Friends, userID)
-	u.UpdatedAt = time.Now()
-	f.UpdatedAt = time.Now()
-	users[u.ID] = u
-	users[f.ID] = f
-	return fs.userService.saveMockUsers(users)
+		u.Friends = filter(u.Friends, friendID)
+		f.Friends = filter(f.Friends, userID)
+		u.MutedFriends = filter(u.MutedFriends, friendID)
+		f.MutedFriends = filter(f.MutedFriends, userID)
+		u.UpdatedAt = clock.Now()
+		f.UpdatedAt = clock.Now()
+		return nil
+	})
 }
 
 func (fs *FriendsService) listFriendsMock(userID string) ([]models.PublicUser, error) {
@@ -218,18 +220,172 @@ func (fs *FriendsService) listFriendRequestsMock(userID string) (*FriendRequests
 	if !ok {
 		return nil, errors.New("user not found")
 	}
-	fr := &FriendRequests{Received: []models.PublicUser{}, Sent: []models.PublicUser{}}
+	now := clock.Now()
+	fr := &FriendRequests{Received: []models.FriendRequestEntry{}, Sent: []models.FriendRequestEntry{}}
 	for _, rid := range u.FriendRequestsReceived {
 		if ru, ok := users[rid]; ok {
-			fr.Received = append(fr.Received, models.PublicUser{ID: ru.ID, Nickname: ru.Nickname})
+			if entry, live := fs.requestEntry(ru, u.FriendRequestDetails[rid], now); live {
+				fr.Received = append(fr.Received, entry)
+			}
 		}
 	}
 	for _, sid := range u.FriendRequestsSent {
 		if su, ok := users[sid]; ok {
-			fr.Sent = append(fr.Sent, models.PublicUser{ID: su.ID, Nickname: su.Nickname})
+			if entry, live := fs.requestEntry(su, su.FriendRequestDetails[userID], now); live {
+				fr.Sent = append(fr.Sent, entry)
+			}
 		}
 	}
 	sort.Slice(fr.Received, func(i, j int) bool { return fr.Received[i].Nickname < fr.Received[j].Nickname })
 	sort.Slice(fr.Sent, func(i, j int) bool { return fr.Sent[i].Nickname < fr.Sent[j].Nickname })
 	return fr, nil
 }
+
+// requestEntry builds a listed request for the other user and reports false once it has expired
+func (fs *FriendsService) requestEntry(other *models.User, detail models.FriendRequestDetail, now time.Time) (models.FriendRequestEntry, bool) {
+	entry := models.FriendRequestEntry{ID: other.ID, Nickname: other.Nickname, Note: detail.Note}
+	// Requests sent before notes existed have no timestamp until the cleanup job backfills one
+	if !detail.SentAt.IsZero() {
+		entry.SentAt = detail.SentAt
+		entry.ExpiresAt = detail.SentAt.Add(fs.requestExpiry)
+		if !now.Before(entry.ExpiresAt) {
+			return entry, false
+		}
+	}
+	return entry, true
+}
+
+func (fs *FriendsService) setFriendMutedMock(userID, friendID string, muted bool) error {
+	return fs.userService.updateMockUsers(func(users map[string]*models.User) error {
+		u, ok := users[userID]
+		if !ok {
+			return errors.New("user not found")
+		}
+
+		isFriend := false
+		for _, fid := range u.Friends {
+			if fid == friendID {
+				isFriend = true
+				break
+			}
+		}
+		if !isFriend {
+			return errors.New("not friends")
+		}
+
+		list := make([]string, 0, len(u.MutedFriends)+1)
+		for _, id := range u.MutedFriends {
+			if id != friendID {
+				list = append(list, id)
+			}
+		}
+		if muted {
+			list = append(list, friendID)
+		}
+		u.MutedFriends = list
+		u.UpdatedAt = clock.Now()
+		return nil
+	})
+}
+
+func (fs *FriendsService) listMutedFriendsMock(userID string) ([]models.PublicUser, error) {
+	users, err := fs.userService.loadMockUsers()
+	if err != nil {
+		return nil, err
+	}
+	u, ok := users[userID]
+	if !ok {
+		return nil, errors.New("user not found")
+	}
+	res := make([]models.PublicUser, 0, len(u.MutedFriends))
+	for _, fid := range u.MutedFriends {
+		if friend, ok := users[fid]; ok {
+			res = append(res, models.PublicUser{ID: friend.ID, Nickname: friend.Nickname})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Nickname < res[j].Nickname })
+	return res, nil
+}
+
+func (fs *FriendsService) expireFriendRequestsMock(now time.Time) (int, error) {
+	expired := 0
+	err := fs.userService.updateMockUsers(func(users map[string]*models.User) error {
+		for _, u := range users {
+			kept := make([]string, 0, len(u.FriendRequestsReceived))
+			for _, rid := range u.FriendRequestsReceived {
+				detail, ok := u.FriendRequestDetails[rid]
+				if !ok {
+					// Start the clock on requests that predate timestamps
+					if u.FriendRequestDetails == nil {
+						u.FriendRequestDetails = make(map[string]models.FriendRequestDetail)
+					}
+					u.FriendRequestDetails[rid] = models.FriendRequestDetail{SentAt: now}
+					kept = append(kept, rid)
+					continue
+				}
+				if now.Sub(detail.SentAt) < fs.requestExpiry {
+					kept = append(kept, rid)
+					continue
+				}
+
+				delete(u.FriendRequestDetails, rid)
+				if requester, ok := users[rid]; ok {
+					sent := make([]string, 0, len(requester.FriendRequestsSent))
+					for _, id := range requester.FriendRequestsSent {
+						if id != u.ID {
+							sent = append(sent, id)
+						}
+					}
+					requester.FriendRequestsSent = sent
+					requester.UpdatedAt = now
+				}
+				expired++
+			}
+			if len(kept) != len(u.FriendRequestsReceived) {
+				u.FriendRequestsReceived = kept
+				u.UpdatedAt = now
+			}
+		}
+		return nil
+	})
+	return expired, err
+}
+
+func (fs *FriendsService) getFriendStatusesMock(userID string, otherIDs []string) (map[string]models.FriendStatus, error) {
+	users, err := fs.userService.loadMockUsers()
+	if err != nil {
+		return nil, err
+	}
+	u, ok := users[userID]
+	if !ok {
+		return nil, errors.New("user not found")
+	}
+
+	toSet := func(list []string) map[string]bool {
+		set := make(map[string]bool, len(list))
+		for _, id := range list {
+			set[id] = true
+		}
+		return set
+	}
+	friends := toSet(u.Friends)
+	sent := toSet(u.FriendRequestsSent)
+	received := toSet(u.FriendRequestsReceived)
+
+	statuses := make(map[string]models.FriendStatus, len(otherIDs))
+	for _, id := range otherIDs {
+		status := models.FriendStatusNone
+		switch {
+		case users[id] != nil && containsString(users[id].BlockedBy, userID):
+			status = models.FriendStatusBlocked
+		case friends[id]:
+			status = models.FriendStatusFriend
+		case sent[id]:
+			status = models.FriendStatusRequestSent
+		case received[id]:
+			status = models.FriendStatusRequestReceived
+		}
+		statuses[id] = status
+	}
+	return statuses, nil
+}
@@ -11,24 +11,8 @@ import (
 // Mock implementations for UserService when running without Firestore
 
 func (us *UserService) createUserMock(email, realName, nickname, passwordHash string) (*models.User, error) {
-	// Load existing users
-	users, err := us.loadMockUsers()
-	if err != nil {
-		return nil, err
-	}
-
-	// Check if email already exists
-	for _, user := range users {
-		if user.Email == email {
-			return nil, errors.New("user with this email already exists")
-		}
-		if user.Nickname == nickname {
-			return nil, errors.New("user with this nickname already exists")
-		}
-	}
-
 	// Generate mock user ID
-	userID := "mock_" + email + "_" + time.Now().Format("20060102150405")
+	userID := "mock_" + email + "_" + clock.Now().Format("20060102150405")
 
 	// Create user object
 	user := &models.User{
@@ -39,13 +23,23 @@ func (us *UserService) createUserMock(email, realName, nickname, passwordHash st
 		PasswordHash:    passwordHash,
 		IsPhoneVerified: false,
 		IsEmailVerified: false,
-		CreatedAt:       time.Now(),
-		UpdatedAt:       time.Now(),
+		CreatedAt:       clock.Now(),
+		UpdatedAt:       clock.Now(),
 	}
 
-	// Store in file-based storage
-	users[userID] = user
-	err = us.saveMockUsers(users)
+	// Check uniqueness and store in one transaction
+	err := us.updateMockUsers(func(users map[string]*models.User) error {
+		for _, existing := range users {
+			if existing.Email == email {
+				return errors.New("user with this email already exists")
+			}
+			if existing.Nickname == nickname {
+				return errors.New("user with this nickname already exists")
+			}
+		}
+		users[userID] = user
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -95,16 +89,24 @@ func (us *UserService) getUserByNicknameMock(nickname string) (*models.User, err
 }
 
 func (us *UserService) updateUserMock(userID string, updates map[string]interface{}) (*models.User, error) {
-	// Load existing users
-	users, err := us.loadMockUsers()
+	var updated *models.User
+	err := us.updateMockUsers(func(users map[string]*models.User) error {
+		user, exists := users[userID]
+		if !exists {
+			return errors.New("user not found")
+		}
+		applyMockUserUpdates(user, updates)
+		updated = user
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	
-	user, exists := users[userID]
-	if !exists {
-		return nil, errors.New("user not found")
-	}
+	return updated, nil
+}
+
+// applyMockUserUpdates copies the supported update fields onto user
+func applyMockUserUpdates(user *models.User, updates map[string]interface{}) {
 
 	// Apply updates
 	if nickname, ok := updates["nickname"].(string); ok {
@@ -131,6 +133,9 @@ func (us *UserService) updateUserMock(userID string, updates map[string]interfac
 	if gender, ok := updates["gender"].(string); ok {
 		user.Gender = gender
 	}
+	if location, ok := updates["location"].(models.Location); ok {
+		user.Location = location
+	}
 	if interests, ok := updates["interests"]; ok {
 		// Handle interests array conversion
 		switch v := interests.(type) {
@@ -156,16 +161,22 @@ func (us *UserService) updateUserMock(userID string, updates map[string]interfac
 	if profileImageURL, ok := updates["profile_image_url"].(string); ok {
 		user.ProfileImageURL = profileImageURL
 	}
-	user.UpdatedAt = time.Now()
-
-	// Save updated user back to file
-	users[userID] = user
-	err = us.saveMockUsers(users)
-	if err != nil {
-		return nil, err
+	if blockedBy, ok := updates["blocked_by"].([]string); ok {
+		user.BlockedBy = blockedBy
 	}
-
-	return user, nil
+	if availability, ok := updates["availability"].(*models.Availability); ok {
+		user.Availability = availability
+	}
+	if orgID, ok := updates["org_id"].(string); ok {
+		user.OrgID = orgID
+	}
+	if orgRole, ok := updates["org_role"].(string); ok {
+		user.OrgRole = orgRole
+	}
+	if orgJoinedAt, ok := updates["org_joined_at"].(*time.Time); ok {
+		user.OrgJoinedAt = orgJoinedAt
+	}
+	user.UpdatedAt = clock.Now()
 }
 
 // Helper function to check if we're in test mode
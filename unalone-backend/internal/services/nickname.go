@@ -0,0 +1,193 @@
+// Nickname service for change history, the change cooldown and released-nickname reservations
+package services
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"unalone-backend/internal/models"
+)
+
+const (
+	// nicknameChangeCooldown is how long a user waits between nickname changes
+	nicknameChangeCooldown = 30 * 24 * time.Hour
+	// nicknameReservationPeriod is how long a released nickname is held for its previous owner
+	nicknameReservationPeriod = 14 * 24 * time.Hour
+	// previousNicknameHints is how many earlier nicknames friends are shown
+	previousNicknameHints = 3
+)
+
+// ErrNicknameReserved is returned for a nickname someone else released recently
+var ErrNicknameReserved = errors.New("this nickname was recently used by someone else and is reserved")
+
+// NicknameCooldownError is returned when the user changed their nickname too recently
+type NicknameCooldownError struct {
+	NextChangeAt time.Time
+}
+
+func (e *NicknameCooldownError) Error() string {
+	return "nickname was changed recently; next change allowed: " + e.NextChangeAt.UTC().Format("2006-01-02")
+}
+
+// NicknameService keeps nickname history so changes cannot be used to impersonate someone:
+// changes are rate-limited and a released nickname stays reserved for a while
+type NicknameService struct {
+	firestoreService *FirestoreService
+}
+
+// NewNicknameService creates a new nickname service
+func NewNicknameService(fs *FirestoreService) *NicknameService {
+	return &NicknameService{firestoreService: fs}
+}
+
+// CheckChange reports whether a user may change to a new nickname now
+func (ns *NicknameService) CheckChange(userID, nickname string, now time.Time) error {
+	history, err := ns.GetHistory(userID)
+	if err != nil {
+		return err
+	}
+	if len(history) > 0 {
+		if next := history[0].ChangedAt.Add(nicknameChangeCooldown); now.Before(next) {
+			return &NicknameCooldownError{NextChangeAt: next}
+		}
+	}
+	return ns.CheckAvailable(userID, nickname, now)
+}
+
+// CheckAvailable reports whether a nickname is free of reservations for this user; pass an empty
+// user ID for registrations
+func (ns *NicknameService) CheckAvailable(userID, nickname string, now time.Time) error {
+	reservation, err := ns.getReservation(nicknameKey(nickname))
+	if err != nil {
+		return err
+	}
+	if reservation != nil && reservation.ReleasedBy != userID && now.Before(reservation.ExpiresAt) {
+		return ErrNicknameReserved
+	}
+	return nil
+}
+
+// RecordChange stores a nickname change and reserves the old nickname for its owner
+func (ns *NicknameService) RecordChange(userID, oldNickname, newNickname string, now time.Time) error {
+	if !ns.isTestMode() {
+		// TODO: Write Firestore nickname_history and nickname_reservations in one batch
+		return errors.New("firestore implementation needed")
+	}
+
+	mockNicknamesMu.Lock()
+	defer mockNicknamesMu.Unlock()
+	mockNicknameHistory[userID] = append(mockNicknameHistory[userID], &models.NicknameChange{
+		UserID: userID, OldNickname: oldNickname, NewNickname: newNickname, ChangedAt: now,
+	})
+	if oldNickname != "" {
+		key := nicknameKey(oldNickname)
+		mockNicknameReservations[key] = &models.NicknameReservation{Nickname: key, ReleasedBy: userID, ExpiresAt: now.Add(nicknameReservationPeriod)}
+	}
+	// Taking back your own released nickname ends its reservation
+	if reservation, ok := mockNicknameReservations[nicknameKey(newNickname)]; ok && reservation.ReleasedBy == userID {
+		delete(mockNicknameReservations, nicknameKey(newNickname))
+	}
+	return nil
+}
+
+// GetHistory returns a user's nickname changes, newest first
+func (ns *NicknameService) GetHistory(userID string) ([]models.NicknameChange, error) {
+	if !ns.isTestMode() {
+		// TODO: Query Firestore nickname_history by user_id ordered by changed_at desc
+		return nil, errors.New("firestore implementation needed")
+	}
+
+	mockNicknamesMu.Lock()
+	defer mockNicknamesMu.Unlock()
+	history := make([]models.NicknameChange, 0, len(mockNicknameHistory[userID]))
+	for _, change := range mockNicknameHistory[userID] {
+		history = append(history, *change)
+	}
+	sort.Slice(history, func(i, j int) bool { return history[i].ChangedAt.After(history[j].ChangedAt) })
+	return history, nil
+}
+
+// GetStatus returns the user's nickname history and when they may next change it
+func (ns *NicknameService) GetStatus(user *models.User, now time.Time) (*models.NicknameStatus, error) {
+	history, err := ns.GetHistory(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	status := &models.NicknameStatus{Nickname: user.Nickname, History: history}
+	if len(history) > 0 {
+		if next := history[0].ChangedAt.Add(nicknameChangeCooldown); now.Before(next) {
+			status.NextChangeAt = &next
+		}
+	}
+	return status, nil
+}
+
+// PreviousNicknames returns a user's most recent earlier nicknames, newest first, as hints for friends
+func (ns *NicknameService) PreviousNicknames(userID, current string) []string {
+	history, err := ns.GetHistory(userID)
+	if err != nil {
+		return nil
+	}
+	var previous []string
+	for _, change := range history {
+		if len(previous) == previousNicknameHints {
+			break
+		}
+		if change.OldNickname != "" && change.OldNickname != current && !containsString(previous, change.OldNickname) {
+			previous = append(previous, change.OldNickname)
+		}
+	}
+	return previous
+}
+
+// PurgeExpiredReservations drops reservations that have lapsed
+func (ns *NicknameService) PurgeExpiredReservations(now time.Time) {
+	if !ns.isTestMode() {
+		// Firestore reservations are checked against expires_at and removed by a TTL policy
+		return
+	}
+
+	mockNicknamesMu.Lock()
+	defer mockNicknamesMu.Unlock()
+	for key, reservation := range mockNicknameReservations {
+		if !now.Before(reservation.ExpiresAt) {
+			delete(mockNicknameReservations, key)
+		}
+	}
+}
+
+func (ns *NicknameService) getReservation(key string) (*models.NicknameReservation, error) {
+	if !ns.isTestMode() {
+		// TODO: Read Firestore document nickname_reservations/{key}
+		return nil, errors.New("firestore implementation needed")
+	}
+
+	mockNicknamesMu.Lock()
+	defer mockNicknamesMu.Unlock()
+	reservation, ok := mockNicknameReservations[key]
+	if !ok {
+		return nil, nil
+	}
+	result := *reservation
+	return &result, nil
+}
+
+func (ns *NicknameService) isTestMode() bool {
+	return ns.firestoreService.client == nil
+}
+
+// nicknameKey folds case so reservations also cover look-alike capitalizations
+func nicknameKey(nickname string) string {
+	return strings.ToLower(strings.TrimSpace(nickname))
+}
+
+// === Mock storage in-memory for development/test ===
+
+var (
+	mockNicknamesMu          sync.Mutex
+	mockNicknameHistory      = make(map[string][]*models.NicknameChange)    // userID -> changes, oldest first
+	mockNicknameReservations = make(map[string]*models.NicknameReservation) // lowercased nickname -> reservation
+)
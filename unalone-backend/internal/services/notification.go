@@ -0,0 +1,526 @@
+// Notification service routing push and email notifications through user preferences
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"unalone-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// chatNotificationPreview is how many characters of a chat message a notification shows
+const chatNotificationPreview = 100
+
+// NotificationSender delivers a notification over a single channel
+type NotificationSender interface {
+	Send(n *models.Notification) error
+}
+
+// logNotificationSender writes notifications to the server log until a provider is configured
+type logNotificationSender struct {
+	channel string
+}
+
+func (s logNotificationSender) Send(n *models.Notification) error {
+	log.Printf("[%s] notify user=%s category=%s title=%q", s.channel, n.UserID, n.Category, n.Title)
+	return nil
+}
+
+// NotificationService decides which channels a notification may use and dispatches it
+type NotificationService struct {
+	profileService *ProfileService
+	userService    *UserService
+	events         *EventService
+	chat           *ChatService
+	clubs          *ClubService
+	push           NotificationSender
+	email          NotificationSender
+}
+
+// NewNotificationService creates a new notification service
+func NewNotificationService(ps *ProfileService, us *UserService, es *EventService, cs *ChatService, clubs *ClubService) *NotificationService {
+	return &NotificationService{
+		profileService: ps,
+		userService:    us,
+		events:         es,
+		chat:           cs,
+		clubs:          clubs,
+		push:           logNotificationSender{channel: "push"},
+		email:          logNotificationSender{channel: "email"},
+	}
+}
+
+// Notify stores a notification in the user's inbox, pushes it to their open
+// connections, and sends it on every channel their settings allow
+func (ns *NotificationService) Notify(userID string, category models.NotificationCategory, notificationType, title, body string, data map[string]string) error {
+	settings, err := ns.profileService.GetUserSettings(userID)
+	if err != nil {
+		return err
+	}
+
+	n := &models.Notification{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Category:  category,
+		Type:      notificationType,
+		Title:     title,
+		Body:      body,
+		Data:      data,
+		CreatedAt: clock.Now(),
+	}
+
+	// The inbox keeps everything; preferences only govern push and email
+	if err := ns.saveNotification(n); err != nil {
+		log.Printf("Failed to store notification: %v", err)
+	}
+	ns.events.Publish(userID, notificationType, n)
+
+	push, email := resolveChannels(settings, category, n.CreatedAt)
+	// Busy users only get pushes that cannot wait
+	if push && category != models.NotificationCategorySafety && ns.isBusy(userID, n.CreatedAt) {
+		push = false
+	}
+	if push {
+		if err := ns.push.Send(n); err != nil {
+			log.Printf("Push notification failed: %v", err)
+		}
+	}
+	if email {
+		if err := ns.email.Send(n); err != nil {
+			log.Printf("Email notification failed: %v", err)
+		}
+	}
+	return nil
+}
+
+// ListNotifications returns a page of the user's inbox, newest first, with the unread count
+func (ns *NotificationService) ListNotifications(userID string, unreadOnly bool, limit, offset int) (*models.NotificationListResponse, error) {
+	if ns.isTestMode() {
+		return ns.listNotificationsMock(userID, unreadOnly, limit, offset), nil
+	}
+
+	// TODO: Query Firestore notifications/{userID}/items ordered by created_at desc
+	return nil, errors.New("firestore implementation needed")
+}
+
+// MarkRead marks a single notification as read
+func (ns *NotificationService) MarkRead(userID, notificationID string) error {
+	if ns.isTestMode() {
+		return ns.markReadMock(userID, notificationID)
+	}
+
+	// TODO: Update Firestore document notifications/{userID}/items/{notificationID}
+	return errors.New("firestore implementation needed")
+}
+
+// MarkAllRead marks every notification in the user's inbox as read and returns how many changed
+func (ns *NotificationService) MarkAllRead(userID string) (int, error) {
+	if ns.isTestMode() {
+		return ns.markAllReadMock(userID), nil
+	}
+
+	// TODO: Batch update unread Firestore notifications for the user
+	return 0, errors.New("firestore implementation needed")
+}
+
+// saveNotification persists a notification to the inbox
+func (ns *NotificationService) saveNotification(n *models.Notification) error {
+	if ns.isTestMode() {
+		ns.saveNotificationMock(n)
+		return nil
+	}
+
+	// TODO: Save to Firestore notifications/{userID}/items/{notificationID}
+	return errors.New("firestore implementation needed")
+}
+
+// NotifyFriendRequest tells a user someone sent them a friend request
+func (ns *NotificationService) NotifyFriendRequest(requesterID, targetID string) error {
+	requester, err := ns.userService.GetUserByID(requesterID)
+	if err != nil {
+		return err
+	}
+	return ns.Notify(targetID, models.NotificationCategoryFriends, models.NotificationTypeFriendRequest,
+		"New friend request",
+		requester.Nickname+" sent you a friend request",
+		map[string]string{"user_id": requesterID})
+}
+
+// NotifyFriendAccepted tells a user their friend request was accepted
+func (ns *NotificationService) NotifyFriendAccepted(accepterID, requesterID string) error {
+	accepter, err := ns.userService.GetUserByID(accepterID)
+	if err != nil {
+		return err
+	}
+	return ns.Notify(requesterID, models.NotificationCategoryFriends, models.NotificationTypeFriendAccepted,
+		"Friend request accepted",
+		accepter.Nickname+" accepted your friend request",
+		map[string]string{"user_id": accepterID})
+}
+
+// NotifyHotspotJoined tells a host someone joined their hotspot
+func (ns *NotificationService) NotifyHotspotJoined(hotspot *models.Hotspot, joinerID string) error {
+	// Imported hotspots have no host to tell
+	if hotspot.CreatedBy == joinerID || hotspot.External != nil {
+		return nil
+	}
+	joiner, err := ns.userService.GetUserByID(joinerID)
+	if err != nil {
+		return err
+	}
+	return ns.Notify(hotspot.CreatedBy, models.NotificationCategoryHotspots, models.NotificationTypeHotspotJoined,
+		"New attendee",
+		joiner.Nickname+" joined "+hotspot.Name,
+		map[string]string{"hotspot_id": hotspot.ID, "user_id": joinerID})
+}
+
+// NotifyClubFollowers tells a club's followers that it published a hotspot they can see
+func (ns *NotificationService) NotifyClubFollowers(hotspot *models.Hotspot) error {
+	if hotspot.ClubID == "" || !isBrowsable(hotspot) {
+		return nil
+	}
+	club, err := ns.clubs.GetClub(hotspot.ClubID, hotspot.OrgID)
+	if err != nil {
+		return err
+	}
+	followers, err := ns.clubs.Followers(club.ID)
+	if err != nil {
+		return err
+	}
+	for _, followerID := range followers {
+		if followerID == hotspot.CreatedBy || !CanViewHotspot(hotspot, followerID, userOrgID(ns.userService, followerID)) {
+			continue
+		}
+		_ = ns.Notify(followerID, models.NotificationCategoryHotspots, models.NotificationTypeClubHotspot,
+			club.Name,
+			club.Name+" is hosting "+hotspot.Name,
+			map[string]string{"hotspot_id": hotspot.ID, "club_id": club.ID})
+	}
+	return nil
+}
+
+// NotifyHotspotUpdated tells attendees that a hotspot they joined changed its time, place or status
+func (ns *NotificationService) NotifyHotspotUpdated(hotspot *models.Hotspot) {
+	body := hotspot.Name + " has new details"
+	if !hotspot.IsActive {
+		body = hotspot.Name + " was cancelled"
+	}
+	for _, attendeeID := range hotspot.Attendees {
+		if attendeeID == hotspot.CreatedBy {
+			continue
+		}
+		_ = ns.Notify(attendeeID, models.NotificationCategoryHotspots, models.NotificationTypeHotspotUpdated,
+			"Hotspot updated", body,
+			map[string]string{"hotspot_id": hotspot.ID})
+	}
+}
+
+// NotifyChatMessage tells attendees who are not in the chat room about a new message,
+// skipping anyone who muted the room
+func (ns *NotificationService) NotifyChatMessage(hotspot *models.Hotspot, msg *models.ChatMessage, inRoom map[string]bool) {
+	body := msg.Nickname + ": " + msg.Content
+	if runes := []rune(body); len(runes) > chatNotificationPreview {
+		body = string(runes[:chatNotificationPreview]) + "…"
+	}
+	for _, attendeeID := range hotspot.Attendees {
+		if attendeeID == msg.UserID || inRoom[attendeeID] || ns.chat.ChatMuted(attendeeID, hotspot.ID, msg.CreatedAt) {
+			continue
+		}
+		_ = ns.Notify(attendeeID, models.NotificationCategoryChat, models.NotificationTypeChatMessage,
+			hotspot.Name, body,
+			map[string]string{"hotspot_id": hotspot.ID, "message_id": msg.ID})
+	}
+}
+
+// NotifyAnnouncement pushes a host announcement to every other attendee; chat mutes do not apply
+func (ns *NotificationService) NotifyAnnouncement(hotspot *models.Hotspot, msg *models.ChatMessage) {
+	for _, attendeeID := range hotspot.Attendees {
+		if attendeeID == msg.UserID {
+			continue
+		}
+		_ = ns.Notify(attendeeID, models.NotificationCategoryHotspots, models.NotificationTypeAnnouncement,
+			hotspot.Name, msg.Nickname+": "+msg.Content,
+			map[string]string{"hotspot_id": hotspot.ID, "message_id": msg.ID})
+	}
+}
+
+// NotifyHotspotInvites tells each invited friend about a hotspot they were invited to
+func (ns *NotificationService) NotifyHotspotInvites(hotspot *models.Hotspot, userIDs []string) {
+	for _, userID := range userIDs {
+		_ = ns.Notify(userID, models.NotificationCategoryHotspots, models.NotificationTypeHotspotInvite,
+			"You're invited",
+			hotspot.CreatedByNickname+" invited you to "+hotspot.Name,
+			map[string]string{"hotspot_id": hotspot.ID, "user_id": hotspot.CreatedBy})
+	}
+}
+
+// NotifyOwnershipOffer asks an attendee to accept becoming the host of a hotspot
+func (ns *NotificationService) NotifyOwnershipOffer(hotspot *models.Hotspot) error {
+	if hotspot.OwnershipOffer == nil {
+		return nil
+	}
+	return ns.Notify(hotspot.OwnershipOffer.ToUserID, models.NotificationCategoryHotspots, models.NotificationTypeOwnershipOffer,
+		"Take over as host?",
+		hotspot.CreatedByNickname+" wants you to host "+hotspot.Name,
+		map[string]string{"hotspot_id": hotspot.ID, "user_id": hotspot.CreatedBy})
+}
+
+// NotifyNewHost tells the new host they now run a hotspot, and why
+func (ns *NotificationService) NotifyNewHost(hotspot *models.Hotspot, previousHostID string) error {
+	previous, err := ns.userService.GetUserByID(previousHostID)
+	if err != nil {
+		return err
+	}
+	body := "You are now the host of " + hotspot.Name
+	if !containsString(hotspot.Attendees, previousHostID) {
+		body = previous.Nickname + " left " + hotspot.Name + ", so you are now the host"
+	}
+	return ns.Notify(hotspot.CreatedBy, models.NotificationCategoryHotspots, models.NotificationTypeNewHost,
+		"You're the host now", body,
+		map[string]string{"hotspot_id": hotspot.ID, "user_id": previousHostID})
+}
+
+// NotifyFeedbackRequested asks attendees of an ended hotspot how it went
+func (ns *NotificationService) NotifyFeedbackRequested(hotspot *models.Hotspot, userIDs []string) {
+	for _, userID := range userIDs {
+		_ = ns.Notify(userID, models.NotificationCategoryHotspots, models.NotificationTypeFeedbackRequest,
+			"How was it?",
+			"Tell us how "+hotspot.Name+" went",
+			map[string]string{"hotspot_id": hotspot.ID})
+	}
+}
+
+// NotifySafetyAlert tells a trusted contact that a friend missed their safety check-in (missed)
+// or has since confirmed they are safe. Safety notifications ignore quiet hours.
+func (ns *NotificationService) NotifySafetyAlert(contactUserID string, user *models.User, timer *models.SafetyTimer, message string, missed bool) error {
+	notificationType, title := models.NotificationTypeSafetyConfirmed, user.Nickname+" is safe"
+	if missed {
+		notificationType, title = models.NotificationTypeSafetyAlert, user.Nickname+" missed a safety check-in"
+	}
+	return ns.Notify(contactUserID, models.NotificationCategorySafety, notificationType, title, message,
+		map[string]string{"hotspot_id": timer.HotspotID, "user_id": user.ID, "timer_id": timer.ID})
+}
+
+// NotifySOS alerts a trusted contact or moderator that a user raised an SOS. Safety notifications ignore quiet hours.
+func (ns *NotificationService) NotifySOS(recipientID string, user *models.User, alert *models.SOSAlert, message string) error {
+	return ns.Notify(recipientID, models.NotificationCategorySafety, models.NotificationTypeSOS,
+		"SOS from "+user.Nickname, message,
+		map[string]string{
+			"hotspot_id": alert.HotspotID,
+			"user_id":    user.ID,
+			"sos_id":     alert.ID,
+			"latitude":   strconv.FormatFloat(alert.Location.Latitude, 'f', 6, 64),
+			"longitude":  strconv.FormatFloat(alert.Location.Longitude, 'f', 6, 64),
+		})
+}
+
+// NotifyFriendNearby tells a user a friend created or joined a hotspot close to them
+func (ns *NotificationService) NotifyFriendNearby(userID string, friend *models.User, hotspot *models.Hotspot, created bool, distanceKm float64) error {
+	action := "joined"
+	if created {
+		action = "is hosting"
+	}
+	return ns.Notify(userID, models.NotificationCategoryNearby, models.NotificationTypeFriendNearby,
+		"A friend is nearby",
+		fmt.Sprintf("%s %s %s, %.1f km away", friend.Nickname, action, hotspot.Name, distanceKm),
+		map[string]string{"hotspot_id": hotspot.ID, "user_id": friend.ID})
+}
+
+// NotifyLevelUp congratulates a user on reaching a new level
+func (ns *NotificationService) NotifyLevelUp(userID string, level int) error {
+	return ns.Notify(userID, models.NotificationCategoryAchievements, models.NotificationTypeLevelUp,
+		"Level up!",
+		fmt.Sprintf("You reached level %d", level),
+		map[string]string{"level": strconv.Itoa(level)})
+}
+
+// isTestMode checks if we're running with mocked database
+// isBusy reports whether the user's availability is set to busy
+func (ns *NotificationService) isBusy(userID string, now time.Time) bool {
+	user, err := ns.userService.GetUserByID(userID)
+	return err == nil && EffectiveAvailability(user, now) == models.AvailabilityBusy
+}
+
+func (ns *NotificationService) isTestMode() bool {
+	return ns.profileService.firestoreService.client == nil
+}
+
+// resolveChannels applies the master switch, per-category overrides and quiet hours
+func resolveChannels(settings *models.UserSettings, category models.NotificationCategory, now time.Time) (push, email bool) {
+	if !settings.NotificationsEnabled {
+		return false, false
+	}
+
+	push, email = settings.PushNotifications, settings.EmailNotifications
+	if prefs, ok := settings.CategoryPreferences[category]; ok {
+		push = push && prefs.Push
+		email = email && prefs.Email
+	}
+
+	// Quiet hours hold back pushes only; safety alerts always get through
+	if push && category != models.NotificationCategorySafety && inQuietHours(settings.QuietHours, now) {
+		push = false
+	}
+	return push, email
+}
+
+// inQuietHours reports whether now falls inside the quiet-hours window
+func inQuietHours(qh models.QuietHours, now time.Time) bool {
+	if !qh.Enabled {
+		return false
+	}
+	start, errStart := parseClock(qh.Start)
+	end, errEnd := parseClock(qh.End)
+	if errStart != nil || errEnd != nil || start == end {
+		return false
+	}
+
+	loc := time.UTC
+	if qh.Timezone != "" {
+		if l, err := time.LoadLocation(qh.Timezone); err == nil {
+			loc = l
+		}
+	}
+	local := now.In(loc)
+	minute := local.Hour()*60 + local.Minute()
+
+	if start < end {
+		return minute >= start && minute < end
+	}
+	// Window spans midnight, e.g. 22:00-07:00
+	return minute >= start || minute < end
+}
+
+// parseClock converts HH:MM to minutes after midnight
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// validateNotificationPreferences checks category names and the quiet-hours window
+func validateNotificationPreferences(prefs map[models.NotificationCategory]models.NotificationChannelPrefs, qh models.QuietHours) error {
+	for category := range prefs {
+		known := false
+		for _, c := range models.NotificationCategories {
+			if c == category {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return errors.New("unknown notification category: " + string(category))
+		}
+	}
+
+	if !qh.Enabled {
+		return nil
+	}
+	if _, err := parseClock(qh.Start); err != nil {
+		return errors.New("quiet hours start: " + err.Error())
+	}
+	if _, err := parseClock(qh.End); err != nil {
+		return errors.New("quiet hours end: " + err.Error())
+	}
+	if qh.Timezone != "" {
+		if _, err := time.LoadLocation(qh.Timezone); err != nil {
+			return errors.New("unknown timezone: " + qh.Timezone)
+		}
+	}
+	return nil
+}
+
+// === Mock storage in-memory for development/test ===
+
+const maxMockNotificationsPerUser = 200
+
+var (
+	mockNotificationsMu sync.Mutex
+	mockNotifications   = make(map[string][]*models.Notification) // userID -> notifications, oldest first
+)
+
+func (ns *NotificationService) saveNotificationMock(n *models.Notification) {
+	mockNotificationsMu.Lock()
+	defer mockNotificationsMu.Unlock()
+
+	list := append(mockNotifications[n.UserID], n)
+	// Keep only the most recent notifications to limit memory
+	if len(list) > maxMockNotificationsPerUser {
+		list = list[len(list)-maxMockNotificationsPerUser:]
+	}
+	mockNotifications[n.UserID] = list
+}
+
+func (ns *NotificationService) listNotificationsMock(userID string, unreadOnly bool, limit, offset int) *models.NotificationListResponse {
+	mockNotificationsMu.Lock()
+	defer mockNotificationsMu.Unlock()
+
+	list := mockNotifications[userID]
+	response := &models.NotificationListResponse{Notifications: []*models.Notification{}}
+	matched := make([]*models.Notification, 0, len(list))
+	for i := len(list) - 1; i >= 0; i-- {
+		n := list[i]
+		if !n.Read {
+			response.UnreadCount++
+		}
+		if unreadOnly && n.Read {
+			continue
+		}
+		matched = append(matched, n)
+	}
+	response.TotalCount = len(matched)
+
+	if offset < len(matched) {
+		end := offset + limit
+		if end > len(matched) {
+			end = len(matched)
+		}
+		// Return copies to avoid external mutation
+		for _, n := range matched[offset:end] {
+			copied := *n
+			response.Notifications = append(response.Notifications, &copied)
+		}
+	}
+	return response
+}
+
+func (ns *NotificationService) markReadMock(userID, notificationID string) error {
+	mockNotificationsMu.Lock()
+	defer mockNotificationsMu.Unlock()
+
+	for _, n := range mockNotifications[userID] {
+		if n.ID == notificationID {
+			if !n.Read {
+				now := clock.Now()
+				n.Read = true
+				n.ReadAt = &now
+			}
+			return nil
+		}
+	}
+	return errors.New("notification not found")
+}
+
+func (ns *NotificationService) markAllReadMock(userID string) int {
+	mockNotificationsMu.Lock()
+	defer mockNotificationsMu.Unlock()
+
+	now := clock.Now()
+	changed := 0
+	for _, n := range mockNotifications[userID] {
+		if !n.Read {
+			n.Read = true
+			n.ReadAt = &now
+			changed++
+		}
+	}
+	return changed
+}
@@ -0,0 +1,114 @@
+// Object storage for uploaded media such as chat voice notes
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+
+	"cloud.google.com/go/storage"
+)
+
+// ErrObjectNotFound is returned when a stored object does not exist
+var ErrObjectNotFound = errors.New("object not found")
+
+// ObjectStore keeps binary objects by key
+type ObjectStore interface {
+	Put(ctx context.Context, key, contentType string, data []byte) error
+	Get(ctx context.Context, key string) ([]byte, string, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// NewObjectStore returns a Google Cloud Storage store for the MEDIA_BUCKET bucket, or an
+// in-memory store when no bucket is configured
+func NewObjectStore(ctx context.Context) (ObjectStore, error) {
+	bucket := strings.TrimSpace(os.Getenv("MEDIA_BUCKET"))
+	if bucket == "" {
+		log.Println("MEDIA_BUCKET not set - uploaded media is kept in memory")
+		return newMemoryObjectStore(), nil
+	}
+	client, err := storage.NewClient(ctx, credentialOptions()...)
+	if err != nil {
+		return nil, err
+	}
+	return &gcsObjectStore{bucket: client.Bucket(bucket)}, nil
+}
+
+// gcsObjectStore stores objects in a Cloud Storage bucket
+type gcsObjectStore struct {
+	bucket *storage.BucketHandle
+}
+
+func (s *gcsObjectStore) Put(ctx context.Context, key, contentType string, data []byte) error {
+	w := s.bucket.Object(key).NewWriter(ctx)
+	w.ContentType = contentType
+	if _, err := w.Write(data); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+func (s *gcsObjectStore) Get(ctx context.Context, key string) ([]byte, string, error) {
+	r, err := s.bucket.Object(key).NewReader(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return nil, "", ErrObjectNotFound
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	return data, r.Attrs.ContentType, err
+}
+
+func (s *gcsObjectStore) Delete(ctx context.Context, key string) error {
+	err := s.bucket.Object(key).Delete(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return nil
+	}
+	return err
+}
+
+// memoryObjectStore keeps objects in process memory for development
+type memoryObjectStore struct {
+	mu      sync.Mutex
+	objects map[string]storedObject
+}
+
+type storedObject struct {
+	contentType string
+	data        []byte
+}
+
+func newMemoryObjectStore() *memoryObjectStore {
+	return &memoryObjectStore{objects: make(map[string]storedObject)}
+}
+
+func (s *memoryObjectStore) Put(ctx context.Context, key, contentType string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[key] = storedObject{contentType: contentType, data: append([]byte(nil), data...)}
+	return nil
+}
+
+func (s *memoryObjectStore) Get(ctx context.Context, key string) ([]byte, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	object, ok := s.objects[key]
+	if !ok {
+		return nil, "", ErrObjectNotFound
+	}
+	return object.data, object.contentType, nil
+}
+
+func (s *memoryObjectStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.objects, key)
+	return nil
+}
@@ -0,0 +1,203 @@
+// Onboarding service tracking checklist steps and rewarding them
+package services
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"unalone-backend/internal/models"
+)
+
+// onboardingPoints is awarded once per step as it completes
+var onboardingPoints = map[string]int{
+	models.OnboardingEmailVerified:      10,
+	models.OnboardingPhoneVerified:      20,
+	models.OnboardingProfileCompleted:   20,
+	models.OnboardingInterestsPicked:    10,
+	models.OnboardingFirstHotspotJoined: 30,
+}
+
+// OnboardingService keeps the server-side onboarding checklist. Steps are derived from the
+// user's profile and activity, and once complete stay complete so points are awarded only once.
+type OnboardingService struct {
+	firestoreService *FirestoreService
+	userService      *UserService
+	hotspotService   *HotspotService
+	gamification     *GamificationService
+	events           *EventService
+}
+
+// NewOnboardingService creates a new onboarding service
+func NewOnboardingService(fs *FirestoreService, us *UserService, hs *HotspotService, gs *GamificationService, es *EventService) *OnboardingService {
+	return &OnboardingService{firestoreService: fs, userService: us, hotspotService: hs, gamification: gs, events: es}
+}
+
+// Refresh re-checks the user's incomplete steps, rewards any that are now done and returns the checklist
+func (obs *OnboardingService) Refresh(userID string) (*models.OnboardingProgress, error) {
+	user, err := obs.userService.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	state, err := obs.getState(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []string
+	for _, step := range models.OnboardingSteps {
+		if _, ok := state.CompletedAt[step]; ok {
+			continue
+		}
+		if obs.stepDone(user, step) {
+			done = append(done, step)
+		}
+	}
+	if len(done) == 0 {
+		return onboardingProgress(state), nil
+	}
+	return obs.complete(userID, done...)
+}
+
+// CompleteStep marks a step done from an activity event, e.g. the user's first hotspot join
+func (obs *OnboardingService) CompleteStep(userID, step string) error {
+	if _, ok := onboardingPoints[step]; !ok {
+		return errors.New("unknown onboarding step")
+	}
+	_, err := obs.complete(userID, step)
+	return err
+}
+
+// complete records steps not already recorded, then awards their points and pushes the checklist
+func (obs *OnboardingService) complete(userID string, steps ...string) (*models.OnboardingProgress, error) {
+	state, added, err := obs.recordSteps(userID, steps, clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	progress := onboardingProgress(state)
+	if len(added) == 0 {
+		return progress, nil
+	}
+
+	for _, step := range added {
+		if _, _, err := obs.gamification.AwardPoints(userID, onboardingPoints[step], "onboarding_"+step); err != nil {
+			log.Printf("[onboarding] award %s for %s: %v", step, userID, err)
+		}
+	}
+	obs.events.Publish(userID, models.UserEventOnboardingStep, progress)
+	return progress, nil
+}
+
+// stepDone reports whether the user currently satisfies a step
+func (obs *OnboardingService) stepDone(user *models.User, step string) bool {
+	switch step {
+	case models.OnboardingEmailVerified:
+		return user.IsEmailVerified
+	case models.OnboardingPhoneVerified:
+		return user.IsPhoneVerified
+	case models.OnboardingProfileCompleted:
+		return user.Bio != "" && user.ProfileImageURL != "" && !user.DateOfBirth.IsZero()
+	case models.OnboardingInterestsPicked:
+		return len(user.Interests) >= minProfileInterests
+	case models.OnboardingFirstHotspotJoined:
+		// Joins are normally recorded from the join event; this catches ones made before onboarding existed
+		joined, err := obs.hotspotService.GetJoinedHotspots(user.ID)
+		if err != nil {
+			return false
+		}
+		for _, hotspot := range joined {
+			if hotspot.CreatedBy != user.ID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// onboardingProgress builds the checklist from the recorded state
+func onboardingProgress(state *models.OnboardingState) *models.OnboardingProgress {
+	progress := &models.OnboardingProgress{
+		Steps:      make([]models.OnboardingStep, 0, len(models.OnboardingSteps)),
+		Total:      len(models.OnboardingSteps),
+		FinishedAt: state.FinishedAt,
+	}
+	for _, key := range models.OnboardingSteps {
+		step := models.OnboardingStep{Key: key, Points: onboardingPoints[key]}
+		if at, ok := state.CompletedAt[key]; ok {
+			at := at
+			step.Completed = true
+			step.CompletedAt = &at
+			progress.Completed++
+		} else if progress.CurrentStep == "" {
+			progress.CurrentStep = key
+		}
+		progress.Steps = append(progress.Steps, step)
+	}
+	progress.Finished = progress.Completed == progress.Total
+	return progress
+}
+
+func (obs *OnboardingService) getState(userID string) (*models.OnboardingState, error) {
+	if !obs.isTestMode() {
+		// TODO: Read Firestore document onboarding/{userID}
+		return nil, errors.New("firestore implementation needed")
+	}
+
+	mockOnboardingMu.Lock()
+	defer mockOnboardingMu.Unlock()
+	return copyOnboardingState(mockOnboardingState(userID)), nil
+}
+
+// recordSteps stores completion times for steps not yet completed and returns the ones it added
+func (obs *OnboardingService) recordSteps(userID string, steps []string, now time.Time) (*models.OnboardingState, []string, error) {
+	if !obs.isTestMode() {
+		// TODO: Update Firestore document onboarding/{userID} in a transaction so each step is added once
+		return nil, nil, errors.New("firestore implementation needed")
+	}
+
+	mockOnboardingMu.Lock()
+	defer mockOnboardingMu.Unlock()
+	state := mockOnboardingState(userID)
+	var added []string
+	for _, step := range steps {
+		if _, ok := state.CompletedAt[step]; !ok {
+			state.CompletedAt[step] = now
+			added = append(added, step)
+		}
+	}
+	if state.FinishedAt == nil && len(state.CompletedAt) == len(models.OnboardingSteps) {
+		state.FinishedAt = &now
+	}
+	return copyOnboardingState(state), added, nil
+}
+
+func (obs *OnboardingService) isTestMode() bool {
+	return obs.firestoreService.client == nil
+}
+
+// mockOnboardingState returns the stored state, creating it; callers hold mockOnboardingMu
+func mockOnboardingState(userID string) *models.OnboardingState {
+	state, ok := mockOnboarding[userID]
+	if !ok {
+		state = &models.OnboardingState{UserID: userID, CompletedAt: make(map[string]time.Time)}
+		mockOnboarding[userID] = state
+	}
+	return state
+}
+
+func copyOnboardingState(state *models.OnboardingState) *models.OnboardingState {
+	result := *state
+	result.CompletedAt = make(map[string]time.Time, len(state.CompletedAt))
+	for step, at := range state.CompletedAt {
+		result.CompletedAt[step] = at
+	}
+	return &result
+}
+
+// === Mock storage in-memory for development/test ===
+
+var (
+	mockOnboardingMu sync.Mutex
+	mockOnboarding   = make(map[string]*models.OnboardingState) // userID -> checklist state
+)
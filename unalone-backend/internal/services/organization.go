@@ -0,0 +1,410 @@
+// Organization service for campus and organization deployments: membership, roles and invite codes
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"unalone-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrOrgNotFound       = errors.New("organization not found")
+	ErrOrgInviteInvalid  = errors.New("invalid invite code")
+	ErrOrgAlreadyMember  = errors.New("you already belong to an organization; leave it first")
+	ErrNotOrgMember      = errors.New("you do not belong to an organization")
+	ErrOrgMemberNotFound = errors.New("member not found")
+	ErrOrgLastAdmin      = errors.New("an organization needs at least one admin")
+)
+
+const (
+	// orgInviteCodeLength is the length of generated invite codes
+	orgInviteCodeLength = 8
+	// orgInviteAlphabet leaves out characters that are easy to misread
+	orgInviteAlphabet = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
+)
+
+// OrganizationService manages organizations and who belongs to them. A user belongs to at most
+// one organization; the membership is stored on the user so every request can scope by it.
+type OrganizationService struct {
+	firestoreService *FirestoreService
+	userService      *UserService
+
+	mu sync.Mutex // Serializes membership changes so counts and the last-admin rule hold
+}
+
+// NewOrganizationService creates a new organization service
+func NewOrganizationService(fs *FirestoreService, us *UserService) *OrganizationService {
+	return &OrganizationService{firestoreService: fs, userService: us}
+}
+
+// OrgOf returns the organization a user belongs to, or "" for the public network
+func (ors *OrganizationService) OrgOf(userID string) string {
+	return userOrgID(ors.userService, userID)
+}
+
+// IsOrgAdmin returns the user's organization when they are one of its admins
+func (ors *OrganizationService) IsOrgAdmin(userID string) (string, bool) {
+	user, err := ors.userService.GetUserByID(userID)
+	if err != nil || user.OrgID == "" || user.OrgRole != models.OrgRoleAdmin {
+		return "", false
+	}
+	return user.OrgID, true
+}
+
+// CreateOrganization creates an organization with adminUserID as its first admin
+func (ors *OrganizationService) CreateOrganization(createdBy string, req *models.CreateOrganizationRequest) (*models.OrgMembership, error) {
+	ors.mu.Lock()
+	defer ors.mu.Unlock()
+
+	admin, err := ors.userService.GetUserByID(req.AdminUserID)
+	if err != nil {
+		return nil, err
+	}
+	if admin.OrgID != "" {
+		return nil, ErrOrgAlreadyMember
+	}
+	code, err := ors.uniqueInviteCode()
+	if err != nil {
+		return nil, err
+	}
+	now := clock.Now()
+	org := &models.Organization{
+		ID:         uuid.New().String(),
+		Name:       strings.TrimSpace(req.Name),
+		InviteCode: code,
+		CreatedBy:  createdBy,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := ors.saveOrganization(org); err != nil {
+		return nil, err
+	}
+	return ors.addMember(org, admin.ID, models.OrgRoleAdmin)
+}
+
+// ListOrganizations returns every organization by name
+func (ors *OrganizationService) ListOrganizations() ([]models.Organization, error) {
+	if !ors.isTestMode() {
+		// TODO: Query Firestore organizations ordered by name
+		return nil, errors.New("firestore implementation needed")
+	}
+
+	mockOrganizationsMu.RLock()
+	defer mockOrganizationsMu.RUnlock()
+	orgs := make([]models.Organization, 0, len(mockOrganizations))
+	for _, org := range mockOrganizations {
+		orgs = append(orgs, *org)
+	}
+	sort.Slice(orgs, func(i, j int) bool {
+		if orgs[i].Name != orgs[j].Name {
+			return orgs[i].Name < orgs[j].Name
+		}
+		return orgs[i].ID < orgs[j].ID
+	})
+	return orgs, nil
+}
+
+// GetMembership returns the user's organization and role. Only admins see the invite code.
+func (ors *OrganizationService) GetMembership(userID string) (*models.OrgMembership, error) {
+	user, err := ors.userService.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.OrgID == "" {
+		return nil, ErrNotOrgMember
+	}
+	org, err := ors.getOrganization(user.OrgID)
+	if err != nil {
+		return nil, err
+	}
+	return membershipOf(org, user.OrgRole, user.OrgJoinedAt), nil
+}
+
+// Join adds the user to the organization with the invite code as a member
+func (ors *OrganizationService) Join(userID, inviteCode string) (*models.OrgMembership, error) {
+	ors.mu.Lock()
+	defer ors.mu.Unlock()
+
+	user, err := ors.userService.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.OrgID != "" {
+		return nil, ErrOrgAlreadyMember
+	}
+	org, err := ors.organizationByInviteCode(inviteCode)
+	if err != nil {
+		return nil, err
+	}
+	return ors.addMember(org, userID, models.OrgRoleMember)
+}
+
+// Leave removes the user from their organization. The last admin cannot leave while others remain.
+func (ors *OrganizationService) Leave(userID string) error {
+	ors.mu.Lock()
+	defer ors.mu.Unlock()
+
+	user, err := ors.userService.GetUserByID(userID)
+	if err != nil {
+		return err
+	}
+	if user.OrgID == "" {
+		return ErrNotOrgMember
+	}
+	org, err := ors.getOrganization(user.OrgID)
+	if err != nil {
+		return err
+	}
+	if err := ors.checkNotLastAdmin(org, user, org.MemberCount > 1); err != nil {
+		return err
+	}
+	return ors.removeMember(org, userID)
+}
+
+// Members lists an organization's members, admins first, reading within the request budget in ctx
+func (ors *OrganizationService) Members(ctx context.Context, orgID string) ([]models.OrgMember, error) {
+	users, err := ors.userService.ListOrgMembers(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	members := make([]models.OrgMember, 0, len(users))
+	for _, user := range users {
+		members = append(members, models.OrgMember{UserID: user.ID, Nickname: user.Nickname, Role: user.OrgRole, JoinedAt: user.OrgJoinedAt})
+	}
+	sort.Slice(members, func(i, j int) bool {
+		if members[i].Role != members[j].Role {
+			return members[i].Role == models.OrgRoleAdmin
+		}
+		return members[i].Nickname < members[j].Nickname
+	})
+	return members, nil
+}
+
+// SetRole changes a member's role; the last admin cannot be demoted
+func (ors *OrganizationService) SetRole(orgID, memberID, role string) (*models.OrgMember, error) {
+	ors.mu.Lock()
+	defer ors.mu.Unlock()
+
+	org, member, err := ors.member(orgID, memberID)
+	if err != nil {
+		return nil, err
+	}
+	if role != models.OrgRoleAdmin {
+		if err := ors.checkNotLastAdmin(org, member, true); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := ors.userService.UpdateUser(memberID, map[string]interface{}{"org_role": role}); err != nil {
+		return nil, err
+	}
+	return &models.OrgMember{UserID: member.ID, Nickname: member.Nickname, Role: role, JoinedAt: member.OrgJoinedAt}, nil
+}
+
+// RemoveMember takes a member out of the organization; the last admin cannot be removed
+func (ors *OrganizationService) RemoveMember(orgID, memberID string) error {
+	ors.mu.Lock()
+	defer ors.mu.Unlock()
+
+	org, member, err := ors.member(orgID, memberID)
+	if err != nil {
+		return err
+	}
+	if err := ors.checkNotLastAdmin(org, member, true); err != nil {
+		return err
+	}
+	return ors.removeMember(org, memberID)
+}
+
+// RotateInviteCode replaces the invite code; the old one stops working at once
+func (ors *OrganizationService) RotateInviteCode(orgID string) (*models.Organization, error) {
+	ors.mu.Lock()
+	defer ors.mu.Unlock()
+
+	org, err := ors.getOrganization(orgID)
+	if err != nil {
+		return nil, err
+	}
+	code, err := ors.uniqueInviteCode()
+	if err != nil {
+		return nil, err
+	}
+	org.InviteCode = code
+	org.UpdatedAt = clock.Now()
+	if err := ors.saveOrganization(org); err != nil {
+		return nil, err
+	}
+	return org, nil
+}
+
+// member loads an organization and one of its members
+func (ors *OrganizationService) member(orgID, memberID string) (*models.Organization, *models.User, error) {
+	org, err := ors.getOrganization(orgID)
+	if err != nil {
+		return nil, nil, err
+	}
+	user, err := ors.userService.GetUserByID(memberID)
+	if err != nil || user.OrgID != orgID {
+		return nil, nil, ErrOrgMemberNotFound
+	}
+	return org, user, nil
+}
+
+// checkNotLastAdmin fails when user is the organization's only admin and others depend on them
+func (ors *OrganizationService) checkNotLastAdmin(org *models.Organization, user *models.User, othersRemain bool) error {
+	if user.OrgRole != models.OrgRoleAdmin || !othersRemain {
+		return nil
+	}
+	members, err := ors.userService.ListOrgMembers(ors.firestoreService.GetContext(), org.ID)
+	if err != nil {
+		return err
+	}
+	for _, m := range members {
+		if m.ID != user.ID && m.OrgRole == models.OrgRoleAdmin {
+			return nil
+		}
+	}
+	return ErrOrgLastAdmin
+}
+
+// addMember stores the membership on the user and counts them
+func (ors *OrganizationService) addMember(org *models.Organization, userID, role string) (*models.OrgMembership, error) {
+	now := clock.Now()
+	if _, err := ors.userService.UpdateUser(userID, map[string]interface{}{
+		"org_id":        org.ID,
+		"org_role":      role,
+		"org_joined_at": &now,
+	}); err != nil {
+		return nil, err
+	}
+	org.MemberCount++
+	org.UpdatedAt = now
+	if err := ors.saveOrganization(org); err != nil {
+		return nil, err
+	}
+	return membershipOf(org, role, &now), nil
+}
+
+// removeMember clears the membership from the user
+func (ors *OrganizationService) removeMember(org *models.Organization, userID string) error {
+	if _, err := ors.userService.UpdateUser(userID, map[string]interface{}{
+		"org_id":        "",
+		"org_role":      "",
+		"org_joined_at": (*time.Time)(nil),
+	}); err != nil {
+		return err
+	}
+	if org.MemberCount > 0 {
+		org.MemberCount--
+	}
+	org.UpdatedAt = clock.Now()
+	return ors.saveOrganization(org)
+}
+
+// uniqueInviteCode generates an invite code no organization uses
+func (ors *OrganizationService) uniqueInviteCode() (string, error) {
+	for {
+		buf := make([]byte, orgInviteCodeLength)
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+		for i, b := range buf {
+			buf[i] = orgInviteAlphabet[int(b)%len(orgInviteAlphabet)]
+		}
+		code := string(buf)
+		if _, err := ors.organizationByInviteCode(code); errors.Is(err, ErrOrgInviteInvalid) {
+			return code, nil
+		} else if err != nil {
+			return "", err
+		}
+	}
+}
+
+func (ors *OrganizationService) getOrganization(orgID string) (*models.Organization, error) {
+	if !ors.isTestMode() {
+		// TODO: Get Firestore document organizations/{orgID}
+		return nil, errors.New("firestore implementation needed")
+	}
+
+	mockOrganizationsMu.RLock()
+	defer mockOrganizationsMu.RUnlock()
+	org, ok := mockOrganizations[orgID]
+	if !ok {
+		return nil, ErrOrgNotFound
+	}
+	copied := *org
+	return &copied, nil
+}
+
+// organizationByInviteCode finds an organization by its invite code, ignoring case and spaces
+func (ors *OrganizationService) organizationByInviteCode(code string) (*models.Organization, error) {
+	code = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(code), " ", ""))
+	if code == "" {
+		return nil, ErrOrgInviteInvalid
+	}
+	if !ors.isTestMode() {
+		// TODO: Query Firestore organizations where invite_code == code
+		return nil, errors.New("firestore implementation needed")
+	}
+
+	mockOrganizationsMu.RLock()
+	defer mockOrganizationsMu.RUnlock()
+	for _, org := range mockOrganizations {
+		if org.InviteCode == code {
+			copied := *org
+			return &copied, nil
+		}
+	}
+	return nil, ErrOrgInviteInvalid
+}
+
+func (ors *OrganizationService) saveOrganization(org *models.Organization) error {
+	if !ors.isTestMode() {
+		// TODO: Set Firestore document organizations/{org.ID}
+		return errors.New("firestore implementation needed")
+	}
+
+	mockOrganizationsMu.Lock()
+	defer mockOrganizationsMu.Unlock()
+	copied := *org
+	mockOrganizations[org.ID] = &copied
+	return nil
+}
+
+// isTestMode checks if we're running with mocked database
+func (ors *OrganizationService) isTestMode() bool {
+	return ors.firestoreService.client == nil
+}
+
+// membershipOf builds a membership view, showing the invite code to admins only
+func membershipOf(org *models.Organization, role string, joinedAt *time.Time) *models.OrgMembership {
+	membership := &models.OrgMembership{Organization: *org, Role: role, JoinedAt: joinedAt}
+	if role != models.OrgRoleAdmin {
+		membership.Organization.InviteCode = ""
+	}
+	return membership
+}
+
+// userOrgID returns the organization a user belongs to, or "" for the public network.
+// Unknown users are treated as outside every organization.
+func userOrgID(us *UserService, userID string) string {
+	user, err := us.GetUserByID(userID)
+	if err != nil {
+		return ""
+	}
+	return user.OrgID
+}
+
+// === Mock storage in-memory for development/test ===
+
+var (
+	mockOrganizationsMu sync.RWMutex
+	mockOrganizations   = make(map[string]*models.Organization) // orgID -> organization
+)
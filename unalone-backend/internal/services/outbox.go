@@ -0,0 +1,174 @@
+// Outbox storing domain events with the change that produced them until they reach the event bus
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"unalone-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+const (
+	outboxPollInterval = 5 * time.Second
+	outboxBatchSize    = 100
+	// outboxMaxAttempts bounds retries; backoff doubles from outboxBaseBackoff up to outboxMaxBackoff
+	outboxMaxAttempts = 10
+	outboxBaseBackoff = time.Second
+	outboxMaxBackoff  = 5 * time.Minute
+)
+
+// Outbox makes event publishing reliable: services record events next to their
+// write, and a dispatcher delivers them to the event bus, retrying with backoff
+type Outbox struct {
+	firestoreService *FirestoreService
+	bus              *EventBus
+	wake             chan struct{}
+}
+
+// NewOutbox creates a new outbox delivering to bus
+func NewOutbox(fs *FirestoreService, bus *EventBus) *Outbox {
+	return &Outbox{firestoreService: fs, bus: bus, wake: make(chan struct{}, 1)}
+}
+
+// Record stores an event for delivery. Call it right after the write it describes;
+// with Firestore it belongs in the same transaction. A nil outbox is a no-op.
+func (ob *Outbox) Record(event *models.DomainEvent) {
+	if ob == nil {
+		return
+	}
+	now := time.Now()
+	if event.ID == "" {
+		event.ID = uuid.New().String()
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = now
+	}
+	entry := &models.OutboxEntry{
+		ID:            event.ID,
+		Event:         *event,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+
+	if !ob.isTestMode() {
+		// TODO: Write outbox/{id} with tx.Create inside the transaction of the primary change
+		log.Printf("Outbox: event %s not stored: firestore implementation needed", event.Type)
+		return
+	}
+	mockOutboxMu.Lock()
+	mockOutbox[entry.ID] = entry
+	mockOutboxMu.Unlock()
+
+	// Deliver promptly instead of waiting for the next poll
+	select {
+	case ob.wake <- struct{}{}:
+	default:
+	}
+}
+
+// StartDispatcher delivers pending entries until ctx is cancelled
+func (ob *Outbox) StartDispatcher(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(outboxPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-ob.wake:
+			}
+			if _, err := ob.DispatchPending(time.Now()); err != nil {
+				log.Printf("Outbox: dispatch failed: %v", err)
+			}
+		}
+	}()
+}
+
+// DispatchPending hands due entries to the event bus, oldest first, and returns how many were delivered
+func (ob *Outbox) DispatchPending(now time.Time) (int, error) {
+	if !ob.isTestMode() {
+		// TODO: Query outbox where dispatched_at == nil and failed_at == nil and next_attempt_at <= now, ordered by created_at
+		return 0, errors.New("firestore implementation needed")
+	}
+
+	mockOutboxMu.Lock()
+	defer mockOutboxMu.Unlock()
+	due := make([]*models.OutboxEntry, 0)
+	for _, entry := range mockOutbox {
+		if entry.DispatchedAt == nil && entry.FailedAt == nil && !entry.NextAttemptAt.After(now) {
+			due = append(due, entry)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].CreatedAt.Before(due[j].CreatedAt) })
+	if len(due) > outboxBatchSize {
+		due = due[:outboxBatchSize]
+	}
+
+	delivered := 0
+	for _, entry := range due {
+		entry.Attempts++
+		event := entry.Event
+		if err := ob.bus.Publish(&event); err != nil {
+			ob.scheduleRetry(entry, err, now)
+			continue
+		}
+		dispatchedAt := now
+		entry.DispatchedAt = &dispatchedAt
+		entry.LastError = ""
+		delivered++
+	}
+	return delivered, nil
+}
+
+// PurgeDispatched deletes entries delivered before cutoff and returns how many were removed
+func (ob *Outbox) PurgeDispatched(cutoff time.Time) (int, error) {
+	if !ob.isTestMode() {
+		// TODO: Delete outbox documents with dispatched_at < cutoff in batches
+		return 0, errors.New("firestore implementation needed")
+	}
+
+	mockOutboxMu.Lock()
+	defer mockOutboxMu.Unlock()
+	purged := 0
+	for id, entry := range mockOutbox {
+		if entry.DispatchedAt != nil && entry.DispatchedAt.Before(cutoff) {
+			delete(mockOutbox, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// scheduleRetry backs an entry off exponentially, giving up after outboxMaxAttempts
+func (ob *Outbox) scheduleRetry(entry *models.OutboxEntry, err error, now time.Time) {
+	entry.LastError = err.Error()
+	if entry.Attempts >= outboxMaxAttempts {
+		failedAt := now
+		entry.FailedAt = &failedAt
+		log.Printf("Outbox: giving up on %s event %s after %d attempts: %v", entry.Event.Type, entry.ID, entry.Attempts, err)
+		return
+	}
+	backoff := outboxBaseBackoff << (entry.Attempts - 1)
+	if backoff > outboxMaxBackoff {
+		backoff = outboxMaxBackoff
+	}
+	entry.NextAttemptAt = now.Add(backoff)
+}
+
+// isTestMode checks if we're running with mocked database
+func (ob *Outbox) isTestMode() bool {
+	return ob.firestoreService.client == nil
+}
+
+// Mock storage for testing
+var (
+	mockOutboxMu sync.Mutex
+	mockOutbox   = make(map[string]*models.OutboxEntry) // entry ID -> entry
+)
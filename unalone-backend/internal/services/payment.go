@@ -0,0 +1,838 @@
+// Payments for ticketed hotspots: pluggable providers, ticket holds, webhook confirmation and refunds
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"unalone-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// Payment errors surfaced to handlers
+var (
+	ErrTicketRequired             = errors.New("a ticket is required to join this hotspot")
+	ErrPaymentsUnavailable        = errors.New("payments are not available right now")
+	ErrPaymentUnknownProvider     = errors.New("unknown payment provider")
+	ErrPaymentWebhookUnauthorized = errors.New("invalid webhook signature")
+	ErrTicketNotFound             = errors.New("ticket not found")
+	ErrTicketTierNotFound         = errors.New("ticket tier not found")
+	ErrTicketsSoldOut             = errors.New("tickets are sold out")
+	ErrTicketAlreadyOwned         = errors.New("you already have a ticket for this hotspot")
+	ErrTicketTierLocked           = errors.New("tickets have been sold for this tier: it cannot be removed, repriced or shrunk below the number sold")
+)
+
+const (
+	paymentRequestTimeout = 15 * time.Second
+	// ticketHoldDuration is how long a pending ticket keeps its place while the buyer pays
+	ticketHoldDuration = 15 * time.Minute
+	// paymentWebhookMaxBytes caps webhook bodies read for signature checks
+	paymentWebhookMaxBytes = 1 << 20
+	// stripeSignatureTolerance rejects replayed Stripe webhooks
+	stripeSignatureTolerance = 5 * time.Minute
+	defaultTicketCurrency    = "INR"
+)
+
+// PaymentProvider takes payments for tickets and reports them through its own webhooks
+type PaymentProvider interface {
+	Name() string
+	// CreateOrder opens a payment for the ticket's amount and fills in what the app needs for checkout
+	CreateOrder(ctx context.Context, ticket *models.Ticket) (*models.PaymentCheckout, error)
+	// Refund returns a paid ticket's money and returns the provider's refund ID
+	Refund(ctx context.Context, ticket *models.Ticket) (string, error)
+	// ParseWebhook verifies a webhook's signature and reads the payment updates in it
+	ParseWebhook(r *http.Request, body []byte) ([]models.PaymentEvent, error)
+}
+
+// PaymentService sells hotspot tickets and keeps their status in step with the payment provider
+type PaymentService struct {
+	firestoreService *FirestoreService
+	hotspotService   *HotspotService
+	providers        map[string]PaymentProvider
+	defaultProvider  PaymentProvider
+}
+
+// NewPaymentService creates a new payment service. PAYMENT_PROVIDER selects the provider
+// (razorpay, stripe or log); providers without credentials are skipped. The log provider
+// confirms nothing by itself and is only usable with the in-memory database.
+// Credentials come from the secrets provider.
+func NewPaymentService(fs *FirestoreService, hs *HotspotService, secrets SecretsProvider) *PaymentService {
+	httpClient := &http.Client{Timeout: paymentRequestTimeout}
+	ps := &PaymentService{
+		firestoreService: fs,
+		hotspotService:   hs,
+		providers:        map[string]PaymentProvider{"log": logPaymentProvider{}},
+	}
+
+	ctx := context.Background()
+	if keyID, keySecret := lookupSecret(ctx, secrets, "RAZORPAY_KEY_ID"), lookupSecret(ctx, secrets, "RAZORPAY_KEY_SECRET"); keyID != "" && keySecret != "" {
+		ps.providers["razorpay"] = &razorpayPaymentProvider{
+			keyID:         keyID,
+			keySecret:     keySecret,
+			webhookSecret: lookupSecret(ctx, secrets, "RAZORPAY_WEBHOOK_SECRET"),
+			httpClient:    httpClient,
+		}
+	}
+	if secretKey := lookupSecret(ctx, secrets, "STRIPE_SECRET_KEY"); secretKey != "" {
+		ps.providers["stripe"] = &stripePaymentProvider{
+			secretKey:     secretKey,
+			webhookSecret: lookupSecret(ctx, secrets, "STRIPE_WEBHOOK_SECRET"),
+			httpClient:    httpClient,
+		}
+	}
+
+	name := strings.ToLower(strings.TrimSpace(os.Getenv("PAYMENT_PROVIDER")))
+	if name == "" {
+		name = "log"
+	}
+	provider, ok := ps.providers[name]
+	if !ok {
+		log.Printf("Payment provider %q is not configured, falling back to log", name)
+		provider = ps.providers["log"]
+	}
+	ps.defaultProvider = provider
+	return ps
+}
+
+// ProviderName returns the provider new tickets are sold through
+func (ps *PaymentService) ProviderName() string {
+	return ps.defaultProvider.Name()
+}
+
+// HasValidTicket reports whether the user holds a paid ticket for the hotspot
+func (ps *PaymentService) HasValidTicket(hotspotID, userID string) bool {
+	ticket, err := ps.validTicket(hotspotID, userID)
+	return err == nil && ticket != nil
+}
+
+// HotspotTickets lists a hotspot's tiers with how many tickets are left, and the user's own ticket
+func (ps *PaymentService) HotspotTickets(hotspot *models.Hotspot, userID string) (*models.HotspotTickets, error) {
+	taken, err := ps.takenByTier(hotspot.ID)
+	if err != nil {
+		return nil, err
+	}
+	result := &models.HotspotTickets{HotspotID: hotspot.ID, Tiers: make([]models.TicketTierAvailability, 0, len(hotspot.TicketTiers))}
+	for _, tier := range hotspot.TicketTiers {
+		entry := models.TicketTierAvailability{TicketTier: tier}
+		if tier.Capacity > 0 {
+			available := max(tier.Capacity-taken[tier.ID], 0)
+			entry.Available = &available
+		}
+		result.Tiers = append(result.Tiers, entry)
+	}
+	if result.MyTicket, err = ps.validTicket(hotspot.ID, userID); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SetTicketTiers replaces a hotspot's tiers (host only). Tiers that have sold tickets
+// must stay, keep their price and currency, and keep room for the tickets sold.
+func (ps *PaymentService) SetTicketTiers(userID, hotspotID string, inputs []models.TicketTierInput) (*models.Hotspot, error) {
+	hotspot, err := ps.hotspotService.GetHotspot(hotspotID)
+	if err != nil {
+		return nil, err
+	}
+	if hotspot.CreatedBy != userID {
+		return nil, errors.New("only the host can change ticket tiers")
+	}
+	sold, err := ps.takenByTier(hotspotID)
+	if err != nil {
+		return nil, err
+	}
+
+	tiers, err := BuildTicketTiers(inputs, hotspot.TicketTiers)
+	if err != nil {
+		return nil, err
+	}
+	kept := make(map[string]models.TicketTier, len(tiers))
+	for _, tier := range tiers {
+		kept[tier.ID] = tier
+	}
+	for _, old := range hotspot.TicketTiers {
+		if sold[old.ID] == 0 {
+			continue
+		}
+		tier, ok := kept[old.ID]
+		if !ok || tier.Price != old.Price || tier.Currency != old.Currency || (tier.Capacity > 0 && tier.Capacity < sold[old.ID]) {
+			return nil, ErrTicketTierLocked
+		}
+	}
+	return ps.hotspotService.UpdateTicketTiers(userID, hotspot, tiers)
+}
+
+// BuildTicketTiers turns requested tiers into stored ones, keeping the IDs of existing tiers
+func BuildTicketTiers(inputs []models.TicketTierInput, existing []models.TicketTier) ([]models.TicketTier, error) {
+	known := make(map[string]bool, len(existing))
+	for _, tier := range existing {
+		known[tier.ID] = true
+	}
+	tiers := make([]models.TicketTier, 0, len(inputs))
+	for _, input := range inputs {
+		id := input.ID
+		if id == "" {
+			id = uuid.New().String()
+		} else if !known[id] {
+			return nil, ErrTicketTierNotFound
+		}
+		currency := strings.ToUpper(strings.TrimSpace(input.Currency))
+		if currency == "" {
+			currency = defaultTicketCurrency
+		}
+		tiers = append(tiers, models.TicketTier{
+			ID:       id,
+			Name:     strings.TrimSpace(input.Name),
+			Price:    input.Price,
+			Currency: currency,
+			Capacity: input.Capacity,
+		})
+	}
+	return tiers, nil
+}
+
+// PurchaseTicket holds a place in a tier and opens a payment for it. The ticket becomes valid
+// once the provider's webhook confirms payment; unpaid holds lapse after ticketHoldDuration.
+func (ps *PaymentService) PurchaseTicket(ctx context.Context, userID, hotspotID, tierID string) (*models.PaymentCheckout, error) {
+	if ps.defaultProvider.Name() == "log" && !ps.isTestMode() {
+		return nil, ErrPaymentsUnavailable
+	}
+	hotspot, err := ps.hotspotService.GetHotspot(hotspotID)
+	if err != nil {
+		return nil, err
+	}
+	// Buyers have to be able to join once they hold a ticket
+	if err := ps.hotspotService.CheckJoin(hotspot, userID, false); err != nil && !errors.Is(err, ErrTicketRequired) {
+		return nil, err
+	}
+	var tier *models.TicketTier
+	for i := range hotspot.TicketTiers {
+		if hotspot.TicketTiers[i].ID == tierID {
+			tier = &hotspot.TicketTiers[i]
+		}
+	}
+	if tier == nil {
+		return nil, ErrTicketTierNotFound
+	}
+
+	now := clock.Now()
+	ticket := &models.Ticket{
+		ID:            uuid.New().String(),
+		HotspotID:     hotspot.ID,
+		HotspotName:   hotspot.Name,
+		TierID:        tier.ID,
+		TierName:      tier.Name,
+		UserID:        userID,
+		Amount:        tier.Price,
+		Currency:      tier.Currency,
+		Status:        models.TicketStatusPending,
+		Provider:      ps.defaultProvider.Name(),
+		HoldExpiresAt: now.Add(ticketHoldDuration),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := ps.holdPlace(hotspot, tier, ticket); err != nil {
+		return nil, err
+	}
+
+	checkout, err := ps.defaultProvider.CreateOrder(ctx, ticket)
+	if err != nil {
+		log.Printf("Payment order via %s for ticket %s failed: %v", ticket.Provider, ticket.ID, err)
+		ps.updateTicket(ticket.ID, func(t *models.Ticket) { t.Status = models.TicketStatusFailed })
+		return nil, errors.New("failed to start payment")
+	}
+	ticket = ps.updateTicket(ticket.ID, func(t *models.Ticket) { t.ProviderOrderID = checkout.OrderID })
+	checkout.Ticket = ticket
+	checkout.Provider = ticket.Provider
+	return checkout, nil
+}
+
+// HandleWebhook applies a provider's payment updates to the tickets they belong to
+func (ps *PaymentService) HandleWebhook(ctx context.Context, providerName string, r *http.Request) error {
+	provider, ok := ps.providers[providerName]
+	if !ok {
+		return ErrPaymentUnknownProvider
+	}
+	// The log provider signs nothing, so it only stands in for a provider in development
+	if providerName == "log" && !ps.isTestMode() {
+		return ErrPaymentWebhookUnauthorized
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, paymentWebhookMaxBytes))
+	if err != nil {
+		return err
+	}
+	events, err := provider.ParseWebhook(r, body)
+	if err != nil {
+		return err
+	}
+	for _, event := range events {
+		ps.applyPaymentEvent(ctx, providerName, event)
+	}
+	return nil
+}
+
+// applyPaymentEvent confirms or fails a ticket. Payments arriving after the hold lapsed,
+// or after the hotspot was cancelled, are refunded.
+func (ps *PaymentService) applyPaymentEvent(ctx context.Context, providerName string, event models.PaymentEvent) {
+	ticket, err := ps.ticketByOrder(providerName, event.OrderID)
+	if err != nil {
+		log.Printf("Payment webhook from %s for unknown order %s", providerName, event.OrderID)
+		return
+	}
+
+	switch event.Status {
+	case models.TicketStatusPaid:
+		if ticket.Status == models.TicketStatusPaid || ticket.Status == models.TicketStatusRefunded || ticket.Status == models.TicketStatusRefundPending {
+			return // Providers retry webhooks; the first delivery already counted
+		}
+		now := clock.Now()
+		late := ticket.Status != models.TicketStatusPending || now.After(ticket.HoldExpiresAt)
+		ticket = ps.updateTicket(ticket.ID, func(t *models.Ticket) {
+			t.Status = models.TicketStatusPaid
+			t.ProviderPaymentID = event.PaymentID
+			t.PaidAt = &now
+		})
+		hotspot, err := ps.hotspotService.GetHotspot(ticket.HotspotID)
+		if late || err != nil || hotspot.CancelledAt != nil {
+			ps.refundTicket(ctx, ticket)
+		}
+	case models.TicketStatusFailed:
+		if ticket.Status == models.TicketStatusPending {
+			ps.updateTicket(ticket.ID, func(t *models.Ticket) { t.Status = models.TicketStatusFailed })
+		}
+	}
+}
+
+// RefundHotspot refunds every paid ticket of a cancelled hotspot and releases unpaid holds.
+// It returns how many tickets were refunded; failed refunds are retried by RunMaintenance.
+func (ps *PaymentService) RefundHotspot(ctx context.Context, hotspotID string) (int, error) {
+	tickets, err := ps.hotspotTickets(hotspotID)
+	if err != nil {
+		return 0, err
+	}
+	refunded := 0
+	for _, ticket := range tickets {
+		switch ticket.Status {
+		case models.TicketStatusPaid:
+			if ps.refundTicket(ctx, ticket) {
+				refunded++
+			}
+		case models.TicketStatusPending:
+			ps.updateTicket(ticket.ID, func(t *models.Ticket) { t.Status = models.TicketStatusExpired })
+		}
+	}
+	return refunded, nil
+}
+
+// RunMaintenance expires lapsed holds and retries refunds that failed
+func (ps *PaymentService) RunMaintenance(ctx context.Context) error {
+	if !ps.isTestMode() {
+		// TODO: Query Firestore tickets where status in (pending, refund_pending)
+		return errors.New("firestore implementation needed")
+	}
+
+	now := clock.Now()
+	var retry []*models.Ticket
+	mockTicketsMu.Lock()
+	for _, ticket := range mockTickets {
+		switch {
+		case ticket.Status == models.TicketStatusPending && now.After(ticket.HoldExpiresAt):
+			ticket.Status = models.TicketStatusExpired
+			ticket.UpdatedAt = now
+		case ticket.Status == models.TicketStatusRefundPending:
+			copied := *ticket
+			retry = append(retry, &copied)
+		}
+	}
+	mockTicketsMu.Unlock()
+
+	for _, ticket := range retry {
+		ps.refundTicket(ctx, ticket)
+	}
+	return nil
+}
+
+// refundTicket asks the ticket's provider for a refund, leaving it refund_pending when that fails
+func (ps *PaymentService) refundTicket(ctx context.Context, ticket *models.Ticket) bool {
+	provider, ok := ps.providers[ticket.Provider]
+	if !ok {
+		ps.updateTicket(ticket.ID, func(t *models.Ticket) {
+			t.Status = models.TicketStatusRefundPending
+			t.RefundError = "provider not configured"
+		})
+		return false
+	}
+	refundID, err := provider.Refund(ctx, ticket)
+	if err != nil {
+		log.Printf("Refund via %s for ticket %s failed: %v", ticket.Provider, ticket.ID, err)
+		ps.updateTicket(ticket.ID, func(t *models.Ticket) {
+			t.Status = models.TicketStatusRefundPending
+			t.RefundError = err.Error()
+		})
+		return false
+	}
+	now := clock.Now()
+	ps.updateTicket(ticket.ID, func(t *models.Ticket) {
+		t.Status = models.TicketStatusRefunded
+		t.ProviderRefundID = refundID
+		t.RefundError = ""
+		t.RefundedAt = &now
+	})
+	return true
+}
+
+// GetTicket returns one of the user's tickets
+func (ps *PaymentService) GetTicket(userID, ticketID string) (*models.Ticket, error) {
+	if !ps.isTestMode() {
+		// TODO: Implement Firestore read of tickets/{ticketID}
+		return nil, errors.New("firestore implementation needed")
+	}
+
+	mockTicketsMu.Lock()
+	defer mockTicketsMu.Unlock()
+	ticket, ok := mockTickets[ticketID]
+	if !ok || ticket.UserID != userID {
+		return nil, ErrTicketNotFound
+	}
+	copied := *ticket
+	return &copied, nil
+}
+
+// UserTickets lists the user's tickets, newest first
+func (ps *PaymentService) UserTickets(userID string) ([]*models.Ticket, error) {
+	if !ps.isTestMode() {
+		// TODO: Query Firestore tickets where user_id == userID ordered by created_at desc
+		return nil, errors.New("firestore implementation needed")
+	}
+
+	mockTicketsMu.Lock()
+	tickets := []*models.Ticket{}
+	for _, ticket := range mockTickets {
+		if ticket.UserID == userID {
+			copied := *ticket
+			tickets = append(tickets, &copied)
+		}
+	}
+	mockTicketsMu.Unlock()
+	sortTickets(tickets)
+	return tickets, nil
+}
+
+// HotspotSales lists a hotspot's tickets for its host, newest first
+func (ps *PaymentService) HotspotSales(userID, hotspotID string) ([]*models.Ticket, error) {
+	hotspot, err := ps.hotspotService.GetHotspot(hotspotID)
+	if err != nil {
+		return nil, err
+	}
+	if hotspot.CreatedBy != userID {
+		return nil, errors.New("only the host can see ticket sales")
+	}
+	tickets, err := ps.hotspotTickets(hotspotID)
+	if err != nil {
+		return nil, err
+	}
+	sortTickets(tickets)
+	return tickets, nil
+}
+
+// holdPlace stores a pending ticket if its tier and the hotspot still have room.
+// Paid tickets and unexpired holds count against both, as do attendees without a ticket.
+func (ps *PaymentService) holdPlace(hotspot *models.Hotspot, tier *models.TicketTier, ticket *models.Ticket) error {
+	if !ps.isTestMode() {
+		// TODO: Count and create in a Firestore transaction on the hotspot's tickets
+		return errors.New("firestore implementation needed")
+	}
+
+	mockTicketsMu.Lock()
+	defer mockTicketsMu.Unlock()
+	now := clock.Now()
+	tierTaken, placesTaken := 0, len(hotspot.Attendees)
+	for _, other := range mockTickets {
+		if other.HotspotID != hotspot.ID || !ticketHoldsPlace(other, now) {
+			continue
+		}
+		if other.UserID == ticket.UserID {
+			if other.Status == models.TicketStatusPaid {
+				return ErrTicketAlreadyOwned
+			}
+			// Starting checkout again replaces the buyer's unpaid hold
+			other.Status = models.TicketStatusExpired
+			other.UpdatedAt = now
+			continue
+		}
+		if other.TierID == tier.ID {
+			tierTaken++
+		}
+		if !containsString(hotspot.Attendees, other.UserID) {
+			placesTaken++
+		}
+	}
+	if (tier.Capacity > 0 && tierTaken >= tier.Capacity) || (hotspot.MaxCapacity > 0 && placesTaken >= hotspot.MaxCapacity) {
+		return ErrTicketsSoldOut
+	}
+	mockTickets[ticket.ID] = ticket
+	return nil
+}
+
+// takenByTier counts paid tickets and unexpired holds per tier
+func (ps *PaymentService) takenByTier(hotspotID string) (map[string]int, error) {
+	tickets, err := ps.hotspotTickets(hotspotID)
+	if err != nil {
+		return nil, err
+	}
+	now := clock.Now()
+	taken := make(map[string]int)
+	for _, ticket := range tickets {
+		if ticketHoldsPlace(ticket, now) {
+			taken[ticket.TierID]++
+		}
+	}
+	return taken, nil
+}
+
+// validTicket returns the user's paid ticket for the hotspot, or nil
+func (ps *PaymentService) validTicket(hotspotID, userID string) (*models.Ticket, error) {
+	tickets, err := ps.hotspotTickets(hotspotID)
+	if err != nil {
+		return nil, err
+	}
+	for _, ticket := range tickets {
+		if ticket.UserID == userID && ticket.Status == models.TicketStatusPaid {
+			return ticket, nil
+		}
+	}
+	return nil, nil
+}
+
+func (ps *PaymentService) hotspotTickets(hotspotID string) ([]*models.Ticket, error) {
+	if !ps.isTestMode() {
+		// TODO: Query Firestore tickets where hotspot_id == hotspotID
+		return nil, errors.New("firestore implementation needed")
+	}
+
+	mockTicketsMu.Lock()
+	defer mockTicketsMu.Unlock()
+	tickets := []*models.Ticket{}
+	for _, ticket := range mockTickets {
+		if ticket.HotspotID == hotspotID {
+			copied := *ticket
+			tickets = append(tickets, &copied)
+		}
+	}
+	return tickets, nil
+}
+
+func (ps *PaymentService) ticketByOrder(providerName, orderID string) (*models.Ticket, error) {
+	if !ps.isTestMode() {
+		// TODO: Query Firestore tickets where provider == providerName and provider_order_id == orderID
+		return nil, errors.New("firestore implementation needed")
+	}
+
+	mockTicketsMu.Lock()
+	defer mockTicketsMu.Unlock()
+	for _, ticket := range mockTickets {
+		if ticket.Provider == providerName && ticket.ProviderOrderID == orderID && orderID != "" {
+			copied := *ticket
+			return &copied, nil
+		}
+	}
+	return nil, ErrTicketNotFound
+}
+
+// updateTicket applies change to a stored ticket and returns a copy of the result
+func (ps *PaymentService) updateTicket(ticketID string, change func(ticket *models.Ticket)) *models.Ticket {
+	if !ps.isTestMode() {
+		// TODO: Implement Firestore transaction on tickets/{ticketID}
+		return nil
+	}
+
+	mockTicketsMu.Lock()
+	defer mockTicketsMu.Unlock()
+	ticket, ok := mockTickets[ticketID]
+	if !ok {
+		return nil
+	}
+	change(ticket)
+	ticket.UpdatedAt = clock.Now()
+	copied := *ticket
+	return &copied
+}
+
+// isTestMode checks if we're running with mocked database
+func (ps *PaymentService) isTestMode() bool {
+	return ps.firestoreService.client == nil
+}
+
+// ticketHoldsPlace reports whether a ticket counts against capacity: paid, or pending and not yet lapsed
+func ticketHoldsPlace(ticket *models.Ticket, now time.Time) bool {
+	return ticket.Status == models.TicketStatusPaid || (ticket.Status == models.TicketStatusPending && now.Before(ticket.HoldExpiresAt))
+}
+
+func sortTickets(tickets []*models.Ticket) {
+	sort.Slice(tickets, func(i, j int) bool { return tickets[i].CreatedAt.After(tickets[j].CreatedAt) })
+}
+
+// Mock storage for testing
+var (
+	mockTicketsMu sync.Mutex
+	mockTickets   = make(map[string]*models.Ticket) // ticketID -> ticket
+)
+
+// logPaymentProvider writes orders and refunds to the server log for local development.
+// Its webhook takes unsigned JSON {"order_id", "payment_id", "status"} to stand in for a provider.
+type logPaymentProvider struct{}
+
+func (logPaymentProvider) Name() string { return "log" }
+
+func (logPaymentProvider) CreateOrder(_ context.Context, ticket *models.Ticket) (*models.PaymentCheckout, error) {
+	orderID := "log_order_" + uuid.New().String()
+	log.Printf("💳 Payment order %s: %d %s for ticket %s", orderID, ticket.Amount, ticket.Currency, ticket.ID)
+	return &models.PaymentCheckout{OrderID: orderID}, nil
+}
+
+func (logPaymentProvider) Refund(_ context.Context, ticket *models.Ticket) (string, error) {
+	log.Printf("💳 Refund of %d %s for ticket %s", ticket.Amount, ticket.Currency, ticket.ID)
+	return "log_refund_" + uuid.New().String(), nil
+}
+
+func (logPaymentProvider) ParseWebhook(_ *http.Request, body []byte) ([]models.PaymentEvent, error) {
+	var payload struct {
+		OrderID   string `json:"order_id"`
+		PaymentID string `json:"payment_id"`
+		Status    string `json:"status"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil || payload.OrderID == "" {
+		return nil, errors.New("invalid payment webhook")
+	}
+	status := models.TicketStatusFailed
+	if payload.Status == models.TicketStatusPaid {
+		status = models.TicketStatusPaid
+	}
+	return []models.PaymentEvent{{OrderID: payload.OrderID, PaymentID: payload.PaymentID, Status: status}}, nil
+}
+
+// razorpayPaymentProvider takes payments through Razorpay Orders and the Razorpay Checkout widget
+type razorpayPaymentProvider struct {
+	keyID         string
+	keySecret     string
+	webhookSecret string
+	httpClient    *http.Client
+}
+
+func (rp *razorpayPaymentProvider) Name() string { return "razorpay" }
+
+func (rp *razorpayPaymentProvider) CreateOrder(ctx context.Context, ticket *models.Ticket) (*models.PaymentCheckout, error) {
+	payload, _ := json.Marshal(map[string]interface{}{
+		"amount":   ticket.Amount,
+		"currency": ticket.Currency,
+		"receipt":  ticket.ID,
+		"notes":    map[string]string{"ticket_id": ticket.ID, "hotspot_id": ticket.HotspotID},
+	})
+	var out struct {
+		ID string `json:"id"`
+	}
+	if err := rp.post(ctx, "https://api.razorpay.com/v1/orders", payload, &out); err != nil {
+		return nil, err
+	}
+	return &models.PaymentCheckout{OrderID: out.ID, KeyID: rp.keyID}, nil
+}
+
+func (rp *razorpayPaymentProvider) Refund(ctx context.Context, ticket *models.Ticket) (string, error) {
+	if ticket.ProviderPaymentID == "" {
+		return "", errors.New("ticket has no Razorpay payment ID")
+	}
+	payload, _ := json.Marshal(map[string]interface{}{"amount": ticket.Amount})
+	var out struct {
+		ID string `json:"id"`
+	}
+	endpoint := "https://api.razorpay.com/v1/payments/" + url.PathEscape(ticket.ProviderPaymentID) + "/refund"
+	if err := rp.post(ctx, endpoint, payload, &out); err != nil {
+		return "", err
+	}
+	return out.ID, nil
+}
+
+// ParseWebhook checks X-Razorpay-Signature, a hex HMAC-SHA256 of the body with the webhook secret
+func (rp *razorpayPaymentProvider) ParseWebhook(r *http.Request, body []byte) ([]models.PaymentEvent, error) {
+	if rp.webhookSecret == "" || !validHMAC(rp.webhookSecret, body, r.Header.Get("X-Razorpay-Signature")) {
+		return nil, ErrPaymentWebhookUnauthorized
+	}
+
+	var event struct {
+		Event   string `json:"event"`
+		Payload struct {
+			Payment struct {
+				Entity struct {
+					ID      string `json:"id"`
+					OrderID string `json:"order_id"`
+				} `json:"entity"`
+			} `json:"payment"`
+		} `json:"payload"`
+	}
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, errors.New("invalid Razorpay webhook")
+	}
+	payment := event.Payload.Payment.Entity
+	switch event.Event {
+	case "payment.captured", "order.paid":
+		return []models.PaymentEvent{{OrderID: payment.OrderID, PaymentID: payment.ID, Status: models.TicketStatusPaid}}, nil
+	case "payment.failed":
+		return []models.PaymentEvent{{OrderID: payment.OrderID, PaymentID: payment.ID, Status: models.TicketStatusFailed}}, nil
+	default:
+		return nil, nil
+	}
+}
+
+func (rp *razorpayPaymentProvider) post(ctx context.Context, endpoint string, payload []byte, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBasicAuth(rp.keyID, rp.keySecret)
+	return doPaymentRequest(rp.httpClient, req, "razorpay", out)
+}
+
+// stripePaymentProvider takes payments through Stripe PaymentIntents and Stripe Elements
+type stripePaymentProvider struct {
+	secretKey     string
+	webhookSecret string
+	httpClient    *http.Client
+}
+
+func (sp *stripePaymentProvider) Name() string { return "stripe" }
+
+func (sp *stripePaymentProvider) CreateOrder(ctx context.Context, ticket *models.Ticket) (*models.PaymentCheckout, error) {
+	form := url.Values{}
+	form.Set("amount", strconv.FormatInt(ticket.Amount, 10))
+	form.Set("currency", strings.ToLower(ticket.Currency))
+	form.Set("automatic_payment_methods[enabled]", "true")
+	form.Set("metadata[ticket_id]", ticket.ID)
+	form.Set("metadata[hotspot_id]", ticket.HotspotID)
+	var out struct {
+		ID           string `json:"id"`
+		ClientSecret string `json:"client_secret"`
+	}
+	if err := sp.post(ctx, "https://api.stripe.com/v1/payment_intents", form, ticket.ID, &out); err != nil {
+		return nil, err
+	}
+	return &models.PaymentCheckout{OrderID: out.ID, ClientSecret: out.ClientSecret}, nil
+}
+
+func (sp *stripePaymentProvider) Refund(ctx context.Context, ticket *models.Ticket) (string, error) {
+	form := url.Values{}
+	form.Set("payment_intent", ticket.ProviderOrderID)
+	var out struct {
+		ID string `json:"id"`
+	}
+	if err := sp.post(ctx, "https://api.stripe.com/v1/refunds", form, "refund-"+ticket.ID, &out); err != nil {
+		return "", err
+	}
+	return out.ID, nil
+}
+
+// ParseWebhook checks the Stripe-Signature header: an HMAC-SHA256 of "timestamp.body"
+// with the endpoint secret, signed within stripeSignatureTolerance
+func (sp *stripePaymentProvider) ParseWebhook(r *http.Request, body []byte) ([]models.PaymentEvent, error) {
+	if sp.webhookSecret == "" {
+		return nil, ErrPaymentWebhookUnauthorized
+	}
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(r.Header.Get("Stripe-Signature"), ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	signedAt, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || time.Since(time.Unix(signedAt, 0)).Abs() > stripeSignatureTolerance {
+		return nil, ErrPaymentWebhookUnauthorized
+	}
+	signed := append([]byte(timestamp+"."), body...)
+	valid := false
+	for _, signature := range signatures {
+		valid = valid || validHMAC(sp.webhookSecret, signed, signature)
+	}
+	if !valid {
+		return nil, ErrPaymentWebhookUnauthorized
+	}
+
+	var event struct {
+		Type string `json:"type"`
+		Data struct {
+			Object struct {
+				ID            string `json:"id"`
+				LatestCharge  string `json:"latest_charge"`
+				PaymentIntent string `json:"payment_intent"`
+			} `json:"object"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, errors.New("invalid Stripe webhook")
+	}
+	intent := event.Data.Object
+	switch event.Type {
+	case "payment_intent.succeeded":
+		return []models.PaymentEvent{{OrderID: intent.ID, PaymentID: intent.LatestCharge, Status: models.TicketStatusPaid}}, nil
+	case "payment_intent.payment_failed":
+		return []models.PaymentEvent{{OrderID: intent.ID, Status: models.TicketStatusFailed}}, nil
+	default:
+		return nil, nil
+	}
+}
+
+func (sp *stripePaymentProvider) post(ctx context.Context, endpoint string, form url.Values, idempotencyKey string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "Bearer "+sp.secretKey)
+	req.Header.Set("Idempotency-Key", idempotencyKey)
+	return doPaymentRequest(sp.httpClient, req, "stripe", out)
+}
+
+// doPaymentRequest sends a provider API request and decodes its JSON response into out
+func doPaymentRequest(client *http.Client, req *http.Request, providerName string, out interface{}) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s returned %d: %s", providerName, resp.StatusCode, string(body))
+	}
+	return json.Unmarshal(body, out)
+}
+
+// validHMAC compares a hex HMAC-SHA256 signature in constant time
+func validHMAC(secret string, payload []byte, signature string) bool {
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
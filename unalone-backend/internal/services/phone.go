@@ -0,0 +1,32 @@
+// Phone number normalization to E.164
+package services
+
+import (
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/nyaruka/phonenumbers"
+)
+
+// ErrInvalidPhoneNumber is returned for numbers that cannot be dialled
+var ErrInvalidPhoneNumber = errors.New("invalid phone number")
+
+// defaultPhoneRegion is assumed for numbers entered without a country code
+const defaultPhoneRegion = "IN"
+
+// NormalizePhoneNumber parses a user-entered number and returns it in E.164 form
+// (e.g. "+919876543210"). Numbers without a country code are read in PHONE_DEFAULT_REGION
+// (an ISO country code, default IN).
+func NormalizePhoneNumber(raw string) (string, error) {
+	region := strings.ToUpper(strings.TrimSpace(os.Getenv("PHONE_DEFAULT_REGION")))
+	if region == "" {
+		region = defaultPhoneRegion
+	}
+
+	number, err := phonenumbers.Parse(strings.TrimSpace(raw), region)
+	if err != nil || !phonenumbers.IsValidNumber(number) {
+		return "", ErrInvalidPhoneNumber
+	}
+	return phonenumbers.Format(number, phonenumbers.E164), nil
+}
@@ -1,12 +1,13 @@
-// Phone verification service using mock SMS for development
+// Phone verification service sending codes through the SMS gateway
 package services
 
 import (
+	"context"
 	"crypto/rand"
 	"errors"
 	"fmt"
-	"log"
 	"math/big"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -17,18 +18,27 @@ import (
 type PhoneVerificationService struct {
 	firestoreService *FirestoreService
 	userService      *UserService
+	smsGateway       *SMSGateway
 }
 
 // NewPhoneVerificationService creates a new phone verification service
-func NewPhoneVerificationService(fs *FirestoreService, us *UserService) *PhoneVerificationService {
+func NewPhoneVerificationService(fs *FirestoreService, us *UserService, sms *SMSGateway) *PhoneVerificationService {
 	return &PhoneVerificationService{
 		firestoreService: fs,
 		userService:      us,
+		smsGateway:       sms,
 	}
 }
 
-// SendVerificationCode sends a verification code to the phone number
-func (pvs *PhoneVerificationService) SendVerificationCode(userID, phoneNumber string) error {
+// SendVerificationCode sends a verification code to the phone number. The code is only
+// stored once the SMS is accepted, so a rate-limited request leaves the previous code valid.
+func (pvs *PhoneVerificationService) SendVerificationCode(ctx context.Context, userID, phoneNumber string) error {
+	// Records, rate limits and the user's profile all use the E.164 form
+	phoneNumber, err := NormalizePhoneNumber(phoneNumber)
+	if err != nil {
+		return err
+	}
+
 	// Generate 6-digit verification code
 	code, err := pvs.generateVerificationCode()
 	if err != nil {
@@ -41,10 +51,20 @@ func (pvs *PhoneVerificationService) SendVerificationCode(userID, phoneNumber st
 		UserID:      userID,
 		PhoneNumber: phoneNumber,
 		Code:        code,
-		ExpiresAt:   time.Now().Add(10 * time.Minute), // 10 minutes expiry
+		ExpiresAt:   clock.Now().Add(10 * time.Minute), // 10 minutes expiry
 		Attempts:    0,
 		IsVerified:  false,
-		CreatedAt:   time.Now(),
+		CreatedAt:   clock.Now(),
+	}
+
+	// Send the code before storing it
+	if err := pvs.smsGateway.Send(ctx, &models.SMSMessage{
+		To:     phoneNumber,
+		Kind:   models.SMSKindVerification,
+		Body:   fmt.Sprintf("Your Unalone verification code is %s. It expires in 10 minutes.", code),
+		Params: map[string]string{"code": code},
+	}); err != nil {
+		return err
 	}
 
 	// Store verification record (in test mode, use mock storage)
@@ -52,12 +72,17 @@ func (pvs *PhoneVerificationService) SendVerificationCode(userID, phoneNumber st
 		return pvs.storeVerificationMock(verification)
 	}
 
-	// In production, store in Firestore and send real SMS
+	// In production, store in Firestore
 	return pvs.storeVerificationFirestore(verification)
 }
 
 // VerifyPhoneCode verifies the submitted code
 func (pvs *PhoneVerificationService) VerifyPhoneCode(userID, phoneNumber, code string) error {
+	phoneNumber, err := NormalizePhoneNumber(phoneNumber)
+	if err != nil {
+		return err
+	}
+
 	// Get verification record
 	verification, err := pvs.getVerificationRecord(userID, phoneNumber)
 	if err != nil {
@@ -65,7 +90,7 @@ func (pvs *PhoneVerificationService) VerifyPhoneCode(userID, phoneNumber, code s
 	}
 
 	// Check if code has expired
-	if time.Now().After(verification.ExpiresAt) {
+	if clock.Now().After(verification.ExpiresAt) {
 		return errors.New("verification code has expired")
 	}
 
@@ -122,24 +147,29 @@ func (pvs *PhoneVerificationService) generateVerificationCode() (string, error)
 	return string(code), nil
 }
 
+// verificationKey identifies the single verification record a user has per canonical number
+func verificationKey(userID, phoneNumber string) string {
+	return fmt.Sprintf("%s_%s", userID, phoneNumber)
+}
+
 // Mock storage functions for testing
-var mockVerifications = make(map[string]*models.PhoneVerification)
+var (
+	mockVerificationsMu sync.Mutex
+	mockVerifications   = make(map[string]*models.PhoneVerification)
+)
 
 func (pvs *PhoneVerificationService) storeVerificationMock(verification *models.PhoneVerification) error {
-	key := fmt.Sprintf("%s_%s", verification.UserID, verification.PhoneNumber)
-	mockVerifications[key] = verification
-	
-	// In test mode, log the verification code for testing
-	log.Printf("📱 SMS Verification Code for %s: %s (expires in 10 minutes)", 
-		verification.PhoneNumber, verification.Code)
-	
+	mockVerificationsMu.Lock()
+	defer mockVerificationsMu.Unlock()
+	mockVerifications[verificationKey(verification.UserID, verification.PhoneNumber)] = verification
 	return nil
 }
 
 func (pvs *PhoneVerificationService) getVerificationRecord(userID, phoneNumber string) (*models.PhoneVerification, error) {
 	if pvs.isTestMode() {
-		key := fmt.Sprintf("%s_%s", userID, phoneNumber)
-		verification, exists := mockVerifications[key]
+		mockVerificationsMu.Lock()
+		defer mockVerificationsMu.Unlock()
+		verification, exists := mockVerifications[verificationKey(userID, phoneNumber)]
 		if !exists {
 			return nil, errors.New("verification record not found")
 		}
@@ -151,16 +181,17 @@ func (pvs *PhoneVerificationService) getVerificationRecord(userID, phoneNumber s
 }
 
 func (pvs *PhoneVerificationService) updateVerificationMock(verification *models.PhoneVerification) error {
-	key := fmt.Sprintf("%s_%s", verification.UserID, verification.PhoneNumber)
-	mockVerifications[key] = verification
+	mockVerificationsMu.Lock()
+	defer mockVerificationsMu.Unlock()
+	mockVerifications[verificationKey(verification.UserID, verification.PhoneNumber)] = verification
 	return nil
 }
 
 func (pvs *PhoneVerificationService) storeVerificationFirestore(verification *models.PhoneVerification) error {
-	// TODO: Implement Firestore storage
+	// TODO: Implement Firestore storage, keyed by user and canonical number so a resend replaces the old code
 	// ctx := pvs.firestoreService.GetContext()
 	// verificationRef := pvs.firestoreService.Collection("phone_verifications")
-	// _, err := verificationRef.Doc(verification.ID).Set(ctx, verification)
+	// _, err := verificationRef.Doc(verificationKey(verification.UserID, verification.PhoneNumber)).Set(ctx, verification)
 	// return err
 	return errors.New("firestore implementation needed")
 }
@@ -174,20 +205,14 @@ func (pvs *PhoneVerificationService) isTestMode() bool {
 	return pvs.firestoreService.client == nil
 }
 
-// SendSMS sends actual SMS (for production)
-func (pvs *PhoneVerificationService) sendSMS(phoneNumber, message string) error {
-	// TODO: Implement with Twilio or Firebase Auth
-	// For now, just log the message
-	log.Printf("📱 SMS to %s: %s", phoneNumber, message)
-	return nil
-}
-
 // CleanupExpiredVerifications removes expired verification records
 func (pvs *PhoneVerificationService) CleanupExpiredVerifications() {
 	if pvs.isTestMode() {
 		// Clean up mock storage
+		mockVerificationsMu.Lock()
+		defer mockVerificationsMu.Unlock()
 		for key, verification := range mockVerifications {
-			if time.Now().After(verification.ExpiresAt) {
+			if clock.Now().After(verification.ExpiresAt) {
 				delete(mockVerifications, key)
 			}
 		}
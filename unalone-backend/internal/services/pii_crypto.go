@@ -0,0 +1,373 @@
+// Application-layer encryption of PII fields at rest, with envelope keys from Cloud KMS or a local key
+package services
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"unalone-backend/internal/models"
+
+	cloudkms "google.golang.org/api/cloudkms/v1"
+)
+
+// Designated PII fields, named as stored
+const (
+	PIIFieldRealName    = "real_name"
+	PIIFieldPhoneNumber = "phone_number"
+	PIIFieldDateOfBirth = "date_of_birth"
+)
+
+// sealedPrefix marks a sealed value: enc:v1:<wrapped data key>:<nonce and ciphertext>, both
+// base64url. Values without it are legacy plaintext and are read as they are.
+const sealedPrefix = "enc:v1:"
+
+var ErrPIIKeyUnavailable = errors.New("field is encrypted but no PII encryption key is configured")
+
+// keyWrapper encrypts data keys with the key encryption key
+type keyWrapper interface {
+	Wrap(ctx context.Context, dataKey []byte) ([]byte, error)
+	Unwrap(ctx context.Context, wrapped []byte) ([]byte, error)
+}
+
+// FieldCipher seals PII with envelope encryption. Each process generates an AES-256 data
+// key, wraps it once with the key encryption key and stores the wrapped key with every value
+// it seals, so any instance can open any value with a single unwrap per data key. Values are
+// AES-GCM sealed with the user ID and field name as associated data, so a value copied onto
+// another user or field does not open.
+type FieldCipher struct {
+	kek keyWrapper
+
+	mu      sync.Mutex
+	aead    cipher.AEAD // Current data key
+	wrapped string      // Current data key, wrapped and encoded
+	opened  map[string]cipher.AEAD
+}
+
+var (
+	fieldCipherOnce sync.Once
+	fieldCipher     *FieldCipher
+)
+
+// defaultFieldCipher returns the process-wide cipher configured by PII_KMS_KEY (a Cloud KMS
+// crypto key resource name) or PII_ENCRYPTION_KEY (a base64 AES-256 key), in that order.
+// It is nil when neither is set, and PII is then stored as plaintext.
+func defaultFieldCipher() *FieldCipher {
+	fieldCipherOnce.Do(func() {
+		kek, err := piiKeyWrapper(context.Background())
+		if err != nil {
+			log.Printf("PII encryption: %v; storing PII as plaintext", err)
+			return
+		}
+		if kek == nil {
+			log.Printf("PII encryption: disabled (no PII_KMS_KEY or PII_ENCRYPTION_KEY set)")
+			return
+		}
+		fieldCipher = &FieldCipher{kek: kek, opened: make(map[string]cipher.AEAD)}
+	})
+	return fieldCipher
+}
+
+// piiKeyWrapper builds the key encryption key from the environment, or nil if none is set
+func piiKeyWrapper(ctx context.Context) (keyWrapper, error) {
+	if name := strings.TrimSpace(os.Getenv("PII_KMS_KEY")); name != "" {
+		svc, err := cloudkms.NewService(ctx, credentialOptions()...)
+		if err != nil {
+			return nil, fmt.Errorf("cloud KMS client: %w", err)
+		}
+		log.Printf("PII encryption: data keys wrapped by Cloud KMS key %s", name)
+		return &kmsKeyWrapper{keys: svc.Projects.Locations.KeyRings.CryptoKeys, name: name}, nil
+	}
+	if raw := strings.TrimSpace(os.Getenv("PII_ENCRYPTION_KEY")); raw != "" {
+		key, err := base64.StdEncoding.DecodeString(raw)
+		if err != nil || len(key) != 32 {
+			return nil, errors.New("PII_ENCRYPTION_KEY must be 32 bytes, base64 encoded")
+		}
+		aead, err := newGCM(key)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("PII encryption: data keys wrapped by the local PII_ENCRYPTION_KEY")
+		return &localKeyWrapper{aead: aead}, nil
+	}
+	return nil, nil
+}
+
+// Seal encrypts one field of a user. Empty values stay empty. Without a cipher the value is
+// returned as is.
+func (fc *FieldCipher) Seal(userID, field, value string) (string, error) {
+	if fc == nil || value == "" || IsSealed(value) {
+		return value, nil
+	}
+	aead, wrapped, err := fc.dataKey()
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(value), piiAssociatedData(userID, field))
+	return sealedPrefix + wrapped + ":" + base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// Open decrypts a field sealed by Seal; plaintext values are returned as they are
+func (fc *FieldCipher) Open(userID, field, value string) (string, error) {
+	if !IsSealed(value) {
+		return value, nil
+	}
+	if fc == nil {
+		return "", ErrPIIKeyUnavailable
+	}
+	wrapped, payload, ok := strings.Cut(strings.TrimPrefix(value, sealedPrefix), ":")
+	if !ok {
+		return "", errors.New("malformed sealed field")
+	}
+	aead, err := fc.openDataKey(wrapped)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", errors.New("malformed sealed field")
+	}
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], piiAssociatedData(userID, field))
+	if err != nil {
+		return "", fmt.Errorf("open %s: %w", field, err)
+	}
+	return string(plaintext), nil
+}
+
+// IsSealed reports whether a stored value was sealed by a FieldCipher
+func IsSealed(value string) bool {
+	return strings.HasPrefix(value, sealedPrefix)
+}
+
+// sealUserPII returns a copy of a user with its PII sealed, for writing to Firestore. The
+// date of birth moves to DateOfBirthSealed since date_of_birth is a timestamp field.
+func sealUserPII(u *models.User) (*models.User, error) {
+	fc := defaultFieldCipher()
+	if fc == nil {
+		return u, nil
+	}
+	sealed := *u
+	var err error
+	if sealed.RealName, err = fc.Seal(u.ID, PIIFieldRealName, u.RealName); err != nil {
+		return nil, err
+	}
+	if sealed.PhoneNumber, err = fc.Seal(u.ID, PIIFieldPhoneNumber, u.PhoneNumber); err != nil {
+		return nil, err
+	}
+	if !u.DateOfBirth.IsZero() {
+		if sealed.DateOfBirthSealed, err = fc.Seal(u.ID, PIIFieldDateOfBirth, u.DateOfBirth.Format(time.RFC3339Nano)); err != nil {
+			return nil, err
+		}
+		sealed.DateOfBirth = time.Time{}
+	}
+	return &sealed, nil
+}
+
+// openUserPII decrypts the PII of a user read from Firestore in place
+func openUserPII(u *models.User) error {
+	fc := defaultFieldCipher()
+	var err error
+	if u.RealName, err = fc.Open(u.ID, PIIFieldRealName, u.RealName); err != nil {
+		return err
+	}
+	if u.PhoneNumber, err = fc.Open(u.ID, PIIFieldPhoneNumber, u.PhoneNumber); err != nil {
+		return err
+	}
+	if u.DateOfBirthSealed != "" {
+		dob, err := fc.Open(u.ID, PIIFieldDateOfBirth, u.DateOfBirthSealed)
+		if err != nil {
+			return err
+		}
+		if u.DateOfBirth, err = time.Parse(time.RFC3339Nano, dob); err != nil {
+			return err
+		}
+		u.DateOfBirthSealed = ""
+	}
+	return nil
+}
+
+// sealPIIUpdates returns a copy of a Firestore field update with any PII sealed
+func sealPIIUpdates(userID string, updates map[string]interface{}) (map[string]interface{}, error) {
+	fc := defaultFieldCipher()
+	if fc == nil {
+		return updates, nil
+	}
+	sealed := make(map[string]interface{}, len(updates)+1)
+	for key, value := range updates {
+		sealed[key] = value
+	}
+	for _, field := range []string{PIIFieldRealName, PIIFieldPhoneNumber} {
+		if v, ok := updates[field].(string); ok {
+			s, err := fc.Seal(userID, field, v)
+			if err != nil {
+				return nil, err
+			}
+			sealed[field] = s
+		}
+	}
+	if dob, ok := updates[PIIFieldDateOfBirth].(time.Time); ok && !dob.IsZero() {
+		s, err := fc.Seal(userID, PIIFieldDateOfBirth, dob.Format(time.RFC3339Nano))
+		if err != nil {
+			return nil, err
+		}
+		sealed[PIIFieldDateOfBirth] = time.Time{}
+		sealed["date_of_birth_sealed"] = s
+	}
+	return sealed, nil
+}
+
+// sealMockUserRecord seals the PII of a user record in the mock_users.json format
+func sealMockUserRecord(id string, m mockUserRecord) error {
+	fc := defaultFieldCipher()
+	if fc == nil {
+		return nil
+	}
+	for _, field := range []string{PIIFieldRealName, PIIFieldPhoneNumber, PIIFieldDateOfBirth} {
+		v, ok := m[field].(string)
+		if !ok || (field == PIIFieldDateOfBirth && strings.HasPrefix(v, "0001-01-01")) {
+			continue
+		}
+		s, err := fc.Seal(id, field, v)
+		if err != nil {
+			return err
+		}
+		m[field] = s
+	}
+	return nil
+}
+
+// openMockUserRecord decrypts the PII of a user record in the mock_users.json format in place.
+// Plaintext records, such as an older mock_users.json, are read as they are.
+func openMockUserRecord(m mockUserRecord) error {
+	id, _ := m["id"].(string)
+	for _, field := range []string{PIIFieldRealName, PIIFieldPhoneNumber, PIIFieldDateOfBirth} {
+		v, ok := m[field].(string)
+		if !ok {
+			continue
+		}
+		s, err := defaultFieldCipher().Open(id, field, v)
+		if err != nil {
+			return err
+		}
+		m[field] = s
+	}
+	return nil
+}
+
+// dataKey returns this process's data key, generating and wrapping it on first use
+func (fc *FieldCipher) dataKey() (cipher.AEAD, string, error) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	if fc.aead != nil {
+		return fc.aead, fc.wrapped, nil
+	}
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, "", err
+	}
+	wrapped, err := fc.kek.Wrap(context.Background(), key)
+	if err != nil {
+		return nil, "", fmt.Errorf("wrap data key: %w", err)
+	}
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, "", err
+	}
+	fc.aead = aead
+	fc.wrapped = base64.RawURLEncoding.EncodeToString(wrapped)
+	fc.opened[fc.wrapped] = aead
+	return fc.aead, fc.wrapped, nil
+}
+
+// openDataKey unwraps a stored data key, caching it for later values
+func (fc *FieldCipher) openDataKey(wrapped string) (cipher.AEAD, error) {
+	fc.mu.Lock()
+	aead, ok := fc.opened[wrapped]
+	fc.mu.Unlock()
+	if ok {
+		return aead, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(wrapped)
+	if err != nil {
+		return nil, errors.New("malformed sealed field")
+	}
+	key, err := fc.kek.Unwrap(context.Background(), raw)
+	if err != nil {
+		return nil, fmt.Errorf("unwrap data key: %w", err)
+	}
+	if aead, err = newGCM(key); err != nil {
+		return nil, err
+	}
+	fc.mu.Lock()
+	fc.opened[wrapped] = aead
+	fc.mu.Unlock()
+	return aead, nil
+}
+
+func piiAssociatedData(userID, field string) []byte {
+	return []byte(userID + "/" + field)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// localKeyWrapper wraps data keys with AES-GCM under a key from the environment
+type localKeyWrapper struct {
+	aead cipher.AEAD
+}
+
+func (w *localKeyWrapper) Wrap(ctx context.Context, dataKey []byte) ([]byte, error) {
+	nonce := make([]byte, w.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return w.aead.Seal(nonce, nonce, dataKey, nil), nil
+}
+
+func (w *localKeyWrapper) Unwrap(ctx context.Context, wrapped []byte) ([]byte, error) {
+	if len(wrapped) < w.aead.NonceSize() {
+		return nil, errors.New("wrapped key too short")
+	}
+	return w.aead.Open(nil, wrapped[:w.aead.NonceSize()], wrapped[w.aead.NonceSize():], nil)
+}
+
+// kmsKeyWrapper wraps data keys with a Cloud KMS symmetric key. KMS keeps older key
+// versions for decryption, so rotating the key there needs no re-encryption here.
+type kmsKeyWrapper struct {
+	keys *cloudkms.ProjectsLocationsKeyRingsCryptoKeysService
+	name string
+}
+
+func (w *kmsKeyWrapper) Wrap(ctx context.Context, dataKey []byte) ([]byte, error) {
+	resp, err := w.keys.Encrypt(w.name, &cloudkms.EncryptRequest{Plaintext: base64.StdEncoding.EncodeToString(dataKey)}).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(resp.Ciphertext)
+}
+
+func (w *kmsKeyWrapper) Unwrap(ctx context.Context, wrapped []byte) ([]byte, error) {
+	resp, err := w.keys.Decrypt(w.name, &cloudkms.DecryptRequest{Ciphertext: base64.StdEncoding.EncodeToString(wrapped)}).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(resp.Plaintext)
+}
@@ -0,0 +1,243 @@
+// Places service proxying venue autocomplete to the places provider
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"unalone-backend/internal/models"
+)
+
+// ErrPlacesRateLimited is returned when a user exceeds the autocomplete rate limit
+var ErrPlacesRateLimited = errors.New("too many autocomplete requests, please slow down")
+
+const (
+	placesAutocompleteURL = "https://places.googleapis.com/v1/places:autocomplete"
+	placesCacheTTL        = 10 * time.Minute
+	placesRateLimit       = 30 // requests per user per window
+	placesRateWindow      = time.Minute
+	placesBiasRadius      = 50000.0 // meters
+)
+
+// PlacesService handles venue autocomplete with server-side key handling
+type PlacesService struct {
+	redisService *RedisService
+	quota        *QuotaService
+	apiKey       string
+	httpClient   *http.Client
+	limiter      *RateLimiter
+
+	mu    sync.Mutex
+	cache map[string]placesCacheEntry // fallback when Redis is unavailable
+}
+
+type placesCacheEntry struct {
+	suggestions []models.PlaceSuggestion
+	expiresAt   time.Time
+}
+
+// NewPlacesService creates a new places service. If PLACES_API_KEY is unset, suggestions are empty.
+func NewPlacesService(rs *RedisService, quota *QuotaService) *PlacesService {
+	return &PlacesService{
+		redisService: rs,
+		quota:        quota,
+		apiKey:       strings.TrimSpace(os.Getenv("PLACES_API_KEY")),
+		httpClient:   &http.Client{Timeout: 5 * time.Second},
+		limiter:      NewRateLimiter(rs, "places", placesRateLimit, placesRateWindow),
+		cache:        make(map[string]placesCacheEntry),
+	}
+}
+
+// IsConfigured reports whether a provider API key is available
+func (ps *PlacesService) IsConfigured() bool {
+	return ps.apiKey != ""
+}
+
+// Autocomplete returns venue suggestions for the given input
+func (ps *PlacesService) Autocomplete(ctx context.Context, userID string, req *models.PlacesAutocompleteRequest) (*models.PlacesAutocompleteResponse, error) {
+	if !ps.allow(userID) {
+		return nil, ErrPlacesRateLimited
+	}
+
+	// Session tokens group keystrokes into a single billable session
+	sessionToken := strings.TrimSpace(req.SessionToken)
+	if sessionToken == "" {
+		sessionToken = genID(16)
+	}
+
+	input := strings.ToLower(strings.TrimSpace(req.Input))
+	cacheKey := ps.cacheKey(input, req.Latitude, req.Longitude)
+
+	if suggestions, ok := ps.getCached(cacheKey); ok {
+		return &models.PlacesAutocompleteResponse{
+			Suggestions:  suggestions,
+			SessionToken: sessionToken,
+			CacheHit:     true,
+		}, nil
+	}
+
+	// Once the daily budget is spent, answer like an unconfigured provider without caching it
+	if ps.IsConfigured() && !ps.quota.Allow(QuotaGeocoding) {
+		return &models.PlacesAutocompleteResponse{
+			Suggestions:  []models.PlaceSuggestion{},
+			SessionToken: sessionToken,
+		}, nil
+	}
+
+	suggestions, err := ps.fetchSuggestions(ctx, input, sessionToken, req.Latitude, req.Longitude)
+	if err != nil {
+		return nil, err
+	}
+	ps.setCached(cacheKey, suggestions)
+
+	return &models.PlacesAutocompleteResponse{
+		Suggestions:  suggestions,
+		SessionToken: sessionToken,
+		CacheHit:     false,
+	}, nil
+}
+
+// fetchSuggestions calls the provider, or returns no suggestions when unconfigured
+func (ps *PlacesService) fetchSuggestions(ctx context.Context, input, sessionToken string, lat, lon *float64) ([]models.PlaceSuggestion, error) {
+	if !ps.IsConfigured() {
+		return []models.PlaceSuggestion{}, nil
+	}
+
+	body := map[string]interface{}{
+		"input":        input,
+		"sessionToken": sessionToken,
+	}
+	if lat != nil && lon != nil {
+		body["locationBias"] = map[string]interface{}{
+			"circle": map[string]interface{}{
+				"center": map[string]float64{"latitude": *lat, "longitude": *lon},
+				"radius": placesBiasRadius,
+			},
+		}
+	}
+	reqBody, _ := json.Marshal(body)
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, placesAutocompleteURL, bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-Goog-Api-Key", ps.apiKey)
+
+	resp, err := ps.httpClient.Do(httpReq)
+	ps.quota.Record(QuotaGeocoding, 1, geocodingMicrosPerCall)
+	if err != nil {
+		log.Printf("places http error: %v", err)
+		return nil, errors.New("places provider unavailable")
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		log.Printf("places non-200: %d body=%s", resp.StatusCode, string(respBody))
+		return nil, errors.New("places provider unavailable")
+	}
+
+	type textValue struct {
+		Text string `json:"text"`
+	}
+	var pr struct {
+		Suggestions []struct {
+			PlacePrediction *struct {
+				PlaceID          string    `json:"placeId"`
+				Text             textValue `json:"text"`
+				StructuredFormat struct {
+					MainText      textValue `json:"mainText"`
+					SecondaryText textValue `json:"secondaryText"`
+				} `json:"structuredFormat"`
+			} `json:"placePrediction"`
+		} `json:"suggestions"`
+	}
+	if err := json.Unmarshal(respBody, &pr); err != nil {
+		log.Printf("places parse error: %v", err)
+		return nil, errors.New("invalid response from places provider")
+	}
+
+	suggestions := make([]models.PlaceSuggestion, 0, len(pr.Suggestions))
+	for _, s := range pr.Suggestions {
+		if s.PlacePrediction == nil {
+			continue
+		}
+		suggestions = append(suggestions, models.PlaceSuggestion{
+			PlaceID:       s.PlacePrediction.PlaceID,
+			Description:   s.PlacePrediction.Text.Text,
+			MainText:      s.PlacePrediction.StructuredFormat.MainText.Text,
+			SecondaryText: s.PlacePrediction.StructuredFormat.SecondaryText.Text,
+		})
+	}
+	return suggestions, nil
+}
+
+// allow applies a fixed-window rate limit per user, shared across replicas through Redis
+func (ps *PlacesService) allow(userID string) bool {
+	allowed, _ := ps.limiter.Allow(userID)
+	return allowed
+}
+
+// cacheKey builds a cache key from the normalized input and a coarse location
+func (ps *PlacesService) cacheKey(input string, lat, lon *float64) string {
+	if lat != nil && lon != nil {
+		return fmt.Sprintf("places:autocomplete:%s:%.2f,%.2f", input, *lat, *lon)
+	}
+	return fmt.Sprintf("places:autocomplete:%s", input)
+}
+
+func (ps *PlacesService) getCached(key string) ([]models.PlaceSuggestion, bool) {
+	if ps.redisService.IsAvailable() {
+		var suggestions []models.PlaceSuggestion
+		hit, err := ps.redisService.GetCachedJSON(key, &suggestions)
+		if err == nil && hit {
+			return suggestions, true
+		}
+		return nil, false
+	}
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	entry, ok := ps.cache[key]
+	if !ok || clock.Now().After(entry.expiresAt) {
+		delete(ps.cache, key)
+		return nil, false
+	}
+	return entry.suggestions, true
+}
+
+func (ps *PlacesService) setCached(key string, suggestions []models.PlaceSuggestion) {
+	if ps.redisService.IsAvailable() {
+		if err := ps.redisService.CacheJSON(key, suggestions, placesCacheTTL); err != nil {
+			log.Printf("places cache write failed: %v", err)
+		}
+		return
+	}
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	ps.cache[key] = placesCacheEntry{suggestions: suggestions, expiresAt: clock.Now().Add(placesCacheTTL)}
+}
+
+// PurgeExpiredCache drops expired entries from the in-process fallback cache and rate counters
+func (ps *PlacesService) PurgeExpiredCache() {
+	ps.limiter.PurgeExpired()
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	now := clock.Now()
+	for key, entry := range ps.cache {
+		if now.After(entry.expiresAt) {
+			delete(ps.cache, key)
+		}
+	}
+}
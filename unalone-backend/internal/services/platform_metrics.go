@@ -0,0 +1,452 @@
+// Platform metrics: daily active users and activity counters rolled up for the admin dashboard
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"unalone-backend/internal/models"
+)
+
+// Platform activity counters
+const (
+	MetricHotspotsCreated = "hotspots_created"
+	MetricHotspotJoins    = "hotspot_joins"
+	MetricChatMessages    = "chat_messages"
+	MetricAISessions      = "ai_sessions"
+	MetricAIMessages      = "ai_messages"
+)
+
+// Wellbeing counters, fed only by users who opted in to AI insights
+const (
+	metricWellbeingMessages = "wellbeing_messages"
+	metricWellbeingPositive = "wellbeing_positive"
+	metricWellbeingNeutral  = "wellbeing_neutral"
+	metricWellbeingNegative = "wellbeing_negative"
+	metricWellbeingMood     = "wellbeing_mood_" // + mood tag
+)
+
+// minWellbeingCohort is how many users must contribute to a day before its wellbeing stats
+// are shown, so no one's mood can be singled out
+const minWellbeingCohort = 5
+
+// wellbeingSentimentBand is how far from zero a message's sentiment must be to count as positive or negative
+const wellbeingSentimentBand = 0.2
+
+const (
+	// metricsRetention keeps raw daily counters long enough to compute weekly actives and backfill missed rollups
+	metricsRetention = 9 * 24 * time.Hour
+	// metricsBackfillDays is how many finished days each rollup checks for missing stats
+	metricsBackfillDays = 7
+	// MaxMetricsDays is the longest range the dashboard returns
+	MaxMetricsDays = 90
+)
+
+// PlatformMetricsService counts active users and platform activity per UTC day. Raw counts live
+// in Redis so replicas share them, falling back to this instance's memory; a nightly rollup
+// turns finished days into stats documents.
+type PlatformMetricsService struct {
+	firestoreService *FirestoreService
+	redisService     *RedisService
+	userService      *UserService
+	consents         *ConsentService
+	startedAt        time.Time
+
+	mu        sync.Mutex
+	active    map[string]map[string]bool // day -> user IDs seen; also skips repeat Redis writes
+	wellbeing map[string]map[string]bool // day -> user IDs who contributed mood scores, when Redis is unavailable
+	counters  map[string]map[string]int  // day -> metric -> count, when Redis is unavailable
+}
+
+// NewPlatformMetricsService creates a new platform metrics service
+func NewPlatformMetricsService(fs *FirestoreService, rs *RedisService, us *UserService, cs *ConsentService) *PlatformMetricsService {
+	return &PlatformMetricsService{
+		firestoreService: fs,
+		redisService:     rs,
+		userService:      us,
+		consents:         cs,
+		startedAt:        time.Now(),
+		active:           make(map[string]map[string]bool),
+		wellbeing:        make(map[string]map[string]bool),
+		counters:         make(map[string]map[string]int),
+	}
+}
+
+// RecordActive marks a user as active today; it is called on every authenticated request.
+// Users without analytics consent are not counted.
+func (pm *PlatformMetricsService) RecordActive(userID string) {
+	if userID == "" {
+		return
+	}
+	day := metricsDay(time.Now())
+
+	pm.mu.Lock()
+	seen := pm.active[day][userID]
+	pm.mu.Unlock()
+	// Consent is only looked up on a user's first request of the day
+	if seen || !pm.consents.Allowed(userID, models.ConsentAnalytics) {
+		return
+	}
+
+	pm.mu.Lock()
+	users, ok := pm.active[day]
+	if !ok {
+		users = make(map[string]bool)
+		pm.active[day] = users
+	}
+	seen = users[userID]
+	users[userID] = true
+	pm.mu.Unlock()
+
+	if !seen {
+		if err := pm.redisService.CountUnique(activeUsersKey(day), userID, metricsRetention); err != nil {
+			log.Printf("[metrics] active user: %v", err)
+		}
+	}
+}
+
+// Increment adds one to a platform activity counter for today
+func (pm *PlatformMetricsService) Increment(metric string) {
+	day := metricsDay(time.Now())
+	if pm.redisService.IsAvailable() {
+		if _, err := pm.redisService.IncrementWindow(counterKey(metric, day), metricsRetention); err != nil {
+			log.Printf("[metrics] %s: %v", metric, err)
+		}
+		return
+	}
+
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	counts, ok := pm.counters[day]
+	if !ok {
+		counts = make(map[string]int)
+		pm.counters[day] = counts
+	}
+	counts[metric]++
+}
+
+// RecordWellbeing counts one consenting user's scored AI message into today's anonymous
+// wellbeing stats: a sentiment band and mood tags, never the text. Users without analytics
+// consent are not counted.
+func (pm *PlatformMetricsService) RecordWellbeing(userID string, score models.AIMoodScore) {
+	if !pm.consents.Allowed(userID, models.ConsentAnalytics) {
+		return
+	}
+	day := metricsDay(time.Now())
+	if pm.redisService.IsAvailable() {
+		if err := pm.redisService.CountUnique(wellbeingUsersKey(day), userID, metricsRetention); err != nil {
+			log.Printf("[metrics] wellbeing user: %v", err)
+		}
+	} else {
+		pm.mu.Lock()
+		users, ok := pm.wellbeing[day]
+		if !ok {
+			users = make(map[string]bool)
+			pm.wellbeing[day] = users
+		}
+		users[userID] = true
+		pm.mu.Unlock()
+	}
+
+	pm.Increment(metricWellbeingMessages)
+	switch {
+	case score.Sentiment > wellbeingSentimentBand:
+		pm.Increment(metricWellbeingPositive)
+	case score.Sentiment < -wellbeingSentimentBand:
+		pm.Increment(metricWellbeingNegative)
+	default:
+		pm.Increment(metricWellbeingNeutral)
+	}
+	for _, mood := range score.Moods {
+		pm.Increment(metricWellbeingMood + mood)
+	}
+}
+
+// HandleDomainEvent counts hotspot creations and joins delivered from the outbox
+func (pm *PlatformMetricsService) HandleDomainEvent(ctx context.Context, event *models.DomainEvent) error {
+	switch event.Type {
+	case models.DomainEventHotspotCreated:
+		pm.Increment(MetricHotspotsCreated)
+	case models.DomainEventUserJoined:
+		pm.Increment(MetricHotspotJoins)
+	}
+	return nil
+}
+
+// RollupPending stores stats for each finished day of the last week that has none yet, so a
+// missed night is filled in by the next run, then drops in-memory counts past retention
+func (pm *PlatformMetricsService) RollupPending(ctx context.Context) error {
+	now := time.Now().UTC()
+	registrations, err := pm.registrationsByDay()
+	if err != nil {
+		return err
+	}
+	for i := metricsBackfillDays; i >= 1; i-- {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		day := metricsDay(now.AddDate(0, 0, -i))
+		// Without Redis, days that ended before this instance started were never counted
+		if !pm.redisService.IsAvailable() && day < metricsDay(pm.startedAt) {
+			continue
+		}
+		_, done, err := pm.getStats(day)
+		if err != nil {
+			return err
+		}
+		if done {
+			continue
+		}
+		stats, err := pm.dayStats(day, registrations)
+		if err != nil {
+			return err
+		}
+		if err := pm.saveStats(stats); err != nil {
+			return err
+		}
+	}
+	pm.purge(now)
+	return nil
+}
+
+// GetDashboard returns the rolled-up stats for the given number of finished days and today's live counts
+func (pm *PlatformMetricsService) GetDashboard(days int) (*models.MetricsDashboard, error) {
+	if days < 1 || days > MaxMetricsDays {
+		return nil, fmt.Errorf("days must be between 1 and %d", MaxMetricsDays)
+	}
+	now := time.Now().UTC()
+	stored, err := pm.listStats(metricsDay(now.AddDate(0, 0, -days)), metricsDay(now.AddDate(0, 0, -1)))
+	if err != nil {
+		return nil, err
+	}
+	registrations, err := pm.registrationsByDay()
+	if err != nil {
+		return nil, err
+	}
+	today, err := pm.dayStats(metricsDay(now), registrations)
+	if err != nil {
+		return nil, err
+	}
+	return &models.MetricsDashboard{Days: stored, Today: *today}, nil
+}
+
+// dayStats builds the stats for one day from the raw counters
+func (pm *PlatformMetricsService) dayStats(day string, registrations map[string]int) (*models.DailyStats, error) {
+	start, err := time.Parse(analyticsDayFormat, day)
+	if err != nil {
+		return nil, err
+	}
+	week := make([]string, 0, 7)
+	for i := 6; i >= 0; i-- {
+		week = append(week, metricsDay(start.AddDate(0, 0, -i)))
+	}
+
+	stats := &models.DailyStats{Day: day, NewRegistrations: registrations[day], GeneratedAt: time.Now()}
+	if stats.ActiveUsers, err = pm.uniqueActive(day); err != nil {
+		return nil, err
+	}
+	if stats.WeeklyActiveUsers, err = pm.uniqueActive(week...); err != nil {
+		return nil, err
+	}
+	for metric, field := range map[string]*int{
+		MetricHotspotsCreated: &stats.HotspotsCreated,
+		MetricHotspotJoins:    &stats.HotspotJoins,
+		MetricChatMessages:    &stats.ChatMessages,
+		MetricAISessions:      &stats.AISessions,
+		MetricAIMessages:      &stats.AIMessages,
+	} {
+		if *field, err = pm.count(metric, day); err != nil {
+			return nil, err
+		}
+	}
+	if stats.Wellbeing, err = pm.wellbeingStats(day); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// uniqueActive counts distinct users active on any of days
+func (pm *PlatformMetricsService) uniqueActive(days ...string) (int, error) {
+	if pm.redisService.IsAvailable() {
+		keys := make([]string, len(days))
+		for i, day := range days {
+			keys[i] = activeUsersKey(day)
+		}
+		n, err := pm.redisService.UniqueCount(keys...)
+		return int(n), err
+	}
+
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	union := make(map[string]bool)
+	for _, day := range days {
+		for userID := range pm.active[day] {
+			union[userID] = true
+		}
+	}
+	return len(union), nil
+}
+
+// wellbeingStats returns a day's anonymous wellbeing counts, or nil when too few users contributed
+func (pm *PlatformMetricsService) wellbeingStats(day string) (*models.WellbeingStats, error) {
+	var users int
+	if pm.redisService.IsAvailable() {
+		n, err := pm.redisService.UniqueCount(wellbeingUsersKey(day))
+		if err != nil {
+			return nil, err
+		}
+		users = int(n)
+	} else {
+		pm.mu.Lock()
+		users = len(pm.wellbeing[day])
+		pm.mu.Unlock()
+	}
+	if users < minWellbeingCohort {
+		return nil, nil
+	}
+
+	stats := &models.WellbeingStats{Users: users, Moods: make(map[string]int)}
+	var err error
+	for metric, field := range map[string]*int{
+		metricWellbeingMessages: &stats.Messages,
+		metricWellbeingPositive: &stats.Positive,
+		metricWellbeingNeutral:  &stats.Neutral,
+		metricWellbeingNegative: &stats.Negative,
+	} {
+		if *field, err = pm.count(metric, day); err != nil {
+			return nil, err
+		}
+	}
+	for _, mood := range models.AIMoodTags {
+		n, err := pm.count(metricWellbeingMood+mood, day)
+		if err != nil {
+			return nil, err
+		}
+		if n > 0 {
+			stats.Moods[mood] = n
+		}
+	}
+	return stats, nil
+}
+
+func (pm *PlatformMetricsService) count(metric, day string) (int, error) {
+	if pm.redisService.IsAvailable() {
+		n, err := pm.redisService.GetCount(counterKey(metric, day))
+		return int(n), err
+	}
+
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	return pm.counters[day][metric], nil
+}
+
+// registrationsByDay counts users by the UTC day they signed up
+func (pm *PlatformMetricsService) registrationsByDay() (map[string]int, error) {
+	if !pm.isTestMode() {
+		// TODO: Implement Firestore count of users grouped by created_at day
+		return nil, errors.New("firestore implementation needed")
+	}
+	users, err := pm.userService.loadMockUsers()
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int)
+	for _, user := range users {
+		counts[metricsDay(user.CreatedAt)]++
+	}
+	return counts, nil
+}
+
+// purge drops in-memory counts older than the retention window
+func (pm *PlatformMetricsService) purge(now time.Time) {
+	oldest := metricsDay(now.Add(-metricsRetention))
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	for day := range pm.active {
+		if day < oldest {
+			delete(pm.active, day)
+		}
+	}
+	for day := range pm.counters {
+		if day < oldest {
+			delete(pm.counters, day)
+		}
+	}
+	for day := range pm.wellbeing {
+		if day < oldest {
+			delete(pm.wellbeing, day)
+		}
+	}
+}
+
+func (pm *PlatformMetricsService) getStats(day string) (*models.DailyStats, bool, error) {
+	if !pm.isTestMode() {
+		// TODO: Implement Firestore read of daily_stats/{day}
+		return nil, false, errors.New("firestore implementation needed")
+	}
+	mockDailyStatsMu.Lock()
+	defer mockDailyStatsMu.Unlock()
+	stats, ok := mockDailyStats[day]
+	return stats, ok, nil
+}
+
+func (pm *PlatformMetricsService) saveStats(stats *models.DailyStats) error {
+	if !pm.isTestMode() {
+		// TODO: Implement Firestore write of daily_stats/{day}
+		return errors.New("firestore implementation needed")
+	}
+	mockDailyStatsMu.Lock()
+	defer mockDailyStatsMu.Unlock()
+	mockDailyStats[stats.Day] = stats
+	return nil
+}
+
+// listStats returns stored stats for days from..to inclusive, oldest first
+func (pm *PlatformMetricsService) listStats(from, to string) ([]models.DailyStats, error) {
+	if !pm.isTestMode() {
+		// TODO: Implement Firestore range query on daily_stats ordered by day
+		return nil, errors.New("firestore implementation needed")
+	}
+	mockDailyStatsMu.Lock()
+	defer mockDailyStatsMu.Unlock()
+	list := []models.DailyStats{}
+	for day, stats := range mockDailyStats {
+		if day >= from && day <= to {
+			list = append(list, *stats)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Day < list[j].Day })
+	return list, nil
+}
+
+func (pm *PlatformMetricsService) isTestMode() bool {
+	return pm.firestoreService.client == nil
+}
+
+func metricsDay(t time.Time) string {
+	return t.UTC().Format(analyticsDayFormat)
+}
+
+func activeUsersKey(day string) string {
+	return "metrics:active:" + day
+}
+
+func wellbeingUsersKey(day string) string {
+	return "metrics:wellbeing_users:" + day
+}
+
+func counterKey(metric, day string) string {
+	return "metrics:" + metric + ":" + day
+}
+
+// === Mock storage in-memory for development/test ===
+
+var (
+	mockDailyStatsMu sync.Mutex
+	mockDailyStats   = make(map[string]*models.DailyStats) // day -> stats
+)
@@ -0,0 +1,153 @@
+// Presence service tracking who is online and when users were last seen
+package services
+
+import (
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"unalone-backend/internal/models"
+)
+
+const (
+	// PresenceHeartbeatInterval is how often clients without the events WebSocket send a heartbeat
+	PresenceHeartbeatInterval = 60 * time.Second
+	// PresenceOnlineWindow is how recently a user must have been seen to count as online;
+	// it allows one late heartbeat, and the events WebSocket touches on every ping
+	PresenceOnlineWindow = 90 * time.Second
+	// presenceWriteInterval throttles last-seen writes per user on this instance
+	presenceWriteInterval = 15 * time.Second
+	// presenceRetention is how long last-seen times are kept
+	presenceRetention = 30 * 24 * time.Hour
+	// presenceKey is the Redis hash of user ID -> last seen unix time
+	presenceKey = "presence:last_seen"
+)
+
+// PresenceService records when users were last active. Last-seen times live in Redis so every
+// replica sees the same presence, falling back to this instance's memory.
+type PresenceService struct {
+	redisService   *RedisService
+	userService    *UserService
+	profileService *ProfileService
+
+	mu       sync.Mutex
+	lastSeen map[string]time.Time // userID -> last recorded time; also throttles Redis writes
+}
+
+// NewPresenceService creates a new presence service
+func NewPresenceService(rs *RedisService, us *UserService, ps *ProfileService) *PresenceService {
+	return &PresenceService{redisService: rs, userService: us, profileService: ps, lastSeen: make(map[string]time.Time)}
+}
+
+// Touch marks a user as seen now
+func (ps *PresenceService) Touch(userID string) {
+	if userID == "" {
+		return
+	}
+	now := clock.Now()
+
+	ps.mu.Lock()
+	if now.Sub(ps.lastSeen[userID]) < presenceWriteInterval {
+		ps.mu.Unlock()
+		return
+	}
+	ps.lastSeen[userID] = now
+	ps.mu.Unlock()
+
+	if err := ps.redisService.HashSetMany(presenceKey, map[string]interface{}{userID: now.Unix()}, presenceRetention); err != nil {
+		log.Printf("[presence] touch %s: %v", userID, err)
+	}
+}
+
+// Annotate sets availability, online and last-seen on users; invisible users get none of them,
+// and online/last-seen only appear for users who share their online status
+func (ps *PresenceService) Annotate(users []models.PublicUser) {
+	if len(users) == 0 {
+		return
+	}
+	ids := make([]string, len(users))
+	for i, user := range users {
+		ids[i] = user.ID
+	}
+	seen := ps.lastSeenMany(ids)
+	now := clock.Now()
+	for i := range users {
+		users[i].Online, users[i].LastSeenAt, users[i].Availability = ps.status(users[i].ID, seen[users[i].ID], now)
+	}
+}
+
+// Status returns a user's online flag, last-seen time and availability, each empty when hidden as in Annotate
+func (ps *PresenceService) Status(userID string) (*bool, *time.Time, string) {
+	return ps.status(userID, ps.lastSeenMany([]string{userID})[userID], clock.Now())
+}
+
+func (ps *PresenceService) status(userID string, lastSeen, now time.Time) (*bool, *time.Time, string) {
+	user, err := ps.userService.GetUserByID(userID)
+	if err != nil {
+		return nil, nil, ""
+	}
+	availability := EffectiveAvailability(user, now)
+	if availability == models.AvailabilityInvisible {
+		return nil, nil, ""
+	}
+	if !ps.sharesStatus(userID) {
+		return nil, nil, availability
+	}
+	online, lastSeenAt := presenceFields(lastSeen, now)
+	return online, lastSeenAt, availability
+}
+
+// PurgeExpired drops in-memory last-seen times past retention
+func (ps *PresenceService) PurgeExpired() {
+	cutoff := clock.Now().Add(-presenceRetention)
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	for userID, at := range ps.lastSeen {
+		if at.Before(cutoff) {
+			delete(ps.lastSeen, userID)
+		}
+	}
+}
+
+// lastSeenMany reads last-seen times, from Redis when available
+func (ps *PresenceService) lastSeenMany(userIDs []string) map[string]time.Time {
+	seen := make(map[string]time.Time, len(userIDs))
+	if ps.redisService.IsAvailable() {
+		values, err := ps.redisService.HashGetMany(presenceKey, userIDs)
+		if err == nil {
+			for i, value := range values {
+				if unix, err := strconv.ParseInt(value, 10, 64); err == nil {
+					seen[userIDs[i]] = time.Unix(unix, 0)
+				}
+			}
+			return seen
+		}
+		log.Printf("[presence] read: %v", err)
+	}
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	for _, userID := range userIDs {
+		if at, ok := ps.lastSeen[userID]; ok {
+			seen[userID] = at
+		}
+	}
+	return seen
+}
+
+// sharesStatus reports whether a user lets others see their presence
+func (ps *PresenceService) sharesStatus(userID string) bool {
+	settings, err := ps.profileService.GetUserSettings(userID)
+	return err == nil && settings.ShowOnlineStatus
+}
+
+// presenceFields turns a last-seen time into the response fields; last seen is rounded to the minute
+func presenceFields(lastSeen, now time.Time) (*bool, *time.Time) {
+	online := !lastSeen.IsZero() && now.Sub(lastSeen) < PresenceOnlineWindow
+	if lastSeen.IsZero() {
+		return &online, nil
+	}
+	rounded := lastSeen.UTC().Truncate(time.Minute)
+	return &online, &rounded
+}
@@ -3,6 +3,8 @@ package services
 
 import (
 	"errors"
+	"log"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -13,13 +15,15 @@ import (
 type ProfileService struct {
 	firestoreService *FirestoreService
 	userService      *UserService
+	nicknameService  *NicknameService
 }
 
 // NewProfileService creates a new profile service
-func NewProfileService(fs *FirestoreService, us *UserService) *ProfileService {
+func NewProfileService(fs *FirestoreService, us *UserService, ns *NicknameService) *ProfileService {
 	return &ProfileService{
 		firestoreService: fs,
 		userService:      us,
+		nicknameService:  ns,
 	}
 }
 
@@ -27,9 +31,8 @@ func NewProfileService(fs *FirestoreService, us *UserService) *ProfileService {
 func (ps *ProfileService) UpdateProfile(userID string, req *models.UpdateProfileRequest) (*models.User, error) {
 	// Validate age (must be 18+)
 	if !req.DateOfBirth.IsZero() {
-		age := time.Now().Year() - req.DateOfBirth.Year()
-		if age < 18 {
-			return nil, errors.New("user must be at least 18 years old")
+		if err := validateDateOfBirth(req.DateOfBirth, clock.Now()); err != nil {
+			return nil, err
 		}
 	}
 
@@ -44,7 +47,17 @@ func (ps *ProfileService) UpdateProfile(userID string, req *models.UpdateProfile
 		return nil, err
 	}
 
-	if req.Nickname != currentUser.Nickname {
+	// Date of birth is locked once the phone is verified; corrections go through support
+	if !req.DateOfBirth.IsZero() && DateOfBirthLocked(currentUser) && !sameDate(req.DateOfBirth, currentUser.DateOfBirth) {
+		return nil, ErrDateOfBirthLocked
+	}
+
+	nicknameChanged := req.Nickname != currentUser.Nickname
+	if nicknameChanged {
+		// Changes are rate-limited and recently released nicknames are reserved to stop impersonation
+		if err := ps.nicknameService.CheckChange(userID, req.Nickname, clock.Now()); err != nil {
+			return nil, err
+		}
 		existingUser, err := ps.userService.GetUserByNickname(req.Nickname)
 		if err == nil && existingUser != nil && existingUser.ID != userID {
 			return nil, errors.New("nickname is already taken")
@@ -58,8 +71,8 @@ func (ps *ProfileService) UpdateProfile(userID string, req *models.UpdateProfile
 		"bio":           req.Bio,
 		"gender":        req.Gender,
 		"location":      req.Location,
-		"interests":     req.Interests,
-		"updated_at":    time.Now(),
+		"interests":     CanonicalizeInterests(req.Interests),
+		"updated_at":    clock.Now(),
 	}
 
 	if !req.DateOfBirth.IsZero() {
@@ -67,14 +80,32 @@ func (ps *ProfileService) UpdateProfile(userID string, req *models.UpdateProfile
 	}
 
 	// Update user
-	return ps.userService.UpdateUser(userID, updates)
+	user, err := ps.userService.UpdateUser(userID, updates)
+	if err != nil {
+		return nil, err
+	}
+	if nicknameChanged {
+		if err := ps.nicknameService.RecordChange(userID, currentUser.Nickname, req.Nickname, clock.Now()); err != nil {
+			log.Printf("[nickname] record change for %s: %v", userID, err)
+		}
+	}
+	return user, nil
+}
+
+// GetNicknameStatus returns the user's nickname history and when they may change it next
+func (ps *ProfileService) GetNicknameStatus(userID string) (*models.NicknameStatus, error) {
+	user, err := ps.userService.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return ps.nicknameService.GetStatus(user, clock.Now())
 }
 
 // UpdateProfileImage updates user's profile image URL
 func (ps *ProfileService) UpdateProfileImage(userID, imageURL string) (*models.User, error) {
 	updates := map[string]interface{}{
 		"profile_image_url": imageURL,
-		"updated_at":        time.Now(),
+		"updated_at":        clock.Now(),
 	}
 
 	return ps.userService.UpdateUser(userID, updates)
@@ -97,6 +128,11 @@ func (ps *ProfileService) UpdateUserSettings(userID string, req *models.UpdateSe
 		return nil, errors.New("minimum age cannot be greater than maximum age")
 	}
 
+	// Validate notification preferences
+	if err := validateNotificationPreferences(req.CategoryPreferences, req.QuietHours); err != nil {
+		return nil, err
+	}
+
 	settings := &models.UserSettings{
 		ID:                   uuid.New().String(),
 		UserID:               userID,
@@ -109,7 +145,9 @@ func (ps *ProfileService) UpdateUserSettings(userID string, req *models.UpdateSe
 		DistanceRadius:       req.DistanceRadius,
 		AgeRangeMin:          req.AgeRangeMin,
 		AgeRangeMax:          req.AgeRangeMax,
-		UpdatedAt:            time.Now(),
+		CategoryPreferences:  req.CategoryPreferences,
+		QuietHours:           req.QuietHours,
+		UpdatedAt:            clock.Now(),
 	}
 
 	if ps.isTestMode() {
@@ -144,7 +182,7 @@ func (ps *ProfileService) BlockUser(blockerID, blockedID string) error {
 	// Update blocked user's record
 	_, err = ps.userService.UpdateUser(blockedID, map[string]interface{}{
 		"blocked_by":   blockedBy,
-		"updated_at":   time.Now(),
+		"updated_at":   clock.Now(),
 	})
 
 	return err
@@ -170,7 +208,7 @@ func (ps *ProfileService) UnblockUser(blockerID, blockedID string) error {
 	// Update blocked user's record
 	_, err = ps.userService.UpdateUser(blockedID, map[string]interface{}{
 		"blocked_by":   blockedBy,
-		"updated_at":   time.Now(),
+		"updated_at":   clock.Now(),
 	})
 
 	return err
@@ -196,7 +234,7 @@ func (ps *ProfileService) ReportUser(reporterID string, req *models.UserReportRe
 		Reason:      req.Reason,
 		Description: req.Description,
 		Status:      "pending",
-		CreatedAt:   time.Now(),
+		CreatedAt:   clock.Now(),
 	}
 
 	if ps.isTestMode() {
@@ -207,9 +245,20 @@ func (ps *ProfileService) ReportUser(reporterID string, req *models.UserReportRe
 	return errors.New("firestore implementation needed")
 }
 
+// CountReportsAgainst counts reports filed against a user since the given time
+func (ps *ProfileService) CountReportsAgainst(userID string, since time.Time) (int, error) {
+	if ps.isTestMode() {
+		return ps.countReportsAgainstMock(userID, since), nil
+	}
+
+	// TODO: Query Firestore user_reports where reported_id == userID and created_at >= since
+	return 0, errors.New("firestore implementation needed")
+}
+
 // Mock storage for testing
 var mockSettings = make(map[string]*models.UserSettings)
 var mockReports = make(map[string]*models.UserReport)
+var mockReportsMu sync.Mutex
 
 func (ps *ProfileService) getUserSettingsMock(userID string) (*models.UserSettings, error) {
 	settings, exists := mockSettings[userID]
@@ -225,10 +274,10 @@ func (ps *ProfileService) getUserSettingsMock(userID string) (*models.UserSettin
 			ProfileVisibility:    "public",
 			ShowOnlineStatus:     true,
 			DistanceRadius:       25,
-			AgeRangeMin:          18,
-			AgeRangeMax:          65,
-			CreatedAt:            time.Now(),
-			UpdatedAt:            time.Now(),
+			AgeRangeMin:          MinUserAge,
+			AgeRangeMax:          fullAgeRangeMax,
+			CreatedAt:            clock.Now(),
+			UpdatedAt:            clock.Now(),
 		}, nil
 	}
 	return settings, nil
@@ -237,7 +286,7 @@ func (ps *ProfileService) getUserSettingsMock(userID string) (*models.UserSettin
 func (ps *ProfileService) updateUserSettingsMock(settings *models.UserSettings) (*models.UserSettings, error) {
 	// Check if settings exist, update CreatedAt if new
 	if existing, exists := mockSettings[settings.UserID]; !exists {
-		settings.CreatedAt = time.Now()
+		settings.CreatedAt = clock.Now()
 	} else {
 		settings.CreatedAt = existing.CreatedAt
 		settings.ID = existing.ID
@@ -248,10 +297,24 @@ func (ps *ProfileService) updateUserSettingsMock(settings *models.UserSettings)
 }
 
 func (ps *ProfileService) createReportMock(report *models.UserReport) error {
+	mockReportsMu.Lock()
+	defer mockReportsMu.Unlock()
 	mockReports[report.ID] = report
 	return nil
 }
 
+func (ps *ProfileService) countReportsAgainstMock(userID string, since time.Time) int {
+	mockReportsMu.Lock()
+	defer mockReportsMu.Unlock()
+	count := 0
+	for _, report := range mockReports {
+		if report.ReportedID == userID && !report.CreatedAt.Before(since) {
+			count++
+		}
+	}
+	return count
+}
+
 func (ps *ProfileService) isTestMode() bool {
 	return ps.firestoreService.client == nil
 }
@@ -0,0 +1,45 @@
+// Profile completeness scoring
+package services
+
+import "unalone-backend/internal/models"
+
+// minProfileInterests is how many interests count as having picked interests
+const minProfileInterests = 3
+
+// profileItemWeights are the percentage points each item contributes, in prompt order
+var profileItemWeights = []struct {
+	Item   string
+	Weight int
+}{
+	{models.ProfileItemPhoto, 30},
+	{models.ProfileItemBio, 20},
+	{models.ProfileItemInterests, 25},
+	{models.ProfileItemPhoneVerified, 25},
+}
+
+// ComputeProfileCompleteness scores a profile out of 100 and lists the items still missing
+func ComputeProfileCompleteness(user *models.User) *models.ProfileCompleteness {
+	completeness := &models.ProfileCompleteness{Missing: []string{}}
+	for _, item := range profileItemWeights {
+		if profileItemDone(user, item.Item) {
+			completeness.Percent += item.Weight
+		} else {
+			completeness.Missing = append(completeness.Missing, item.Item)
+		}
+	}
+	return completeness
+}
+
+func profileItemDone(user *models.User, item string) bool {
+	switch item {
+	case models.ProfileItemPhoto:
+		return user.ProfileImageURL != ""
+	case models.ProfileItemBio:
+		return user.Bio != ""
+	case models.ProfileItemInterests:
+		return len(user.Interests) >= minProfileInterests
+	case models.ProfileItemPhoneVerified:
+		return user.IsPhoneVerified
+	}
+	return false
+}
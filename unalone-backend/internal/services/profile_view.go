@@ -0,0 +1,247 @@
+// Profile view service builds another user's profile with mutual friends and shared hotspots
+package services
+
+import (
+	"errors"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"unalone-backend/internal/models"
+)
+
+const (
+	profileViewPreviewSize = 5
+	// connectionsCacheTTL bounds how stale mutual friends and shared hotspots may be
+	connectionsCacheTTL = 5 * time.Minute
+)
+
+// userConnections is the cached intersection of two users' friends and attended hotspots
+type userConnections struct {
+	MutualFriendIDs  []string `json:"mutual_friend_ids"`
+	SharedHotspotIDs []string `json:"shared_hotspot_ids"`
+}
+
+type connectionsCacheEntry struct {
+	connections userConnections
+	expiresAt   time.Time
+}
+
+// ProfileViewService assembles the view of one user's profile as seen by another
+type ProfileViewService struct {
+	userService    *UserService
+	profileService *ProfileService
+	friendsService *FriendsService
+	hotspotService *HotspotService
+	redisService   *RedisService
+	nicknames      *NicknameService
+	presence       *PresenceService
+
+	mu    sync.Mutex
+	cache map[string]connectionsCacheEntry // used when Redis is unavailable
+}
+
+// NewProfileViewService creates a new profile view service
+func NewProfileViewService(us *UserService, ps *ProfileService, fs *FriendsService, hs *HotspotService, rs *RedisService, ns *NicknameService, prs *PresenceService) *ProfileViewService {
+	return &ProfileViewService{
+		userService:    us,
+		profileService: ps,
+		friendsService: fs,
+		hotspotService: hs,
+		redisService:   rs,
+		nicknames:      ns,
+		presence:       prs,
+		cache:          make(map[string]connectionsCacheEntry),
+	}
+}
+
+// GetProfileView returns targetID's profile as viewerID sees it, honoring the target's profile visibility
+func (pvs *ProfileViewService) GetProfileView(viewerID, targetID string) (*models.UserProfileView, error) {
+	target, err := pvs.userService.GetUserByID(targetID)
+	if err != nil {
+		return nil, err
+	}
+	// Users who blocked the viewer, or belong to another organization, are
+	// indistinguishable from missing ones. BlockedBy lists who blocked a user,
+	// so check the viewer's own record.
+	viewer, err := pvs.userService.GetUserByID(viewerID)
+	if err != nil {
+		return nil, err
+	}
+	if containsString(viewer.BlockedBy, targetID) || viewer.OrgID != target.OrgID {
+		return nil, errors.New("user not found")
+	}
+
+	statuses, err := pvs.friendsService.GetFriendStatuses(viewerID, []string{targetID})
+	if err != nil {
+		return nil, err
+	}
+	view := &models.UserProfileView{
+		ID:              target.ID,
+		Nickname:        target.Nickname,
+		ProfileImageURL: target.ProfileImageURL,
+		Relationship:    statuses[targetID],
+		MutualFriends:   []models.PublicUser{},
+		SharedHotspots:  []models.SharedHotspot{},
+	}
+
+	if viewerID != targetID {
+		settings, err := pvs.profileService.GetUserSettings(targetID)
+		if err != nil {
+			return nil, err
+		}
+		switch settings.ProfileVisibility {
+		case "private":
+			view.Restricted = true
+		case "friends":
+			view.Restricted = view.Relationship != models.FriendStatusFriend
+		}
+		if view.Restricted {
+			return view, nil
+		}
+	}
+
+	view.Bio = target.Bio
+	view.Interests = target.Interests
+	view.Level = target.Level
+	// Friends get a hint of earlier nicknames so a renamed friend stays recognizable
+	if view.Relationship == models.FriendStatusFriend {
+		view.PreviousNicknames = pvs.nicknames.PreviousNicknames(target.ID, target.Nickname)
+	}
+	if viewerID == targetID {
+		return view, nil
+	}
+	view.Online, view.LastSeenAt, view.Availability = pvs.presence.Status(targetID)
+
+	connections, err := pvs.getConnections(viewerID, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	view.MutualFriendCount = len(connections.MutualFriendIDs)
+	for _, id := range connections.MutualFriendIDs {
+		if len(view.MutualFriends) == profileViewPreviewSize {
+			break
+		}
+		if friend, err := pvs.userService.GetUserByID(id); err == nil {
+			view.MutualFriends = append(view.MutualFriends, models.PublicUser{ID: friend.ID, Nickname: friend.Nickname})
+		}
+	}
+	pvs.presence.Annotate(view.MutualFriends)
+
+	view.SharedHotspotCount = len(connections.SharedHotspotIDs)
+	for _, id := range connections.SharedHotspotIDs {
+		if len(view.SharedHotspots) == profileViewPreviewSize {
+			break
+		}
+		if hotspot, err := pvs.hotspotService.GetHotspot(id); err == nil {
+			view.SharedHotspots = append(view.SharedHotspots, models.SharedHotspot{
+				ID:            hotspot.ID,
+				Name:          hotspot.Name,
+				Category:      hotspot.Category,
+				ScheduledTime: hotspot.ScheduledTime,
+			})
+		}
+	}
+	return view, nil
+}
+
+// getConnections returns the cached intersection for a pair of users, computing it on a miss
+func (pvs *ProfileViewService) getConnections(userA, userB string) (userConnections, error) {
+	// The intersection is symmetric, so both viewing directions share one entry
+	if userB < userA {
+		userA, userB = userB, userA
+	}
+	key := "social:connections:" + userA + ":" + userB
+
+	if cached, ok := pvs.getCachedConnections(key); ok {
+		return cached, nil
+	}
+
+	connections, err := pvs.computeConnections(userA, userB)
+	if err != nil {
+		return userConnections{}, err
+	}
+	pvs.setCachedConnections(key, connections)
+	return connections, nil
+}
+
+// computeConnections intersects the two users' friend lists and attended hotspots
+func (pvs *ProfileViewService) computeConnections(userA, userB string) (userConnections, error) {
+	a, err := pvs.userService.GetUserByID(userA)
+	if err != nil {
+		return userConnections{}, err
+	}
+	b, err := pvs.userService.GetUserByID(userB)
+	if err != nil {
+		return userConnections{}, err
+	}
+
+	connections := userConnections{MutualFriendIDs: []string{}, SharedHotspotIDs: []string{}}
+	friendsOfA := make(map[string]bool, len(a.Friends))
+	for _, id := range a.Friends {
+		friendsOfA[id] = true
+	}
+	for _, id := range b.Friends {
+		if friendsOfA[id] {
+			connections.MutualFriendIDs = append(connections.MutualFriendIDs, id)
+		}
+	}
+	sort.Strings(connections.MutualFriendIDs)
+
+	joinedByA, err := pvs.hotspotService.GetJoinedHotspots(userA)
+	if err != nil {
+		return userConnections{}, err
+	}
+	// Most recent first so the preview shows the latest shared events
+	sort.Slice(joinedByA, func(i, j int) bool { return joinedByA[i].CreatedAt.After(joinedByA[j].CreatedAt) })
+	for _, hotspot := range joinedByA {
+		if containsString(hotspot.Attendees, userB) {
+			connections.SharedHotspotIDs = append(connections.SharedHotspotIDs, hotspot.ID)
+		}
+	}
+	return connections, nil
+}
+
+func (pvs *ProfileViewService) getCachedConnections(key string) (userConnections, bool) {
+	if pvs.redisService.IsAvailable() {
+		var connections userConnections
+		hit, err := pvs.redisService.GetCachedJSON(key, &connections)
+		return connections, err == nil && hit
+	}
+
+	pvs.mu.Lock()
+	defer pvs.mu.Unlock()
+	entry, ok := pvs.cache[key]
+	if !ok || clock.Now().After(entry.expiresAt) {
+		delete(pvs.cache, key)
+		return userConnections{}, false
+	}
+	return entry.connections, true
+}
+
+func (pvs *ProfileViewService) setCachedConnections(key string, connections userConnections) {
+	if pvs.redisService.IsAvailable() {
+		if err := pvs.redisService.CacheJSON(key, connections, connectionsCacheTTL); err != nil {
+			log.Printf("connections cache write failed: %v", err)
+		}
+		return
+	}
+
+	pvs.mu.Lock()
+	defer pvs.mu.Unlock()
+	pvs.cache[key] = connectionsCacheEntry{connections: connections, expiresAt: clock.Now().Add(connectionsCacheTTL)}
+}
+
+// PurgeExpiredCache drops expired entries from the in-process fallback cache
+func (pvs *ProfileViewService) PurgeExpiredCache() {
+	pvs.mu.Lock()
+	defer pvs.mu.Unlock()
+	now := clock.Now()
+	for key, entry := range pvs.cache {
+		if now.After(entry.expiresAt) {
+			delete(pvs.cache, key)
+		}
+	}
+}
@@ -0,0 +1,288 @@
+// Prompt service for versioned AI prompt templates with experiment overrides
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"unalone-backend/internal/models"
+)
+
+// promptVariablePattern matches {{variable}} placeholders in a template body
+var promptVariablePattern = regexp.MustCompile(`\{\{\s*([a-z_]+)\s*\}\}`)
+
+// promptVariables are the placeholders a template may use
+var promptVariables = map[string]bool{
+	models.PromptVarUserName: true,
+	models.PromptVarLanguage: true,
+	models.PromptVarMood:     true,
+	models.PromptVarDate:     true,
+}
+
+// promptDefaults are the built-in bodies, published as version 1 of each key
+var promptDefaults = map[string]string{
+	models.PromptKeyAISystem: defaultAISystemPrompt,
+}
+
+var ErrPromptNotFound = errors.New("prompt not found")
+
+// PromptService stores published prompt versions and renders the one that applies to a user.
+// Versions are append-only, so rolling back means publishing an older body again.
+type PromptService struct {
+	firestoreService  *FirestoreService
+	experimentService *ExperimentService
+	systemOverride    string // AI_SYSTEM_PROMPT, used as version 1 and when the store is unavailable
+}
+
+// NewPromptService creates a new prompt service. Version 1 of the system prompt is
+// AI_SYSTEM_PROMPT when set, otherwise the built-in default.
+func NewPromptService(fs *FirestoreService, es *ExperimentService) *PromptService {
+	ps := &PromptService{
+		firestoreService:  fs,
+		experimentService: es,
+		systemOverride:    strings.TrimSpace(os.Getenv("AI_SYSTEM_PROMPT")),
+	}
+	if ps.isTestMode() {
+		seedMockPrompts(ps.systemOverride)
+	}
+	return ps
+}
+
+// ListPrompts returns every prompt key with its current version and experiment overrides
+func (ps *PromptService) ListPrompts() ([]models.PromptSummary, error) {
+	keys := make([]string, 0, len(promptDefaults))
+	for key := range promptDefaults {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	summaries := make([]models.PromptSummary, 0, len(keys))
+	for _, key := range keys {
+		versions, err := ps.ListVersions(key)
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, summarizePrompt(key, versions))
+	}
+	return summaries, nil
+}
+
+// ListVersions returns every published version of a prompt, newest first
+func (ps *PromptService) ListVersions(key string) ([]models.PromptTemplate, error) {
+	if _, ok := promptDefaults[key]; !ok {
+		return nil, ErrPromptNotFound
+	}
+	if !ps.isTestMode() {
+		// TODO: Query Firestore prompt_templates where key == key ordered by version desc
+		return nil, errors.New("firestore implementation needed")
+	}
+
+	mockPromptsMu.RLock()
+	defer mockPromptsMu.RUnlock()
+	versions := append([]models.PromptTemplate(nil), mockPrompts[key]...)
+	sort.Slice(versions, func(i, j int) bool { return versions[i].Version > versions[j].Version })
+	return versions, nil
+}
+
+// PublishVersion stores a new version of a prompt, effective immediately for new AI replies
+func (ps *PromptService) PublishVersion(key, adminID string, req *models.PublishPromptRequest) (*models.PromptTemplate, error) {
+	if _, ok := promptDefaults[key]; !ok {
+		return nil, ErrPromptNotFound
+	}
+	variables, err := promptTemplateVariables(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	if (req.ExperimentKey == "") != (req.Variant == "") {
+		return nil, errors.New("experiment_key and variant must be set together")
+	}
+	if req.ExperimentKey != "" {
+		experiment, err := ps.experimentService.getExperiment(req.ExperimentKey)
+		if err != nil {
+			return nil, err
+		}
+		found := false
+		for _, variant := range experiment.Variants {
+			found = found || variant.Name == req.Variant
+		}
+		if !found {
+			return nil, errors.New("variant is not part of the experiment")
+		}
+	}
+
+	if !ps.isTestMode() {
+		// TODO: Create Firestore document prompt_templates/{key}_{version} in a transaction
+		return nil, errors.New("firestore implementation needed")
+	}
+
+	mockPromptsMu.Lock()
+	defer mockPromptsMu.Unlock()
+	template := models.PromptTemplate{
+		Key:           key,
+		Version:       len(mockPrompts[key]) + 1,
+		Body:          req.Body,
+		Description:   req.Description,
+		Variables:     variables,
+		ExperimentKey: req.ExperimentKey,
+		Variant:       req.Variant,
+		PublishedBy:   adminID,
+		PublishedAt:   clock.Now(),
+	}
+	mockPrompts[key] = append(mockPrompts[key], template)
+	return &template, nil
+}
+
+// Render returns the prompt body that applies to a user with variables filled in: the latest
+// version for an experiment variant they are in, else the latest default version. When the
+// store is unavailable it falls back to AI_SYSTEM_PROMPT or the built-in body so the assistant
+// keeps working.
+func (ps *PromptService) Render(key, userID string, vars map[string]string) string {
+	body := promptDefaults[key]
+	if key == models.PromptKeyAISystem && ps.systemOverride != "" {
+		body = ps.systemOverride
+	}
+	if versions, err := ps.ListVersions(key); err != nil {
+		log.Printf("[prompts] %s: %v", key, err)
+	} else if template := ps.resolve(versions, userID); template != nil {
+		body = template.Body
+	}
+
+	return strings.TrimSpace(promptVariablePattern.ReplaceAllStringFunc(body, func(match string) string {
+		name := promptVariablePattern.FindStringSubmatch(match)[1]
+		return vars[name]
+	}))
+}
+
+// resolve picks the version that applies to a user from versions sorted newest first
+func (ps *PromptService) resolve(versions []models.PromptTemplate, userID string) *models.PromptTemplate {
+	var fallback *models.PromptTemplate
+	variants := make(map[string]string)
+	for i := range versions {
+		template := &versions[i]
+		if template.ExperimentKey == "" {
+			if fallback == nil {
+				fallback = template
+			}
+			continue
+		}
+		variant, ok := variants[template.ExperimentKey]
+		if !ok {
+			variant = ps.experimentService.Variant(userID, template.ExperimentKey)
+			variants[template.ExperimentKey] = variant
+		}
+		if variant == template.Variant {
+			return template
+		}
+	}
+	return fallback
+}
+
+// isTestMode checks if we're running with mocked database
+func (ps *PromptService) isTestMode() bool {
+	return ps.firestoreService.client == nil
+}
+
+// promptTemplateVariables returns the variables a body uses, rejecting unknown ones
+func promptTemplateVariables(body string) ([]string, error) {
+	seen := make(map[string]bool)
+	variables := make([]string, 0)
+	for _, match := range promptVariablePattern.FindAllStringSubmatch(body, -1) {
+		name := match[1]
+		if !promptVariables[name] {
+			return nil, fmt.Errorf("unknown prompt variable: %s", name)
+		}
+		if !seen[name] {
+			seen[name] = true
+			variables = append(variables, name)
+		}
+	}
+	sort.Strings(variables)
+	return variables, nil
+}
+
+// summarizePrompt finds the current default and the latest override per experiment variant
+func summarizePrompt(key string, versions []models.PromptTemplate) models.PromptSummary {
+	summary := models.PromptSummary{Key: key, Versions: len(versions)}
+	seen := make(map[string]bool)
+	current := false
+	for _, template := range versions {
+		if template.ExperimentKey == "" {
+			if !current {
+				summary.Current, current = template, true
+			}
+			continue
+		}
+		if id := template.ExperimentKey + "/" + template.Variant; !seen[id] {
+			seen[id] = true
+			summary.Overrides = append(summary.Overrides, template)
+		}
+	}
+	return summary
+}
+
+// === Mock storage in-memory for development/test ===
+
+var (
+	mockPromptsMu sync.RWMutex
+	mockPrompts   = make(map[string][]models.PromptTemplate) // key -> versions, oldest first
+)
+
+// seedMockPrompts publishes version 1 of each prompt that has none; systemOverride replaces
+// the built-in system prompt
+func seedMockPrompts(systemOverride string) {
+	mockPromptsMu.Lock()
+	defer mockPromptsMu.Unlock()
+	for key, body := range promptDefaults {
+		if len(mockPrompts[key]) > 0 {
+			continue
+		}
+		description := "Built-in default"
+		if key == models.PromptKeyAISystem && systemOverride != "" {
+			body, description = systemOverride, "From AI_SYSTEM_PROMPT"
+		}
+		variables, _ := promptTemplateVariables(body)
+		mockPrompts[key] = []models.PromptTemplate{{
+			Key:         key,
+			Version:     1,
+			Body:        body,
+			Description: description,
+			Variables:   variables,
+			PublishedBy: "system",
+			PublishedAt: clock.Now(),
+		}}
+	}
+}
+
+// defaultAISystemPrompt is a warm, culturally sensitive persona for Indian students and young adults
+const defaultAISystemPrompt = `
+You are Unalone’s Wellbeing Guide — a warm, non-clinical companion for students and young adults in India.
+
+Core persona and tone:
+- Speak like a caring senior, mentor, or friend: warm, respectful, and stigma-free.
+- Be concise, practical, and hopeful. Prefer short paragraphs and lists over long lectures.
+- Use simple English that’s comfortable for an Indian audience; you may acknowledge local context (family, exams, hostels, placements, finances, societal pressure, culture).
+
+Scope and boundaries:
+- You offer emotional support, reflection, coping skills, and psychoeducation.
+- You are NOT a medical professional; do not diagnose or prescribe. Add a gentle reminder: “I’m not a substitute for a professional.”
+- If you detect crisis, self-harm, harm to others, severe abuse, or immediate danger, encourage reaching out to trusted people and local emergency services/helplines. Do not provide step-by-step methods of self-harm.
+
+How to respond:
+- Start with brief, empathetic validation (reflect feelings without clichés).
+- Offer 2–5 concrete, low-effort steps (e.g., paced breathing, grounding, micro-breaks, journaling prompts, sleep hygiene tweaks) tailored to what they said.
+- Where relevant, suggest approachable, low-cost options (campus counselor, peer groups, government or NGO services, tele-helplines, college dean/mentor), framed as choices, never commands.
+- Encourage small check-ins, routines, and self-compassion rather than perfection.
+- When asked factual questions, answer simply and practically; cite if needed; avoid medical or legal claims.
+
+Style guidelines:
+- Prefer “Could we try…”, “One gentle option…”, “If it helps…”.
+- Avoid judgment, labels, or pathologizing language.
+- Keep most answers under ~180 words unless the user asks for depth.
+
+If the user asks “Who are you?”, answer as this persona (Unalone’s Wellbeing Guide) instead of calling yourself a generic large language model.`
@@ -0,0 +1,135 @@
+// Proximity service alerting users when a friend is active at a hotspot near them
+package services
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"unalone-backend/internal/models"
+)
+
+// proximityAlertCooldown keeps a user from being alerted twice about the same hotspot
+const proximityAlertCooldown = 24 * time.Hour
+
+// proximityActivity is a friend creating or joining a hotspot, waiting to be matched
+type proximityActivity struct {
+	actorID string
+	hotspot *models.Hotspot
+	created bool
+}
+
+// ProximityService matches friends' hotspot activity against each user's location and
+// distance radius. It runs from the event bus, so requests never wait on the fan-out.
+type ProximityService struct {
+	userService    *UserService
+	profileService *ProfileService
+	hotspotService *HotspotService
+	notifications  *NotificationService
+
+	mu      sync.Mutex
+	alerted map[string]time.Time // userID|hotspotID -> when the alert was sent
+}
+
+// NewProximityService creates a new proximity service
+func NewProximityService(us *UserService, ps *ProfileService, hs *HotspotService, ns *NotificationService) *ProximityService {
+	return &ProximityService{
+		userService:    us,
+		profileService: ps,
+		hotspotService: hs,
+		notifications:  ns,
+		alerted:        make(map[string]time.Time),
+	}
+}
+
+// HandleDomainEvent alerts the host's friends when a hotspot goes live and the joiner's
+// friends when someone joins
+func (ps *ProximityService) HandleDomainEvent(ctx context.Context, event *models.DomainEvent) error {
+	hotspot := event.Hotspot
+	if hotspot == nil || hotspot.IsDraft || !hotspot.IsPublic || !hotspot.IsActive {
+		return nil
+	}
+	ps.match(proximityActivity{actorID: event.ActorID, hotspot: hotspot, created: event.Type == models.DomainEventHotspotPublished})
+	return nil
+}
+
+// match alerts every friend of the actor who is within their chosen radius of the hotspot
+func (ps *ProximityService) match(activity proximityActivity) {
+	actor, err := ps.userService.GetUserByID(activity.actorID)
+	if err != nil {
+		log.Printf("Proximity match skipped: %v", err)
+		return
+	}
+	hotspot := activity.hotspot
+
+	attending := make(map[string]bool, len(hotspot.Attendees))
+	for _, id := range hotspot.Attendees {
+		attending[id] = true
+	}
+
+	for _, friendID := range actor.Friends {
+		if attending[friendID] || friendID == hotspot.CreatedBy {
+			continue
+		}
+		friend, err := ps.userService.GetUserByID(friendID)
+		if err != nil {
+			continue
+		}
+		if containsString(friend.MutedFriends, actor.ID) || friend.OrgID != hotspot.OrgID {
+			continue
+		}
+		// Users who never shared a location cannot be matched
+		if friend.Location.Latitude == 0 && friend.Location.Longitude == 0 {
+			continue
+		}
+		settings, err := ps.profileService.GetUserSettings(friendID)
+		if err != nil {
+			continue
+		}
+
+		distance := ps.hotspotService.calculateDistance(
+			friend.Location.Latitude, friend.Location.Longitude,
+			hotspot.Location.Latitude, hotspot.Location.Longitude,
+		)
+		if distance > float64(settings.DistanceRadius) {
+			continue
+		}
+		if !ps.markAlerted(friendID, hotspot.ID) {
+			continue
+		}
+		if err := ps.notifications.NotifyFriendNearby(friendID, actor, hotspot, activity.created, distance); err != nil {
+			log.Printf("Proximity alert failed: %v", err)
+		}
+	}
+}
+
+// markAlerted records an alert and reports false if one was already sent recently
+func (ps *ProximityService) markAlerted(userID, hotspotID string) bool {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	now := clock.Now()
+	for key, at := range ps.alerted {
+		if now.Sub(at) > proximityAlertCooldown {
+			delete(ps.alerted, key)
+		}
+	}
+
+	key := userID + "|" + hotspotID
+	if _, ok := ps.alerted[key]; ok {
+		return false
+	}
+	ps.alerted[key] = now
+	return true
+}
+
+// containsString reports whether list contains s
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+// Read-only hotspot browsing for visitors who are not signed in
+package services
+
+import (
+	"errors"
+
+	"unalone-backend/internal/models"
+)
+
+const (
+	// PublicMaxRadiusKm caps anonymous nearby searches
+	PublicMaxRadiusKm = 25.0
+	// publicScanLimit is how many candidates are read before filtering to public hotspots
+	publicScanLimit = 100
+)
+
+// ErrHotspotNotPublic hides hotspots that exist but are not open to anonymous visitors
+var ErrHotspotNotPublic = errors.New("hotspot not found")
+
+// IsPubliclyListed reports whether anonymous visitors may see a hotspot: it must be
+// browsable, not over, not limited to a friend list, and open to everyone. Audience-restricted
+// hotspots (e.g. women-only) are left out so they cannot be found without an account.
+func IsPubliclyListed(hotspot *models.Hotspot) bool {
+	if !isBrowsable(hotspot) || hotspot.OrgID != "" || hotspot.FriendListID != "" || hotspot.Audience != nil {
+		return false
+	}
+	ends := hotspotEndsAt(hotspot)
+	return ends == nil || ends.After(clock.Now())
+}
+
+// PublicHotspotOf redacts a hotspot for anonymous visitors
+func PublicHotspotOf(hotspot *models.Hotspot, distance *float64) models.PublicHotspot {
+	view := models.PublicHotspot{
+		ID:            hotspot.ID,
+		Name:          hotspot.Name,
+		Description:   hotspot.Description,
+		Category:      hotspot.Category,
+		Subcategory:   hotspot.Subcategory,
+		Location:      hotspot.Location,
+		Address:       hotspot.Address,
+		VenueID:       hotspot.VenueID,
+		Tags:          hotspot.Tags,
+		ScheduledTime: hotspot.ScheduledTime,
+		EndTime:       hotspot.EndTime,
+		ImageURL:      hotspot.ImageURL,
+		AttendeeCount: len(hotspot.Attendees),
+		MaxCapacity:   hotspot.MaxCapacity,
+		Distance:      distance,
+	}
+	if view.Tags == nil {
+		view.Tags = []string{}
+	}
+	if hotspot.External != nil {
+		view.SourceURL = hotspot.External.URL
+	}
+	return view
+}
+
+// GetPublicHotspot returns a publicly listed hotspot; anything else reads as not found
+func (hs *HotspotService) GetPublicHotspot(hotspotID string) (*models.PublicHotspot, error) {
+	hotspot, _, err := hs.readCache.Hotspot(hotspotID, hs.GetHotspot)
+	if err != nil || !IsPubliclyListed(hotspot) {
+		return nil, ErrHotspotNotPublic
+	}
+	view := PublicHotspotOf(hotspot, nil)
+	return &view, nil
+}
+
+// PublicNearbyHotspots lists active public hotspots within radiusKm (at most PublicMaxRadiusKm),
+// nearest first. Only the nearest publicScanLimit hotspots are considered.
+func (hs *HotspotService) PublicNearbyHotspots(latitude, longitude, radiusKm float64, limit, offset int) (*models.PublicHotspotList, error) {
+	isActive := true
+	req := &models.HotspotSearchRequest{
+		Latitude:  latitude,
+		Longitude: longitude,
+		Radius:    min(radiusKm, PublicMaxRadiusKm),
+		IsActive:  &isActive,
+		Limit:     publicScanLimit,
+	}
+	response, _, err := hs.readCache.Search(req, hs.SearchHotspots)
+	if err != nil {
+		return nil, err
+	}
+	return publicPage(response.Hotspots, true, limit, offset), nil
+}
+
+// PublicCityHotspots lists a city's public hotspots, soonest first
+func (hs *HotspotService) PublicCityHotspots(city, country string, limit, offset int) (*models.PublicHotspotList, error) {
+	response, err := hs.GetHotspotsByCity("", city, country, publicScanLimit, 0)
+	if err != nil {
+		return nil, err
+	}
+	return publicPage(response.Hotspots, false, limit, offset), nil
+}
+
+// publicPage filters results to publicly listed hotspots, redacts them and applies pagination
+func publicPage(results []models.HotspotWithDistance, withDistance bool, limit, offset int) *models.PublicHotspotList {
+	listed := make([]models.PublicHotspot, 0, len(results))
+	for i := range results {
+		if !IsPubliclyListed(&results[i].Hotspot) {
+			continue
+		}
+		var distance *float64
+		if withDistance {
+			distance = &results[i].Distance
+		}
+		listed = append(listed, PublicHotspotOf(&results[i].Hotspot, distance))
+	}
+
+	total := len(listed)
+	start := min(offset, total)
+	end := min(start+limit, total)
+	return &models.PublicHotspotList{Hotspots: listed[start:end], Total: total, HasMore: end < total}
+}
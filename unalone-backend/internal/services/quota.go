@@ -0,0 +1,240 @@
+// Daily usage, estimated cost and budgets for paid external providers
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"unalone-backend/internal/models"
+)
+
+// Metered external providers
+const (
+	QuotaGemini    = "gemini"
+	QuotaSMS       = "sms"
+	QuotaGeocoding = "geocoding"
+)
+
+// quotaProviders lists the metered providers in report order, with the env var holding each daily budget
+var quotaProviders = []struct {
+	name      string
+	budgetEnv string
+}{
+	{QuotaGemini, "GEMINI_DAILY_BUDGET_USD"},
+	{QuotaSMS, "SMS_DAILY_BUDGET_USD"},
+	{QuotaGeocoding, "GEOCODING_DAILY_BUDGET_USD"},
+}
+
+// MaxUsageDays is how many days of provider usage are kept and reported
+const MaxUsageDays = 7
+
+// Estimated list prices in micro-USD
+const (
+	geminiInputMicrosPerMTok  = 300_000   // gemini-2.5-flash input, per million tokens
+	geminiOutputMicrosPerMTok = 2_500_000 // gemini-2.5-flash output and thinking, per million tokens
+	geocodingMicrosPerCall    = 2_830     // Places Autocomplete request
+)
+
+// smsMicrosPerMessage estimates one text by SMS provider
+var smsMicrosPerMessage = map[string]int64{
+	"twilio": 7_900,
+	"msg91":  2_500,
+}
+
+// QuotaService counts calls to paid providers per UTC day with their estimated cost, and
+// tells callers when a provider's daily budget is spent so they can degrade instead.
+// Counts live in Redis so every instance shares the budget; without Redis each instance counts alone.
+type QuotaService struct {
+	redisService *RedisService
+	budgets      map[string]int64 // provider -> daily budget in micro-USD, 0 for none
+
+	mu              sync.Mutex
+	local           map[string]*quotaCounters // day:provider -> counters when Redis is unavailable
+	exhaustedLogged map[string]bool           // day:provider already logged as over budget
+}
+
+type quotaCounters struct {
+	calls, denied, costMicros int64
+}
+
+// NewQuotaService creates a new quota service. GEMINI_DAILY_BUDGET_USD, SMS_DAILY_BUDGET_USD
+// and GEOCODING_DAILY_BUDGET_USD cap each provider's estimated daily spend; unset means no cap.
+func NewQuotaService(rs *RedisService) *QuotaService {
+	qs := &QuotaService{
+		redisService:    rs,
+		budgets:         make(map[string]int64),
+		local:           make(map[string]*quotaCounters),
+		exhaustedLogged: make(map[string]bool),
+	}
+	for _, provider := range quotaProviders {
+		raw := strings.TrimSpace(os.Getenv(provider.budgetEnv))
+		if raw == "" {
+			continue
+		}
+		budget, err := strconv.ParseFloat(raw, 64)
+		if err != nil || budget < 0 {
+			log.Printf("Ignoring invalid %s=%q", provider.budgetEnv, raw)
+			continue
+		}
+		qs.budgets[provider.name] = int64(math.Round(budget * 1e6))
+	}
+	return qs
+}
+
+// Allow reports whether the provider's daily budget still has room. A refused call counts as denied.
+func (qs *QuotaService) Allow(provider string) bool {
+	budget := qs.budgets[provider]
+	if budget <= 0 {
+		return true
+	}
+	day := quotaDay(time.Now())
+	usage, err := qs.read(day, provider)
+	if err != nil {
+		log.Printf("Quota read for %s failed: %v", provider, err)
+		return true // A failing counter should not take the provider down with it
+	}
+	if usage.costMicros < budget {
+		return true
+	}
+
+	qs.add(day, provider, quotaCounters{denied: 1})
+	qs.mu.Lock()
+	if !qs.exhaustedLogged[day+":"+provider] {
+		qs.exhaustedLogged[day+":"+provider] = true
+		log.Printf("[quota] %s daily budget of $%.2f spent; degrading until midnight UTC", provider, float64(budget)/1e6)
+	}
+	qs.mu.Unlock()
+	return false
+}
+
+// Record counts calls made to a provider and their estimated cost in micro-USD
+func (qs *QuotaService) Record(provider string, calls, costMicros int64) {
+	qs.add(quotaDay(time.Now()), provider, quotaCounters{calls: calls, costMicros: costMicros})
+}
+
+// Usage reports each provider's daily usage for the last days days, today first
+func (qs *QuotaService) Usage(days int) (*models.ProviderUsageReport, error) {
+	if days < 1 || days > MaxUsageDays {
+		return nil, fmt.Errorf("days must be between 1 and %d", MaxUsageDays)
+	}
+
+	report := &models.ProviderUsageReport{Shared: qs.redisService.IsAvailable(), Days: make([]models.DailyProviderUsage, 0, days)}
+	now := time.Now()
+	for i := 0; i < days; i++ {
+		day := quotaDay(now.AddDate(0, 0, -i))
+		daily := models.DailyProviderUsage{Date: day, Providers: make([]models.ProviderUsage, 0, len(quotaProviders))}
+		for _, provider := range quotaProviders {
+			usage, err := qs.read(day, provider.name)
+			if err != nil {
+				return nil, err
+			}
+			budget := qs.budgets[provider.name]
+			daily.Providers = append(daily.Providers, models.ProviderUsage{
+				Provider:  provider.name,
+				Calls:     usage.calls,
+				Denied:    usage.denied,
+				CostUSD:   float64(usage.costMicros) / 1e6,
+				BudgetUSD: float64(budget) / 1e6,
+				Exhausted: budget > 0 && usage.costMicros >= budget,
+			})
+		}
+		report.Days = append(report.Days, daily)
+	}
+	return report, nil
+}
+
+// PurgeExpired drops in-process counters older than MaxUsageDays
+func (qs *QuotaService) PurgeExpired() {
+	oldest := quotaDay(time.Now().AddDate(0, 0, -MaxUsageDays))
+	qs.mu.Lock()
+	defer qs.mu.Unlock()
+	for key := range qs.local {
+		if key[:len(oldest)] < oldest {
+			delete(qs.local, key)
+		}
+	}
+	for key := range qs.exhaustedLogged {
+		if key[:len(oldest)] < oldest {
+			delete(qs.exhaustedLogged, key)
+		}
+	}
+}
+
+func (qs *QuotaService) add(day, provider string, delta quotaCounters) {
+	if qs.redisService.IsAvailable() {
+		deltas := make(map[string]int64)
+		for field, value := range map[string]int64{"calls": delta.calls, "denied": delta.denied, "cost_micros": delta.costMicros} {
+			if value != 0 {
+				deltas[field] = value
+			}
+		}
+		if err := qs.redisService.HashIncrementMany(quotaKey(day, provider), deltas, (MaxUsageDays+1)*24*time.Hour); err != nil {
+			log.Printf("Quota write for %s failed: %v", provider, err)
+		}
+		return
+	}
+
+	qs.mu.Lock()
+	defer qs.mu.Unlock()
+	counters := qs.local[day+":"+provider]
+	if counters == nil {
+		counters = &quotaCounters{}
+		qs.local[day+":"+provider] = counters
+	}
+	counters.calls += delta.calls
+	counters.denied += delta.denied
+	counters.costMicros += delta.costMicros
+}
+
+func (qs *QuotaService) read(day, provider string) (quotaCounters, error) {
+	if qs.redisService.IsAvailable() {
+		values, err := qs.redisService.HashGetMany(quotaKey(day, provider), []string{"calls", "denied", "cost_micros"})
+		if err != nil {
+			return quotaCounters{}, err
+		}
+		var counters quotaCounters
+		counters.calls, _ = strconv.ParseInt(values[0], 10, 64)
+		counters.denied, _ = strconv.ParseInt(values[1], 10, 64)
+		counters.costMicros, _ = strconv.ParseInt(values[2], 10, 64)
+		return counters, nil
+	}
+
+	qs.mu.Lock()
+	defer qs.mu.Unlock()
+	if counters := qs.local[day+":"+provider]; counters != nil {
+		return *counters, nil
+	}
+	return quotaCounters{}, nil
+}
+
+func quotaKey(day, provider string) string {
+	return "quota:" + day + ":" + provider
+}
+
+// quotaDay is the UTC day budgets reset on
+func quotaDay(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
+
+// geminiCostMicros estimates a generateContent call from the usage metadata in its response
+func geminiCostMicros(body []byte) int64 {
+	var response struct {
+		UsageMetadata struct {
+			PromptTokenCount     int64 `json:"promptTokenCount"`
+			CandidatesTokenCount int64 `json:"candidatesTokenCount"`
+			ThoughtsTokenCount   int64 `json:"thoughtsTokenCount"`
+		} `json:"usageMetadata"`
+	}
+	if len(body) == 0 || json.Unmarshal(body, &response) != nil {
+		return 0
+	}
+	usage := response.UsageMetadata
+	return (usage.PromptTokenCount*geminiInputMicrosPerMTok + (usage.CandidatesTokenCount+usage.ThoughtsTokenCount)*geminiOutputMicrosPerMTok) / 1_000_000
+}
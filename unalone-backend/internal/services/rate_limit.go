@@ -0,0 +1,97 @@
+// Fixed-window rate limiting for anonymous and other shared endpoints
+package services
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultPublicRateLimit is how many anonymous requests one IP may make per minute
+const defaultPublicRateLimit = 30
+
+// RateLimiter allows up to limit hits per key in each fixed window. Counters live in Redis so
+// replicas share them; without Redis each instance counts on its own.
+type RateLimiter struct {
+	redisService *RedisService
+	name         string
+	limit        int
+	window       time.Duration
+
+	mu      sync.Mutex
+	windows map[string]*rateWindow
+}
+
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// NewRateLimiter creates a limiter; name keeps its Redis keys apart from other limiters
+func NewRateLimiter(rs *RedisService, name string, limit int, window time.Duration) *RateLimiter {
+	return &RateLimiter{
+		redisService: rs,
+		name:         name,
+		limit:        limit,
+		window:       window,
+		windows:      make(map[string]*rateWindow),
+	}
+}
+
+// NewPublicRateLimiter creates the per-IP limiter for unauthenticated endpoints.
+// PUBLIC_RATE_LIMIT overrides the requests allowed per minute.
+func NewPublicRateLimiter(rs *RedisService) *RateLimiter {
+	limit := defaultPublicRateLimit
+	if n, err := strconv.Atoi(strings.TrimSpace(os.Getenv("PUBLIC_RATE_LIMIT"))); err == nil && n > 0 {
+		limit = n
+	}
+	return NewRateLimiter(rs, "public", limit, time.Minute)
+}
+
+// Limit is the number of hits allowed per window
+func (rl *RateLimiter) Limit() int {
+	return rl.limit
+}
+
+// Allow counts a hit for key. When the key is over its limit it returns false and how long
+// until the window resets. Redis errors fail open so an outage does not block reads.
+func (rl *RateLimiter) Allow(key string) (bool, time.Duration) {
+	now := time.Now()
+	start := now.Truncate(rl.window)
+	retryAfter := start.Add(rl.window).Sub(now)
+
+	if rl.redisService.IsAvailable() {
+		count, err := rl.redisService.IncrementWindow(fmt.Sprintf("ratelimit:%s:%s:%d", rl.name, key, start.Unix()), rl.window)
+		if err != nil {
+			log.Printf("[ratelimit] %s: %v", rl.name, err)
+			return true, 0
+		}
+		return count <= int64(rl.limit), retryAfter
+	}
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	state, ok := rl.windows[key]
+	if !ok || !state.start.Equal(start) {
+		rl.windows[key] = &rateWindow{start: start, count: 1}
+		return true, retryAfter
+	}
+	state.count++
+	return state.count <= rl.limit, retryAfter
+}
+
+// PurgeExpired drops in-process counters from past windows
+func (rl *RateLimiter) PurgeExpired() {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	current := time.Now().Truncate(rl.window)
+	for key, state := range rl.windows {
+		if state.start.Before(current) {
+			delete(rl.windows, key)
+		}
+	}
+}
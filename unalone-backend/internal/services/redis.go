@@ -4,10 +4,13 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"unalone-backend/internal/models"
@@ -18,14 +21,22 @@ import (
 type RedisService struct {
 	client *redis.Client
 	ctx    context.Context
+	// unreachable is set by the health registry while probes fail, so callers use their fallbacks
+	unreachable atomic.Bool
 }
 
 // NewRedisService creates a new Redis service instance
-func NewRedisService(ctx context.Context) (*RedisService, error) {
+func NewRedisService(ctx context.Context, secrets SecretsProvider) (*RedisService, error) {
+	// REDIS_DISABLED skips the connection entirely (e.g. for the end-to-end harness)
+	if disabled, _ := strconv.ParseBool(os.Getenv("REDIS_DISABLED")); disabled {
+		log.Println("Redis disabled. Running without cache.")
+		return &RedisService{client: nil, ctx: ctx}, nil
+	}
+
 	// Redis configuration from environment variables
 	redisHost := os.Getenv("REDIS_HOST")
 	redisPort := os.Getenv("REDIS_PORT")
-	redisPassword := os.Getenv("REDIS_PASSWORD")
+	redisPassword := lookupSecret(ctx, secrets, "REDIS_PASSWORD")
 	redisDB := os.Getenv("REDIS_DB")
 
 	// Default values for development
@@ -67,9 +78,21 @@ func NewRedisService(ctx context.Context) (*RedisService, error) {
 	return &RedisService{client: rdb, ctx: ctx}, nil
 }
 
-// IsAvailable checks if Redis is available
+// IsAvailable checks if Redis is connected and not marked down by the health registry
 func (rs *RedisService) IsAvailable() bool {
-	return rs.client != nil
+	return rs != nil && rs.client != nil && !rs.unreachable.Load()
+}
+
+// Ping checks that Redis answers, whether or not it is marked down
+func (rs *RedisService) Ping(ctx context.Context) error {
+	if rs == nil || rs.client == nil {
+		return errors.New("redis unavailable")
+	}
+	return rs.client.Ping(ctx).Err()
+}
+
+func (rs *RedisService) setReachable(up bool) {
+	rs.unreachable.Store(!up)
 }
 
 // Close closes the Redis connection
@@ -117,13 +140,14 @@ func (rs *RedisService) GetCachedHotspots(lat, lon, radius float64) ([]*models.H
 	return hotspots, err
 }
 
-// CacheClusterResults caches clustering results for different zoom levels
-func (rs *RedisService) CacheClusterResults(lat, lon, radius float64, zoomLevel int, clusters []models.HotspotCluster, ttl time.Duration) error {
+// CacheClusterResults caches clustering results for different zoom levels. variant tells apart
+// results computed with different options for the same region, e.g. with previews embedded.
+func (rs *RedisService) CacheClusterResults(lat, lon, radius float64, zoomLevel int, variant string, clusters []models.HotspotCluster, ttl time.Duration) error {
 	if !rs.IsAvailable() {
 		return nil
 	}
 
-	key := rs.getClusterKey(lat, lon, radius, zoomLevel)
+	key := rs.getClusterKey(lat, lon, radius, zoomLevel) + variant
 	data, err := json.Marshal(clusters)
 	if err != nil {
 		return err
@@ -133,12 +157,12 @@ func (rs *RedisService) CacheClusterResults(lat, lon, radius float64, zoomLevel
 }
 
 // GetCachedClusterResults retrieves cached clustering results
-func (rs *RedisService) GetCachedClusterResults(lat, lon, radius float64, zoomLevel int) ([]models.HotspotCluster, error) {
+func (rs *RedisService) GetCachedClusterResults(lat, lon, radius float64, zoomLevel int, variant string) ([]models.HotspotCluster, error) {
 	if !rs.IsAvailable() {
 		return nil, nil
 	}
 
-	key := rs.getClusterKey(lat, lon, radius, zoomLevel)
+	key := rs.getClusterKey(lat, lon, radius, zoomLevel) + variant
 	data, err := rs.client.Get(rs.ctx, key).Result()
 	if err != nil {
 		if err == redis.Nil {
@@ -152,6 +176,306 @@ func (rs *RedisService) GetCachedClusterResults(lat, lon, radius float64, zoomLe
 	return clusters, err
 }
 
+// === Generic Caching Methods ===
+
+// CacheJSON stores any JSON-serializable value under key
+func (rs *RedisService) CacheJSON(key string, value interface{}, ttl time.Duration) error {
+	if !rs.IsAvailable() {
+		return nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return rs.client.Set(rs.ctx, key, data, ttl).Err()
+}
+
+// GetCachedJSON loads a cached value into dest; returns false on cache miss
+func (rs *RedisService) GetCachedJSON(key string, dest interface{}) (bool, error) {
+	if !rs.IsAvailable() {
+		return false, nil
+	}
+
+	data, err := rs.client.Get(rs.ctx, key).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return false, nil // Cache miss
+		}
+		return false, err
+	}
+
+	if err := json.Unmarshal([]byte(data), dest); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Delete removes keys
+func (rs *RedisService) Delete(keys ...string) error {
+	if !rs.IsAvailable() {
+		return nil
+	}
+
+	return rs.client.Del(rs.ctx, keys...).Err()
+}
+
+// === Sliding Window Counters ===
+
+// HashGetMany reads several fields of a hash, returning "" for missing ones
+func (rs *RedisService) HashGetMany(key string, fields []string) ([]string, error) {
+	values := make([]string, len(fields))
+	if !rs.IsAvailable() || len(fields) == 0 {
+		return values, nil
+	}
+
+	raw, err := rs.client.HMGet(rs.ctx, key, fields...).Result()
+	if err != nil {
+		return nil, err
+	}
+	for i, value := range raw {
+		if s, ok := value.(string); ok {
+			values[i] = s
+		}
+	}
+	return values, nil
+}
+
+// HashSetMany writes several fields of a hash and (re)sets its TTL
+func (rs *RedisService) HashSetMany(key string, values map[string]interface{}, ttl time.Duration) error {
+	if !rs.IsAvailable() || len(values) == 0 {
+		return nil
+	}
+
+	pipe := rs.client.TxPipeline()
+	pipe.HSet(rs.ctx, key, values)
+	pipe.Expire(rs.ctx, key, ttl)
+	_, err := pipe.Exec(rs.ctx)
+	return err
+}
+
+// HashIncrementMany adds deltas to integer fields of a hash and (re)sets its TTL
+func (rs *RedisService) HashIncrementMany(key string, deltas map[string]int64, ttl time.Duration) error {
+	if !rs.IsAvailable() || len(deltas) == 0 {
+		return nil
+	}
+
+	pipe := rs.client.TxPipeline()
+	for field, delta := range deltas {
+		pipe.HIncrBy(rs.ctx, key, field, delta)
+	}
+	pipe.Expire(rs.ctx, key, ttl)
+	_, err := pipe.Exec(rs.ctx)
+	return err
+}
+
+// RecordWindowEvent adds a timestamped event to a sorted set and trims entries older than window
+func (rs *RedisService) RecordWindowEvent(key, member string, at time.Time, window time.Duration) error {
+	if !rs.IsAvailable() {
+		return nil
+	}
+
+	pipe := rs.client.TxPipeline()
+	pipe.ZAdd(rs.ctx, key, &redis.Z{Score: float64(at.UnixMilli()), Member: member})
+	pipe.ZRemRangeByScore(rs.ctx, key, "-inf", strconv.FormatInt(at.Add(-window).UnixMilli(), 10))
+	pipe.Expire(rs.ctx, key, window)
+	_, err := pipe.Exec(rs.ctx)
+	return err
+}
+
+// CountWindowEvents counts events recorded at or after since
+func (rs *RedisService) CountWindowEvents(key string, since time.Time) (int64, error) {
+	if !rs.IsAvailable() {
+		return 0, nil
+	}
+
+	return rs.client.ZCount(rs.ctx, key, strconv.FormatInt(since.UnixMilli(), 10), "+inf").Result()
+}
+
+// IncrementWindow counts a hit against a fixed window key, expiring it with the window
+func (rs *RedisService) IncrementWindow(key string, window time.Duration) (int64, error) {
+	if !rs.IsAvailable() {
+		return 0, nil
+	}
+
+	pipe := rs.client.TxPipeline()
+	incr := pipe.Incr(rs.ctx, key)
+	pipe.Expire(rs.ctx, key, window)
+	if _, err := pipe.Exec(rs.ctx); err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+// CountUnique adds member to a HyperLogLog for approximate distinct counts, expiring it after ttl
+func (rs *RedisService) CountUnique(key, member string, ttl time.Duration) error {
+	if !rs.IsAvailable() {
+		return nil
+	}
+
+	pipe := rs.client.TxPipeline()
+	pipe.PFAdd(rs.ctx, key, member)
+	pipe.Expire(rs.ctx, key, ttl)
+	_, err := pipe.Exec(rs.ctx)
+	return err
+}
+
+// UniqueCount returns the approximate number of distinct members across the HyperLogLogs at keys
+func (rs *RedisService) UniqueCount(keys ...string) (int64, error) {
+	if !rs.IsAvailable() {
+		return 0, nil
+	}
+
+	return rs.client.PFCount(rs.ctx, keys...).Result()
+}
+
+// GetCount reads a counter written by IncrementWindow; a missing key counts as zero
+func (rs *RedisService) GetCount(key string) (int64, error) {
+	if !rs.IsAvailable() {
+		return 0, nil
+	}
+
+	n, err := rs.client.Get(rs.ctx, key).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return n, err
+}
+
+// === One-time values ===
+
+// SetString stores a string value that expires after ttl
+func (rs *RedisService) SetString(key, value string, ttl time.Duration) error {
+	if !rs.IsAvailable() {
+		return nil
+	}
+
+	return rs.client.Set(rs.ctx, key, value, ttl).Err()
+}
+
+// TakeString reads and deletes a value written by SetString, so only one caller gets it
+func (rs *RedisService) TakeString(key string) (string, bool, error) {
+	if !rs.IsAvailable() {
+		return "", false, nil
+	}
+
+	value, err := rs.client.GetDel(rs.ctx, key).Result()
+	if err == redis.Nil {
+		return "", false, nil
+	}
+	return value, err == nil, err
+}
+
+// === Leases ===
+
+// renewLeaseScript extends a lease only if it is still held by the caller
+var renewLeaseScript = redis.NewScript(`if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("PEXPIRE", KEYS[1], ARGV[2]) end return 0`)
+
+// releaseLeaseScript deletes a lease only if it is still held by the caller
+var releaseLeaseScript = redis.NewScript(`if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("DEL", KEYS[1]) end return 0`)
+
+// AcquireLease takes or renews a lease on key for holder. Without Redis the caller always holds it.
+func (rs *RedisService) AcquireLease(key, holder string, ttl time.Duration) (bool, error) {
+	if !rs.IsAvailable() {
+		return true, nil
+	}
+
+	acquired, err := rs.client.SetNX(rs.ctx, key, holder, ttl).Result()
+	if err != nil || acquired {
+		return acquired, err
+	}
+	renewed, err := renewLeaseScript.Run(rs.ctx, rs.client, []string{key}, holder, ttl.Milliseconds()).Int()
+	return renewed == 1, err
+}
+
+// ReleaseLease gives up a lease held by holder so another instance can take it immediately
+func (rs *RedisService) ReleaseLease(key, holder string) error {
+	if !rs.IsAvailable() {
+		return nil
+	}
+
+	return releaseLeaseScript.Run(rs.ctx, rs.client, []string{key}, holder).Err()
+}
+
+// === Streams ===
+
+// StreamEntry is one message read from a Redis Stream
+type StreamEntry struct {
+	ID      string
+	Payload []byte
+}
+
+// AppendToStream adds value as JSON to a stream capped at roughly maxLen entries
+func (rs *RedisService) AppendToStream(key string, value interface{}, maxLen int64) error {
+	if !rs.IsAvailable() {
+		return errors.New("redis unavailable")
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return rs.client.XAdd(rs.ctx, &redis.XAddArgs{
+		Stream: key,
+		MaxLen: maxLen,
+		Approx: true,
+		Values: map[string]interface{}{"payload": data},
+	}).Err()
+}
+
+// EnsureStreamGroup creates a consumer group reading new entries, creating the stream if needed
+func (rs *RedisService) EnsureStreamGroup(key, group string) error {
+	if !rs.IsAvailable() {
+		return errors.New("redis unavailable")
+	}
+
+	err := rs.client.XGroupCreateMkStream(rs.ctx, key, group, "$").Err()
+	if err != nil && strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return nil
+	}
+	return err
+}
+
+// ReadStreamGroup reads up to count new entries for a consumer, waiting up to block for them
+func (rs *RedisService) ReadStreamGroup(key, group, consumer string, count int64, block time.Duration) ([]StreamEntry, error) {
+	if !rs.IsAvailable() {
+		return nil, errors.New("redis unavailable")
+	}
+
+	streams, err := rs.client.XReadGroup(rs.ctx, &redis.XReadGroupArgs{
+		Group:    group,
+		Consumer: consumer,
+		Streams:  []string{key, ">"},
+		Count:    count,
+		Block:    block,
+	}).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []StreamEntry
+	for _, stream := range streams {
+		for _, msg := range stream.Messages {
+			payload, _ := msg.Values["payload"].(string)
+			entries = append(entries, StreamEntry{ID: msg.ID, Payload: []byte(payload)})
+		}
+	}
+	return entries, nil
+}
+
+// AckStream acknowledges a handled entry for a consumer group
+func (rs *RedisService) AckStream(key, group, id string) error {
+	if !rs.IsAvailable() {
+		return nil
+	}
+
+	return rs.client.XAck(rs.ctx, key, group, id).Err()
+}
+
 // === Geospatial Operations ===
 
 // AddHotspotToGeoIndex adds a hotspot to the geospatial index
@@ -207,6 +531,99 @@ func (rs *RedisService) GetNearbyHotspotIDs(lat, lon, radiusKm float64) ([]strin
 	return ids, nil
 }
 
+// === Geohash Counts ===
+
+// Hotspot counts are kept per geohash cell at every precision from 1 to maxCountPrecision,
+// one hash per precision, so a viewport at any zoom reads a handful of fields.
+const (
+	maxCountPrecision = 6
+	hotspotCellsKey   = "hotspots:cells" // hotspot ID -> geohash it is counted under
+)
+
+// moveHotspotCellScript moves a hotspot's count from its recorded cell to a new one ("" removes it)
+// in one step, so replays and concurrent updates cannot count a hotspot twice
+var moveHotspotCellScript = redis.NewScript(`
+local old = redis.call("HGET", KEYS[1], ARGV[1])
+local new = ARGV[2]
+if old == new or (not old and new == "") then return 0 end
+for p = 1, tonumber(ARGV[3]) do
+	local key = "hotspots:counts:p" .. p
+	if old then
+		local cell = string.sub(old, 1, p)
+		if redis.call("HINCRBY", key, cell, -1) <= 0 then redis.call("HDEL", key, cell) end
+	end
+	if new ~= "" then redis.call("HINCRBY", key, string.sub(new, 1, p), 1) end
+end
+if new == "" then redis.call("HDEL", KEYS[1], ARGV[1]) else redis.call("HSET", KEYS[1], ARGV[1], new) end
+return 1`)
+
+func hotspotCountsKey(precision int) string {
+	return fmt.Sprintf("hotspots:counts:p%d", precision)
+}
+
+// SetHotspotCountCell counts an active hotspot in the cell containing its location
+func (rs *RedisService) SetHotspotCountCell(hotspot *models.Hotspot) error {
+	if !rs.IsAvailable() {
+		return nil
+	}
+	geohash := EncodeGeohash(hotspot.Location.Latitude, hotspot.Location.Longitude, maxCountPrecision)
+	return moveHotspotCellScript.Run(rs.ctx, rs.client, []string{hotspotCellsKey}, hotspot.ID, geohash, maxCountPrecision).Err()
+}
+
+// ClearHotspotCountCell stops counting a hotspot that was deleted, archived or hidden
+func (rs *RedisService) ClearHotspotCountCell(hotspotID string) error {
+	if !rs.IsAvailable() {
+		return nil
+	}
+	return moveHotspotCellScript.Run(rs.ctx, rs.client, []string{hotspotCellsKey}, hotspotID, "", maxCountPrecision).Err()
+}
+
+// GetHotspotCounts reads the counts for the given cells, which must all have the same length
+func (rs *RedisService) GetHotspotCounts(geohashes []string) ([]int64, error) {
+	counts := make([]int64, len(geohashes))
+	if !rs.IsAvailable() || len(geohashes) == 0 {
+		return counts, nil
+	}
+
+	values, err := rs.client.HMGet(rs.ctx, hotspotCountsKey(len(geohashes[0])), geohashes...).Result()
+	if err != nil {
+		return nil, err
+	}
+	for i, value := range values {
+		if s, ok := value.(string); ok {
+			counts[i], _ = strconv.ParseInt(s, 10, 64)
+		}
+	}
+	return counts, nil
+}
+
+// === User Locations ===
+
+// usersGeoKey indexes users' last known locations
+const usersGeoKey = "users:geo"
+
+// SetUserLocation records a user's last known location in the geo index
+func (rs *RedisService) SetUserLocation(userID string, lat, lon float64) error {
+	if !rs.IsAvailable() {
+		return nil
+	}
+
+	return rs.client.GeoAdd(rs.ctx, usersGeoKey, &redis.GeoLocation{
+		Name:      userID,
+		Longitude: lon,
+		Latitude:  lat,
+	}).Err()
+}
+
+// RemoveUserLocation drops a user from the geo index
+func (rs *RedisService) RemoveUserLocation(userID string) error {
+	if !rs.IsAvailable() {
+		return nil
+	}
+
+	return rs.client.ZRem(rs.ctx, usersGeoKey, userID).Err()
+}
+
 // === Cache Invalidation ===
 
 // InvalidateRegionCache invalidates cache for a specific region
@@ -226,7 +643,7 @@ func (rs *RedisService) InvalidateRegionCache(lat, lon, radius float64) error {
 	return rs.client.Del(rs.ctx, regionKey).Err()
 }
 
-// InvalidateHotspotCache invalidates all cache related to a hotspot
+// InvalidateHotspotCache removes a hotspot from the geo index and drops every cached search result
 func (rs *RedisService) InvalidateHotspotCache(hotspot *models.Hotspot) error {
 	if !rs.IsAvailable() {
 		return nil
@@ -238,13 +655,19 @@ func (rs *RedisService) InvalidateHotspotCache(hotspot *models.Hotspot) error {
 		log.Printf("Failed to remove hotspot from geo index: %v", err)
 	}
 
-	// Invalidate surrounding regions (approximate)
-	radiusesToInvalidate := []float64{1, 5, 10, 25, 50}
-	for _, radius := range radiusesToInvalidate {
-		rs.InvalidateRegionCache(hotspot.Location.Latitude, hotspot.Location.Longitude, radius)
+	return rs.InvalidateSearchCaches()
+}
+
+// InvalidateSearchCaches drops every cached region and cluster result at once. Their keys
+// embed a version number, so bumping it orphans the old entries until their TTL expires.
+// Keys are centered on the query rather than on hotspots, so there is no cheaper way to
+// find every cached result a hotspot appears in.
+func (rs *RedisService) InvalidateSearchCaches() error {
+	if !rs.IsAvailable() {
+		return nil
 	}
 
-	return nil
+	return rs.client.Incr(rs.ctx, hotspotCacheVersionKey).Err()
 }
 
 // === Performance Monitoring ===
@@ -272,6 +695,18 @@ func (rs *RedisService) GetCacheStats() (map[string]interface{}, error) {
 
 // === Helper Methods ===
 
+// hotspotCacheVersionKey holds the version embedded in region and cluster cache keys
+const hotspotCacheVersionKey = "hotspots:cache:version"
+
+// cacheVersion returns the current search cache version ("0" until the first invalidation)
+func (rs *RedisService) cacheVersion() string {
+	version, err := rs.client.Get(rs.ctx, hotspotCacheVersionKey).Result()
+	if err != nil {
+		return "0"
+	}
+	return version
+}
+
 // getRegionKey generates a cache key for a specific region
 func (rs *RedisService) getRegionKey(lat, lon, radius float64) string {
 	// Round coordinates to reduce cache key variations
@@ -279,7 +714,7 @@ func (rs *RedisService) getRegionKey(lat, lon, radius float64) string {
 	lonRounded := fmt.Sprintf("%.4f", lon)
 	radiusRounded := fmt.Sprintf("%.1f", radius)
 
-	return fmt.Sprintf("hotspots:region:%s,%s:%s", latRounded, lonRounded, radiusRounded)
+	return fmt.Sprintf("hotspots:region:v%s:%s,%s:%s", rs.cacheVersion(), latRounded, lonRounded, radiusRounded)
 }
 
 // getClusterKey generates a cache key for clustering results
@@ -288,16 +723,14 @@ func (rs *RedisService) getClusterKey(lat, lon, radius float64, zoomLevel int) s
 	lonRounded := fmt.Sprintf("%.4f", lon)
 	radiusRounded := fmt.Sprintf("%.1f", radius)
 
-	return fmt.Sprintf("hotspots:clusters:%s,%s:%s:z%d", latRounded, lonRounded, radiusRounded, zoomLevel)
+	return fmt.Sprintf("hotspots:clusters:v%s:%s,%s:%s:z%d", rs.cacheVersion(), latRounded, lonRounded, radiusRounded, zoomLevel)
 }
 
 // === Geohash Utilities ===
 
 // EncodeGeohash encodes latitude and longitude into a geohash
 func (rs *RedisService) EncodeGeohash(lat, lon float64, precision int) string {
-	// Simple geohash implementation for basic geographical hashing
-	// In production, you might want to use a more robust geohash library
-	return fmt.Sprintf("gh_%d_%d_%d", int(lat*1000000), int(lon*1000000), precision)
+	return EncodeGeohash(lat, lon, precision)
 }
 
 // GetGeohashNeighbors returns neighboring geohash cells for better coverage
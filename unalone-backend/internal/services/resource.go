@@ -0,0 +1,320 @@
+// Resource service for the curated wellbeing resources directory
+package services
+
+import (
+	"errors"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"unalone-backend/internal/models"
+)
+
+// regionAnchorRadiusKm is how close a point must be to a city anchor to take its region
+const regionAnchorRadiusKm = 150
+
+// maxCrisisResources is how many crisis resources are added to an AI crisis response
+const maxCrisisResources = 3
+
+// ResourceService manages the curated directory of helplines, counseling centers and NGOs
+type ResourceService struct {
+	firestoreService *FirestoreService
+	defaultCountry   string // Used when a user's location is unknown
+}
+
+// NewResourceService creates a new resource service
+func NewResourceService(fs *FirestoreService) *ResourceService {
+	country := strings.ToUpper(strings.TrimSpace(os.Getenv("RESOURCES_DEFAULT_COUNTRY")))
+	if country == "" {
+		country = "IN"
+	}
+	return &ResourceService{firestoreService: fs, defaultCountry: country}
+}
+
+// ResourcesNear returns the active resources that apply at a point: international services,
+// nationwide and regional ones for the country and region the point falls in, and local
+// services whose radius covers it
+func (rs *ResourceService) ResourcesNear(lat, lon float64) (*models.ResourceDirectory, error) {
+	country, region := locateRegion(lat, lon)
+	at := &models.HotspotLocation{Latitude: lat, Longitude: lon}
+	resources, err := rs.matchResources(country, region, at)
+	if err != nil {
+		return nil, err
+	}
+	return &models.ResourceDirectory{Country: country, Region: region, Resources: resources}, nil
+}
+
+// CrisisResources returns the crisis resources to show someone at a point, or in the default
+// country when their location is unknown. Lookup errors yield no resources.
+func (rs *ResourceService) CrisisResources(at *models.HotspotLocation) []models.WellbeingResource {
+	country, region := rs.defaultCountry, ""
+	if at != nil {
+		if c, r := locateRegion(at.Latitude, at.Longitude); c != "" {
+			country, region = c, r
+		}
+	}
+	resources, err := rs.matchResources(country, region, at)
+	if err != nil {
+		return nil
+	}
+
+	crisis := make([]models.WellbeingResource, 0, maxCrisisResources)
+	for _, r := range resources {
+		if r.Crisis {
+			crisis = append(crisis, r)
+		}
+		if len(crisis) == maxCrisisResources {
+			break
+		}
+	}
+	return crisis
+}
+
+// UpsertResource creates or updates a resource
+func (rs *ResourceService) UpsertResource(id string, req *models.UpsertResourceRequest) (*models.WellbeingResource, error) {
+	if !categoryIDPattern.MatchString(id) || len(id) > 50 {
+		return nil, errors.New("resource ID must be a lowercase slug")
+	}
+	if req.Phone == "" && req.URL == "" && req.Location == nil {
+		return nil, errors.New("resource needs a phone number, URL or location")
+	}
+	country := strings.ToUpper(req.Country)
+	region := strings.ToUpper(req.Region)
+	if region != "" && !strings.HasPrefix(region, country+"-") {
+		return nil, errors.New("region must be a subdivision of the resource's country, e.g. IN-KA")
+	}
+	if req.Location != nil && req.RadiusKm == 0 {
+		return nil, errors.New("resources with a location need a radius")
+	}
+
+	if !rs.isTestMode() {
+		// TODO: Upsert Firestore document wellbeing_resources/{id}
+		return nil, errors.New("firestore implementation needed")
+	}
+
+	mockResourcesMu.Lock()
+	defer mockResourcesMu.Unlock()
+
+	now := clock.Now()
+	resource, exists := mockResources[id]
+	if !exists {
+		resource = &models.WellbeingResource{ID: id, IsActive: true, CreatedAt: now}
+	}
+	resource.Name = req.Name
+	resource.Kind = req.Kind
+	resource.Description = req.Description
+	resource.Phone = req.Phone
+	resource.URL = req.URL
+	resource.Hours = req.Hours
+	resource.Languages = req.Languages
+	resource.Country = country
+	resource.Region = region
+	resource.Location = req.Location
+	resource.RadiusKm = req.RadiusKm
+	resource.Crisis = req.Crisis
+	resource.SortOrder = req.SortOrder
+	if req.IsActive != nil {
+		resource.IsActive = *req.IsActive
+	}
+	resource.UpdatedAt = now
+	mockResources[id] = resource
+
+	result := *resource
+	return &result, nil
+}
+
+// DeactivateResource hides a resource from the directory and AI responses
+func (rs *ResourceService) DeactivateResource(id string) error {
+	if !rs.isTestMode() {
+		// TODO: Update Firestore document wellbeing_resources/{id}
+		return errors.New("firestore implementation needed")
+	}
+
+	mockResourcesMu.Lock()
+	defer mockResourcesMu.Unlock()
+
+	resource, exists := mockResources[id]
+	if !exists {
+		return errors.New("resource not found")
+	}
+	resource.IsActive = false
+	resource.UpdatedAt = clock.Now()
+	return nil
+}
+
+// matchResources returns active resources for a country and region, plus local resources
+// covering the point. Crisis services come first, then the most local ones.
+func (rs *ResourceService) matchResources(country, region string, at *models.HotspotLocation) ([]models.WellbeingResource, error) {
+	if !rs.isTestMode() {
+		// TODO: Query Firestore wellbeing_resources by country
+		return nil, errors.New("firestore implementation needed")
+	}
+
+	mockResourcesMu.RLock()
+	matched := make([]models.WellbeingResource, 0)
+	for _, r := range mockResources {
+		if !r.IsActive {
+			continue
+		}
+		resource := *r
+		if resource.Location != nil {
+			if at == nil {
+				continue
+			}
+			resource.DistanceKm = haversineKm(*at, *resource.Location)
+			if resource.DistanceKm > resource.RadiusKm {
+				continue
+			}
+		} else if resource.Country != "" && (resource.Country != country || (resource.Region != "" && resource.Region != region)) {
+			continue
+		}
+		matched = append(matched, resource)
+	}
+	mockResourcesMu.RUnlock()
+
+	sort.Slice(matched, func(i, j int) bool {
+		a, b := matched[i], matched[j]
+		if a.Crisis != b.Crisis {
+			return a.Crisis
+		}
+		if sa, sb := resourceScope(a), resourceScope(b); sa != sb {
+			return sa < sb
+		}
+		if a.DistanceKm != b.DistanceKm {
+			return a.DistanceKm < b.DistanceKm
+		}
+		if a.SortOrder != b.SortOrder {
+			return a.SortOrder < b.SortOrder
+		}
+		return a.Name < b.Name
+	})
+	return matched, nil
+}
+
+// resourceScope ranks how local a resource is: local, regional, nationwide, international
+func resourceScope(r models.WellbeingResource) int {
+	switch {
+	case r.Location != nil:
+		return 0
+	case r.Region != "":
+		return 1
+	case r.Country != "":
+		return 2
+	default:
+		return 3
+	}
+}
+
+// isTestMode checks if we're running with mocked database
+func (rs *ResourceService) isTestMode() bool {
+	return rs.firestoreService.client == nil
+}
+
+// === Offline region lookup ===
+
+// resourceCountry is a country's bounding box; the curated countries' boxes do not overlap
+type resourceCountry struct {
+	code                           string
+	minLat, maxLat, minLon, maxLon float64
+}
+
+// regionAnchor is a city whose surroundings belong to a region
+type regionAnchor struct {
+	region   string
+	lat, lon float64
+}
+
+var resourceCountries = []resourceCountry{
+	{code: "IN", minLat: 6.5, maxLat: 35.7, minLon: 68.1, maxLon: 97.4},
+	{code: "US", minLat: 24.4, maxLat: 49.4, minLon: -125.0, maxLon: -66.9},
+	{code: "GB", minLat: 49.9, maxLat: 60.9, minLon: -8.2, maxLon: 1.8},
+}
+
+// regionAnchors maps major cities to their ISO 3166-2 regions. State borders are not
+// stored, so a point takes the region of the nearest anchor within regionAnchorRadiusKm.
+var regionAnchors = []regionAnchor{
+	{region: "IN-DL", lat: 28.61, lon: 77.21},
+	{region: "IN-HR", lat: 28.46, lon: 77.03},
+	{region: "IN-UP", lat: 28.54, lon: 77.39},
+	{region: "IN-UP", lat: 26.85, lon: 80.95},
+	{region: "IN-MH", lat: 19.08, lon: 72.88},
+	{region: "IN-MH", lat: 18.52, lon: 73.86},
+	{region: "IN-MH", lat: 21.15, lon: 79.09},
+	{region: "IN-KA", lat: 12.97, lon: 77.59},
+	{region: "IN-KA", lat: 12.30, lon: 76.64},
+	{region: "IN-KA", lat: 12.91, lon: 74.86},
+	{region: "IN-KA", lat: 15.36, lon: 75.12},
+	{region: "IN-TN", lat: 13.08, lon: 80.27},
+	{region: "IN-TN", lat: 11.02, lon: 76.96},
+	{region: "IN-TN", lat: 9.93, lon: 78.12},
+	{region: "IN-TG", lat: 17.39, lon: 78.49},
+	{region: "IN-TG", lat: 17.97, lon: 79.59},
+	{region: "IN-WB", lat: 22.57, lon: 88.36},
+	{region: "IN-KL", lat: 9.93, lon: 76.27},
+	{region: "IN-KL", lat: 8.52, lon: 76.94},
+	{region: "IN-GJ", lat: 23.02, lon: 72.57},
+}
+
+// locateRegion returns the curated country and, near a known city, the region containing a point
+func locateRegion(lat, lon float64) (country, region string) {
+	for _, c := range resourceCountries {
+		if lat >= c.minLat && lat <= c.maxLat && lon >= c.minLon && lon <= c.maxLon {
+			country = c.code
+			break
+		}
+	}
+	if country == "" {
+		return "", ""
+	}
+
+	point := models.HotspotLocation{Latitude: lat, Longitude: lon}
+	nearest := float64(regionAnchorRadiusKm)
+	for _, a := range regionAnchors {
+		if !strings.HasPrefix(a.region, country+"-") {
+			continue
+		}
+		if d := haversineKm(point, models.HotspotLocation{Latitude: a.lat, Longitude: a.lon}); d <= nearest {
+			nearest, region = d, a.region
+		}
+	}
+	return country, region
+}
+
+// === Mock storage in-memory for development/test ===
+
+// defaultResources seeds the directory; operators keep it current through the admin endpoints
+var defaultResources = []models.WellbeingResource{
+	{ID: "emergency-112", Name: "Emergency Response (112)", Kind: models.ResourceKindHelpline, Description: "Police, fire and ambulance", Phone: "112", Hours: "24/7", Country: "IN", Crisis: true},
+	{ID: "tele-manas", Name: "Tele-MANAS", Kind: models.ResourceKindHelpline, Description: "Government of India mental health helpline", Phone: "14416", Hours: "24/7", Languages: []string{"en", "hi"}, Country: "IN", Crisis: true},
+	{ID: "vandrevala", Name: "Vandrevala Foundation", Kind: models.ResourceKindNGO, Description: "Free counselling by phone and WhatsApp", Phone: "+91 9999666555", Hours: "24/7", Languages: []string{"en", "hi"}, Country: "IN", Crisis: true},
+	{ID: "aasra", Name: "AASRA", Kind: models.ResourceKindNGO, Description: "Suicide prevention helpline", Phone: "+91 9820466726", Hours: "24/7", Languages: []string{"en", "hi"}, Country: "IN", Crisis: true},
+	{ID: "icall", Name: "iCall (TISS)", Kind: models.ResourceKindHelpline, Description: "Psychosocial counselling by trained professionals", Phone: "+91 9152987821", Hours: "Mon-Sat 10:00-20:00", Languages: []string{"en", "hi"}, Country: "IN"},
+	{ID: "sumaitri", Name: "Sumaitri", Kind: models.ResourceKindNGO, Description: "Emotional support for people in distress", Phone: "011-23389090", Country: "IN", Region: "IN-DL", Crisis: true},
+	{ID: "sneha", Name: "Sneha", Kind: models.ResourceKindNGO, Description: "Suicide prevention helpline", Phone: "044-24640050", Hours: "24/7", Languages: []string{"en", "ta"}, Country: "IN", Region: "IN-TN", Crisis: true},
+	{ID: "sahai", Name: "Sahai", Kind: models.ResourceKindNGO, Description: "Emotional support helpline", Phone: "080-25497777", Languages: []string{"en", "kn"}, Country: "IN", Region: "IN-KA", Crisis: true},
+	{ID: "roshni", Name: "Roshni", Kind: models.ResourceKindNGO, Description: "Emotional support for the depressed and suicidal", Phone: "040-66202000", Languages: []string{"en", "te"}, Country: "IN", Region: "IN-TG", Crisis: true},
+	{ID: "iitb-student-wellness", Name: "IIT Bombay Student Wellness Centre", Kind: models.ResourceKindCounselingCenter, Description: "Counselling for enrolled students", Country: "IN", Region: "IN-MH", Location: &models.HotspotLocation{Latitude: 19.1334, Longitude: 72.9133}, RadiusKm: 5},
+	{ID: "988-lifeline", Name: "988 Suicide & Crisis Lifeline", Kind: models.ResourceKindHelpline, Phone: "988", Hours: "24/7", Languages: []string{"en", "es"}, Country: "US", Crisis: true},
+	{ID: "samaritans", Name: "Samaritans", Kind: models.ResourceKindNGO, Phone: "116 123", Hours: "24/7", Languages: []string{"en"}, Country: "GB", Crisis: true},
+	{ID: "find-a-helpline", Name: "Find A Helpline", Kind: models.ResourceKindNGO, Description: "Directory of free, confidential helplines worldwide", URL: "https://findahelpline.com", Hours: "24/7", Crisis: true},
+}
+
+var (
+	mockResourcesMu sync.RWMutex
+	mockResources   = seedMockResources()
+)
+
+func seedMockResources() map[string]*models.WellbeingResource {
+	now := clock.Now()
+	resources := make(map[string]*models.WellbeingResource, len(defaultResources))
+	for i, r := range defaultResources {
+		resource := r
+		resource.SortOrder = i
+		resource.IsActive = true
+		resource.CreatedAt = now
+		resource.UpdatedAt = now
+		resources[resource.ID] = &resource
+	}
+	return resources
+}
@@ -0,0 +1,87 @@
+// Data retention policy: how long personal data is kept, configured per deployment
+package services
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"unalone-backend/internal/models"
+)
+
+const (
+	// defaultAISessionRetentionDays is how long an AI session is kept after its last message
+	defaultAISessionRetentionDays = 30
+	// defaultLocationRetentionDays is how long a last known location is kept after the app last reported it
+	defaultLocationRetentionDays = 30
+)
+
+// RetentionPolicy holds the configurable retention periods. Cleanup jobs enforce them and
+// GET /privacy/policy publishes them.
+type RetentionPolicy struct {
+	AISessions time.Duration // AI_SESSION_RETENTION_DAYS, from a session's last activity
+	Locations  time.Duration // LOCATION_RETENTION_DAYS, from the last location report
+	Chats      time.Duration // CHAT_RETENTION_DAYS, from the end of the hotspot
+}
+
+// NewRetentionPolicy reads the retention periods from the environment
+func NewRetentionPolicy() *RetentionPolicy {
+	return &RetentionPolicy{
+		AISessions: retentionDays("AI_SESSION_RETENTION_DAYS", defaultAISessionRetentionDays),
+		Locations:  retentionDays("LOCATION_RETENTION_DAYS", defaultLocationRetentionDays),
+		Chats:      retentionDays("CHAT_RETENTION_DAYS", defaultChatRetentionDays),
+	}
+}
+
+// retentionDays reads a whole number of days of at least 1 from key
+func retentionDays(key string, fallback int) time.Duration {
+	days := fallback
+	if n, err := strconv.Atoi(strings.TrimSpace(os.Getenv(key))); err == nil && n >= 1 {
+		days = n
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// Describe lists every retention rule, the configurable ones and those fixed in code
+func (rp *RetentionPolicy) Describe() *models.PrivacyPolicy {
+	days := func(d time.Duration) int { return int(d / (24 * time.Hour)) }
+	return &models.PrivacyPolicy{Retention: []models.RetentionRule{
+		{
+			Data:        models.RetentionAISessions,
+			Days:        days(rp.AISessions),
+			After:       "last_activity",
+			Description: "AI assistant sessions, their messages and hotspot drafts are deleted after this long without a new message. You can delete a session yourself at any time.",
+		},
+		{
+			Data:        models.RetentionAIInsights,
+			Days:        models.MaxAIInsightDays,
+			After:       "scored",
+			Description: "Mood insights hold counts, never message text, and are only collected while you opt in. Opting out deletes them at once.",
+		},
+		{
+			Data:        models.RetentionLocation,
+			Days:        days(rp.Locations),
+			After:       "last_report",
+			Description: "Your last known location is forgotten after this long without a new report, or at once when you turn location sharing off or clear it.",
+		},
+		{
+			Data:        models.RetentionHotspotChats,
+			Days:        days(rp.Chats),
+			After:       "hotspot_end",
+			Description: "Hotspot group chats, with their voice notes, are deleted this long after the hotspot ends.",
+		},
+		{
+			Data:        models.RetentionEphemeralChats,
+			Hours:       int(EphemeralChatTTL / time.Hour),
+			After:       "sent",
+			Description: "Messages in hotspots with ephemeral chat disappear after this long.",
+		},
+		{
+			Data:        models.RetentionPresence,
+			Days:        days(presenceRetention),
+			After:       "last_seen",
+			Description: "Last-seen times are dropped after this long without activity.",
+		},
+	}}
+}
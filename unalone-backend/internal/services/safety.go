@@ -1,24 +1,77 @@
-// Safety service for handling user safety features like blocking and reporting
+// Safety service for handling user safety features like blocking, reporting, trusted contacts and safety timers
 package services
 
 import (
 	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"unalone-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+const (
+	maxTrustedContacts = 5
+	// sosGracePeriod keeps SOS available for a while after a hotspot ends, while people head home
+	sosGracePeriod = time.Hour
 )
 
+// SafetyAlertSender delivers safety alerts to trusted contacts who are not on the app
+type SafetyAlertSender interface {
+	SendSafetyAlert(contact *models.TrustedContact, message string) error
+}
+
+// logSafetyAlertSender writes alerts to the server log until an SMS or email provider is configured
+type logSafetyAlertSender struct{}
+
+func (logSafetyAlertSender) SendSafetyAlert(contact *models.TrustedContact, message string) error {
+	// Contact details and the alert itself are personal data, so only the contact ID is logged
+	log.Printf("[safety] alert contact=%s (%d characters)", contact.ID, len(message))
+	return nil
+}
+
 // SafetyService handles safety-related operations
 type SafetyService struct {
 	firestoreService *FirestoreService
 	userService      *UserService
+	friendsService   *FriendsService
+	hotspotService   *HotspotService
+	notifications    *NotificationService
+	alerts           SafetyAlertSender
+	moderatorEmails  []string
 }
 
-// NewSafetyService creates a new safety service
-func NewSafetyService(fs *FirestoreService, us *UserService) *SafetyService {
+// NewSafetyService creates a new safety service. Platform moderators are the
+// accounts listed in ADMIN_EMAILS (comma-separated).
+func NewSafetyService(fs *FirestoreService, us *UserService, frs *FriendsService, hs *HotspotService, ns *NotificationService) *SafetyService {
+	var moderators []string
+	for _, email := range strings.Split(os.Getenv("ADMIN_EMAILS"), ",") {
+		if email = strings.ToLower(strings.TrimSpace(email)); email != "" {
+			moderators = append(moderators, email)
+		}
+	}
 	return &SafetyService{
 		firestoreService: fs,
 		userService:      us,
+		friendsService:   frs,
+		hotspotService:   hs,
+		notifications:    ns,
+		alerts:           logSafetyAlertSender{},
+		moderatorEmails:  moderators,
 	}
 }
 
+// SetAlertSender replaces how alerts reach trusted contacts who are not on the app
+func (ss *SafetyService) SetAlertSender(sender SafetyAlertSender) {
+	ss.alerts = sender
+}
+
 // BlockUser blocks a user for the current user
 func (ss *SafetyService) BlockUser(userID, targetUserID string) error {
 	if userID == targetUserID {
@@ -65,3 +118,396 @@ func (ss *SafetyService) IsUserBlocked(userID, targetUserID string) (bool, error
 	// For now, we'll return false
 	return false, nil
 }
+
+// ListTrustedContacts returns a user's trusted contacts in the order they were added
+func (ss *SafetyService) ListTrustedContacts(userID string) ([]*models.TrustedContact, error) {
+	if ss.isTestMode() {
+		mockSafetyMu.Lock()
+		defer mockSafetyMu.Unlock()
+		contacts := make([]*models.TrustedContact, len(mockTrustedContacts[userID]))
+		copy(contacts, mockTrustedContacts[userID])
+		return contacts, nil
+	}
+
+	// TODO: Query Firestore users/{userID}/trusted_contacts ordered by created_at
+	return nil, errors.New("firestore implementation needed")
+}
+
+// AddTrustedContact nominates a trusted contact. Contacts on the app must be friends.
+func (ss *SafetyService) AddTrustedContact(userID string, req *models.AddTrustedContactRequest) (*models.TrustedContact, error) {
+	contact := &models.TrustedContact{
+		ID:        uuid.New().String(),
+		Name:      strings.TrimSpace(req.Name),
+		UserID:    req.UserID,
+		Email:     strings.TrimSpace(req.Email),
+		CreatedAt: clock.Now(),
+	}
+	if strings.TrimSpace(req.PhoneNumber) != "" {
+		phoneNumber, err := NormalizePhoneNumber(req.PhoneNumber)
+		if err != nil {
+			return nil, err
+		}
+		contact.PhoneNumber = phoneNumber
+	}
+	if contact.UserID == "" && contact.PhoneNumber == "" && contact.Email == "" {
+		return nil, errors.New("a trusted contact needs a user, phone number or email")
+	}
+	if contact.UserID != "" {
+		statuses, err := ss.friendsService.GetFriendStatuses(userID, []string{contact.UserID})
+		if err != nil {
+			return nil, err
+		}
+		if statuses[contact.UserID] != models.FriendStatusFriend {
+			return nil, errors.New("trusted contacts on the app must be your friends")
+		}
+	}
+
+	if ss.isTestMode() {
+		return contact, ss.addTrustedContactMock(userID, contact)
+	}
+
+	// TODO: Write Firestore document users/{userID}/trusted_contacts/{contactID}
+	return nil, errors.New("firestore implementation needed")
+}
+
+// RemoveTrustedContact deletes one of the user's trusted contacts
+func (ss *SafetyService) RemoveTrustedContact(userID, contactID string) error {
+	if ss.isTestMode() {
+		mockSafetyMu.Lock()
+		defer mockSafetyMu.Unlock()
+		contacts := mockTrustedContacts[userID]
+		for i, contact := range contacts {
+			if contact.ID == contactID {
+				mockTrustedContacts[userID] = append(contacts[:i:i], contacts[i+1:]...)
+				return nil
+			}
+		}
+		return errors.New("trusted contact not found")
+	}
+
+	// TODO: Delete Firestore document users/{userID}/trusted_contacts/{contactID}
+	return errors.New("firestore implementation needed")
+}
+
+// CanStartTimer reports why a user cannot start a safety timer, or nil if they can
+func (ss *SafetyService) CanStartTimer(userID string) error {
+	contacts, err := ss.ListTrustedContacts(userID)
+	if err != nil {
+		return err
+	}
+	if len(contacts) == 0 {
+		return errors.New("add a trusted contact before starting a safety timer")
+	}
+	return nil
+}
+
+// StartTimer starts a safety timer for a hotspot the user checked into, replacing
+// any timer they already had running for it
+func (ss *SafetyService) StartTimer(userID string, hotspot *models.Hotspot, duration time.Duration) (*models.SafetyTimer, error) {
+	if err := ss.CanStartTimer(userID); err != nil {
+		return nil, err
+	}
+
+	now := clock.Now()
+	timer := &models.SafetyTimer{
+		ID:          uuid.New().String(),
+		UserID:      userID,
+		HotspotID:   hotspot.ID,
+		HotspotName: hotspot.Name,
+		Address:     formatAddress(hotspot.Address),
+		Location:    hotspot.Location,
+		Deadline:    now.Add(duration),
+		Status:      models.SafetyTimerActive,
+		CreatedAt:   now,
+	}
+
+	if ss.isTestMode() {
+		ss.startTimerMock(timer)
+		return timer, nil
+	}
+
+	// TODO: Write Firestore document safety_timers/{timerID} and cancel earlier active timers
+	return nil, errors.New("firestore implementation needed")
+}
+
+// ListTimers returns the user's active and alerted safety timers, soonest deadline first
+func (ss *SafetyService) ListTimers(userID string) ([]*models.SafetyTimer, error) {
+	if !ss.isTestMode() {
+		// TODO: Query Firestore safety_timers where user_id == userID and status in (active, alerted)
+		return nil, errors.New("firestore implementation needed")
+	}
+
+	mockSafetyMu.Lock()
+	defer mockSafetyMu.Unlock()
+	timers := []*models.SafetyTimer{}
+	for _, timer := range mockSafetyTimers {
+		if timer.UserID == userID && timer.Status != models.SafetyTimerConfirmed {
+			copied := *timer
+			timers = append(timers, &copied)
+		}
+	}
+	sort.Slice(timers, func(i, j int) bool {
+		return timers[i].Deadline.Before(timers[j].Deadline)
+	})
+	return timers, nil
+}
+
+// ConfirmSafe stops a safety timer. If contacts were already alerted they are told the user is safe.
+func (ss *SafetyService) ConfirmSafe(userID, timerID string) (*models.SafetyTimer, error) {
+	if !ss.isTestMode() {
+		// TODO: Update Firestore safety_timers/{timerID} after checking user_id
+		return nil, errors.New("firestore implementation needed")
+	}
+
+	timer, wasAlerted, err := ss.confirmTimerMock(userID, timerID, clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	if wasAlerted {
+		ss.alertContacts(timer, false)
+	}
+	return timer, nil
+}
+
+// RaiseSOS records an emergency alert from an attendee of a hotspot that is happening now,
+// alerts their trusted contacts and the platform moderators, and flags the hotspot for review
+func (ss *SafetyService) RaiseSOS(userID string, req *models.SOSRequest) (*models.SOSAlert, error) {
+	hotspot, err := ss.hotspotService.GetHotspot(req.HotspotID)
+	if err != nil {
+		return nil, err
+	}
+	if !containsString(hotspot.Attendees, userID) {
+		return nil, errors.New("user is not in this hotspot")
+	}
+	now := clock.Now()
+	if hotspot.ScheduledTime != nil && now.Before(hotspot.ScheduledTime.Add(-checkInEarlyStart)) {
+		return nil, errors.New("hotspot has not started yet")
+	}
+	if end := hotspotEndsAt(hotspot); end != nil && now.After(end.Add(sosGracePeriod)) {
+		return nil, errors.New("hotspot has already ended")
+	}
+	user, err := ss.userService.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	alert := &models.SOSAlert{
+		ID:          uuid.New().String(),
+		UserID:      userID,
+		HotspotID:   hotspot.ID,
+		HotspotName: hotspot.Name,
+		HostID:      hotspot.CreatedBy,
+		Address:     formatAddress(hotspot.Address),
+		Location:    models.HotspotLocation{Latitude: req.Latitude, Longitude: req.Longitude},
+		Message:     strings.TrimSpace(req.Message),
+		CreatedAt:   now,
+	}
+
+	// Alert people first; storing the record and flagging the hotspot must not delay them
+	message := fmt.Sprintf("%s raised an SOS at %s (%s). Their location: %.5f,%.5f.",
+		user.Nickname, alert.HotspotName, alert.Address, alert.Location.Latitude, alert.Location.Longitude)
+	if alert.Message != "" {
+		message += " Message: " + alert.Message
+	}
+	alert.ContactsAlerted = ss.sendSOS(user, alert, message)
+
+	if ss.isTestMode() {
+		mockSafetyMu.Lock()
+		mockSOSAlerts = append(mockSOSAlerts, alert)
+		mockSafetyMu.Unlock()
+	} else {
+		// TODO: Write Firestore document sos_alerts/{alertID}
+		log.Printf("SOS %s from user %s not stored: firestore implementation needed", alert.ID, userID)
+	}
+	if err := ss.hotspotService.FlagForReview(hotspot.ID); err != nil {
+		log.Printf("Failed to flag hotspot %s after SOS %s: %v", hotspot.ID, alert.ID, err)
+	}
+	log.Printf("SOS %s raised by user %s in hotspot %s", alert.ID, userID, hotspot.ID)
+	return alert, nil
+}
+
+// ListSOSAlerts returns SOS alerts for moderators, newest first
+func (ss *SafetyService) ListSOSAlerts(limit, offset int) ([]*models.SOSAlert, error) {
+	if !ss.isTestMode() {
+		// TODO: Query Firestore sos_alerts ordered by created_at desc
+		return nil, errors.New("firestore implementation needed")
+	}
+
+	mockSafetyMu.Lock()
+	defer mockSafetyMu.Unlock()
+	alerts := make([]*models.SOSAlert, 0, len(mockSOSAlerts))
+	for i := len(mockSOSAlerts) - 1; i >= 0; i-- {
+		alerts = append(alerts, mockSOSAlerts[i])
+	}
+	if offset >= len(alerts) {
+		return []*models.SOSAlert{}, nil
+	}
+	end := offset + limit
+	if end > len(alerts) {
+		end = len(alerts)
+	}
+	return alerts[offset:end], nil
+}
+
+// sendSOS delivers an SOS to the user's trusted contacts and to moderators, returning how many contacts were reached
+func (ss *SafetyService) sendSOS(user *models.User, alert *models.SOSAlert, message string) int {
+	contacts, err := ss.ListTrustedContacts(user.ID)
+	if err != nil {
+		log.Printf("SOS %s could not load trusted contacts: %v", alert.ID, err)
+	}
+	reached := 0
+	for _, contact := range contacts {
+		if contact.UserID != "" {
+			if ss.notifications != nil && ss.notifications.NotifySOS(contact.UserID, user, alert, message) == nil {
+				reached++
+			}
+			continue
+		}
+		if err := ss.alerts.SendSafetyAlert(contact, message); err != nil {
+			log.Printf("SOS alert to contact %s failed: %v", contact.ID, err)
+			continue
+		}
+		reached++
+	}
+
+	if ss.notifications != nil {
+		for _, email := range ss.moderatorEmails {
+			moderator, err := ss.userService.GetUserByEmail(email)
+			if err != nil {
+				continue
+			}
+			_ = ss.notifications.NotifySOS(moderator.ID, user, alert, message)
+		}
+	}
+	return reached
+}
+
+// AlertOverdue marks every active timer past its deadline as alerted and notifies the contacts
+func (ss *SafetyService) AlertOverdue(now time.Time) {
+	if !ss.isTestMode() {
+		// TODO: Query Firestore safety_timers where status == active and deadline <= now
+		return
+	}
+
+	mockSafetyMu.Lock()
+	var overdue []*models.SafetyTimer
+	for _, timer := range mockSafetyTimers {
+		if timer.Status == models.SafetyTimerActive && !timer.Deadline.After(now) {
+			alertedAt := now
+			timer.Status = models.SafetyTimerAlerted
+			timer.AlertedAt = &alertedAt
+			copied := *timer
+			overdue = append(overdue, &copied)
+		}
+	}
+	mockSafetyMu.Unlock()
+
+	for _, timer := range overdue {
+		ss.alertContacts(timer, true)
+	}
+}
+
+// alertContacts tells each of the user's trusted contacts that a timer ran out (missed)
+// or that the user has since confirmed they are safe
+func (ss *SafetyService) alertContacts(timer *models.SafetyTimer, missed bool) {
+	user, err := ss.userService.GetUserByID(timer.UserID)
+	if err != nil {
+		log.Printf("Safety alert for timer %s skipped: %v", timer.ID, err)
+		return
+	}
+	contacts, err := ss.ListTrustedContacts(timer.UserID)
+	if err != nil {
+		log.Printf("Safety alert for timer %s skipped: %v", timer.ID, err)
+		return
+	}
+
+	message := fmt.Sprintf("%s has confirmed they are safe after %s.", user.Nickname, timer.HotspotName)
+	if missed {
+		message = fmt.Sprintf("%s checked into %s (%s, map: %.5f,%.5f) on Unalone and did not confirm they were safe by %s UTC. Please check on them.",
+			user.Nickname, timer.HotspotName, timer.Address, timer.Location.Latitude, timer.Location.Longitude,
+			timer.Deadline.UTC().Format("15:04 Jan 2"))
+	}
+	for _, contact := range contacts {
+		if contact.UserID != "" {
+			if ss.notifications != nil {
+				_ = ss.notifications.NotifySafetyAlert(contact.UserID, user, timer, message, missed)
+			}
+			continue
+		}
+		if err := ss.alerts.SendSafetyAlert(contact, message); err != nil {
+			log.Printf("Safety alert to contact %s failed: %v", contact.ID, err)
+		}
+	}
+}
+
+// formatAddress joins the non-empty parts of an address for alert messages
+func formatAddress(address models.HotspotAddress) string {
+	parts := make([]string, 0, 5)
+	for _, part := range []string{address.Street, address.City, address.Region, address.PostalCode, address.Country} {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// isTestMode checks if we're running with mocked database
+func (ss *SafetyService) isTestMode() bool {
+	return ss.firestoreService.client == nil
+}
+
+// Mock storage for testing
+var (
+	mockSafetyMu        sync.Mutex
+	mockTrustedContacts = make(map[string][]*models.TrustedContact) // userID -> contacts
+	mockSafetyTimers    = make(map[string]*models.SafetyTimer)      // timerID -> timer
+	mockSOSAlerts       []*models.SOSAlert
+)
+
+func (ss *SafetyService) addTrustedContactMock(userID string, contact *models.TrustedContact) error {
+	mockSafetyMu.Lock()
+	defer mockSafetyMu.Unlock()
+	if len(mockTrustedContacts[userID]) >= maxTrustedContacts {
+		return fmt.Errorf("you can have at most %d trusted contacts", maxTrustedContacts)
+	}
+	for _, existing := range mockTrustedContacts[userID] {
+		if (contact.UserID != "" && existing.UserID == contact.UserID) ||
+			(contact.PhoneNumber != "" && existing.PhoneNumber == contact.PhoneNumber) {
+			return errors.New("this person is already a trusted contact")
+		}
+	}
+	mockTrustedContacts[userID] = append(mockTrustedContacts[userID], contact)
+	return nil
+}
+
+// startTimerMock stores a timer, confirming any active timer the user had for the same hotspot
+func (ss *SafetyService) startTimerMock(timer *models.SafetyTimer) {
+	mockSafetyMu.Lock()
+	defer mockSafetyMu.Unlock()
+	for _, existing := range mockSafetyTimers {
+		if existing.UserID == timer.UserID && existing.HotspotID == timer.HotspotID && existing.Status == models.SafetyTimerActive {
+			confirmedAt := timer.CreatedAt
+			existing.Status = models.SafetyTimerConfirmed
+			existing.ConfirmedAt = &confirmedAt
+		}
+	}
+	mockSafetyTimers[timer.ID] = timer
+}
+
+// confirmTimerMock confirms a timer and reports whether its contacts had already been alerted
+func (ss *SafetyService) confirmTimerMock(userID, timerID string, now time.Time) (*models.SafetyTimer, bool, error) {
+	mockSafetyMu.Lock()
+	defer mockSafetyMu.Unlock()
+	timer, ok := mockSafetyTimers[timerID]
+	if !ok || timer.UserID != userID {
+		return nil, false, errors.New("safety timer not found")
+	}
+	if timer.Status == models.SafetyTimerConfirmed {
+		return nil, false, errors.New("safety timer already confirmed")
+	}
+	wasAlerted := timer.Status == models.SafetyTimerAlerted
+	timer.Status = models.SafetyTimerConfirmed
+	timer.ConfirmedAt = &now
+	copied := *timer
+	return &copied, wasAlerted, nil
+}
@@ -0,0 +1,189 @@
+// Scheduler running periodic maintenance jobs on a single leader instance
+package services
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"unalone-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+const (
+	schedulerLeaderKey = "scheduler:leader"
+	// schedulerLeaseTTL is how long a crashed leader blocks the other replicas
+	schedulerLeaseTTL   = 30 * time.Second
+	schedulerLeaseRenew = 10 * time.Second
+	// schedulerJitter spreads each run by up to this fraction of the job interval
+	schedulerJitter = 0.1
+)
+
+// scheduledJob is a registered maintenance job and its metrics
+type scheduledJob struct {
+	name     string
+	interval time.Duration
+	run      func(ctx context.Context) error
+	local    bool // Runs on every instance, not just the leader
+	stats    models.ScheduledJobStats
+}
+
+// Scheduler runs registered jobs at a jittered interval. When several replicas share
+// Redis, only the one holding the leader lease runs jobs; without Redis every instance
+// considers itself the leader.
+type Scheduler struct {
+	redisService *RedisService
+	instanceID   string
+
+	mu     sync.Mutex
+	jobs   []*scheduledJob
+	leader bool
+}
+
+// NewScheduler creates a new scheduler
+func NewScheduler(rs *RedisService) *Scheduler {
+	return &Scheduler{redisService: rs, instanceID: uuid.New().String()}
+}
+
+// Register adds a job. Jobs must be registered before Start.
+func (s *Scheduler) Register(name string, interval time.Duration, run func(ctx context.Context) error) {
+	s.register(name, interval, run, false)
+}
+
+// RegisterLocal adds a job every instance runs, for state kept in the instance's own memory
+// that the leader cannot reach. Jobs must be registered before Start.
+func (s *Scheduler) RegisterLocal(name string, interval time.Duration, run func(ctx context.Context) error) {
+	s.register(name, interval, run, true)
+}
+
+func (s *Scheduler) register(name string, interval time.Duration, run func(ctx context.Context) error, local bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs = append(s.jobs, &scheduledJob{
+		name:     name,
+		interval: interval,
+		run:      run,
+		local:    local,
+		stats:    models.ScheduledJobStats{Name: name, Interval: interval.String(), Local: local},
+	})
+}
+
+// Start campaigns for leadership and runs every registered job until ctx is cancelled
+func (s *Scheduler) Start(ctx context.Context) {
+	s.campaign()
+	go func() {
+		ticker := time.NewTicker(schedulerLeaseRenew)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				if err := s.redisService.ReleaseLease(schedulerLeaderKey, s.instanceID); err != nil {
+					log.Printf("[scheduler] lease release failed: %v", err)
+				}
+				return
+			case <-ticker.C:
+				s.campaign()
+			}
+		}
+	}()
+
+	s.mu.Lock()
+	jobs := append([]*scheduledJob(nil), s.jobs...)
+	s.mu.Unlock()
+	for _, job := range jobs {
+		go s.loop(ctx, job)
+	}
+}
+
+// Status returns leadership and per-job metrics for this instance
+func (s *Scheduler) Status() models.SchedulerStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status := models.SchedulerStatus{InstanceID: s.instanceID, Leader: s.leader, Jobs: make([]models.ScheduledJobStats, 0, len(s.jobs))}
+	for _, job := range s.jobs {
+		status.Jobs = append(status.Jobs, job.stats)
+	}
+	sort.Slice(status.Jobs, func(i, j int) bool { return status.Jobs[i].Name < status.Jobs[j].Name })
+	return status
+}
+
+// campaign takes or renews the leader lease. On a Redis error the instance steps down
+// rather than risk two leaders.
+func (s *Scheduler) campaign() {
+	leader, err := s.redisService.AcquireLease(schedulerLeaderKey, s.instanceID, schedulerLeaseTTL)
+	if err != nil {
+		log.Printf("[scheduler] leader election failed: %v", err)
+		leader = false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if leader != s.leader {
+		log.Printf("[scheduler] instance %s leader=%t", s.instanceID, leader)
+	}
+	s.leader = leader
+}
+
+// loop runs one job after a short random delay, then once per jittered interval. The first
+// run is not a full interval away so daily jobs still run on instances that restart often.
+func (s *Scheduler) loop(ctx context.Context, job *scheduledJob) {
+	wait := time.Duration(rand.Int63n(int64(float64(job.interval)*schedulerJitter) + 1))
+	for {
+		next := time.Now().Add(wait)
+		s.mu.Lock()
+		job.stats.NextRunAt = &next
+		s.mu.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		s.runOnce(ctx, job)
+		wait = jittered(job.interval)
+	}
+}
+
+// runOnce executes a job if this instance is the leader, or the job is local, and records the outcome
+func (s *Scheduler) runOnce(ctx context.Context, job *scheduledJob) {
+	s.mu.Lock()
+	leader := s.leader || job.local
+	if !leader {
+		job.stats.Skipped++
+	}
+	s.mu.Unlock()
+	if !leader {
+		return
+	}
+
+	started := time.Now()
+	err := job.run(ctx)
+	duration := time.Since(started)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job.stats.Runs++
+	job.stats.LastRunAt = &started
+	job.stats.LastDurationMs = duration.Milliseconds()
+	job.stats.LastError = ""
+	if err != nil {
+		job.stats.Failures++
+		job.stats.LastError = err.Error()
+		log.Printf("[scheduler] job %s failed after %s: %v", job.name, duration, err)
+	}
+}
+
+// jittered returns interval shifted randomly by up to schedulerJitter in either direction
+func jittered(interval time.Duration) time.Duration {
+	spread := int64(float64(interval) * schedulerJitter)
+	if spread <= 0 {
+		return interval
+	}
+	return interval + time.Duration(rand.Int63n(2*spread+1)-spread)
+}
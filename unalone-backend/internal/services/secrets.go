@@ -0,0 +1,213 @@
+// Secrets provider: credentials read from the environment, Google Secret Manager or Vault
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"google.golang.org/api/googleapi"
+	secretmanager "google.golang.org/api/secretmanager/v1"
+)
+
+var ErrSecretNotFound = errors.New("secret not found")
+
+// secretsRequestTimeout bounds one read from a remote secret store
+const secretsRequestTimeout = 10 * time.Second
+
+// SecretsProvider reads credentials by their environment variable name, such as JWT_SECRET
+type SecretsProvider interface {
+	// Name identifies the backend in logs
+	Name() string
+	// Secret returns the current value of a secret, or ErrSecretNotFound
+	Secret(ctx context.Context, name string) (string, error)
+}
+
+// NewSecretsProvider builds the provider selected by SECRETS_PROVIDER: env (default), gcp or
+// vault. Secrets missing from Secret Manager or Vault fall back to the environment, so they
+// can be moved one at a time.
+func NewSecretsProvider(ctx context.Context) (SecretsProvider, error) {
+	switch provider := strings.ToLower(strings.TrimSpace(os.Getenv("SECRETS_PROVIDER"))); provider {
+	case "", "env":
+		return envSecrets{}, nil
+	case "gcp":
+		project := strings.TrimSpace(os.Getenv("SECRETS_GCP_PROJECT"))
+		if project == "" {
+			project = strings.TrimSpace(os.Getenv("GOOGLE_CLOUD_PROJECT"))
+		}
+		if project == "" {
+			return nil, errors.New("SECRETS_GCP_PROJECT or GOOGLE_CLOUD_PROJECT is required for Secret Manager")
+		}
+		svc, err := secretmanager.NewService(ctx, credentialOptions()...)
+		if err != nil {
+			return nil, fmt.Errorf("secret manager client: %w", err)
+		}
+		return withEnvFallback(&gcpSecrets{versions: svc.Projects.Secrets.Versions, project: project}), nil
+	case "vault":
+		addr := strings.TrimRight(strings.TrimSpace(os.Getenv("VAULT_ADDR")), "/")
+		token := strings.TrimSpace(os.Getenv("VAULT_TOKEN"))
+		if addr == "" || token == "" {
+			return nil, errors.New("VAULT_ADDR and VAULT_TOKEN are required for Vault")
+		}
+		path := strings.Trim(strings.TrimSpace(os.Getenv("VAULT_SECRET_PATH")), "/")
+		if path == "" {
+			path = "secret/data/unalone"
+		}
+		return withEnvFallback(&vaultSecrets{
+			addr:       addr,
+			token:      token,
+			namespace:  strings.TrimSpace(os.Getenv("VAULT_NAMESPACE")),
+			path:       path,
+			httpClient: &http.Client{Timeout: secretsRequestTimeout},
+		}), nil
+	default:
+		return nil, fmt.Errorf("unknown SECRETS_PROVIDER %q (use env, gcp or vault)", provider)
+	}
+}
+
+// lookupSecret reads a secret, returning "" when it is not set anywhere. Other errors are
+// logged and also return "", so the caller's own default applies.
+func lookupSecret(ctx context.Context, sp SecretsProvider, name string) string {
+	if sp == nil {
+		sp = envSecrets{}
+	}
+	ctx, cancel := context.WithTimeout(ctx, secretsRequestTimeout)
+	defer cancel()
+	value, err := sp.Secret(ctx, name)
+	if err != nil {
+		if !errors.Is(err, ErrSecretNotFound) {
+			log.Printf("Secrets: reading %s from %s failed: %v", name, sp.Name(), err)
+		}
+		return ""
+	}
+	return value
+}
+
+// signingSecret returns the first of names that is set. Only with the env provider, used for
+// local development, does it fall back to devDefault; with Secret Manager or Vault a missing
+// secret is an error, so nothing is ever signed with a key published in this repository.
+func signingSecret(ctx context.Context, sp SecretsProvider, devDefault string, names ...string) (string, error) {
+	for _, name := range names {
+		if value := lookupSecret(ctx, sp, name); value != "" {
+			return value, nil
+		}
+	}
+	if _, isEnv := sp.(envSecrets); sp == nil || isEnv {
+		return devDefault, nil
+	}
+	return "", fmt.Errorf("%s must be set in %s or the environment", strings.Join(names, " or "), sp.Name())
+}
+
+// envSecrets reads secrets from environment variables
+type envSecrets struct{}
+
+func (envSecrets) Name() string { return "env" }
+
+func (envSecrets) Secret(ctx context.Context, name string) (string, error) {
+	if value := strings.TrimSpace(os.Getenv(name)); value != "" {
+		return value, nil
+	}
+	return "", ErrSecretNotFound
+}
+
+// fallbackSecrets reads from a store and then from the environment
+type fallbackSecrets struct {
+	store SecretsProvider
+}
+
+func withEnvFallback(store SecretsProvider) SecretsProvider {
+	return fallbackSecrets{store: store}
+}
+
+func (fs fallbackSecrets) Name() string { return fs.store.Name() }
+
+func (fs fallbackSecrets) Secret(ctx context.Context, name string) (string, error) {
+	value, err := fs.store.Secret(ctx, name)
+	if errors.Is(err, ErrSecretNotFound) {
+		return envSecrets{}.Secret(ctx, name)
+	}
+	return value, err
+}
+
+// gcpSecrets reads the latest version of a Secret Manager secret with the same ID as the
+// variable, optionally prefixed by SECRETS_PREFIX
+type gcpSecrets struct {
+	versions *secretmanager.ProjectsSecretsVersionsService
+	project  string
+}
+
+func (gs *gcpSecrets) Name() string { return "gcp" }
+
+func (gs *gcpSecrets) Secret(ctx context.Context, name string) (string, error) {
+	id := strings.TrimSpace(os.Getenv("SECRETS_PREFIX")) + name
+	resp, err := gs.versions.Access("projects/" + gs.project + "/secrets/" + id + "/versions/latest").Context(ctx).Do()
+	if err != nil {
+		var apiErr *googleapi.Error
+		if errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound {
+			return "", ErrSecretNotFound
+		}
+		return "", err
+	}
+	if resp.Payload == nil {
+		return "", ErrSecretNotFound
+	}
+	data, err := base64.StdEncoding.DecodeString(resp.Payload.Data)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// vaultSecrets reads keys of one KV version 2 secret at VAULT_SECRET_PATH
+type vaultSecrets struct {
+	addr       string
+	token      string
+	namespace  string
+	path       string
+	httpClient *http.Client
+}
+
+func (vs *vaultSecrets) Name() string { return "vault" }
+
+func (vs *vaultSecrets) Secret(ctx context.Context, name string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, vs.addr+"/v1/"+(&url.URL{Path: vs.path}).EscapedPath(), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", vs.token)
+	if vs.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", vs.namespace)
+	}
+	resp, err := vs.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return "", ErrSecretNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault returned %d", resp.StatusCode)
+	}
+	var body struct {
+		Data struct {
+			Data map[string]interface{} `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", err
+	}
+	value, ok := body.Data.Data[name].(string)
+	if !ok || strings.TrimSpace(value) == "" {
+		return "", ErrSecretNotFound
+	}
+	return strings.TrimSpace(value), nil
+}
@@ -0,0 +1,270 @@
+// Share links: short codes that deep link to hotspots and profiles
+package services
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"unalone-backend/internal/models"
+)
+
+const (
+	shareCodeLength   = 8
+	shareCodeAlphabet = "23456789abcdefghjkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ" // No 0/O, 1/l/I
+	defaultShareBase  = "https://unalone.app/s/"
+	defaultShareDeep  = "unalone://share/"
+)
+
+var (
+	ErrShareLinkNotFound = errors.New("share link not found")
+	ErrShareLinkExpired  = errors.New("share link has expired")
+	ErrShareNotAllowed   = errors.New("only the host or a co-host can share a hotspot limited to a friend list")
+)
+
+// ShareLinkService creates and resolves share links. Links made by a host or co-host for a
+// friend-list hotspot let whoever opens them join; other links only point at the target.
+type ShareLinkService struct {
+	firestoreService   *FirestoreService
+	hotspotService     *HotspotService
+	userService        *UserService
+	profileViewService *ProfileViewService
+
+	baseURL  string
+	deepLink string
+}
+
+// NewShareLinkService creates a new share link service. SHARE_BASE_URL and SHARE_DEEP_LINK_PREFIX
+// override the web and app URL prefixes the code is appended to.
+func NewShareLinkService(fs *FirestoreService, hs *HotspotService, us *UserService, pvs *ProfileViewService) *ShareLinkService {
+	return &ShareLinkService{
+		firestoreService:   fs,
+		hotspotService:     hs,
+		userService:        us,
+		profileViewService: pvs,
+		baseURL:            envPrefix("SHARE_BASE_URL", defaultShareBase),
+		deepLink:           envPrefix("SHARE_DEEP_LINK_PREFIX", defaultShareDeep),
+	}
+}
+
+// envPrefix reads a URL prefix, making sure the code can be appended to it
+func envPrefix(key, fallback string) string {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return fallback
+	}
+	if !strings.HasSuffix(value, "/") {
+		value += "/"
+	}
+	return value
+}
+
+// ShareHotspot creates a link to a hotspot the user can see. Links to friend-list hotspots
+// can only be made by the host or a co-host and let the people who open them join.
+func (sls *ShareLinkService) ShareHotspot(userID, hotspotID string, req *models.CreateShareLinkRequest) (*models.ShareLinkResponse, error) {
+	hotspot, err := sls.hotspotService.GetHotspot(hotspotID)
+	if err != nil {
+		return nil, err
+	}
+	isHost := hotspot.CreatedBy == userID || containsString(hotspot.CoHosts, userID)
+	if hotspot.IsDraft || !CanViewHotspot(hotspot, userID, userOrgID(sls.userService, userID)) || !hotspot.IsActive || hotspot.ArchivedAt != nil {
+		if hotspot.IsDraft && isHost {
+			return nil, errors.New("publish the hotspot before sharing it")
+		}
+		return nil, errors.New("hotspot not found")
+	}
+	grantsAccess := hotspot.FriendListID != ""
+	if grantsAccess && !isHost {
+		return nil, ErrShareNotAllowed
+	}
+
+	expiresAt := hotspotEndsAt(hotspot)
+	if req.ExpiresInHours > 0 {
+		if requested := clock.Now().Add(time.Duration(req.ExpiresInHours) * time.Hour); expiresAt == nil || requested.Before(*expiresAt) {
+			expiresAt = &requested
+		}
+	}
+	return sls.create(userID, models.ShareKindHotspot, hotspotID, grantsAccess, expiresAt, req.IncludeQR)
+}
+
+// ShareProfile creates a link to the user's own profile. Opening it still honors the
+// profile's visibility settings.
+func (sls *ShareLinkService) ShareProfile(userID string, req *models.CreateShareLinkRequest) (*models.ShareLinkResponse, error) {
+	var expiresAt *time.Time
+	if req.ExpiresInHours > 0 {
+		at := clock.Now().Add(time.Duration(req.ExpiresInHours) * time.Hour)
+		expiresAt = &at
+	}
+	return sls.create(userID, models.ShareKindProfile, userID, false, expiresAt, req.IncludeQR)
+}
+
+// Resolve returns what a share link points at, as the viewer may see it, and whether they can join
+func (sls *ShareLinkService) Resolve(viewerID, code string) (*models.ShareLinkPreview, error) {
+	link, err := sls.activeLink(code)
+	if err != nil {
+		return nil, err
+	}
+	preview := &models.ShareLinkPreview{Code: link.Code, Kind: link.Kind}
+
+	if link.Kind == models.ShareKindProfile {
+		profile, err := sls.profileViewService.GetProfileView(viewerID, link.TargetID)
+		if err != nil {
+			return nil, ErrShareLinkNotFound
+		}
+		preview.Profile = profile
+		return preview, nil
+	}
+
+	hotspot, err := sls.hotspotService.GetHotspot(link.TargetID)
+	if err != nil {
+		return nil, ErrShareLinkNotFound
+	}
+	hasAccess := sls.grantsAccess(link, hotspot)
+	orgID := userOrgID(sls.userService, viewerID)
+	if hotspot.OrgID != orgID || (!hasAccess && !CanViewHotspot(hotspot, viewerID, orgID)) {
+		return nil, ErrShareLinkNotFound
+	}
+	view := PublicHotspotOf(hotspot, nil)
+	preview.Hotspot = &view
+	if hotspot.External == nil {
+		preview.Host = &models.PublicUser{ID: hotspot.CreatedBy, Nickname: hotspot.CreatedByNickname}
+	}
+	preview.Joined = containsString(hotspot.Attendees, viewerID)
+	if err := sls.hotspotService.CheckJoin(hotspot, viewerID, hasAccess); err != nil {
+		preview.Reason = err.Error()
+	} else {
+		preview.CanJoin = true
+	}
+	return preview, nil
+}
+
+// JoinHotspot joins the hotspot a share link points at, using the access the link grants
+func (sls *ShareLinkService) JoinHotspot(userID, hotspotID, code string) (*models.Hotspot, error) {
+	link, err := sls.activeLink(code)
+	if err != nil {
+		return nil, err
+	}
+	if link.Kind != models.ShareKindHotspot || link.TargetID != hotspotID {
+		return nil, ErrShareLinkNotFound
+	}
+	hotspot, err := sls.hotspotService.GetHotspot(hotspotID)
+	if err != nil {
+		return nil, err
+	}
+	if sls.grantsAccess(link, hotspot) {
+		return sls.hotspotService.JoinHotspotWithAccess(userID, hotspotID)
+	}
+	return sls.hotspotService.JoinHotspot(userID, hotspotID)
+}
+
+// Revoke disables a link; only its creator can revoke it
+func (sls *ShareLinkService) Revoke(userID, code string) error {
+	if !sls.isTestMode() {
+		// TODO: Implement Firestore update of share_links/{code}.revoked_at
+		return errors.New("firestore implementation needed")
+	}
+	mockShareLinksMu.Lock()
+	defer mockShareLinksMu.Unlock()
+	link, ok := mockShareLinks[code]
+	if !ok || link.CreatedBy != userID || link.RevokedAt != nil {
+		return ErrShareLinkNotFound
+	}
+	now := clock.Now()
+	link.RevokedAt = &now
+	return nil
+}
+
+// grantsAccess reports whether a link still opens a friend-list hotspot: its creator must
+// still host or co-host it, so demoted co-hosts' links stop working
+func (sls *ShareLinkService) grantsAccess(link *models.ShareLink, hotspot *models.Hotspot) bool {
+	return link.GrantsAccess && (hotspot.CreatedBy == link.CreatedBy || containsString(hotspot.CoHosts, link.CreatedBy))
+}
+
+func (sls *ShareLinkService) create(userID, kind, targetID string, grantsAccess bool, expiresAt *time.Time, includeQR bool) (*models.ShareLinkResponse, error) {
+	link := &models.ShareLink{
+		Kind:         kind,
+		TargetID:     targetID,
+		CreatedBy:    userID,
+		GrantsAccess: grantsAccess,
+		CreatedAt:    clock.Now(),
+		ExpiresAt:    expiresAt,
+	}
+
+	if !sls.isTestMode() {
+		// TODO: Implement Firestore create of share_links/{code}, retrying on an existing code
+		return nil, errors.New("firestore implementation needed")
+	}
+	mockShareLinksMu.Lock()
+	for {
+		code, err := newShareCode()
+		if err != nil {
+			mockShareLinksMu.Unlock()
+			return nil, err
+		}
+		if _, taken := mockShareLinks[code]; !taken {
+			link.Code = code
+			break
+		}
+	}
+	mockShareLinks[link.Code] = link
+	mockShareLinksMu.Unlock()
+
+	response := &models.ShareLinkResponse{
+		Code:      link.Code,
+		URL:       sls.baseURL + link.Code,
+		DeepLink:  sls.deepLink + link.Code,
+		ExpiresAt: link.ExpiresAt,
+	}
+	if includeQR {
+		response.QRPayload = response.URL
+	}
+	return response, nil
+}
+
+// activeLink loads a link that has not been revoked or expired
+func (sls *ShareLinkService) activeLink(code string) (*models.ShareLink, error) {
+	if !sls.isTestMode() {
+		// TODO: Implement Firestore read of share_links/{code}
+		return nil, errors.New("firestore implementation needed")
+	}
+	mockShareLinksMu.Lock()
+	defer mockShareLinksMu.Unlock()
+	link, ok := mockShareLinks[code]
+	if !ok || link.RevokedAt != nil {
+		return nil, ErrShareLinkNotFound
+	}
+	if link.ExpiresAt != nil && !link.ExpiresAt.After(clock.Now()) {
+		return nil, ErrShareLinkExpired
+	}
+	copied := *link
+	return &copied, nil
+}
+
+// newShareCode returns a random code from an alphabet without look-alike characters
+func newShareCode() (string, error) {
+	code := make([]byte, shareCodeLength)
+	alphabetSize := big.NewInt(int64(len(shareCodeAlphabet)))
+	for i := range code {
+		n, err := rand.Int(rand.Reader, alphabetSize)
+		if err != nil {
+			return "", err
+		}
+		code[i] = shareCodeAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
+
+func (sls *ShareLinkService) isTestMode() bool {
+	return sls.firestoreService.client == nil
+}
+
+// === Mock storage in-memory for development/test ===
+
+var (
+	mockShareLinksMu sync.Mutex
+	mockShareLinks   = make(map[string]*models.ShareLink) // code -> link
+)
@@ -0,0 +1,498 @@
+// SMS gateway with pluggable providers, per-number rate limits and delivery tracking
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"unalone-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// SMS gateway errors surfaced to handlers
+var (
+	ErrSMSRateLimited          = errors.New("too many messages to this number, please try again later")
+	ErrSMSUnknownProvider      = errors.New("unknown SMS provider")
+	ErrSMSCallbackUnauthorized = errors.New("invalid callback token")
+	ErrSMSBudgetExhausted      = errors.New("text messages are temporarily unavailable, please try again later")
+	ErrAlertUndeliverable      = errors.New("trusted contact has no phone number or email to alert")
+)
+
+const (
+	smsSendTimeout = 10 * time.Second
+	// smsIndiaPrefix routes Indian numbers to MSG91 when it is configured (DLT-registered templates)
+	smsIndiaPrefix = "+91"
+)
+
+// smsRateLimit caps how often one number can receive a kind of message
+type smsRateLimit struct {
+	cooldown  time.Duration // minimum gap between messages, 0 for none
+	perWindow int
+	window    time.Duration
+}
+
+var smsRateLimits = map[string]smsRateLimit{
+	models.SMSKindVerification: {cooldown: time.Minute, perWindow: 5, window: time.Hour},
+	models.SMSKindSafetyAlert:  {perWindow: 20, window: time.Hour},
+}
+
+// SMSProvider sends text messages and parses its own delivery callbacks
+type SMSProvider interface {
+	Name() string
+	// Send delivers a message and returns the provider's message ID
+	Send(ctx context.Context, msg *models.SMSMessage) (string, error)
+	// ParseStatusCallback reads a delivery report webhook
+	ParseStatusCallback(r *http.Request) ([]models.SMSStatusReport, error)
+}
+
+// SMSGateway picks a provider per number and records delivery status
+type SMSGateway struct {
+	firestoreService *FirestoreService
+	redisService     *RedisService
+	quota            *QuotaService
+	email            EmailSender // safety alerts for contacts without a phone number
+	providers        map[string]SMSProvider
+	defaultProvider  SMSProvider
+	callbackToken    string
+
+	mu      sync.Mutex
+	sendLog map[string][]time.Time // rate limit fallback when Redis is unavailable
+}
+
+// NewSMSGateway creates a new SMS gateway. SMS_PROVIDER selects the default provider
+// (twilio, msg91 or log); Indian numbers go through MSG91 whenever it is configured.
+// Providers without credentials are skipped and messages fall back to the server log.
+// Credentials come from the secrets provider. Safety alerts for contacts with only an
+// email go through the email sender.
+func NewSMSGateway(fs *FirestoreService, rs *RedisService, secrets SecretsProvider, quota *QuotaService, email EmailSender) *SMSGateway {
+	callbackToken := strings.TrimSpace(os.Getenv("SMS_CALLBACK_TOKEN"))
+	httpClient := &http.Client{Timeout: smsSendTimeout}
+
+	gw := &SMSGateway{
+		firestoreService: fs,
+		redisService:     rs,
+		quota:            quota,
+		email:            email,
+		providers:        map[string]SMSProvider{"log": logSMSProvider{}},
+		callbackToken:    callbackToken,
+		sendLog:          make(map[string][]time.Time),
+	}
+
+	ctx := context.Background()
+	if sid, token := lookupSecret(ctx, secrets, "TWILIO_ACCOUNT_SID"), lookupSecret(ctx, secrets, "TWILIO_AUTH_TOKEN"); sid != "" && token != "" {
+		statusURL := ""
+		if base := strings.TrimRight(strings.TrimSpace(os.Getenv("PUBLIC_BASE_URL")), "/"); base != "" && callbackToken != "" {
+			statusURL = base + "/api/v1/sms/status/twilio?token=" + url.QueryEscape(callbackToken)
+		}
+		gw.providers["twilio"] = &twilioSMSProvider{
+			accountSID:          sid,
+			authToken:           token,
+			from:                strings.TrimSpace(os.Getenv("TWILIO_FROM_NUMBER")),
+			messagingServiceSID: strings.TrimSpace(os.Getenv("TWILIO_MESSAGING_SERVICE_SID")),
+			statusCallbackURL:   statusURL,
+			httpClient:          httpClient,
+		}
+	}
+	if authKey := lookupSecret(ctx, secrets, "MSG91_AUTH_KEY"); authKey != "" {
+		gw.providers["msg91"] = &msg91SMSProvider{
+			authKey: authKey,
+			templates: map[string]string{
+				models.SMSKindVerification: strings.TrimSpace(os.Getenv("MSG91_OTP_TEMPLATE_ID")),
+				models.SMSKindSafetyAlert:  strings.TrimSpace(os.Getenv("MSG91_ALERT_TEMPLATE_ID")),
+			},
+			httpClient: httpClient,
+		}
+	}
+
+	name := strings.ToLower(strings.TrimSpace(os.Getenv("SMS_PROVIDER")))
+	if name == "" {
+		name = "log"
+	}
+	provider, ok := gw.providers[name]
+	if !ok {
+		log.Printf("SMS provider %q is not configured, falling back to log", name)
+		provider = gw.providers["log"]
+	}
+	gw.defaultProvider = provider
+	return gw
+}
+
+// ProviderName returns the default provider's name
+func (gw *SMSGateway) ProviderName() string {
+	return gw.defaultProvider.Name()
+}
+
+// Send rate-limits, delivers and records a message. Once the daily SMS budget is spent only
+// safety alerts are still sent.
+func (gw *SMSGateway) Send(ctx context.Context, msg *models.SMSMessage) error {
+	if msg.Kind != models.SMSKindSafetyAlert && !gw.quota.Allow(QuotaSMS) {
+		return ErrSMSBudgetExhausted
+	}
+	if !gw.allow(msg.To, msg.Kind) {
+		return ErrSMSRateLimited
+	}
+
+	provider := gw.providerFor(msg.To)
+	messageID, err := provider.Send(ctx, msg)
+	if err != nil {
+		log.Printf("SMS via %s to %s failed: %v", provider.Name(), msg.To, err)
+		return errors.New("failed to send SMS")
+	}
+	gw.quota.Record(QuotaSMS, 1, smsMicrosPerMessage[provider.Name()])
+
+	now := time.Now()
+	gw.recordDelivery(&models.SMSDelivery{
+		ID:                uuid.New().String(),
+		Provider:          provider.Name(),
+		ProviderMessageID: messageID,
+		PhoneNumber:       msg.To,
+		Kind:              msg.Kind,
+		Status:            models.SMSStatusSent,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	})
+	return nil
+}
+
+// SendSafetyAlert texts a trusted contact, emailing them instead when they have no phone
+// number or the text fails
+func (gw *SMSGateway) SendSafetyAlert(contact *models.TrustedContact, message string) error {
+	var smsErr error
+	if contact.PhoneNumber != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), smsSendTimeout)
+		defer cancel()
+		smsErr = gw.Send(ctx, &models.SMSMessage{
+			To:     contact.PhoneNumber,
+			Kind:   models.SMSKindSafetyAlert,
+			Body:   message,
+			Params: map[string]string{"message": message},
+		})
+		if smsErr == nil {
+			return nil
+		}
+	}
+	if contact.Email == "" || gw.email == nil {
+		if smsErr != nil {
+			return smsErr
+		}
+		return ErrAlertUndeliverable
+	}
+	if err := gw.email.SendEmail(&models.EmailMessage{To: contact.Email, Subject: "Safety alert from Unalone", Body: message}); err != nil {
+		return fmt.Errorf("failed to email safety alert: %w", err)
+	}
+	return nil
+}
+
+// HandleStatusCallback applies a provider delivery report to the tracked messages
+func (gw *SMSGateway) HandleStatusCallback(providerName, token string, r *http.Request) error {
+	if gw.callbackToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(gw.callbackToken)) != 1 {
+		return ErrSMSCallbackUnauthorized
+	}
+	provider, ok := gw.providers[providerName]
+	if !ok {
+		return ErrSMSUnknownProvider
+	}
+
+	reports, err := provider.ParseStatusCallback(r)
+	if err != nil {
+		return err
+	}
+	for _, report := range reports {
+		if report.Status == models.SMSStatusFailed || report.Status == models.SMSStatusUndelivered {
+			log.Printf("SMS %s via %s not delivered (code %s)", report.ProviderMessageID, providerName, report.ErrorCode)
+		}
+		gw.updateDelivery(providerName, report)
+	}
+	return nil
+}
+
+// providerFor picks MSG91 for Indian numbers when available, otherwise the default provider
+func (gw *SMSGateway) providerFor(phoneNumber string) SMSProvider {
+	if strings.HasPrefix(phoneNumber, smsIndiaPrefix) {
+		if provider, ok := gw.providers["msg91"]; ok {
+			return provider
+		}
+	}
+	return gw.defaultProvider
+}
+
+// allow enforces the per-number limits for a message kind and records the send when allowed
+func (gw *SMSGateway) allow(phoneNumber, kind string) bool {
+	limit, ok := smsRateLimits[kind]
+	if !ok {
+		return true
+	}
+	key := "sms:rate:" + kind + ":" + phoneNumber
+	now := time.Now()
+
+	if gw.redisService.IsAvailable() {
+		if limit.cooldown > 0 {
+			if recent, err := gw.redisService.CountWindowEvents(key, now.Add(-limit.cooldown)); err == nil && recent > 0 {
+				return false
+			}
+		}
+		if count, err := gw.redisService.CountWindowEvents(key, now.Add(-limit.window)); err == nil && count >= int64(limit.perWindow) {
+			return false
+		}
+		if err := gw.redisService.RecordWindowEvent(key, uuid.New().String(), now, limit.window); err != nil {
+			log.Printf("SMS rate limit write failed: %v", err)
+		}
+		return true
+	}
+
+	gw.mu.Lock()
+	defer gw.mu.Unlock()
+	sends := gw.sendLog[key][:0]
+	for _, at := range gw.sendLog[key] {
+		if now.Sub(at) < limit.window {
+			sends = append(sends, at)
+		}
+	}
+	if len(sends) >= limit.perWindow || (limit.cooldown > 0 && len(sends) > 0 && now.Sub(sends[len(sends)-1]) < limit.cooldown) {
+		gw.sendLog[key] = sends
+		return false
+	}
+	gw.sendLog[key] = append(sends, now)
+	return true
+}
+
+// Mock storage for testing
+var (
+	mockSMSDeliveriesMu sync.Mutex
+	mockSMSDeliveries   = make(map[string]*models.SMSDelivery) // provider:messageID -> delivery
+)
+
+// recordDelivery stores a sent message so callbacks can update it (best-effort)
+func (gw *SMSGateway) recordDelivery(delivery *models.SMSDelivery) {
+	if gw.isTestMode() {
+		mockSMSDeliveriesMu.Lock()
+		mockSMSDeliveries[delivery.Provider+":"+delivery.ProviderMessageID] = delivery
+		mockSMSDeliveriesMu.Unlock()
+		return
+	}
+	// TODO: Write Firestore document sms_deliveries/{provider}_{providerMessageID}
+}
+
+func (gw *SMSGateway) updateDelivery(providerName string, report models.SMSStatusReport) {
+	if gw.isTestMode() {
+		mockSMSDeliveriesMu.Lock()
+		defer mockSMSDeliveriesMu.Unlock()
+		if delivery, ok := mockSMSDeliveries[providerName+":"+report.ProviderMessageID]; ok {
+			delivery.Status = report.Status
+			delivery.ErrorCode = report.ErrorCode
+			delivery.UpdatedAt = time.Now()
+		}
+		return
+	}
+	// TODO: Update Firestore document sms_deliveries/{provider}_{providerMessageID}
+}
+
+// isTestMode checks if we're running with mocked database
+func (gw *SMSGateway) isTestMode() bool {
+	return gw.firestoreService.client == nil
+}
+
+// logSMSProvider writes messages to the server log for local development
+type logSMSProvider struct{}
+
+func (logSMSProvider) Name() string { return "log" }
+
+func (logSMSProvider) Send(_ context.Context, msg *models.SMSMessage) (string, error) {
+	log.Printf("📱 SMS to %s: %s", msg.To, msg.Body)
+	return uuid.New().String(), nil
+}
+
+func (logSMSProvider) ParseStatusCallback(_ *http.Request) ([]models.SMSStatusReport, error) {
+	return nil, nil
+}
+
+// twilioSMSProvider sends through the Twilio Messages API
+type twilioSMSProvider struct {
+	accountSID          string
+	authToken           string
+	from                string
+	messagingServiceSID string
+	statusCallbackURL   string
+	httpClient          *http.Client
+}
+
+func (tp *twilioSMSProvider) Name() string { return "twilio" }
+
+func (tp *twilioSMSProvider) Send(ctx context.Context, msg *models.SMSMessage) (string, error) {
+	form := url.Values{}
+	form.Set("To", msg.To)
+	form.Set("Body", msg.Body)
+	if tp.messagingServiceSID != "" {
+		form.Set("MessagingServiceSid", tp.messagingServiceSID)
+	} else {
+		form.Set("From", tp.from)
+	}
+	if tp.statusCallbackURL != "" {
+		form.Set("StatusCallback", tp.statusCallbackURL)
+	}
+
+	endpoint := fmt.Sprintf("https://api.twilio.com/2010-04-01/Accounts/%s/Messages.json", tp.accountSID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(tp.accountSID, tp.authToken)
+
+	resp, err := tp.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("twilio returned %d: %s", resp.StatusCode, string(body))
+	}
+
+	var out struct {
+		SID string `json:"sid"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return "", err
+	}
+	return out.SID, nil
+}
+
+func (tp *twilioSMSProvider) ParseStatusCallback(r *http.Request) ([]models.SMSStatusReport, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+	sid := r.PostForm.Get("MessageSid")
+	if sid == "" {
+		return nil, errors.New("missing MessageSid")
+	}
+	return []models.SMSStatusReport{{
+		ProviderMessageID: sid,
+		Status:            twilioStatus(r.PostForm.Get("MessageStatus")),
+		ErrorCode:         r.PostForm.Get("ErrorCode"),
+	}}, nil
+}
+
+// twilioStatus maps Twilio message statuses onto the gateway's statuses
+func twilioStatus(status string) string {
+	switch status {
+	case "delivered", "read":
+		return models.SMSStatusDelivered
+	case "sent":
+		return models.SMSStatusSent
+	case "failed", "canceled":
+		return models.SMSStatusFailed
+	case "undelivered":
+		return models.SMSStatusUndelivered
+	default:
+		return models.SMSStatusQueued
+	}
+}
+
+// msg91SMSProvider sends through MSG91 flows, which wrap the DLT templates Indian carriers require
+type msg91SMSProvider struct {
+	authKey    string
+	templates  map[string]string // message kind -> flow template ID
+	httpClient *http.Client
+}
+
+func (mp *msg91SMSProvider) Name() string { return "msg91" }
+
+func (mp *msg91SMSProvider) Send(ctx context.Context, msg *models.SMSMessage) (string, error) {
+	templateID := mp.templates[msg.Kind]
+	if templateID == "" {
+		return "", fmt.Errorf("no MSG91 template configured for %s messages", msg.Kind)
+	}
+
+	recipient := map[string]string{"mobiles": strings.TrimPrefix(msg.To, "+")}
+	for k, v := range msg.Params {
+		recipient[k] = v
+	}
+	payload, _ := json.Marshal(map[string]interface{}{
+		"template_id": templateID,
+		"short_url":   "0",
+		"recipients":  []map[string]string{recipient},
+	})
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://control.msg91.com/api/v5/flow/", bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("authkey", mp.authKey)
+
+	resp, err := mp.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	var out struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return "", fmt.Errorf("msg91 returned %d: %s", resp.StatusCode, string(body))
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 || out.Type != "success" {
+		return "", fmt.Errorf("msg91 returned %d: %s", resp.StatusCode, out.Message)
+	}
+	// On success the message field carries the request ID used in delivery reports
+	return out.Message, nil
+}
+
+// ParseStatusCallback reads MSG91 delivery reports, posted either as a JSON body
+// or as a form field named "data" holding the same JSON
+func (mp *msg91SMSProvider) ParseStatusCallback(r *http.Request) ([]models.SMSStatusReport, error) {
+	var raw []byte
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		raw = body
+	} else {
+		if err := r.ParseForm(); err != nil {
+			return nil, err
+		}
+		raw = []byte(r.PostForm.Get("data"))
+	}
+
+	var batches []struct {
+		RequestID string `json:"requestId"`
+		Report    []struct {
+			Status string `json:"status"`
+			Desc   string `json:"desc"`
+		} `json:"report"`
+	}
+	if err := json.Unmarshal(raw, &batches); err != nil {
+		return nil, errors.New("invalid MSG91 delivery report")
+	}
+
+	reports := make([]models.SMSStatusReport, 0, len(batches))
+	for _, batch := range batches {
+		for _, entry := range batch.Report {
+			report := models.SMSStatusReport{ProviderMessageID: batch.RequestID, Status: models.SMSStatusDelivered}
+			if entry.Status != "1" {
+				report.Status = models.SMSStatusFailed
+				report.ErrorCode = strings.TrimSpace(entry.Status + " " + entry.Desc)
+			}
+			reports = append(reports, report)
+		}
+	}
+	return reports, nil
+}
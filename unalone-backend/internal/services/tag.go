@@ -0,0 +1,192 @@
+// Tag service for tag normalization and per-region popularity counts
+package services
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"unalone-backend/internal/models"
+)
+
+const (
+	maxTagLength           = 30
+	defaultPopularTagLimit = 20
+	globalTagRegion        = "" // Region key for counts across all cities
+)
+
+// TagService maintains tag usage counts for browsable hotspots
+type TagService struct {
+	firestoreService *FirestoreService
+}
+
+// NewTagService creates a new tag service
+func NewTagService(fs *FirestoreService) *TagService {
+	return &TagService{firestoreService: fs}
+}
+
+// NormalizeTags lowercases, slugifies and de-duplicates tags so that
+// "BoardGames", "board games" and "board-games" all become "board-games"
+func NormalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		slug := slugifyTag(tag)
+		if slug == "" || seen[slug] {
+			continue
+		}
+		seen[slug] = true
+		normalized = append(normalized, slug)
+	}
+	return normalized
+}
+
+// slugifyTag splits camelCase words and joins alphanumeric runs with hyphens
+func slugifyTag(tag string) string {
+	var b strings.Builder
+	var prev rune
+	pendingHyphen := false
+	for _, r := range strings.TrimSpace(tag) {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			pendingHyphen = b.Len() > 0
+			prev = r
+			continue
+		}
+		// Word boundary inside camelCase, e.g. "BoardGames"
+		if unicode.IsUpper(r) && (unicode.IsLower(prev) || unicode.IsDigit(prev)) {
+			pendingHyphen = b.Len() > 0
+		}
+		if pendingHyphen {
+			b.WriteByte('-')
+			pendingHyphen = false
+		}
+		b.WriteRune(unicode.ToLower(r))
+		prev = r
+	}
+
+	slug := b.String()
+	if len(slug) > maxTagLength {
+		slug = strings.TrimRight(truncateUTF8(slug, maxTagLength), "-")
+	}
+	return slug
+}
+
+// truncateUTF8 cuts s to at most n bytes without splitting a character
+func truncateUTF8(s string, n int) string {
+	for n > 0 && n < len(s) && s[n]&0xC0 == 0x80 {
+		n--
+	}
+	return s[:n]
+}
+
+// GetPopularTags returns the most used tags, optionally within a city and matching a prefix
+func (ts *TagService) GetPopularTags(req *models.PopularTagsRequest) ([]models.TagCount, error) {
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultPopularTagLimit
+	}
+	region := NormalizeCity(req.City)
+	prefix := slugifyTag(req.Prefix)
+
+	if ts.isTestMode() {
+		return ts.getPopularTagsMock(region, prefix, limit), nil
+	}
+
+	// TODO: Query Firestore tag_counts/{region}/tags ordered by count desc
+	return nil, errors.New("firestore implementation needed")
+}
+
+// tagContribution is what a hotspot adds to the tag counts
+type tagContribution struct {
+	region string
+	tags   []string
+}
+
+// tagContributionOf returns a hotspot's contribution, or nil when it is not browsable.
+// Tag counts are shared by everyone, so organization hotspots do not contribute.
+func tagContributionOf(hotspot *models.Hotspot) *tagContribution {
+	if !isBrowsable(hotspot) || hotspot.OrgID != "" || len(hotspot.Tags) == 0 {
+		return nil
+	}
+	tags := make([]string, len(hotspot.Tags))
+	copy(tags, hotspot.Tags)
+	return &tagContribution{region: hotspot.CityKey, tags: tags}
+}
+
+// applyContribution replaces a hotspot's previous contribution with its new one;
+// either side may be nil for hotspots entering or leaving the browsable set
+func (ts *TagService) applyContribution(before, after *tagContribution) {
+	if ts.isTestMode() {
+		ts.applyContributionMock(before, after)
+		return
+	}
+	// TODO: Implement Firestore increments on tag_counts/{region}/tags/{tag}
+}
+
+// isTestMode checks if we're running with mocked database
+func (ts *TagService) isTestMode() bool {
+	return ts.firestoreService.client == nil
+}
+
+// === Mock storage in-memory for development/test ===
+
+var (
+	mockTagCountsMu sync.Mutex
+	mockTagCounts   = make(map[string]map[string]int) // region -> tag -> count
+)
+
+func (ts *TagService) applyContributionMock(before, after *tagContribution) {
+	mockTagCountsMu.Lock()
+	defer mockTagCountsMu.Unlock()
+
+	adjust := func(c *tagContribution, delta int) {
+		if c == nil {
+			return
+		}
+		regions := []string{globalTagRegion}
+		if c.region != globalTagRegion {
+			regions = append(regions, c.region)
+		}
+		for _, region := range regions {
+			counts, ok := mockTagCounts[region]
+			if !ok {
+				counts = make(map[string]int)
+				mockTagCounts[region] = counts
+			}
+			for _, tag := range c.tags {
+				counts[tag] += delta
+				if counts[tag] <= 0 {
+					delete(counts, tag)
+				}
+			}
+		}
+	}
+	adjust(before, -1)
+	adjust(after, 1)
+}
+
+func (ts *TagService) getPopularTagsMock(region, prefix string, limit int) []models.TagCount {
+	mockTagCountsMu.Lock()
+	defer mockTagCountsMu.Unlock()
+
+	results := make([]models.TagCount, 0)
+	for tag, count := range mockTagCounts[region] {
+		if strings.HasPrefix(tag, prefix) {
+			results = append(results, models.TagCount{Tag: tag, Count: count})
+		}
+	}
+
+	// Most used first; alphabetical for ties so results are stable
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Count != results[j].Count {
+			return results[i].Count > results[j].Count
+		}
+		return results[i].Tag < results[j].Tag
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
@@ -0,0 +1,418 @@
+// Travel-time estimates for search results through a pluggable routing provider
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"unalone-backend/internal/models"
+)
+
+// Travel modes a routing provider is asked about
+const (
+	TravelModeWalking = "walking"
+	TravelModeDriving = "driving"
+)
+
+const (
+	routingTimeout = 5 * time.Second
+	// travelOriginPrecision snaps origins to ~150 m geohash cells so nearby users share cache entries
+	travelOriginPrecision = 7
+	travelCacheTTL        = 30 * time.Minute
+	// maxTravelAnnotations bounds how many results per search get provider travel times
+	maxTravelAnnotations = 25
+
+	// Straight-line fallback: routes are rarely direct, so distances are stretched before
+	// dividing by a typical city speed
+	estimateDetourFactor  = 1.3
+	estimateWalkingKmPerH = 4.8
+	estimateDrivingKmPerH = 25.0
+)
+
+// RoutingProvider returns travel durations from one origin to many destinations
+type RoutingProvider interface {
+	Name() string
+	// Durations returns one duration per destination; a negative value means no route was found
+	Durations(ctx context.Context, origin models.HotspotLocation, destinations []models.HotspotLocation, mode string) ([]time.Duration, error)
+}
+
+// TravelTimeService annotates search results with bearing and walking/driving times
+type TravelTimeService struct {
+	redisService *RedisService
+	provider     RoutingProvider
+	fallback     RoutingProvider
+
+	mu    sync.Mutex
+	cache map[string]travelCacheEntry // fallback when Redis is unavailable
+}
+
+type travelCacheEntry struct {
+	seconds   int64
+	expiresAt time.Time
+}
+
+// NewTravelTimeService creates the service. ROUTING_PROVIDER selects osrm (OSRM_URL) or google
+// (GOOGLE_MAPS_API_KEY); without one, times are estimated from straight-line distance.
+func NewTravelTimeService(rs *RedisService) *TravelTimeService {
+	httpClient := &http.Client{Timeout: routingTimeout}
+	fallback := estimateRoutingProvider{}
+
+	var provider RoutingProvider = fallback
+	switch name := strings.ToLower(strings.TrimSpace(os.Getenv("ROUTING_PROVIDER"))); name {
+	case "", "estimate":
+	case "osrm":
+		if base := strings.TrimRight(strings.TrimSpace(os.Getenv("OSRM_URL")), "/"); base != "" {
+			provider = &osrmRoutingProvider{baseURL: base, httpClient: httpClient}
+		} else {
+			log.Printf("Routing provider osrm needs OSRM_URL, falling back to estimates")
+		}
+	case "google":
+		if key := strings.TrimSpace(os.Getenv("GOOGLE_MAPS_API_KEY")); key != "" {
+			provider = &googleRoutingProvider{apiKey: key, httpClient: httpClient}
+		} else {
+			log.Printf("Routing provider google needs GOOGLE_MAPS_API_KEY, falling back to estimates")
+		}
+	default:
+		log.Printf("Unknown routing provider %q, falling back to estimates", name)
+	}
+
+	return &TravelTimeService{
+		redisService: rs,
+		provider:     provider,
+		fallback:     fallback,
+		cache:        make(map[string]travelCacheEntry),
+	}
+}
+
+// ProviderName returns the configured routing provider's name
+func (ts *TravelTimeService) ProviderName() string {
+	return ts.provider.Name()
+}
+
+// Annotate sets the bearing from origin on every result and, when withTravel is set, walking
+// and driving times on the first results. Provider failures fall back to estimates.
+func (ts *TravelTimeService) Annotate(ctx context.Context, origin models.HotspotLocation, results []models.HotspotWithDistance, withTravel bool) {
+	for i := range results {
+		results[i].Bearing = initialBearing(origin, results[i].Hotspot.Location)
+	}
+	if !withTravel || len(results) == 0 {
+		return
+	}
+
+	annotated := results
+	if len(annotated) > maxTravelAnnotations {
+		annotated = annotated[:maxTravelAnnotations]
+	}
+	walking, walkingSource := ts.durations(ctx, origin, annotated, TravelModeWalking)
+	driving, drivingSource := ts.durations(ctx, origin, annotated, TravelModeDriving)
+	for i := range annotated {
+		// Either mode falling back makes the pair approximate
+		source := walkingSource
+		if drivingSource == ts.fallback.Name() {
+			source = drivingSource
+		}
+		annotated[i].Travel = &models.TravelEstimate{
+			WalkingMinutes: travelMinutes(walking[i]),
+			DrivingMinutes: travelMinutes(driving[i]),
+			Source:         source,
+		}
+	}
+}
+
+// durations returns seconds per result for one mode, reading and filling the cache keyed by
+// the origin's geohash cell. Routes are computed from the cell center so entries are shareable.
+func (ts *TravelTimeService) durations(ctx context.Context, origin models.HotspotLocation, results []models.HotspotWithDistance, mode string) ([]int64, string) {
+	seconds := make([]int64, len(results))
+	if ts.provider.Name() == ts.fallback.Name() {
+		return ts.estimate(ctx, origin, results, mode), ts.fallback.Name()
+	}
+
+	cell := EncodeGeohash(origin.Latitude, origin.Longitude, travelOriginPrecision)
+	cellBox := geohashCell(origin.Latitude, origin.Longitude, travelOriginPrecision)
+	cellCenter := models.HotspotLocation{
+		Latitude:  (cellBox.SouthWest.Latitude + cellBox.NorthEast.Latitude) / 2,
+		Longitude: (cellBox.SouthWest.Longitude + cellBox.NorthEast.Longitude) / 2,
+	}
+	cacheKey := fmt.Sprintf("travel:%s:%s:%s", ts.provider.Name(), mode, cell)
+
+	ids := make([]string, len(results))
+	for i, result := range results {
+		ids[i] = result.Hotspot.ID
+	}
+	cached := ts.getCached(cacheKey, ids)
+
+	var missing []int
+	var destinations []models.HotspotLocation
+	for i, id := range ids {
+		if value, ok := cached[id]; ok {
+			seconds[i] = value
+			continue
+		}
+		missing = append(missing, i)
+		destinations = append(destinations, results[i].Hotspot.Location)
+	}
+	if len(missing) == 0 {
+		return seconds, ts.provider.Name()
+	}
+
+	durations, err := ts.provider.Durations(ctx, cellCenter, destinations, mode)
+	if err != nil || len(durations) != len(destinations) {
+		log.Printf("Routing via %s failed (%s): %v", ts.provider.Name(), mode, err)
+		return ts.estimate(ctx, origin, results, mode), ts.fallback.Name()
+	}
+
+	fresh := make(map[string]int64, len(missing))
+	for j, i := range missing {
+		seconds[i] = int64(durations[j].Seconds())
+		fresh[ids[i]] = seconds[i]
+	}
+	ts.setCached(cacheKey, fresh)
+	return seconds, ts.provider.Name()
+}
+
+func (ts *TravelTimeService) estimate(ctx context.Context, origin models.HotspotLocation, results []models.HotspotWithDistance, mode string) []int64 {
+	destinations := make([]models.HotspotLocation, len(results))
+	for i, result := range results {
+		destinations[i] = result.Hotspot.Location
+	}
+	durations, _ := ts.fallback.Durations(ctx, origin, destinations, mode)
+	seconds := make([]int64, len(durations))
+	for i, d := range durations {
+		seconds[i] = int64(d.Seconds())
+	}
+	return seconds
+}
+
+func (ts *TravelTimeService) getCached(key string, ids []string) map[string]int64 {
+	found := make(map[string]int64, len(ids))
+	if ts.redisService.IsAvailable() {
+		values, err := ts.redisService.HashGetMany(key, ids)
+		if err == nil {
+			for i, value := range values {
+				if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+					found[ids[i]] = seconds
+				}
+			}
+			return found
+		}
+		log.Printf("Failed to read travel cache: %v", err)
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	now := clock.Now()
+	for _, id := range ids {
+		if entry, ok := ts.cache[key+":"+id]; ok && now.Before(entry.expiresAt) {
+			found[id] = entry.seconds
+		}
+	}
+	return found
+}
+
+func (ts *TravelTimeService) setCached(key string, values map[string]int64) {
+	if ts.redisService.IsAvailable() {
+		fields := make(map[string]interface{}, len(values))
+		for id, seconds := range values {
+			fields[id] = seconds
+		}
+		if err := ts.redisService.HashSetMany(key, fields, travelCacheTTL); err == nil {
+			return
+		}
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	expiresAt := clock.Now().Add(travelCacheTTL)
+	for id, seconds := range values {
+		ts.cache[key+":"+id] = travelCacheEntry{seconds: seconds, expiresAt: expiresAt}
+	}
+}
+
+// PurgeExpiredCache drops expired in-memory entries (only used without Redis)
+func (ts *TravelTimeService) PurgeExpiredCache() {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	now := clock.Now()
+	for key, entry := range ts.cache {
+		if now.After(entry.expiresAt) {
+			delete(ts.cache, key)
+		}
+	}
+}
+
+// travelMinutes rounds a duration up to whole minutes, keeping -1 for "no route"
+func travelMinutes(seconds int64) int {
+	if seconds < 0 {
+		return -1
+	}
+	return max(int(math.Ceil(float64(seconds)/60)), 1)
+}
+
+// initialBearing returns the compass bearing in degrees (0 = north, 90 = east) from a to b
+func initialBearing(a, b models.HotspotLocation) float64 {
+	lat1, lat2 := a.Latitude*math.Pi/180, b.Latitude*math.Pi/180
+	dLon := (b.Longitude - a.Longitude) * math.Pi / 180
+	y := math.Sin(dLon) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(dLon)
+	bearing := math.Mod(math.Atan2(y, x)*180/math.Pi+360, 360)
+	return math.Round(bearing*10) / 10
+}
+
+// === Providers ===
+
+// estimateRoutingProvider approximates durations from straight-line distance
+type estimateRoutingProvider struct{}
+
+func (estimateRoutingProvider) Name() string { return "estimate" }
+
+func (estimateRoutingProvider) Durations(_ context.Context, origin models.HotspotLocation, destinations []models.HotspotLocation, mode string) ([]time.Duration, error) {
+	speed := estimateWalkingKmPerH
+	if mode == TravelModeDriving {
+		speed = estimateDrivingKmPerH
+	}
+	durations := make([]time.Duration, len(destinations))
+	for i, destination := range destinations {
+		km := haversineKm(origin, destination) * estimateDetourFactor
+		durations[i] = time.Duration(km / speed * float64(time.Hour))
+	}
+	return durations, nil
+}
+
+// osrmRoutingProvider calls the OSRM table service (one source, many destinations)
+type osrmRoutingProvider struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+func (p *osrmRoutingProvider) Name() string { return "osrm" }
+
+func (p *osrmRoutingProvider) Durations(ctx context.Context, origin models.HotspotLocation, destinations []models.HotspotLocation, mode string) ([]time.Duration, error) {
+	profile := "foot"
+	if mode == TravelModeDriving {
+		profile = "car"
+	}
+	coords := make([]string, 0, len(destinations)+1)
+	coords = append(coords, fmt.Sprintf("%f,%f", origin.Longitude, origin.Latitude))
+	for _, d := range destinations {
+		coords = append(coords, fmt.Sprintf("%f,%f", d.Longitude, d.Latitude))
+	}
+	endpoint := fmt.Sprintf("%s/table/v1/%s/%s?sources=0&annotations=duration", p.baseURL, profile, strings.Join(coords, ";"))
+
+	var body struct {
+		Code      string       `json:"code"`
+		Message   string       `json:"message"`
+		Durations [][]*float64 `json:"durations"`
+	}
+	if err := getRoutingJSON(ctx, p.httpClient, endpoint, &body); err != nil {
+		return nil, err
+	}
+	if body.Code != "Ok" || len(body.Durations) != 1 || len(body.Durations[0]) != len(destinations)+1 {
+		return nil, fmt.Errorf("osrm: %s %s", body.Code, body.Message)
+	}
+
+	durations := make([]time.Duration, len(destinations))
+	for i, seconds := range body.Durations[0][1:] {
+		durations[i] = -1
+		if seconds != nil {
+			durations[i] = time.Duration(*seconds * float64(time.Second))
+		}
+	}
+	return durations, nil
+}
+
+// googleRoutingProvider calls the Google Distance Matrix API
+type googleRoutingProvider struct {
+	apiKey     string
+	httpClient *http.Client
+}
+
+// googleMatrixMaxDestinations is the Distance Matrix limit per request
+const googleMatrixMaxDestinations = 25
+
+func (p *googleRoutingProvider) Name() string { return "google" }
+
+func (p *googleRoutingProvider) Durations(ctx context.Context, origin models.HotspotLocation, destinations []models.HotspotLocation, mode string) ([]time.Duration, error) {
+	durations := make([]time.Duration, 0, len(destinations))
+	for start := 0; start < len(destinations); start += googleMatrixMaxDestinations {
+		end := min(start+googleMatrixMaxDestinations, len(destinations))
+		points := make([]string, 0, end-start)
+		for _, d := range destinations[start:end] {
+			points = append(points, fmt.Sprintf("%f,%f", d.Latitude, d.Longitude))
+		}
+		query := url.Values{}
+		query.Set("origins", fmt.Sprintf("%f,%f", origin.Latitude, origin.Longitude))
+		query.Set("destinations", strings.Join(points, "|"))
+		query.Set("mode", mode)
+		query.Set("key", p.apiKey)
+
+		var body struct {
+			Status       string `json:"status"`
+			ErrorMessage string `json:"error_message"`
+			Rows         []struct {
+				Elements []struct {
+					Status   string `json:"status"`
+					Duration struct {
+						Value int64 `json:"value"`
+					} `json:"duration"`
+				} `json:"elements"`
+			} `json:"rows"`
+		}
+		if err := getRoutingJSON(ctx, p.httpClient, "https://maps.googleapis.com/maps/api/distancematrix/json?"+query.Encode(), &body); err != nil {
+			return nil, err
+		}
+		if body.Status != "OK" || len(body.Rows) != 1 || len(body.Rows[0].Elements) != end-start {
+			return nil, fmt.Errorf("google distance matrix: %s %s", body.Status, body.ErrorMessage)
+		}
+		for _, element := range body.Rows[0].Elements {
+			if element.Status != "OK" {
+				durations = append(durations, -1)
+				continue
+			}
+			durations = append(durations, time.Duration(element.Duration.Value)*time.Second)
+		}
+	}
+	return durations, nil
+}
+
+// getRoutingJSON fetches a routing API response into out
+func getRoutingJSON(ctx context.Context, client *http.Client, endpoint string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("routing provider returned %d", resp.StatusCode)
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return errors.New("invalid response from routing provider")
+	}
+	return nil
+}
+
+// haversineKm returns the great-circle distance between two points in kilometers
+func haversineKm(a, b models.HotspotLocation) float64 {
+	const earthRadiusKm = 6371
+	lat1, lat2 := a.Latitude*math.Pi/180, b.Latitude*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (b.Longitude - a.Longitude) * math.Pi / 180
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
+}
@@ -0,0 +1,175 @@
+// Trending service ranking hotspots by recent activity over a sliding window
+package services
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"unalone-backend/internal/models"
+)
+
+// Trending signal kinds
+const (
+	TrendingSignalJoin    = "joins"
+	TrendingSignalMessage = "messages"
+	TrendingSignalView    = "views"
+)
+
+const (
+	trendingWindow        = 6 * time.Hour
+	trendingCandidateSize = 100
+)
+
+// Relative weight of each signal in the trending score
+var trendingWeights = map[string]float64{
+	TrendingSignalJoin:    3.0,
+	TrendingSignalMessage: 1.0,
+	TrendingSignalView:    0.5,
+}
+
+// TrendingService records activity signals and ranks hotspots by them
+type TrendingService struct {
+	redisService   *RedisService
+	hotspotService *HotspotService
+
+	// In-memory fallback when Redis is unavailable: key -> member -> timestamp
+	mu     sync.Mutex
+	events map[string]map[string]time.Time
+}
+
+// NewTrendingService creates a new trending service
+func NewTrendingService(rs *RedisService, hs *HotspotService) *TrendingService {
+	return &TrendingService{
+		redisService:   rs,
+		hotspotService: hs,
+		events:         make(map[string]map[string]time.Time),
+	}
+}
+
+// RecordJoin records a user joining a hotspot
+func (ts *TrendingService) RecordJoin(hotspotID, userID string) {
+	ts.record(TrendingSignalJoin, hotspotID, userID)
+}
+
+// RecordMessage records a chat message sent in a hotspot
+func (ts *TrendingService) RecordMessage(hotspotID, messageID string) {
+	ts.record(TrendingSignalMessage, hotspotID, messageID)
+}
+
+// RecordView records a user viewing a hotspot; repeat views by the same user count once per window
+func (ts *TrendingService) RecordView(hotspotID, userID string) {
+	ts.record(TrendingSignalView, hotspotID, userID)
+}
+
+// GetTrending ranks the active hotspots a user can see within radius of a point by recent activity
+func (ts *TrendingService) GetTrending(userID, orgID string, lat, lon, radiusKm float64, limit int) ([]models.TrendingHotspot, error) {
+	isActive := true
+	candidates, err := ts.hotspotService.SearchHotspots(&models.HotspotSearchRequest{
+		Latitude:  lat,
+		Longitude: lon,
+		Radius:    radiusKm,
+		IsActive:  &isActive,
+		Limit:     trendingCandidateSize,
+		OrgID:     orgID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	since := clock.Now().Add(-trendingWindow)
+	trending := make([]models.TrendingHotspot, 0, len(candidates.Hotspots))
+	for _, candidate := range candidates.Hotspots {
+		// Friend-list hotspots only trend for the people invited to them
+		if !CanViewHotspot(&candidate.Hotspot, userID, orgID) {
+			continue
+		}
+		joins := ts.count(TrendingSignalJoin, candidate.Hotspot.ID, since)
+		messages := ts.count(TrendingSignalMessage, candidate.Hotspot.ID, since)
+		views := ts.count(TrendingSignalView, candidate.Hotspot.ID, since)
+
+		score := float64(joins)*trendingWeights[TrendingSignalJoin] +
+			float64(messages)*trendingWeights[TrendingSignalMessage] +
+			float64(views)*trendingWeights[TrendingSignalView]
+		if score == 0 {
+			continue
+		}
+
+		trending = append(trending, models.TrendingHotspot{
+			Hotspot:  candidate.Hotspot,
+			Distance: candidate.Distance,
+			Score:    score,
+			Joins:    joins,
+			Messages: messages,
+			Views:    views,
+		})
+	}
+
+	// Highest score first; closer hotspots win ties
+	sort.Slice(trending, func(i, j int) bool {
+		if trending[i].Score != trending[j].Score {
+			return trending[i].Score > trending[j].Score
+		}
+		return trending[i].Distance < trending[j].Distance
+	})
+
+	if limit > 0 && len(trending) > limit {
+		trending = trending[:limit]
+	}
+	return trending, nil
+}
+
+// record stores a signal in Redis, or in memory when Redis is unavailable
+func (ts *TrendingService) record(kind, hotspotID, member string) {
+	key := trendingKey(kind, hotspotID)
+	now := clock.Now()
+
+	if ts.redisService.IsAvailable() {
+		_ = ts.redisService.RecordWindowEvent(key, member, now, trendingWindow)
+		return
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	members, ok := ts.events[key]
+	if !ok {
+		members = make(map[string]time.Time)
+		ts.events[key] = members
+	}
+	members[member] = now
+
+	// Trim entries that fell out of the window
+	cutoff := now.Add(-trendingWindow)
+	for m, at := range members {
+		if at.Before(cutoff) {
+			delete(members, m)
+		}
+	}
+}
+
+// count returns the number of signals recorded since the given time
+func (ts *TrendingService) count(kind, hotspotID string, since time.Time) int {
+	key := trendingKey(kind, hotspotID)
+
+	if ts.redisService.IsAvailable() {
+		n, err := ts.redisService.CountWindowEvents(key, since)
+		if err != nil {
+			return 0
+		}
+		return int(n)
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	n := 0
+	for _, at := range ts.events[key] {
+		if !at.Before(since) {
+			n++
+		}
+	}
+	return n
+}
+
+func trendingKey(kind, hotspotID string) string {
+	return "trending:" + kind + ":" + hotspotID
+}
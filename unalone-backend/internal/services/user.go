@@ -2,12 +2,9 @@
 package services
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
-	"io/ioutil"
-	"os"
-	"path/filepath"
-	"time"
+	"sort"
 
 	"cloud.google.com/go/firestore"
 	"github.com/google/uuid"
@@ -20,13 +17,18 @@ import (
 // UserService handles user-related operations
 type UserService struct {
 	firestoreService *FirestoreService
+	store            UserStore // Mock mode only
 }
 
 // NewUserService creates a new user service
 func NewUserService(fs *FirestoreService) *UserService {
-	return &UserService{
+	us := &UserService{
 		firestoreService: fs,
 	}
+	if us.isTestMode() {
+		us.store = NewUserStore()
+	}
+	return us
 }
 
 // CreateUser creates a new user in Firestore or mock storage
@@ -63,12 +65,16 @@ func (us *UserService) CreateUser(email, realName, nickname, passwordHash string
 		PasswordHash:    passwordHash,
 		IsPhoneVerified: false,
 		IsEmailVerified: false,
-		CreatedAt:       time.Now(),
-		UpdatedAt:       time.Now(),
+		CreatedAt:       clock.Now(),
+		UpdatedAt:       clock.Now(),
 	}
 
-	// Save user to Firestore
-	_, err = usersRef.Doc(userID).Set(ctx, user)
+	// Save user to Firestore, with PII sealed
+	sealed, err := sealUserPII(user)
+	if err != nil {
+		return nil, err
+	}
+	_, err = us.firestoreService.SetDoc(ctx, usersRef.Doc(userID), sealed)
 	if err != nil {
 		return nil, err
 	}
@@ -86,7 +92,7 @@ func (us *UserService) GetUserByID(userID string) (*models.User, error) {
 	ctx := us.firestoreService.GetContext()
 	usersRef := us.firestoreService.Collection(UsersCollection)
 
-	doc, err := usersRef.Doc(userID).Get(ctx)
+	doc, err := us.firestoreService.GetDoc(ctx, usersRef.Doc(userID))
 	if err != nil {
 		if status.Code(err) == codes.NotFound {
 			return nil, errors.New("user not found")
@@ -98,6 +104,9 @@ func (us *UserService) GetUserByID(userID string) (*models.User, error) {
 	if err := doc.DataTo(&user); err != nil {
 		return nil, err
 	}
+	if err := openUserPII(&user); err != nil {
+		return nil, err
+	}
 
 	return &user, nil
 }
@@ -112,7 +121,7 @@ func (us *UserService) GetUserByEmail(email string) (*models.User, error) {
 	ctx := us.firestoreService.GetContext()
 	usersRef := us.firestoreService.Collection(UsersCollection)
 
-	docs, err := usersRef.Where("email", "==", email).Limit(1).Documents(ctx).GetAll()
+	docs, err := us.firestoreService.Query(ctx, usersRef.Where("email", "==", email), 1)
 	if err != nil {
 		return nil, err
 	}
@@ -125,6 +134,9 @@ func (us *UserService) GetUserByEmail(email string) (*models.User, error) {
 	if err := docs[0].DataTo(&user); err != nil {
 		return nil, err
 	}
+	if err := openUserPII(&user); err != nil {
+		return nil, err
+	}
 
 	return &user, nil
 }
@@ -139,7 +151,7 @@ func (us *UserService) GetUserByNickname(nickname string) (*models.User, error)
 	ctx := us.firestoreService.GetContext()
 	usersRef := us.firestoreService.Collection(UsersCollection)
 
-	docs, err := usersRef.Where("nickname", "==", nickname).Limit(1).Documents(ctx).GetAll()
+	docs, err := us.firestoreService.Query(ctx, usersRef.Where("nickname", "==", nickname), 1)
 	if err != nil {
 		return nil, err
 	}
@@ -152,6 +164,9 @@ func (us *UserService) GetUserByNickname(nickname string) (*models.User, error)
 	if err := docs[0].DataTo(&user); err != nil {
 		return nil, err
 	}
+	if err := openUserPII(&user); err != nil {
+		return nil, err
+	}
 
 	return &user, nil
 }
@@ -167,10 +182,14 @@ func (us *UserService) UpdateUser(userID string, updates map[string]interface{})
 	usersRef := us.firestoreService.Collection(UsersCollection)
 
 	// Add updated timestamp
-	updates["updated_at"] = time.Now()
+	updates["updated_at"] = clock.Now()
 
 	// Update user document
-	_, err := usersRef.Doc(userID).Set(ctx, updates, firestore.MergeAll)
+	sealed, err := sealPIIUpdates(userID, updates)
+	if err != nil {
+		return nil, err
+	}
+	_, err = us.firestoreService.SetDoc(ctx, usersRef.Doc(userID), sealed, firestore.MergeAll)
 	if err != nil {
 		return nil, err
 	}
@@ -185,93 +204,96 @@ func (us *UserService) DeleteUser(userID string) error {
 	usersRef := us.firestoreService.Collection(UsersCollection)
 
 	updates := map[string]interface{}{
-		"deleted_at": time.Now(),
-		"updated_at": time.Now(),
+		"deleted_at": clock.Now(),
+		"updated_at": clock.Now(),
 	}
 
-	_, err := usersRef.Doc(userID).Set(ctx, updates, firestore.MergeAll)
+	_, err := us.firestoreService.SetDoc(ctx, usersRef.Doc(userID), updates, firestore.MergeAll)
 	return err
 }
 
-// File-based persistent mock storage for development
-const mockDataFile = "mock_users.json"
-
-// loadMockUsers loads users from JSON file
-func (us *UserService) loadMockUsers() (map[string]*models.User, error) {
-	users := make(map[string]*models.User)
-
-	// Check if file exists
-	if _, err := os.Stat(mockDataFile); os.IsNotExist(err) {
-		return users, nil // Return empty map if file doesn't exist
-	}
-
-	// Read file
-	data, err := ioutil.ReadFile(mockDataFile)
-	if err != nil {
-		return users, err
-	}
-
-	if len(data) == 0 {
-		return users, nil
-	}
-
-	// Read into generic map to capture password_hash too
-	var raw map[string]map[string]interface{}
-	if err := json.Unmarshal(data, &raw); err != nil {
-		return users, err
-	}
-
-	for id, m := range raw {
-		// Extract password hash if present
-		pw := ""
-		if v, ok := m["password_hash"].(string); ok {
-			pw = v
-		}
-		// Marshal back to JSON and unmarshal into models.User
-		b, err := json.Marshal(m)
+// ListUserIDs returns the IDs of every user, for batch jobs such as the weekly digest
+func (us *UserService) ListUserIDs() ([]string, error) {
+	if us.isTestMode() {
+		users, err := us.loadMockUsers()
 		if err != nil {
-			continue
+			return nil, err
 		}
-		var u models.User
-		if err := json.Unmarshal(b, &u); err != nil {
-			continue
+		ids := make([]string, 0, len(users))
+		for id := range users {
+			ids = append(ids, id)
 		}
-		u.PasswordHash = pw
-		users[id] = &u
+		sort.Strings(ids)
+		return ids, nil
 	}
 
-	return users, nil
-}
-
-// saveMockUsers saves users to JSON file
-func (us *UserService) saveMockUsers(users map[string]*models.User) error {
-	// Create directory if it doesn't exist
-	dir := filepath.Dir(mockDataFile)
-	if dir != "." {
-		os.MkdirAll(dir, 0755)
+	// Paged so the scan stays within the query limit however many users there are
+	ids := []string{}
+	query := us.firestoreService.Collection(UsersCollection).Select()
+	err := us.firestoreService.Scan(us.firestoreService.GetContext(), query, us.firestoreService.guard.MaxQueryLimit(), func(docs []*firestore.DocumentSnapshot) error {
+		for _, doc := range docs {
+			ids = append(ids, doc.Ref.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	return ids, nil
+}
 
-	// Build output map with password_hash included
-	out := make(map[string]map[string]interface{})
-	for id, u := range users {
-		// Marshal user (without password due to json:"-")
-		b, err := json.Marshal(u)
+// ListOrgMembers returns the users who belong to an organization, reading within the request
+// budget in ctx
+func (us *UserService) ListOrgMembers(ctx context.Context, orgID string) ([]*models.User, error) {
+	if us.isTestMode() {
+		users, err := us.loadMockUsers()
 		if err != nil {
-			continue
+			return nil, err
 		}
-		var m map[string]interface{}
-		if err := json.Unmarshal(b, &m); err != nil {
-			continue
+		members := []*models.User{}
+		for _, user := range users {
+			if user.OrgID == orgID {
+				members = append(members, user)
+			}
 		}
-		// Add password hash explicitly for persistence in test mode
-		m["password_hash"] = u.PasswordHash
-		out[id] = m
+		return members, nil
 	}
 
-	data, err := json.MarshalIndent(out, "", "  ")
+	members := []*models.User{}
+	query := us.firestoreService.Collection(UsersCollection).Where("org_id", "==", orgID)
+	err := us.firestoreService.Scan(ctx, query, us.firestoreService.guard.MaxQueryLimit(), func(docs []*firestore.DocumentSnapshot) error {
+		for _, doc := range docs {
+			var user models.User
+			if err := doc.DataTo(&user); err != nil {
+				return err
+			}
+			if err := openUserPII(&user); err != nil {
+				return err
+			}
+			members = append(members, &user)
+		}
+		return nil
+	})
 	if err != nil {
-		return err
+		return nil, err
 	}
+	return members, nil
+}
 
-	return ioutil.WriteFile(mockDataFile, data, 0644)
+// loadMockUsers returns a copy of every stored user; change users through updateMockUsers
+func (us *UserService) loadMockUsers() (map[string]*models.User, error) {
+	return us.store.Load()
+}
+
+// updateMockUsers applies fn to the stored users in one transaction
+func (us *UserService) updateMockUsers(fn func(users map[string]*models.User) error) error {
+	return us.store.Update(fn)
+}
+
+// Close releases the mock user store
+func (us *UserService) Close() error {
+	if us.store == nil {
+		return nil
+	}
+	return us.store.Close()
 }
@@ -0,0 +1,113 @@
+// Last known user location, stored on the user and in a Redis geo index
+package services
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"unalone-backend/internal/models"
+)
+
+var (
+	// ErrLocationSharingDisabled is returned when a user who turned location sharing off reports a location
+	ErrLocationSharingDisabled = errors.New("location sharing is turned off in your settings")
+	// ErrLocationConsentRequired is returned when a user who revoked location consent reports a location
+	ErrLocationConsentRequired = errors.New("you have not consented to location processing")
+)
+
+// UserLocationService keeps each user's last reported location for features that run
+// without a fresh GPS fix, such as nearby hotspots and friend proximity alerts
+type UserLocationService struct {
+	userService    *UserService
+	profileService *ProfileService
+	redisService   *RedisService
+	consents       *ConsentService
+}
+
+// NewUserLocationService creates a new user location service
+func NewUserLocationService(us *UserService, ps *ProfileService, rs *RedisService, cs *ConsentService) *UserLocationService {
+	return &UserLocationService{
+		userService:    us,
+		profileService: ps,
+		redisService:   rs,
+		consents:       cs,
+	}
+}
+
+// UpdateLocation stores the user's current location. It is refused while location sharing is
+// off or without location consent.
+func (uls *UserLocationService) UpdateLocation(userID string, req *models.UpdateLocationRequest) (*models.User, error) {
+	if !uls.consents.Allowed(userID, models.ConsentLocation) {
+		return nil, ErrLocationConsentRequired
+	}
+	if !uls.sharingEnabled(userID) {
+		return nil, ErrLocationSharingDisabled
+	}
+
+	now := clock.Now()
+	location := models.Location{
+		Latitude:  *req.Latitude,
+		Longitude: *req.Longitude,
+		City:      req.City,
+		Country:   req.Country,
+		UpdatedAt: &now,
+	}
+	user, err := uls.userService.UpdateUser(userID, map[string]interface{}{"location": location})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uls.redisService.SetUserLocation(userID, location.Latitude, location.Longitude); err != nil {
+		log.Printf("Failed to index location for user %s: %v", userID, err)
+	}
+	return user, nil
+}
+
+// ClearLocation forgets the user's stored location
+func (uls *UserLocationService) ClearLocation(userID string) error {
+	if _, err := uls.userService.UpdateUser(userID, map[string]interface{}{"location": models.Location{}}); err != nil {
+		return err
+	}
+	return uls.redisService.RemoveUserLocation(userID)
+}
+
+// LastKnownLocation returns the stored location, if the user has one and still shares it
+// with location consent
+func (uls *UserLocationService) LastKnownLocation(userID string) (*models.Location, bool) {
+	user, err := uls.userService.GetUserByID(userID)
+	if err != nil || user.Location.UpdatedAt == nil {
+		return nil, false
+	}
+	if !uls.sharingEnabled(userID) || !uls.consents.Allowed(userID, models.ConsentLocation) {
+		return nil, false
+	}
+	location := user.Location
+	return &location, true
+}
+
+// PurgeStaleLocations forgets locations last reported before cutoff, returning how many went
+func (uls *UserLocationService) PurgeStaleLocations(cutoff time.Time) (int, error) {
+	userIDs, err := uls.userService.ListUserIDs()
+	if err != nil {
+		return 0, err
+	}
+	purged := 0
+	for _, userID := range userIDs {
+		user, err := uls.userService.GetUserByID(userID)
+		if err != nil || user.Location.UpdatedAt == nil || !user.Location.UpdatedAt.Before(cutoff) {
+			continue
+		}
+		if err := uls.ClearLocation(userID); err != nil {
+			log.Printf("Failed to purge stale location for user %s: %v", userID, err)
+			continue
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+func (uls *UserLocationService) sharingEnabled(userID string) bool {
+	settings, err := uls.profileService.GetUserSettings(userID)
+	return err == nil && settings.LocationSharing
+}
@@ -0,0 +1,338 @@
+// Embedded persistence for users in mock mode
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"unalone-backend/internal/models"
+)
+
+// UserStore persists users when running without Firestore. Callers get copies: changes are
+// only stored through Update, which runs in one transaction so concurrent writers cannot
+// overwrite each other.
+type UserStore interface {
+	// Load returns every user keyed by ID
+	Load() (map[string]*models.User, error)
+	// Update runs fn on every user and stores the users it added, changed or removed.
+	// Nothing is stored if fn returns an error. fn must not call back into the store.
+	Update(fn func(users map[string]*models.User) error) error
+	Close() error
+}
+
+const (
+	// Legacy JSON file, imported into the embedded store on first start
+	mockDataFile = "mock_users.json"
+	// Default embedded database file, overridable with MOCK_USERS_DB
+	mockUsersDBFile = "mock_users.db"
+)
+
+var usersBucket = []byte("users")
+
+// NewUserStore opens the embedded user database (MOCK_USERS_DB, default mock_users.db),
+// importing mock_users.json when the database is new. If the file cannot be opened, for
+// example because another process holds it, users are kept in memory instead.
+func NewUserStore() UserStore {
+	path := MockUsersDBPath()
+	store, err := OpenUserStoreFile(path)
+	if err != nil {
+		log.Printf("Mock users: cannot open %s (%v); keeping users in memory", path, err)
+		return newMemoryUserStore()
+	}
+	if err := importLegacyMockUsers(store); err != nil {
+		log.Printf("Mock users: importing %s failed: %v", mockDataFile, err)
+	}
+	return store
+}
+
+// MockUsersDBPath returns the embedded user database file from MOCK_USERS_DB
+func MockUsersDBPath() string {
+	if path := os.Getenv("MOCK_USERS_DB"); path != "" {
+		return path
+	}
+	return mockUsersDBFile
+}
+
+// OpenUserStoreFile opens (creating if needed) an embedded user database. Only one process
+// can hold the file; others wait a second and then fail.
+func OpenUserStoreFile(path string) (UserStore, error) {
+	return openBoltUserStore(path)
+}
+
+// importLegacyMockUsers copies mock_users.json into an empty store
+func importLegacyMockUsers(store UserStore) error {
+	users, err := store.Load()
+	if err != nil || len(users) > 0 {
+		return err
+	}
+	f, err := os.Open(mockDataFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	n, err := ImportUsersJSON(store, f)
+	if err != nil {
+		return err
+	}
+	log.Printf("Mock users: imported %d users from %s", n, mockDataFile)
+	return nil
+}
+
+// mockUserRecord is the stored form of a user: the public JSON plus the fields hidden from it
+type mockUserRecord map[string]interface{}
+
+func encodeMockUser(u *models.User) ([]byte, error) {
+	b, err := json.Marshal(u)
+	if err != nil {
+		return nil, err
+	}
+	var m mockUserRecord
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	m["password_hash"] = u.PasswordHash
+	if len(u.BlockedBy) > 0 {
+		m["blocked_by"] = u.BlockedBy
+	}
+	if u.ReportCount > 0 {
+		m["report_count"] = u.ReportCount
+	}
+	if u.OrgJoinedAt != nil {
+		m["org_joined_at"] = u.OrgJoinedAt.Format(time.RFC3339Nano)
+	}
+	if err := sealMockUserRecord(u.ID, m); err != nil {
+		return nil, err
+	}
+	return json.Marshal(m)
+}
+
+func decodeMockUser(data []byte) (*models.User, error) {
+	var m mockUserRecord
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return decodeMockUserRecord(m)
+}
+
+func decodeMockUserRecord(m mockUserRecord) (*models.User, error) {
+	if err := openMockUserRecord(m); err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	var u models.User
+	if err := json.Unmarshal(b, &u); err != nil {
+		return nil, err
+	}
+	if v, ok := m["password_hash"].(string); ok {
+		u.PasswordHash = v
+	}
+	// Blocks and report counts are hidden from JSON responses, so restore them explicitly
+	if list, ok := m["blocked_by"].([]interface{}); ok {
+		for _, v := range list {
+			if s, ok := v.(string); ok {
+				u.BlockedBy = append(u.BlockedBy, s)
+			}
+		}
+	}
+	if v, ok := m["report_count"].(float64); ok {
+		u.ReportCount = int(v)
+	}
+	if v, ok := m["org_joined_at"].(string); ok {
+		if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			u.OrgJoinedAt = &t
+		}
+	}
+	return &u, nil
+}
+
+// ExportUsersJSON writes every user in the mock_users.json format
+func ExportUsersJSON(store UserStore, w io.Writer) (int, error) {
+	users, err := store.Load()
+	if err != nil {
+		return 0, err
+	}
+	out := make(map[string]json.RawMessage, len(users))
+	for id, u := range users {
+		data, err := encodeMockUser(u)
+		if err != nil {
+			return 0, fmt.Errorf("user %s: %w", id, err)
+		}
+		out[id] = data
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return len(users), err
+}
+
+// ImportUsersJSON adds or replaces users from the mock_users.json format
+func ImportUsersJSON(store UserStore, r io.Reader) (int, error) {
+	var raw map[string]mockUserRecord
+	if err := json.NewDecoder(r).Decode(&raw); err != nil && err != io.EOF {
+		return 0, err
+	}
+	imported := make(map[string]*models.User, len(raw))
+	for id, m := range raw {
+		u, err := decodeMockUserRecord(m)
+		if err != nil {
+			log.Printf("Mock users: skipping %s: %v", id, err)
+			continue
+		}
+		imported[id] = u
+	}
+	err := store.Update(func(users map[string]*models.User) error {
+		for id, u := range imported {
+			users[id] = u
+		}
+		return nil
+	})
+	return len(imported), err
+}
+
+// boltUserStore keeps one record per user in a bbolt file
+type boltUserStore struct {
+	db *bolt.DB
+}
+
+func openBoltUserStore(path string) (*boltUserStore, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(usersBucket)
+		return err
+	}); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &boltUserStore{db: db}, nil
+}
+
+func (s *boltUserStore) Load() (map[string]*models.User, error) {
+	users := make(map[string]*models.User)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(usersBucket).ForEach(func(k, v []byte) error {
+			u, err := decodeMockUser(v)
+			if err != nil {
+				log.Printf("Mock users: skipping corrupt record %s: %v", k, err)
+				return nil
+			}
+			users[string(k)] = u
+			return nil
+		})
+	})
+	return users, err
+}
+
+func (s *boltUserStore) Update(fn func(users map[string]*models.User) error) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(usersBucket)
+		original := make(map[string][]byte)
+		users := make(map[string]*models.User)
+		if err := bucket.ForEach(func(k, v []byte) error {
+			u, err := decodeMockUser(v)
+			if err != nil {
+				return nil
+			}
+			original[string(k)] = append([]byte(nil), v...)
+			users[string(k)] = u
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		if err := fn(users); err != nil {
+			return err
+		}
+
+		// Write only what changed
+		for id, u := range users {
+			data, err := encodeMockUser(u)
+			if err != nil {
+				return fmt.Errorf("user %s: %w", id, err)
+			}
+			if bytes.Equal(original[id], data) {
+				continue
+			}
+			if err := bucket.Put([]byte(id), data); err != nil {
+				return err
+			}
+		}
+		for id := range original {
+			if _, ok := users[id]; !ok {
+				if err := bucket.Delete([]byte(id)); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func (s *boltUserStore) Close() error {
+	return s.db.Close()
+}
+
+// memoryUserStore is the fallback when no database file can be opened
+type memoryUserStore struct {
+	mu      sync.Mutex
+	records map[string][]byte
+}
+
+func newMemoryUserStore() *memoryUserStore {
+	return &memoryUserStore{records: make(map[string][]byte)}
+}
+
+func (s *memoryUserStore) Load() (map[string]*models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.decodeAll(), nil
+}
+
+func (s *memoryUserStore) decodeAll() map[string]*models.User {
+	users := make(map[string]*models.User, len(s.records))
+	for id, data := range s.records {
+		if u, err := decodeMockUser(data); err == nil {
+			users[id] = u
+		}
+	}
+	return users
+}
+
+func (s *memoryUserStore) Update(fn func(users map[string]*models.User) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	users := s.decodeAll()
+	if err := fn(users); err != nil {
+		return err
+	}
+	records := make(map[string][]byte, len(users))
+	for id, u := range users {
+		data, err := encodeMockUser(u)
+		if err != nil {
+			return fmt.Errorf("user %s: %w", id, err)
+		}
+		records[id] = data
+	}
+	s.records = records
+	return nil
+}
+
+func (s *memoryUserStore) Close() error { return nil }
@@ -0,0 +1,197 @@
+// Venue service: canonical places with proximity and name based deduplication
+package services
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"unicode"
+
+	"unalone-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// venueMatchRadiusKm is how far apart two mentions of the same place may be
+	venueMatchRadiusKm = 0.1
+	// venueLinkRadiusKm bounds how far a hotspot may be from a venue it is explicitly linked to
+	venueLinkRadiusKm = 0.3
+	// venueNameThreshold is the minimum name similarity for two places to be the same venue
+	venueNameThreshold = 0.6
+	venueGeohashLength = 6
+)
+
+// ErrVenueTooFar is returned when a hotspot is linked to a venue somewhere else
+var ErrVenueTooFar = errors.New("venue is too far from the hotspot location")
+
+// VenueService stores venues and matches new hotspots to existing ones
+type VenueService struct {
+	firestoreService *FirestoreService
+}
+
+// NewVenueService creates a new venue service
+func NewVenueService(fs *FirestoreService) *VenueService {
+	return &VenueService{firestoreService: fs}
+}
+
+// GetVenue returns a venue by ID
+func (vs *VenueService) GetVenue(venueID string) (*models.Venue, error) {
+	if vs.isTestMode() {
+		mockVenuesMu.Lock()
+		defer mockVenuesMu.Unlock()
+		venue, ok := mockVenues[venueID]
+		if !ok {
+			return nil, errors.New("venue not found")
+		}
+		copied := *venue
+		return &copied, nil
+	}
+
+	// TODO: Implement Firestore read of venues/{venueID}
+	return nil, errors.New("firestore implementation needed")
+}
+
+// LinkVenue checks that a hotspot at location may be linked to an existing venue
+func (vs *VenueService) LinkVenue(venueID string, location models.HotspotLocation) (*models.Venue, error) {
+	venue, err := vs.GetVenue(venueID)
+	if err != nil {
+		return nil, err
+	}
+	if haversineKm(venue.Location, location) > venueLinkRadiusKm {
+		return nil, ErrVenueTooFar
+	}
+	return venue, nil
+}
+
+// ResolveVenue returns the venue named name at location, creating it when no venue within
+// venueMatchRadiusKm has a similar enough name. It returns nil when name is blank.
+func (vs *VenueService) ResolveVenue(userID, name string, location models.HotspotLocation, address models.HotspotAddress) (*models.Venue, error) {
+	key := normalizeVenueName(name)
+	if key == "" {
+		return nil, nil
+	}
+
+	if !vs.isTestMode() {
+		// TODO: Query Firestore venues by geohash cell and its neighbors, then create in a transaction
+		return nil, errors.New("firestore implementation needed")
+	}
+
+	// Hold the lock across lookup and insert so concurrent creates at one place share a venue
+	mockVenuesMu.Lock()
+	defer mockVenuesMu.Unlock()
+
+	var best *models.Venue
+	bestScore, bestDistance := 0.0, 0.0
+	for _, venue := range mockVenues {
+		distance := haversineKm(venue.Location, location)
+		if distance > venueMatchRadiusKm {
+			continue
+		}
+		score := venueNameSimilarity(key, venue.NameKey)
+		if score < venueNameThreshold {
+			continue
+		}
+		if best == nil || score > bestScore || (score == bestScore && distance < bestDistance) {
+			best, bestScore, bestDistance = venue, score, distance
+		}
+	}
+	if best != nil {
+		copied := *best
+		return &copied, nil
+	}
+
+	venue := &models.Venue{
+		ID:        uuid.New().String(),
+		Name:      strings.TrimSpace(name),
+		NameKey:   key,
+		Location:  location,
+		Address:   address,
+		Geohash:   EncodeGeohash(location.Latitude, location.Longitude, venueGeohashLength),
+		CreatedBy: userID,
+		CreatedAt: clock.Now(),
+	}
+	mockVenues[venue.ID] = venue
+	copied := *venue
+	return &copied, nil
+}
+
+// venueNameStopWords carry no identity when comparing place names
+var venueNameStopWords = map[string]bool{"the": true, "a": true, "an": true, "and": true, "of": true}
+
+// normalizeVenueName lowercases a place name and keeps only its letters, digits and significant words
+func normalizeVenueName(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	kept := words[:0]
+	for _, word := range words {
+		if !venueNameStopWords[word] {
+			kept = append(kept, word)
+		}
+	}
+	return strings.Join(kept, " ")
+}
+
+// venueNameSimilarity scores two normalized names from 0 to 1. Names where every word of
+// one appears in the other ("blue tokai" and "blue tokai coffee roasters") score 0.9;
+// otherwise it is the Dice coefficient of their character bigrams.
+func venueNameSimilarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	wordsA, wordsB := strings.Fields(a), strings.Fields(b)
+	if len(wordsA) > len(wordsB) {
+		wordsA, wordsB = wordsB, wordsA
+	}
+	if len(wordsA) > 0 {
+		contained := true
+		for _, word := range wordsA {
+			if !containsString(wordsB, word) {
+				contained = false
+				break
+			}
+		}
+		if contained {
+			return 0.9
+		}
+	}
+
+	bigramsA, bigramsB := nameBigrams(a), nameBigrams(b)
+	if len(bigramsA) == 0 || len(bigramsB) == 0 {
+		return 0
+	}
+	counts := make(map[string]int, len(bigramsA))
+	for _, bigram := range bigramsA {
+		counts[bigram]++
+	}
+	shared := 0
+	for _, bigram := range bigramsB {
+		if counts[bigram] > 0 {
+			counts[bigram]--
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(bigramsA)+len(bigramsB))
+}
+
+// nameBigrams returns the adjacent character pairs of a name, ignoring spaces
+func nameBigrams(name string) []string {
+	runes := []rune(strings.ReplaceAll(name, " ", ""))
+	bigrams := make([]string, 0, len(runes))
+	for i := 0; i+1 < len(runes); i++ {
+		bigrams = append(bigrams, string(runes[i:i+2]))
+	}
+	return bigrams
+}
+
+func (vs *VenueService) isTestMode() bool {
+	return vs.firestoreService.client == nil
+}
+
+// === Mock storage in-memory for development/test ===
+
+var (
+	mockVenuesMu sync.Mutex
+	mockVenues   = make(map[string]*models.Venue) // venueID -> venue
+)
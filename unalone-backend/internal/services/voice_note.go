@@ -0,0 +1,398 @@
+// Voice note service: validates uploads, transcodes them in the background and posts them to chat
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"unalone-backend/internal/jobs"
+	"unalone-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// voiceNoteTranscodeTimeout stops a stuck transcode
+	voiceNoteTranscodeTimeout = 30 * time.Second
+	// failedVoiceNoteRetention is how long failed uploads stay visible to their uploader
+	failedVoiceNoteRetention = time.Hour
+)
+
+// voiceNoteSniffedTypes are the detected content types accepted as audio uploads
+var voiceNoteSniffedTypes = map[string]bool{
+	"application/ogg": true, // Ogg Opus or Vorbis
+	"audio/ogg":       true,
+	"audio/mpeg":      true,
+	"audio/wave":      true,
+	"audio/aiff":      true,
+	"video/webm":      true, // WebM audio from browsers
+	"video/mp4":       true, // AAC in .m4a
+}
+
+// Voice note errors
+var (
+	ErrVoiceNoteTooLarge    = fmt.Errorf("voice notes can be at most %d KB", models.MaxVoiceNoteBytes>>10)
+	ErrVoiceNoteFormat      = errors.New("unsupported audio format")
+	ErrVoiceNoteNotFound    = errors.New("voice note not found")
+	ErrVoiceNoteQueueFull   = errors.New("voice notes cannot be processed right now, try again shortly")
+	ErrVoiceNoteUnavailable = errors.New("voice note is not ready")
+)
+
+// VoiceNoteTranscodeJob transcodes an uploaded voice note and posts it to the chat
+var VoiceNoteTranscodeJob = jobs.Type[VoiceNoteJob]{Name: "voice_note.transcode", MaxAttempts: 3, Timeout: 2 * time.Minute}
+
+// VoiceNoteJob is the payload of VoiceNoteTranscodeJob
+type VoiceNoteJob struct {
+	NoteID string `json:"note_id"`
+}
+
+// AudioTranscoder converts audio to the format voice notes are served in
+type AudioTranscoder interface {
+	Transcode(ctx context.Context, data []byte) ([]byte, string, error)
+}
+
+// NewAudioTranscoder uses ffmpeg (FFMPEG_PATH, or ffmpeg on the PATH) to produce mono Opus in Ogg.
+// Without ffmpeg, voice notes are served in their uploaded format.
+func NewAudioTranscoder() AudioTranscoder {
+	path := strings.TrimSpace(os.Getenv("FFMPEG_PATH"))
+	if path == "" {
+		path, _ = exec.LookPath("ffmpeg")
+	}
+	if path == "" {
+		log.Println("ffmpeg not found - voice notes are stored as uploaded")
+		return passthroughTranscoder{}
+	}
+	return ffmpegTranscoder{path: path}
+}
+
+// ffmpegTranscoder re-encodes audio with an ffmpeg subprocess
+type ffmpegTranscoder struct {
+	path string
+}
+
+func (t ffmpegTranscoder) Transcode(ctx context.Context, data []byte) ([]byte, string, error) {
+	cmd := exec.CommandContext(ctx, t.path, "-hide_banner", "-loglevel", "error",
+		"-i", "pipe:0", "-vn", "-ac", "1", "-ar", "48000", "-c:a", "libopus", "-b:a", "32k", "-f", "ogg", "pipe:1")
+	cmd.Stdin = bytes.NewReader(data)
+	var out, stderr bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, "", fmt.Errorf("ffmpeg: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return out.Bytes(), "audio/ogg", nil
+}
+
+// passthroughTranscoder keeps the uploaded audio as is
+type passthroughTranscoder struct{}
+
+func (passthroughTranscoder) Transcode(ctx context.Context, data []byte) ([]byte, string, error) {
+	return data, http.DetectContentType(data), nil
+}
+
+// VoiceNoteService accepts voice note uploads and posts them to the chat once transcoded
+type VoiceNoteService struct {
+	firestoreService *FirestoreService
+	chat             *ChatService
+	store            ObjectStore
+	transcoder       AudioTranscoder
+	queue            *jobs.Queue
+
+	mu      sync.Mutex
+	onReady []func(*models.ChatMessage)
+}
+
+// NewVoiceNoteService creates a new voice note service that transcodes uploads on the job queue
+func NewVoiceNoteService(fs *FirestoreService, cs *ChatService, store ObjectStore, transcoder AudioTranscoder, queue *jobs.Queue) *VoiceNoteService {
+	vs := &VoiceNoteService{
+		firestoreService: fs,
+		chat:             cs,
+		store:            store,
+		transcoder:       transcoder,
+		queue:            queue,
+	}
+	VoiceNoteTranscodeJob.Handle(queue, func(ctx context.Context, job VoiceNoteJob) error {
+		return vs.process(ctx, job.NoteID)
+	})
+	// A note whose attempts ran out is shown to its uploader as failed
+	VoiceNoteTranscodeJob.HandleDead(queue, func(ctx context.Context, job VoiceNoteJob, cause error) {
+		if note, err := vs.get(job.NoteID); err == nil && note.Status == models.VoiceNoteProcessing {
+			vs.fail(note, cause)
+		}
+	})
+	return vs
+}
+
+// OnReady registers a callback for voice notes posted to a chat
+func (vs *VoiceNoteService) OnReady(fn func(*models.ChatMessage)) {
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+	vs.onReady = append(vs.onReady, fn)
+}
+
+// Upload validates and stores a voice note, then queues it for transcoding
+func (vs *VoiceNoteService) Upload(ctx context.Context, userID string, req *models.UploadVoiceNoteRequest, data []byte) (*models.VoiceNote, error) {
+	if _, err := vs.chat.RequireMember(userID, req.HotspotID); err != nil {
+		return nil, err
+	}
+	if len(data) > models.MaxVoiceNoteBytes {
+		return nil, ErrVoiceNoteTooLarge
+	}
+	contentType := http.DetectContentType(data)
+	if !voiceNoteSniffedTypes[contentType] {
+		return nil, ErrVoiceNoteFormat
+	}
+
+	now := clock.Now()
+	note := &models.VoiceNote{
+		ID:          uuid.New().String(),
+		HotspotID:   req.HotspotID,
+		UserID:      userID,
+		Status:      models.VoiceNoteProcessing,
+		ContentType: contentType,
+		SizeBytes:   len(data),
+		DurationMs:  req.DurationMs,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	note.ObjectKey = voiceNoteKey(note, "original")
+	if err := vs.store.Put(ctx, note.ObjectKey, contentType, data); err != nil {
+		return nil, err
+	}
+	if err := vs.save(note); err != nil {
+		return nil, err
+	}
+
+	if _, err := VoiceNoteTranscodeJob.Enqueue(ctx, vs.queue, VoiceNoteJob{NoteID: note.ID}); err != nil {
+		vs.fail(note, err)
+		return nil, ErrVoiceNoteQueueFull
+	}
+	return note, nil
+}
+
+// GetVoiceNote returns a voice note to its uploader or a member of its hotspot
+func (vs *VoiceNoteService) GetVoiceNote(userID, noteID string) (*models.VoiceNote, error) {
+	note, err := vs.get(noteID)
+	if err != nil {
+		return nil, err
+	}
+	if note.UserID != userID {
+		if _, err := vs.chat.RequireMember(userID, note.HotspotID); err != nil {
+			return nil, ErrVoiceNoteNotFound
+		}
+	}
+	return note, nil
+}
+
+// Open returns the audio of a ready voice note for a member of its hotspot
+func (vs *VoiceNoteService) Open(ctx context.Context, userID, noteID string) ([]byte, string, error) {
+	note, err := vs.GetVoiceNote(userID, noteID)
+	if err != nil {
+		return nil, "", err
+	}
+	if note.Status != models.VoiceNoteReady {
+		return nil, "", ErrVoiceNoteUnavailable
+	}
+	return vs.store.Get(ctx, note.ObjectKey)
+}
+
+// process transcodes one voice note, stores the result and posts it to the chat. Errors that
+// retrying cannot fix are permanent; the rest are retried by the job queue.
+func (vs *VoiceNoteService) process(ctx context.Context, noteID string) error {
+	note, err := vs.get(noteID)
+	if errors.Is(err, ErrVoiceNoteNotFound) {
+		return nil // Purged while it was queued
+	}
+	if err != nil {
+		return err
+	}
+	if note.Status != models.VoiceNoteProcessing {
+		return nil // Already handled by an earlier attempt
+	}
+	original, _, err := vs.store.Get(ctx, note.ObjectKey)
+	if err != nil {
+		return err
+	}
+
+	transcodeCtx, cancel := context.WithTimeout(ctx, voiceNoteTranscodeTimeout)
+	audio, contentType, err := vs.transcoder.Transcode(transcodeCtx, original)
+	cancel()
+	if err != nil {
+		return jobs.Permanent(err)
+	}
+
+	originalKey := note.ObjectKey
+	audioKey := voiceNoteKey(note, "audio")
+	if err := vs.store.Put(ctx, audioKey, contentType, audio); err != nil {
+		return err
+	}
+	note.ObjectKey = audioKey
+	note.ContentType = contentType
+	note.SizeBytes = len(audio)
+
+	msg, err := vs.chat.SendVoiceNote(note.UserID, note.HotspotID, &models.ChatAttachment{
+		ID:          note.ID,
+		Type:        models.ChatAttachmentVoice,
+		ContentType: contentType,
+		SizeBytes:   len(audio),
+		DurationMs:  note.DurationMs,
+		URL:         "/api/v1/voice-notes/" + note.ID + "/audio",
+	})
+	if err != nil {
+		// The sender may have left the hotspot while the note was processing
+		if err := vs.store.Delete(ctx, audioKey); err != nil {
+			log.Printf("[voice] delete %s: %v", note.ID, err)
+		}
+		return jobs.Permanent(err)
+	}
+
+	note.Status = models.VoiceNoteReady
+	note.MessageID = msg.ID
+	note.UpdatedAt = clock.Now()
+	if err := vs.save(note); err != nil {
+		log.Printf("[voice] save %s: %v", note.ID, err)
+	}
+	// The original is only deleted once the note points at its transcoded audio
+	if err := vs.store.Delete(ctx, originalKey); err != nil {
+		log.Printf("[voice] delete original %s: %v", note.ID, err)
+	}
+
+	vs.mu.Lock()
+	callbacks := append([]func(*models.ChatMessage){}, vs.onReady...)
+	vs.mu.Unlock()
+	for _, fn := range callbacks {
+		fn(msg)
+	}
+	return nil
+}
+
+// fail marks a voice note failed and deletes its audio
+func (vs *VoiceNoteService) fail(note *models.VoiceNote, cause error) {
+	log.Printf("[voice] %s failed: %v", note.ID, cause)
+	if err := vs.store.Delete(context.Background(), note.ObjectKey); err != nil {
+		log.Printf("[voice] delete %s: %v", note.ID, err)
+	}
+	note.Status = models.VoiceNoteFailed
+	note.Error = "could not process the voice note"
+	note.UpdatedAt = clock.Now()
+	if err := vs.save(note); err != nil {
+		log.Printf("[voice] save %s: %v", note.ID, err)
+	}
+}
+
+// PurgeOrphaned deletes the audio of voice notes whose chat message was purged or expired,
+// and failed uploads after an hour
+func (vs *VoiceNoteService) PurgeOrphaned(ctx context.Context, now time.Time) (int, error) {
+	notes, err := vs.list()
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, note := range notes {
+		switch note.Status {
+		case models.VoiceNoteReady:
+			exists, err := vs.chat.HasMessage(note.HotspotID, note.MessageID)
+			if err != nil || exists {
+				continue
+			}
+			if err := vs.store.Delete(ctx, note.ObjectKey); err != nil {
+				log.Printf("[voice] delete %s: %v", note.ID, err)
+				continue
+			}
+		case models.VoiceNoteFailed:
+			if now.Sub(note.UpdatedAt) < failedVoiceNoteRetention {
+				continue
+			}
+		default:
+			continue
+		}
+		if err := vs.delete(note.ID); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// voiceNoteKey names a voice note object in the store
+func voiceNoteKey(note *models.VoiceNote, name string) string {
+	return "voice-notes/" + note.HotspotID + "/" + note.ID + "/" + name
+}
+
+func (vs *VoiceNoteService) get(noteID string) (*models.VoiceNote, error) {
+	if !vs.isTestMode() {
+		// TODO: Read Firestore document voice_notes/{noteID}
+		return nil, errors.New("firestore implementation needed")
+	}
+
+	mockVoiceNotesMu.Lock()
+	defer mockVoiceNotesMu.Unlock()
+	note, ok := mockVoiceNotes[noteID]
+	if !ok {
+		return nil, ErrVoiceNoteNotFound
+	}
+	result := *note
+	return &result, nil
+}
+
+func (vs *VoiceNoteService) save(note *models.VoiceNote) error {
+	if !vs.isTestMode() {
+		// TODO: Set Firestore document voice_notes/{noteID}
+		return errors.New("firestore implementation needed")
+	}
+
+	mockVoiceNotesMu.Lock()
+	defer mockVoiceNotesMu.Unlock()
+	stored := *note
+	mockVoiceNotes[note.ID] = &stored
+	return nil
+}
+
+func (vs *VoiceNoteService) list() ([]*models.VoiceNote, error) {
+	if !vs.isTestMode() {
+		// TODO: Query Firestore voice_notes where status in (ready, failed)
+		return nil, errors.New("firestore implementation needed")
+	}
+
+	mockVoiceNotesMu.Lock()
+	defer mockVoiceNotesMu.Unlock()
+	notes := make([]*models.VoiceNote, 0, len(mockVoiceNotes))
+	for _, note := range mockVoiceNotes {
+		copied := *note
+		notes = append(notes, &copied)
+	}
+	return notes, nil
+}
+
+func (vs *VoiceNoteService) delete(noteID string) error {
+	if !vs.isTestMode() {
+		// TODO: Delete Firestore document voice_notes/{noteID}
+		return errors.New("firestore implementation needed")
+	}
+
+	mockVoiceNotesMu.Lock()
+	defer mockVoiceNotesMu.Unlock()
+	delete(mockVoiceNotes, noteID)
+	return nil
+}
+
+func (vs *VoiceNoteService) isTestMode() bool {
+	return vs.firestoreService.client == nil
+}
+
+// === Mock storage in-memory for development/test ===
+
+var (
+	mockVoiceNotesMu sync.Mutex
+	mockVoiceNotes   = make(map[string]*models.VoiceNote) // noteID -> voice note
+)
@@ -0,0 +1,103 @@
+// WebSocket ticket service: single-use tickets that authenticate one WebSocket handshake
+package services
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"unalone-backend/internal/models"
+)
+
+const (
+	// WebSocketTicketTTL is how long a ticket can be redeemed after it is issued
+	WebSocketTicketTTL = 30 * time.Second
+	// wsTicketKeyPrefix namespaces tickets in Redis
+	wsTicketKeyPrefix = "ws_ticket:"
+)
+
+// WebSocketTicketService issues tickets that stand in for the access token when opening a
+// WebSocket, so the token never appears in a URL. Tickets live in Redis so any replica can
+// redeem them, falling back to this instance's memory.
+type WebSocketTicketService struct {
+	redisService *RedisService
+
+	mu      sync.Mutex
+	tickets map[string]wsTicket
+}
+
+type wsTicket struct {
+	userID    string
+	expiresAt time.Time
+}
+
+// NewWebSocketTicketService creates a new WebSocket ticket service
+func NewWebSocketTicketService(rs *RedisService) *WebSocketTicketService {
+	return &WebSocketTicketService{redisService: rs, tickets: make(map[string]wsTicket)}
+}
+
+// Issue creates a ticket for userID
+func (ts *WebSocketTicketService) Issue(userID string) (*models.WebSocketTicket, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	ticket := base64.RawURLEncoding.EncodeToString(b)
+	expiresAt := clock.Now().Add(WebSocketTicketTTL)
+
+	if ts.redisService.IsAvailable() {
+		if err := ts.redisService.SetString(wsTicketKeyPrefix+ticket, userID, WebSocketTicketTTL); err != nil {
+			return nil, err
+		}
+	} else {
+		ts.mu.Lock()
+		ts.tickets[ticket] = wsTicket{userID: userID, expiresAt: expiresAt}
+		ts.mu.Unlock()
+	}
+	return &models.WebSocketTicket{Ticket: ticket, ExpiresAt: expiresAt}, nil
+}
+
+// Redeem returns the user a ticket was issued to and invalidates it
+func (ts *WebSocketTicketService) Redeem(ticket string) (string, error) {
+	if ticket == "" {
+		return "", ErrInvalidWebSocketTicket
+	}
+	if ts.redisService.IsAvailable() {
+		userID, found, err := ts.redisService.TakeString(wsTicketKeyPrefix + ticket)
+		if err != nil {
+			log.Printf("[ws] redeem ticket: %v", err)
+			return "", err
+		}
+		if !found {
+			return "", ErrInvalidWebSocketTicket
+		}
+		return userID, nil
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	t, ok := ts.tickets[ticket]
+	delete(ts.tickets, ticket)
+	if !ok || clock.Now().After(t.expiresAt) {
+		return "", ErrInvalidWebSocketTicket
+	}
+	return t.userID, nil
+}
+
+// PurgeExpired drops unredeemed in-memory tickets
+func (ts *WebSocketTicketService) PurgeExpired() {
+	now := clock.Now()
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	for ticket, t := range ts.tickets {
+		if now.After(t.expiresAt) {
+			delete(ts.tickets, ticket)
+		}
+	}
+}
+
+// ErrInvalidWebSocketTicket is returned for unknown, used or expired tickets
+var ErrInvalidWebSocketTicket = errors.New("invalid or expired ticket")